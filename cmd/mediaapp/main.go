@@ -5,13 +5,16 @@ import (
 	"flag"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/GoArmGo/MediaApp/internal/di"
 )
 
 func main() {
 
-	mode := flag.String("mode", "server", "Режим запуска приложения: server или worker")
+	mode := flag.String("mode", "server", "Режим запуска приложения: server, worker или migrate (migrate up | down [N] | version)")
+	maxMessages := flag.Int("max-messages", 0, "Количество сообщений, после обработки которых воркер завершится (0 — работать бесконечно)")
 	flag.Parse()
 
 	// bootstrap-логгер (используется только на этапе инициализации т.к еще не создал slogger)
@@ -22,7 +25,19 @@ func main() {
 
 	ctx := context.Background()
 
-	app, err := di.BuildApp()
+	// миграции не требуют остальных зависимостей приложения
+	if *mode == "migrate" {
+		if err := di.RunMigrations(flag.Args()); err != nil {
+			bootstrapLogger.Error("migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// SIGINT/SIGTERM во время инициализации прерывают её, а не ждут таймаутов
+	bootstrapCtx, stopBootstrap := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	app, err := di.BuildApp(bootstrapCtx)
+	stopBootstrap()
 	if err != nil {
 		bootstrapLogger.Error("failed to build app", "error", err)
 		os.Exit(1)
@@ -43,6 +58,11 @@ func main() {
 
 	slog.Info("application using main logger")
 
+	// флаг имеет приоритет над WORKER_MAX_MESSAGES из окружения
+	if *maxMessages > 0 {
+		app.Config.WorkerMaxMessages = *maxMessages
+	}
+
 	if err := app.Run(ctx, mode); err != nil {
 		slog.Error("application run failed", "error", err)
 		os.Exit(1)
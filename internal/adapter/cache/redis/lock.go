@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// unlockTimeout — сколько ждём снятия блокировки, даже если контекст запроса уже отменён
+const unlockTimeout = 2 * time.Second
+
+// unlockScript удаляет ключ, только если в нём всё ещё наш токен: блокировку, истёкшую
+// по ttl и захваченную другим экземпляром, снимать нельзя
+var unlockScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Acquire реализует lock.DistributedLocker через SET key <token> NX EX <ttl>.
+// Токен уникален для каждого захвата, чтобы unlock не снял чужую блокировку
+func (c *Client) Acquire(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := uuid.NewString()
+	acquired, err := c.rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire lock %s in Redis: %w", key, err)
+	}
+	if !acquired {
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), unlockTimeout)
+		defer cancel()
+		if err := unlockScript.Run(ctx, c.rdb, []string{key}, token).Err(); err != nil {
+			c.logger.Warn("failed to release lock in Redis", "key", key, "error", err)
+		}
+	}
+	return unlock, true, nil
+}
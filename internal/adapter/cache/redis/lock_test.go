@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newTestClient подключается к Redis из TEST_REDIS_URL; без него тест пропускается
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	redisURL := os.Getenv("TEST_REDIS_URL")
+	if redisURL == "" {
+		t.Skip("TEST_REDIS_URL is not set")
+	}
+	c, err := NewClient(context.Background(), redisURL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestAcquire_OneWinner(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+	key := "photo:lock:test-" + uuid.NewString()
+
+	var acquired atomic.Int64
+	var unlocks sync.Map
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, ok, err := c.Acquire(ctx, key, time.Minute)
+			if err != nil {
+				t.Errorf("Acquire: %v", err)
+				return
+			}
+			if ok {
+				acquired.Add(1)
+				unlocks.Store(i, unlock)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := acquired.Load(); n != 1 {
+		t.Fatalf("acquired by %d goroutines, want 1", n)
+	}
+
+	unlocks.Range(func(_, unlock any) bool {
+		unlock.(func())()
+		return true
+	})
+	unlock, ok, err := c.Acquire(ctx, key, time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("Acquire after unlock = %v, %v; want acquired", ok, err)
+	}
+	unlock()
+}
+
+func TestAcquire_StaleUnlockKeepsNewOwner(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+	key := "photo:lock:test-" + uuid.NewString()
+
+	staleUnlock, ok, err := c.Acquire(ctx, key, time.Second)
+	if err != nil || !ok {
+		t.Fatalf("first Acquire = %v, %v; want acquired", ok, err)
+	}
+	time.Sleep(1100 * time.Millisecond)
+
+	unlock, ok, err := c.Acquire(ctx, key, time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("Acquire after expiry = %v, %v; want acquired", ok, err)
+	}
+	defer unlock()
+
+	staleUnlock()
+	if _, ok, err := c.Acquire(ctx, key, time.Minute); err != nil || ok {
+		t.Errorf("Acquire after stale unlock = %v, %v; want the lock still held", ok, err)
+	}
+}
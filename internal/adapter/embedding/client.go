@@ -0,0 +1,106 @@
+// internal/adapter/embedding/client.go
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxErrorBody — сколько байт тела ответа с ошибкой попадает в лог и текст ошибки
+const maxErrorBody = 1024
+
+var tracer = tracing.Tracer("embedding")
+
+// embedRequest — тело запроса к сервису эмбеддингов
+type embedRequest struct {
+	ImageURL string `json:"image_url"`
+}
+
+// embedResponse — ответ сервиса эмбеддингов
+type embedResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+// Client вычисляет эмбеддинги изображений во внешнем сервисе:
+// POST {"image_url": "..."} на endpoint, в ответ {"embedding": [...]} из domain.EmbeddingDimensions чисел
+type Client struct {
+	httpClient *http.Client
+	endpoint   string
+	logger     *slog.Logger
+}
+
+// NewClient создает новый экземпляр Client. timeout ограничивает один запрос к сервису
+func NewClient(endpoint string, timeout time.Duration, logger *slog.Logger) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: timeout},
+		endpoint:   endpoint,
+		logger:     logger,
+	}
+}
+
+// ComputeEmbedding реализует метод VectorEmbedder
+func (c *Client) ComputeEmbedding(ctx context.Context, imageURL string) (_ []float32, err error) {
+	ctx, span := tracer.Start(ctx, "Embedding.ComputeEmbedding", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("image_url", imageURL)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	body, err := json.Marshal(embedRequest{ImageURL: imageURL})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка кодирования запроса к сервису эмбеддингов: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		c.log(ctx).Error("ошибка создания HTTP-запроса", slog.Any("error", err))
+		return nil, fmt.Errorf("ошибка создания HTTP-запроса к сервису эмбеддингов: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.log(ctx).Error("ошибка выполнения HTTP-запроса к сервису эмбеддингов", slog.Any("error", err))
+		return nil, fmt.Errorf("ошибка выполнения HTTP-запроса к сервису эмбеддингов: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		c.log(ctx).Warn("сервис эмбеддингов вернул ошибку", slog.Int("status", resp.StatusCode), slog.String("body", string(bodyBytes)))
+		return nil, fmt.Errorf("сервис эмбеддингов вернул статус %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var response embedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		c.log(ctx).Error("ошибка декодирования JSON", slog.Any("error", err))
+		return nil, fmt.Errorf("ошибка декодирования ответа сервиса эмбеддингов: %w", err)
+	}
+	if len(response.Embedding) != domain.EmbeddingDimensions {
+		return nil, fmt.Errorf("сервис эмбеддингов вернул вектор длины %d, ожидалось %d",
+			len(response.Embedding), domain.EmbeddingDimensions)
+	}
+
+	c.log(ctx).Debug("эмбеддинг вычислен", slog.String("image_url", imageURL),
+		slog.Int64("duration_ms", time.Since(start).Milliseconds()))
+	return response.Embedding, nil
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (c *Client) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, c.logger)
+}
@@ -0,0 +1,197 @@
+// Package fakefetcher — источник фото для разработки без ключей API и сети.
+// Фото берутся из встроенного набора samples, а их файлы отдаёт HTTP-сервер внутри процесса,
+// поэтому весь путь импорта, включая скачивание и загрузку в S3, работает офлайн
+package fakefetcher
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+)
+
+//go:embed samples/photos.json samples/images
+var samples embed.FS
+
+// samplePhoto — фото из samples/photos.json
+type samplePhoto struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	File        string   `json:"file"`
+	Width       int      `json:"width"`
+	Height      int      `json:"height"`
+	Likes       int      `json:"likes"`
+	Tags        []string `json:"tags"`
+}
+
+// FakePhotoFetcher реализует usecase.PhotoFetcher поверх встроенного набора фото.
+// ID фото хранятся в domain.Photo.UnsplashID с префиксом domain.FakeIDPrefix ("fake-ocean-waves").
+// Поиск оставляет фото, в названии, описании или тегах которых есть query (без учёта регистра);
+// фильтр по цвету и сортировка не поддерживаются
+type FakePhotoFetcher struct {
+	photos  []samplePhoto
+	baseURL string // адрес встроенного сервера файлов, со слешем в конце
+	logger  *slog.Logger
+}
+
+// NewFakePhotoFetcher создает новый экземпляр FakePhotoFetcher и запускает на 127.0.0.1
+// со случайным портом сервер, который отдаёт файлы фото. Сервер работает до завершения процесса
+func NewFakePhotoFetcher(logger *slog.Logger) (*FakePhotoFetcher, error) {
+	data, err := samples.ReadFile("samples/photos.json")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения встроенного набора фото: %w", err)
+	}
+	var photos []samplePhoto
+	if err := json.Unmarshal(data, &photos); err != nil {
+		return nil, fmt.Errorf("ошибка разбора встроенного набора фото: %w", err)
+	}
+
+	images, err := fs.Sub(samples, "samples/images")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия встроенных файлов фото: %w", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запуска сервера файлов фото: %w", err)
+	}
+	server := &http.Server{Handler: http.FileServerFS(images), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("сервер файлов фото остановлен", slog.Any("error", err))
+		}
+	}()
+
+	baseURL := "http://" + listener.Addr().String() + "/"
+	logger.Info("локальный источник фото запущен", slog.Int("photos", len(photos)), slog.String("url", baseURL))
+	return &FakePhotoFetcher{photos: photos, baseURL: baseURL, logger: logger}, nil
+}
+
+// FetcherName реализует метод PhotoFetcher
+func (f *FakePhotoFetcher) FetcherName() string {
+	return domain.SourceFake
+}
+
+// FetchPhotoByIDFromExternal реализует метод PhotoFetcher: ID принимается и с префиксом, и без него
+func (f *FakePhotoFetcher) FetchPhotoByIDFromExternal(ctx context.Context, id string) (*domain.Photo, error) {
+	sampleID := strings.TrimPrefix(id, domain.FakeIDPrefix)
+	for i := range f.photos {
+		if f.photos[i].ID == sampleID {
+			return f.toDomain(&f.photos[i]), nil
+		}
+	}
+	f.log(ctx).Warn("фото не найдено в локальном наборе", slog.String("id", id))
+	return nil, fmt.Errorf("фото с ID %s не найдено в локальном наборе: %w", id, domain.ErrExternalPhotoNotFound)
+}
+
+// SearchPhotosFromExternal реализует метод PhotoFetcher. color и orderBy не учитываются
+func (f *FakePhotoFetcher) SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (domain.PhotoPage, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matched []samplePhoto
+	for _, photo := range f.photos {
+		if matchesQuery(&photo, query) && matchesOrientation(&photo, orientation) {
+			matched = append(matched, photo)
+		}
+	}
+
+	f.log(ctx).Info("поиск фото в локальном наборе", slog.String("query", query), slog.Int("found", len(matched)))
+	return domain.PhotoPage{
+		Photos:     f.page(matched, page, perPage),
+		Total:      len(matched),
+		TotalPages: domain.TotalPagesFor(len(matched), perPage),
+	}, nil
+}
+
+// ListNewPhotosFromExternal реализует метод PhotoFetcher: новыми считаются все фото набора по порядку
+func (f *FakePhotoFetcher) ListNewPhotosFromExternal(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	f.log(ctx).Info("запрос списка новых фото из локального набора", slog.Int("page", page), slog.Int("per_page", perPage))
+	return f.page(f.photos, page, perPage), nil
+}
+
+// page возвращает страницу page по perPage фото; perPage <= 0 — все фото на первой странице
+func (f *FakePhotoFetcher) page(photos []samplePhoto, page, perPage int) []domain.Photo {
+	if perPage <= 0 {
+		perPage = len(photos)
+	}
+	from := (max(page, 1) - 1) * perPage
+	if from >= len(photos) {
+		return []domain.Photo{}
+	}
+	photos = photos[from:min(from+perPage, len(photos))]
+
+	result := make([]domain.Photo, 0, len(photos))
+	for i := range photos {
+		result = append(result, *f.toDomain(&photos[i]))
+	}
+	return result
+}
+
+// matchesQuery сообщает, что query (в нижнем регистре) есть в названии, описании или тегах фото
+func matchesQuery(photo *samplePhoto, query string) bool {
+	if query == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(photo.Title), query) || strings.Contains(strings.ToLower(photo.Description), query) {
+		return true
+	}
+	for _, tag := range photo.Tags {
+		if strings.Contains(strings.ToLower(tag), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesOrientation проверяет ориентацию в терминах Unsplash: landscape, portrait или squarish
+func matchesOrientation(photo *samplePhoto, orientation string) bool {
+	switch orientation {
+	case "landscape":
+		return photo.Width > photo.Height
+	case "portrait":
+		return photo.Width < photo.Height
+	case "squarish":
+		return photo.Width == photo.Height
+	default:
+		return true
+	}
+}
+
+// toDomain маппит фото набора в domain.Photo со ссылкой на встроенный сервер
+func (f *FakePhotoFetcher) toDomain(photo *samplePhoto) *domain.Photo {
+	tags := make([]domain.Tag, 0, len(photo.Tags))
+	for _, name := range photo.Tags {
+		tags = append(tags, domain.Tag{Name: name})
+	}
+	return &domain.Photo{
+		ID:             uuid.New(),
+		UnsplashID:     domain.FakeIDPrefix + photo.ID,
+		ExternalSource: domain.SourceFake,
+		ExternalID:     domain.FakeIDPrefix + photo.ID,
+		Title:          photo.Title,
+		Description:    photo.Description,
+		AuthorName:     photo.Author,
+		Width:          photo.Width,
+		Height:         photo.Height,
+		LikesCount:     photo.Likes,
+		OriginalURL:    f.baseURL + photo.File,
+		Tags:           tags,
+	}
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (f *FakePhotoFetcher) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, f.logger)
+}
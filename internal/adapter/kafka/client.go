@@ -0,0 +1,236 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+const (
+	// dialTimeout ограничивает проверку доступности брокера при старте
+	dialTimeout = 10 * time.Second
+
+	// retryDelay — пауза перед повторной обработкой сообщения после ошибки обработчика.
+	// В Kafka нет NACK с возвратом в очередь, поэтому сообщение повторяется на месте,
+	// а его смещение не коммитится, пока обработка не удастся
+	retryDelay = 5 * time.Second
+
+	// commitTimeout ограничивает фиксацию смещения
+	commitTimeout = 5 * time.Second
+)
+
+// Client — клиент Kafka, реализующий ports.PhotoSearchPublisher и ports.PhotoSearchConsumer.
+// Задачи публикуются в топик cfg.Kafka.TopicName как JSON payloads.PhotoSearchPayload;
+// воркеры читают его в группе cfg.Kafka.ConsumerGroup и делят партиции между собой
+type Client struct {
+	writer  *kafkago.Writer
+	reader  *kafkago.Reader
+	cfg     *config.Config
+	metrics *metrics.Metrics
+	logger  *slog.Logger
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewClient создает клиент Kafka и проверяет доступность брокера.
+// Reader создаётся только при запуске потребления, чтобы сервер не вступал в группу потребителей
+func NewClient(ctx context.Context, cfg *config.Config, m *metrics.Metrics, logger *slog.Logger) (*Client, error) {
+	start := time.Now()
+
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	conn, err := kafkago.DialContext(dialCtx, "tcp", cfg.Kafka.BootstrapServers[0])
+	if err != nil {
+		logger.Error("failed to connect to Kafka", "bootstrap_servers", cfg.Kafka.BootstrapServers, "error", err)
+		return nil, fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+	if err := conn.Close(); err != nil {
+		logger.Warn("failed to close Kafka probe connection", "error", err)
+	}
+	logger.Info("connected to Kafka",
+		"bootstrap_servers", cfg.Kafka.BootstrapServers,
+		"topic", cfg.Kafka.TopicName,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	writer := &kafkago.Writer{
+		Addr:                   kafkago.TCP(cfg.Kafka.BootstrapServers...),
+		Topic:                  cfg.Kafka.TopicName,
+		RequiredAcks:           kafkago.RequireAll,
+		AllowAutoTopicCreation: true,
+	}
+
+	return &Client{
+		writer:  writer,
+		cfg:     cfg,
+		metrics: m,
+		logger:  logger,
+	}, nil
+}
+
+// Close закрывает writer и reader Kafka. Клиент — одновременно publisher и consumer,
+// поэтому Close может быть вызван дважды; повторный вызов ничего не делает
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		var errs []error
+		if err := c.writer.Close(); err != nil {
+			c.logger.Error("failed to close Kafka writer", "error", err)
+			errs = append(errs, err)
+		}
+		if c.reader != nil {
+			if err := c.reader.Close(); err != nil {
+				c.logger.Error("failed to close Kafka reader", "error", err)
+				errs = append(errs, err)
+			}
+		}
+		c.logger.Info("Kafka client closed")
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
+
+// PublishPhotoSearchRequest публикует сообщение о поиске фото в топик Kafka
+func (c *Client) PublishPhotoSearchRequest(ctx context.Context, payload payloads.PhotoSearchPayload) error {
+	// Передаём ID запроса в воркер, чтобы логи обработки задачи были связаны с исходным запросом
+	if payload.RequestID == "" {
+		payload.RequestID = logger.RequestIDFromContext(ctx)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		c.logger.Error("failed to marshal payload", "error", err)
+		return fmt.Errorf("failed to marshal payload to JSON: %w", err)
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err = c.writer.WriteMessages(publishCtx, kafkago.Message{
+		Value:   body,
+		Headers: []kafkago.Header{{Key: "Content-Type", Value: []byte("application/json")}},
+	})
+	if err != nil {
+		c.metrics.IncQueueMessages(c.cfg.Kafka.TopicName, "publish_failed")
+		c.logger.Error("failed to publish message", "topic", c.cfg.Kafka.TopicName, "error", err)
+		return fmt.Errorf("failed to publish a message: %w", err)
+	}
+	c.metrics.IncQueueMessages(c.cfg.Kafka.TopicName, "published")
+	c.logger.Info("message published successfully",
+		"topic", c.cfg.Kafka.TopicName,
+		"payload", string(body),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// StartConsumingPhotoSearchRequests вступает в группу потребителей и начинает чтение топика.
+// Смещение коммитится только после успешной обработки (или для нечитаемого сообщения).
+// Этот метод реализует интерфейс ports.PhotoSearchConsumer
+func (c *Client) StartConsumingPhotoSearchRequests(ctx context.Context, handler func(context.Context, payloads.PhotoSearchPayload) error) error {
+	if c.reader != nil {
+		return fmt.Errorf("kafka consumer is already started")
+	}
+	c.reader = kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers: c.cfg.Kafka.BootstrapServers,
+		GroupID: c.cfg.Kafka.ConsumerGroup,
+		Topic:   c.cfg.Kafka.TopicName,
+	})
+
+	c.logger.Info("consumer registered, waiting for messages",
+		"topic", c.cfg.Kafka.TopicName,
+		"group", c.cfg.Kafka.ConsumerGroup,
+	)
+
+	go c.consume(ctx, handler)
+	return nil
+}
+
+func (c *Client) consume(ctx context.Context, handler func(context.Context, payloads.PhotoSearchPayload) error) {
+	topic := c.cfg.Kafka.TopicName
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				c.logger.Warn("context cancelled, stopping Kafka consumer")
+				return
+			}
+			c.logger.Error("failed to fetch message, stopping Kafka consumer", "topic", topic, "error", err)
+			return
+		}
+
+		c.metrics.IncQueueMessages(topic, "consumed")
+
+		var payload payloads.PhotoSearchPayload
+		if err := json.Unmarshal(msg.Value, &payload); err != nil {
+			// Повтор не поможет — коммитим, чтобы не застрять на этом сообщении
+			c.metrics.IncQueueMessages(topic, "nacked")
+			c.logger.Error("failed to unmarshal message", "error", err, "body", string(msg.Value))
+			c.commit(ctx, msg)
+			continue
+		}
+
+		c.logger.Info("received message from topic",
+			"topic", topic,
+			"partition", msg.Partition,
+			"offset", msg.Offset,
+			"payload", payload,
+		)
+
+		if !c.handleWithRetry(ctx, handler, payload) {
+			// контекст отменён: смещение не коммитим, сообщение получит следующий потребитель группы
+			c.logger.Warn("context cancelled, stopping Kafka consumer")
+			return
+		}
+		if c.commit(ctx, msg) {
+			c.metrics.IncQueueMessages(topic, "acked")
+			c.logger.Info("message processed and committed", "payload", payload)
+		}
+	}
+}
+
+// handleWithRetry вызывает обработчик, пока он не завершится успешно.
+// Возвращает false, если ctx отменён раньше
+func (c *Client) handleWithRetry(ctx context.Context, handler func(context.Context, payloads.PhotoSearchPayload) error, payload payloads.PhotoSearchPayload) bool {
+	for {
+		err := handler(ctx, payload)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		c.metrics.IncQueueMessages(c.cfg.Kafka.TopicName, "nacked")
+		c.logger.Error("error processing message, will retry", "error", err, "payload", payload, "retry_in", retryDelay)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// commit фиксирует смещение сообщения в группе. Воркер может отменить ctx сразу после
+// обработки последнего сообщения (WorkerMaxMessages), поэтому коммит от отмены не зависит
+func (c *Client) commit(ctx context.Context, msg kafkago.Message) bool {
+	commitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), commitTimeout)
+	defer cancel()
+	if err := c.reader.CommitMessages(commitCtx, msg); err != nil {
+		c.logger.Error("failed to commit message", "partition", msg.Partition, "offset", msg.Offset, "error", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package multisource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// searchAggregate отправляет поиск во все источники сразу и перемежает их ответы.
+// Каждый источник получает тот же запрос с тем же perPage; Total — сумма по ответившим источникам,
+// TotalPages — наибольшее из них, Sources — источники, чьи фото вошли в страницу
+func (f *MultiFetcher) searchAggregate(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (domain.PhotoPage, error) {
+	results, err := fanOut(ctx, f, "search_photos", func(fetcher usecase.PhotoFetcher) (domain.PhotoPage, error) {
+		return fetcher.SearchPhotosFromExternal(ctx, query, page, perPage, orientation, color, orderBy)
+	})
+	if err != nil {
+		return domain.PhotoPage{}, err
+	}
+
+	var merged domain.PhotoPage
+	pages := make([]sourcePhotos, 0, len(results))
+	for _, result := range results {
+		merged.Total += result.value.Total
+		merged.TotalPages = max(merged.TotalPages, result.value.TotalPages)
+		pages = append(pages, sourcePhotos{source: result.source, photos: result.value.Photos})
+	}
+	merged.Photos, merged.Sources = interleave(pages, perPage)
+	return merged, nil
+}
+
+// listAggregate запрашивает новые фото у всех источников сразу и перемежает их ответы
+func (f *MultiFetcher) listAggregate(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	results, err := fanOut(ctx, f, "list_photos", func(fetcher usecase.PhotoFetcher) ([]domain.Photo, error) {
+		return fetcher.ListNewPhotosFromExternal(ctx, page, perPage)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]sourcePhotos, 0, len(results))
+	for _, result := range results {
+		pages = append(pages, sourcePhotos{source: result.source, photos: result.value})
+	}
+	photos, _ := interleave(pages, perPage)
+	return photos, nil
+}
+
+// sourceResult — ответ одного источника
+type sourceResult[T any] struct {
+	source string
+	value  T
+}
+
+// fanOut вызывает call для всех источников одновременно и возвращает ответы успешных
+// в порядке источников. Ошибка возвращается, только если не ответил ни один источник:
+// ошибки объединяются errors.Join, и *domain.ErrRateLimited в них находится через errors.As
+func fanOut[T any](ctx context.Context, f *MultiFetcher, operation string, call func(usecase.PhotoFetcher) (T, error)) ([]sourceResult[T], error) {
+	if len(f.fetchers) == 0 {
+		return nil, errNoFetchers
+	}
+
+	values := make([]T, len(f.fetchers))
+	errs := make([]error, len(f.fetchers))
+	var wg sync.WaitGroup
+	for i, fetcher := range f.fetchers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = call(fetcher)
+		}()
+	}
+	wg.Wait()
+
+	results := make([]sourceResult[T], 0, len(f.fetchers))
+	var failed []error
+	for i, fetcher := range f.fetchers {
+		if errs[i] != nil {
+			f.log(ctx).Warn("источник фото не ответил",
+				slog.String("source", fetcher.FetcherName()),
+				slog.String("operation", operation),
+				slog.Any("error", errs[i]),
+			)
+			failed = append(failed, fmt.Errorf("%s: %w", fetcher.FetcherName(), errs[i]))
+			continue
+		}
+		results = append(results, sourceResult[T]{source: fetcher.FetcherName(), value: values[i]})
+	}
+	if len(results) == 0 {
+		return nil, errors.Join(failed...)
+	}
+
+	f.log(ctx).Debug("фото получены из нескольких источников",
+		slog.String("operation", operation),
+		slog.Int("answered", len(results)),
+		slog.Int("failed", len(failed)),
+	)
+	return results, nil
+}
+
+// sourcePhotos — фото одного источника для interleave
+type sourcePhotos struct {
+	source string
+	photos []domain.Photo
+}
+
+// interleave перемежает фото источников по одному, пропуская повторы по OriginalURL,
+// пока не наберётся limit (limit <= 0 — без предела). Фото без ExternalSource получают
+// имя своего источника. Возвращает фото и источники, чьи фото в них вошли, в порядке pages
+func interleave(pages []sourcePhotos, limit int) ([]domain.Photo, []string) {
+	var (
+		photos      []domain.Photo
+		seen        = make(map[string]struct{})
+		contributed = make([]bool, len(pages))
+	)
+	for i := 0; ; i++ {
+		left := false
+		for p, page := range pages {
+			if limit > 0 && len(photos) >= limit {
+				break
+			}
+			if i >= len(page.photos) {
+				continue
+			}
+			left = true
+
+			photo := page.photos[i]
+			if key := originalURLKey(photo.OriginalURL); key != "" {
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+			}
+			if photo.ExternalSource == "" {
+				photo.ExternalSource = page.source
+			}
+			photos = append(photos, photo)
+			contributed[p] = true
+		}
+		if !left || (limit > 0 && len(photos) >= limit) {
+			break
+		}
+	}
+
+	var sources []string
+	for p, page := range pages {
+		if contributed[p] {
+			sources = append(sources, page.source)
+		}
+	}
+	return photos, sources
+}
+
+// originalURLKey возвращает хеш OriginalURL для поиска повторов. Учитываются только хост
+// (без учёта регистра) и путь: параметры запроса у одного и того же файла бывают разными,
+// например ixid у Unsplash. Пустая строка — адреса нет, сравнивать не с чем
+func originalURLKey(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	normalized := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		normalized = strings.ToLower(u.Host) + u.EscapedPath()
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package multisource
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// ErrCircuitOpen — источник пропущен без запроса: он был недоступен Breaker.Failures раз подряд
+// и отключён на Breaker.Cooldown. Оборачивает domain.ErrSourceUnavailable, поэтому MultiFetcher
+// переходит к следующему источнику, а вызывающие видят обычную недоступность источника
+var ErrCircuitOpen = fmt.Errorf("источник фото временно отключён после серии ошибок: %w", domain.ErrSourceUnavailable)
+
+// Breaker — настройки автомата, который отключает недоступный источник, чтобы не тратить на него
+// время каждого запроса: после Failures ошибок недоступности подряд (лимит, 5xx, сеть) источник
+// пропускается Cooldown, затем получает один пробный запрос. Успешный пробный запрос включает источник,
+// неуспешный отключает его ещё на Cooldown. Failures 0 — автомат выключен
+type Breaker struct {
+	Failures int
+	Cooldown time.Duration
+}
+
+// breakerFetcher — источник фото за автоматом Breaker
+type breakerFetcher struct {
+	usecase.PhotoFetcher
+	settings Breaker
+	now      func() time.Time
+	logger   *slog.Logger
+
+	mu          sync.Mutex
+	consecutive int       // ошибок недоступности подряд
+	openUntil   time.Time // до какого момента источник отключён
+	probing     bool      // пробный запрос после Cooldown ещё выполняется
+}
+
+func newBreakerFetcher(fetcher usecase.PhotoFetcher, settings Breaker, logger *slog.Logger) *breakerFetcher {
+	return &breakerFetcher{PhotoFetcher: fetcher, settings: settings, now: time.Now, logger: logger}
+}
+
+// FetchPhotoByIDFromExternal реализует метод PhotoFetcher
+func (b *breakerFetcher) FetchPhotoByIDFromExternal(ctx context.Context, id string) (*domain.Photo, error) {
+	return guard(ctx, b, func() (*domain.Photo, error) {
+		return b.PhotoFetcher.FetchPhotoByIDFromExternal(ctx, id)
+	})
+}
+
+// SearchPhotosFromExternal реализует метод PhotoFetcher
+func (b *breakerFetcher) SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (domain.PhotoPage, error) {
+	return guard(ctx, b, func() (domain.PhotoPage, error) {
+		return b.PhotoFetcher.SearchPhotosFromExternal(ctx, query, page, perPage, orientation, color, orderBy)
+	})
+}
+
+// ListNewPhotosFromExternal реализует метод PhotoFetcher
+func (b *breakerFetcher) ListNewPhotosFromExternal(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	return guard(ctx, b, func() ([]domain.Photo, error) {
+		return b.PhotoFetcher.ListNewPhotosFromExternal(ctx, page, perPage)
+	})
+}
+
+// guard выполняет call, если автомат источника не отключил его, и учитывает результат
+func guard[T any](ctx context.Context, b *breakerFetcher, call func() (T, error)) (T, error) {
+	if err := b.allow(); err != nil {
+		var zero T
+		return zero, err
+	}
+	result, err := call()
+	b.record(ctx, err)
+	return result, err
+}
+
+// allow возвращает ErrCircuitOpen, пока источник отключён. После Cooldown пропускает
+// один пробный запрос, а остальные отклоняет, пока он не завершится
+func (b *breakerFetcher) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.settings.Failures <= 0 || b.consecutive < b.settings.Failures {
+		return nil
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return fmt.Errorf("до %s: %w", b.openUntil.Format(time.RFC3339), ErrCircuitOpen)
+	}
+	b.probing = true
+	return nil
+}
+
+// record учитывает ответ источника. Любой ответ, кроме недоступности, — даже «не найдено» —
+// значит, что источник работает. Отмена запроса вызывающим ничего не говорит об источнике
+func (b *breakerFetcher) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if b.settings.Failures <= 0 {
+		return
+	}
+
+	switch {
+	case err != nil && isUnavailable(err):
+		b.consecutive++
+		if b.consecutive >= b.settings.Failures {
+			b.openUntil = b.now().Add(b.settings.Cooldown)
+			b.log(ctx).Warn("источник фото отключён после серии ошибок",
+				slog.String("source", b.FetcherName()),
+				slog.Int("failures", b.consecutive),
+				slog.Time("until", b.openUntil),
+				slog.Any("error", err),
+			)
+		}
+	case err == nil || ctx.Err() == nil:
+		if b.consecutive >= b.settings.Failures {
+			b.log(ctx).Info("источник фото снова доступен", slog.String("source", b.FetcherName()))
+		}
+		b.consecutive = 0
+	}
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (b *breakerFetcher) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, b.logger)
+}
@@ -0,0 +1,139 @@
+package multisource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// newTestBreakerFetcher возвращает источник за автоматом с часами, которые двигает тест
+func newTestBreakerFetcher(source *fakeSource, settings Breaker) (*breakerFetcher, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newBreakerFetcher(source, settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{name: "unsplash", err: fmt.Errorf("unsplash: %w", domain.ErrSourceUnavailable)}
+	b, now := newTestBreakerFetcher(source, Breaker{Failures: 3, Cooldown: time.Minute})
+
+	for range 3 {
+		if _, err := b.SearchPhotosFromExternal(ctx, "cats", 1, 10, "", "", ""); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("breaker opened before %d failures: %v", 3, err)
+		}
+	}
+
+	_, err := b.SearchPhotosFromExternal(ctx, "cats", 1, 10, "", "", "")
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, domain.ErrSourceUnavailable) {
+		t.Fatalf("err = %v, want ErrCircuitOpen wrapping ErrSourceUnavailable", err)
+	}
+	if got := source.calls.Load(); got != 3 {
+		t.Errorf("source calls = %d, want 3: an open breaker must not reach the source", got)
+	}
+
+	// после Cooldown проходит один пробный запрос; источник всё ещё лежит — автомат снова открыт
+	*now = now.Add(time.Minute)
+	if _, err := b.FetchPhotoByIDFromExternal(ctx, "abc"); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe after cooldown rejected: %v", err)
+	}
+	if _, err := b.ListNewPhotosFromExternal(ctx, 1, 10); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err after failed probe = %v, want ErrCircuitOpen", err)
+	}
+
+	// источник поднялся: удачный пробный запрос закрывает автомат
+	*now = now.Add(time.Minute)
+	source.err = nil
+	source.photos = []domain.Photo{sourcePhoto("u1", "https://images.unsplash.com/u1")}
+	for range 2 {
+		if _, err := b.ListNewPhotosFromExternal(ctx, 1, 10); err != nil {
+			t.Fatalf("ListNewPhotosFromExternal after recovery: %v", err)
+		}
+	}
+	if got := source.calls.Load(); got != 6 {
+		t.Errorf("source calls = %d, want 6", got)
+	}
+}
+
+func TestBreaker_IgnoresNonAvailabilityErrors(t *testing.T) {
+	unavailable := fmt.Errorf("unsplash: %w", domain.ErrSourceUnavailable)
+	tests := []struct {
+		name string
+		err  error
+		ctx  func() context.Context
+	}{
+		{name: "not found means the source works", err: fmt.Errorf("unsplash: %w", domain.ErrExternalPhotoNotFound),
+			ctx: context.Background},
+		{name: "canceled request says nothing about the source", err: context.Canceled, ctx: func() context.Context {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{name: "unsplash", err: unavailable}
+			b, _ := newTestBreakerFetcher(source, Breaker{Failures: 2, Cooldown: time.Minute})
+
+			_, _ = b.FetchPhotoByIDFromExternal(context.Background(), "abc")
+			source.err = tt.err
+			_, _ = b.FetchPhotoByIDFromExternal(tt.ctx(), "abc")
+			source.err = unavailable
+			_, _ = b.FetchPhotoByIDFromExternal(context.Background(), "abc")
+
+			_, err := b.FetchPhotoByIDFromExternal(context.Background(), "abc")
+			notFoundResets := errors.Is(tt.err, domain.ErrExternalPhotoNotFound)
+			if notFoundResets && errors.Is(err, ErrCircuitOpen) {
+				t.Errorf("breaker open after a non-consecutive failure: %v", err)
+			}
+			if !notFoundResets && !errors.Is(err, ErrCircuitOpen) {
+				t.Errorf("err = %v, want ErrCircuitOpen: cancellation must neither count nor reset", err)
+			}
+		})
+	}
+}
+
+func TestBreaker_DisabledWithZeroFailures(t *testing.T) {
+	source := &fakeSource{name: "unsplash", err: fmt.Errorf("unsplash: %w", domain.ErrSourceUnavailable)}
+	b, _ := newTestBreakerFetcher(source, Breaker{})
+
+	for range 10 {
+		if _, err := b.FetchPhotoByIDFromExternal(context.Background(), "abc"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("disabled breaker opened: %v", err)
+		}
+	}
+	if got := source.calls.Load(); got != 10 {
+		t.Errorf("source calls = %d, want 10", got)
+	}
+}
+
+func TestMultiFetcher_SkipsSourceWithOpenBreaker(t *testing.T) {
+	ctx := context.Background()
+	unsplash := &fakeSource{name: "unsplash", err: fmt.Errorf("unsplash: %w", domain.ErrSourceUnavailable)}
+	pexels := &fakeSource{name: "pexels", photos: []domain.Photo{sourcePhoto("p1", "https://images.pexels.com/p1")}}
+	f := NewMultiFetcher([]usecase.PhotoFetcher{unsplash, pexels}, ModeFallback, Breaker{Failures: 1, Cooldown: time.Hour},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for range 3 {
+		photos, err := f.ListNewPhotosFromExternal(ctx, 1, 10)
+		if err != nil || len(photos) != 1 {
+			t.Fatalf("ListNewPhotosFromExternal = %v, %v; want the photo from pexels", photoIDs(photos), err)
+		}
+	}
+	if got := unsplash.calls.Load(); got != 1 {
+		t.Errorf("unsplash calls = %d, want 1: after the first failure the chain must skip it", got)
+	}
+
+	pexels.err = fmt.Errorf("pexels: %w", domain.ErrSourceUnavailable)
+	if _, err := f.ListNewPhotosFromExternal(ctx, 1, 10); !errors.Is(err, domain.ErrSourceUnavailable) {
+		t.Errorf("err = %v, want ErrSourceUnavailable when every source is down", err)
+	}
+}
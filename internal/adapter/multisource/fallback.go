@@ -0,0 +1,107 @@
+package multisource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// searchFallback опрашивает источники по порядку. Страница дополняется результатами
+// следующих источников, пока в ней меньше perPage фото; Total — сумма по ответившим источникам,
+// TotalPages — наибольшее из них, Sources — источники, чьи фото вошли в страницу.
+// Ошибка источника, который только дополнял страницу, лишь логируется
+func (f *MultiFetcher) searchFallback(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (domain.PhotoPage, error) {
+	var (
+		merged  domain.PhotoPage
+		errs    []error
+		sources int // сколько источников ответило
+	)
+	for _, fetcher := range f.fetchers {
+		if sources > 0 && (len(merged.Photos) >= perPage || ctx.Err() != nil) {
+			break
+		}
+		result, err := fetcher.SearchPhotosFromExternal(ctx, query, page, perPage, orientation, color, orderBy)
+		if err != nil {
+			if sources > 0 {
+				f.log(ctx).Warn("источник фото не дополнил результаты поиска",
+					slog.String("source", fetcher.FetcherName()),
+					slog.Any("error", err),
+				)
+				continue
+			}
+			if ctx.Err() != nil || !isUnavailable(err) {
+				return domain.PhotoPage{}, err
+			}
+			f.log(ctx).Warn("источник фото недоступен, пробуем следующий",
+				slog.String("source", fetcher.FetcherName()),
+				slog.String("operation", "search_photos"),
+				slog.Any("error", err),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", fetcher.FetcherName(), err))
+			continue
+		}
+
+		if sources == 0 {
+			f.logSuccess(ctx, fetcher, len(errs))
+		} else {
+			f.log(ctx).Info("результаты поиска дополнены из следующего источника",
+				slog.String("source", fetcher.FetcherName()),
+				slog.Int("count", len(result.Photos)),
+			)
+		}
+		sources++
+
+		photos := result.Photos
+		if perPage > 0 {
+			photos = photos[:min(len(photos), perPage-len(merged.Photos))]
+		}
+		if len(photos) > 0 {
+			merged.Sources = append(merged.Sources, fetcher.FetcherName())
+		}
+		merged.Photos = append(merged.Photos, photos...)
+		merged.Total += result.Total
+		merged.TotalPages = max(merged.TotalPages, result.TotalPages)
+	}
+	if sources == 0 {
+		if len(errs) == 0 {
+			return domain.PhotoPage{}, errNoFetchers
+		}
+		return domain.PhotoPage{}, errors.Join(errs...)
+	}
+	return merged, nil
+}
+
+// fallback вызывает call для источников по порядку, пока очередной источник недоступен.
+// Если недоступны все, ошибки источников объединяются errors.Join: *domain.ErrRateLimited
+// в них по-прежнему находится через errors.As
+func fallback[T any](ctx context.Context, f *MultiFetcher, operation string, call func(usecase.PhotoFetcher) (T, error)) (T, error) {
+	var (
+		zero T
+		errs []error
+	)
+	for _, fetcher := range f.fetchers {
+		result, err := call(fetcher)
+		if err == nil {
+			f.logSuccess(ctx, fetcher, len(errs))
+			return result, nil
+		}
+		if ctx.Err() != nil || !isUnavailable(err) {
+			return zero, err
+		}
+		f.log(ctx).Warn("источник фото недоступен, пробуем следующий",
+			slog.String("source", fetcher.FetcherName()),
+			slog.String("operation", operation),
+			slog.Any("error", err),
+		)
+		errs = append(errs, fmt.Errorf("%s: %w", fetcher.FetcherName(), err))
+	}
+	if len(errs) == 0 {
+		return zero, errNoFetchers
+	}
+	return zero, errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package multisource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+func TestMultiFetcher_Fallback(t *testing.T) {
+	ctx := context.Background()
+	secondary := func() *fakeSource {
+		return &fakeSource{name: "pixabay", photos: []domain.Photo{
+			sourcePhoto("x1", "https://b/x1"), sourcePhoto("x2", "https://b/x2"),
+		}}
+	}
+
+	t.Run("unavailable primary falls back", func(t *testing.T) {
+		for name, primaryErr := range map[string]error{
+			"rate limited": &domain.ErrRateLimited{Source: "unsplash", ResetAt: time.Now().Add(time.Minute)},
+			"5xx":          fmt.Errorf("ответ 503: %w", domain.ErrSourceUnavailable),
+		} {
+			t.Run(name, func(t *testing.T) {
+				primary := &fakeSource{name: "unsplash", err: primaryErr}
+				backup := secondary()
+				f := newTestMultiFetcher(ModeFallback, primary, backup)
+
+				page, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 10, "", "", "")
+				if err != nil {
+					t.Fatalf("SearchPhotosFromExternal: %v", err)
+				}
+				if want := []string{"x1", "x2"}; !reflect.DeepEqual(photoIDs(page.Photos), want) {
+					t.Errorf("photos = %v, want %v", photoIDs(page.Photos), want)
+				}
+				if want := []string{"pixabay"}; !reflect.DeepEqual(page.Sources, want) {
+					t.Errorf("sources = %v, want %v", page.Sources, want)
+				}
+
+				photos, err := f.ListNewPhotosFromExternal(ctx, 1, 10)
+				if err != nil {
+					t.Fatalf("ListNewPhotosFromExternal: %v", err)
+				}
+				if len(photos) != 2 {
+					t.Errorf("new photos = %v, want the backup's 2", photoIDs(photos))
+				}
+			})
+		}
+	})
+
+	t.Run("other primary errors are returned", func(t *testing.T) {
+		primaryErr := errors.New("invalid API key")
+		backup := secondary()
+		f := newTestMultiFetcher(ModeFallback, &fakeSource{name: "unsplash", err: primaryErr}, backup)
+
+		if _, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 10, "", "", ""); !errors.Is(err, primaryErr) {
+			t.Errorf("err = %v, want the primary's error", err)
+		}
+		if n := backup.calls.Load(); n != 0 {
+			t.Errorf("backup calls = %d, want 0", n)
+		}
+	})
+
+	t.Run("full primary page is not topped up", func(t *testing.T) {
+		primary := &fakeSource{name: "unsplash", photos: []domain.Photo{sourcePhoto("u1", "https://a/u1"), sourcePhoto("u2", "https://a/u2")}}
+		backup := secondary()
+		f := newTestMultiFetcher(ModeFallback, primary, backup)
+
+		page, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 2, "", "", "")
+		if err != nil {
+			t.Fatalf("SearchPhotosFromExternal: %v", err)
+		}
+		if want := []string{"u1", "u2"}; !reflect.DeepEqual(photoIDs(page.Photos), want) {
+			t.Errorf("photos = %v, want %v", photoIDs(page.Photos), want)
+		}
+		if n := backup.calls.Load(); n != 0 {
+			t.Errorf("backup calls = %d, want 0", n)
+		}
+	})
+
+	t.Run("partial primary page is topped up", func(t *testing.T) {
+		primary := &fakeSource{name: "unsplash", photos: []domain.Photo{sourcePhoto("u1", "https://a/u1")}}
+		f := newTestMultiFetcher(ModeFallback, primary, secondary())
+
+		page, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 2, "", "", "")
+		if err != nil {
+			t.Fatalf("SearchPhotosFromExternal: %v", err)
+		}
+		if want := []string{"u1", "x1"}; !reflect.DeepEqual(photoIDs(page.Photos), want) {
+			t.Errorf("photos = %v, want %v", photoIDs(page.Photos), want)
+		}
+		if want := []string{"unsplash", "pixabay"}; !reflect.DeepEqual(page.Sources, want) {
+			t.Errorf("sources = %v, want %v", page.Sources, want)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+// Package multisource объединяет несколько внешних источников фото в один usecase.PhotoFetcher:
+// опрашивает их по порядку (ModeFallback) или все сразу (ModeAggregate)
+package multisource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// errNoFetchers возвращается, если источников нет
+var errNoFetchers = errors.New("не настроено ни одного источника фото")
+
+// Mode — как MultiFetcher опрашивает источники при поиске и запросе новых фото
+type Mode string
+
+const (
+	// ModeFallback — источники по порядку: следующий опрашивается, только если текущий недоступен
+	// (исчерпан лимит, 5xx, сетевая ошибка) или вернул неполную страницу поиска
+	ModeFallback Mode = "fallback"
+	// ModeAggregate — все источники сразу, их ответы перемежаются в одну страницу
+	ModeAggregate Mode = "aggregate"
+)
+
+// MultiFetcher объединяет источники фото. В ModeFallback поиск и список новых фото переходят
+// к следующему источнику, только если текущий недоступен; остальные ошибки возвращаются сразу.
+// Неполная страница поиска дополняется результатами следующих источников до perPage.
+// В ModeAggregate запрос уходит во все источники сразу, и их ответы перемежаются: первое фото
+// первого источника, первое фото второго и так далее, пока не наберётся perPage. Одно и то же
+// изображение, выложенное в двух источниках, попадает в страницу один раз — совпадение определяется
+// по хешу OriginalURL; ошибка источника лишь логируется, если ответил хотя бы один другой.
+// Фото по ID в обоих режимах ищется по порядку до первого найденного: ID принадлежит одному источнику
+type MultiFetcher struct {
+	fetchers []usecase.PhotoFetcher
+	mode     Mode
+	logger   *slog.Logger
+}
+
+// NewMultiFetcher создает новый экземпляр MultiFetcher. fetchers — в порядке приоритета;
+// он же задаёт порядок источников в перемежаемой странице. Неизвестный mode работает как ModeFallback.
+// Каждый источник ставится за свой автомат breaker: отключённый источник сразу отвечает ErrCircuitOpen
+func NewMultiFetcher(fetchers []usecase.PhotoFetcher, mode Mode, breaker Breaker, logger *slog.Logger) *MultiFetcher {
+	if mode != ModeAggregate {
+		mode = ModeFallback
+	}
+	guarded := make([]usecase.PhotoFetcher, len(fetchers))
+	for i, fetcher := range fetchers {
+		guarded[i] = newBreakerFetcher(fetcher, breaker, logger)
+	}
+	return &MultiFetcher{fetchers: guarded, mode: mode, logger: logger}
+}
+
+// FetcherName реализует метод PhotoFetcher: режим и имена источников через запятую
+func (f *MultiFetcher) FetcherName() string {
+	names := make([]string, 0, len(f.fetchers))
+	for _, fetcher := range f.fetchers {
+		names = append(names, fetcher.FetcherName())
+	}
+	return string(f.mode) + "(" + strings.Join(names, ",") + ")"
+}
+
+// FetchPhotoByIDFromExternal реализует метод PhotoFetcher. ID не говорит, из какого он источника,
+// поэтому «не найдено» тоже ведёт к следующему источнику. Если фото нет нигде,
+// возвращаются ошибки всех источников, объединённые errors.Join
+func (f *MultiFetcher) FetchPhotoByIDFromExternal(ctx context.Context, id string) (*domain.Photo, error) {
+	var errs []error
+	for _, fetcher := range f.fetchers {
+		photo, err := fetcher.FetchPhotoByIDFromExternal(ctx, id)
+		if err == nil && photo != nil {
+			f.logSuccess(ctx, fetcher, len(errs))
+			return photo, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("фото %s: %w", id, domain.ErrExternalPhotoNotFound)
+		}
+		if ctx.Err() != nil || !(errors.Is(err, domain.ErrExternalPhotoNotFound) || isUnavailable(err)) {
+			return nil, err
+		}
+		f.log(ctx).Warn("фото не получено из источника, пробуем следующий",
+			slog.String("source", fetcher.FetcherName()),
+			slog.String("id", id),
+			slog.Any("error", err),
+		)
+		errs = append(errs, fmt.Errorf("%s: %w", fetcher.FetcherName(), err))
+	}
+	if len(errs) == 0 {
+		return nil, errNoFetchers
+	}
+	return nil, errors.Join(errs...)
+}
+
+// SearchPhotosFromExternal реализует метод PhotoFetcher
+func (f *MultiFetcher) SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (domain.PhotoPage, error) {
+	if f.mode == ModeAggregate {
+		return f.searchAggregate(ctx, query, page, perPage, orientation, color, orderBy)
+	}
+	return f.searchFallback(ctx, query, page, perPage, orientation, color, orderBy)
+}
+
+// ListNewPhotosFromExternal реализует метод PhotoFetcher
+func (f *MultiFetcher) ListNewPhotosFromExternal(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	if f.mode == ModeAggregate {
+		return f.listAggregate(ctx, page, perPage)
+	}
+	return fallback(ctx, f, "list_photos", func(fetcher usecase.PhotoFetcher) ([]domain.Photo, error) {
+		return fetcher.ListNewPhotosFromExternal(ctx, page, perPage)
+	})
+}
+
+// isUnavailable сообщает, что источник временно не отвечает и стоит попробовать другой
+func isUnavailable(err error) bool {
+	var rateLimited *domain.ErrRateLimited
+	return errors.As(err, &rateLimited) || errors.Is(err, domain.ErrSourceUnavailable)
+}
+
+// logSuccess пишет, какой источник ответил; если до него пришлось пропустить другие — на уровне Info
+func (f *MultiFetcher) logSuccess(ctx context.Context, fetcher usecase.PhotoFetcher, skipped int) {
+	if skipped == 0 {
+		f.log(ctx).Debug("фото получены из основного источника", slog.String("source", fetcher.FetcherName()))
+		return
+	}
+	f.log(ctx).Info("фото получены из резервного источника",
+		slog.String("source", fetcher.FetcherName()),
+		slog.Int("skipped", skipped),
+	)
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (f *MultiFetcher) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, f.logger)
+}
@@ -0,0 +1,183 @@
+package multisource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// fakeSource — источник фото с заранее заданными ответами; err возвращается на любой запрос
+type fakeSource struct {
+	name   string
+	photos []domain.Photo
+	byID   map[string]domain.Photo
+	err    error
+	calls  atomic.Int32
+}
+
+func (s *fakeSource) FetcherName() string { return s.name }
+
+func (s *fakeSource) FetchPhotoByIDFromExternal(_ context.Context, id string) (*domain.Photo, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	photo, ok := s.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("фото %s: %w", id, domain.ErrExternalPhotoNotFound)
+	}
+	return &photo, nil
+}
+
+func (s *fakeSource) SearchPhotosFromExternal(_ context.Context, _ string, _, perPage int, _, _, _ string) (domain.PhotoPage, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return domain.PhotoPage{}, s.err
+	}
+	photos := s.photos[:min(len(s.photos), perPage)]
+	return domain.PhotoPage{Photos: photos, Total: len(s.photos), TotalPages: 1}, nil
+}
+
+func (s *fakeSource) ListNewPhotosFromExternal(_ context.Context, _, perPage int) ([]domain.Photo, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.photos[:min(len(s.photos), perPage)], nil
+}
+
+// sourcePhoto возвращает фото источника с оригиналом по адресу url
+func sourcePhoto(id, url string) domain.Photo {
+	return domain.Photo{UnsplashID: id, OriginalURL: url}
+}
+
+func photoIDs(photos []domain.Photo) []string {
+	ids := make([]string, len(photos))
+	for i, photo := range photos {
+		ids[i] = photo.UnsplashID
+	}
+	return ids
+}
+
+func newTestMultiFetcher(mode Mode, sources ...*fakeSource) *MultiFetcher {
+	fetchers := make([]usecase.PhotoFetcher, len(sources))
+	for i, source := range sources {
+		fetchers[i] = source
+	}
+	return NewMultiFetcher(fetchers, mode, Breaker{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestMultiFetcher_AggregateSearch(t *testing.T) {
+	ctx := context.Background()
+	unsplash := &fakeSource{name: "unsplash", photos: []domain.Photo{
+		sourcePhoto("u1", "https://images.unsplash.com/u1?ixid=a"),
+		sourcePhoto("u2", "https://images.unsplash.com/shared.jpg"),
+		sourcePhoto("u3", "https://images.unsplash.com/u3"),
+	}}
+	pexels := &fakeSource{name: "pexels", photos: []domain.Photo{
+		sourcePhoto("p1", "https://images.pexels.com/p1"),
+		// то же изображение, что u2, с другими параметрами и регистром хоста
+		sourcePhoto("p2", "https://IMAGES.unsplash.com/shared.jpg?w=1080"),
+		sourcePhoto("p3", "https://images.pexels.com/p3"),
+	}}
+
+	t.Run("interleaves and dedupes", func(t *testing.T) {
+		f := newTestMultiFetcher(ModeAggregate, unsplash, pexels)
+		page, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 10, "", "", "")
+		if err != nil {
+			t.Fatalf("SearchPhotosFromExternal: %v", err)
+		}
+		if want := []string{"u1", "p1", "u2", "u3", "p3"}; !reflect.DeepEqual(photoIDs(page.Photos), want) {
+			t.Errorf("photos = %v, want %v", photoIDs(page.Photos), want)
+		}
+		if page.Total != 6 || page.TotalPages != 1 {
+			t.Errorf("total = %d, pages = %d; want 6 and 1", page.Total, page.TotalPages)
+		}
+		if want := []string{"unsplash", "pexels"}; !reflect.DeepEqual(page.Sources, want) {
+			t.Errorf("sources = %v, want %v", page.Sources, want)
+		}
+		if page.Photos[1].ExternalSource != "pexels" {
+			t.Errorf("external source = %q, want the name of the answering source", page.Photos[1].ExternalSource)
+		}
+	})
+
+	t.Run("stops at perPage", func(t *testing.T) {
+		f := newTestMultiFetcher(ModeAggregate, unsplash, pexels)
+		page, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 3, "", "", "")
+		if err != nil {
+			t.Fatalf("SearchPhotosFromExternal: %v", err)
+		}
+		if want := []string{"u1", "p1", "u2"}; !reflect.DeepEqual(photoIDs(page.Photos), want) {
+			t.Errorf("photos = %v, want %v", photoIDs(page.Photos), want)
+		}
+	})
+
+	t.Run("one source failing", func(t *testing.T) {
+		broken := &fakeSource{name: "pixabay", err: domain.ErrSourceUnavailable}
+		f := newTestMultiFetcher(ModeAggregate, broken, pexels)
+		page, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 10, "", "", "")
+		if err != nil {
+			t.Fatalf("SearchPhotosFromExternal: %v", err)
+		}
+		if want := []string{"p1", "p2", "p3"}; !reflect.DeepEqual(photoIDs(page.Photos), want) {
+			t.Errorf("photos = %v, want %v", photoIDs(page.Photos), want)
+		}
+		if want := []string{"pexels"}; !reflect.DeepEqual(page.Sources, want) {
+			t.Errorf("sources = %v, want %v", page.Sources, want)
+		}
+	})
+
+	t.Run("all sources failing", func(t *testing.T) {
+		limited := &fakeSource{name: "unsplash", err: &domain.ErrRateLimited{Source: "unsplash", ResetAt: time.Now().Add(time.Minute)}}
+		broken := &fakeSource{name: "pexels", err: domain.ErrSourceUnavailable}
+		f := newTestMultiFetcher(ModeAggregate, limited, broken)
+		_, err := f.SearchPhotosFromExternal(ctx, "cats", 1, 10, "", "", "")
+		var rateLimited *domain.ErrRateLimited
+		if !errors.As(err, &rateLimited) || !errors.Is(err, domain.ErrSourceUnavailable) {
+			t.Errorf("err = %v, want both source errors joined", err)
+		}
+	})
+}
+
+func TestMultiFetcher_AggregateListNew(t *testing.T) {
+	unsplash := &fakeSource{name: "unsplash", photos: []domain.Photo{sourcePhoto("u1", "https://a/u1"), sourcePhoto("u2", "https://a/u2")}}
+	pexels := &fakeSource{name: "pexels", photos: []domain.Photo{sourcePhoto("p1", "https://b/p1")}}
+	f := newTestMultiFetcher(ModeAggregate, unsplash, pexels)
+
+	photos, err := f.ListNewPhotosFromExternal(context.Background(), 1, 10)
+	if err != nil {
+		t.Fatalf("ListNewPhotosFromExternal: %v", err)
+	}
+	if want := []string{"u1", "p1", "u2"}; !reflect.DeepEqual(photoIDs(photos), want) {
+		t.Errorf("photos = %v, want %v", photoIDs(photos), want)
+	}
+}
+
+func TestMultiFetcher_FetchPhotoByID(t *testing.T) {
+	ctx := context.Background()
+	for _, mode := range []Mode{ModeFallback, ModeAggregate} {
+		t.Run(string(mode), func(t *testing.T) {
+			unsplash := &fakeSource{name: "unsplash", byID: map[string]domain.Photo{"u1": sourcePhoto("u1", "https://a/u1")}}
+			pexels := &fakeSource{name: "pexels", byID: map[string]domain.Photo{"pexels-1": sourcePhoto("pexels-1", "https://b/1")}}
+			f := newTestMultiFetcher(mode, unsplash, pexels)
+
+			photo, err := f.FetchPhotoByIDFromExternal(ctx, "pexels-1")
+			if err != nil || photo.UnsplashID != "pexels-1" {
+				t.Fatalf("photo = %v, err = %v; want pexels-1 from the second source", photo, err)
+			}
+
+			if _, err := f.FetchPhotoByIDFromExternal(ctx, "missing"); !errors.Is(err, domain.ErrExternalPhotoNotFound) {
+				t.Errorf("err = %v, want domain.ErrExternalPhotoNotFound", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// internal/adapter/palette/extractor.go
+package palette
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // регистрация декодера для image.Decode
+	_ "image/jpeg" // регистрация декодера для image.Decode
+	_ "image/png"  // регистрация декодера для image.Decode
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	_ "golang.org/x/image/webp" // регистрация декодера для image.Decode
+)
+
+const (
+	// maxSamples — сколько пикселей берётся из изображения для кластеризации.
+	// Пиксели выбираются равномерной сеткой, так что палитра не зависит от размера фото
+	maxSamples = 10000
+
+	// maxIterations — предел итераций k-means; обычно сходится раньше
+	maxIterations = 20
+
+	// minAlpha — более прозрачные пиксели не участвуют в палитре
+	minAlpha = 0x8000
+)
+
+var tracer = tracing.Tracer("palette")
+
+// Extractor извлекает доминирующие цвета изображения кластеризацией k-means в пространстве RGB
+type Extractor struct {
+	logger *slog.Logger
+}
+
+// NewExtractor создает новый экземпляр Extractor
+func NewExtractor(logger *slog.Logger) *Extractor {
+	return &Extractor{logger: logger}
+}
+
+// point — цвет пикселя в RGB (0..255 по каждой компоненте)
+type point [3]float64
+
+// cluster — центр кластера и число пикселей в нём
+type cluster struct {
+	center point
+	size   int
+}
+
+// ExtractPalette декодирует изображение из r и возвращает до n доминирующих цветов вида "#rrggbb",
+// самый частый первым. Поддерживаются JPEG, PNG, GIF и WebP.
+// Результат детерминирован: одно и то же изображение всегда даёт одну и ту же палитру
+func (e *Extractor) ExtractPalette(ctx context.Context, r io.Reader, n int) (_ []string, err error) {
+	ctx, span := tracer.Start(ctx, "Palette.ExtractPalette")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if n <= 0 {
+		return nil, errors.New("количество цветов палитры должно быть положительным")
+	}
+
+	start := time.Now()
+
+	img, format, err := image.Decode(r)
+	if err != nil {
+		e.log(ctx).Warn("failed to decode image", "error", err)
+		return nil, fmt.Errorf("ошибка декодирования изображения: %w", err)
+	}
+
+	points := samplePixels(img)
+	span.SetAttributes(attribute.String("format", format), attribute.Int("samples", len(points)))
+	if len(points) == 0 {
+		return []string{}, nil
+	}
+
+	clusters, err := kmeans(ctx, points, n)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		colors = append(colors, fmt.Sprintf("#%02x%02x%02x",
+			uint8(math.Round(c.center[0])), uint8(math.Round(c.center[1])), uint8(math.Round(c.center[2]))))
+	}
+
+	e.log(ctx).Debug("palette extracted",
+		"format", format,
+		"samples", len(points),
+		"colors", colors,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return colors, nil
+}
+
+// samplePixels берёт не больше maxSamples непрозрачных пикселей равномерной сеткой
+func samplePixels(img image.Image) []point {
+	bounds := img.Bounds()
+	total := bounds.Dx() * bounds.Dy()
+	if total == 0 {
+		return nil
+	}
+	step := max(1, int(math.Sqrt(float64(total)/maxSamples)))
+
+	points := make([]point, 0, min(total, maxSamples*2))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < minAlpha {
+				continue
+			}
+			// RGBA возвращает компоненты, умноженные на альфу; возвращаем их к непрозрачному цвету
+			points = append(points, point{
+				float64(r) * 255 / float64(a),
+				float64(g) * 255 / float64(a),
+				float64(b) * 255 / float64(a),
+			})
+		}
+	}
+	return points
+}
+
+// kmeans разбивает точки на k кластеров (меньше, если различных цветов меньше k)
+// и возвращает их по убыванию размера. Начальные центры выбираются по k-means++
+// с фиксированным зерном, поэтому результат воспроизводим
+func kmeans(ctx context.Context, points []point, k int) ([]cluster, error) {
+	centers := initCenters(points, k)
+	assignment := make([]int, len(points))
+
+	var clusters []cluster
+	for iter := 0; iter < maxIterations; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		changed := false
+		for i, p := range points {
+			nearest := nearestCenter(centers, p)
+			if iter == 0 || assignment[i] != nearest {
+				assignment[i] = nearest
+				changed = true
+			}
+		}
+
+		sums := make([]point, len(centers))
+		clusters = make([]cluster, len(centers))
+		for i, p := range points {
+			c := assignment[i]
+			for j := range p {
+				sums[c][j] += p[j]
+			}
+			clusters[c].size++
+		}
+		for c := range centers {
+			if clusters[c].size > 0 {
+				for j := range sums[c] {
+					centers[c][j] = sums[c][j] / float64(clusters[c].size)
+				}
+			}
+			clusters[c].center = centers[c]
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	clusters = slices.DeleteFunc(clusters, func(c cluster) bool { return c.size == 0 })
+	slices.SortStableFunc(clusters, func(a, b cluster) int { return b.size - a.size })
+	return clusters, nil
+}
+
+// initCenters выбирает до k начальных центров по k-means++: каждый следующий центр
+// выбирается с вероятностью, пропорциональной квадрату расстояния до ближайшего уже выбранного
+func initCenters(points []point, k int) []point {
+	rng := rand.New(rand.NewPCG(1, uint64(len(points))))
+
+	centers := []point{points[rng.IntN(len(points))]}
+	dist := make([]float64, len(points))
+	for len(centers) < k {
+		var sum float64
+		for i, p := range points {
+			dist[i] = sqDistance(p, centers[nearestCenter(centers, p)])
+			sum += dist[i]
+		}
+		if sum == 0 {
+			break // все оставшиеся точки совпадают с уже выбранными центрами
+		}
+
+		target := rng.Float64() * sum
+		next := len(points) - 1
+		for i, d := range dist {
+			target -= d
+			if target < 0 {
+				next = i
+				break
+			}
+		}
+		centers = append(centers, points[next])
+	}
+	return centers
+}
+
+// nearestCenter возвращает индекс ближайшего к p центра
+func nearestCenter(centers []point, p point) int {
+	best, bestDist := 0, math.Inf(1)
+	for i, c := range centers {
+		if d := sqDistance(p, c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// sqDistance — квадрат евклидова расстояния между цветами
+func sqDistance(a, b point) float64 {
+	dr, dg, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dr*dr + dg*dg + db*db
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (e *Extractor) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, e.logger)
+}
@@ -0,0 +1,257 @@
+// internal/adapter/pexels/client.go
+package pexels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	baseURL = "https://api.pexels.com/v1"
+
+	// Pexels отдаёт не больше 80 фото на страницу
+	maxPerPage = 80
+
+	// maxErrorBodyBytes — сколько байт тела ответа с ошибкой попадает в лог и текст ошибки
+	maxErrorBodyBytes = 4 << 10
+)
+
+var tracer = tracing.Tracer("pexels")
+
+// orientations переводит ориентацию в терминах Unsplash в параметр Pexels
+var orientations = map[string]string{
+	"landscape": "landscape",
+	"portrait":  "portrait",
+	"squarish":  "square",
+}
+
+// colors переводит цвета Unsplash в названия Pexels (совпадающие не перечислены).
+// black_and_white у Pexels нет — такой фильтр не передаётся
+var colors = map[string]string{
+	"purple":  "violet",
+	"magenta": "pink",
+	"teal":    "turquoise",
+}
+
+// PexelsAPIClient представляет клиент для взаимодействия с Pexels API.
+// Числовые ID Pexels хранятся в domain.Photo.UnsplashID строкой с префиксом domain.PexelsIDPrefix
+// ("pexels-2014422"); FetchPhotoByIDFromExternal принимает ID и с префиксом, и без него
+type PexelsAPIClient struct {
+	httpClient *http.Client
+	apiKey     string
+	logger     *slog.Logger
+}
+
+// NewPexelsAPIClient создает новый экземпляр PexelsAPIClient
+func NewPexelsAPIClient(cfg *config.Config, logger *slog.Logger) *PexelsAPIClient {
+	return &PexelsAPIClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		apiKey:     cfg.PexelsAPIKey,
+		logger:     logger,
+	}
+}
+
+// FetcherName реализует метод PhotoFetcher
+func (c *PexelsAPIClient) FetcherName() string {
+	return domain.SourcePexels
+}
+
+// FetchPhotoByIDFromExternal реализует метод PhotoFetcher
+func (c *PexelsAPIClient) FetchPhotoByIDFromExternal(ctx context.Context, id string) (_ *domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "Pexels.FetchPhotoByID", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("pexels_id", id)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// ID Pexels — число; ID других источников здесь заведомо не найдутся
+	numericID := strings.TrimPrefix(id, domain.PexelsIDPrefix)
+	if _, err := strconv.ParseInt(numericID, 10, 64); err != nil {
+		c.log(ctx).Warn("ID не похож на ID Pexels", slog.String("pexels_id", id))
+		return nil, fmt.Errorf("фото с ID %s не найдено в Pexels: %w", id, domain.ErrExternalPhotoNotFound)
+	}
+
+	c.log(ctx).Info("запрос фото по ID из Pexels", slog.String("pexels_id", numericID))
+
+	var photo PexelsPhoto
+	status, err := c.doRequest(ctx, "/photos/"+numericID, nil, &photo)
+	if status == http.StatusNotFound {
+		c.log(ctx).Warn("фото не найдено в Pexels", slog.String("pexels_id", numericID))
+		return nil, fmt.Errorf("фото с ID %s не найдено в Pexels: %w", id, domain.ErrExternalPhotoNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapPexelsPhotoToDomain(&photo), nil
+}
+
+// SearchPhotosFromExternal реализует метод PhotoFetcher.
+// Сортировки у поиска Pexels нет, поэтому orderBy не передаётся
+func (c *PexelsAPIClient) SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (_ domain.PhotoPage, err error) {
+	ctx, span := tracer.Start(ctx, "Pexels.SearchPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("query", query), attribute.Int("page", page), attribute.Int("per_page", perPage),
+			attribute.String("orientation", orientation), attribute.String("color", color), attribute.String("order_by", orderBy)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("page", strconv.Itoa(page))
+	if o, ok := orientations[orientation]; ok {
+		params.Add("orientation", o)
+	}
+	if color != "" && color != "black_and_white" {
+		if mapped, ok := colors[color]; ok {
+			color = mapped
+		}
+		params.Add("color", color)
+	}
+
+	c.log(ctx).Info("поиск фото в Pexels API", slog.String("query", query), slog.Int("page", page), slog.Int("per_page", perPage),
+		slog.String("orientation", orientation), slog.String("color", color))
+
+	return c.listPhotos(ctx, "/search", params, perPage)
+}
+
+// ListNewPhotosFromExternal реализует метод PhotoFetcher.
+// Отдельного списка новых фото у Pexels нет — берётся подборка /curated, которая обновляется ежечасно
+func (c *PexelsAPIClient) ListNewPhotosFromExternal(ctx context.Context, page, perPage int) (_ []domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "Pexels.ListNewPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("page", page), attribute.Int("per_page", perPage)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	params := url.Values{}
+	params.Add("page", strconv.Itoa(page))
+
+	c.log(ctx).Info("запрос списка новых фото", slog.Int("page", page), slog.Int("per_page", perPage))
+
+	result, err := c.listPhotos(ctx, "/curated", params, perPage)
+	return result.Photos, err
+}
+
+// listPhotos запрашивает страницу фото эндпоинта path и маппит её в domain.Photo
+func (c *PexelsAPIClient) listPhotos(ctx context.Context, path string, params url.Values, perPage int) (domain.PhotoPage, error) {
+	params.Add("per_page", strconv.Itoa(min(max(perPage, 1), maxPerPage)))
+
+	var response PexelsPhotosResponse
+	if _, err := c.doRequest(ctx, path, params, &response); err != nil {
+		return domain.PhotoPage{}, err
+	}
+
+	domainPhotos := make([]domain.Photo, 0, len(response.Photos))
+	for i := range response.Photos {
+		domainPhotos = append(domainPhotos, *mapPexelsPhotoToDomain(&response.Photos[i]))
+	}
+
+	c.log(ctx).Info("ответ Pexels обработан", slog.Int("count", len(domainPhotos)), slog.Int("total_results", response.TotalResults))
+	return domain.PhotoPage{
+		Photos:     domainPhotos,
+		Total:      response.TotalResults,
+		TotalPages: domain.TotalPagesFor(response.TotalResults, perPage),
+	}, nil
+}
+
+// doRequest выполняет GET к path с ключом в заголовке Authorization и декодирует ответ в dst.
+// Код ответа возвращается и вместе с ошибкой, чтобы вызывающий распознал 404
+func (c *PexelsAPIClient) doRequest(ctx context.Context, path string, params url.Values, dst any) (int, error) {
+	endpoint := baseURL + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		c.log(ctx).Error("ошибка создания HTTP-запроса", slog.Any("error", err))
+		return 0, fmt.Errorf("ошибка создания HTTP-запроса к Pexels: %w", err)
+	}
+	req.Header.Set("Authorization", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.log(ctx).Error("ошибка выполнения HTTP-запроса к Pexels", slog.Any("error", err))
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("ошибка выполнения HTTP-запроса к Pexels: %w", err)
+		}
+		return 0, fmt.Errorf("%w: ошибка выполнения HTTP-запроса к Pexels: %w", domain.ErrSourceUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		c.log(ctx).Warn("Pexels API вернул ошибку", slog.Int("status", resp.StatusCode), slog.String("body", string(bodyBytes)))
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return resp.StatusCode, &domain.ErrRateLimited{Source: domain.SourcePexels, ResetAt: rateLimitReset(resp.Header)}
+		case resp.StatusCode >= http.StatusInternalServerError:
+			return resp.StatusCode, fmt.Errorf("%w: pexels API вернул статус %d: %s", domain.ErrSourceUnavailable, resp.StatusCode, string(bodyBytes))
+		default:
+			return resp.StatusCode, fmt.Errorf("pexels API вернул статус %d: %s", resp.StatusCode, string(bodyBytes))
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		c.log(ctx).Error("ошибка декодирования JSON", slog.Any("error", err))
+		return resp.StatusCode, fmt.Errorf("ошибка декодирования JSON ответа Pexels: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// mapPexelsPhotoToDomain преобразует PexelsPhoto в domain.Photo.
+// Лайков, просмотров и скачиваний Pexels не сообщает — они остаются нулевыми
+func mapPexelsPhotoToDomain(photo *PexelsPhoto) *domain.Photo {
+	originalURL := photo.Src.Original
+	if originalURL == "" {
+		originalURL = photo.Src.Large2x
+	}
+
+	return &domain.Photo{
+		ID:             uuid.New(),
+		UnsplashID:     domain.PexelsIDPrefix + strconv.FormatInt(photo.ID, 10),
+		ExternalSource: domain.SourcePexels,
+		ExternalID:     strconv.FormatInt(photo.ID, 10),
+		Title:          photo.Alt,
+		AuthorName:     photo.Photographer,
+		Width:          max(photo.Width, 0),
+		Height:         max(photo.Height, 0),
+		OriginalURL:    originalURL,
+	}
+}
+
+// rateLimitReset возвращает момент сброса лимита Pexels: X-Ratelimit-Reset — UNIX-время сброса.
+// Без заголовка ждём час: лимит Pexels считается по часам и по месяцам
+func rateLimitReset(h http.Header) time.Time {
+	if ts, err := strconv.ParseInt(h.Get("X-Ratelimit-Reset"), 10, 64); err == nil && ts > 0 {
+		if reset := time.Unix(ts, 0); reset.After(time.Now()) {
+			return reset
+		}
+	}
+	return time.Now().Add(time.Hour)
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (c *PexelsAPIClient) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, c.logger)
+}
@@ -0,0 +1,30 @@
+package pexels
+
+// PexelsPhotoSrc — ссылки на изображение в разных размерах
+type PexelsPhotoSrc struct {
+	Original string `json:"original"`
+	Large2x  string `json:"large2x"`
+	Large    string `json:"large"`
+	Medium   string `json:"medium"`
+}
+
+// PexelsPhoto — одно фото из ответа Pexels API (/photos/{id} или элемент массива photos)
+type PexelsPhoto struct {
+	ID              int64          `json:"id"`
+	Width           int            `json:"width"`
+	Height          int            `json:"height"`
+	URL             string         `json:"url"`
+	Photographer    string         `json:"photographer"`
+	PhotographerURL string         `json:"photographer_url"`
+	AvgColor        string         `json:"avg_color"`
+	Src             PexelsPhotoSrc `json:"src"`
+	Alt             string         `json:"alt"`
+}
+
+// PexelsPhotosResponse — ответ эндпоинтов /search и /curated
+type PexelsPhotosResponse struct {
+	TotalResults int           `json:"total_results"`
+	Page         int           `json:"page"`
+	PerPage      int           `json:"per_page"`
+	Photos       []PexelsPhoto `json:"photos"`
+}
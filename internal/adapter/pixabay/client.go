@@ -0,0 +1,305 @@
+// internal/adapter/pixabay/client.go
+package pixabay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	baseURL = "https://pixabay.com/api/" // Единственный эндпоинт Pixabay API для изображений
+
+	// Pixabay принимает per_page только в диапазоне 3..200
+	minPerPage = 3
+	maxPerPage = 200
+)
+
+var tracer = tracing.Tracer("pixabay")
+
+// orientations переводит ориентацию в терминах Unsplash в параметр Pixabay.
+// squarish у Pixabay нет — такой фильтр просто не передаётся
+var orientations = map[string]string{
+	"landscape": "horizontal",
+	"portrait":  "vertical",
+}
+
+// colors переводит цвета Unsplash в названия Pixabay (совпадающие не перечислены)
+var colors = map[string]string{
+	"black_and_white": "grayscale",
+	"purple":          "lilac",
+	"magenta":         "pink",
+	"teal":            "turquoise",
+}
+
+// PixabayAPIClient представляет клиент для взаимодействия с Pixabay API.
+// Числовые ID Pixabay хранятся в domain.Photo.UnsplashID строкой с префиксом domain.PixabayIDPrefix
+// ("pixabay-736877"); FetchPhotoByIDFromExternal принимает ID и с префиксом, и без него
+type PixabayAPIClient struct {
+	httpClient *http.Client
+	apiKey     string
+	logger     *slog.Logger
+}
+
+// NewPixabayAPIClient создает новый экземпляр PixabayAPIClient
+func NewPixabayAPIClient(cfg *config.Config, logger *slog.Logger) *PixabayAPIClient {
+	return &PixabayAPIClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		apiKey:     cfg.PixabayAPIKey,
+		logger:     logger,
+	}
+}
+
+// FetcherName реализует метод PhotoFetcher
+func (c *PixabayAPIClient) FetcherName() string {
+	return domain.SourcePixabay
+}
+
+// FetchPhotoByIDFromExternal реализует метод PhotoFetcher
+func (c *PixabayAPIClient) FetchPhotoByIDFromExternal(ctx context.Context, id string) (_ *domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "Pixabay.FetchPhotoByID", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("pixabay_id", id)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// ID Pixabay — число; ID других источников здесь заведомо не найдутся
+	numericID := strings.TrimPrefix(id, domain.PixabayIDPrefix)
+	if _, err := strconv.ParseInt(numericID, 10, 64); err != nil {
+		c.log(ctx).Warn("ID не похож на ID Pixabay", slog.String("pixabay_id", id))
+		return nil, fmt.Errorf("фото с ID %s не найдено в Pixabay: %w", id, domain.ErrExternalPhotoNotFound)
+	}
+
+	c.log(ctx).Info("запрос фото по ID из Pixabay", slog.String("pixabay_id", numericID))
+
+	params := url.Values{}
+	params.Add("id", numericID)
+
+	response, err := c.doRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Hits) == 0 {
+		c.log(ctx).Warn("фото не найдено в Pixabay", slog.String("pixabay_id", id))
+		return nil, fmt.Errorf("фото с ID %s не найдено в Pixabay: %w", id, domain.ErrExternalPhotoNotFound)
+	}
+	return mapPixabayPhotoToDomain(&response.Hits[0]), nil
+}
+
+// SearchPhotosFromExternal реализует метод PhotoFetcher
+func (c *PixabayAPIClient) SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (_ domain.PhotoPage, err error) {
+	ctx, span := tracer.Start(ctx, "Pixabay.SearchPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("query", query), attribute.Int("page", page), attribute.Int("per_page", perPage),
+			attribute.String("orientation", orientation), attribute.String("color", color), attribute.String("order_by", orderBy)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("page", strconv.Itoa(page))
+	if o, ok := orientations[orientation]; ok {
+		params.Add("orientation", o)
+	}
+	if color != "" {
+		if mapped, ok := colors[color]; ok {
+			color = mapped
+		}
+		params.Add("colors", color)
+	}
+	// relevant у Pixabay соответствует порядку по умолчанию (popular)
+	if orderBy == "latest" {
+		params.Add("order", "latest")
+	}
+
+	c.log(ctx).Info("поиск фото в Pixabay API", slog.String("query", query), slog.Int("page", page), slog.Int("per_page", perPage),
+		slog.String("orientation", orientation), slog.String("color", color), slog.String("order_by", orderBy))
+
+	return c.listPhotos(ctx, params, perPage)
+}
+
+// ListNewPhotosFromExternal реализует метод PhotoFetcher
+func (c *PixabayAPIClient) ListNewPhotosFromExternal(ctx context.Context, page, perPage int) (_ []domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "Pixabay.ListNewPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("page", page), attribute.Int("per_page", perPage)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	params := url.Values{}
+	params.Add("order", "latest")
+	params.Add("page", strconv.Itoa(page))
+
+	c.log(ctx).Info("запрос списка новых фото", slog.Int("page", page), slog.Int("per_page", perPage))
+
+	result, err := c.listPhotos(ctx, params, perPage)
+	return result.Photos, err
+}
+
+// listPhotos запрашивает страницу изображений и маппит её в domain.Photo.
+// per_page меньше минимума Pixabay запрашивается с запасом, а лишнее отбрасывается.
+// Total — число результатов, доступных через API (totalHits), а не всех найденных
+func (c *PixabayAPIClient) listPhotos(ctx context.Context, params url.Values, perPage int) (domain.PhotoPage, error) {
+	params.Add("per_page", strconv.Itoa(min(max(perPage, minPerPage), maxPerPage)))
+
+	response, err := c.doRequest(ctx, params)
+	if err != nil {
+		return domain.PhotoPage{}, err
+	}
+
+	hits := response.Hits
+	if perPage > 0 && len(hits) > perPage {
+		hits = hits[:perPage]
+	}
+
+	var domainPhotos []domain.Photo
+	for i := range hits {
+		domainPhotos = append(domainPhotos, *mapPixabayPhotoToDomain(&hits[i]))
+	}
+
+	c.log(ctx).Info("ответ Pixabay обработан", slog.Int("count", len(domainPhotos)), slog.Int("total_hits", response.TotalHits))
+	return domain.PhotoPage{
+		Photos:     domainPhotos,
+		Total:      response.TotalHits,
+		TotalPages: domain.TotalPagesFor(response.TotalHits, perPage),
+	}, nil
+}
+
+// doRequest выполняет запрос к /api/ с ключом и общими параметрами и декодирует ответ
+func (c *PixabayAPIClient) doRequest(ctx context.Context, params url.Values) (*PixabaySearchResponse, error) {
+	params.Set("key", c.apiKey)
+	params.Set("image_type", "photo")
+	endpoint := baseURL + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		c.log(ctx).Error("ошибка создания HTTP-запроса", slog.Any("error", err))
+		return nil, fmt.Errorf("ошибка создания HTTP-запроса к Pixabay: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// В тексте ошибки net/http есть URL вместе с ключом — не логируем его
+		c.log(ctx).Error("ошибка выполнения HTTP-запроса к Pixabay", slog.Any("error", redactKey(err, c.apiKey)))
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ошибка выполнения HTTP-запроса к Pixabay: %w", redactKey(err, c.apiKey))
+		}
+		return nil, fmt.Errorf("%w: ошибка выполнения HTTP-запроса к Pixabay: %w", domain.ErrSourceUnavailable, redactKey(err, c.apiKey))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		c.log(ctx).Warn("Pixabay API вернул ошибку", slog.Int("status", resp.StatusCode), slog.String("body", string(bodyBytes)))
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return nil, &domain.ErrRateLimited{Source: domain.SourcePixabay, ResetAt: rateLimitReset(resp.Header)}
+		case resp.StatusCode >= http.StatusInternalServerError:
+			return nil, fmt.Errorf("%w: pixabay API вернул статус %d: %s", domain.ErrSourceUnavailable, resp.StatusCode, string(bodyBytes))
+		default:
+			return nil, fmt.Errorf("pixabay API вернул статус %d: %s", resp.StatusCode, string(bodyBytes))
+		}
+	}
+
+	var response PixabaySearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		c.log(ctx).Error("ошибка декодирования JSON", slog.Any("error", err))
+		return nil, fmt.Errorf("ошибка декодирования JSON ответа Pixabay: %w", err)
+	}
+	return &response, nil
+}
+
+// mapPixabayPhotoToDomain преобразует PixabayHit в domain.Photo.
+// ID Pixabay хранится в UnsplashID — общем поле для ID во внешнем источнике — с префиксом domain.PixabayIDPrefix
+func mapPixabayPhotoToDomain(hit *PixabayHit) *domain.Photo {
+	tags := parseTags(hit.Tags)
+
+	// Описаний у Pixabay нет, поэтому заголовок собираем из тегов
+	title := strings.Join(tagNames(tags), ", ")
+
+	// Если нет ссылки на большое изображение, берём среднее
+	originalURL := hit.LargeImageURL
+	if originalURL == "" {
+		originalURL = hit.WebformatURL
+	}
+
+	return &domain.Photo{
+		ID:             uuid.New(),
+		UnsplashID:     domain.PixabayIDPrefix + strconv.FormatInt(hit.ID, 10),
+		ExternalSource: domain.SourcePixabay,
+		ExternalID:     strconv.FormatInt(hit.ID, 10),
+		Title:          title,
+		AuthorName:     hit.User,
+		Width:          max(hit.ImageWidth, 0), // размеры бывают не указаны — тогда 0
+		Height:         max(hit.ImageHeight, 0),
+		LikesCount:     hit.Likes,
+		OriginalURL:    originalURL,
+		ViewsCount:     hit.Views,
+		DownloadsCount: hit.Downloads,
+		Tags:           tags,
+	}
+}
+
+// parseTags разбирает строку тегов вида "nature, landscape, sky" в []domain.Tag,
+// пропуская пустые элементы
+func parseTags(raw string) []domain.Tag {
+	var tags []domain.Tag
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, domain.Tag{Name: name})
+		}
+	}
+	return tags
+}
+
+// tagNames возвращает имена тегов
+func tagNames(tags []domain.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// rateLimitReset возвращает момент сброса лимита Pixabay: X-RateLimit-Reset — секунды до сброса окна.
+// Без заголовка ждём минуту — столько длится окно лимита Pixabay
+func rateLimitReset(h http.Header) time.Time {
+	if secs, err := strconv.Atoi(h.Get("X-RateLimit-Reset")); err == nil && secs > 0 {
+		return time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	return time.Now().Add(time.Minute)
+}
+
+// redactKey убирает API-ключ из URL в ошибке net/http, сохраняя причину для errors.Is
+func redactKey(err error, key string) error {
+	var urlErr *url.Error
+	if key == "" || !errors.As(err, &urlErr) {
+		return err
+	}
+	return &url.Error{Op: urlErr.Op, URL: strings.ReplaceAll(urlErr.URL, key, "***"), Err: urlErr.Err}
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (c *PixabayAPIClient) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, c.logger)
+}
@@ -0,0 +1,54 @@
+package pixabay
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+func TestMapPixabayPhotoToDomain(t *testing.T) {
+	hit := &PixabayHit{
+		ID:            736877,
+		Tags:          "nature, , landscape ,sky",
+		WebformatURL:  "https://pixabay.com/get/web.jpg",
+		LargeImageURL: "https://pixabay.com/get/large.jpg",
+		ImageWidth:    4000,
+		ImageHeight:   -1,
+		Views:         120,
+		Downloads:     30,
+		Likes:         7,
+		User:          "jane",
+	}
+
+	photo := mapPixabayPhotoToDomain(hit)
+	if photo.UnsplashID != "pixabay-736877" {
+		t.Errorf("UnsplashID = %q, want the numeric ID with domain.PixabayIDPrefix", photo.UnsplashID)
+	}
+	if photo.ExternalSource != domain.SourcePixabay || photo.ExternalID != "736877" {
+		t.Errorf("external source/ID = %q/%q, want %q/%q", photo.ExternalSource, photo.ExternalID, domain.SourcePixabay, "736877")
+	}
+	if photo.Title != "nature, landscape, sky" {
+		t.Errorf("Title = %q, want the tags joined", photo.Title)
+	}
+	if want := []domain.Tag{{Name: "nature"}, {Name: "landscape"}, {Name: "sky"}}; !reflect.DeepEqual(photo.Tags, want) {
+		t.Errorf("Tags = %v, want %v", photo.Tags, want)
+	}
+	if photo.OriginalURL != hit.LargeImageURL {
+		t.Errorf("OriginalURL = %q, want the large image", photo.OriginalURL)
+	}
+	if photo.Width != 4000 || photo.Height != 0 {
+		t.Errorf("size = %dx%d, want 4000x0", photo.Width, photo.Height)
+	}
+	if photo.AuthorName != "jane" || photo.LikesCount != 7 || photo.ViewsCount != 120 || photo.DownloadsCount != 30 {
+		t.Errorf("author/counters = %q %d %d %d, want jane 7 120 30",
+			photo.AuthorName, photo.LikesCount, photo.ViewsCount, photo.DownloadsCount)
+	}
+
+	t.Run("falls back to the webformat image", func(t *testing.T) {
+		photo := mapPixabayPhotoToDomain(&PixabayHit{ID: 1, WebformatURL: "https://pixabay.com/get/web.jpg"})
+		if photo.OriginalURL != "https://pixabay.com/get/web.jpg" {
+			t.Errorf("OriginalURL = %q, want the webformat image", photo.OriginalURL)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package pixabay
+
+// PixabayHit — одно изображение из ответа Pixabay API (элемент массива hits)
+type PixabayHit struct {
+	ID            int64  `json:"id"`
+	PageURL       string `json:"pageURL"`
+	Type          string `json:"type"`
+	Tags          string `json:"tags"` // теги через запятую: "nature, landscape, sky"
+	PreviewURL    string `json:"previewURL"`
+	WebformatURL  string `json:"webformatURL"`
+	LargeImageURL string `json:"largeImageURL"`
+	ImageWidth    int    `json:"imageWidth"`
+	ImageHeight   int    `json:"imageHeight"`
+	ImageSize     int64  `json:"imageSize"`
+	Views         int64  `json:"views"`
+	Downloads     int64  `json:"downloads"`
+	Likes         int    `json:"likes"`
+	Comments      int    `json:"comments"`
+	UserID        int64  `json:"user_id"`
+	User          string `json:"user"`
+	UserImageURL  string `json:"userImageURL"`
+}
+
+// PixabaySearchResponse — ответ эндпоинта /api/ (и поиска, и запроса по id)
+type PixabaySearchResponse struct {
+	Total     int          `json:"total"`
+	TotalHits int          `json:"totalHits"` // сколько результатов реально доступно через API
+	Hits      []PixabayHit `json:"hits"`
+}
@@ -2,9 +2,12 @@ package minio
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -16,18 +19,38 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 
 	appconfig "github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = tracing.Tracer("minio")
+
 // Client представляет собой клиент для взаимодействия с MinIO (S3-совместимым хранилищем)
 type Client struct {
 	s3Client   *s3.Client
 	uploader   *manager.Uploader
+	presigner  *s3.PresignClient
 	bucketName string
+	metrics    *metrics.Metrics
 	logger     *slog.Logger
 }
 
-// NewMinioClient создает и инициализирует новый MinIO Client, используя переданную конфигурацию
-func NewMinioClient(cfg *appconfig.Config, logger *slog.Logger) (*Client, error) {
+// Ошибки подготовки бакета при старте
+var (
+	// ErrBucketCheckTimeout — проверка или ожидание бакета не уложились в MINIO_BOOTSTRAP_TIMEOUT
+	// (или старт отменён через ctx)
+	ErrBucketCheckTimeout = errors.New("minio: bucket check timed out")
+	// ErrBucketCreateFailed — бакета нет, и создать его не удалось
+	ErrBucketCreateFailed = errors.New("minio: bucket create failed")
+)
+
+// NewMinioClient создает и инициализирует новый MinIO Client, используя переданную конфигурацию.
+// Проверка, создание бакета и ожидание его готовности ограничены ctx и cfg.MinioBootstrapTimeout
+func NewMinioClient(ctx context.Context, cfg *appconfig.Config, m *metrics.Metrics, logger *slog.Logger) (*Client, error) {
 	minioAccessKey := cfg.MinioAccessKeyID
 	minioSecretKey := cfg.MinioSecretAccessKey
 	minioBucketName := cfg.MinioBucketName
@@ -46,7 +69,11 @@ func NewMinioClient(cfg *appconfig.Config, logger *slog.Logger) (*Client, error)
 		fullMinioEndpointURL = fmt.Sprintf("http://%s", minioEndpoint)
 	}
 
-	cfgAws, err := awsconfig.LoadDefaultConfig(context.TODO(),
+	// Один дедлайн на всю подготовку бакета: проверку, создание и ожидание
+	ctx, cancel := context.WithTimeout(ctx, cfg.MinioBootstrapTimeout)
+	defer cancel()
+
+	cfgAws, err := awsconfig.LoadDefaultConfig(ctx,
 		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(minioAccessKey, minioSecretKey, "")),
 		awsconfig.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(
 			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
@@ -75,95 +102,145 @@ func NewMinioClient(cfg *appconfig.Config, logger *slog.Logger) (*Client, error)
 	// uploader.LeavePartsOnError = true    // Не удалять части при ошибке
 	// ----------------------------
 
-	// Проверяем существование бакета
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	_, err = s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
-		Bucket: aws.String(minioBucketName),
-	})
-
-	if err != nil {
-		logger.Warn("bucket not found, creating...", "bucket", minioBucketName)
-
-		_, createErr := s3Client.CreateBucket(context.TODO(), &s3.CreateBucketInput{
-			Bucket: aws.String(minioBucketName),
-			// Для MinIO может потребоваться явное указание региона
-			CreateBucketConfiguration: &types.CreateBucketConfiguration{
-				LocationConstraint: types.BucketLocationConstraint(minioRegion),
-			},
-		})
-
-		if createErr != nil {
-			logger.Error("failed to create bucket", "bucket", minioBucketName, "error", createErr)
-			return nil, fmt.Errorf("failed to create bucket '%s': %w", minioBucketName, createErr)
-		}
-
-		// Ждем пока бакет станет доступен
-		waiter := s3.NewBucketExistsWaiter(s3Client)
-		if err := waiter.Wait(context.TODO(), &s3.HeadBucketInput{
-			Bucket: aws.String(minioBucketName),
-		}, 30*time.Second); err != nil {
-			logger.Error("failed waiting for bucket to be created", "bucket", minioBucketName, "error", err)
-			return nil, fmt.Errorf("failed waiting for bucket '%s' to be created: %w", minioBucketName, err)
-		}
-
-		logger.Info("bucket created successfully", "bucket", minioBucketName)
-	} else {
-		logger.Info("bucket already exists", "bucket", minioBucketName)
+	if err := ensureBucket(ctx, s3Client, minioBucketName, minioRegion, cfg.MinioBootstrapTimeout, logger); err != nil {
+		return nil, err
 	}
 
 	return &Client{
 		s3Client:   s3Client,
 		uploader:   uploader,
+		presigner:  s3.NewPresignClient(s3Client),
 		bucketName: minioBucketName,
+		metrics:    m,
 		logger:     logger,
 	}, nil
 }
 
-// UploadFile загружает файл в указанный бакет MinIO
-func (c *Client) UploadFile(ctx context.Context, objectKey string, fileContent io.Reader, contentType string) (string, error) {
+// ensureBucket проверяет существование бакета и создаёт его, если нужно.
+// Истечение ctx на любом шаге возвращается как ErrBucketCheckTimeout, ошибка создания — как ErrBucketCreateFailed
+func ensureBucket(ctx context.Context, s3Client *s3.Client, bucket, region string, timeout time.Duration, logger *slog.Logger) error {
+	_, err := s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err == nil {
+		logger.Info("bucket already exists", "bucket", bucket)
+		return nil
+	}
+	if ctx.Err() != nil {
+		logger.Error("bucket check timed out", "bucket", bucket, "timeout", timeout, "error", err)
+		return fmt.Errorf("%w: bucket '%s' (timeout %s): %w", ErrBucketCheckTimeout, bucket, timeout, err)
+	}
+
+	logger.Warn("bucket not found, creating...", "bucket", bucket)
+
+	_, err = s3Client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+		// Для MinIO может потребоваться явное указание региона
+		CreateBucketConfiguration: &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(region),
+		},
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			logger.Error("bucket create timed out", "bucket", bucket, "timeout", timeout, "error", err)
+			return fmt.Errorf("%w: creating bucket '%s' (timeout %s): %w", ErrBucketCheckTimeout, bucket, timeout, err)
+		}
+		logger.Error("failed to create bucket", "bucket", bucket, "error", err)
+		return fmt.Errorf("%w: '%s': %w", ErrBucketCreateFailed, bucket, err)
+	}
+
+	// Ждем пока бакет станет доступен, но не дольше оставшегося времени
+	maxWait := timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = time.Until(deadline)
+	}
+	if maxWait <= 0 {
+		return fmt.Errorf("%w: waiting for bucket '%s' (timeout %s)", ErrBucketCheckTimeout, bucket, timeout)
+	}
+	waiter := s3.NewBucketExistsWaiter(s3Client)
+	if err := waiter.Wait(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	}, maxWait); err != nil {
+		logger.Error("failed waiting for bucket to be created", "bucket", bucket, "timeout", timeout, "error", err)
+		return fmt.Errorf("%w: waiting for bucket '%s' (timeout %s): %w", ErrBucketCheckTimeout, bucket, timeout, err)
+	}
+
+	logger.Info("bucket created successfully", "bucket", bucket)
+	return nil
+}
+
+// mimeTypeMetadataKey — ключ пользовательских метаданных объекта (x-amz-meta-mime-type) с MIME-типом
+const mimeTypeMetadataKey = "mime-type"
+
+// UploadFile загружает файл в указанный бакет MinIO и возвращает URL и число записанных байт.
+// MIME-тип сохраняется и как Content-Type, и в метаданных объекта
+func (c *Client) UploadFile(ctx context.Context, objectKey string, fileContent io.Reader, contentType string) (string, int64, error) {
+	ctx, span := tracer.Start(ctx, "S3.UploadFile", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("s3_key", objectKey),
+		attribute.String("s3_bucket", c.bucketName),
+		attribute.String("content_type", contentType),
+	))
+	defer span.End()
+
 	start := time.Now()
 
+	// считаем байты по мере чтения, так как размер потока заранее неизвестен
+	body := &countingReader{r: fileContent}
+
 	uploadOutput, err := c.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(c.bucketName),
 		Key:         aws.String(objectKey),
-		Body:        fileContent,
+		Body:        body,
 		ContentType: aws.String(contentType),
+		Metadata:    map[string]string{mimeTypeMetadataKey: contentType},
 	})
+	c.metrics.ObserveS3Upload(body.n, time.Since(start), err)
+	span.SetAttributes(attribute.Int64("bytes", body.n))
+	tracing.RecordError(span, err)
 	if err != nil {
-		c.logger.Error("failed to upload file",
+		c.log(ctx).Error("failed to upload file",
 			"bucket", c.bucketName,
 			"object", objectKey,
 			"error", err,
 		)
-		return "", fmt.Errorf("failed to upload file %s to bucket %s using multipart upload: %w", objectKey,
+		return "", 0, fmt.Errorf("failed to upload file %s to bucket %s using multipart upload: %w", objectKey,
 			c.bucketName, err)
 	}
 
 	duration := time.Since(start)
-	c.logger.Info("file uploaded successfully",
+	c.log(ctx).Info("file uploaded successfully",
 		"bucket", c.bucketName,
 		"object", objectKey,
 		"location", uploadOutput.Location,
+		"bytes", body.n,
 		"duration_ms", duration.Milliseconds(),
 	)
 
-	return fmt.Sprintf("%s/%s/%s", "http://localhost:9000", c.bucketName, objectKey), nil
+	return c.objectURL(objectKey), body.n, nil
+}
+
+// objectURL возвращает публичный URL объекта
+func (c *Client) objectURL(objectKey string) string {
+	return fmt.Sprintf("%s/%s/%s", "http://localhost:9000", c.bucketName, objectKey)
 }
 
 // GetFile получает содержимое файла из MinIO
 func (c *Client) GetFile(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	ctx, span := tracer.Start(ctx, "S3.GetFile", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("s3_key", objectKey)))
+	defer span.End()
+
 	start := time.Now()
 	output, err := c.s3Client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(objectKey),
 	})
+	tracing.RecordError(span, err)
 	if err != nil {
-		c.logger.Error("failed to get file", "bucket", c.bucketName, "object", objectKey, "error", err)
+		c.log(ctx).Error("failed to get file", "bucket", c.bucketName, "object", objectKey, "error", err)
 		return nil, fmt.Errorf("failed to get file %s from bucket %s: %w", objectKey, c.bucketName, err)
 	}
-	c.logger.Info("file fetched successfully",
+	c.log(ctx).Info("file fetched successfully",
 		"bucket", c.bucketName,
 		"object", objectKey,
 		"duration_ms", time.Since(start).Milliseconds(),
@@ -171,16 +248,260 @@ func (c *Client) GetFile(ctx context.Context, objectKey string) (io.ReadCloser,
 	return output.Body, nil
 }
 
+// StatFile получает размер, MIME-тип и время изменения объекта через HeadObject, не скачивая его.
+// Если объекта нет, ошибка оборачивает usecase.ErrFileNotFound
+func (c *Client) StatFile(ctx context.Context, objectKey string) (usecase.FileInfo, error) {
+	ctx, span := tracer.Start(ctx, "S3.StatFile", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("s3_key", objectKey)))
+	defer span.End()
+
+	output, err := c.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucketName),
+		Key:    aws.String(objectKey),
+	})
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		c.log(ctx).Info("file not found", "bucket", c.bucketName, "object", objectKey)
+		return usecase.FileInfo{}, fmt.Errorf("file %s not found in bucket %s: %w", objectKey, c.bucketName, usecase.ErrFileNotFound)
+	}
+	tracing.RecordError(span, err)
+	if err != nil {
+		c.log(ctx).Error("failed to stat file", "bucket", c.bucketName, "object", objectKey, "error", err)
+		return usecase.FileInfo{}, fmt.Errorf("failed to stat file %s in bucket %s: %w", objectKey, c.bucketName, err)
+	}
+
+	return usecase.FileInfo{
+		Key:          objectKey,
+		Size:         aws.ToInt64(output.ContentLength),
+		LastModified: aws.ToTime(output.LastModified),
+		ContentType:  aws.ToString(output.ContentType),
+		URL:          c.objectURL(objectKey),
+	}, nil
+}
+
+// GeneratePresignedPutURL подписывает запрос PUT объекта objectKey, действующий expiry.
+// В подпись входят Content-Type и Content-Length (size), поэтому хранилище примет только файл
+// заявленного типа и размера. Без размера SDK убирает Content-Type из подписи, так что size обязателен
+func (c *Client) GeneratePresignedPutURL(ctx context.Context, objectKey, contentType string, size int64, expiry time.Duration) (string, error) {
+	ctx, span := tracer.Start(ctx, "S3.GeneratePresignedPutURL", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("s3_key", objectKey),
+		attribute.String("s3_bucket", c.bucketName),
+		attribute.String("content_type", contentType),
+		attribute.Int64("bytes", size),
+	))
+	defer span.End()
+
+	if size <= 0 {
+		err := fmt.Errorf("failed to presign upload of %s: size must be positive, got %d", objectKey, size)
+		tracing.RecordError(span, err)
+		return "", err
+	}
+
+	request, err := c.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(c.bucketName),
+		Key:           aws.String(objectKey),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(expiry))
+	tracing.RecordError(span, err)
+	if err != nil {
+		c.log(ctx).Error("failed to presign upload", "bucket", c.bucketName, "object", objectKey, "error", err)
+		return "", fmt.Errorf("failed to presign upload of %s to bucket %s: %w", objectKey, c.bucketName, err)
+	}
+
+	c.log(ctx).Info("upload url presigned",
+		"bucket", c.bucketName,
+		"object", objectKey,
+		"content_type", contentType,
+		"bytes", size,
+		"expiry", expiry,
+	)
+	return request.URL, nil
+}
+
 // DeleteFile удаляет файл из MinIO
 func (c *Client) DeleteFile(ctx context.Context, objectKey string) error {
+	ctx, span := tracer.Start(ctx, "S3.DeleteFile", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("s3_key", objectKey)))
+	defer span.End()
+
 	_, err := c.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(objectKey),
 	})
+	tracing.RecordError(span, err)
 	if err != nil {
-		c.logger.Error("failed to delete file", "bucket", c.bucketName, "object", objectKey, "error", err)
+		c.log(ctx).Error("failed to delete file", "bucket", c.bucketName, "object", objectKey, "error", err)
 		return fmt.Errorf("failed to delete file %s from bucket %s: %w", objectKey, c.bucketName, err)
 	}
-	c.logger.Info("file deleted successfully", "bucket", c.bucketName, "object", objectKey)
+	c.log(ctx).Info("file deleted successfully", "bucket", c.bucketName, "object", objectKey)
+	return nil
+}
+
+// deleteObjectsBatchSize — максимум ключей в одном запросе DeleteObjects (ограничение S3 API)
+const deleteObjectsBatchSize = 1000
+
+// DeleteFilesError — часть объектов не удалось удалить; Failed содержит ключ и причину
+type DeleteFilesError struct {
+	Failed map[string]string
+}
+
+func (e *DeleteFilesError) Error() string {
+	keys := make([]string, 0, len(e.Failed))
+	for key := range e.Failed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to delete %d object(s):", len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s (%s);", key, e.Failed[key])
+	}
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+// DeleteFiles удаляет объекты пачками через DeleteObjects (до 1000 ключей за запрос).
+// Ошибки отдельных ключей не прерывают удаление: они собираются в *DeleteFilesError.
+// Ошибка запроса целиком возвращается сразу, оставшиеся пачки не обрабатываются
+func (c *Client) DeleteFiles(ctx context.Context, keys []string) error {
+	ctx, span := tracer.Start(ctx, "S3.DeleteFiles", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("s3_keys.count", len(keys)), attribute.String("s3_bucket", c.bucketName)))
+	defer span.End()
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	deleted := 0
+	failed := make(map[string]string)
+
+	for from := 0; from < len(keys); from += deleteObjectsBatchSize {
+		batch := keys[from:min(from+deleteObjectsBatchSize, len(keys))]
+
+		objects := make([]types.ObjectIdentifier, 0, len(batch))
+		for _, key := range batch {
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		output, err := c.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(c.bucketName),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			tracing.RecordError(span, err)
+			c.log(ctx).Error("failed to delete files batch",
+				"bucket", c.bucketName,
+				"batch_size", len(batch),
+				"deleted", deleted,
+				"error", err,
+			)
+			return fmt.Errorf("failed to delete %d files from bucket %s (%d already deleted): %w",
+				len(keys)-deleted, c.bucketName, deleted, err)
+		}
+
+		// В режиме Quiet ответ содержит только ошибки
+		for _, e := range output.Errors {
+			failed[aws.ToString(e.Key)] = aws.ToString(e.Code) + ": " + aws.ToString(e.Message)
+		}
+		deleted += len(batch) - len(output.Errors)
+	}
+
+	c.log(ctx).Info("files deleted",
+		"bucket", c.bucketName,
+		"deleted", deleted,
+		"failed", len(failed),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	if len(failed) > 0 {
+		err := &DeleteFilesError{Failed: failed}
+		tracing.RecordError(span, err)
+		return err
+	}
 	return nil
 }
+
+// ObjectInfo — сведения об объекте в бакете
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ListObjects возвращает все объекты бакета с заданным префиксом (например, "unsplash-photos/"),
+// постранично через ListObjectsV2. Пустой префикс — весь бакет
+func (c *Client) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	ctx, span := tracer.Start(ctx, "S3.ListObjects", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("s3_prefix", prefix), attribute.String("s3_bucket", c.bucketName)))
+	defer span.End()
+
+	start := time.Now()
+
+	paginator := s3.NewListObjectsV2Paginator(c.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucketName),
+		Prefix: aws.String(prefix),
+	})
+
+	var objects []ObjectInfo
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			tracing.RecordError(span, err)
+			c.log(ctx).Error("failed to list objects", "bucket", c.bucketName, "prefix", prefix, "error", err)
+			return nil, fmt.Errorf("failed to list objects with prefix %s in bucket %s: %w", prefix, c.bucketName, err)
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+
+	span.SetAttributes(attribute.Int("count", len(objects)))
+	c.log(ctx).Info("objects listed successfully",
+		"bucket", c.bucketName,
+		"prefix", prefix,
+		"count", len(objects),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return objects, nil
+}
+
+// ListFiles реализует usecase.FileStorage поверх ListObjects
+func (c *Client) ListFiles(ctx context.Context, prefix string) ([]usecase.FileInfo, error) {
+	objects, err := c.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]usecase.FileInfo, 0, len(objects))
+	for _, obj := range objects {
+		files = append(files, usecase.FileInfo{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+		})
+	}
+	return files, nil
+}
+
+// countingReader подсчитывает количество прочитанных байт
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (c *Client) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, c.logger)
+}
@@ -0,0 +1,221 @@
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/GoArmGo/MediaApp/internal/database/sqlite"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+const testBucket = "photos"
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// fakeObjectStore — S3 в памяти: ListObjectsV2 постранично по pageSize ключей и DeleteObject
+type fakeObjectStore struct {
+	mu       sync.Mutex
+	pageSize int
+	objects  map[string]ObjectInfo
+	listed   int // сколько страниц ListObjectsV2 отдано
+}
+
+func (s *fakeObjectStore) put(key string, size int64, lastModified time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.objects == nil {
+		s.objects = make(map[string]ObjectInfo)
+	}
+	s.objects[key] = ObjectInfo{Key: key, Size: size, LastModified: lastModified.UTC().Truncate(time.Millisecond)}
+}
+
+func (s *fakeObjectStore) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Name                  string
+	Prefix                string
+	KeyCount              int
+	IsTruncated           bool
+	NextContinuationToken string `xml:",omitempty"`
+	Contents              []listedObject
+}
+
+type listedObject struct {
+	Key          string
+	Size         int64
+	LastModified string
+}
+
+func (s *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != testBucket {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && key == "" && r.URL.Query().Get("list-type") == "2":
+		s.list(w, r)
+	case r.Method == http.MethodDelete && key != "":
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "NotImplemented", http.StatusNotImplemented)
+	}
+}
+
+// list отдаёт ключи с префиксом по возрастанию; continuation-token — индекс первого ключа страницы
+func (s *fakeObjectStore) list(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	from, _ := strconv.Atoi(r.URL.Query().Get("continuation-token"))
+	to := min(from+s.pageSize, len(keys))
+	result := listBucketResult{Name: testBucket, Prefix: prefix, KeyCount: to - from, IsTruncated: to < len(keys)}
+	if result.IsTruncated {
+		result.NextContinuationToken = strconv.Itoa(to)
+	}
+	for _, key := range keys[from:to] {
+		obj := s.objects[key]
+		result.Contents = append(result.Contents, listedObject{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			LastModified: obj.LastModified.Format("2006-01-02T15:04:05.000Z"),
+		})
+	}
+	s.listed++
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+// newTestClient возвращает Client, который ходит в store вместо MinIO
+func newTestClient(t *testing.T, store *fakeObjectStore) *Client {
+	t.Helper()
+	srv := httptest.NewServer(store)
+	t.Cleanup(srv.Close)
+
+	s3Client := s3.New(s3.Options{
+		BaseEndpoint: aws.String(srv.URL),
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+		UsePathStyle: true,
+	})
+	return &Client{s3Client: s3Client, bucketName: testBucket, logger: discardLogger()}
+}
+
+func TestListObjects_Paginates(t *testing.T) {
+	store := &fakeObjectStore{pageSize: 2}
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, key := range []string{
+		"unsplash-photos/a", "unsplash-photos/b/1", "unsplash-photos/c", "unsplash-photos/d", "unsplash-photos/e",
+		"user-uploads/x",
+	} {
+		store.put(key, int64(100+i), modified.Add(time.Duration(i)*time.Minute))
+	}
+	c := newTestClient(t, store)
+
+	objects, err := c.ListObjects(context.Background(), "unsplash-photos/")
+	if err != nil {
+		t.Fatalf("ListObjects: %v", err)
+	}
+	var keys []string
+	for _, obj := range objects {
+		keys = append(keys, obj.Key)
+	}
+	if want := []string{"unsplash-photos/a", "unsplash-photos/b/1", "unsplash-photos/c", "unsplash-photos/d", "unsplash-photos/e"}; !slices.Equal(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	if store.listed != 3 {
+		t.Errorf("pages requested = %d, want 3 for 5 keys by 2", store.listed)
+	}
+	if objects[1].Size != 101 || !objects[1].LastModified.Equal(modified.Add(time.Minute)) {
+		t.Errorf("object %s = size %d, modified %s; want 101, %s",
+			objects[1].Key, objects[1].Size, objects[1].LastModified, modified.Add(time.Minute))
+	}
+
+	files, err := c.ListFiles(context.Background(), "user-uploads/")
+	if err != nil || len(files) != 1 || files[0].Key != "user-uploads/x" {
+		t.Errorf("ListFiles = %v, %v; want only user-uploads/x", files, err)
+	}
+}
+
+// TestCleanupOrphanedObjects_ListsThroughS3 удаляет осиротевшие файлы, которые CleanupOrphanedObjects
+// нашла через ListFiles → ListObjects, с постраничным листингом и фото в SQLite
+func TestCleanupOrphanedObjects_ListsThroughS3(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"), discardLogger())
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	photos, users := sqlite.NewPhotoStorage(db, discardLogger()), sqlite.NewUserStorage(db, discardLogger())
+	userID, err := users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("create system user: %v", err)
+	}
+	kept := &domain.Photo{UnsplashID: "kept", UserID: userID, OriginalURL: "https://images.example.com/kept"}
+	if _, err := photos.SavePhotoTx(ctx, kept); err != nil {
+		t.Fatalf("save photo: %v", err)
+	}
+
+	store := &fakeObjectStore{pageSize: 2}
+	old := time.Now().Add(-2 * time.Hour)
+	store.put("unsplash-photos/kept", 1, old)                                      // старый ключ без ID записи
+	store.put("unsplash-photos/kept/"+kept.ID.String(), 1, old)                    // файл сохранённой записи
+	store.put("unsplash-photos/kept/00000000-0000-0000-0000-000000000001", 1, old) // проигравший гонку
+	store.put("unsplash-photos/gone", 1, old)                                      // записи нет
+	store.put("unsplash-photos/fresh", 1, time.Now())                              // запись может ещё сохраняться
+	store.put("user-uploads/other", 1, old)                                        // не фото Unsplash
+
+	uc := usecase.NewPhotoUseCase(photos, users, nil, nil, nil, nil, nil, newTestClient(t, store), nil, nil, nil,
+		nil, 0, usecase.StepTimeouts{}, nil, nil, discardLogger())
+	deleted, err := uc.CleanupOrphanedObjects(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupOrphanedObjects: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted)
+	}
+	want := []string{"unsplash-photos/fresh", "unsplash-photos/kept", "unsplash-photos/kept/" + kept.ID.String(), "user-uploads/other"}
+	if got := store.keys(); !slices.Equal(got, want) {
+		t.Errorf("objects left = %v, want %v", got, want)
+	}
+}
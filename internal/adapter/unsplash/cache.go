@@ -0,0 +1,82 @@
+package unsplash
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// responseCache — кеш ответов Unsplash в памяти, ограниченный по времени жизни и числу записей.
+// Хранит тело успешного ответа или ошибку (ответ 404), ключ — полный адрес запроса с параметрами.
+// При переполнении вытесняется запись, к которой дольше всего не обращались. Безопасен для
+// одновременного использования
+type responseCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // значения — *cacheEntry
+	order   *list.List               // от недавно запрошенных к давно запрошенным
+	hits    int64
+	misses  int64
+}
+
+// cacheEntry — закешированный ответ: body при успехе или err
+type cacheEntry struct {
+	key       string
+	body      []byte
+	err       error
+	expiresAt time.Time
+}
+
+// newResponseCache создаёт кеш на maxEntries ответов; при maxEntries <= 0 возвращает nil — кеш выключен
+func newResponseCache(maxEntries int) *responseCache {
+	if maxEntries <= 0 {
+		return nil
+	}
+	return &responseCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// get возвращает неистёкшую запись по key и счётчики попаданий и промахов с учётом этого обращения
+func (c *responseCache) get(key string, now time.Time) (entry *cacheEntry, hits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*cacheEntry)
+		if now.Before(e.expiresAt) {
+			c.order.MoveToFront(el)
+			c.hits++
+			return e, c.hits, c.misses
+		}
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+	c.misses++
+	return nil, c.hits, c.misses
+}
+
+// set сохраняет ответ на ttl; ttl <= 0 — не сохранять
+func (c *responseCache) set(key string, body []byte, err error, ttl time.Duration, now time.Time) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, body: body, err: err, expiresAt: now.Add(ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
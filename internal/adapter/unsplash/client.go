@@ -4,74 +4,272 @@ package unsplash
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/config"
 	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/google/uuid"
 )
 
-const (
-	baseURL = "https://api.unsplash.com" // Базовый URL для Unsplash API
-)
+// maxErrorBodyBytes — сколько байт тела ответа с ошибкой попадает в лог и текст ошибки
+const maxErrorBodyBytes = 4 << 10
+
+// headerTotal — заголовок, в котором списочные эндпоинты Unsplash сообщают общее число элементов
+const headerTotal = "X-Total"
+
+var tracer = tracing.Tracer("unsplash")
 
 // UnsplashAPIClient представляет клиент для взаимодействия с Unsplash API
 type UnsplashAPIClient struct {
-	httpClient *http.Client
-	accessKey  string
-	logger     *slog.Logger
+	httpClient     *http.Client
+	baseURL        string // без завершающего "/"
+	accessKey      string
+	maxAttempts    int
+	retryBaseDelay time.Duration
+	rateLimit      rateLimitState
+	cache          *responseCache     // nil — кеш выключен
+	inflight       singleflight.Group // одновременные промахи кеша по одному адресу — один запрос
+	cacheTTL       time.Duration
+	negativeTTL    time.Duration
+	metrics        *metrics.Metrics
+	logger         *slog.Logger
 }
 
-// NewUnsplashAPIClient создает новый экземпляр UnsplashAPIClient
-func NewUnsplashAPIClient(cfg *config.Config, logger *slog.Logger) *UnsplashAPIClient {
-	return &UnsplashAPIClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
-		accessKey:  cfg.UnsplashAPIKey,
-		logger:     logger,
+// NewUnsplashAPIClient создает новый экземпляр UnsplashAPIClient.
+// transport необязателен (nil — стандартный транспорт с прокси из UNSPLASH_PROXY_URL, если он задан).
+// Некорректный UNSPLASH_BASE_URL или UNSPLASH_PROXY_URL — ошибка сразу при создании клиента
+func NewUnsplashAPIClient(cfg *config.Config, transport http.RoundTripper, m *metrics.Metrics, logger *slog.Logger) (*UnsplashAPIClient, error) {
+	base, err := parseHTTPURL(cfg.UnsplashBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный UNSPLASH_BASE_URL: %w", err)
+	}
+
+	if transport == nil {
+		defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.UnsplashProxyURL != "" {
+			proxy, err := parseHTTPURL(cfg.UnsplashProxyURL)
+			if err != nil {
+				return nil, fmt.Errorf("некорректный UNSPLASH_PROXY_URL: %w", err)
+			}
+			defaultTransport.Proxy = http.ProxyURL(proxy)
+		}
+		transport = defaultTransport
+	}
+
+	client := &UnsplashAPIClient{
+		httpClient:     &http.Client{Timeout: cfg.UnsplashHTTPTimeout, Transport: transport},
+		baseURL:        strings.TrimSuffix(base.String(), "/"),
+		accessKey:      cfg.UnsplashAPIKey,
+		maxAttempts:    max(cfg.UnsplashMaxAttempts, 1),
+		retryBaseDelay: cfg.UnsplashRetryBaseDelay,
+		cacheTTL:       cfg.UnsplashCacheTTL,
+		negativeTTL:    cfg.UnsplashCacheNegativeTTL,
+		metrics:        m,
+		logger:         logger,
+	}
+	if cfg.UnsplashCacheTTL > 0 {
+		client.cache = newResponseCache(cfg.UnsplashCacheMaxEntries)
 	}
+	return client, nil
 }
 
-// fetchAndMapPhoto выполняет HTTP-запрос к Unsplash и маппит ответ в domain.Photo
-// Это вспомогательная функция, которая используется всеми методами fetcher
-func (c *UnsplashAPIClient) fetchAndMapPhoto(endpoint string) (*domain.Photo, error) {
-	c.logger.Info("выполнение запроса к Unsplash API", slog.String("endpoint", endpoint))
+// parseHTTPURL разбирает абсолютный http(s)-адрес
+func parseHTTPURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%q: ожидается схема http или https", raw)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%q: не указан хост", raw)
+	}
+	return u, nil
+}
+
+// get выполняет GET-запрос к Unsplash API и декодирует JSON-ответ в dst.
+// Сетевые ошибки и ответы 5xx повторяются с экспоненциальной паузой и разбросом.
+// Исчерпанный лимит возвращается сразу как *domain.ErrRateLimited, без запроса, если он уже известен
+func (c *UnsplashAPIClient) get(ctx context.Context, endpoint string, dst any) error {
+	_, err := c.getWithHeader(ctx, endpoint, dst)
+	return err
+}
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+// getWithHeader выполняет то же, что get, и возвращает заголовки успешного ответа:
+// в них списочные эндпоинты Unsplash сообщают общее число элементов (X-Total)
+func (c *UnsplashAPIClient) getWithHeader(ctx context.Context, endpoint string, dst any) (http.Header, error) {
+	body, header, err := c.fetch(ctx, endpoint)
 	if err != nil {
-		c.logger.Error("ошибка создания HTTP-запроса", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка создания HTTP-запроса: %w", err)
+		return nil, err
+	}
+	return header, c.decode(ctx, body, dst)
+}
+
+// getCached выполняет то же, что get, но сначала ищет ответ в кеше: успешные ответы хранятся cacheTTL,
+// «фото не найдено» — negativeTTL. Ответ из кеша отдаётся и тогда, когда лимит запросов исчерпан.
+// Одновременные промахи по одному адресу ждут один общий запрос к Unsplash
+func (c *UnsplashAPIClient) getCached(ctx context.Context, endpoint string, dst any) error {
+	if c.cache == nil {
+		return c.get(ctx, endpoint, dst)
+	}
+
+	entry, hits, misses := c.cache.get(endpoint, time.Now())
+	if entry != nil {
+		c.log(ctx).Debug("ответ Unsplash взят из кеша", slog.String("endpoint", endpoint),
+			slog.Bool("not_found", entry.err != nil), slog.Int64("cache_hits", hits), slog.Int64("cache_misses", misses))
+		if entry.err != nil {
+			return entry.err
+		}
+		return c.decode(ctx, entry.body, dst)
+	}
+	c.log(ctx).Debug("ответа Unsplash нет в кеше", slog.String("endpoint", endpoint),
+		slog.Int64("cache_hits", hits), slog.Int64("cache_misses", misses))
+
+	result, err, _ := c.inflight.Do(endpoint, func() (any, error) {
+		body, _, err := c.fetch(ctx, endpoint)
+		switch {
+		case err == nil:
+			c.cache.set(endpoint, body, nil, c.cacheTTL, time.Now())
+		case errors.Is(err, ErrPhotoNotFound):
+			c.cache.set(endpoint, nil, err, c.negativeTTL, time.Now())
+		}
+		return body, err
+	})
+	if err != nil {
+		return err
+	}
+	return c.decode(ctx, result.([]byte), dst)
+}
+
+// fetch выполняет GET-запрос с повторами и возвращает тело и заголовки успешного ответа
+func (c *UnsplashAPIClient) fetch(ctx context.Context, endpoint string) ([]byte, http.Header, error) {
+	if err := c.rateLimit.check(); err != nil {
+		c.log(ctx).Warn("лимит запросов к Unsplash исчерпан, запрос не выполняется", slog.Time("reset_at", err.ResetAt))
+		return nil, nil, err
 	}
 
+	delay := c.retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		body, header, retryable, err := c.doGet(ctx, endpoint)
+		if err == nil || !retryable || attempt >= c.maxAttempts {
+			return body, header, err
+		}
+
+		wait := withJitter(delay)
+		c.log(ctx).Warn("повтор запроса к Unsplash API",
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", wait),
+			slog.Any("error", err),
+		)
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("запрос к Unsplash прерван: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// decode разбирает JSON-ответ Unsplash в dst
+func (c *UnsplashAPIClient) decode(ctx context.Context, body []byte, dst any) error {
+	if err := json.Unmarshal(body, dst); err != nil {
+		c.log(ctx).Error("ошибка декодирования JSON", slog.Any("error", err))
+		return fmt.Errorf("ошибка декодирования JSON ответа Unsplash: %w", err)
+	}
+	return nil
+}
+
+// doGet выполняет одну попытку запроса и возвращает тело и заголовки успешного ответа.
+// retryable сообщает, имеет ли смысл повторить запрос
+func (c *UnsplashAPIClient) doGet(ctx context.Context, endpoint string) (body []byte, header http.Header, retryable bool, err error) {
+	c.log(ctx).Info("выполнение запроса к Unsplash API", slog.String("endpoint", endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		c.log(ctx).Error("ошибка создания HTTP-запроса", slog.Any("error", err))
+		return nil, nil, false, fmt.Errorf("ошибка создания HTTP-запроса: %w", err)
+	}
 	req.Header.Set("Authorization", "Client-ID "+c.accessKey) // заголовок авторизации
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		c.logger.Error("ошибка выполнения HTTP-запроса к Unsplash", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка выполнения HTTP-запроса к Unsplash: %w", err)
+		c.log(ctx).Error("ошибка выполнения HTTP-запроса к Unsplash", slog.Any("error", err))
+		// отменённый вызывающим запрос повторять не нужно, и источник в этом не виноват
+		if ctx.Err() != nil {
+			return nil, nil, false, fmt.Errorf("ошибка выполнения HTTP-запроса к Unsplash: %w", err)
+		}
+		return nil, nil, true, fmt.Errorf("%w: ошибка выполнения HTTP-запроса к Unsplash: %w", domain.ErrSourceUnavailable, err)
 	}
 	defer resp.Body.Close()
 
+	if limit, remaining, ok := c.rateLimit.update(resp.Header); ok {
+		c.metrics.SetUnsplashRateLimitRemaining(remaining)
+		c.log(ctx).Debug("лимит запросов Unsplash", slog.Int("limit", limit), slog.Int("remaining", remaining))
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		c.logger.Warn("Unsplash API вернул ошибку", slog.Int("status", resp.StatusCode), slog.String("body", string(bodyBytes)))
-		return nil, fmt.Errorf("unsplash API вернул статус %d: %s", resp.StatusCode, string(bodyBytes))
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		if isRateLimited(resp, bodyBytes) {
+			rlErr := c.rateLimit.exhausted(resp.Header)
+			c.log(ctx).Warn("Unsplash API: лимит запросов исчерпан",
+				slog.Int("status", resp.StatusCode),
+				slog.Time("reset_at", rlErr.ResetAt),
+			)
+			return nil, nil, false, rlErr
+		}
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(bodyBytes)}
+		if errors.Is(apiErr, ErrInvalidAccessKey) {
+			c.log(ctx).Error("Unsplash API отклонил ключ доступа", slog.Int("status", resp.StatusCode))
+		} else {
+			c.log(ctx).Warn("Unsplash API вернул ошибку", slog.Int("status", resp.StatusCode), slog.String("body", apiErr.Body))
+		}
+		return nil, nil, resp.StatusCode >= http.StatusInternalServerError, apiErr
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		c.log(ctx).Error("ошибка чтения ответа Unsplash", slog.Any("error", err))
+		return nil, nil, true, fmt.Errorf("%w: ошибка чтения ответа Unsplash: %w", domain.ErrSourceUnavailable, err)
 	}
+	return body, resp.Header, false, nil
+}
 
+// withJitter возвращает случайную паузу в диапазоне [d/2, d], чтобы повторы
+// нескольких экземпляров не приходили в Unsplash одновременно
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// fetchAndMapPhoto выполняет HTTP-запрос к Unsplash и маппит ответ в domain.Photo
+func (c *UnsplashAPIClient) fetchAndMapPhoto(ctx context.Context, endpoint string) (*domain.Photo, error) {
 	var unsplashPhoto UnsplashPhotoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&unsplashPhoto); err != nil {
-		c.logger.Error("ошибка декодирования JSON", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка декодирования JSON ответа Unsplash: %w", err)
+	if err := c.getCached(ctx, endpoint, &unsplashPhoto); err != nil {
+		return nil, err
 	}
 
 	// Маппинг UnsplashPhotoResponse в domain.Photo
-	c.logger.Debug("успешно получен ответ от Unsplash API", slog.String("photo_id", unsplashPhoto.ID))
+	c.log(ctx).Debug("успешно получен ответ от Unsplash API", slog.String("photo_id", unsplashPhoto.ID))
 	return c.mapUnsplashPhotoToDomain(&unsplashPhoto), nil
 }
 
@@ -86,9 +284,31 @@ func (c *UnsplashAPIClient) mapUnsplashPhotoToDomain(unsplashPhoto *UnsplashPhot
 		description = unsplashPhoto.AltDescription
 	}
 
+	var tags []domain.Tag
+	for _, t := range unsplashPhoto.Tags {
+		if title := strings.TrimSpace(t.Title); title != "" {
+			tags = append(tags, domain.Tag{Name: title})
+		}
+	}
+
+	// некорректный цвет не сохраняем, чтобы клиенту не пришлось его проверять
+	dominantColor, err := domain.NormalizeHexColor(unsplashPhoto.Color)
+	if err != nil {
+		dominantColor = ""
+	}
+
+	// Unsplash отдаёт 0,0 вместо null у части фото без места съёмки — такие координаты не сохраняем
+	var latitude, longitude *float64
+	if pos := unsplashPhoto.Location.Position; pos.Latitude != nil && pos.Longitude != nil &&
+		(*pos.Latitude != 0 || *pos.Longitude != 0) {
+		latitude, longitude = pos.Latitude, pos.Longitude
+	}
+
 	return &domain.Photo{
 		ID:             newPhotoID,
 		UnsplashID:     unsplashPhoto.ID,
+		ExternalSource: domain.SourceUnsplash,
+		ExternalID:     unsplashPhoto.ID,
 		S3URL:          "",          // S3 URL будет установлен после загрузки в S3, не тут
 		Title:          description, // В качестве заголовка используем описание или alt_description
 		Description:    description,
@@ -100,103 +320,222 @@ func (c *UnsplashAPIClient) mapUnsplashPhotoToDomain(unsplashPhoto *UnsplashPhot
 		UploadedAt:     unsplashPhoto.CreatedAt,
 		ViewsCount:     unsplashPhoto.Views,
 		DownloadsCount: unsplashPhoto.Downloads,
-		Tags:           nil,
+		Latitude:       latitude,
+		Longitude:      longitude,
+		BlurHash:       unsplashPhoto.BlurHash,
+		DominantColor:  dominantColor,
+		Tags:           tags,
 	}
 }
 
+// FetcherName реализует метод PhotoFetcher
+func (c *UnsplashAPIClient) FetcherName() string {
+	return domain.SourceUnsplash
+}
+
 // FetchPhotoByIDFromExternal реализует метод PhotoFetcher
 func (c *UnsplashAPIClient) FetchPhotoByIDFromExternal(ctx context.Context, id string) (*domain.Photo, error) {
-	endpoint := fmt.Sprintf("%s/photos/%s", baseURL, id)
-	c.logger.Info("запрос фото по ID из Unsplash", slog.String("unsplash_id", id))
-	return c.fetchAndMapPhoto(endpoint)
+	endpoint := fmt.Sprintf("%s/photos/%s", c.baseURL, id)
+	c.log(ctx).Info("запрос фото по ID из Unsplash", slog.String("unsplash_id", id))
+
+	ctx, span := tracer.Start(ctx, "Unsplash.FetchPhotoByID", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("unsplash_id", id)))
+	defer span.End()
+
+	start := time.Now()
+	photo, err := c.fetchAndMapPhoto(ctx, endpoint)
+	c.metrics.ObserveUnsplashRequest("fetch_photo", time.Since(start), err)
+	if errors.Is(err, ErrPhotoNotFound) {
+		// отсутствие фото — ответ на запрос, а не сбой, поэтому на спане ошибкой не отмечаем
+		return nil, fmt.Errorf("фото %s: %w", id, err)
+	}
+	tracing.RecordError(span, err)
+	return photo, err
 }
 
 // SearchPhotosFromExternal реализует метод PhotoFetcher
-func (c *UnsplashAPIClient) SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int) (
-	[]domain.Photo, error) {
+func (c *UnsplashAPIClient) SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string) (_ domain.PhotoPage, err error) {
+	ctx, span := tracer.Start(ctx, "Unsplash.SearchPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("query", query), attribute.Int("page", page), attribute.Int("per_page", perPage),
+			attribute.String("orientation", orientation), attribute.String("color", color), attribute.String("order_by", orderBy)))
+	defer func(start time.Time) {
+		c.metrics.ObserveUnsplashRequest("search_photos", time.Since(start), err)
+		tracing.RecordError(span, err)
+		span.End()
+	}(time.Now())
 
 	params := url.Values{}
 	params.Add("query", query)
 	params.Add("page", strconv.Itoa(page))
 	params.Add("per_page", strconv.Itoa(perPage))
-
-	endpoint := fmt.Sprintf("%s/search/photos?%s", baseURL, params.Encode())
-	c.logger.Info("поиск фото в Unsplash API", slog.String("query", query), slog.Int("page", page), slog.Int("per_page", perPage))
-
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		c.logger.Error("ошибка создания HTTP-запроса поиска", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка создания HTTP-запроса для поиска: %w", err)
+	if orientation != "" {
+		params.Add("orientation", orientation)
 	}
-	req.Header.Set("Authorization", "Client-ID "+c.accessKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.logger.Error("ошибка выполнения HTTP-запроса поиска", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка выполнения HTTP-запроса к Unsplash для поиска: %w", err)
+	if color != "" {
+		params.Add("color", color)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		c.logger.Warn("ошибка поиска Unsplash API", slog.Int("status", resp.StatusCode), slog.String("body", string(bodyBytes)))
-		return nil, fmt.Errorf("unsplash API поиска вернул статус %d: %s", resp.StatusCode, string(bodyBytes))
+	if orderBy != "" {
+		params.Add("order_by", orderBy)
 	}
 
+	endpoint := fmt.Sprintf("%s/search/photos?%s", c.baseURL, params.Encode())
+	c.log(ctx).Info("поиск фото в Unsplash API", slog.String("query", query), slog.Int("page", page), slog.Int("per_page", perPage),
+		slog.String("orientation", orientation), slog.String("color", color), slog.String("order_by", orderBy))
+
 	var searchResponse UnsplashSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
-		c.logger.Error("ошибка декодирования JSON ответа поиска", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка декодирования JSON ответа поиска Unsplash: %w", err)
+	if err := c.getCached(ctx, endpoint, &searchResponse); err != nil {
+		return domain.PhotoPage{}, err
 	}
 
 	var domainPhotos []domain.Photo
 	for _, unsplashPhoto := range searchResponse.Results {
 		domainPhotos = append(domainPhotos, *c.mapUnsplashPhotoToDomain(&unsplashPhoto))
 	}
-	c.logger.Info("поиск завершён", slog.Int("count", len(domainPhotos)))
-	return domainPhotos, nil
+	c.log(ctx).Info("поиск завершён", slog.Int("count", len(domainPhotos)),
+		slog.Int("total", searchResponse.Total), slog.Int("total_pages", searchResponse.TotalPages))
+	return domain.PhotoPage{Photos: domainPhotos, Total: searchResponse.Total, TotalPages: searchResponse.TotalPages}, nil
 }
 
 // ListNewPhotosFromExternal реализует метод PhotoFetcher
-func (c *UnsplashAPIClient) ListNewPhotosFromExternal(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+func (c *UnsplashAPIClient) ListNewPhotosFromExternal(ctx context.Context, page, perPage int) (_ []domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "Unsplash.ListNewPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("page", page), attribute.Int("per_page", perPage)))
+	defer func(start time.Time) {
+		c.metrics.ObserveUnsplashRequest("list_photos", time.Since(start), err)
+		tracing.RecordError(span, err)
+		span.End()
+	}(time.Now())
+
 	// Строим URL для получения списка фото - /photos эндпоинт
 	params := url.Values{}
 	params.Add("page", strconv.Itoa(page))
 	params.Add("per_page", strconv.Itoa(perPage))
 
-	endpoint := fmt.Sprintf("%s/photos?%s", baseURL, params.Encode())
-	c.logger.Info("запрос списка новых фото", slog.Int("page", page), slog.Int("per_page", perPage))
+	endpoint := fmt.Sprintf("%s/photos?%s", c.baseURL, params.Encode())
+	c.log(ctx).Info("запрос списка новых фото", slog.Int("page", page), slog.Int("per_page", perPage))
 
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		c.logger.Error("ошибка создания HTTP-запроса списка", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка создания HTTP-запроса для списка фото: %w", err)
+	var unsplashPhotos []UnsplashPhotoResponse // Список фото напрямую
+	if err := c.get(ctx, endpoint, &unsplashPhotos); err != nil {
+		return nil, err
 	}
-	req.Header.Set("Authorization", "Client-ID "+c.accessKey)
 
-	resp, err := c.httpClient.Do(req)
+	var domainPhotos []domain.Photo
+	for _, unsplashPhoto := range unsplashPhotos {
+		domainPhotos = append(domainPhotos, *c.mapUnsplashPhotoToDomain(&unsplashPhoto))
+	}
+	c.log(ctx).Info("список фото успешно получен", slog.Int("count", len(domainPhotos)))
+	return domainPhotos, nil
+}
+
+// FetchCollectionPhotos возвращает страницу фото коллекции Unsplash (/collections/{id}/photos).
+// Размер коллекции берётся из заголовка X-Total. Пустой список означает, что страницы закончились.
+// Если коллекции нет, ошибка оборачивает domain.ErrExternalCollectionNotFound
+func (c *UnsplashAPIClient) FetchCollectionPhotos(ctx context.Context, collectionID string, page, perPage int) (_ domain.PhotoPage, err error) {
+	ctx, span := tracer.Start(ctx, "Unsplash.FetchCollectionPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("collection_id", collectionID), attribute.Int("page", page), attribute.Int("per_page", perPage)))
+	defer func(start time.Time) {
+		c.metrics.ObserveUnsplashRequest("collection_photos", time.Since(start), err)
+		tracing.RecordError(span, err)
+		span.End()
+	}(time.Now())
+
+	params := url.Values{}
+	params.Add("page", strconv.Itoa(page))
+	params.Add("per_page", strconv.Itoa(perPage))
+
+	endpoint := fmt.Sprintf("%s/collections/%s/photos?%s", c.baseURL, url.PathEscape(collectionID), params.Encode())
+	c.log(ctx).Info("запрос фото коллекции", slog.String("collection_id", collectionID), slog.Int("page", page), slog.Int("per_page", perPage))
+
+	var unsplashPhotos []UnsplashPhotoResponse
+	header, err := c.getWithHeader(ctx, endpoint, &unsplashPhotos)
 	if err != nil {
-		c.logger.Error("ошибка выполнения HTTP-запроса списка", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка выполнения HTTP-запроса к Unsplash для списка фото: %w", err)
+		if errors.Is(err, ErrPhotoNotFound) {
+			return domain.PhotoPage{}, fmt.Errorf("коллекция %s: %w", collectionID, domain.ErrExternalCollectionNotFound)
+		}
+		return domain.PhotoPage{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		c.logger.Warn("ошибка получения списка фото Unsplash API", slog.Int("status", resp.StatusCode), slog.String("body", string(bodyBytes)))
-		return nil, fmt.Errorf("unsplash API списка фото вернул статус %d: %s", resp.StatusCode, string(bodyBytes))
+	domainPhotos := make([]domain.Photo, 0, len(unsplashPhotos))
+	for _, unsplashPhoto := range unsplashPhotos {
+		domainPhotos = append(domainPhotos, *c.mapUnsplashPhotoToDomain(&unsplashPhoto))
 	}
+	// без заголовка размер коллекции неизвестен — импорт остановится на пустой странице
+	total, _ := strconv.Atoi(header.Get(headerTotal))
+	c.log(ctx).Info("фото коллекции получены", slog.String("collection_id", collectionID), slog.Int("count", len(domainPhotos)),
+		slog.Int("total", total))
+	return domain.PhotoPage{Photos: domainPhotos, Total: total, TotalPages: domain.TotalPagesFor(total, perPage)}, nil
+}
 
-	var unsplashPhotos []UnsplashPhotoResponse // Список фото напрямую
-	if err := json.NewDecoder(resp.Body).Decode(&unsplashPhotos); err != nil {
-		c.logger.Error("ошибка декодирования JSON списка фото", slog.Any("error", err))
-		return nil, fmt.Errorf("ошибка декодирования JSON ответа списка фото Unsplash: %w", err)
+// FetchUserPhotos возвращает страницу фото автора Unsplash (/users/{username}/photos), новые первыми.
+// Число фото автора берётся из заголовка X-Total. Пустой список означает, что страницы закончились.
+// Если автора нет, ошибка оборачивает domain.ErrExternalAuthorNotFound
+func (c *UnsplashAPIClient) FetchUserPhotos(ctx context.Context, username string, page, perPage int) (_ domain.PhotoPage, err error) {
+	ctx, span := tracer.Start(ctx, "Unsplash.FetchUserPhotos", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("username", username), attribute.Int("page", page), attribute.Int("per_page", perPage)))
+	defer func(start time.Time) {
+		c.metrics.ObserveUnsplashRequest("user_photos", time.Since(start), err)
+		tracing.RecordError(span, err)
+		span.End()
+	}(time.Now())
+
+	params := url.Values{}
+	params.Add("page", strconv.Itoa(page))
+	params.Add("per_page", strconv.Itoa(perPage))
+
+	endpoint := fmt.Sprintf("%s/users/%s/photos?%s", c.baseURL, url.PathEscape(username), params.Encode())
+	c.log(ctx).Info("запрос фото автора", slog.String("username", username), slog.Int("page", page), slog.Int("per_page", perPage))
+
+	var unsplashPhotos []UnsplashPhotoResponse
+	header, err := c.getWithHeader(ctx, endpoint, &unsplashPhotos)
+	if err != nil {
+		if errors.Is(err, ErrPhotoNotFound) {
+			return domain.PhotoPage{}, fmt.Errorf("автор %s: %w", username, domain.ErrExternalAuthorNotFound)
+		}
+		return domain.PhotoPage{}, err
 	}
 
-	var domainPhotos []domain.Photo
+	domainPhotos := make([]domain.Photo, 0, len(unsplashPhotos))
 	for _, unsplashPhoto := range unsplashPhotos {
 		domainPhotos = append(domainPhotos, *c.mapUnsplashPhotoToDomain(&unsplashPhoto))
 	}
-	c.logger.Info("список фото успешно получен", slog.Int("count", len(domainPhotos)))
-	return domainPhotos, nil
+	// без заголовка число фото неизвестно — импорт остановится на пустой странице
+	total, _ := strconv.Atoi(header.Get(headerTotal))
+	c.log(ctx).Info("фото автора получены", slog.String("username", username), slog.Int("count", len(domainPhotos)),
+		slog.Int("total", total))
+	return domain.PhotoPage{Photos: domainPhotos, Total: total, TotalPages: domain.TotalPagesFor(total, perPage)}, nil
+}
+
+// FetchPhotoStatistics возвращает просмотры и скачивания фото за всё время (/photos/{id}/statistics).
+// В списках и поиске Unsplash этих счётчиков не отдаёт. Если фото нет, ошибка оборачивает domain.ErrExternalPhotoNotFound
+func (c *UnsplashAPIClient) FetchPhotoStatistics(ctx context.Context, unsplashID string) (_ domain.PhotoStats, err error) {
+	ctx, span := tracer.Start(ctx, "Unsplash.FetchPhotoStatistics", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("unsplash_id", unsplashID)))
+	defer func(start time.Time) {
+		c.metrics.ObserveUnsplashRequest("photo_statistics", time.Since(start), err)
+		if !errors.Is(err, ErrPhotoNotFound) {
+			tracing.RecordError(span, err)
+		}
+		span.End()
+	}(time.Now())
+
+	endpoint := fmt.Sprintf("%s/photos/%s/statistics", c.baseURL, url.PathEscape(unsplashID))
+	c.log(ctx).Info("запрос статистики фото", slog.String("unsplash_id", unsplashID))
+
+	var stats UnsplashPhotoStatisticsResponse
+	if err := c.get(ctx, endpoint, &stats); err != nil {
+		if errors.Is(err, ErrPhotoNotFound) {
+			return domain.PhotoStats{}, fmt.Errorf("фото %s: %w", unsplashID, err)
+		}
+		return domain.PhotoStats{}, err
+	}
+
+	c.log(ctx).Info("статистика фото получена", slog.String("unsplash_id", unsplashID),
+		slog.Int64("views", stats.Views.Total), slog.Int64("downloads", stats.Downloads.Total))
+	return domain.PhotoStats{Views: stats.Views.Total, Downloads: stats.Downloads.Total}, nil
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (c *UnsplashAPIClient) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, c.logger)
 }
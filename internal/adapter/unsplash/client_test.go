@@ -0,0 +1,252 @@
+package unsplash
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const testAccessKey = "test-key"
+
+const photoJSON = `{
+	"id": "abc",
+	"description": "",
+	"alt_description": "red fox in snow",
+	"width": 4000,
+	"height": 3000,
+	"likes": 42,
+	"color": "#A0522D",
+	"urls": {"full": "https://images.example/abc-full"},
+	"user": {"username": "jane", "name": "Jane Doe"},
+	"tags": [{"title": "fox"}, "snow", {"title": " "}],
+	"location": {"position": {"latitude": 0, "longitude": 0}},
+	"views": 1000,
+	"downloads": 7,
+	"created_at": "2024-03-01T10:00:00Z"
+}`
+
+const searchJSON = `{
+	"total": 31,
+	"total_pages": 16,
+	"results": [
+		{"id": "a1", "description": "first", "urls": {"full": "https://images.example/a1"}, "user": {"name": "A"}},
+		{"id": "a2", "description": "second", "urls": {"full": "https://images.example/a2"}, "user": {"name": "B"}}
+	]
+}`
+
+// requestLog запоминает адреса запросов к тестовому серверу
+type requestLog struct {
+	mu   sync.Mutex
+	urls []*url.URL
+}
+
+func (l *requestLog) add(u *url.URL) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.urls = append(l.urls, u)
+}
+
+func (l *requestLog) all() []*url.URL {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*url.URL(nil), l.urls...)
+}
+
+// newTestServer имитирует Unsplash API: /photos/abc, /search/photos, остальное — 404.
+// Запрос без ключа доступа проваливает тест
+func newTestServer(t *testing.T) (*httptest.Server, *requestLog) {
+	t.Helper()
+	requests := &requestLog{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.add(r.URL)
+		if got := r.Header.Get("Authorization"); got != "Client-ID "+testAccessKey {
+			t.Errorf("Authorization = %q, want %q", got, "Client-ID "+testAccessKey)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/photos/abc":
+			_, _ = io.WriteString(w, photoJSON)
+		case "/search/photos":
+			_, _ = io.WriteString(w, searchJSON)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"errors": ["Couldn't find Photo"]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func newTestClient(t *testing.T, baseURL string) *UnsplashAPIClient {
+	t.Helper()
+	cfg := &config.Config{
+		UnsplashBaseURL:     baseURL,
+		UnsplashAPIKey:      testAccessKey,
+		UnsplashHTTPTimeout: 5 * time.Second,
+		UnsplashMaxAttempts: 1,
+	}
+	c, err := NewUnsplashAPIClient(cfg, nil, metrics.New(prometheus.NewRegistry()),
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewUnsplashAPIClient: %v", err)
+	}
+	return c
+}
+
+func TestFetchPhotoByIDFromExternal(t *testing.T) {
+	srv, requests := newTestServer(t)
+	c := newTestClient(t, srv.URL+"/")
+
+	photo, err := c.FetchPhotoByIDFromExternal(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("FetchPhotoByIDFromExternal: %v", err)
+	}
+	if urls := requests.all(); len(urls) != 1 || urls[0].Path != "/photos/abc" {
+		t.Fatalf("requests = %v, want one GET /photos/abc", urls)
+	}
+
+	if photo.UnsplashID != "abc" || photo.ExternalSource != domain.SourceUnsplash || photo.ExternalID != "abc" {
+		t.Errorf("source = %s/%s (unsplash_id %q), want unsplash/abc", photo.ExternalSource, photo.ExternalID, photo.UnsplashID)
+	}
+	if photo.Title != "red fox in snow" || photo.AuthorName != "Jane Doe" {
+		t.Errorf("title, author = %q, %q; want alt description and user name", photo.Title, photo.AuthorName)
+	}
+	if photo.Width != 4000 || photo.Height != 3000 || photo.LikesCount != 42 || photo.ViewsCount != 1000 || photo.DownloadsCount != 7 {
+		t.Errorf("photo = %dx%d, %d likes, %d views, %d downloads", photo.Width, photo.Height,
+			photo.LikesCount, photo.ViewsCount, photo.DownloadsCount)
+	}
+	if photo.OriginalURL != "https://images.example/abc-full" {
+		t.Errorf("original URL = %q", photo.OriginalURL)
+	}
+	if photo.DominantColor != "#a0522d" {
+		t.Errorf("dominant color = %q, want %q", photo.DominantColor, "#a0522d")
+	}
+	if photo.Latitude != nil || photo.Longitude != nil {
+		t.Errorf("coordinates = %v, %v; want none for 0,0", photo.Latitude, photo.Longitude)
+	}
+	if len(photo.Tags) != 2 || photo.Tags[0].Name != "fox" || photo.Tags[1].Name != "snow" {
+		t.Errorf("tags = %v, want [fox snow]", photo.Tags)
+	}
+	if want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC); !photo.UploadedAt.Equal(want) {
+		t.Errorf("uploaded at = %v, want %v", photo.UploadedAt, want)
+	}
+}
+
+func TestFetchPhotoByIDFromExternal_NotFound(t *testing.T) {
+	srv, _ := newTestServer(t)
+	c := newTestClient(t, srv.URL)
+
+	_, err := c.FetchPhotoByIDFromExternal(context.Background(), "missing")
+	if !errors.Is(err, domain.ErrExternalPhotoNotFound) {
+		t.Fatalf("err = %v, want ErrExternalPhotoNotFound", err)
+	}
+}
+
+func TestFetchPhotoByIDFromExternal_ErrorStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		want   error // цель errors.Is; nil — ожидается *domain.ErrRateLimited
+	}{
+		{name: "404 not found", status: http.StatusNotFound, body: `{"errors": ["Couldn't find Photo"]}`, want: ErrPhotoNotFound},
+		{name: "401 invalid key", status: http.StatusUnauthorized, body: `{"errors": ["OAuth error: The access token is invalid"]}`, want: ErrInvalidAccessKey},
+		{name: "403 forbidden", status: http.StatusForbidden, body: `{"errors": ["Forbidden"]}`, want: ErrForbidden},
+		{name: "403 rate limit by header", status: http.StatusForbidden,
+			header: map[string]string{headerRateLimitRemaining: "0"}, body: `{"errors": ["Forbidden"]}`},
+		{name: "403 rate limit by body", status: http.StatusForbidden, body: "Rate Limit Exceeded"},
+		{name: "429 too many requests", status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "30"}},
+		{name: "500 internal error", status: http.StatusInternalServerError, want: domain.ErrSourceUnavailable},
+		{name: "503 unavailable", status: http.StatusServiceUnavailable, want: domain.ErrSourceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				for key, value := range tt.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			t.Cleanup(srv.Close)
+			c := newTestClient(t, srv.URL)
+
+			_, err := c.FetchPhotoByIDFromExternal(context.Background(), "abc")
+			if tt.want == nil {
+				var rateLimited *domain.ErrRateLimited
+				if !errors.As(err, &rateLimited) || rateLimited.Source != domain.SourceUnsplash {
+					t.Fatalf("err = %v, want *domain.ErrRateLimited from unsplash", err)
+				}
+				if !rateLimited.ResetAt.After(time.Now()) {
+					t.Errorf("reset at = %v, want a moment in the future", rateLimited.ResetAt)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("err = %v, want *APIError with status %d", err, tt.status)
+			}
+		})
+	}
+}
+
+func TestSearchPhotosFromExternal(t *testing.T) {
+	srv, requests := newTestServer(t)
+	c := newTestClient(t, srv.URL)
+
+	page, err := c.SearchPhotosFromExternal(context.Background(), "red fox", 2, 2, "landscape", "", "latest")
+	if err != nil {
+		t.Fatalf("SearchPhotosFromExternal: %v", err)
+	}
+	urls := requests.all()
+	if len(urls) != 1 || urls[0].Path != "/search/photos" {
+		t.Fatalf("requests = %v, want one GET /search/photos", urls)
+	}
+	query := urls[0].Query()
+	wantQuery := map[string]string{"query": "red fox", "page": "2", "per_page": "2", "orientation": "landscape", "order_by": "latest"}
+	for key, want := range wantQuery {
+		if got := query.Get(key); got != want {
+			t.Errorf("query %s = %q, want %q", key, got, want)
+		}
+	}
+	if query.Has("color") {
+		t.Errorf("query has color = %q, want no empty filter", query.Get("color"))
+	}
+
+	if page.Total != 31 || page.TotalPages != 16 {
+		t.Errorf("total = %d (%d pages), want 31 (16 pages)", page.Total, page.TotalPages)
+	}
+	if len(page.Photos) != 2 || page.Photos[0].UnsplashID != "a1" || page.Photos[1].UnsplashID != "a2" {
+		t.Fatalf("photos = %v, want a1, a2", page.Photos)
+	}
+	if page.Photos[0].ID == page.Photos[1].ID {
+		t.Error("search results share one internal ID")
+	}
+}
+
+func TestNewUnsplashAPIClient_InvalidBaseURL(t *testing.T) {
+	for _, raw := range []string{"api.unsplash.com", "ftp://api.unsplash.com", "http://"} {
+		t.Run(raw, func(t *testing.T) {
+			cfg := &config.Config{UnsplashBaseURL: raw}
+			if _, err := NewUnsplashAPIClient(cfg, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil))); err == nil {
+				t.Error("err = nil, want invalid UNSPLASH_BASE_URL")
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package unsplash
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+var (
+	// ErrPhotoNotFound — Unsplash ответил 404. Оборачивает domain.ErrExternalPhotoNotFound,
+	// поэтому usecase распознаёт её, не завися от адаптера
+	ErrPhotoNotFound = fmt.Errorf("фото не найдено в Unsplash: %w", domain.ErrExternalPhotoNotFound)
+
+	// ErrInvalidAccessKey — Unsplash ответил 401: ключ доступа неверен или отозван
+	ErrInvalidAccessKey = errors.New("неверный ключ доступа Unsplash")
+
+	// ErrForbidden — Unsplash ответил 403 не из-за лимита: у ключа нет прав на запрос.
+	// 403 из-за исчерпанного лимита возвращается как *domain.ErrRateLimited
+	ErrForbidden = errors.New("нет прав на запрос к Unsplash")
+)
+
+// APIError — ответ Unsplash с неуспешным статусом. Для 401, 403 и 404 errors.Is
+// находит соответствующую ошибку пакета, для 5xx — domain.ErrSourceUnavailable
+type APIError struct {
+	StatusCode int
+	Body       string // начало тела ответа, не больше maxErrorBodyBytes
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unsplash API вернул статус %d: %s", e.StatusCode, e.Body)
+}
+
+// Unwrap возвращает ошибку, соответствующую статусу, или nil для остальных статусов
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrPhotoNotFound
+	case http.StatusUnauthorized:
+		return ErrInvalidAccessKey
+	case http.StatusForbidden:
+		return ErrForbidden
+	}
+	if e.StatusCode >= http.StatusInternalServerError {
+		return domain.ErrSourceUnavailable
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package unsplash
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
 
 // Отдельная структура для URL-ов
 type UnsplashPhotoURLs struct {
@@ -18,6 +22,36 @@ type UnsplashUser struct {
 	Name     string `json:"name"`
 }
 
+// Тег фото (приходит в ответе на запрос одного фото и в результатах поиска)
+type UnsplashTag struct {
+	Title string `json:"title"`
+}
+
+// UnmarshalJSON принимает тег и объектом {"title": ...}, и просто строкой.
+// Тег другой формы не ломает разбор всего фото, а остаётся пустым и пропускается при преобразовании
+func (t *UnsplashTag) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &t.Title)
+	}
+	var tag struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(data, &tag); err == nil {
+		t.Title = tag.Title
+	}
+	return nil
+}
+
+// Место съёмки. Координаты приходят только в ответе на запрос одного фото и могут быть null
+type UnsplashLocation struct {
+	Name     string `json:"name"`
+	Position struct {
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	} `json:"position"`
+}
+
 // Теперь UnsplashPhotoResponse использует эти именованные структуры
 type UnsplashPhotoResponse struct {
 	ID             string `json:"id"`
@@ -26,10 +60,15 @@ type UnsplashPhotoResponse struct {
 	Width          int    `json:"width"`
 	Height         int    `json:"height"`
 	Likes          int    `json:"likes"`
+	Color          string `json:"color"`     // средний цвет фото, "#rrggbb"
+	BlurHash       string `json:"blur_hash"` // может отсутствовать у старых фото
 
 	URLs UnsplashPhotoURLs `json:"urls"`
 	User UnsplashUser      `json:"user"`
 
+	Tags     []UnsplashTag    `json:"tags,omitempty"`
+	Location UnsplashLocation `json:"location"`
+
 	Views     int64     `json:"views,omitempty"`
 	Downloads int64     `json:"downloads,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
@@ -41,3 +80,15 @@ type UnsplashSearchResponse struct {
 	TotalPages int                     `json:"total_pages"`
 	Results    []UnsplashPhotoResponse `json:"results"`
 }
+
+// UnsplashStatTotal — счётчик статистики фото; история по дням не используется
+type UnsplashStatTotal struct {
+	Total int64 `json:"total"`
+}
+
+// UnsplashPhotoStatisticsResponse — ответ /photos/{id}/statistics
+type UnsplashPhotoStatisticsResponse struct {
+	ID        string            `json:"id"`
+	Views     UnsplashStatTotal `json:"views"`
+	Downloads UnsplashStatTotal `json:"downloads"`
+}
@@ -0,0 +1,91 @@
+package unsplash
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// Заголовки, в которых Unsplash сообщает лимит запросов в час и остаток
+const (
+	headerRateLimit          = "X-Ratelimit-Limit"
+	headerRateLimitRemaining = "X-Ratelimit-Remaining"
+)
+
+// rateLimitState — последние известные лимиты Unsplash из заголовков ответов.
+// Unsplash не сообщает, когда окно сбросится, поэтому без Retry-After
+// сброс ожидается в начале следующего часа
+type rateLimitState struct {
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	known     bool // получен хотя бы один ответ с заголовками лимита
+	resetAt   time.Time
+}
+
+// check возвращает ошибку, если лимит уже исчерпан и окно ещё не сбросилось
+func (s *rateLimitState) check() *domain.ErrRateLimited {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.known && s.remaining == 0 && time.Now().Before(s.resetAt) {
+		return &domain.ErrRateLimited{Source: domain.SourceUnsplash, ResetAt: s.resetAt}
+	}
+	return nil
+}
+
+// update запоминает лимиты из заголовков ответа. ok == false, если заголовков нет
+func (s *rateLimitState) update(h http.Header) (limit, remaining int, ok bool) {
+	remaining, err := strconv.Atoi(h.Get(headerRateLimitRemaining))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.known = true
+	s.remaining = remaining
+	if limit, err := strconv.Atoi(h.Get(headerRateLimit)); err == nil {
+		s.limit = limit
+	}
+	if remaining == 0 {
+		s.resetAt = resetTime(h)
+	}
+	return s.limit, remaining, true
+}
+
+// exhausted отмечает лимит исчерпанным по ответу 429/403 и возвращает ошибку для вызывающего
+func (s *rateLimitState) exhausted(h http.Header) *domain.ErrRateLimited {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.known = true
+	s.remaining = 0
+	s.resetAt = resetTime(h)
+	return &domain.ErrRateLimited{Source: domain.SourceUnsplash, ResetAt: s.resetAt}
+}
+
+// resetTime берёт момент сброса лимита из Retry-After (в секундах),
+// а без него — начало следующего часа
+func resetTime(h http.Header) time.Time {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	return time.Now().Truncate(time.Hour).Add(time.Hour)
+}
+
+// isRateLimited распознаёт ответ об исчерпанном лимите: 429 или 403,
+// которым Unsplash отвечает на превышение квоты demo-приложения
+func isRateLimited(resp *http.Response, body []byte) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get(headerRateLimitRemaining) == "0" ||
+			bytes.Contains(bytes.ToLower(body), []byte("rate limit exceeded"))
+	default:
+		return false
+	}
+}
@@ -6,37 +6,104 @@ import (
 	"log/slog"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/GoArmGo/MediaApp/internal/auth"
 	"github.com/GoArmGo/MediaApp/internal/config"
 	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/database/storage"
+	"github.com/GoArmGo/MediaApp/internal/events"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
 	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/GoArmGo/MediaApp/internal/webhook"
 	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type App struct {
 	Config               *config.Config
 	Logger               *slog.Logger
 	db                   *sqlx.DB
+	photoStorage         ports.PhotoStorage
+	auditStorage         ports.AuditStorage
+	idempotencyStorage   ports.IdempotencyStorage
 	photoUseCase         usecase.PhotoUseCase
+	userUseCase          usecase.UserUseCase
+	collectionUseCase    usecase.CollectionUseCase
+	seriesUseCase        usecase.SeriesUseCase
+	webhookUseCase       usecase.WebhookUseCase
+	tokenManager         *auth.TokenManager
+	validator            *validation.Validator
 	photoSearchPublisher ports.PhotoSearchPublisher
 	photoSearchConsumer  ports.PhotoSearchConsumer
+	embeddingConsumer    ports.EmbeddingConsumer
+	queueStats           ports.QueueStatsProvider
+	schemaVersion        ports.SchemaVersionReader
+	eventBus             *events.AsyncEventBus
+	webhookDispatcher    *webhook.Dispatcher
+	viewBuffer           *storage.ViewCountBuffer
+	stopPoolMonitor      context.CancelFunc
 	uploadLimiter        chan struct{}
+	metrics              *metrics.Metrics
+	metricsGatherer      prometheus.Gatherer
+	tracingShutdown      tracing.ShutdownFunc
 }
 
 func NewApp(cfg *config.Config,
 	Logger *slog.Logger,
 	db *sqlx.DB,
+	photoStorage ports.PhotoStorage,
+	auditStorage ports.AuditStorage,
+	idempotencyStorage ports.IdempotencyStorage,
 	photoUseCase usecase.PhotoUseCase,
+	userUseCase usecase.UserUseCase,
+	collectionUseCase usecase.CollectionUseCase,
+	seriesUseCase usecase.SeriesUseCase,
+	webhookUseCase usecase.WebhookUseCase,
+	tokenManager *auth.TokenManager,
+	validator *validation.Validator,
 	photoSearchPublisher ports.PhotoSearchPublisher,
 	photoSearchConsumer ports.PhotoSearchConsumer,
-	uploadLimiter chan struct{}) *App {
+	embeddingConsumer ports.EmbeddingConsumer,
+	queueStats ports.QueueStatsProvider,
+	schemaVersion ports.SchemaVersionReader,
+	eventBus *events.AsyncEventBus,
+	webhookDispatcher *webhook.Dispatcher,
+	viewBuffer *storage.ViewCountBuffer,
+	stopPoolMonitor context.CancelFunc,
+	uploadLimiter chan struct{},
+	appMetrics *metrics.Metrics,
+	metricsGatherer prometheus.Gatherer,
+	tracingShutdown tracing.ShutdownFunc) *App {
 	return &App{
+		Config:               cfg,
 		db:                   db,
+		photoStorage:         photoStorage,
+		auditStorage:         auditStorage,
+		idempotencyStorage:   idempotencyStorage,
 		Logger:               Logger,
 		photoUseCase:         photoUseCase,
+		userUseCase:          userUseCase,
+		collectionUseCase:    collectionUseCase,
+		seriesUseCase:        seriesUseCase,
+		webhookUseCase:       webhookUseCase,
+		tokenManager:         tokenManager,
+		validator:            validator,
 		photoSearchPublisher: photoSearchPublisher,
 		photoSearchConsumer:  photoSearchConsumer,
+		embeddingConsumer:    embeddingConsumer,
+		queueStats:           queueStats,
+		schemaVersion:        schemaVersion,
+		eventBus:             eventBus,
+		webhookDispatcher:    webhookDispatcher,
+		viewBuffer:           viewBuffer,
+		stopPoolMonitor:      stopPoolMonitor,
 		uploadLimiter:        uploadLimiter,
+		metrics:              appMetrics,
+		metricsGatherer:      metricsGatherer,
+		tracingShutdown:      tracingShutdown,
 	}
 }
 
@@ -52,14 +119,14 @@ func (a *App) Run(ctx context.Context, mode *string) error {
 	switch *mode {
 	case "server":
 		a.Logger.Info("starting server mode")
-		err = runServer(ctx, a.Config, a.photoUseCase, a.photoSearchPublisher, a.uploadLimiter, a.Logger)
+		err = runServer(ctx, a.Config, a.photoStorage, a.auditStorage, a.idempotencyStorage, a.photoUseCase, a.userUseCase, a.collectionUseCase, a.seriesUseCase, a.webhookUseCase, a.tokenManager, a.validator, a.photoSearchPublisher, a.queueStats, a.schemaVersion, a.uploadLimiter, a.metrics, a.metricsGatherer, a.Logger)
 
 	case "worker":
 		a.Logger.Info("starting worker mode")
-		err = runWorker(ctx, a.Config, a.photoUseCase, a.photoSearchConsumer, a.Logger)
+		err = runWorker(ctx, a.Config, a.photoUseCase, a.photoSearchConsumer, a.embeddingConsumer, a.idempotencyStorage, a.Logger)
 
 	default:
-		err = fmt.Errorf("неизвестный режим: %s (используйте 'server' или 'worker')", mode)
+		err = fmt.Errorf("неизвестный режим: %s (используйте 'server' или 'worker')", *mode)
 		a.Logger.Error("invalid mode", "mode", *mode, "error", err)
 	}
 
@@ -68,9 +135,9 @@ func (a *App) Run(ctx context.Context, mode *string) error {
 		return err
 	}
 
-	// ожидаем сигнал завершения
-	<-ctx.Done()
-	a.Logger.Info("shutdown signal received")
+	// runServer/runWorker блокируются до сигнала завершения
+	// (воркер также может завершиться сам после WorkerMaxMessages сообщений)
+	a.Logger.Info("application run finished, releasing resources")
 
 	// аккуратно закрываем ресурсы
 	if closeErr := a.Shutdown(); closeErr != nil {
@@ -85,6 +152,39 @@ func (a *App) Run(ctx context.Context, mode *string) error {
 
 // Shutdown закрывает все ресурсы приложения
 func (a *App) Shutdown() error {
+	// повторы доставки вебхуков не ждём: иначе остановка затянется на все паузы между попытками
+	if a.webhookDispatcher != nil {
+		a.webhookDispatcher.Close()
+	}
+
+	// дожидаемся подписчиков доменных событий: они ещё могут писать в кеш
+	if a.eventBus != nil {
+		a.Logger.Info("waiting for event handlers")
+		if err := a.eventBus.Close(); err != nil {
+			a.Logger.Error("failed to close event bus", "error", err)
+		}
+	}
+
+	// записываем накопленные просмотры фото, пока БД ещё открыта
+	if a.viewBuffer != nil {
+		a.Logger.Info("flushing photo views")
+		if err := a.viewBuffer.Close(); err != nil {
+			a.Logger.Error("failed to flush photo views", "error", err)
+		}
+	}
+
+	// дописываем накопившиеся события аудита, пока БД ещё открыта
+	if closer, ok := a.auditStorage.(interface{ Close() error }); ok {
+		a.Logger.Info("flushing audit log")
+		if err := closer.Close(); err != nil {
+			a.Logger.Error("failed to flush audit log", "error", err)
+		}
+	}
+
+	if a.stopPoolMonitor != nil {
+		a.stopPoolMonitor()
+	}
+
 	if a.db != nil {
 		a.Logger.Info("closing database connection")
 		if err := a.db.Close(); err != nil {
@@ -107,6 +207,15 @@ func (a *App) Shutdown() error {
 		}
 	}
 
+	// сбрасываем оставшиеся спаны в коллектор
+	if a.tracingShutdown != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.tracingShutdown(ctx); err != nil {
+			a.Logger.Error("failed to shutdown tracing", "error", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/database/sqlite"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// TestAutocompletePhotos_EndToEnd проходит GET /photos/autocomplete через роутер,
+// настоящий PhotoUseCase и хранилище SQLite
+func TestAutocompletePhotos_EndToEnd(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	photos, users := sqlite.NewPhotoStorage(db, logger), sqlite.NewUserStorage(db, logger)
+
+	userID, err := users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("create system user: %v", err)
+	}
+	for _, photo := range []domain.Photo{
+		{UnsplashID: "lake", Title: "Mountain lake", AuthorName: "Moss Walker"},
+		{UnsplashID: "dawn", Title: "mountains at dawn", AuthorName: "Ann"},
+		{UnsplashID: "gone", Title: "Mountain pass", AuthorName: "Ann"},
+	} {
+		photo.UserID = userID
+		photo.OriginalURL = "https://images.example.com/" + photo.UnsplashID
+		if _, err := photos.SavePhotoTx(ctx, &photo); err != nil {
+			t.Fatalf("save %s: %v", photo.UnsplashID, err)
+		}
+		if photo.UnsplashID == "gone" {
+			deletedAt := time.Now().UTC()
+			if err := photos.SetPhotoDeletedAt(ctx, photo.ID, &deletedAt); err != nil {
+				t.Fatalf("delete %s: %v", photo.UnsplashID, err)
+			}
+		}
+	}
+
+	photoUseCase := usecase.NewPhotoUseCase(photos, users, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, 0, usecase.StepTimeouts{}, nil, nil, logger)
+	r := newTestRouter(t, testConfig(), photoUseCase)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		want   []string
+	}{
+		{name: "titles and authors", query: "q=mo", status: http.StatusOK,
+			want: []string{"Moss Walker", "Mountain lake", "mountains at dawn"}},
+		{name: "case insensitive", query: "q=MOUNTAIN", status: http.StatusOK,
+			want: []string{"Mountain lake", "mountains at dawn"}},
+		{name: "limit", query: "q=mo&limit=1", status: http.StatusOK, want: []string{"Moss Walker"}},
+		{name: "no matches", query: "q=giraffe", status: http.StatusOK, want: []string{}},
+		{name: "missing prefix", query: "limit=5", status: http.StatusBadRequest},
+		{name: "limit above maximum", query: "q=mo&limit=21", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/photos/autocomplete?"+tt.query, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Suggestions []string `json:"suggestions"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v; body: %s", err, rec.Body)
+			}
+			if !slices.Equal(body.Suggestions, tt.want) {
+				t.Errorf("suggestions = %q, want %q", body.Suggestions, tt.want)
+			}
+		})
+	}
+}
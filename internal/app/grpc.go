@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/grpcapi"
+	"github.com/GoArmGo/MediaApp/internal/grpcapi/photov1"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startGRPCServer запускает gRPC API фото на GRPC_PORT. Ошибка работы сервера
+// приходит в возвращаемый канал; канал закрывается, когда сервер остановлен
+func startGRPCServer(cfg *config.Config, photoUseCase usecase.PhotoUseCase, validator *validation.Validator, logger *slog.Logger) (*grpc.Server, <-chan error, error) {
+	addr := fmt.Sprintf(":%s", cfg.GRPCPort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка при запуске gRPC-сервера на %s: %w", addr, err)
+	}
+
+	server := grpc.NewServer(grpcapi.UnaryInterceptors(cfg.RequestTimeout, logger))
+	photov1.RegisterPhotoServiceServer(server, grpcapi.NewPhotoServer(photoUseCase, validator, cfg.MaxPerPage, logger))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("grpc server started", "addr", addr)
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+	return server, serverErr, nil
+}
+
+// stopGRPCServer дожидается завершения активных вызовов, а по истечении ctx обрывает их
+func stopGRPCServer(ctx context.Context, server *grpc.Server, logger *slog.Logger) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		logger.Info("grpc server stopped gracefully")
+	case <-ctx.Done():
+		logger.Warn("grpc graceful stop timed out, closing remaining calls")
+		server.Stop()
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/GoArmGo/MediaApp/internal/database/migrator"
+)
+
+// RunMigrations выполняет действие -mode=migrate:
+//
+//	up        — применить все новые миграции (при autoRollback неудача возвращает схему к исходной версии)
+//	down [N]  — откатить N последних миграций (по умолчанию 1)
+//	version   — показать текущую версию схемы
+func RunMigrations(m *migrator.Migrator, args []string, autoRollback bool, logger *slog.Logger) error {
+	if len(args) == 0 {
+		return fmt.Errorf("не указано действие миграции: up, down [N] или version")
+	}
+
+	switch action := args[0]; action {
+	case "up":
+		logger.Info("applying migrations", "auto_rollback", autoRollback)
+		return m.Up(autoRollback)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("некорректное количество шагов отката %q", args[1])
+			}
+			steps = n
+		}
+		logger.Info("rolling back migrations", "steps", steps)
+		return m.Down(steps)
+
+	case "version":
+		version, dirty, err := m.Version()
+		if err != nil {
+			return err
+		}
+		logger.Info("current schema version", "version", version, "dirty", dirty)
+		return nil
+
+	default:
+		return fmt.Errorf("неизвестное действие миграции %q: используйте up, down [N] или version", action)
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runPeriodic выполняет job каждые interval, пока не отменён ctx.
+// Ошибка одного запуска логируется и не останавливает расписание
+func runPeriodic(ctx context.Context, name string, interval time.Duration, job func(context.Context) error, logger *slog.Logger) {
+	logger.Info("periodic job scheduled", "job", name, "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("periodic job stopped", "job", name)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := job(ctx); err != nil {
+				logger.Error("periodic job failed", "job", name, "error", err)
+				continue
+			}
+			logger.Info("periodic job finished", "job", name, "duration_ms", time.Since(start).Milliseconds())
+		}
+	}
+}
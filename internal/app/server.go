@@ -2,44 +2,50 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"log/slog"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
+	"github.com/GoArmGo/MediaApp/internal/auth"
 	"github.com/GoArmGo/MediaApp/internal/config"
 	"github.com/GoArmGo/MediaApp/internal/core/ports"
 	"github.com/GoArmGo/MediaApp/internal/handler"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
 	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
 )
 
 // runServer запускает HTTP сервер и логику публикации сообщений
 func runServer(
 	ctx context.Context,
 	cfg *config.Config,
+	photoStorage ports.PhotoStorage,
+	auditStorage ports.AuditStorage,
+	idempotencyStorage ports.IdempotencyStorage,
 	photoUseCase usecase.PhotoUseCase,
+	userUseCase usecase.UserUseCase,
+	collectionUseCase usecase.CollectionUseCase,
+	seriesUseCase usecase.SeriesUseCase,
+	webhookUseCase usecase.WebhookUseCase,
+	tokenManager *auth.TokenManager,
+	validator *validation.Validator,
 	photoSearchPublisher ports.PhotoSearchPublisher,
+	queueStats ports.QueueStatsProvider,
+	schemaVersion ports.SchemaVersionReader,
 	uploadLimiter chan struct{},
+	appMetrics *metrics.Metrics,
+	metricsGatherer prometheus.Gatherer,
 	logger *slog.Logger,
 ) error {
-	photoHandler := handler.NewPhotoHandler(photoUseCase, photoSearchPublisher, uploadLimiter, logger)
-
-	r := chi.NewRouter()
-
-	r.Use(handler.RequestLogger(logger))
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(cfg.RequestTimeout))
-
-	r.Get("/photos/{unsplashID}", photoHandler.GetOrCreatePhotoByUnsplashID)
-	r.Get("/photos/search", photoHandler.SearchAndSavePhotos)
-	r.Get("/photos/recent", photoHandler.GetRecentPhotosFromDB)
-	r.Get("/photos/{id}", photoHandler.GetPhotoDetailsFromDB)
+	r := newRouter(cfg, photoStorage, auditStorage, idempotencyStorage, photoUseCase, userUseCase, collectionUseCase,
+		seriesUseCase, webhookUseCase, tokenManager, validator, photoSearchPublisher, queueStats, schemaVersion,
+		uploadLimiter, appMetrics, metricsGatherer, logger)
 
 	serverAddr := fmt.Sprintf(":%s", cfg.ServerPort)
 	server := &http.Server{
@@ -47,28 +53,208 @@ func runServer(
 		Handler: r,
 	}
 
+	// gRPC API для внутренних сервисов работает рядом с HTTP на своём порту.
+	// Пока он выключен, grpcErr остаётся nil-каналом и в select не срабатывает
+	var (
+		grpcServer *grpc.Server
+		grpcErr    <-chan error
+	)
+	if cfg.GRPCPort != "" {
+		var err error
+		grpcServer, grpcErr, err = startGRPCServer(cfg, photoUseCase, validator, logger)
+		if err != nil {
+			logger.Error("grpc server failed to start", "error", err)
+			return err
+		}
+	}
+
+	// Ошибку запуска возвращаем через канал, а не log.Fatalf,
+	// чтобы App успел корректно закрыть ресурсы
+	serverErr := make(chan error, 1)
 	go func() {
-		log.Printf("Сервер запущен на %s", serverAddr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Ошибка при запуске сервера: %v", err)
+		logger.Info("server started", "addr", serverAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
 		}
+		close(serverErr)
 	}()
 
-	// Graceful Shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	<-quit
-	log.Println("Получен сигнал завершения. Завершаем работу сервера...")
+	// Graceful Shutdown: завершаемся по отмене родительского контекста (SIGINT/SIGTERM в App.Run)
+	// или если один из серверов упал — тогда останавливаем и второй
+	var runErr error
+	select {
+	case err, ok := <-serverErr:
+		if ok {
+			logger.Error("server failed", "error", err)
+			runErr = fmt.Errorf("ошибка при запуске сервера: %w", err)
+		}
+	case err, ok := <-grpcErr:
+		if ok {
+			logger.Error("grpc server failed", "error", err)
+			runErr = fmt.Errorf("ошибка при работе gRPC-сервера: %w", err)
+		}
+	case <-ctx.Done():
+		logger.Info("shutdown signal received, stopping server", "grace_period", cfg.ShutdownTimeout)
+	}
 
-	ctxServer, cancel := context.WithTimeout(ctx, 30*time.Second)
+	// Родительский контекст уже отменён, поэтому наследуем только его значения,
+	// а на завершение активных запросов даём отдельный grace period
+	ctxServer, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.ShutdownTimeout)
 	defer cancel()
 
+	if grpcServer != nil {
+		stopGRPCServer(ctxServer, grpcServer, logger)
+	}
 	if err := server.Shutdown(ctxServer); err != nil {
-		//log.Fatalf("Ошибка при завершении работы сервера: %v", err)
-		return fmt.Errorf("graceful shutdown failed: %w", err)
+		return errors.Join(runErr, fmt.Errorf("graceful shutdown failed: %w", err))
+	}
+	if runErr != nil {
+		return runErr
 	}
 
-	log.Println("Сервер успешно завершил работу.")
+	logger.Info("server stopped gracefully")
 	return nil
 }
+
+// newRouter собирает обработчики и маршруты HTTP API
+func newRouter(
+	cfg *config.Config,
+	photoStorage ports.PhotoStorage,
+	auditStorage ports.AuditStorage,
+	idempotencyStorage ports.IdempotencyStorage,
+	photoUseCase usecase.PhotoUseCase,
+	userUseCase usecase.UserUseCase,
+	collectionUseCase usecase.CollectionUseCase,
+	seriesUseCase usecase.SeriesUseCase,
+	webhookUseCase usecase.WebhookUseCase,
+	tokenManager *auth.TokenManager,
+	validator *validation.Validator,
+	photoSearchPublisher ports.PhotoSearchPublisher,
+	queueStats ports.QueueStatsProvider,
+	schemaVersion ports.SchemaVersionReader,
+	uploadLimiter chan struct{},
+	appMetrics *metrics.Metrics,
+	metricsGatherer prometheus.Gatherer,
+	logger *slog.Logger,
+) chi.Router {
+	photoHandler := handler.NewPhotoHandler(photoUseCase, photoSearchPublisher, uploadLimiter, int64(cfg.MaxUploadSizeMB)<<20, cfg.UploadIntentTTL, cfg.ColorMatchDistance, cfg.MaxPerPage, validator, logger)
+	userHandler := handler.NewUserHandler(userUseCase, tokenManager, logger)
+	collectionHandler := handler.NewCollectionHandler(collectionUseCase, cfg.MaxPerPage, logger)
+	seriesHandler := handler.NewSeriesHandler(seriesUseCase, cfg.MaxPerPage, logger)
+	webhookHandler := handler.NewWebhookHandler(webhookUseCase, validator, logger)
+	auditHandler := handler.NewAuditHandler(auditStorage, logger)
+	adminHandler := handler.NewAdminHandler(queueStats, schemaVersion, photoSearchPublisher, photoUseCase,
+		cfg.RabbitMQ.RabbitMQQueueName, cfg.RabbitMQ.RabbitMQDeadLetterQueue, cfg.AuthorImportMaxPhotos, validator, logger)
+	healthHandler := handler.NewHealthHandler(schemaVersion, logger)
+	// повтор загрузки или импорта после таймаута не должен сохранять фото второй раз
+	idempotent := handler.IdempotencyMiddleware(idempotencyStorage, time.Duration(cfg.IdempotencyKeyTTLHours)*time.Hour, logger)
+
+	r := chi.NewRouter()
+
+	r.Use(handler.RequestID())
+	r.Use(handler.TracingMiddleware())
+	r.Use(handler.RequestLogger(logger))
+	r.Use(handler.MetricsMiddleware(appMetrics))
+	r.Use(handler.AuditMiddleware(auditStorage, logger))
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.Timeout(cfg.RequestTimeout))
+	r.Use(handler.MaxBodySize(cfg.MaxRequestBodyBytes, logger))
+	r.Use(handler.NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst, cfg.RateLimitIdleTTL, cfg.APIKeys, logger).Middleware())
+
+	r.Method(http.MethodGet, "/metrics", metrics.Handler(metricsGatherer))
+	r.Get("/readyz", healthHandler.Ready)
+
+	r.Get("/photos/unsplash/{unsplashID}", photoHandler.GetOrCreatePhotoByUnsplashID)
+	r.Get("/photos/search/local", photoHandler.SearchPhotosInDB)
+	r.Get("/photos/download", photoHandler.DownloadPhotos)
+	r.Get("/photos/autocomplete", photoHandler.AutocompletePhotos)
+	r.Get("/photos/trending", photoHandler.GetTrendingPhotos)
+	r.Get("/photos/nearby", photoHandler.FindPhotosNear)
+	r.Get("/search/history", photoHandler.SearchHistory)
+	r.Get("/search/suggest", photoHandler.SuggestSearches)
+	r.Get("/tags/cloud", photoHandler.GetTagCloud)
+	r.Get("/tags/search", photoHandler.SearchTags)
+	r.With(handler.ETagMiddleware(photoStorage, cfg.MaxPerPage)).Get("/photos/recent", photoHandler.GetRecentPhotosFromDB)
+	r.With(handler.OptionalJWTAuth(tokenManager, logger), handler.MarkAdmin(cfg.AdminUserIDs), handler.ETagMiddleware(photoStorage, cfg.MaxPerPage)).
+		Get("/photos/{id}", photoHandler.GetPhotoDetailsFromDB)
+	r.Get("/photos/{id}/attribution", photoHandler.GetPhotoAttribution)
+	r.Get("/photos/{id}/similar", photoHandler.GetSimilarPhotos)
+	r.Post("/photos/{id}/view", photoHandler.RecordView)
+	r.Post("/photos/{id}/download", photoHandler.RecordDownload)
+	r.Get("/authors/{name}/photos", photoHandler.ListPhotosByAuthor)
+	r.Get("/users/{id}/photos", photoHandler.ListPhotosByUser)
+
+	// маршруты, которые тратят квоту Unsplash, место в S3, меняют фото или выгружают всю базу, требуют API-ключ
+	if len(cfg.APIKeys) == 0 {
+		logger.Warn("API_KEYS is not set: write endpoints are not protected by API key")
+	}
+	r.Group(func(r chi.Router) {
+		r.Use(handler.APIKeyAuth(cfg.APIKeys, logger))
+		r.Get("/photos/search", photoHandler.SearchAndSavePhotos)
+		r.Get("/photos/export", photoHandler.ExportPhotos)
+		r.Delete("/photos/{id}", photoHandler.DeletePhoto)
+		r.Post("/photos/{id}/restore", photoHandler.RestorePhoto)
+		r.Post("/photos/{id}/refresh", photoHandler.RefreshPhoto)
+		r.Post("/photos/batch-tag", photoHandler.BatchTagPhotos)
+		r.With(idempotent).Post("/photos/batch-import", photoHandler.BatchImportPhotos)
+	})
+
+	r.Post("/users/register", userHandler.Register)
+	r.Post("/users/login", userHandler.Login)
+	r.Group(func(r chi.Router) {
+		r.Use(handler.JWTAuth(tokenManager, logger))
+		r.Get("/users/me", userHandler.GetMe)
+		// загрузки тратят место в S3: кроме токена пользователя нужен API-ключ в X-API-Key
+		r.Group(func(r chi.Router) {
+			r.Use(handler.APIKeyAuth(cfg.APIKeys, logger))
+			r.With(idempotent).Post("/photos/upload", photoHandler.UploadPhoto)
+			r.Post("/photos/upload-intent", photoHandler.CreateUploadIntent)
+			r.Post("/photos/{id}/complete", photoHandler.CompleteUpload)
+		})
+		r.Patch("/users/me", userHandler.UpdateMe)
+		r.Get("/users/me/likes", photoHandler.ListMyLikes)
+		r.Put("/photos/{id}/like", photoHandler.LikePhoto)
+		r.Delete("/photos/{id}/like", photoHandler.UnlikePhoto)
+
+		r.Post("/collections", collectionHandler.CreateCollection)
+		r.Get("/collections", collectionHandler.ListCollections)
+		r.Get("/collections/{id}", collectionHandler.GetCollection)
+		r.Patch("/collections/{id}", collectionHandler.UpdateCollection)
+		r.Delete("/collections/{id}", collectionHandler.DeleteCollection)
+		r.Get("/collections/{id}/photos", collectionHandler.ListCollectionPhotos)
+		r.Post("/collections/{id}/photos/{photoID}", collectionHandler.AddCollectionPhoto)
+		r.Delete("/collections/{id}/photos/{photoID}", collectionHandler.RemoveCollectionPhoto)
+
+		r.Post("/series", seriesHandler.CreateSeries)
+		r.Get("/series", seriesHandler.ListSeries)
+		r.Get("/series/{id}", seriesHandler.GetSeries)
+		r.Patch("/series/{id}", seriesHandler.UpdateSeries)
+		r.Delete("/series/{id}", seriesHandler.DeleteSeries)
+		r.Get("/series/{id}/photos", seriesHandler.ListSeriesPhotos)
+		r.Post("/series/{id}/photos", seriesHandler.AddSeriesPhoto)
+		r.Patch("/series/{id}/photos", seriesHandler.ReorderSeriesPhotos)
+		r.Delete("/series/{id}/photos/{photoID}", seriesHandler.RemoveSeriesPhoto)
+
+		r.Post("/webhooks", webhookHandler.CreateWebhook)
+		r.Get("/webhooks", webhookHandler.ListWebhooks)
+		r.Get("/webhooks/{id}", webhookHandler.GetWebhook)
+		r.Delete("/webhooks/{id}", webhookHandler.DeleteWebhook)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(handler.JWTAuth(tokenManager, logger))
+		r.Use(handler.AdminOnly(cfg.AdminUserIDs, logger))
+		r.Get("/admin/audit", auditHandler.ListAuditEvents)
+		r.Get("/photos/trash", photoHandler.ListDeletedPhotos)
+	})
+	// мониторинг и служебные задачи для скриптов: по отдельному ключу, без JWT
+	r.Group(func(r chi.Router) {
+		r.Use(handler.AdminAPIKeyAuth(cfg.AdminAPIKey, logger))
+		r.Get("/admin/queues", adminHandler.GetQueueStats)
+		r.Get("/admin/migration-version", adminHandler.GetMigrationVersion)
+		r.With(idempotent).Post("/admin/collections/{id}/import", adminHandler.ImportCollection)
+		r.With(idempotent).Post("/admin/authors/{username}/import", adminHandler.ImportAuthor)
+		r.Post("/photos/{id}/refresh-stats", photoHandler.RefreshPhotoStats)
+	})
+
+	return r
+}
@@ -0,0 +1,257 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/auth"
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/database/sqlite"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/handler"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// stubPhotoUseCase подменяет нужные тесту методы PhotoUseCase; вызов остальных паникует
+type stubPhotoUseCase struct {
+	usecase.PhotoUseCase
+	getOrCreate  func(ctx context.Context, unsplashID string) (*domain.Photo, error)
+	listDeleted  func(ctx context.Context, page, perPage int) ([]domain.Photo, int64, error)
+	refresh      func(ctx context.Context, unsplashID string) (*domain.Photo, error)
+	refreshStats func(ctx context.Context, unsplashID string) (*domain.Photo, error)
+}
+
+func (s *stubPhotoUseCase) RefreshPhotoStats(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+	return s.refreshStats(ctx, unsplashID)
+}
+
+func (s *stubPhotoUseCase) RefreshPhotoMetadata(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+	return s.refresh(ctx, unsplashID)
+}
+
+func (s *stubPhotoUseCase) ListDeletedPhotos(ctx context.Context, page, perPage int) ([]domain.Photo, int64, error) {
+	return s.listDeleted(ctx, page, perPage)
+}
+
+func (s *stubPhotoUseCase) GetOrCreatePhotoByUnsplashID(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+	return s.getOrCreate(ctx, unsplashID)
+}
+
+// testConfig — минимальная конфигурация, с которой собирается роутер
+func testConfig() *config.Config {
+	return &config.Config{
+		RequestTimeout:      5 * time.Second,
+		MaxRequestBodyBytes: 1 << 20,
+		MaxUploadSizeMB:     10,
+		UploadIntentTTL:     15 * time.Minute,
+		MaxPerPage:          100,
+	}
+}
+
+// testTokens подписывает токены для роутера из newTestRouter
+var testTokens = auth.NewTokenManager("test-secret", time.Hour)
+
+func newTestRouter(t *testing.T, cfg *config.Config, photoUseCase usecase.PhotoUseCase) chi.Router {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reg := prometheus.NewRegistry()
+	return newRouter(cfg, nil, nil, nil, photoUseCase, nil, nil, nil, nil,
+		testTokens, validation.New(), nil, nil, nil,
+		make(chan struct{}, 1), metrics.New(reg), reg, logger)
+}
+
+// newTestRouterWithDB — newTestRouter с аудитом и ключами идемпотентности в SQLite:
+// без них роутер не принимает POST-запросы
+func newTestRouterWithDB(t *testing.T, cfg *config.Config, photoUseCase usecase.PhotoUseCase) chi.Router {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	reg := prometheus.NewRegistry()
+	return newRouter(cfg, nil, sqlite.NewAuditStorage(db, logger), sqlite.NewIdempotencyStorage(db, logger), photoUseCase, nil, nil, nil, nil,
+		testTokens, validation.New(), nil, nil, nil,
+		make(chan struct{}, 1), metrics.New(reg), reg, logger)
+}
+
+// bearer возвращает заголовок Authorization с токеном пользователя userID
+func bearer(t *testing.T, userID uuid.UUID) string {
+	t.Helper()
+	token, err := testTokens.Issue(userID)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func TestRouter_UnsplashPhotoRouteReachable(t *testing.T) {
+	called := false
+	r := newTestRouter(t, testConfig(), &stubPhotoUseCase{
+		getOrCreate: func(_ context.Context, unsplashID string) (*domain.Photo, error) {
+			called = true
+			if unsplashID != "abc123" {
+				t.Errorf("unsplash ID = %q, want %q", unsplashID, "abc123")
+			}
+			return nil, domain.ErrExternalPhotoNotFound
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/photos/unsplash/abc123", nil))
+
+	if !called {
+		t.Fatal("GET /photos/unsplash/{unsplashID} did not reach GetOrCreatePhotoByUnsplashID")
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusNotFound, rec.Body)
+	}
+}
+
+func TestRouter_TrashIsAdminOnly(t *testing.T) {
+	adminID, userID := uuid.New(), uuid.New()
+	cfg := testConfig()
+	cfg.AdminUserIDs = []uuid.UUID{adminID}
+	r := newTestRouter(t, cfg, &stubPhotoUseCase{
+		listDeleted: func(context.Context, int, int) ([]domain.Photo, int64, error) {
+			return []domain.Photo{{ID: uuid.New()}}, 1, nil
+		},
+	})
+
+	tests := []struct {
+		name   string
+		auth   string
+		status int
+	}{
+		{name: "anonymous", status: http.StatusUnauthorized},
+		{name: "regular user", auth: bearer(t, userID), status: http.StatusForbidden},
+		{name: "admin", auth: bearer(t, adminID), status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/photos/trash", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+}
+
+func TestRouter_BatchImportIsIdempotent(t *testing.T) {
+	var calls []string
+	uc := &stubPhotoUseCase{
+		getOrCreate: func(_ context.Context, unsplashID string) (*domain.Photo, error) {
+			calls = append(calls, unsplashID)
+			return &domain.Photo{ID: uuid.New(), UnsplashID: unsplashID}, nil
+		},
+	}
+	cfg := testConfig()
+	cfg.APIKeys = []string{"import-key"}
+	cfg.IdempotencyKeyTTLHours = 24
+	r := newTestRouterWithDB(t, cfg, uc)
+
+	importPhotos := func(apiKey, idempotencyKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/photos/batch-import", strings.NewReader(`{"unsplash_ids": ["a", "b"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(handler.IdempotencyKeyHeader, idempotencyKey)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := importPhotos("", "retry-1"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("without API key: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	first := importPhotos("import-key", "retry-1")
+	if first.Code != http.StatusOK {
+		t.Fatalf("first import: status = %d, want %d; body: %s", first.Code, http.StatusOK, first.Body)
+	}
+	retry := importPhotos("import-key", "retry-1")
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want the cached %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("retry was not replayed from the idempotency cache")
+	}
+	if want := []string{"a", "b"}; !slices.Equal(calls, want) {
+		t.Errorf("imported %v, want %v once", calls, want)
+	}
+
+	if rec := importPhotos("import-key", "retry-2"); rec.Code != http.StatusOK || len(calls) != 4 {
+		t.Errorf("new key: status = %d after %d imports, want %d after 4", rec.Code, len(calls), http.StatusOK)
+	}
+}
+
+func TestRouter_UploadRequiresTokenAndAPIKey(t *testing.T) {
+	cfg := testConfig()
+	cfg.APIKeys = []string{"upload-key"}
+	r := newTestRouterWithDB(t, cfg, &stubPhotoUseCase{})
+	token := bearer(t, uuid.New())
+
+	tests := []struct {
+		name    string
+		auth    string
+		apiKey  string
+		status  int
+		message string // часть сообщения об ошибке: какая проверка не пройдена
+	}{
+		{name: "anonymous", status: http.StatusUnauthorized, message: "авторизация"},
+		{name: "API key without token", apiKey: "upload-key", status: http.StatusUnauthorized, message: "авторизация"},
+		{name: "token without API key", auth: token, status: http.StatusUnauthorized, message: "API-ключ"},
+		{name: "token with wrong API key", auth: token, apiKey: "old-key", status: http.StatusUnauthorized, message: "API-ключ"},
+		// обе проверки пройдены: запрос дошёл до обработчика, и тот отклонил пустую форму
+		{name: "token and API key", auth: token, apiKey: "upload-key", status: http.StatusBadRequest, message: "форма"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/photos/upload", strings.NewReader("not a form"))
+			req.Header.Set("Content-Type", "text/plain")
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set(handler.APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+			var resp handler.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !strings.Contains(strings.ToLower(resp.Message), strings.ToLower(tt.message)) {
+				t.Errorf("message = %q, want it to mention %q", resp.Message, tt.message)
+			}
+		})
+	}
+}
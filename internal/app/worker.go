@@ -2,17 +2,20 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/config"
 	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+	applog "github.com/GoArmGo/MediaApp/internal/logger"
 	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
 	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/google/uuid"
 )
 
 // runWorker запускает потребителя RabbitMQ и обрабатывает сообщения
@@ -21,54 +24,155 @@ func runWorker(
 	cfg *config.Config,
 	photoUseCase usecase.PhotoUseCase,
 	photoSearchConsumer ports.PhotoSearchConsumer,
+	embeddingConsumer ports.EmbeddingConsumer, // nil — эмбеддинги не вычисляются
+	idempotencyStorage ports.IdempotencyStorage,
 	logger *slog.Logger, // ← добавили логгер
 ) error {
-	logger.Info("worker started", "queue", cfg.RabbitMQ.RabbitMQQueueName)
+	maxMessages := int64(cfg.WorkerMaxMessages)
+	logger.Info("worker started", "broker", cfg.MessageBroker, "max_messages", maxMessages)
 
 	workerCtx, cancelWorker := context.WithCancel(ctx)
 	defer cancelWorker()
 
+	// done закрывается, когда обработано maxMessages сообщений
+	done := make(chan struct{})
+	var processed atomic.Int64
+
 	// Определяем функцию-обработчик для сообщений RabbitMQ
 	messageHandler := func(ctx context.Context, payload payloads.PhotoSearchPayload) error {
-		logger.Info("processing task",
-			"query", payload.Query,
-			"page", payload.Page,
-			"per_page", payload.PerPage,
-		)
+		// ID запроса, опубликовавшего задачу, или новый — чтобы связать все логи обработки сообщения
+		requestID := payload.RequestID
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		ctx = applog.WithRequestID(ctx, requestID)
+		log := applog.FromContext(ctx, logger)
 
-		// Вызываем PhotoUseCase для выполнения реальной работы
-		_, err := photoUseCase.SearchAndSavePhotos(ctx, payload.Query, payload.Page, payload.PerPage)
-		if err != nil {
-			logger.Error("failed to process task",
-				"query", payload.Query,
-				"page", payload.Page,
-				"per_page", payload.PerPage,
-				"error", err,
-			)
-			return err
+		switch payload.Type {
+		case "", payloads.TaskSearchPhotos:
+			if err := processSearchTask(ctx, photoUseCase, payload, log); err != nil {
+				waitBeforeRequeue(ctx, err, log)
+				return err
+			}
+		case payloads.TaskRefreshPhoto:
+			if err := processRefreshTask(ctx, photoUseCase, payload, log); err != nil {
+				waitBeforeRequeue(ctx, err, log)
+				return err
+			}
+		case payloads.TaskRefreshStats:
+			if err := processStatsRefreshTask(ctx, photoUseCase, payload.StatsRefreshPayload(), log); err != nil {
+				waitBeforeRequeue(ctx, err, log)
+				return err
+			}
+		case payloads.TaskSyncCollection:
+			// лимиты Unsplash между страницами выдерживает сам usecase
+			if err := processCollectionSyncTask(ctx, photoUseCase, payload, log); err != nil {
+				return err
+			}
+		case payloads.TaskSyncAuthor:
+			if err := processAuthorSyncTask(ctx, photoUseCase, payload, log); err != nil {
+				return err
+			}
+		default:
+			// Повтор не поможет — подтверждаем сообщение, чтобы не зациклить его в очереди
+			log.Error("unknown task type, skipping", "type", payload.Type)
 		}
 
-		logger.Info("task processed successfully",
-			"query", payload.Query,
-			"page", payload.Page,
-			"per_page", payload.PerPage,
-		)
+		// Потребитель подтверждает сообщение сразу после возврата из обработчика
+		// и только потом проверяет отмену контекста, поэтому последнее сообщение тоже будет ACK-нуто
+		if maxMessages > 0 && processed.Add(1) == maxMessages {
+			logger.Info("max messages limit reached, stopping worker", "max_messages", maxMessages)
+			cancelWorker()
+			close(done)
+		}
 		return nil
 	}
 
+	// Периодическая очистка файлов в S3, для которых нет записи в БД
+	if cfg.OrphanCleanupInterval > 0 {
+		go runPeriodic(workerCtx, "cleanup_orphaned_objects", cfg.OrphanCleanupInterval, func(ctx context.Context) error {
+			_, err := photoUseCase.CleanupOrphanedObjects(ctx, cfg.OrphanMinAge)
+			return err
+		}, logger)
+	}
+
+	// Периодическая очистка корзины от фото старше PhotoRetentionDays
+	if cfg.PhotoRetentionDays > 0 && cfg.PhotoPurgeInterval > 0 {
+		retention := time.Duration(cfg.PhotoRetentionDays) * 24 * time.Hour
+		go runPeriodic(workerCtx, "purge_deleted_photos", cfg.PhotoPurgeInterval, func(ctx context.Context) error {
+			_, err := photoUseCase.PurgeDeletedPhotos(ctx, retention)
+			return err
+		}, logger)
+	}
+
+	// Периодический пересчёт популярности фото для GET /photos/trending
+	if cfg.PopularityUpdateInterval > 0 {
+		go runPeriodic(workerCtx, "update_popularity_scores", cfg.PopularityUpdateInterval, func(ctx context.Context) error {
+			_, err := photoUseCase.UpdatePopularityScores(ctx)
+			return err
+		}, logger)
+	}
+
+	// Периодическое обновление просмотров и скачиваний недавно просмотренных фото из статистики Unsplash
+	if cfg.StatsRefreshInterval > 0 && cfg.StatsRefreshLimit > 0 {
+		go runPeriodic(workerCtx, "refresh_photo_stats", cfg.StatsRefreshInterval, func(ctx context.Context) error {
+			_, err := photoUseCase.RefreshRecentPhotoStats(ctx, cfg.StatsRefreshLimit, cfg.StatsRefreshSpacing)
+			if errors.Is(err, usecase.ErrStatsUnavailable) {
+				return nil // источник Unsplash не настроен
+			}
+			return err
+		}, logger)
+	}
+
+	// Периодическое удаление истёкших ключей идемпотентности
+	if cfg.IdempotencyKeyTTLHours > 0 && cfg.IdempotencyCleanupInterval > 0 {
+		ttl := time.Duration(cfg.IdempotencyKeyTTLHours) * time.Hour
+		go runPeriodic(workerCtx, "delete_expired_idempotency_keys", cfg.IdempotencyCleanupInterval, func(ctx context.Context) error {
+			_, err := idempotencyStorage.DeleteExpiredIdempotencyKeys(ctx, time.Now().Add(-ttl))
+			return err
+		}, logger)
+	}
+
+	// Периодическое удаление брошенных загрузок напрямую в S3 вместе с их файлами
+	if cfg.UploadIntentCleanupInterval > 0 {
+		go runPeriodic(workerCtx, "cleanup_upload_intents", cfg.UploadIntentCleanupInterval, func(ctx context.Context) error {
+			_, err := photoUseCase.CleanupExpiredUploadIntents(ctx)
+			return err
+		}, logger)
+	}
+
 	// Запускаем потребление сообщений
 	err := photoSearchConsumer.StartConsumingPhotoSearchRequests(workerCtx, messageHandler)
 	if err != nil {
-		logger.Error("failed to start RabbitMQ consumer", "error", err)
-		return fmt.Errorf("ошибка при запуске потребителя RabbitMQ: %w", err)
+		logger.Error("failed to start message consumer", "broker", cfg.MessageBroker, "error", err)
+		return fmt.Errorf("ошибка при запуске потребителя %s: %w", cfg.MessageBroker, err)
 	}
 
-	// Graceful Shutdown для воркера
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Задачи эмбеддингов идут через свою очередь и в счёт WorkerMaxMessages не входят
+	if embeddingConsumer != nil {
+		embeddingHandler := func(ctx context.Context, payload payloads.EmbeddingPayload) error {
+			requestID := payload.RequestID
+			if requestID == "" {
+				requestID = uuid.NewString()
+			}
+			ctx = applog.WithRequestID(ctx, requestID)
+			return processEmbeddingTask(ctx, photoUseCase, payload, applog.FromContext(ctx, logger))
+		}
+		if err := embeddingConsumer.StartConsumingEmbeddingRequests(workerCtx, embeddingHandler); err != nil {
+			logger.Error("failed to start embedding consumer", "broker", cfg.MessageBroker, "error", err)
+			return fmt.Errorf("ошибка при запуске потребителя эмбеддингов %s: %w", cfg.MessageBroker, err)
+		}
+	} else {
+		logger.Info("embedding consumer disabled")
+	}
 
-	logger.Warn("shutdown signal received, stopping worker...")
+	// Graceful Shutdown для воркера: ждём сигнал завершения (ctx) или достижения лимита сообщений
+	select {
+	case <-ctx.Done():
+		logger.Warn("shutdown signal received, stopping worker...")
+	case <-done:
+		logger.Info("worker finished processing messages", "processed", processed.Load())
+	}
 
 	cancelWorker()
 
@@ -77,3 +181,165 @@ func runWorker(
 
 	return nil
 }
+
+// lockRetryDelay — пауза перед возвратом в очередь сообщения, фото из которого
+// прямо сейчас обрабатывает другой экземпляр
+const lockRetryDelay = time.Second
+
+// waitBeforeRequeue ждёт (или отмены ctx), прежде чем сообщение вернётся в очередь: иначе воркер
+// будет сразу получать его снова и тратить попытки впустую. При исчерпанном лимите внешнего API
+// ждёт его сброса, при занятой блокировке фото — lockRetryDelay. Для остальных ошибок ничего не делает
+func waitBeforeRequeue(ctx context.Context, err error, log *slog.Logger) {
+	var wait time.Duration
+	var rateLimited *domain.ErrRateLimited
+	switch {
+	case errors.As(err, &rateLimited):
+		wait = rateLimited.RetryAfter()
+		log.Warn("photo source rate limit exceeded, delaying requeue", "source", rateLimited.Source, "retry_in", wait)
+	case errors.Is(err, lock.ErrNotAcquired):
+		wait = lockRetryDelay
+		log.Warn("photo is locked by another instance, delaying requeue", "retry_in", wait)
+	default:
+		return
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// processSearchTask ищет фото во внешнем API и сохраняет их
+func processSearchTask(ctx context.Context, photoUseCase usecase.PhotoUseCase, payload payloads.PhotoSearchPayload, log *slog.Logger) error {
+	log.Info("processing task",
+		"query", payload.Query,
+		"page", payload.Page,
+		"per_page", payload.PerPage,
+	)
+
+	// Вызываем PhotoUseCase для выполнения реальной работы
+	result, err := photoUseCase.SearchAndSavePhotos(ctx, payload.Query, payload.Page, payload.PerPage,
+		payload.Orientation, payload.Color, payload.OrderBy, payload.MinWidth, payload.MinHeight, domain.SearchSourceWorker)
+	if err != nil {
+		log.Error("failed to process task",
+			"query", payload.Query,
+			"page", payload.Page,
+			"per_page", payload.PerPage,
+			"error", err,
+		)
+		return err
+	}
+
+	log.Info("task processed successfully",
+		"query", payload.Query,
+		"page", payload.Page,
+		"per_page", payload.PerPage,
+		"saved", len(result.Photos),
+		"total", result.Total,
+		"total_pages", result.TotalPages,
+	)
+
+	return nil
+}
+
+// processRefreshTask обновляет метаданные одного фото из Unsplash
+func processRefreshTask(ctx context.Context, photoUseCase usecase.PhotoUseCase, payload payloads.PhotoSearchPayload, log *slog.Logger) error {
+	log.Info("processing refresh task", "unsplash_id", payload.UnsplashID)
+
+	if _, err := photoUseCase.RefreshPhotoMetadata(ctx, payload.UnsplashID); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			// Фото удалено у нас или из Unsplash, пока задача ждала в очереди — повторять бессмысленно
+			log.Warn("photo for refresh task not found", "unsplash_id", payload.UnsplashID)
+			return nil
+		}
+		log.Error("failed to process refresh task", "unsplash_id", payload.UnsplashID, "error", err)
+		return err
+	}
+
+	log.Info("refresh task processed successfully", "unsplash_id", payload.UnsplashID)
+	return nil
+}
+
+// processStatsRefreshTask обновляет просмотры и скачивания фото из статистики Unsplash
+func processStatsRefreshTask(ctx context.Context, photoUseCase usecase.PhotoUseCase, payload payloads.PhotoStatsRefreshPayload, log *slog.Logger) error {
+	log.Info("processing stats refresh task", "unsplash_id", payload.UnsplashID)
+
+	if _, err := photoUseCase.RefreshPhotoStats(ctx, payload.UnsplashID); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) || errors.Is(err, usecase.ErrStatsUnavailable) {
+			// Фото удалено у нас или из Unsplash, либо Unsplash не настроен — повторять бессмысленно
+			log.Warn("stats refresh task skipped", "unsplash_id", payload.UnsplashID, "error", err)
+			return nil
+		}
+		log.Error("failed to process stats refresh task", "unsplash_id", payload.UnsplashID, "error", err)
+		return err
+	}
+
+	log.Info("stats refresh task processed successfully", "unsplash_id", payload.UnsplashID)
+	return nil
+}
+
+// processCollectionSyncTask импортирует коллекцию Unsplash страница за страницей
+func processCollectionSyncTask(ctx context.Context, photoUseCase usecase.PhotoUseCase, payload payloads.PhotoSearchPayload, log *slog.Logger) error {
+	log.Info("processing collection sync task",
+		"collection_id", payload.CollectionID,
+		"page", payload.Page,
+		"per_page", payload.PerPage,
+	)
+
+	saved, err := photoUseCase.ImportUnsplashCollection(ctx, payload.CollectionID, payload.Page, payload.PerPage)
+	if err != nil {
+		if errors.Is(err, domain.ErrExternalCollectionNotFound) || errors.Is(err, usecase.ErrCollectionImportUnavailable) {
+			// Повтор не поможет: коллекции нет или Unsplash не настроен
+			log.Warn("collection sync task skipped", "collection_id", payload.CollectionID, "error", err)
+			return nil
+		}
+		log.Error("failed to process collection sync task", "collection_id", payload.CollectionID, "saved", saved, "error", err)
+		return err
+	}
+
+	log.Info("collection sync task processed successfully", "collection_id", payload.CollectionID, "saved", saved)
+	return nil
+}
+
+// processAuthorSyncTask импортирует фото автора Unsplash страница за страницей
+func processAuthorSyncTask(ctx context.Context, photoUseCase usecase.PhotoUseCase, payload payloads.PhotoSearchPayload, log *slog.Logger) error {
+	log.Info("processing author sync task",
+		"username", payload.AuthorUsername,
+		"per_page", payload.PerPage,
+		"max_photos", payload.MaxPhotos,
+	)
+
+	saved, err := photoUseCase.ImportAuthorPhotos(ctx, payload.AuthorUsername, payload.PerPage, payload.MaxPhotos)
+	if err != nil {
+		if errors.Is(err, domain.ErrExternalAuthorNotFound) || errors.Is(err, usecase.ErrAuthorImportUnavailable) {
+			// Повтор не поможет: автора нет или Unsplash не настроен
+			log.Warn("author sync task skipped", "username", payload.AuthorUsername, "error", err)
+			return nil
+		}
+		log.Error("failed to process author sync task", "username", payload.AuthorUsername, "saved", saved, "error", err)
+		return err
+	}
+
+	log.Info("author sync task processed successfully", "username", payload.AuthorUsername, "saved", saved)
+	return nil
+}
+
+// processEmbeddingTask вычисляет и сохраняет эмбеддинг фото
+func processEmbeddingTask(ctx context.Context, photoUseCase usecase.PhotoUseCase, payload payloads.EmbeddingPayload, log *slog.Logger) error {
+	log.Info("processing embedding task", "photo_id", payload.PhotoID)
+
+	if err := photoUseCase.ComputePhotoEmbedding(ctx, payload.PhotoID); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) || errors.Is(err, usecase.ErrEmbeddingUnavailable) {
+			// Фото удалено, пока задача ждала в очереди, или сервис эмбеддингов не настроен — повтор не поможет
+			log.Warn("embedding task skipped", "photo_id", payload.PhotoID, "error", err)
+			return nil
+		}
+		log.Error("failed to process embedding task", "photo_id", payload.PhotoID, "error", err)
+		return err
+	}
+
+	log.Info("embedding task processed successfully", "photo_id", payload.PhotoID)
+	return nil
+}
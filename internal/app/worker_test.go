@@ -0,0 +1,144 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
+)
+
+// queueConsumer — PhotoSearchConsumer, который отдаёт обработчику сообщения из бесконечной очереди
+// и, как настоящий потребитель, подтверждает сообщение после обработчика и только потом проверяет отмену
+type queueConsumer struct {
+	acked atomic.Int64
+	wg    sync.WaitGroup
+}
+
+func (c *queueConsumer) StartConsumingPhotoSearchRequests(ctx context.Context, handler func(context.Context, payloads.PhotoSearchPayload) error) error {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for ctx.Err() == nil {
+			payload := payloads.PhotoSearchPayload{Type: payloads.TaskRefreshPhoto, UnsplashID: "abc"}
+			if err := handler(ctx, payload); err == nil {
+				c.acked.Add(1)
+			}
+		}
+	}()
+	return nil
+}
+
+func TestRunWorker_ExitsAfterMaxMessages(t *testing.T) {
+	for _, maxMessages := range []int{1, 3} {
+		t.Run(fmt.Sprintf("max %d", maxMessages), func(t *testing.T) {
+			t.Parallel()
+			consumer := &queueConsumer{}
+			var refreshed atomic.Int64
+			uc := &stubPhotoUseCase{
+				refresh: func(context.Context, string) (*domain.Photo, error) {
+					refreshed.Add(1)
+					return &domain.Photo{}, nil
+				},
+			}
+			cfg := &config.Config{MessageBroker: "test", WorkerMaxMessages: maxMessages}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			errc := make(chan error, 1)
+			go func() { errc <- runWorker(context.Background(), cfg, uc, consumer, nil, nil, logger) }()
+
+			select {
+			case err := <-errc:
+				if err != nil {
+					t.Fatalf("runWorker: %v", err)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("worker did not exit after %d messages", maxMessages)
+			}
+			consumer.wg.Wait()
+
+			if got := consumer.acked.Load(); got != int64(maxMessages) {
+				t.Errorf("acked = %d, want %d", got, maxMessages)
+			}
+			if got := refreshed.Load(); got != int64(maxMessages) {
+				t.Errorf("processed = %d, want %d", got, maxMessages)
+			}
+		})
+	}
+}
+
+// listConsumer — PhotoSearchConsumer, который по очереди отдаёт обработчику заданные сообщения в JSON,
+// как они приходят из брокера
+type listConsumer struct {
+	messages [][]byte
+	wg       sync.WaitGroup
+}
+
+func (c *listConsumer) StartConsumingPhotoSearchRequests(ctx context.Context, handler func(context.Context, payloads.PhotoSearchPayload) error) error {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for _, message := range c.messages {
+			var payload payloads.PhotoSearchPayload
+			if err := json.Unmarshal(message, &payload); err != nil || ctx.Err() != nil {
+				return
+			}
+			_ = handler(ctx, payload)
+		}
+	}()
+	return nil
+}
+
+func TestRunWorker_StatsRefreshTask(t *testing.T) {
+	typed, err := json.Marshal(payloads.NewStatsRefreshTask(payloads.PhotoStatsRefreshPayload{UnsplashID: "typed"}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	consumer := &listConsumer{messages: [][]byte{
+		typed,
+		// сообщение, опубликованное до появления PhotoStatsRefreshPayload
+		[]byte(`{"type":"refresh_stats","unsplash_id":"legacy"}`),
+	}}
+
+	var mu sync.Mutex
+	var refreshed []string
+	uc := &stubPhotoUseCase{
+		refreshStats: func(_ context.Context, unsplashID string) (*domain.Photo, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			refreshed = append(refreshed, unsplashID)
+			return &domain.Photo{}, nil
+		},
+	}
+	cfg := &config.Config{MessageBroker: "test", WorkerMaxMessages: len(consumer.messages)}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	errc := make(chan error, 1)
+	go func() { errc <- runWorker(context.Background(), cfg, uc, consumer, nil, nil, logger) }()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("runWorker: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("worker did not exit after the stats refresh messages")
+	}
+	consumer.wg.Wait()
+
+	if want := []string{"typed", "legacy"}; !slices.Equal(refreshed, want) {
+		t.Errorf("refreshed = %v, want %v", refreshed, want)
+	}
+	if !bytes.Contains(typed, []byte(`"stats_refresh":{"unsplash_id":"typed"}`)) {
+		t.Errorf("typed message = %s, want the photo in stats_refresh", typed)
+	}
+}
@@ -7,34 +7,250 @@ import (
 
 	// Будет нужен для ручного парсинга bool из строки
 	"github.com/caarlos0/env/v6"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 )
 
 // Config хранит все конфигурационные параметры приложения
 type Config struct {
 	MaxConcurrentUploads int
-	RequestTimeout       time.Duration
 
-	DatabaseURL    string `env:"DATABASE_URL,required"`
-	ServerPort     string `env:"SERVER_PORT"`
-	UnsplashAPIKey string `env:"UNSPLASH_API_KEY,required"`
+	// RequestTimeout — сколько может обрабатываться один HTTP-запрос: по истечении контекст запроса
+	// отменяется, и клиент получает 504. Задаётся длительностью, например 30s или 1m30s
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" envDefault:"30s"`
 
-	// Настройки для MinIO
-	MinioEndpoint        string `env:"MINIO_ENDPOINT,required"`
-	MinioAccessKeyID     string `env:"MINIO_ACCESS_KEY_ID,required"`
-	MinioSecretAccessKey string `env:"MINIO_SECRET_ACCESS_KEY,required"`
+	// ShutdownTimeout — сколько ждать завершения активных HTTP-запросов при остановке
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+
+	DatabaseURL string `env:"DATABASE_URL"`
+	ServerPort  string `env:"SERVER_PORT"`
+
+	// GRPCPort — порт gRPC API фото для внутренних сервисов, который в режиме server
+	// работает рядом с HTTP. Пустой порт — gRPC API выключен
+	GRPCPort string `env:"GRPC_PORT"`
+
+	// Хранилище метаданных: postgres (по умолчанию) или sqlite — файл SQLitePath
+	// для локальной разработки без PostgreSQL. DATABASE_URL нужен только для postgres
+	StorageDriver string `env:"STORAGE_DRIVER" envDefault:"postgres"`
+	SQLitePath    string `env:"SQLITE_PATH" envDefault:"mediaapp.db"`
+
+	// AutoMigrate — применять миграции при старте server/worker; при false их запускают
+	// явно через -mode=migrate
+	AutoMigrate bool `env:"AUTO_MIGRATE" envDefault:"true"`
+	// MigrationAutoRollback — если миграция упала, откатить применённые за этот запуск
+	// и вернуть схему к версии до запуска, а не оставлять её в состоянии dirty
+	MigrationAutoRollback bool `env:"MIGRATION_AUTO_ROLLBACK" envDefault:"true"`
+
+	// Подключение к БД при старте: до DBConnectMaxAttempts попыток, пауза от DBConnectBaseDelayMs
+	// с удвоением после каждой неудачи
+	DBConnectMaxAttempts int `env:"DB_CONNECT_MAX_ATTEMPTS" envDefault:"10"`
+	DBConnectBaseDelayMs int `env:"DB_CONNECT_BASE_DELAY_MS" envDefault:"500"`
+
+	// Пул соединений с Postgres. 0 открытых — без ограничения, 0 простаивающих — не держать их.
+	// DBConnMaxLifetimeMinutes — через сколько минут соединение закрывается и открывается заново
+	// (например, чтобы пул переключился на новый адрес базы после failover); 0 — соединения не пересоздаются
+	DBMaxOpenConns           int `env:"DB_MAX_OPEN_CONNS" envDefault:"25"`
+	DBMaxIdleConns           int `env:"DB_MAX_IDLE_CONNS" envDefault:"10"`
+	DBConnMaxLifetimeMinutes int `env:"DB_CONN_MAX_LIFETIME_MINUTES" envDefault:"5"`
+
+	// DBSlowQueryThresholdMs — запросы фото и пользователей к Postgres дольше этого порога
+	// пишутся в лог с SQL и параметрами. 0 — не писать
+	DBSlowQueryThresholdMs int64 `env:"DB_SLOW_QUERY_THRESHOLD_MS" envDefault:"500"`
+
+	// Внешний источник фото: unsplash (по умолчанию), pixabay, pexels или fake — встроенный набор фото
+	// для разработки без ключей и сети. Ключ API обязателен только для выбранного источника
+	PhotoSource string `env:"PHOTO_SOURCE" envDefault:"unsplash"`
+	// PhotoProvider — то же, что PhotoSource; если задан, важнее PHOTO_SOURCE
+	PhotoProvider string `env:"PHOTO_PROVIDER"`
+	// PhotoSources — цепочка источников через запятую, например "unsplash,pixabay": если источник
+	// недоступен, запрос уходит в следующий. Пустая цепочка — только PHOTO_SOURCE
+	PhotoSources   []string `env:"PHOTO_SOURCES" envSeparator:","`
+	UnsplashAPIKey string   `env:"UNSPLASH_API_KEY"`
+	PixabayAPIKey  string   `env:"PIXABAY_API_KEY"`
+	PexelsAPIKey   string   `env:"PEXELS_API_KEY"`
+	// PhotoSourcesMode — как работают несколько источников из PHOTO_SOURCES: fallback — по порядку,
+	// следующий только если текущий недоступен; aggregate — поиск и новые фото запрашиваются
+	// у всех источников сразу, а результаты перемежаются
+	PhotoSourcesMode string `env:"PHOTO_SOURCES_MODE" envDefault:"fallback"`
+	// Источник из PHOTO_SOURCES, недоступный PhotoSourcesBreakerFailures раз подряд, пропускается
+	// PhotoSourcesBreakerCooldown, а затем получает один пробный запрос. 0 ошибок — не отключать
+	PhotoSourcesBreakerFailures int           `env:"PHOTO_SOURCES_BREAKER_FAILURES" envDefault:"5"`
+	PhotoSourcesBreakerCooldown time.Duration `env:"PHOTO_SOURCES_BREAKER_COOLDOWN" envDefault:"30s"`
+
+	// Повторы запросов к Unsplash при сетевых ошибках и ответах 5xx: всего до UnsplashMaxAttempts попыток,
+	// пауза от UnsplashRetryBaseDelay удваивается после каждой неудачи (плюс случайный разброс)
+	UnsplashMaxAttempts    int           `env:"UNSPLASH_MAX_ATTEMPTS" envDefault:"3"`
+	UnsplashRetryBaseDelay time.Duration `env:"UNSPLASH_RETRY_BASE_DELAY" envDefault:"500ms"`
+
+	// Подключение к Unsplash: адрес API (можно указать мок-сервер или кеширующий прокси),
+	// таймаут одного HTTP-запроса и необязательный HTTP-прокси для исходящих запросов
+	UnsplashBaseURL     string        `env:"UNSPLASH_BASE_URL" envDefault:"https://api.unsplash.com"`
+	UnsplashHTTPTimeout time.Duration `env:"UNSPLASH_HTTP_TIMEOUT" envDefault:"10s"`
+	UnsplashProxyURL    string        `env:"UNSPLASH_PROXY_URL"`
+
+	// Кеш ответов Unsplash в памяти процесса: фото по ID и страницы поиска хранятся UnsplashCacheTTL,
+	// ответ «фото не найдено» — UnsplashCacheNegativeTTL, всего не больше UnsplashCacheMaxEntries ответов
+	// (самые давно запрошенные вытесняются). Нулевой UNSPLASH_CACHE_TTL или размер отключают кеш,
+	// нулевой UNSPLASH_CACHE_NEGATIVE_TTL — кеширование 404
+	UnsplashCacheTTL         time.Duration `env:"UNSPLASH_CACHE_TTL" envDefault:"60s"`
+	UnsplashCacheNegativeTTL time.Duration `env:"UNSPLASH_CACHE_NEGATIVE_TTL" envDefault:"10s"`
+	UnsplashCacheMaxEntries  int           `env:"UNSPLASH_CACHE_MAX_ENTRIES" envDefault:"1000"`
+
+	// Таймауты шагов обработки фото в usecase: запрос к API источника (поиск, фото по ID, статистика),
+	// скачивание оригинала и загрузка в S3. Каждый шаг ограничен отдельно, чтобы медленный источник
+	// или S3 не съедали весь REQUEST_TIMEOUT. 0 — без собственного ограничения
+	ExternalFetchTimeout time.Duration `env:"EXTERNAL_FETCH_TIMEOUT" envDefault:"8s"`
+	PhotoDownloadTimeout time.Duration `env:"PHOTO_DOWNLOAD_TIMEOUT" envDefault:"10s"`
+	S3UploadTimeout      time.Duration `env:"S3_UPLOAD_TIMEOUT" envDefault:"10s"`
+
+	// AuthorImportMaxPhotos — сколько фото автора Unsplash импортирует одна задача
+	// POST /admin/authors/{username}/import; 0 — без ограничения, до последней страницы
+	AuthorImportMaxPhotos int `env:"AUTHOR_IMPORT_MAX_PHOTOS" envDefault:"300"`
+
+	// Сервис эмбеддингов изображений для поиска похожих фото: POST {"image_url": ...} → {"embedding": [...]}.
+	// Пустой адрес — эмбеддинги не вычисляются и похожие фото не ищутся
+	EmbeddingServiceURL string        `env:"EMBEDDING_SERVICE_URL"`
+	EmbeddingTimeout    time.Duration `env:"EMBEDDING_TIMEOUT" envDefault:"30s"`
+
+	// Настройки для MinIO (обязательные проверяются в Validate). MinioEndpoint — host:port без схемы,
+	// протокол задаёт MinioUseSSL
+	MinioEndpoint        string `env:"MINIO_ENDPOINT"`
+	MinioAccessKeyID     string `env:"MINIO_ACCESS_KEY_ID"`
+	MinioSecretAccessKey string `env:"MINIO_SECRET_ACCESS_KEY"`
 	MinioUseSSL          bool   `env:"MINIO_USE_SSL"`
-	MinioBucketName      string `env:"MINIO_BUCKET_NAME,required"`
+	MinioBucketName      string `env:"MINIO_BUCKET_NAME"`
+
+	MinioRegion string `env:"MINIO_REGION"`
 
-	MinioRegion string `env:"MINIO_REGION,required"`
+	// MinioBootstrapTimeout ограничивает проверку/создание бакета при старте
+	MinioBootstrapTimeout time.Duration `env:"MINIO_BOOTSTRAP_TIMEOUT" envDefault:"30s"`
 
+	// Настройки JWT для аутентификации пользователей
+	JWTSecret   string        `env:"JWT_SECRET"`
+	JWTTokenTTL time.Duration `env:"JWT_TOKEN_TTL" envDefault:"24h"`
+
+	// Трассировка OpenTelemetry: без OTLP endpoint трассировка выключена
+	OTLPEndpoint    string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OTelServiceName string `env:"OTEL_SERVICE_NAME" envDefault:"mediaapp"`
+
+	// Уровень логов: debug, info, warn или error; формат: json или text
 	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`
 	LogFormat string `env:"LOG_FORMAT" envDefault:"json"`
 
+	// Вывод логов: stdout (по умолчанию) или file — файл LogFilePath с ротацией по размеру
+	LogOutput         string `env:"LOG_OUTPUT" envDefault:"stdout"`
+	LogFilePath       string `env:"LOG_FILE_PATH"`
+	LogFileMaxSizeMB  int    `env:"LOG_FILE_MAX_SIZE_MB" envDefault:"100"`
+	LogFileMaxBackups int    `env:"LOG_FILE_MAX_BACKUPS" envDefault:"5"`
+	LogFileMaxAgeDays int    `env:"LOG_FILE_MAX_AGE_DAYS" envDefault:"30"`
+	LogFileCompress   bool   `env:"LOG_FILE_COMPRESS" envDefault:"false"`
+
+	// Брокер сообщений для задач воркера: rabbitmq (по умолчанию) или kafka
+	MessageBroker string `env:"MESSAGE_BROKER" envDefault:"rabbitmq"`
+
 	RabbitMQ struct {
-		RabbitMQURL       string `env:"RABBITMQ_URL,required"`
+		RabbitMQURL       string `env:"RABBITMQ_URL"`
 		RabbitMQQueueName string `env:"RABBITMQ_QUEUE_NAME" envDefault:"photo_search_queue"`
+		// RabbitMQEmbeddingQueueName — отдельная очередь задач вычисления эмбеддингов фото
+		RabbitMQEmbeddingQueueName string `env:"RABBITMQ_EMBEDDING_QUEUE_NAME" envDefault:"photo_embeddings"`
+
+		// HTTP API плагина management для GET /admin/queues (пустой URL — мониторинг очередей выключен).
+		// Virtual host берётся из RABBITMQ_URL
+		RabbitMQManagementURL      string `env:"RABBITMQ_MANAGEMENT_URL" envDefault:"http://localhost:15672"`
+		RabbitMQManagementUser     string `env:"RABBITMQ_MANAGEMENT_USER" envDefault:"guest"`
+		RabbitMQManagementPassword string `env:"RABBITMQ_MANAGEMENT_PASSWORD" envDefault:"guest"`
+		// RabbitMQDeadLetterQueue — очередь, куда брокер перекладывает отклонённые сообщения
+		// (настраивается политикой dead-letter в RabbitMQ); пусто — глубина DLQ не показывается
+		RabbitMQDeadLetterQueue string `env:"RABBITMQ_DLQ_NAME"`
+	}
+
+	Kafka struct {
+		BootstrapServers []string `env:"KAFKA_BOOTSTRAP_SERVERS" envSeparator:","`
+		TopicName        string   `env:"KAFKA_TOPIC_NAME" envDefault:"photo_search"`
+		// ConsumerGroup — все воркеры одной группы делят сообщения топика между собой
+		ConsumerGroup string `env:"KAFKA_CONSUMER_GROUP" envDefault:"mediaapp-worker"`
 	}
+
+	// Очистка файлов в S3 без записи в БД (выполняется воркером, 0 — отключена)
+	OrphanCleanupInterval time.Duration `env:"ORPHAN_CLEANUP_INTERVAL" envDefault:"0"`
+	OrphanMinAge          time.Duration `env:"ORPHAN_MIN_AGE" envDefault:"1h"`
+
+	// Окончательное удаление фото из корзины (выполняется воркером, 0 дней — отключено)
+	PhotoRetentionDays int           `env:"PHOTO_RETENTION_DAYS" envDefault:"0"`
+	PhotoPurgeInterval time.Duration `env:"PHOTO_PURGE_INTERVAL" envDefault:"24h"`
+
+	// PopularityUpdateInterval — как часто воркер пересчитывает popularity_score фото (0 — не пересчитывать)
+	PopularityUpdateInterval time.Duration `env:"POPULARITY_UPDATE_INTERVAL" envDefault:"15m"`
+
+	// Обновление просмотров и скачиваний из статистики Unsplash (выполняется воркером, 0 — отключено):
+	// каждые StatsRefreshInterval для StatsRefreshLimit фото, просмотренных последними,
+	// с паузой StatsRefreshSpacing между запросами к Unsplash
+	StatsRefreshInterval time.Duration `env:"STATS_REFRESH_INTERVAL" envDefault:"6h"`
+	StatsRefreshLimit    int           `env:"STATS_REFRESH_LIMIT" envDefault:"20"`
+	StatsRefreshSpacing  time.Duration `env:"STATS_REFRESH_SPACING" envDefault:"2s"`
+
+	// Кеш фото в Redis (пустой REDIS_URL — кеш выключен)
+	RedisURL      string        `env:"REDIS_URL"`
+	PhotoCacheTTL time.Duration `env:"PHOTO_CACHE_TTL" envDefault:"10m"`
+
+	// APIKeys — ключи для изменяющих маршрутов без JWT (поиск с сохранением, удаление, обновление фото)
+	// и для загрузок фото, где ключ нужен вместе с JWT, через запятую. Несколько ключей — для ротации; пустой список отключает проверку
+	APIKeys []string `env:"API_KEYS" envSeparator:","`
+
+	// Ограничение частоты запросов на клиента (API-ключ или IP): RateLimitRPS в секунду в среднем
+	// и до RateLimitBurst подряд. Корзины клиентов, простаивающих RateLimitIdleTTL, удаляются. 0 RPS — без ограничения
+	RateLimitRPS     float64       `env:"RATE_LIMIT_RPS" envDefault:"10"`
+	RateLimitBurst   int           `env:"RATE_LIMIT_BURST" envDefault:"20"`
+	RateLimitIdleTTL time.Duration `env:"RATE_LIMIT_IDLE_TTL" envDefault:"10m"`
+
+	// AdminAPIKey — ключ для служебных маршрутов мониторинга (/admin/queues) в заголовке X-Admin-API-Key,
+	// чтобы скрипты мониторинга обходились без сессии пользователя. Пустой ключ закрывает эти маршруты
+	AdminAPIKey string `env:"ADMIN_API_KEY"`
+
+	// AdminUserIDs — пользователи с доступом к /admin/* (через запятую)
+	AdminUserIDs []uuid.UUID `env:"ADMIN_USER_IDS" envSeparator:","`
+
+	// AuditBufferSize — сколько событий аудита может ждать фоновой записи; лишние отбрасываются
+	AuditBufferSize int `env:"AUDIT_BUFFER_SIZE" envDefault:"1000"`
+
+	// MaxUploadSizeMB — максимальный размер фото, загружаемого пользователем
+	MaxUploadSizeMB int `env:"MAX_UPLOAD_SIZE_MB" envDefault:"10"`
+
+	// MaxRequestBodyBytes — предел тела любого запроса, включая загрузку фото; больший запрос получает 413
+	MaxRequestBodyBytes int64 `env:"MAX_REQUEST_BODY_BYTES" envDefault:"52428800"`
+
+	// ColorMatchDistance — максимальное евклидово расстояние в RGB (0..441),
+	// при котором цвет палитры фото считается совпавшим при поиске ?color=RRGGBB
+	ColorMatchDistance float64 `env:"COLOR_MATCH_DISTANCE" envDefault:"40"`
+
+	// MaxPerPage — верхний предел per_page для списков; большие значения урезаются до него
+	MaxPerPage int `env:"MAX_PER_PAGE" envDefault:"100"`
+
+	// ViewsFlushInterval — как часто накопленные в памяти просмотры фото записываются в бд
+	ViewsFlushInterval time.Duration `env:"VIEWS_FLUSH_INTERVAL" envDefault:"5s"`
+
+	// Ключи идемпотентности (X-Idempotency-Key): сколько хранится ответ и как часто воркер удаляет истёкшие
+	IdempotencyKeyTTLHours     int           `env:"IDEMPOTENCY_KEY_TTL_HOURS" envDefault:"24"`
+	IdempotencyCleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" envDefault:"1h"`
+
+	// Загрузка фото напрямую в S3 (POST /photos/upload-intent): сколько действует подписанная ссылка
+	// и как часто воркер удаляет брошенные загрузки с истёкшей ссылкой (0 — не удалять)
+	UploadIntentTTL             time.Duration `env:"UPLOAD_INTENT_TTL" envDefault:"15m"`
+	UploadIntentCleanupInterval time.Duration `env:"UPLOAD_INTENT_CLEANUP_INTERVAL" envDefault:"1h"`
+
+	// Вебхуки: таймаут одного POST, число попыток доставки и пауза перед первым повтором
+	// (удваивается после каждой неудачи)
+	WebhookTimeout        time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	WebhookMaxAttempts    int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	WebhookRetryBaseDelay time.Duration `env:"WEBHOOK_RETRY_BASE_DELAY" envDefault:"1s"`
+
+	// WorkerMaxMessages — сколько сообщений воркер обработает перед завершением (0 — без ограничения)
+	WorkerMaxMessages int `env:"WORKER_MAX_MESSAGES" envDefault:"0"`
+}
+
+// DBConnMaxLifetime возвращает DBConnMaxLifetimeMinutes длительностью для sql.DB.SetConnMaxLifetime
+func (c *Config) DBConnMaxLifetime() time.Duration {
+	return time.Duration(c.DBConnMaxLifetimeMinutes) * time.Minute
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения
@@ -56,9 +272,17 @@ func LoadConfig() (*Config, error) {
 	if cfg.ServerPort == "" {
 		cfg.ServerPort = "8080"
 	}
-
+	if cfg.PhotoProvider != "" {
+		cfg.PhotoSource = cfg.PhotoProvider
+	}
+	if len(cfg.PhotoSources) == 0 {
+		cfg.PhotoSources = []string{cfg.PhotoSource}
+	}
 	cfg.MaxConcurrentUploads = 5
-	cfg.RequestTimeout = 30 * time.Second
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("некорректная конфигурация:\n%w", err)
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// AuditStorage определяет методы для работы с журналом аудита
+type AuditStorage interface {
+	// RecordAuditEvent сохраняет событие аудита
+	RecordAuditEvent(ctx context.Context, event *domain.AuditLog) error
+	// ListAuditEvents возвращает последние limit событий по типу сущности (пустой — по всем), новые первыми
+	ListAuditEvents(ctx context.Context, entityType string, limit int) ([]domain.AuditLog, error)
+}
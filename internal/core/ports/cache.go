@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCacheMiss возвращается из Cache.Get, если ключа нет в кеше
+var ErrCacheMiss = errors.New("ключ не найден в кеше")
+
+// Cache определяет методы для работы с кешем ключ-значение (например, Redis)
+type Cache interface {
+	// Get возвращает значение по ключу или ErrCacheMiss, если ключа нет
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set сохраняет значение на время ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Del удаляет ключи; отсутствующие ключи ошибкой не считаются
+	Del(ctx context.Context, keys ...string) error
+}
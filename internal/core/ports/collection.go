@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CollectionStorage определяет методы для работы с подборками фото и их составом
+type CollectionStorage interface {
+	CreateCollection(ctx context.Context, collection *domain.Collection) error
+	// GetCollectionByID возвращает domain.ErrCollectionNotFound, если подборки нет
+	GetCollectionByID(ctx context.Context, id uuid.UUID) (*domain.Collection, error)
+	// ListCollectionsByUser возвращает страницу подборок пользователя, новые первыми
+	ListCollectionsByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Collection, error)
+	CountCollectionsByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	// UpdateCollection обновляет только заданные поля; domain.ErrCollectionNotFound, если подборки нет
+	UpdateCollection(ctx context.Context, id uuid.UUID, updates domain.CollectionUpdate) error
+	// DeleteCollection удаляет подборку вместе со связями, но не сами фото
+	DeleteCollection(ctx context.Context, id uuid.UUID) error
+
+	// AddPhotoToCollection добавляет фото в подборку; повторное добавление ничего не меняет
+	// и возвращает added == false
+	AddPhotoToCollection(ctx context.Context, collectionID, photoID uuid.UUID) (added bool, err error)
+	// RemovePhotoFromCollection убирает фото из подборки; отсутствие фото в подборке не ошибка
+	RemovePhotoFromCollection(ctx context.Context, collectionID, photoID uuid.UUID) error
+	// ListCollectionPhotos возвращает страницу неудалённых фото подборки, последние добавленные первыми
+	ListCollectionPhotos(ctx context.Context, collectionID uuid.UUID, page, perPage int) ([]domain.Photo, error)
+	CountCollectionPhotos(ctx context.Context, collectionID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// IdempotencyStorage хранит ответы на запросы с ключом идемпотентности,
+// чтобы повтор запроса после таймаута не выполнял его второй раз
+type IdempotencyStorage interface {
+	// ReserveIdempotencyKey занимает ключ за текущим запросом и возвращает nil.
+	// Если ключ уже занят записью не старше expiresBefore, возвращает её (возможно, ещё без ответа),
+	// а более старая запись считается истёкшей и занимается заново
+	ReserveIdempotencyKey(ctx context.Context, key string, expiresBefore time.Time) (*domain.IdempotencyRecord, error)
+	// SaveIdempotentResponse сохраняет ответ для занятого ключа
+	SaveIdempotentResponse(ctx context.Context, key string, statusCode int, response []byte) error
+	// ReleaseIdempotencyKey освобождает ключ, если запрос не удался, чтобы клиент мог его повторить
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	// DeleteExpiredIdempotencyKeys удаляет записи старше olderThan и возвращает их число
+	DeleteExpiredIdempotencyKeys(ctx context.Context, olderThan time.Time) (int64, error)
+}
@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 
+	"github.com/GoArmGo/MediaApp/internal/domain"
 	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
 )
 
@@ -19,3 +20,20 @@ type PhotoSearchConsumer interface {
 	// принимает функцию-обработчик, которая будет вызываться для каждого полученного сообщения
 	StartConsumingPhotoSearchRequests(ctx context.Context, handler func(context.Context, payloads.PhotoSearchPayload) error) error
 }
+
+// EmbeddingPublisher публикует задачи вычисления эмбеддингов фото в отдельную очередь
+type EmbeddingPublisher interface {
+	PublishEmbeddingRequest(ctx context.Context, payload payloads.EmbeddingPayload) error
+}
+
+// EmbeddingConsumer потребляет задачи вычисления эмбеддингов фото.
+// Ошибка обработчика возвращает сообщение в очередь
+type EmbeddingConsumer interface {
+	StartConsumingEmbeddingRequests(ctx context.Context, handler func(context.Context, payloads.EmbeddingPayload) error) error
+}
+
+// QueueStatsProvider отдаёт состояние очередей брокера для мониторинга.
+// Если очереди нет, ошибка оборачивает domain.ErrQueueNotFound
+type QueueStatsProvider interface {
+	GetQueueStats(ctx context.Context, queueName string) (*domain.QueueStats, error)
+}
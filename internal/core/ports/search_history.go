@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// SearchHistoryStorage определяет методы для работы с историей поисковых запросов
+type SearchHistoryStorage interface {
+	// RecordSearchQuery сохраняет выполненный поиск
+	RecordSearchQuery(ctx context.Context, query *domain.SearchQuery) error
+	// ListSearchQueries возвращает страницу истории, последние запросы первыми
+	ListSearchQueries(ctx context.Context, page, perPage int) ([]domain.SearchQuery, error)
+	CountSearchQueries(ctx context.Context) (int64, error)
+	// SuggestSearchQueries возвращает до limit запросов, начинающихся с prefix (без учёта регистра),
+	// самые частые первыми
+	SuggestSearchQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error)
+}
@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SeriesStorage определяет методы для работы с сериями фото и порядком фото в них.
+// Позиции фото в серии идут подряд с нуля; фото в корзине сохраняют своё место, а окончательно
+// удалённые оставляют пропуск до следующей перестановки
+type SeriesStorage interface {
+	CreateSeries(ctx context.Context, series *domain.PhotoSeries) error
+	// GetSeriesByID возвращает domain.ErrSeriesNotFound, если серии нет
+	GetSeriesByID(ctx context.Context, id uuid.UUID) (*domain.PhotoSeries, error)
+	// ListSeriesByUser возвращает страницу серий пользователя, новые первыми
+	ListSeriesByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.PhotoSeries, error)
+	CountSeriesByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	// UpdateSeries обновляет только заданные поля; domain.ErrSeriesNotFound, если серии нет
+	UpdateSeries(ctx context.Context, id uuid.UUID, updates domain.PhotoSeriesUpdate) error
+	// DeleteSeries удаляет серию вместе со связями, но не сами фото
+	DeleteSeries(ctx context.Context, id uuid.UUID) error
+
+	// AddPhotoToSeries вставляет фото в серию на позицию position, сдвигая следующие фото.
+	// position < 0 или за последним фото серии — в конец. Повторное добавление ничего
+	// не меняет и возвращает added == false
+	AddPhotoToSeries(ctx context.Context, seriesID, photoID uuid.UUID, position int) (added bool, err error)
+	// RemovePhotoFromSeries убирает фото из серии и сдвигает следующие; отсутствие фото в серии не ошибка
+	RemovePhotoFromSeries(ctx context.Context, seriesID, photoID uuid.UUID) error
+	// ReorderSeriesPhotos одной транзакцией расставляет фото серии в порядке photoIDs.
+	// photoIDs должен содержать каждое неудалённое фото серии ровно один раз, иначе
+	// domain.ErrInvalidSeriesOrder; фото из корзины переносятся в конец в прежнем порядке
+	ReorderSeriesPhotos(ctx context.Context, seriesID uuid.UUID, photoIDs []uuid.UUID) error
+	// ListPhotosInSeries возвращает страницу неудалённых фото серии в порядке серии
+	ListPhotosInSeries(ctx context.Context, seriesID uuid.UUID, page, perPage int) ([]domain.Photo, error)
+	CountPhotosInSeries(ctx context.Context, seriesID uuid.UUID) (int64, error)
+}
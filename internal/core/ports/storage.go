@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/domain"
 	"github.com/google/uuid"
@@ -9,15 +10,148 @@ import (
 
 // PhotoStorage определяет методы для взаимодействия с хранилищем фотографий
 type PhotoStorage interface {
-	SavePhoto(ctx context.Context, photo *domain.Photo) error
+	// SavePhoto сохраняет фото и сообщает, создана ли новая запись. Если фото с таким unsplash_id
+	// уже есть, photo заполняется сохранённой строкой (у фото в корзине заполнен DeletedAt)
+	SavePhoto(ctx context.Context, photo *domain.Photo) (created bool, err error)
+	// SavePhotoTx сохраняет фото и его теги атомарно: либо всё, либо ничего. Если фото с таким
+	// unsplash_id уже есть, created = false, а photo заполняется сохранённой строкой с её тегами, как в SavePhoto
+	SavePhotoTx(ctx context.Context, photo *domain.Photo) (created bool, err error)
+	// SavePhotos сохраняет пачку фото с тегами в одной транзакции многострочными INSERT.
+	// Возвращаются ID реально вставленных; фото с уже существующим unsplash_id не вставляются,
+	// а заполняются сохранённой строкой, как в SavePhoto
+	SavePhotos(ctx context.Context, photos []*domain.Photo) ([]uuid.UUID, error)
+	// UpsertPhoto сохраняет фото или обновляет изменяемые метаданные уже существующего по unsplash_id
+	UpsertPhoto(ctx context.Context, photo *domain.Photo) error
 	GetPhotoByIDFromDB(ctx context.Context, id uuid.UUID) (*domain.Photo, error)
+	// GetPhotoByIDIncludingDeleted — как GetPhotoByIDFromDB, но находит и фото в корзине (DeletedAt заполнено).
+	// Если фото нет, возвращает nil, nil
+	GetPhotoByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.Photo, error)
 	GetPhotosByUnsplashIDFromDB(ctx context.Context, unsplashID string) (*domain.Photo, error)
-	SearchPhotosInDB(ctx context.Context, query string, page, perPage int) ([]domain.Photo, error)
+	// GetPhotosByIDs возвращает найденные фото из переданного списка (без фото в корзине), порядок не гарантирован
+	GetPhotosByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Photo, error)
+	// ExistsByUnsplashIDs возвращает unsplash_id -> id для уже сохранённых фото из переданного списка
+	ExistsByUnsplashIDs(ctx context.Context, unsplashIDs []string) (map[string]uuid.UUID, error)
+	GetPhotosByUnsplashIDsFromDB(ctx context.Context, unsplashIDs []string) ([]domain.Photo, error)
+	// FindPhotosByUnsplashIDs возвращает сохранённые фото из переданного списка вместе с фото
+	// в корзине (у них заполнен DeletedAt): по ним видно, что загружать фото повторно не нужно
+	FindPhotosByUnsplashIDs(ctx context.Context, unsplashIDs []string) ([]domain.Photo, error)
+	// SearchPhotosInDB ищет по названию, описанию и автору (с заполненным Rank);
+	// filter дополнительно ограничивает лайки и размер (нулевой — без ограничений).
+	// Нулевой sort — самые релевантные первыми
+	SearchPhotosInDB(ctx context.Context, query string, page, perPage int, filter domain.PhotoSearchFilter, sort domain.PhotoSort) ([]domain.Photo, error)
+	// CountSearchResults считает все фото, подходящие под SearchPhotosInDB с теми же фильтрами
+	CountSearchResults(ctx context.Context, query string, filter domain.PhotoSearchFilter) (int64, error)
+	// SearchPhotosByColor ищет фото, в палитре которых есть цвет не дальше maxDistance от color
+	// (евклидово расстояние в RGB); самые близкие первыми. color — "#rrggbb"
+	SearchPhotosByColor(ctx context.Context, color string, maxDistance float64, page, perPage int) ([]domain.Photo, error)
+	// CountPhotosByColor считает все фото, подходящие под SearchPhotosByColor
+	CountPhotosByColor(ctx context.Context, color string, maxDistance float64) (int64, error)
+	// FindPhotosNear ищет фото с координатами не дальше radiusKm от точки (lat, lon), ближайшие первыми
+	FindPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64, page, perPage int) ([]domain.Photo, error)
+	// CountPhotosNear считает все фото, подходящие под FindPhotosNear
+	CountPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64) (int64, error)
+	// UpdatePhotoEmbedding сохраняет эмбеддинг фото; фото в корзине не обновляется (domain.ErrPhotoNotFound)
+	UpdatePhotoEmbedding(ctx context.Context, id uuid.UUID, vector []float32) error
+	// GetPhotoEmbedding возвращает эмбеддинг фото вне корзины или nil, если он ещё не вычислен.
+	// Если фото нет, возвращает domain.ErrPhotoNotFound
+	GetPhotoEmbedding(ctx context.Context, id uuid.UUID) ([]float32, error)
+	// FindSimilarPhotos возвращает до limit фото с эмбеддингом, ближайших к vector, кроме excludeID
+	FindSimilarPhotos(ctx context.Context, vector []float32, excludeID uuid.UUID, limit int) ([]domain.Photo, error)
+	// AutocompletePhotoTitles возвращает до limit различных title и author_name, начинающихся
+	// с prefix (без учёта регистра), в алфавитном порядке
+	AutocompletePhotoTitles(ctx context.Context, prefix string, limit int) ([]string, error)
+	// GetTagCloud возвращает до limit тегов с числом фото вне корзины, самые частые первыми.
+	// Теги, у которых остались только фото в корзине, не попадают в выдачу
+	GetTagCloud(ctx context.Context, limit int) ([]domain.TagCount, error)
+	// SearchTagsByPrefix возвращает до limit тегов, начинающихся с prefix (без учёта регистра),
+	// в алфавитном порядке
+	SearchTagsByPrefix(ctx context.Context, prefix string, limit int) ([]domain.Tag, error)
+	// BatchUpdateTags в одной транзакции снимает с фото из photoIDs теги removeTags и добавляет addTags
+	// (имена приводятся к нижнему регистру, отсутствующие теги создаются). Снятие выполняется первым,
+	// поэтому тег из обоих списков у фото остаётся. Фото в корзине пропускаются.
+	// Возвращает число изменённых фото
+	BatchUpdateTags(ctx context.Context, photoIDs []uuid.UUID, addTags, removeTags []string) (int64, error)
+	// CountPhotosInDB считает фото, не находящиеся в корзине
+	CountPhotosInDB(ctx context.Context) (int64, error)
 	ListAllPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error)
-	ListPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error)
+	// ListPhotosInDB — страница фото в порядке sort; нулевой sort — новые первыми (created_at DESC)
+	ListPhotosInDB(ctx context.Context, page, perPage int, sort domain.PhotoSort) ([]domain.Photo, error)
+	// ListPhotosWithTagsInDB — как ListPhotosInDB, но с тегами, загруженными одним запросом на страницу
+	ListPhotosWithTagsInDB(ctx context.Context, page, perPage int, sort domain.PhotoSort) ([]domain.Photo, error)
+	// StreamPhotosFromDB отправляет в out фото из выборки filter вместе с тегами, старые первыми,
+	// читая строки курсором, а не загружая выборку в память. out не закрывается;
+	// при отмене ctx возвращается его ошибка
+	StreamPhotosFromDB(ctx context.Context, filter domain.PhotoFilter, out chan<- domain.Photo) error
+	// ListPhotosByAuthor — фото автора (author_name без учёта регистра), новые первыми
+	ListPhotosByAuthor(ctx context.Context, authorName string, page, perPage int) ([]domain.Photo, error)
+	CountPhotosByAuthor(ctx context.Context, authorName string) (int64, error)
+	// ListPhotosByUser — фото, сохранённые пользователем (user_id), новые первыми
+	ListPhotosByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, error)
+	CountPhotosByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// Локальные счётчики просмотров и скачиваний: увеличиваются одним UPDATE без чтения,
+	// поэтому параллельные запросы не теряют инкременты. Для фото в корзине — domain.ErrPhotoNotFound
+	IncrementViewsCount(ctx context.Context, id uuid.UUID) error
+	IncrementDownloadsCount(ctx context.Context, id uuid.UUID) error
+	// AddViews прибавляет к views_count накопленные просмотры (id фото -> сколько прибавить).
+	// updated_at не меняется; фото в корзине и неизвестные ID пропускаются без ошибки
+	AddViews(ctx context.Context, views map[uuid.UUID]int64) error
+
+	// UpdatePopularityScores пересчитывает popularity_score всех фото вне корзины одним UPDATE
+	// по формуле domain.PopularityScore и возвращает число обновлённых строк
+	UpdatePopularityScores(ctx context.Context) (int64, error)
+	// ListTrendingPhotos — до limit неудалённых фото с наибольшим popularity_score
+	ListTrendingPhotos(ctx context.Context, limit int) ([]domain.Photo, error)
+	// ListRecentlyViewedPhotos — до limit неудалённых фото источника source, просмотренных последними.
+	// Фото, которые ни разу не просматривали, не попадают в выдачу
+	ListRecentlyViewedPhotos(ctx context.Context, source string, limit int) ([]domain.Photo, error)
+
+	// Избранное — локальные лайки пользователей, не связанные с likes_count из внешнего источника.
+	// LikePhoto для несуществующего фото или фото в корзине — domain.ErrPhotoNotFound;
+	// повторный лайк ничего не меняет и возвращает added == false. UnlikePhoto без лайка — не ошибка
+	LikePhoto(ctx context.Context, photoID, userID uuid.UUID) (added bool, err error)
+	UnlikePhoto(ctx context.Context, photoID, userID uuid.UUID) error
+	// ListLikedPhotos — неудалённые фото из избранного пользователя, последние лайки первыми
+	ListLikedPhotos(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, error)
+	CountLikedPhotos(ctx context.Context, userID uuid.UUID) (int64, error)
+	// GetPhotoLikes возвращает число лайков фото и есть ли среди них лайк userID (uuid.Nil — аноним)
+	GetPhotoLikes(ctx context.Context, photoID, userID uuid.UUID) (domain.PhotoLikes, error)
+
+	// Мягкое удаление: удалённые фото исключаются из get/list/search, но остаются в бд до очистки.
+	// SetPhotoDeletedAt с непустым deletedAt перемещает фото в корзину, с nil — восстанавливает его.
+	// Если фото нет или оно уже в нужном состоянии (удаляется удалённое, восстанавливается не удалённое),
+	// возвращает domain.ErrPhotoNotFound
+	SetPhotoDeletedAt(ctx context.Context, id uuid.UUID, deletedAt *time.Time) error
+	ListDeletedPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error)
+	CountDeletedPhotosInDB(ctx context.Context) (int64, error)
+	PurgeDeletedPhotos(ctx context.Context, olderThan time.Time) ([]domain.Photo, error)
+
+	// Ожидающие загрузки напрямую в S3: запись создаётся до загрузки файла клиентом вместе с фото
+	// в статусе pending и удаляется при подтверждении загрузки или очисткой. GetUploadIntent
+	// для отсутствующей записи возвращает domain.ErrUploadIntentNotFound
+	CreateUploadIntent(ctx context.Context, intent *domain.UploadIntent, pending *domain.Photo) error
+	GetUploadIntent(ctx context.Context, id uuid.UUID) (*domain.UploadIntent, error)
+	// ActivateUploadedPhoto заполняет pending-фото загрузки photo.ID данными файла, переводит его в active
+	// и удаляет запись загрузки. Из параллельных вызовов с одним ID фото активирует только один,
+	// остальные (и вызов для фото не в статусе pending) получают domain.ErrUploadIntentNotFound
+	ActivateUploadedPhoto(ctx context.Context, photo *domain.Photo) error
+	// DeleteExpiredUploadIntents удаляет загрузки, ссылка которых истекла раньше before, и их pending-фото
+	// и возвращает загрузки, чтобы вызывающий код удалил уже загруженные файлы
+	DeleteExpiredUploadIntents(ctx context.Context, before time.Time) ([]domain.UploadIntent, error)
 }
 
 // UserStorage определяет методы для взаимодействия с хранилищем пользователей
 type UserStorage interface {
 	GetOrCreateSystemUser(ctx context.Context) (uuid.UUID, error)
+	CreateUser(ctx context.Context, user *domain.User) error
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, updates domain.UserUpdate) error
+}
+
+// SchemaVersionReader отдаёт версию схемы бд для мониторинга и проверки готовности
+type SchemaVersionReader interface {
+	// SchemaVersion возвращает номер последней применённой миграции (0 — ни одной) и признак dirty:
+	// миграция упала на середине и схема требует ручного исправления
+	SchemaVersion(ctx context.Context) (version uint, dirty bool, err error)
 }
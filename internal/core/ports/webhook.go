@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// WebhookStorage определяет методы для работы с вебхуками пользователей и журналом их доставки
+type WebhookStorage interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) error
+	// ListWebhooksByUserID возвращает вебхуки пользователя, новые первыми
+	ListWebhooksByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error)
+	// GetWebhookByID возвращает domain.ErrWebhookNotFound, если вебхука нет
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	// DeleteWebhook удаляет вебхук вместе с журналом доставки; domain.ErrWebhookNotFound, если вебхука нет
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+
+	// ListActiveWebhooksByEvent возвращает активные вебхуки, подписанные на eventType
+	ListActiveWebhooksByEvent(ctx context.Context, eventType string) ([]domain.Webhook, error)
+	// RecordWebhookDelivery сохраняет попытку доставки события
+	RecordWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
@@ -1,12 +1,15 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/config"
+	applog "github.com/GoArmGo/MediaApp/internal/logger"
 	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // драйвер "postgres" для sqlx.Connect
 )
 
 // Client представляет клиент для взаимодействия с PostgreSQL
@@ -16,33 +19,102 @@ type Client struct {
 	logger *slog.Logger
 }
 
-// NewClient инициализирует новое подключение к PostgreSQL и применяет миграции
+// NewClient инициализирует новое подключение к PostgreSQL (одна попытка)
 func NewClient(cfg *config.Config, logger *slog.Logger) (*Client, error) {
 	start := time.Now()
 
-	db, err := sqlx.Connect("postgres", cfg.DatabaseURL)
+	db, err := open(context.Background(), cfg)
 	if err != nil {
-		logger.Error("failed to open PostgreSQL connection", "error", err)
-		return nil, fmt.Errorf("ошибка открытия соединения с БД: %w", err)
-	}
-
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(10)
-	db.SetConnMaxLifetime(5 * time.Minute)
-
-	if err = db.Ping(); err != nil {
-		logger.Error("failed to ping database", "error", err)
-		return nil, fmt.Errorf("не удалось подключиться к базе данных: %w", err)
+		logger.Error("failed to connect to PostgreSQL", "error", err)
+		return nil, err
 	}
 
 	logger.Info("PostgreSQL connection established successfully",
-		"dsn", cfg.DatabaseURL,
+		"dsn", applog.RedactURL(cfg.DatabaseURL),
+		"max_open_conns", cfg.DBMaxOpenConns,
+		"max_idle_conns", cfg.DBMaxIdleConns,
+		"conn_max_lifetime", cfg.DBConnMaxLifetime(),
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
 
 	return &Client{DB: db, logger: logger}, nil
 }
 
+// maxConnectDelay ограничивает паузу между попытками подключения
+const maxConnectDelay = 30 * time.Second
+
+// dialFunc открывает пул соединений и проверяет доступность базы; в тестах подменяется
+type dialFunc func(ctx context.Context, cfg *config.Config) (*sqlx.DB, error)
+
+// ConnectWithRetry подключается к PostgreSQL, делая до maxAttempts попыток.
+// Пауза между попытками начинается с baseDelay и удваивается (не больше maxConnectDelay):
+// при старте через Docker Compose база часто ещё не готова принимать соединения.
+// Отмена ctx (например, SIGINT во время старта) прерывает и попытку, и паузу между попытками
+func ConnectWithRetry(ctx context.Context, cfg *config.Config, logger *slog.Logger, maxAttempts int, baseDelay time.Duration) (*Client, error) {
+	return connectWithRetry(ctx, cfg, logger, maxAttempts, baseDelay, open)
+}
+
+func connectWithRetry(ctx context.Context, cfg *config.Config, logger *slog.Logger, maxAttempts int, baseDelay time.Duration, dial dialFunc) (*Client, error) {
+	start := time.Now()
+	maxAttempts = max(maxAttempts, 1)
+	delay := baseDelay
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var db *sqlx.DB
+		db, err = dial(ctx, cfg)
+		if err == nil {
+			logger.Info("PostgreSQL connection established successfully",
+				"dsn", applog.RedactURL(cfg.DatabaseURL),
+				"attempt", attempt,
+				"max_open_conns", cfg.DBMaxOpenConns,
+				"max_idle_conns", cfg.DBMaxIdleConns,
+				"conn_max_lifetime", cfg.DBConnMaxLifetime(),
+				"duration_ms", time.Since(start).Milliseconds(),
+			)
+			return &Client{DB: db, logger: logger}, nil
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+		logger.Warn("failed to connect to PostgreSQL, retrying",
+			"attempt", attempt,
+			"remaining_attempts", maxAttempts-attempt,
+			"retry_in", delay,
+			"error", err,
+		)
+		select {
+		case <-ctx.Done():
+			logger.Warn("connecting to PostgreSQL cancelled", "attempts", attempt, "error", ctx.Err())
+			return nil, fmt.Errorf("подключение к базе данных прервано после %d попыток: %w", attempt, ctx.Err())
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxConnectDelay)
+	}
+
+	logger.Error("failed to connect to PostgreSQL, giving up", "attempts", maxAttempts, "error", err)
+	return nil, fmt.Errorf("не удалось подключиться к базе данных за %d попыток: %w", maxAttempts, err)
+}
+
+// open открывает пул соединений и проверяет доступность базы
+func open(ctx context.Context, cfg *config.Config) (*sqlx.DB, error) {
+	db, err := sqlx.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия соединения с БД: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime())
+
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("не удалось подключиться к базе данных: %w", err)
+	}
+	return db, nil
+}
+
 func (c *Client) Close() error {
 	start := time.Now()
 	err := c.DB.Close()
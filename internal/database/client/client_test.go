@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestConnectWithRetry_PasswordNeverLogged(t *testing.T) {
+	const password = "s3cr3t-Pa55"
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cfg := &config.Config{
+		// на порту 1 никто не слушает: подключение сразу завершается ошибкой
+		DatabaseURL:    "postgres://app:" + password + "@127.0.0.1:1/media?sslmode=disable&connect_timeout=1",
+		DBMaxOpenConns: 1,
+	}
+
+	if _, err := ConnectWithRetry(context.Background(), cfg, logger, 2, time.Millisecond); err == nil {
+		t.Fatal("err = nil, want a connection error")
+	} else if strings.Contains(err.Error(), password) {
+		t.Errorf("error contains the password: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "retrying") || !strings.Contains(out, "giving up") {
+		t.Fatalf("log has no retry and failure records: %s", out)
+	}
+	if strings.Contains(out, password) {
+		t.Errorf("log contains the password: %s", out)
+	}
+}
+
+// flakyDialer не может подключиться первые failures раз, а затем возвращает пул без соединений
+type flakyDialer struct {
+	failures int
+	calls    int
+}
+
+func (d *flakyDialer) dial(context.Context, *config.Config) (*sqlx.DB, error) {
+	d.calls++
+	if d.calls <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+	// sqlx.Open не подключается к базе, пока пул не понадобится
+	return sqlx.Open("postgres", "postgres://app@127.0.0.1:1/media?sslmode=disable")
+}
+
+func TestConnectWithRetry_SucceedsAfterFailures(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dialer := &flakyDialer{failures: 3}
+
+	c, err := connectWithRetry(context.Background(), &config.Config{}, logger, 5, time.Millisecond, dialer.dial)
+	if err != nil {
+		t.Fatalf("connectWithRetry: %v", err)
+	}
+	defer c.Close()
+	if dialer.calls != 4 {
+		t.Errorf("dial calls = %d, want 4", dialer.calls)
+	}
+}
+
+func TestConnectWithRetry_CancelStopsWaiting(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dialer := &flakyDialer{failures: 100}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := connectWithRetry(ctx, &config.Config{}, logger, 5, time.Hour, dialer.dial)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("connectWithRetry returned after %s, want right after the cancellation", elapsed)
+	}
+	if dialer.calls != 1 {
+		t.Errorf("dial calls = %d, want 1", dialer.calls)
+	}
+}
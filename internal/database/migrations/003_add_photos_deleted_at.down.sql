@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_photos_deleted_at;
+
+ALTER TABLE photos DROP COLUMN IF EXISTS deleted_at;
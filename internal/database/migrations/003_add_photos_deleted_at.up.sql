@@ -0,0 +1,4 @@
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- корзина и задача очистки выбирают только удалённые фото
+CREATE INDEX IF NOT EXISTS idx_photos_deleted_at ON photos (deleted_at) WHERE deleted_at IS NOT NULL;
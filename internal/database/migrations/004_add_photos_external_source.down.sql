@@ -0,0 +1,4 @@
+DELETE FROM photos WHERE unsplash_id IS NULL;
+
+ALTER TABLE photos ALTER COLUMN unsplash_id SET NOT NULL;
+ALTER TABLE photos DROP COLUMN IF EXISTS external_source;
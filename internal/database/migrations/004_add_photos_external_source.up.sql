@@ -0,0 +1,3 @@
+-- фото теперь приходят не только из Unsplash: у загруженных пользователем unsplash_id нет
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS external_source VARCHAR(20) NOT NULL DEFAULT 'unsplash';
+ALTER TABLE photos ALTER COLUMN unsplash_id DROP NOT NULL;
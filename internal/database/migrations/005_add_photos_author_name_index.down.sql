@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_photos_author_name_lower;
@@ -0,0 +1,3 @@
+-- список фото автора сравнивает author_name без учёта регистра;
+-- для списка фото пользователя хватает idx_photos_user_id из 001
+CREATE INDEX IF NOT EXISTS idx_photos_author_name_lower ON photos (LOWER(author_name));
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY,
+    user_id UUID,                              -- NULL для анонимных запросов
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID,
+    old_value JSONB,
+    new_value JSONB,
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- GET /admin/audit выбирает последние события по типу сущности
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity_type_created_at ON audit_logs (entity_type, created_at DESC);
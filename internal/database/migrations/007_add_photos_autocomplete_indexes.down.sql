@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_photos_author_name_prefix;
+DROP INDEX IF EXISTS idx_photos_title_prefix;
@@ -0,0 +1,3 @@
+-- автодополнение ищет по префиксу: LOWER(col) LIKE 'abc%' использует только text_pattern_ops
+CREATE INDEX IF NOT EXISTS idx_photos_title_prefix ON photos (LOWER(title) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_photos_author_name_prefix ON photos (LOWER(author_name) text_pattern_ops);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_photos_search_vector;
+
+ALTER TABLE photos DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,9 @@
+-- полнотекстовый поиск по бд: название важнее описания, описание важнее автора
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(author_name, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_photos_search_vector ON photos USING GIN (search_vector);
@@ -0,0 +1,2 @@
+ALTER TABLE photos DROP COLUMN IF EXISTS mime_type;
+ALTER TABLE photos DROP COLUMN IF EXISTS size_bytes;
@@ -0,0 +1,3 @@
+-- размер и MIME-тип файла в S3, чтобы клиенту не нужен был HEAD-запрос
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS size_bytes BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS mime_type VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- галерея пользователя сортирует по created_at DESC; у системного пользователя почти все фото,
+-- поэтому одного idx_photos_user_id мало — нужен индекс и под сортировку
+CREATE INDEX IF NOT EXISTS idx_photos_user_id_created_at ON photos (user_id, created_at DESC) WHERE deleted_at IS NULL;
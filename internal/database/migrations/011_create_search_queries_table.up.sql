@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS search_queries (
+    id UUID PRIMARY KEY,
+    query TEXT NOT NULL,
+    page INTEGER NOT NULL,
+    per_page INTEGER NOT NULL,
+    results_saved INTEGER NOT NULL DEFAULT 0,
+    source VARCHAR(16) NOT NULL,               -- server или worker
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- GET /search/history отдаёт последние запросы первыми
+CREATE INDEX IF NOT EXISTS idx_search_queries_created_at ON search_queries (created_at DESC);
+-- GET /search/suggest ищет по префиксу: LOWER(query) LIKE 'abc%' использует только text_pattern_ops
+CREATE INDEX IF NOT EXISTS idx_search_queries_query_prefix ON search_queries (LOWER(query) text_pattern_ops);
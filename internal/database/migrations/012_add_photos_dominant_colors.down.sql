@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS palette_distance(TEXT[], TEXT);
+DROP FUNCTION IF EXISTS hex_color_distance(TEXT, TEXT);
+ALTER TABLE photos DROP COLUMN IF EXISTS dominant_colors;
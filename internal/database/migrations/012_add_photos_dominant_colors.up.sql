@@ -0,0 +1,18 @@
+-- доминирующие цвета фото ("#rrggbb", самый частый первым) и поиск по ним
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS dominant_colors TEXT[] NOT NULL DEFAULT '{}';
+
+-- hex_color_distance — евклидово расстояние в RGB между цветами вида "#rrggbb"
+CREATE OR REPLACE FUNCTION hex_color_distance(a TEXT, b TEXT) RETURNS DOUBLE PRECISION
+LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE AS $$
+    SELECT sqrt(
+        power(('x' || substr(ltrim(a, '#'), 1, 2))::bit(8)::int - ('x' || substr(ltrim(b, '#'), 1, 2))::bit(8)::int, 2) +
+        power(('x' || substr(ltrim(a, '#'), 3, 2))::bit(8)::int - ('x' || substr(ltrim(b, '#'), 3, 2))::bit(8)::int, 2) +
+        power(('x' || substr(ltrim(a, '#'), 5, 2))::bit(8)::int - ('x' || substr(ltrim(b, '#'), 5, 2))::bit(8)::int, 2)
+    )
+$$;
+
+-- palette_distance — расстояние от target до ближайшего цвета палитры; NULL для пустой палитры
+CREATE OR REPLACE FUNCTION palette_distance(palette TEXT[], target TEXT) RETURNS DOUBLE PRECISION
+LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE AS $$
+    SELECT MIN(hex_color_distance(c, target)) FROM unnest(palette) AS c
+$$;
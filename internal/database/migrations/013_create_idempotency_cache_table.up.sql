@@ -0,0 +1,10 @@
+-- ответы на запросы с заголовком X-Idempotency-Key; status_code = 0 — запрос ещё выполняется
+CREATE TABLE IF NOT EXISTS idempotency_cache (
+    key TEXT PRIMARY KEY,
+    status_code INT NOT NULL DEFAULT 0,
+    response JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- периодическая очистка удаляет записи старше IDEMPOTENCY_KEY_TTL_HOURS
+CREATE INDEX IF NOT EXISTS idx_idempotency_cache_created_at ON idempotency_cache (created_at);
@@ -0,0 +1,23 @@
+-- подборки фото пользователей
+CREATE TABLE IF NOT EXISTS collections (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- GET /collections отдаёт подборки пользователя, новые первыми
+CREATE INDEX IF NOT EXISTS idx_collections_user_created ON collections (user_id, created_at DESC);
+
+-- состав подборок: удаление подборки или фото убирает только связь, сами фото остаются
+CREATE TABLE IF NOT EXISTS collection_photos (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    photo_id UUID NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, photo_id)
+);
+
+-- GET /collections/{id}/photos отдаёт фото в порядке добавления, последние первыми
+CREATE INDEX IF NOT EXISTS idx_collection_photos_added ON collection_photos (collection_id, added_at DESC);
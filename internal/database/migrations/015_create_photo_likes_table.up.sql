@@ -0,0 +1,10 @@
+-- избранное: локальные лайки пользователей, не связанные с likes_count из Unsplash
+CREATE TABLE IF NOT EXISTS photo_likes (
+    photo_id UUID NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (photo_id, user_id)
+);
+
+-- GET /users/me/likes отдаёт избранное пользователя, последние лайки первыми
+CREATE INDEX IF NOT EXISTS idx_photo_likes_user_created ON photo_likes (user_id, created_at DESC);
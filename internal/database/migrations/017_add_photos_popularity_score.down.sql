@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_photos_popularity;
+DROP FUNCTION IF EXISTS popularity_score(BIGINT, BIGINT, BIGINT, DOUBLE PRECISION);
+ALTER TABLE photos DROP COLUMN IF EXISTS popularity_score;
@@ -0,0 +1,14 @@
+-- оценка популярности фото для GET /photos/trending; пересчитывается фоновой задачей
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS popularity_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- popularity_score — формула из domain.PopularityScore; отрицательные значения считаются нулём
+CREATE OR REPLACE FUNCTION popularity_score(views BIGINT, likes BIGINT, downloads BIGINT, age_days DOUBLE PRECISION)
+RETURNS DOUBLE PRECISION
+LANGUAGE SQL IMMUTABLE STRICT PARALLEL SAFE AS $$
+    SELECT ln(1 + GREATEST(views, 0)) * 0.3
+         + ln(1 + GREATEST(likes, 0)) * 0.5
+         + ln(1 + GREATEST(downloads, 0)) * 0.2
+         - GREATEST(age_days, 0) * 0.1
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_photos_popularity ON photos (popularity_score DESC);
@@ -0,0 +1,5 @@
+-- расширение postgis не удаляем: оно могло быть установлено до миграции
+DROP INDEX IF EXISTS idx_photos_geo_point;
+ALTER TABLE photos DROP COLUMN IF EXISTS geo_point;
+ALTER TABLE photos DROP COLUMN IF EXISTS longitude;
+ALTER TABLE photos DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,11 @@
+-- координаты съёмки фото и поиск рядом с точкой (GET /photos/nearby)
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+-- geo_point пересчитывается из latitude и longitude; NULL, если координат нет
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS geo_point geography(Point, 4326)
+    GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_photos_geo_point ON photos USING GIST (geo_point);
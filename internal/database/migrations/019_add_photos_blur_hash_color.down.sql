@@ -0,0 +1,2 @@
+ALTER TABLE photos DROP COLUMN IF EXISTS dominant_color;
+ALTER TABLE photos DROP COLUMN IF EXISTS blur_hash;
@@ -0,0 +1,3 @@
+-- BlurHash и средний цвет фото от Unsplash: клиент рисует заглушку, пока грузится само фото
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS blur_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS dominant_color TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- расширение vector не удаляем: оно могло быть установлено до миграции
+DROP INDEX IF EXISTS idx_photos_embedding;
+ALTER TABLE photos DROP COLUMN IF EXISTS embedding;
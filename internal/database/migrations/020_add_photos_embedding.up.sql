@@ -0,0 +1,7 @@
+-- эмбеддинги фото для поиска похожих (GET /photos/{id}/similar); нужен pgvector >= 0.5 для HNSW
+CREATE EXTENSION IF NOT EXISTS vector;
+
+-- NULL, пока воркер не вычислил эмбеддинг
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS embedding vector(512);
+
+CREATE INDEX IF NOT EXISTS idx_photos_embedding ON photos USING hnsw (embedding vector_l2_ops);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tags_name_prefix;
+DROP INDEX IF EXISTS idx_photo_tags_tag_id;
@@ -0,0 +1,4 @@
+-- облако тегов считает фото по tag_id: первичный ключ photo_tags начинается с photo_id и не подходит
+CREATE INDEX IF NOT EXISTS idx_photo_tags_tag_id ON photo_tags (tag_id);
+-- поиск тегов по префиксу: LOWER(name) LIKE 'abc%' использует только text_pattern_ops
+CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags (LOWER(name) text_pattern_ops);
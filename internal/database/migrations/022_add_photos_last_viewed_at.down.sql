@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_photos_last_viewed_at;
+ALTER TABLE photos DROP COLUMN IF EXISTS last_viewed_at;
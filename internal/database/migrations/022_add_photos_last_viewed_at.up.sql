@@ -0,0 +1,4 @@
+-- время последнего просмотра: по нему воркер выбирает фото для обновления статистики Unsplash
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS last_viewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_photos_last_viewed_at ON photos (last_viewed_at DESC) WHERE last_viewed_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_photos_source_external_id;
+ALTER TABLE photos DROP COLUMN IF EXISTS external_id;
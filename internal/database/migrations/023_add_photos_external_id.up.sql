@@ -0,0 +1,11 @@
+-- ID фото в его источнике без префикса: у Pexels в unsplash_id хранится "pexels-<id>".
+-- unsplash_id остаётся как есть для обратной совместимости, external_id вычисляется из него
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS external_id VARCHAR(50) GENERATED ALWAYS AS (
+    CASE WHEN external_source = 'pexels' AND unsplash_id LIKE 'pexels-%'
+         THEN substr(unsplash_id, length('pexels-') + 1)
+         ELSE unsplash_id
+    END
+) STORED;
+
+-- у фото, загруженных пользователями, external_id NULL и уникальности не мешает
+CREATE UNIQUE INDEX IF NOT EXISTS idx_photos_source_external_id ON photos (external_source, external_id);
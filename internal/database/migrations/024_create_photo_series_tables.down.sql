@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS photo_series_members;
+DROP TABLE IF EXISTS photo_series;
@@ -0,0 +1,24 @@
+-- серии связанных снимков пользователей
+CREATE TABLE IF NOT EXISTS photo_series (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- GET /series отдаёт серии пользователя, новые первыми
+CREATE INDEX IF NOT EXISTS idx_photo_series_user_created ON photo_series (user_id, created_at DESC);
+
+-- состав серий с порядком: position идёт подряд с нуля. Уникальности (series_id, position) нет,
+-- чтобы перестановка могла временно совпадать позициями внутри транзакции
+CREATE TABLE IF NOT EXISTS photo_series_members (
+    series_id UUID NOT NULL REFERENCES photo_series(id) ON DELETE CASCADE,
+    photo_id UUID NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (series_id, photo_id)
+);
+
+-- GET /series/{id}/photos отдаёт фото в порядке серии
+CREATE INDEX IF NOT EXISTS idx_photo_series_members_position ON photo_series_members (series_id, position);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_photos_downloads_count;
+DROP INDEX IF EXISTS idx_photos_views_count;
+DROP INDEX IF EXISTS idx_photos_likes_count;
@@ -0,0 +1,5 @@
+-- сортировка списков по счётчикам (?sort=likes_count|views_count|downloads_count): порядок совпадает
+-- с ORDER BY из orderByClause, фото в корзине в выдачу не попадают
+CREATE INDEX IF NOT EXISTS idx_photos_likes_count ON photos (likes_count DESC NULLS LAST, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_photos_views_count ON photos (views_count DESC NULLS LAST, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_photos_downloads_count ON photos (downloads_count DESC NULLS LAST, id DESC) WHERE deleted_at IS NULL;
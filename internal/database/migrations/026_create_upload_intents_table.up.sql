@@ -0,0 +1,15 @@
+-- загрузки фото напрямую в S3 по подписанной ссылке, ещё не подтверждённые клиентом
+CREATE TABLE IF NOT EXISTS upload_intents (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    s3_key TEXT NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- периодическая очистка удаляет брошенные загрузки с истёкшей ссылкой
+CREATE INDEX IF NOT EXISTS idx_upload_intents_expires_at ON upload_intents (expires_at);
@@ -0,0 +1,4 @@
+-- без колонки status неподтверждённые загрузки стали бы видны как обычные фото
+DELETE FROM photos WHERE status = 'pending';
+
+ALTER TABLE photos DROP COLUMN IF EXISTS status;
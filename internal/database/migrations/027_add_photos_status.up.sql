@@ -0,0 +1,4 @@
+-- фото загрузки напрямую в S3 создаётся в статусе pending вместе с upload_intents
+-- и становится active после POST /photos/{id}/complete; pending-фото не попадают в выдачу
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pending', 'active'));
@@ -0,0 +1,2 @@
+ALTER TABLE photos DROP COLUMN IF EXISTS unsplash_downloads;
+ALTER TABLE photos DROP COLUMN IF EXISTS unsplash_views;
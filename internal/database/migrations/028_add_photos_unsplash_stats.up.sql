@@ -0,0 +1,4 @@
+-- статистика Unsplash хранится отдельно от собственных счётчиков views_count/downloads_count:
+-- иначе обновление статистики затирало бы просмотры и скачивания, посчитанные приложением
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS unsplash_views BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS unsplash_downloads BIGINT NOT NULL DEFAULT 0;
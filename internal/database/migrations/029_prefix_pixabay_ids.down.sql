@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_photos_source_external_id;
+ALTER TABLE photos DROP COLUMN IF EXISTS external_id;
+
+UPDATE photos SET unsplash_id = substr(unsplash_id, length('pixabay-') + 1)
+WHERE external_source = 'pixabay' AND unsplash_id LIKE 'pixabay-%';
+
+ALTER TABLE photos ADD COLUMN external_id VARCHAR(50) GENERATED ALWAYS AS (
+    CASE WHEN external_source = 'pexels' AND unsplash_id LIKE 'pexels-%'
+         THEN substr(unsplash_id, length('pexels-') + 1)
+         ELSE unsplash_id
+    END
+) STORED;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_photos_source_external_id ON photos (external_source, external_id);
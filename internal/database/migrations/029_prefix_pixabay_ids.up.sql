@@ -0,0 +1,17 @@
+-- числовые ID Pixabay и Pexels пересекаются, поэтому ID Pixabay в unsplash_id
+-- хранится с префиксом "pixabay-", как у Pexels; external_id по-прежнему без префикса
+UPDATE photos SET unsplash_id = 'pixabay-' || unsplash_id
+WHERE external_source = 'pixabay' AND unsplash_id NOT LIKE 'pixabay-%';
+
+DROP INDEX IF EXISTS idx_photos_source_external_id;
+ALTER TABLE photos DROP COLUMN IF EXISTS external_id;
+ALTER TABLE photos ADD COLUMN external_id VARCHAR(50) GENERATED ALWAYS AS (
+    CASE WHEN external_source = 'pexels' AND unsplash_id LIKE 'pexels-%'
+         THEN substr(unsplash_id, length('pexels-') + 1)
+         WHEN external_source = 'pixabay' AND unsplash_id LIKE 'pixabay-%'
+         THEN substr(unsplash_id, length('pixabay-') + 1)
+         ELSE unsplash_id
+    END
+) STORED;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_photos_source_external_id ON photos (external_source, external_id);
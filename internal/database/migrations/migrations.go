@@ -0,0 +1,9 @@
+// Package migrations встраивает SQL-миграции в бинарник, чтобы они не зависели от рабочей директории
+package migrations
+
+import "embed"
+
+// FS содержит файлы миграций в формате golang-migrate (NNN_name.up.sql / NNN_name.down.sql)
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,221 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/database/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrator применяет встроенные миграции (migrations.FS) к PostgreSQL.
+// Миграции читаются из бинарника, поэтому рабочая директория процесса не важна
+type Migrator struct {
+	m      *migrate.Migrate
+	logger *slog.Logger
+}
+
+// New создает Migrator поверх уже открытого пула соединений: берёт из него одно соединение
+// и возвращает его в Close. Сам пул Close не закрывает
+func New(ctx context.Context, db *sqlx.DB, logger *slog.Logger) (*Migrator, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения встроенных миграций: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения соединения для миграций: %w", err)
+	}
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("ошибка инициализации драйвера миграций: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, fmt.Errorf("ошибка инициализации миграций: %w", err)
+	}
+	return &Migrator{m: m, logger: logger}, nil
+}
+
+// Open создает Migrator с собственным подключением по databaseURL; Close его закрывает.
+// Нужен для -mode=migrate, где остальные зависимости приложения не поднимаются
+func Open(databaseURL string, logger *slog.Logger) (*Migrator, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения встроенных миграций: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка инициализации миграций: %w", err)
+	}
+	return &Migrator{m: m, logger: logger}, nil
+}
+
+// Up применяет все ещё не применённые миграции по одной, логируя каждую.
+// Если миграция упала и autoRollback включён, схема возвращается к версии до запуска:
+// упавшая миграция выполняется одним запросом в неявной транзакции и не оставляет следов,
+// поэтому dirty снимается принудительным переходом на последнюю успешную версию,
+// а применённые за этот запуск миграции откатываются. Без autoRollback схема остаётся dirty
+// до ручного исправления
+func (mg *Migrator) Up(autoRollback bool) error {
+	start := time.Now()
+	before, dirty, err := mg.Version()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		mg.logger.Error("database schema is dirty, refusing to migrate", "version", before)
+		return fmt.Errorf("схема в состоянии dirty на версии %d: требуется ручное исправление", before)
+	}
+
+	// versions — версии, применённые за этот запуск, по порядку
+	var versions []uint
+	for {
+		stepStart := time.Now()
+		err := mg.m.Steps(1)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			mg.logger.Error("failed to apply migration", "version_before", before, "applied", len(versions), "error", err)
+			if !autoRollback {
+				return fmt.Errorf("ошибка применения миграций: %w", err)
+			}
+			if rbErr := mg.rollback(before, versions); rbErr != nil {
+				return fmt.Errorf("ошибка применения миграций: %w; откат не удался: %w", err, rbErr)
+			}
+			return fmt.Errorf("ошибка применения миграций, схема возвращена к версии %d: %w", before, err)
+		}
+
+		version, _, err := mg.Version()
+		if err != nil {
+			return err
+		}
+		versions = append(versions, version)
+		mg.logger.Info("migration applied", "version", version, "duration_ms", time.Since(stepStart).Milliseconds())
+	}
+
+	if len(versions) == 0 {
+		mg.logger.Info("database schema is up to date", "version", before)
+		return nil
+	}
+	mg.logVersion("migrations applied successfully", start)
+	return nil
+}
+
+// rollback возвращает схему к версии before после неудачного Up, успевшего применить versions
+func (mg *Migrator) rollback(before uint, versions []uint) error {
+	failed, _, err := mg.Version()
+	if err != nil {
+		return err
+	}
+
+	// последняя успешная версия; -1 в Force означает «ни одной миграции»
+	last := int(before)
+	if len(versions) > 0 {
+		last = int(versions[len(versions)-1])
+	} else if before == 0 {
+		last = -1
+	}
+	if err := mg.m.Force(last); err != nil {
+		return fmt.Errorf("не удалось снять dirty с версии %d: %w", failed, err)
+	}
+	mg.logger.Info("failed migration discarded", "failed_version", failed, "version", max(last, 0))
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		stepStart := time.Now()
+		if err := mg.m.Steps(-1); err != nil {
+			return fmt.Errorf("ошибка отката миграции %d: %w", versions[i], err)
+		}
+		mg.logger.Info("migration rolled back", "version", versions[i], "duration_ms", time.Since(stepStart).Milliseconds())
+	}
+	mg.logger.Info("schema restored to pre-run version", "version", before)
+	return nil
+}
+
+// Down откатывает steps последних миграций
+func (mg *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("количество шагов отката должно быть положительным, получено %d", steps)
+	}
+
+	start := time.Now()
+	if err := mg.m.Steps(-steps); err != nil {
+		mg.logger.Error("failed to roll back migrations", "steps", steps, "error", err)
+		return fmt.Errorf("ошибка отката миграций: %w", err)
+	}
+	mg.logVersion("migrations rolled back successfully", start)
+	return nil
+}
+
+// Version возвращает номер последней применённой миграции (0 — ни одной) и признак dirty:
+// миграция упала на середине и схему нужно чинить вручную
+func (mg *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("ошибка получения версии схемы: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// Close освобождает соединение мигратора
+func (mg *Migrator) Close() error {
+	sourceErr, dbErr := mg.m.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+func (mg *Migrator) logVersion(msg string, start time.Time) {
+	version, dirty, err := mg.Version()
+	if err != nil {
+		mg.logger.Warn("failed to read schema version", "error", err)
+	}
+	mg.logger.Info(msg,
+		"version", version,
+		"dirty", dirty,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+// VersionReader читает версию схемы из таблицы schema_migrations, которую ведёт golang-migrate,
+// — то же, что Migrator.Version, но через общий пул: без отдельного соединения и блокировки миграций.
+// Нужен для проверок готовности и мониторинга во время работы сервера
+type VersionReader struct {
+	db *sqlx.DB
+}
+
+// NewVersionReader создает новый экземпляр VersionReader
+func NewVersionReader(db *sqlx.DB) *VersionReader {
+	return &VersionReader{db: db}
+}
+
+// SchemaVersion возвращает номер последней применённой миграции (0 — ни одной) и признак dirty
+func (r *VersionReader) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	var row struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+	err := r.db.GetContext(ctx, &row, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("ошибка получения версии схемы: %w", err)
+	}
+	return uint(max(row.Version, 0)), row.Dirty, nil
+}
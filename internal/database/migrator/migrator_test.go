@@ -0,0 +1,95 @@
+package migrator
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/database/migrations"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// embeddedVersions возвращает версии встроенных миграций по порядку и проверяет,
+// что у каждой есть и up, и down
+func embeddedVersions(t *testing.T) []uint {
+	t.Helper()
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		t.Fatalf("read embedded migrations: %v", err)
+	}
+	defer source.Close()
+
+	var versions []uint
+	version, err := source.First()
+	for err == nil {
+		for _, read := range []func(uint) (io.ReadCloser, string, error){source.ReadUp, source.ReadDown} {
+			r, _, err := read(version)
+			if err != nil {
+				t.Fatalf("migration %d: %v", version, err)
+			}
+			_ = r.Close()
+		}
+		versions = append(versions, version)
+		version, err = source.Next(version)
+	}
+	return versions
+}
+
+func TestEmbeddedMigrations_IndependentOfWorkingDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	versions := embeddedVersions(t)
+	if len(versions) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	for i, version := range versions {
+		if version != uint(i+1) {
+			t.Fatalf("migration #%d has version %d, want %d: versions must have no gaps", i+1, version, i+1)
+		}
+	}
+}
+
+// TestMigrator_UpVersionDown прогоняет up, version и down 1 на Postgres из TEST_DATABASE_URL
+// из пустой рабочей директории; в конце схема снова на последней версии
+func TestMigrator_UpVersionDown(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	t.Chdir(t.TempDir())
+	versions := embeddedVersions(t)
+	latest := versions[len(versions)-1]
+
+	m, err := Open(url, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer m.Close()
+
+	assertVersion := func(t *testing.T, want uint) {
+		t.Helper()
+		version, dirty, err := m.Version()
+		if err != nil {
+			t.Fatalf("Version: %v", err)
+		}
+		if version != want || dirty {
+			t.Fatalf("version = %d (dirty %t), want %d", version, dirty, want)
+		}
+	}
+
+	if err := m.Up(false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	assertVersion(t, latest)
+
+	if err := m.Down(1); err != nil {
+		t.Fatalf("Down(1): %v", err)
+	}
+	assertVersion(t, latest-1)
+
+	if err := m.Up(false); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+	assertVersion(t, latest)
+}
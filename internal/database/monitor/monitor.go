@@ -0,0 +1,87 @@
+// Package monitor следит за работой с БД: публикует состояние пула соединений в виде
+// Prometheus-метрик и пишет в лог медленные запросы.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pollInterval — как часто снимается db.Stats()
+const pollInterval = 10 * time.Second
+
+const namespace = "mediaapp"
+
+// poolMetrics — метрики пула; обновляются из sql.DBStats
+type poolMetrics struct {
+	open      prometheus.Gauge
+	inUse     prometheus.Gauge
+	idle      prometheus.Gauge
+	waitCount prometheus.Counter
+
+	// lastWaitCount — предыдущее значение WaitCount: счётчик прирастает на разницу
+	lastWaitCount int64
+}
+
+// StartPoolMonitor регистрирует метрики пула в reg и раз в pollInterval обновляет их из db.Stats().
+// Фоновое обновление останавливается с отменой ctx
+func StartPoolMonitor(ctx context.Context, db *sqlx.DB, reg prometheus.Registerer) error {
+	m := &poolMetrics{
+		open: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_open_connections",
+			Help:      "Количество открытых соединений с БД (занятых и свободных).",
+		}),
+		inUse: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_in_use_connections",
+			Help:      "Количество соединений с БД, занятых запросами.",
+		}),
+		idle: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_idle_connections",
+			Help:      "Количество свободных соединений в пуле.",
+		}),
+		waitCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_wait_count_total",
+			Help:      "Сколько раз запрос ждал свободного соединения из-за DB_MAX_OPEN_CONNS.",
+		}),
+	}
+	for _, c := range []prometheus.Collector{m.open, m.inUse, m.idle, m.waitCount} {
+		if err := reg.Register(c); err != nil {
+			return fmt.Errorf("ошибка регистрации метрик пула соединений: %w", err)
+		}
+	}
+
+	m.update(db)
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.update(db)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// update переносит текущее состояние пула в метрики
+func (m *poolMetrics) update(db *sqlx.DB) {
+	stats := db.Stats()
+	m.open.Set(float64(stats.OpenConnections))
+	m.inUse.Set(float64(stats.InUse))
+	m.idle.Set(float64(stats.Idle))
+	if delta := stats.WaitCount - m.lastWaitCount; delta > 0 {
+		m.waitCount.Add(float64(delta))
+	}
+	m.lastWaitCount = stats.WaitCount
+}
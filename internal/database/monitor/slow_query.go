@@ -0,0 +1,310 @@
+package monitor
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxLoggedParamLen — длиннее этого значение параметра в логе обрезается (эмбеддинги, длинные описания)
+const maxLoggedParamLen = 200
+
+// maskedParam заменяет в логе значения параметров для колонок с паролями
+const maskedParam = "***"
+
+// SlowQueryDB оборачивает *sqlx.DB и пишет в лог на уровне Warn запросы, выполнявшиеся
+// дольше порога: SQL, параметры (значения для колонок с password в имени скрыты) и длительность.
+// Транзакции из BeginTxx замеряются так же; остальные методы *sqlx.DB доступны без замера
+type SlowQueryDB struct {
+	*sqlx.DB
+	threshold time.Duration
+	logger    *slog.Logger
+	// now — источник времени для замера
+	now func() time.Time
+}
+
+// NewSlowQueryDB создает новый экземпляр SlowQueryDB. threshold <= 0 отключает журнал медленных запросов
+func NewSlowQueryDB(db *sqlx.DB, threshold time.Duration, logger *slog.Logger) *SlowQueryDB {
+	return &SlowQueryDB{DB: db, threshold: threshold, logger: logger, now: time.Now}
+}
+
+// QueryxContext выполняет запрос, как *sqlx.DB, и замеряет его
+func (d *SlowQueryDB) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	start := d.now()
+	rows, err := d.DB.QueryxContext(ctx, query, args...)
+	d.observe(ctx, start, query, args)
+	return rows, err
+}
+
+// GetContext выполняет запрос, как *sqlx.DB, и замеряет его
+func (d *SlowQueryDB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	start := d.now()
+	err := d.DB.GetContext(ctx, dest, query, args...)
+	d.observe(ctx, start, query, args)
+	return err
+}
+
+// SelectContext выполняет запрос, как *sqlx.DB, и замеряет его
+func (d *SlowQueryDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	start := d.now()
+	err := d.DB.SelectContext(ctx, dest, query, args...)
+	d.observe(ctx, start, query, args)
+	return err
+}
+
+// ExecContext выполняет запрос, как *sqlx.DB, и замеряет его
+func (d *SlowQueryDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := d.now()
+	res, err := d.DB.ExecContext(ctx, query, args...)
+	d.observe(ctx, start, query, args)
+	return res, err
+}
+
+// NamedExecContext выполняет запрос с именованными параметрами, как *sqlx.DB, и замеряет его
+func (d *SlowQueryDB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	start := d.now()
+	res, err := d.DB.NamedExecContext(ctx, query, arg)
+	d.observeNamed(ctx, start, query, arg)
+	return res, err
+}
+
+// NamedQueryContext выполняет запрос с именованными параметрами, как *sqlx.DB, и замеряет его
+func (d *SlowQueryDB) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	start := d.now()
+	rows, err := d.DB.NamedQueryContext(ctx, query, arg)
+	d.observeNamed(ctx, start, query, arg)
+	return rows, err
+}
+
+// BeginTxx открывает транзакцию, запросы которой замеряются так же, как запросы SlowQueryDB
+func (d *SlowQueryDB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*SlowQueryTx, error) {
+	tx, err := d.DB.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &SlowQueryTx{Tx: tx, db: d}, nil
+}
+
+// SlowQueryTx оборачивает *sqlx.Tx и пишет медленные запросы в лог с порогом и логгером своего SlowQueryDB.
+// Commit, Rollback и остальные методы *sqlx.Tx доступны без замера
+type SlowQueryTx struct {
+	*sqlx.Tx
+	db *SlowQueryDB
+}
+
+// QueryxContext выполняет запрос, как *sqlx.Tx, и замеряет его
+func (t *SlowQueryTx) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	start := t.db.now()
+	rows, err := t.Tx.QueryxContext(ctx, query, args...)
+	t.db.observe(ctx, start, query, args)
+	return rows, err
+}
+
+// GetContext выполняет запрос, как *sqlx.Tx, и замеряет его
+func (t *SlowQueryTx) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	start := t.db.now()
+	err := t.Tx.GetContext(ctx, dest, query, args...)
+	t.db.observe(ctx, start, query, args)
+	return err
+}
+
+// SelectContext выполняет запрос, как *sqlx.Tx, и замеряет его
+func (t *SlowQueryTx) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	start := t.db.now()
+	err := t.Tx.SelectContext(ctx, dest, query, args...)
+	t.db.observe(ctx, start, query, args)
+	return err
+}
+
+// ExecContext выполняет запрос, как *sqlx.Tx, и замеряет его
+func (t *SlowQueryTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := t.db.now()
+	res, err := t.Tx.ExecContext(ctx, query, args...)
+	t.db.observe(ctx, start, query, args)
+	return res, err
+}
+
+// NamedExecContext выполняет запрос с именованными параметрами, как *sqlx.Tx, и замеряет его
+func (t *SlowQueryTx) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	start := t.db.now()
+	res, err := t.Tx.NamedExecContext(ctx, query, arg)
+	t.db.observeNamed(ctx, start, query, arg)
+	return res, err
+}
+
+// observe пишет запрос с позиционными параметрами ($1, $2, ...) в лог, если он медленный
+func (d *SlowQueryDB) observe(ctx context.Context, start time.Time, query string, args []interface{}) {
+	elapsed, slow := d.slow(start)
+	if !slow {
+		return
+	}
+	sensitive := sensitivePositions(query)
+	params := make([]string, len(args))
+	for i, arg := range args {
+		if sensitive[i+1] {
+			params[i] = maskedParam
+			continue
+		}
+		params[i] = formatParam(arg)
+	}
+	d.logSlow(ctx, query, params, elapsed)
+}
+
+// observeNamed пишет запрос с именованными параметрами (:name) в лог, если он медленный
+func (d *SlowQueryDB) observeNamed(ctx context.Context, start time.Time, query string, arg interface{}) {
+	elapsed, slow := d.slow(start)
+	if !slow {
+		return
+	}
+	// sqlx.Named раскладывает arg в том же порядке, в каком имена встречаются в запросе
+	_, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		d.logSlow(ctx, query, nil, elapsed)
+		return
+	}
+	names := namedParamNames(query)
+	params := make([]string, len(args))
+	for i, value := range args {
+		if i < len(names) && isSensitiveColumn(names[i]) {
+			params[i] = maskedParam
+			continue
+		}
+		params[i] = formatParam(value)
+	}
+	d.logSlow(ctx, query, params, elapsed)
+}
+
+// slow возвращает длительность запроса и превысила ли она порог
+func (d *SlowQueryDB) slow(start time.Time) (time.Duration, bool) {
+	if d.threshold <= 0 {
+		return 0, false
+	}
+	elapsed := d.now().Sub(start)
+	return elapsed, elapsed >= d.threshold
+}
+
+// logSlow пишет медленный запрос в лог под ключом slow_query
+func (d *SlowQueryDB) logSlow(ctx context.Context, query string, params []string, elapsed time.Duration) {
+	logger.FromContext(ctx, d.logger).Warn("slow query",
+		slog.Group("slow_query",
+			slog.String("sql", strings.Join(strings.Fields(query), " ")),
+			slog.Any("params", params),
+			slog.Int64("duration_ms", elapsed.Milliseconds()),
+			slog.Int64("threshold_ms", d.threshold.Milliseconds()),
+		),
+	)
+}
+
+var (
+	// comparedPlaceholder — сравнение колонки с параметром: password_hash = $3
+	comparedPlaceholder = regexp.MustCompile(`(?i)(\w+)\s*(?:=|<>|!=)\s*\$(\d+)`)
+	// insertColumns — список колонок INSERT и начало VALUES
+	insertColumns = regexp.MustCompile(`(?is)INSERT\s+INTO\s+[\w.]+\s*\(([^)]*)\)\s*VALUES\s*\(`)
+	placeholder   = regexp.MustCompile(`\$(\d+)`)
+	// namedParam — именованный параметр sqlx; "::" — приведение типа Postgres, а не параметр
+	namedParam = regexp.MustCompile(`::|:(\w+)`)
+)
+
+// sensitivePositions находит номера позиционных параметров, которые пишутся в колонки
+// с паролями или сравниваются с ними: в SET и WHERE (col = $n) и в первой строке INSERT ... VALUES
+func sensitivePositions(query string) map[int]bool {
+	sensitive := make(map[int]bool)
+	for _, m := range comparedPlaceholder.FindAllStringSubmatch(query, -1) {
+		if isSensitiveColumn(m[1]) {
+			n, _ := strconv.Atoi(m[2])
+			sensitive[n] = true
+		}
+	}
+
+	loc := insertColumns.FindStringSubmatchIndex(query)
+	if loc == nil {
+		return sensitive
+	}
+	columns := strings.Split(query[loc[2]:loc[3]], ",")
+	values := splitTopLevel(query[loc[1]:])
+	for i, column := range columns {
+		if i >= len(values) || !isSensitiveColumn(strings.TrimSpace(column)) {
+			continue
+		}
+		for _, m := range placeholder.FindAllStringSubmatch(values[i], -1) {
+			n, _ := strconv.Atoi(m[1])
+			sensitive[n] = true
+		}
+	}
+	return sensitive
+}
+
+// splitTopLevel делит выражения строки VALUES по запятым верхнего уровня до закрывающей скобки.
+// s начинается сразу после открывающей скобки
+func splitTopLevel(s string) []string {
+	var (
+		parts []string
+		depth int
+		from  int
+	)
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return append(parts, s[from:i])
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[from:i])
+				from = i + 1
+			}
+		}
+	}
+	return append(parts, s[from:])
+}
+
+// namedParamNames возвращает имена параметров :name в порядке появления в запросе
+func namedParamNames(query string) []string {
+	var names []string
+	for _, m := range namedParam.FindAllStringSubmatch(query, -1) {
+		if m[1] != "" {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// isSensitiveColumn сообщает, что в колонке хранится пароль или его хеш
+func isSensitiveColumn(name string) bool {
+	return strings.Contains(strings.ToLower(name), "password")
+}
+
+// formatParam приводит параметр запроса к строке для лога; pq.Array и другие driver.Valuer
+// показываются так, как уходят в бд
+func formatParam(arg interface{}) string {
+	if valuer, ok := arg.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			arg = value
+		}
+	}
+	var s string
+	switch v := arg.(type) {
+	case nil:
+		s = "NULL"
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+	if len(s) > maxLoggedParamLen {
+		s = strings.ToValidUTF8(s[:maxLoggedParamLen], "") + "..."
+	}
+	return s
+}
@@ -0,0 +1,185 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+// stepClock — поддельные часы: каждый вызов now сдвигает время на step,
+// поэтому запрос (два вызова now) длится ровно step
+type stepClock struct {
+	t    time.Time
+	step time.Duration
+}
+
+func (c *stepClock) now() time.Time {
+	t := c.t
+	c.t = c.t.Add(c.step)
+	return t
+}
+
+// newTestSlowQueryDB возвращает SlowQueryDB на SQLite, у которого каждый запрос длится elapsed,
+// и буфер с его логом
+func newTestSlowQueryDB(t *testing.T, threshold, elapsed time.Duration) (*SlowQueryDB, *bytes.Buffer) {
+	t.Helper()
+	db, err := sqlx.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	var buf bytes.Buffer
+	d := NewSlowQueryDB(db, threshold, slog.New(slog.NewJSONHandler(&buf, nil)))
+	d.now = (&stepClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), step: elapsed}).now
+	return d, &buf
+}
+
+// slowQueryRecord — запись "slow query" в JSON-логе
+type slowQueryRecord struct {
+	Msg       string `json:"msg"`
+	Level     string `json:"level"`
+	SlowQuery struct {
+		SQL         string   `json:"sql"`
+		Params      []string `json:"params"`
+		DurationMs  int64    `json:"duration_ms"`
+		ThresholdMs int64    `json:"threshold_ms"`
+	} `json:"slow_query"`
+}
+
+func slowQueryRecords(t *testing.T, buf *bytes.Buffer) []slowQueryRecord {
+	t.Helper()
+	var records []slowQueryRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec slowQueryRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		if rec.Msg == "slow query" {
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+func TestSlowQueryDB_Threshold(t *testing.T) {
+	const threshold = 100 * time.Millisecond
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		elapsed   time.Duration
+		logged    bool
+	}{
+		{name: "just below threshold", threshold: threshold, elapsed: threshold - time.Millisecond},
+		{name: "at threshold", threshold: threshold, elapsed: threshold, logged: true},
+		{name: "above threshold", threshold: threshold, elapsed: 3 * threshold, logged: true},
+		{name: "disabled", threshold: 0, elapsed: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, buf := newTestSlowQueryDB(t, tt.threshold, tt.elapsed)
+			var n int
+			if err := d.GetContext(context.Background(), &n, "SELECT ?  +\n  1", 41); err != nil {
+				t.Fatalf("GetContext: %v", err)
+			}
+
+			records := slowQueryRecords(t, buf)
+			if !tt.logged {
+				if len(records) != 0 {
+					t.Errorf("slow query records = %d, want none", len(records))
+				}
+				return
+			}
+			if len(records) != 1 {
+				t.Fatalf("slow query records = %d, want 1; log: %s", len(records), buf)
+			}
+			rec := records[0].SlowQuery
+			if records[0].Level != "WARN" {
+				t.Errorf("level = %s, want WARN", records[0].Level)
+			}
+			if rec.SQL != "SELECT ? + 1" {
+				t.Errorf("sql = %q, want whitespace collapsed", rec.SQL)
+			}
+			if len(rec.Params) != 1 || rec.Params[0] != "41" {
+				t.Errorf("params = %v, want [41]", rec.Params)
+			}
+			if rec.DurationMs != tt.elapsed.Milliseconds() || rec.ThresholdMs != tt.threshold.Milliseconds() {
+				t.Errorf("duration, threshold = %dms, %dms; want %dms, %dms",
+					rec.DurationMs, rec.ThresholdMs, tt.elapsed.Milliseconds(), tt.threshold.Milliseconds())
+			}
+		})
+	}
+}
+
+func TestSlowQueryDB_MasksPasswordParams(t *testing.T) {
+	d, buf := newTestSlowQueryDB(t, time.Millisecond, time.Second)
+	ctx := context.Background()
+	if _, err := d.ExecContext(ctx, `CREATE TABLE users (name TEXT, password_hash TEXT)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	buf.Reset()
+
+	if _, err := d.ExecContext(ctx, `INSERT INTO users (name, password_hash) VALUES ($1, $2)`, "alice", "secret-hash"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if _, err := d.NamedExecContext(ctx, `UPDATE users SET password_hash = :password_hash WHERE name = :name`,
+		map[string]any{"name": "alice", "password_hash": "new-secret-hash"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	records := slowQueryRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("slow query records = %d, want 2; log: %s", len(records), buf)
+	}
+	if strings.Contains(buf.String(), "secret-hash") {
+		t.Errorf("log contains a password hash: %s", buf)
+	}
+	want := [][]string{{"alice", maskedParam}, {maskedParam, "alice"}}
+	for i, rec := range records {
+		if strings.Join(rec.SlowQuery.Params, ",") != strings.Join(want[i], ",") {
+			t.Errorf("query %d params = %v, want %v", i, rec.SlowQuery.Params, want[i])
+		}
+	}
+}
+
+func TestSlowQueryTx_LogsQueriesInTransaction(t *testing.T) {
+	d, buf := newTestSlowQueryDB(t, 100*time.Millisecond, time.Second)
+	ctx := context.Background()
+
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTxx: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE photos (id INTEGER)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO photos (id) VALUES (?), (?)`, 1, 2); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	var ids []int
+	if err := tx.SelectContext(ctx, &ids, `SELECT id FROM photos`); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	records := slowQueryRecords(t, buf)
+	if len(records) != 3 {
+		t.Fatalf("slow query records = %d, want 3; log: %s", len(records), buf)
+	}
+	insert := records[1].SlowQuery
+	if insert.SQL != "INSERT INTO photos (id) VALUES (?), (?)" || len(insert.Params) != 2 || insert.DurationMs != 1000 {
+		t.Errorf("insert record = %+v, want the batch insert with 2 params and 1000ms", insert)
+	}
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// AuditStorage реализует ports.AuditStorage поверх таблицы audit_logs в SQLite
+type AuditStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewAuditStorage создает новый экземпляр AuditStorage
+func NewAuditStorage(db *sqlx.DB, logger *slog.Logger) *AuditStorage {
+	return &AuditStorage{db: db, logger: logger}
+}
+
+// RecordAuditEvent сохраняет событие аудита
+func (s *AuditStorage) RecordAuditEvent(ctx context.Context, event *domain.AuditLog) error {
+	ctx, span := startSpan(ctx, "RecordAuditEvent", attribute.String("action", event.Action))
+	defer span.End()
+
+	start := time.Now()
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+	INSERT INTO audit_logs (id, user_id, action, entity_type, entity_id, old_value, new_value, ip_address, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID, nullableUUID(event.UserID), event.Action, event.EntityType, nullableUUID(event.EntityID),
+		nullableJSON(event.OldValue), nullableJSON(event.NewValue), event.IPAddress, formatTime(event.CreatedAt),
+	)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to record audit event", "action", event.Action, "error", err)
+		return fmt.Errorf("ошибка при сохранении события аудита: %w", err)
+	}
+
+	s.log(ctx).Debug("audit event recorded",
+		"id", event.ID,
+		"action", event.Action,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// ListAuditEvents возвращает последние limit событий, новые первыми.
+// Пустой entityType — события по всем сущностям
+func (s *AuditStorage) ListAuditEvents(ctx context.Context, entityType string, limit int) ([]domain.AuditLog, error) {
+	ctx, span := startSpan(ctx, "ListAuditEvents", attribute.String("entity_type", entityType), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	// JSON читается как BLOB: строку database/sql в json.RawMessage не сканирует
+	q := `
+	SELECT id, user_id, action, entity_type, entity_id, CAST(COALESCE(old_value, '') AS BLOB) AS old_value,
+	       CAST(COALESCE(new_value, '') AS BLOB) AS new_value,
+	       COALESCE(ip_address, '') AS ip_address, created_at
+	FROM audit_logs
+	WHERE ?1 = '' OR entity_type = ?1
+	ORDER BY created_at DESC
+	LIMIT ?2
+	`
+
+	events := []domain.AuditLog{}
+	if err := s.db.SelectContext(ctx, &events, q, entityType, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list audit events", "entity_type", entityType, "error", err)
+		return nil, fmt.Errorf("ошибка при получении журнала аудита: %w", err)
+	}
+
+	s.log(ctx).Info("listed audit events successfully",
+		"entity_type", entityType,
+		"count", len(events),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return events, nil
+}
+
+func (s *AuditStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
+
+// nullableUUID превращает uuid.Nil в NULL
+func nullableUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
+// nullableJSON сохраняет JSON строкой, пустой — как NULL
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
@@ -0,0 +1,291 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// collectionColumns — явный список колонок collections
+const collectionColumns = `id, user_id, name, description, created_at, updated_at`
+
+// CollectionStorage реализует ports.CollectionStorage поверх таблиц collections и collection_photos в SQLite
+type CollectionStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewCollectionStorage создает новый экземпляр CollectionStorage
+func NewCollectionStorage(db *sqlx.DB, logger *slog.Logger) *CollectionStorage {
+	return &CollectionStorage{db: db, logger: logger}
+}
+
+// CreateCollection сохраняет новую подборку
+func (s *CollectionStorage) CreateCollection(ctx context.Context, collection *domain.Collection) error {
+	ctx, span := startSpan(ctx, "CreateCollection", attribute.String("user_id", collection.UserID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	if collection.ID == uuid.Nil {
+		collection.ID = uuid.New()
+	}
+	now := time.Now()
+	collection.CreatedAt = now
+	collection.UpdatedAt = now
+
+	_, err := s.db.ExecContext(ctx, `
+	INSERT INTO collections (id, user_id, name, description, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)`,
+		collection.ID, collection.UserID, collection.Name, collection.Description, formatTime(now), formatTime(now),
+	)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to create collection", "user_id", collection.UserID, "error", err)
+		return fmt.Errorf("ошибка при создании подборки: %w", err)
+	}
+
+	s.log(ctx).Info("collection created successfully",
+		"id", collection.ID,
+		"user_id", collection.UserID,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// GetCollectionByID получает подборку по ID
+func (s *CollectionStorage) GetCollectionByID(ctx context.Context, id uuid.UUID) (*domain.Collection, error) {
+	ctx, span := startSpan(ctx, "GetCollectionByID", attribute.String("collection_id", id.String()))
+	defer span.End()
+
+	var collection domain.Collection
+	err := s.db.GetContext(ctx, &collection, `SELECT `+collectionColumns+` FROM collections WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCollectionNotFound
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to get collection by id", "id", id, "error", err)
+		return nil, fmt.Errorf("ошибка при получении подборки по ID: %w", err)
+	}
+	return &collection, nil
+}
+
+// ListCollectionsByUser получает страницу подборок пользователя, новые первыми
+func (s *CollectionStorage) ListCollectionsByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Collection, error) {
+	ctx, span := startSpan(ctx, "ListCollectionsByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT ` + collectionColumns + ` FROM collections
+	WHERE user_id = ?
+	ORDER BY created_at DESC, id
+	LIMIT ? OFFSET ?
+	`
+
+	collections := []domain.Collection{}
+	if err := s.db.SelectContext(ctx, &collections, q, userID, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list collections", "user_id", userID, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении списка подборок: %w", err)
+	}
+
+	s.log(ctx).Info("listed collections successfully",
+		"user_id", userID,
+		"page", page,
+		"per_page", perPage,
+		"count", len(collections),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return collections, nil
+}
+
+// CountCollectionsByUser считает подборки пользователя
+func (s *CollectionStorage) CountCollectionsByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := startSpan(ctx, "CountCollectionsByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	var total int64
+	err := s.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM collections WHERE user_id = ?`, userID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to count collections", "user_id", userID, "error", err)
+		return 0, fmt.Errorf("ошибка при подсчёте подборок: %w", err)
+	}
+	return total, nil
+}
+
+// UpdateCollection обновляет только заданные в updates поля подборки
+func (s *CollectionStorage) UpdateCollection(ctx context.Context, id uuid.UUID, updates domain.CollectionUpdate) error {
+	ctx, span := startSpan(ctx, "UpdateCollection", attribute.String("collection_id", id.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	sets := []string{"updated_at = :updated_at"}
+	args := map[string]interface{}{
+		"id":         id,
+		"updated_at": formatTime(time.Now()),
+	}
+	if updates.Name != nil {
+		sets = append(sets, "name = :name")
+		args["name"] = *updates.Name
+	}
+	if updates.Description != nil {
+		sets = append(sets, "description = :description")
+		args["description"] = *updates.Description
+	}
+
+	res, err := s.db.NamedExecContext(ctx, `UPDATE collections SET `+strings.Join(sets, ", ")+` WHERE id = :id`, args)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to update collection", "id", id, "error", err)
+		return fmt.Errorf("ошибка при обновлении подборки: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrCollectionNotFound
+	}
+
+	s.log(ctx).Info("collection updated successfully",
+		"id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// DeleteCollection удаляет подборку; связи с фото удаляются каскадно, сами фото остаются
+func (s *CollectionStorage) DeleteCollection(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "DeleteCollection", attribute.String("collection_id", id.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM collections WHERE id = ?`, id)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to delete collection", "id", id, "error", err)
+		return fmt.Errorf("ошибка при удалении подборки: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrCollectionNotFound
+	}
+
+	s.log(ctx).Info("collection deleted successfully",
+		"id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// AddPhotoToCollection добавляет фото в подборку. Повторное добавление гасится ON CONFLICT
+func (s *CollectionStorage) AddPhotoToCollection(ctx context.Context, collectionID, photoID uuid.UUID) (bool, error) {
+	ctx, span := startSpan(ctx, "AddPhotoToCollection",
+		attribute.String("collection_id", collectionID.String()),
+		attribute.String("photo_id", photoID.String()),
+	)
+	defer span.End()
+
+	res, err := s.db.ExecContext(ctx, `
+	INSERT INTO collection_photos (collection_id, photo_id, added_at) VALUES (?, ?, ?)
+	ON CONFLICT (collection_id, photo_id) DO NOTHING`, collectionID, photoID, formatTime(time.Now()))
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to add photo to collection", "collection_id", collectionID, "photo_id", photoID, "error", err)
+		return false, fmt.Errorf("ошибка при добавлении фото в подборку: %w", err)
+	}
+	n, _ := res.RowsAffected()
+
+	s.log(ctx).Info("photo added to collection",
+		"collection_id", collectionID,
+		"photo_id", photoID,
+		"already_present", n == 0,
+	)
+	return n > 0, nil
+}
+
+// RemovePhotoFromCollection убирает фото из подборки, само фото не удаляется
+func (s *CollectionStorage) RemovePhotoFromCollection(ctx context.Context, collectionID, photoID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RemovePhotoFromCollection",
+		attribute.String("collection_id", collectionID.String()),
+		attribute.String("photo_id", photoID.String()),
+	)
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM collection_photos WHERE collection_id = ? AND photo_id = ?`, collectionID, photoID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to remove photo from collection", "collection_id", collectionID, "photo_id", photoID, "error", err)
+		return fmt.Errorf("ошибка при удалении фото из подборки: %w", err)
+	}
+
+	s.log(ctx).Info("photo removed from collection", "collection_id", collectionID, "photo_id", photoID)
+	return nil
+}
+
+// ListCollectionPhotos получает страницу неудалённых фото подборки, последние добавленные первыми
+func (s *CollectionStorage) ListCollectionPhotos(ctx context.Context, collectionID uuid.UUID, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListCollectionPhotos", attribute.String("collection_id", collectionID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	// у collection_photos нет колонок с именами из photoColumns, поэтому их можно не уточнять таблицей
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	JOIN collection_photos cp ON cp.photo_id = photos.id
+	WHERE cp.collection_id = ? AND photos.deleted_at IS NULL AND photos.status = 'active'
+	ORDER BY cp.added_at DESC, photos.id
+	LIMIT ? OFFSET ?
+	`
+
+	photos := []domain.Photo{}
+	if err := s.db.SelectContext(ctx, &photos, q, collectionID, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list collection photos", "collection_id", collectionID, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото подборки: %w", err)
+	}
+
+	s.log(ctx).Info("listed collection photos successfully",
+		"collection_id", collectionID,
+		"page", page,
+		"per_page", perPage,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountCollectionPhotos считает неудалённые фото подборки
+func (s *CollectionStorage) CountCollectionPhotos(ctx context.Context, collectionID uuid.UUID) (int64, error) {
+	ctx, span := startSpan(ctx, "CountCollectionPhotos", attribute.String("collection_id", collectionID.String()))
+	defer span.End()
+
+	var total int64
+	err := s.db.GetContext(ctx, &total, `
+	SELECT COUNT(*) FROM collection_photos cp
+	JOIN photos ON photos.id = cp.photo_id
+	WHERE cp.collection_id = ? AND photos.deleted_at IS NULL AND photos.status = 'active'`, collectionID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to count collection photos", "collection_id", collectionID, "error", err)
+		return 0, fmt.Errorf("ошибка при подсчёте фото подборки: %w", err)
+	}
+	return total, nil
+}
+
+func (s *CollectionStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
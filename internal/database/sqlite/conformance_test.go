@@ -0,0 +1,15 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/database/storagetest"
+)
+
+func TestStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) (ports.PhotoStorage, ports.UserStorage) {
+		db := openTestDB(t)
+		return NewPhotoStorage(db, discardLogger()), NewUserStorage(db, discardLogger())
+	})
+}
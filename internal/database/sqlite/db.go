@@ -0,0 +1,212 @@
+// Package sqlite — хранилище фото, пользователей и журнала аудита на SQLite для локальной разработки.
+// Реализует те же порты, что и internal/database/storage, но не требует запущенного PostgreSQL
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// driverName — имя драйвера modernc.org/sqlite (чистый Go, без cgo)
+const driverName = "sqlite"
+
+//go:embed schema.sql
+var schema string
+
+var tracer = tracing.Tracer("sqlite")
+
+func init() {
+	// sqlx не знает, что драйвер "sqlite" использует плейсхолдеры "?"
+	sqlx.BindDriver(driverName, sqlx.QUESTION)
+
+	// аналог функции palette_distance из миграции 012 для поиска по цвету
+	if err := sqlite.RegisterDeterministicScalarFunction("palette_distance", 2, paletteDistance); err != nil {
+		panic(err)
+	}
+	// аналог функции popularity_score из миграции 017 для пересчёта популярности
+	if err := sqlite.RegisterDeterministicScalarFunction("popularity_score", 4, popularityScore); err != nil {
+		panic(err)
+	}
+	// замена ST_Distance из PostGIS (миграция 018) для поиска фото рядом с точкой
+	if err := sqlite.RegisterDeterministicScalarFunction("distance_km", 4, distanceKm); err != nil {
+		panic(err)
+	}
+	// замена оператора <-> из pgvector (миграция 020) для поиска похожих фото
+	if err := sqlite.RegisterDeterministicScalarFunction("l2_distance", 2, l2Distance); err != nil {
+		panic(err)
+	}
+}
+
+// paletteDistance(palette, target) возвращает расстояние от target до ближайшего цвета палитры
+// или NULL, если палитра пуста
+func paletteDistance(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var palette domain.ColorPalette
+	if err := palette.Scan(args[0]); err != nil {
+		return nil, err
+	}
+	target, ok := args[1].(string)
+	if !ok {
+		return nil, nil
+	}
+	distance, ok := domain.PaletteDistance(palette, target)
+	if !ok {
+		return nil, nil
+	}
+	return distance, nil
+}
+
+// popularityScore(views, likes, downloads, age_days) считает оценку по domain.PopularityScore.
+// NULL в любом аргументе даёт NULL, как у STRICT-функции Postgres
+func popularityScore(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var nums [4]float64
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case int64:
+			nums[i] = float64(v)
+		case float64:
+			nums[i] = v
+		default:
+			return nil, nil
+		}
+	}
+	return domain.PopularityScore(int64(nums[0]), int64(nums[1]), int64(nums[2]), nums[3]), nil
+}
+
+// distanceKm(lat1, lon1, lat2, lon2) возвращает расстояние в километрах по domain.DistanceKm
+// или NULL, если у какой-то точки нет координат
+func distanceKm(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var coords [4]float64
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case int64:
+			coords[i] = float64(v)
+		case float64:
+			coords[i] = v
+		default:
+			return nil, nil
+		}
+	}
+	return domain.DistanceKm(coords[0], coords[1], coords[2], coords[3]), nil
+}
+
+// l2Distance(a, b) возвращает евклидово расстояние между векторами по domain.L2Distance
+// или NULL, если какого-то вектора нет или размерности не совпадают
+func l2Distance(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var a, b domain.Vector
+	if err := a.Scan(args[0]); err != nil {
+		return nil, err
+	}
+	if err := b.Scan(args[1]); err != nil {
+		return nil, err
+	}
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	distance, ok := domain.L2Distance(a, b)
+	if !ok {
+		return nil, nil
+	}
+	return distance, nil
+}
+
+// Open открывает базу SQLite по пути path (":memory:" — в памяти) и создаёт схему, если её нет.
+// SQLite допускает одного писателя, поэтому пул ограничен одним соединением:
+// запросы выстраиваются в очередь вместо ошибок SQLITE_BUSY
+func Open(path string, logger *slog.Logger) (*sqlx.DB, error) {
+	start := time.Now()
+
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	db, err := sqlx.Open(driverName, dsn)
+	if err != nil {
+		logger.Error("failed to open SQLite database", "path", path, "error", err)
+		return nil, fmt.Errorf("ошибка открытия базы SQLite %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		logger.Error("failed to create SQLite schema", "path", path, "error", err)
+		return nil, fmt.Errorf("ошибка создания схемы SQLite: %w", err)
+	}
+
+	logger.Info("SQLite database opened successfully",
+		"path", path,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return db, nil
+}
+
+// timeLayout — RFC 3339 с фиксированным числом знаков после запятой: все значения записываются в UTC,
+// поэтому строки сравниваются и сортируются так же, как моменты времени
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// formatTime переводит время в строку для хранения в SQLite. Читать его обратно не нужно:
+// колонки TIMESTAMP драйвер сам возвращает как time.Time
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// isUniqueViolation проверяет, что ошибка вызвана нарушением уникальности
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+// withTx выполняет fn в транзакции: коммит при успехе, откат при ошибке или панике
+func withTx(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка при открытии транзакции: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (ошибка отката транзакции: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка при фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// startSpan открывает клиентский спан запроса к SQLite
+func startSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("db.system", "sqlite"),
+		attribute.String("db.operation", operation),
+	)
+	return tracer.Start(ctx, "SQLite."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// recordDBError отмечает ошибку на спане; sql.ErrNoRows ошибкой не считается
+func recordDBError(span trace.Span, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	tracing.RecordError(span, err)
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// openTestDB открывает пустую базу во временном каталоге теста
+func openTestDB(t testing.TB) *sqlx.DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"), discardLogger())
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// newTestPhotoStorage возвращает хранилище фото на пустой базе и ID пользователя-владельца фото
+func newTestPhotoStorage(t testing.TB) (*PhotoStorage, uuid.UUID) {
+	t.Helper()
+	db := openTestDB(t)
+	userID, err := NewUserStorage(db, discardLogger()).GetOrCreateSystemUser(context.Background())
+	if err != nil {
+		t.Fatalf("create system user: %v", err)
+	}
+	return NewPhotoStorage(db, discardLogger()), userID
+}
+
+// testPhoto возвращает фото Unsplash с заполненными обязательными полями
+func testPhoto(userID uuid.UUID, unsplashID string, tags ...string) *domain.Photo {
+	photo := &domain.Photo{
+		UnsplashID:  unsplashID,
+		UserID:      userID,
+		S3URL:       "http://localhost:9000/photos/unsplash-photos/" + unsplashID,
+		Title:       "photo " + unsplashID,
+		AuthorName:  "author",
+		Width:       640,
+		Height:      480,
+		OriginalURL: "https://images.example.com/" + unsplashID,
+	}
+	for _, name := range tags {
+		photo.Tags = append(photo.Tags, domain.Tag{Name: name})
+	}
+	return photo
+}
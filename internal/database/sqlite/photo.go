@@ -0,0 +1,1442 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// photoColumns — явный список колонок photos. Колонки перечисляются без выражений (кроме unsplash_id),
+// иначе драйвер не узнает их тип TIMESTAMP и вернёт время строкой
+const photoColumns = `id, COALESCE(unsplash_id, '') AS unsplash_id, external_source, COALESCE(external_id, '') AS external_id, user_id, s3_url,
+	title, description, author_name, width, height, likes_count, original_url, uploaded_at,
+	views_count, downloads_count, unsplash_views, unsplash_downloads, created_at, updated_at, deleted_at, size_bytes, mime_type, dominant_colors,
+	popularity_score, latitude, longitude, blur_hash, dominant_color, status`
+
+// insertPhotoQuery вставляет фото; пустой unsplash_id сохраняется как NULL, чтобы не мешать уникальности
+const insertPhotoQuery = `
+	INSERT INTO photos (id, unsplash_id, external_source, user_id, s3_url, title, description, author_name, width, height,
+	                    likes_count, original_url, uploaded_at, views_count, downloads_count, created_at, updated_at,
+	                    size_bytes, mime_type, dominant_colors, latitude, longitude, blur_hash, dominant_color)
+	VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// PhotoStorage реализует ports.PhotoStorage поверх SQLite с той же семантикой, что и PostgresStorage.
+// Отличие одно: поиск идёт подстрокой (LIKE) без ранжирования, Rank всегда nil
+type PhotoStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewPhotoStorage создает новый экземпляр PhotoStorage
+func NewPhotoStorage(db *sqlx.DB, logger *slog.Logger) *PhotoStorage {
+	return &PhotoStorage{db: db, logger: logger}
+}
+
+// photoArgs — аргументы insertPhotoQuery в порядке колонок
+func photoArgs(photo *domain.Photo) []interface{} {
+	return []interface{}{
+		photo.ID, photo.UnsplashID, photo.ExternalSource, photo.UserID, photo.S3URL, photo.Title, photo.Description, photo.AuthorName,
+		photo.Width, photo.Height, photo.LikesCount, photo.OriginalURL, formatTime(photo.UploadedAt),
+		photo.ViewsCount, photo.DownloadsCount, formatTime(photo.CreatedAt), formatTime(photo.UpdatedAt),
+		photo.SizeBytes, photo.MimeType, photo.DominantColors, photo.Latitude, photo.Longitude, photo.BlurHash, photo.DominantColor,
+	}
+}
+
+// SavePhoto сохраняет метаданные фотографии в базе данных. При конфликте по unsplash_id
+// photo заполняется уже сохранённой строкой, как в PostgresStorage
+func (s *PhotoStorage) SavePhoto(ctx context.Context, photo *domain.Photo) (bool, error) {
+	ctx, span := startSpan(ctx, "SavePhoto", attribute.String("unsplash_id", photo.UnsplashID))
+	defer span.End()
+
+	start := time.Now()
+
+	if photo.ID == uuid.Nil {
+		photo.ID = uuid.New()
+	}
+	setPhotoDefaults(photo)
+
+	var id uuid.UUID
+	err := s.db.GetContext(ctx, &id, insertPhotoQuery+` ON CONFLICT (unsplash_id) DO NOTHING RETURNING id`, photoArgs(photo)...)
+	created := err == nil
+	if errors.Is(err, sql.ErrNoRows) {
+		err = s.db.GetContext(ctx, photo, `SELECT `+photoColumns+` FROM photos WHERE unsplash_id = ?`, photo.UnsplashID)
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to save photo", "unsplash_id", photo.UnsplashID, "error", err)
+		return false, fmt.Errorf("ошибка при сохранении фото: %w", err)
+	}
+
+	span.SetAttributes(attribute.Bool("photo.created", created))
+	s.log(ctx).Info("photo saved successfully",
+		"id", photo.ID,
+		"unsplash_id", photo.UnsplashID,
+		"created", created,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return created, nil
+}
+
+// SavePhotos сохраняет пачку фото вместе с тегами в одной транзакции.
+// Возвращаются ID вставленных, у них заполняются Tags; фото с уже существующим unsplash_id
+// заполняются сохранённой строкой
+func (s *PhotoStorage) SavePhotos(ctx context.Context, photos []*domain.Photo) ([]uuid.UUID, error) {
+	ctx, span := startSpan(ctx, "SavePhotos", attribute.Int("photos.count", len(photos)))
+	defer span.End()
+
+	if len(photos) == 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+
+	for _, photo := range photos {
+		if photo.ID == uuid.Nil {
+			photo.ID = uuid.New()
+		}
+		setPhotoDefaults(photo)
+	}
+
+	var inserted []uuid.UUID
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		inserted = inserted[:0]
+		for _, photo := range photos {
+			ok, err := insertPhoto(ctx, tx, photo)
+			if err != nil {
+				return fmt.Errorf("ошибка при пакетном сохранении фото: %w", err)
+			}
+			if !ok {
+				if err := tx.GetContext(ctx, photo,
+					`SELECT `+photoColumns+` FROM photos WHERE unsplash_id = ?`, photo.UnsplashID); err != nil {
+					return fmt.Errorf("ошибка при получении уже сохранённого фото: %w", err)
+				}
+				continue
+			}
+			if err := saveTags(ctx, tx, photo); err != nil {
+				return err
+			}
+			inserted = append(inserted, photo.ID)
+		}
+		return nil
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to save photos batch", "count", len(photos), "error", err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("photos.inserted", len(inserted)))
+	s.log(ctx).Info("photos batch saved",
+		"count", len(photos),
+		"inserted", len(inserted),
+		"skipped", len(photos)-len(inserted),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return inserted, nil
+}
+
+// UpsertPhoto сохраняет фото, а если фото с таким unsplash_id уже есть — обновляет только
+// изменяемые метаданные (лайки, статистику Unsplash, описание, updated_at), а также
+// blur_hash и dominant_color, если источник их вернул: у фото, сохранённых до миграции 019, их нет.
+// Собственные счётчики views_count и downloads_count не перезаписываются.
+// photo заполняется итоговой строкой из бд. Для фото в корзине возвращает domain.ErrPhotoNotFound
+func (s *PhotoStorage) UpsertPhoto(ctx context.Context, photo *domain.Photo) error {
+	ctx, span := startSpan(ctx, "UpsertPhoto", attribute.String("unsplash_id", photo.UnsplashID))
+	defer span.End()
+
+	start := time.Now()
+
+	if photo.ID == uuid.Nil {
+		photo.ID = uuid.New()
+	}
+	setPhotoDefaults(photo)
+
+	query := insertPhotoQuery + `
+	ON CONFLICT (unsplash_id) DO UPDATE SET
+		likes_count        = excluded.likes_count,
+		unsplash_views     = ?,
+		unsplash_downloads = ?,
+		description        = excluded.description,
+		blur_hash          = COALESCE(NULLIF(excluded.blur_hash, ''), photos.blur_hash),
+		dominant_color     = COALESCE(NULLIF(excluded.dominant_color, ''), photos.dominant_color),
+		updated_at         = ?
+	WHERE photos.deleted_at IS NULL AND photos.status = 'active'
+	RETURNING id`
+
+	var id uuid.UUID
+	err := s.db.GetContext(ctx, &id, query, append(photoArgs(photo), photo.UnsplashViews, photo.UnsplashDownloads, formatTime(time.Now()))...)
+	if errors.Is(err, sql.ErrNoRows) {
+		// конфликт с фото из корзины: WHERE не дал обновить строку
+		s.log(ctx).Warn("photo is deleted, upsert skipped", "unsplash_id", photo.UnsplashID)
+		return domain.ErrPhotoNotFound
+	}
+	if err == nil {
+		err = s.db.GetContext(ctx, photo, `SELECT `+photoColumns+` FROM photos WHERE id = ?`, id)
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to upsert photo", "unsplash_id", photo.UnsplashID, "error", err)
+		return fmt.Errorf("ошибка при сохранении или обновлении фото: %w", err)
+	}
+
+	s.log(ctx).Info("photo upserted",
+		"id", photo.ID,
+		"unsplash_id", photo.UnsplashID,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// SavePhotoTx сохраняет фото вместе с тегами в одной транзакции.
+// Если фото с таким unsplash_id уже есть, ничего не меняется: возвращается false,
+// а photo заполняется сохранённой строкой вместе с её тегами
+func (s *PhotoStorage) SavePhotoTx(ctx context.Context, photo *domain.Photo) (bool, error) {
+	ctx, span := startSpan(ctx, "SavePhotoTx", attribute.String("unsplash_id", photo.UnsplashID))
+	defer span.End()
+
+	start := time.Now()
+
+	if photo.ID == uuid.Nil {
+		photo.ID = uuid.New()
+	}
+	setPhotoDefaults(photo)
+
+	var inserted bool
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		var err error
+		inserted, err = insertPhoto(ctx, tx, photo)
+		if err != nil {
+			return fmt.Errorf("ошибка при сохранении фото: %w", err)
+		}
+		if !inserted {
+			var existing domain.Photo
+			if err := tx.GetContext(ctx, &existing,
+				`SELECT `+photoColumns+` FROM photos WHERE unsplash_id = ?`, photo.UnsplashID); err != nil {
+				return fmt.Errorf("ошибка при получении уже сохранённого фото: %w", err)
+			}
+			*photo = existing
+			return nil
+		}
+		return saveTags(ctx, tx, photo)
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to save photo in transaction", "unsplash_id", photo.UnsplashID, "error", err)
+		return false, err
+	}
+
+	if !inserted {
+		existing := []domain.Photo{*photo}
+		if err := s.attachTags(ctx, existing); err != nil {
+			return false, err
+		}
+		*photo = existing[0]
+		s.log(ctx).Warn("photo already exists, nothing saved", "id", photo.ID, "unsplash_id", photo.UnsplashID)
+		return false, nil
+	}
+
+	s.log(ctx).Info("photo saved with tags",
+		"id", photo.ID,
+		"unsplash_id", photo.UnsplashID,
+		"tags", len(photo.Tags),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return true, nil
+}
+
+// insertPhoto вставляет фото и сообщает, было ли оно вставлено (false — конфликт по unsplash_id)
+func insertPhoto(ctx context.Context, tx *sqlx.Tx, photo *domain.Photo) (bool, error) {
+	res, err := tx.ExecContext(ctx, insertPhotoQuery+` ON CONFLICT (unsplash_id) DO NOTHING`, photoArgs(photo)...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// saveTags добавляет недостающие теги фото и связи photo_tags; photo.Tags заменяется сохранёнными тегами
+func saveTags(ctx context.Context, tx *sqlx.Tx, photo *domain.Photo) error {
+	names := normalizeTagNames(photo.Tags)
+	if len(names) == 0 {
+		return nil
+	}
+
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO tags (id, name) VALUES (?, ?) ON CONFLICT (name) DO NOTHING`, uuid.New(), name); err != nil {
+			return fmt.Errorf("ошибка при сохранении тегов: %w", err)
+		}
+	}
+
+	q, args, err := sqlx.In(`SELECT id, name FROM tags WHERE name IN (?)`, names)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении тегов: %w", err)
+	}
+	var tags []domain.Tag
+	if err := tx.SelectContext(ctx, &tags, tx.Rebind(q), args...); err != nil {
+		return fmt.Errorf("ошибка при получении тегов: %w", err)
+	}
+
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO photo_tags (photo_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, photo.ID, tag.ID); err != nil {
+			return fmt.Errorf("ошибка при связывании фото с тегами: %w", err)
+		}
+	}
+
+	photo.Tags = tags
+	return nil
+}
+
+// setPhotoDefaults заполняет незаданные created_at, updated_at и uploaded_at текущим временем;
+// источник по умолчанию — Unsplash. Повторяет одноимённую функцию хранилища PostgreSQL
+func setPhotoDefaults(photo *domain.Photo) {
+	if photo.ExternalSource == "" {
+		photo.ExternalSource = domain.SourceUnsplash
+	}
+	now := time.Now()
+	if photo.CreatedAt.IsZero() {
+		photo.CreatedAt = now
+	}
+	if photo.UpdatedAt.IsZero() {
+		photo.UpdatedAt = now
+	}
+	if photo.UploadedAt.IsZero() {
+		photo.UploadedAt = now
+	}
+	// колонка status в INSERT не передаётся и по умолчанию active; pending пишет только CreateUploadIntent
+	if photo.Status == "" {
+		photo.Status = domain.PhotoStatusActive
+	}
+}
+
+// normalizeTagNames приводит имена тегов к нижнему регистру, убирает пустые, дубликаты и длиннее 50 символов
+func normalizeTagNames(tags []domain.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return normalizeNames(names)
+}
+
+// normalizeNames — normalizeTagNames для имён без обёртки domain.Tag
+func normalizeNames(raw []string) []string {
+	seen := make(map[string]struct{}, len(raw))
+	names := make([]string, 0, len(raw))
+	for _, name := range raw {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || utf8.RuneCountInString(name) > 50 {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// GetPhotoByIDFromDB получает детали фото по ID
+func (s *PhotoStorage) GetPhotoByIDFromDB(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotoByIDFromDB", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	return s.getPhoto(ctx, span, "id", id)
+}
+
+// GetPhotoByIDIncludingDeleted получает фото по ID, в том числе из корзины
+func (s *PhotoStorage) GetPhotoByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotoByIDIncludingDeleted", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	var photo domain.Photo
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE id = ? AND status = 'active' LIMIT 1`
+
+	err := s.db.GetContext(ctx, &photo, query, id)
+	recordDBError(span, err)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log(ctx).Warn("photo not found by id", "id", id, "include_deleted", true)
+			return nil, nil
+		}
+		s.log(ctx).Error("failed to get photo by id", "id", id, "include_deleted", true, "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото по id: %w", err)
+	}
+
+	s.log(ctx).Info("photo retrieved by id",
+		"id", id,
+		"deleted", photo.DeletedAt != nil,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return &photo, nil
+}
+
+// GetPhotosByUnsplashIDFromDB получает фото по Unsplash ID
+func (s *PhotoStorage) GetPhotosByUnsplashIDFromDB(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotosByUnsplashIDFromDB", attribute.String("unsplash_id", unsplashID))
+	defer span.End()
+
+	return s.getPhoto(ctx, span, "unsplash_id", unsplashID)
+}
+
+// getPhoto получает неудалённое фото по значению колонки column; если фото нет, возвращает nil, nil
+func (s *PhotoStorage) getPhoto(ctx context.Context, span trace.Span, column string, value interface{}) (*domain.Photo, error) {
+	start := time.Now()
+
+	var photo domain.Photo
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE ` + column + ` = ? AND deleted_at IS NULL AND status = 'active' LIMIT 1`
+
+	err := s.db.GetContext(ctx, &photo, query, value)
+	recordDBError(span, err)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log(ctx).Warn("photo not found by "+column, column, value)
+			return nil, nil
+		}
+		s.log(ctx).Error("failed to get photo by "+column, column, value, "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото по %s: %w", column, err)
+	}
+
+	s.log(ctx).Info("photo retrieved by "+column,
+		column, value,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return &photo, nil
+}
+
+// ExistsByUnsplashIDs одним запросом проверяет, какие из переданных Unsplash ID уже есть в БД.
+// Мягко удалённые фото тоже считаются существующими
+func (s *PhotoStorage) ExistsByUnsplashIDs(ctx context.Context, unsplashIDs []string) (map[string]uuid.UUID, error) {
+	ctx, span := startSpan(ctx, "ExistsByUnsplashIDs", attribute.Int("ids.count", len(unsplashIDs)))
+	defer span.End()
+
+	start := time.Now()
+
+	existing := make(map[string]uuid.UUID, len(unsplashIDs))
+	if len(unsplashIDs) == 0 {
+		return existing, nil
+	}
+
+	var rows []struct {
+		UnsplashID string    `db:"unsplash_id"`
+		ID         uuid.UUID `db:"id"`
+	}
+	err := s.selectIn(ctx, &rows, `SELECT unsplash_id, id FROM photos WHERE unsplash_id IN (?)`, unsplashIDs)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to check photos existence", "count", len(unsplashIDs), "error", err)
+		return nil, fmt.Errorf("ошибка при проверке существования фото: %w", err)
+	}
+	for _, row := range rows {
+		existing[row.UnsplashID] = row.ID
+	}
+
+	s.log(ctx).Info("photos existence checked",
+		"requested", len(unsplashIDs),
+		"existing", len(existing),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return existing, nil
+}
+
+// FindPhotosByUnsplashIDs получает фото по списку Unsplash ID одним запросом, включая фото в корзине
+func (s *PhotoStorage) FindPhotosByUnsplashIDs(ctx context.Context, unsplashIDs []string) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "FindPhotosByUnsplashIDs", attribute.Int("ids.count", len(unsplashIDs)))
+	defer span.End()
+
+	start := time.Now()
+
+	var photos []domain.Photo
+	if len(unsplashIDs) == 0 {
+		return photos, nil
+	}
+
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE unsplash_id IN (?)`
+	if err := s.selectIn(ctx, &photos, query, unsplashIDs); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to find photos by unsplash_ids", "count", len(unsplashIDs), "error", err)
+		return nil, fmt.Errorf("ошибка при поиске фото по списку Unsplash ID: %w", err)
+	}
+
+	s.log(ctx).Info("photos found by unsplash_ids",
+		"requested", len(unsplashIDs),
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// GetPhotosByUnsplashIDsFromDB получает фото по списку Unsplash ID одним запросом
+func (s *PhotoStorage) GetPhotosByUnsplashIDsFromDB(ctx context.Context, unsplashIDs []string) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotosByUnsplashIDsFromDB", attribute.Int("ids.count", len(unsplashIDs)))
+	defer span.End()
+
+	start := time.Now()
+
+	var photos []domain.Photo
+	if len(unsplashIDs) == 0 {
+		return photos, nil
+	}
+
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE unsplash_id IN (?) AND deleted_at IS NULL AND status = 'active'`
+	if err := s.selectIn(ctx, &photos, query, unsplashIDs); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to get photos by unsplash_ids", "count", len(unsplashIDs), "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото по списку Unsplash ID: %w", err)
+	}
+
+	s.log(ctx).Info("photos retrieved by unsplash_ids",
+		"requested", len(unsplashIDs),
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// BatchUpdateTags в одной транзакции снимает с фото теги removeTags и добавляет addTags.
+// Фото в корзине не меняются; у изменённых фото обновляется updated_at
+func (s *PhotoStorage) BatchUpdateTags(ctx context.Context, photoIDs []uuid.UUID, addTags, removeTags []string) (int64, error) {
+	ctx, span := startSpan(ctx, "BatchUpdateTags",
+		attribute.Int("ids.count", len(photoIDs)),
+		attribute.Int("tags.add", len(addTags)),
+		attribute.Int("tags.remove", len(removeTags)),
+	)
+	defer span.End()
+
+	start := time.Now()
+
+	add, remove := normalizeNames(addTags), normalizeNames(removeTags)
+	if len(photoIDs) == 0 || (len(add) == 0 && len(remove) == 0) {
+		return 0, nil
+	}
+
+	var updated []uuid.UUID
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		q, args, err := sqlx.In(`UPDATE photos SET updated_at = ? WHERE id IN (?) AND deleted_at IS NULL AND status = 'active' RETURNING id`,
+			formatTime(time.Now()), photoIDs)
+		if err != nil {
+			return err
+		}
+		if err := tx.SelectContext(ctx, &updated, tx.Rebind(q), args...); err != nil {
+			return fmt.Errorf("ошибка при обновлении фото: %w", err)
+		}
+		if len(updated) == 0 {
+			return nil
+		}
+
+		if len(remove) > 0 {
+			q, args, err := sqlx.In(`
+			DELETE FROM photo_tags
+			WHERE photo_id IN (?) AND tag_id IN (SELECT id FROM tags WHERE name IN (?))`, updated, remove)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, tx.Rebind(q), args...); err != nil {
+				return fmt.Errorf("ошибка при удалении тегов фото: %w", err)
+			}
+		}
+
+		if len(add) > 0 {
+			for _, name := range add {
+				if _, err := tx.ExecContext(ctx,
+					`INSERT INTO tags (id, name) VALUES (?, ?) ON CONFLICT (name) DO NOTHING`, uuid.New(), name); err != nil {
+					return fmt.Errorf("ошибка при сохранении тегов: %w", err)
+				}
+			}
+			// WHERE обязателен: без него SQLite не отличает ON CONFLICT от условия соединения
+			q, args, err := sqlx.In(`
+			INSERT INTO photo_tags (photo_id, tag_id)
+			SELECT p.id, t.id FROM photos p CROSS JOIN tags t
+			WHERE p.id IN (?) AND t.name IN (?)
+			ON CONFLICT DO NOTHING`, updated, add)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, tx.Rebind(q), args...); err != nil {
+				return fmt.Errorf("ошибка при связывании фото с тегами: %w", err)
+			}
+		}
+		return nil
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to batch update tags", "count", len(photoIDs), "error", err)
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("photos.updated", len(updated)))
+	s.log(ctx).Info("photo tags batch updated",
+		"requested", len(photoIDs),
+		"updated", len(updated),
+		"added", len(add),
+		"removed", len(remove),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return int64(len(updated)), nil
+}
+
+// GetPhotosByIDs получает фото по списку внутренних ID одним запросом
+func (s *PhotoStorage) GetPhotosByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotosByIDs", attribute.Int("ids.count", len(ids)))
+	defer span.End()
+
+	start := time.Now()
+
+	var photos []domain.Photo
+	if len(ids) == 0 {
+		return photos, nil
+	}
+
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE id IN (?) AND deleted_at IS NULL AND status = 'active'`
+	if err := s.selectIn(ctx, &photos, query, ids); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to get photos by ids", "count", len(ids), "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото по списку ID: %w", err)
+	}
+
+	s.log(ctx).Info("photos retrieved by ids",
+		"requested", len(ids),
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// selectIn выполняет SELECT с условием IN (?), раскрывая список list в отдельные параметры
+func (s *PhotoStorage) selectIn(ctx context.Context, dest interface{}, query string, list interface{}) error {
+	q, args, err := sqlx.In(query, list)
+	if err != nil {
+		return err
+	}
+	return s.db.SelectContext(ctx, dest, s.db.Rebind(q), args...)
+}
+
+// likeEscaper экранирует спецсимволы LIKE, чтобы текст искался буквально (ESCAPE '\')
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// AutocompletePhotoTitles ищет подсказки по префиксу среди названий фото и имён авторов.
+// LOWER в SQLite меняет регистр только у латиницы
+func (s *PhotoStorage) AutocompletePhotoTitles(ctx context.Context, prefix string, limit int) ([]string, error) {
+	ctx, span := startSpan(ctx, "AutocompletePhotoTitles", attribute.String("prefix", prefix), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT suggestion FROM (
+		SELECT title AS suggestion FROM photos
+		WHERE deleted_at IS NULL AND status = 'active' AND LOWER(title) LIKE LOWER(?1) || '%' ESCAPE '\'
+		UNION
+		SELECT author_name FROM photos
+		WHERE deleted_at IS NULL AND status = 'active' AND LOWER(author_name) LIKE LOWER(?1) || '%' ESCAPE '\'
+	)
+	ORDER BY suggestion
+	LIMIT ?2
+	`
+
+	suggestions := []string{}
+	if err := s.db.SelectContext(ctx, &suggestions, q, likeEscaper.Replace(prefix), limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to autocomplete photo titles", "prefix", prefix, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске подсказок: %w", err)
+	}
+
+	s.log(ctx).Info("autocomplete suggestions found",
+		"prefix", prefix,
+		"count", len(suggestions),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return suggestions, nil
+}
+
+// GetTagCloud считает фото вне корзины по каждому тегу, самые частые теги первыми
+func (s *PhotoStorage) GetTagCloud(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	ctx, span := startSpan(ctx, "GetTagCloud", attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT t.id, t.name, COUNT(pt.photo_id) AS count
+	FROM tags t
+	JOIN photo_tags pt ON pt.tag_id = t.id
+	JOIN photos p ON p.id = pt.photo_id AND p.deleted_at IS NULL AND p.status = 'active'
+	GROUP BY t.id
+	ORDER BY count DESC, t.name
+	LIMIT ?
+	`
+
+	tags := []domain.TagCount{}
+	if err := s.db.SelectContext(ctx, &tags, q, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to get tag cloud", "limit", limit, "error", err)
+		return nil, fmt.Errorf("ошибка при получении облака тегов: %w", err)
+	}
+
+	s.log(ctx).Info("tag cloud fetched",
+		"count", len(tags),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return tags, nil
+}
+
+// SearchTagsByPrefix ищет теги по префиксу названия.
+// LOWER в SQLite меняет регистр только у латиницы
+func (s *PhotoStorage) SearchTagsByPrefix(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	ctx, span := startSpan(ctx, "SearchTagsByPrefix", attribute.String("prefix", prefix), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT id, name FROM tags
+	WHERE LOWER(name) LIKE LOWER(?1) || '%' ESCAPE '\'
+	ORDER BY name
+	LIMIT ?2
+	`
+
+	tags := []domain.Tag{}
+	if err := s.db.SelectContext(ctx, &tags, q, likeEscaper.Replace(prefix), limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to search tags", "prefix", prefix, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске тегов: %w", err)
+	}
+
+	s.log(ctx).Info("tags found by prefix",
+		"prefix", prefix,
+		"count", len(tags),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return tags, nil
+}
+
+// SearchPhotosInDB ищет фото подстрокой в названии, описании и имени автора.
+// Ранжирования нет, поэтому без явной сортировки новые загрузки идут первыми
+func (s *PhotoStorage) SearchPhotosInDB(ctx context.Context, query string, page, perPage int, filter domain.PhotoSearchFilter, sort domain.PhotoSort) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "SearchPhotosInDB", attribute.String("query", query), attribute.String("sort", sort.Field))
+	defer span.End()
+
+	start := time.Now()
+
+	orderBy, err := orderByClause(sort, "uploaded_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, err := searchConditions(query, filter)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, perPage, (page-1)*perPage)
+	q := "SELECT " + photoColumns + " FROM photos WHERE " + where + " ORDER BY " + orderBy + " LIMIT ? OFFSET ?"
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, args...); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to search photos",
+			"query", query,
+			"page", page,
+			"per_page", perPage,
+			"error", err,
+		)
+		return nil, fmt.Errorf("ошибка при поиске фото: %w", err)
+	}
+
+	s.log(ctx).Info("photos search completed",
+		"query", query,
+		"min_likes", filter.MinLikes,
+		"min_width", filter.MinWidth,
+		"min_height", filter.MinHeight,
+		"author", filter.Author,
+		"orientation", filter.Orientation,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// searchConditions собирает условие WHERE и аргументы поиска, общие для SearchPhotosInDB и CountSearchResults.
+// LIKE в SQLite без учёта регистра только для латиницы.
+// Некорректный фильтр возвращает ошибку, оборачивающую domain.ErrInvalidFilter
+func searchConditions(query string, filter domain.PhotoSearchFilter) (string, []interface{}, error) {
+	if err := filter.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	where := `deleted_at IS NULL AND status = 'active'
+	  AND (title LIKE ?1 ESCAPE '\'
+	   OR description LIKE ?1 ESCAPE '\'
+	   OR author_name LIKE ?1 ESCAPE '\')`
+	args := []interface{}{"%" + likeEscaper.Replace(query) + "%"}
+
+	if filter.MinLikes > 0 {
+		args = append(args, filter.MinLikes)
+		where += fmt.Sprintf(" AND likes_count >= ?%d", len(args))
+	}
+	if filter.MinWidth > 0 {
+		args = append(args, filter.MinWidth)
+		where += fmt.Sprintf(" AND width >= ?%d", len(args))
+	}
+	if filter.MinHeight > 0 {
+		args = append(args, filter.MinHeight)
+		where += fmt.Sprintf(" AND height >= ?%d", len(args))
+	}
+	if filter.Author != "" {
+		args = append(args, filter.Author)
+		where += fmt.Sprintf(" AND LOWER(author_name) = LOWER(?%d)", len(args))
+	}
+	if filter.Orientation != "" {
+		where += " AND " + orientationConditions[filter.Orientation]
+	}
+	if filter.From != nil {
+		args = append(args, formatTime(*filter.From))
+		where += fmt.Sprintf(" AND uploaded_at >= ?%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, formatTime(*filter.To))
+		where += fmt.Sprintf(" AND uploaded_at < ?%d", len(args))
+	}
+	return where, args, nil
+}
+
+// orientationConditions — условие на размеры фото для каждой ориентации фильтра поиска
+var orientationConditions = map[string]string{
+	domain.OrientationLandscape: "width > height",
+	domain.OrientationPortrait:  "height > width",
+	domain.OrientationSquarish:  "width = height",
+}
+
+// CountSearchResults считает все фото, подходящие под поиск (без пагинации)
+func (s *PhotoStorage) CountSearchResults(ctx context.Context, query string, filter domain.PhotoSearchFilter) (int64, error) {
+	ctx, span := startSpan(ctx, "CountSearchResults", attribute.String("query", query))
+	defer span.End()
+
+	where, args, err := searchConditions(query, filter)
+	if err != nil {
+		return 0, err
+	}
+	return s.count(ctx, span, "SELECT COUNT(*) FROM photos WHERE "+where, args...)
+}
+
+// colorSearchCondition — фото с цветом палитры не дальше ?2 от ?1; palette_distance регистрируется в db.go
+const colorSearchCondition = `deleted_at IS NULL AND status = 'active' AND palette_distance(dominant_colors, ?1) <= ?2`
+
+// SearchPhotosByColor ищет фото по цвету палитры, самые близкие по цвету первыми
+func (s *PhotoStorage) SearchPhotosByColor(ctx context.Context, color string, maxDistance float64, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "SearchPhotosByColor", attribute.String("color", color), attribute.Float64("max_distance", maxDistance))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `SELECT ` + photoColumns + ` FROM photos WHERE ` + colorSearchCondition + `
+	ORDER BY palette_distance(dominant_colors, ?1), uploaded_at DESC, id LIMIT ?3 OFFSET ?4`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, color, maxDistance, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to search photos by color", "color", color, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске фото по цвету: %w", err)
+	}
+
+	s.log(ctx).Info("photos color search completed",
+		"color", color,
+		"max_distance", maxDistance,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountPhotosByColor считает фото, подходящие под SearchPhotosByColor
+func (s *PhotoStorage) CountPhotosByColor(ctx context.Context, color string, maxDistance float64) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosByColor", attribute.String("color", color))
+	defer span.End()
+
+	return s.count(ctx, span, "SELECT COUNT(*) FROM photos WHERE "+colorSearchCondition, color, maxDistance)
+}
+
+// nearCondition — фото не дальше ?3 км от точки (?1 — широта, ?2 — долгота); distance_km регистрируется в db.go
+const nearCondition = `deleted_at IS NULL AND status = 'active' AND latitude IS NOT NULL AND longitude IS NOT NULL
+	AND distance_km(latitude, longitude, ?1, ?2) <= ?3`
+
+// FindPhotosNear ищет фото рядом с точкой, ближайшие первыми
+func (s *PhotoStorage) FindPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "FindPhotosNear",
+		attribute.Float64("lat", lat), attribute.Float64("lon", lon), attribute.Float64("radius_km", radiusKm))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `SELECT ` + photoColumns + ` FROM photos WHERE ` + nearCondition + `
+	ORDER BY distance_km(latitude, longitude, ?1, ?2), id LIMIT ?4 OFFSET ?5`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, lat, lon, radiusKm, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to find photos near point", "lat", lat, "lon", lon, "radius_km", radiusKm, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске фото рядом с точкой: %w", err)
+	}
+
+	s.log(ctx).Info("photos near point found",
+		"lat", lat,
+		"lon", lon,
+		"radius_km", radiusKm,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountPhotosNear считает фото, подходящие под FindPhotosNear
+func (s *PhotoStorage) CountPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosNear", attribute.Float64("radius_km", radiusKm))
+	defer span.End()
+
+	return s.count(ctx, span, "SELECT COUNT(*) FROM photos WHERE "+nearCondition, lat, lon, radiusKm)
+}
+
+// UpdatePhotoEmbedding сохраняет эмбеддинг фото. updated_at не меняется: эмбеддинг не виден в карточке фото
+func (s *PhotoStorage) UpdatePhotoEmbedding(ctx context.Context, id uuid.UUID, vector []float32) error {
+	ctx, span := startSpan(ctx, "UpdatePhotoEmbedding", attribute.String("photo_id", id.String()), attribute.Int("dimensions", len(vector)))
+	defer span.End()
+
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE photos SET embedding = ? WHERE id = ? AND deleted_at IS NULL AND status = 'active'`,
+		domain.Vector(vector), id)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to update photo embedding", "id", id, "error", err)
+		return fmt.Errorf("ошибка при сохранении эмбеддинга фото %s: %w", id, err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		s.log(ctx).Warn("photo not found for embedding update", "id", id)
+		return domain.ErrPhotoNotFound
+	}
+
+	s.log(ctx).Info("photo embedding updated",
+		"id", id,
+		"dimensions", len(vector),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// GetPhotoEmbedding возвращает эмбеддинг фото или nil, если он ещё не вычислен
+func (s *PhotoStorage) GetPhotoEmbedding(ctx context.Context, id uuid.UUID) ([]float32, error) {
+	ctx, span := startSpan(ctx, "GetPhotoEmbedding", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	var vector domain.Vector
+	err := s.db.GetContext(ctx, &vector, `SELECT embedding FROM photos WHERE id = ? AND deleted_at IS NULL AND status = 'active'`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPhotoNotFound
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to get photo embedding", "id", id, "error", err)
+		return nil, fmt.Errorf("ошибка при получении эмбеддинга фото %s: %w", id, err)
+	}
+	return vector, nil
+}
+
+// FindSimilarPhotos ищет фото, ближайшие к vector по евклидову расстоянию (функция l2_distance).
+// Индекса нет: расстояние считается для каждого фото с эмбеддингом
+func (s *PhotoStorage) FindSimilarPhotos(ctx context.Context, vector []float32, excludeID uuid.UUID, limit int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "FindSimilarPhotos", attribute.String("photo_id", excludeID.String()), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NULL AND status = 'active' AND embedding IS NOT NULL AND id <> ?2
+	ORDER BY l2_distance(embedding, ?1), id
+	LIMIT ?3
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, domain.Vector(vector), excludeID, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to find similar photos", "photo_id", excludeID, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске похожих фото: %w", err)
+	}
+
+	s.log(ctx).Info("similar photos found",
+		"photo_id", excludeID,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountPhotosInDB считает фото, не находящиеся в корзине
+func (s *PhotoStorage) CountPhotosInDB(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosInDB")
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NULL AND status = 'active'`)
+}
+
+// CountDeletedPhotosInDB считает фото в корзине
+func (s *PhotoStorage) CountDeletedPhotosInDB(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "CountDeletedPhotosInDB")
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NOT NULL`)
+}
+
+// CountPhotosByAuthor считает фото автора, не находящиеся в корзине
+func (s *PhotoStorage) CountPhotosByAuthor(ctx context.Context, authorName string) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosByAuthor", attribute.String("author_name", authorName))
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NULL AND status = 'active' AND LOWER(author_name) = LOWER(?)`, authorName)
+}
+
+// CountPhotosByUser считает фото пользователя, не находящиеся в корзине
+func (s *PhotoStorage) CountPhotosByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NULL AND status = 'active' AND user_id = ?`, userID)
+}
+
+// count выполняет запрос SELECT COUNT(*) и логирует результат
+func (s *PhotoStorage) count(ctx context.Context, span trace.Span, q string, args ...interface{}) (int64, error) {
+	start := time.Now()
+
+	var total int64
+	err := s.db.GetContext(ctx, &total, q, args...)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to count photos", "error", err)
+		return 0, fmt.Errorf("ошибка при подсчёте фото: %w", err)
+	}
+
+	s.log(ctx).Debug("photos counted",
+		"total", total,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return total, nil
+}
+
+// ListAllPhotosInDB получает все фото, последние загруженные первыми
+func (s *PhotoStorage) ListAllPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListAllPhotosInDB")
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, "deleted_at IS NULL AND status = 'active'", "uploaded_at DESC", page, perPage)
+}
+
+// ListPhotosInDB получает страницу фотографий. Без явной сортировки — новые первыми
+func (s *PhotoStorage) ListPhotosInDB(ctx context.Context, page, perPage int, sort domain.PhotoSort) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListPhotosInDB", attribute.String("sort", sort.Field))
+	defer span.End()
+
+	orderBy, err := orderByClause(sort, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	return s.listPhotosPage(ctx, span, "deleted_at IS NULL AND status = 'active'", orderBy, page, perPage)
+}
+
+// ListPhotosWithTagsInDB — как ListPhotosInDB, но с тегами, загруженными одним запросом на страницу
+func (s *PhotoStorage) ListPhotosWithTagsInDB(ctx context.Context, page, perPage int, sort domain.PhotoSort) ([]domain.Photo, error) {
+	photos, err := s.ListPhotosInDB(ctx, page, perPage, sort)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// ListPhotosByAuthor получает страницу фото автора. Имя сравнивается целиком, без учёта регистра
+func (s *PhotoStorage) ListPhotosByAuthor(ctx context.Context, authorName string, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListPhotosByAuthor", attribute.String("author_name", authorName))
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, "deleted_at IS NULL AND status = 'active' AND LOWER(author_name) = LOWER(?)", "created_at DESC", page, perPage, authorName)
+}
+
+// ListPhotosByUser получает страницу фото, сохранённых пользователем
+func (s *PhotoStorage) ListPhotosByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListPhotosByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, "deleted_at IS NULL AND status = 'active' AND user_id = ?", "created_at DESC", page, perPage, userID)
+}
+
+// ListDeletedPhotosInDB получает мягко удалённые фото (корзину), последние удалённые первыми
+func (s *PhotoStorage) ListDeletedPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListDeletedPhotosInDB")
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, "deleted_at IS NOT NULL", "deleted_at DESC", page, perPage)
+}
+
+// listPhotosPage получает страницу фото по условию where с параметрами args в порядке orderBy
+func (s *PhotoStorage) listPhotosPage(ctx context.Context, span trace.Span, where, orderBy string, page, perPage int, args ...interface{}) ([]domain.Photo, error) {
+	start := time.Now()
+
+	q := `SELECT ` + photoColumns + ` FROM photos WHERE ` + where + ` ORDER BY ` + orderBy + ` LIMIT ? OFFSET ?`
+	args = append(args, perPage, (page-1)*perPage)
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, args...); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list photos", "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении списка фото: %w", err)
+	}
+
+	s.log(ctx).Info("listed photos successfully",
+		"page", page,
+		"per_page", perPage,
+		"order_by", orderBy,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// photoSortColumns — белый список колонок для ORDER BY
+var photoSortColumns = map[string]string{
+	domain.SortByCreatedAt:  "created_at",
+	domain.SortByUploadedAt: "uploaded_at",
+	domain.SortByLikes:      "likes_count",
+	domain.SortByViews:      "views_count",
+	domain.SortByDownloads:  "downloads_count",
+	domain.SortByPopularity: "popularity_score",
+}
+
+// orderByClause собирает выражение ORDER BY для sort или возвращает defaultOrder для нулевой сортировки
+func orderByClause(sort domain.PhotoSort, defaultOrder string) (string, error) {
+	if sort.IsZero() {
+		return defaultOrder, nil
+	}
+	column, ok := photoSortColumns[sort.Field]
+	if !ok {
+		return "", fmt.Errorf("%w: неизвестное поле %q", domain.ErrInvalidSort, sort.Field)
+	}
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+	return column + " " + direction + " NULLS LAST, id " + direction, nil
+}
+
+// exportRow — строка выгрузки: фото и JSON-массив имён его тегов, собранный подзапросом
+type exportRow struct {
+	domain.Photo
+	TagNames string `db:"tag_names"`
+}
+
+// StreamPhotosFromDB отправляет в out фото из выборки filter, читая строки курсором
+func (s *PhotoStorage) StreamPhotosFromDB(ctx context.Context, filter domain.PhotoFilter, out chan<- domain.Photo) error {
+	ctx, span := startSpan(ctx, "StreamPhotosFromDB", attribute.String("query", filter.Query))
+	defer span.End()
+
+	start := time.Now()
+
+	where, args := "deleted_at IS NULL AND status = 'active'", []interface{}{}
+	if filter.Query != "" {
+		// нулевой фильтр всегда корректен
+		where, args, _ = searchConditions(filter.Query, domain.PhotoSearchFilter{})
+	}
+	if filter.From != nil {
+		args = append(args, formatTime(*filter.From))
+		where += fmt.Sprintf(" AND uploaded_at >= ?%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, formatTime(*filter.To))
+		where += fmt.Sprintf(" AND uploaded_at < ?%d", len(args))
+	}
+	q := `
+	SELECT ` + photoColumns + `,
+	       (SELECT json_group_array(name) FROM (
+	            SELECT t.name FROM photo_tags pt JOIN tags t ON t.id = pt.tag_id
+	            WHERE pt.photo_id = photos.id ORDER BY t.name)) AS tag_names
+	FROM photos
+	WHERE ` + where + `
+	ORDER BY uploaded_at, id
+	`
+
+	rows, err := s.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to start photo export", "error", err)
+		return fmt.Errorf("ошибка при выгрузке фото: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var (
+			row      exportRow
+			tagNames []string
+		)
+		if err := rows.StructScan(&row); err != nil {
+			recordDBError(span, err)
+			s.log(ctx).Error("failed to scan exported photo", "exported", count, "error", err)
+			return fmt.Errorf("ошибка при чтении выгружаемого фото: %w", err)
+		}
+		if err := json.Unmarshal([]byte(row.TagNames), &tagNames); err != nil {
+			return fmt.Errorf("ошибка при чтении тегов выгружаемого фото %s: %w", row.ID, err)
+		}
+		for _, name := range tagNames {
+			row.Photo.Tags = append(row.Photo.Tags, domain.Tag{Name: name})
+		}
+		select {
+		case out <- row.Photo:
+			count++
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("photo export interrupted", "exported", count, "error", err)
+		return fmt.Errorf("ошибка при выгрузке фото: %w", err)
+	}
+
+	s.log(ctx).Info("photos exported",
+		"query", filter.Query,
+		"count", count,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// attachTags загружает теги для всех переданных фото одним запросом и раскладывает их в памяти
+func (s *PhotoStorage) attachTags(ctx context.Context, photos []domain.Photo) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	start := time.Now()
+
+	ids := make([]uuid.UUID, 0, len(photos))
+	index := make(map[uuid.UUID]int, len(photos))
+	for i, p := range photos {
+		ids = append(ids, p.ID)
+		index[p.ID] = i
+	}
+
+	var rows []struct {
+		PhotoID uuid.UUID `db:"photo_id"`
+		domain.Tag
+	}
+	q := `
+	SELECT pt.photo_id, t.id, t.name
+	FROM photo_tags pt
+	JOIN tags t ON t.id = pt.tag_id
+	WHERE pt.photo_id IN (?)
+	ORDER BY t.name
+	`
+	if err := s.selectIn(ctx, &rows, q, ids); err != nil {
+		s.log(ctx).Error("failed to load tags for photos", "count", len(photos), "error", err)
+		return fmt.Errorf("ошибка при получении тегов фото: %w", err)
+	}
+	for _, row := range rows {
+		if i, ok := index[row.PhotoID]; ok {
+			photos[i].Tags = append(photos[i].Tags, row.Tag)
+		}
+	}
+
+	s.log(ctx).Info("tags attached to photos",
+		"photos", len(photos),
+		"tags", len(rows),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// SetPhotoDeletedAt перемещает фото в корзину (deletedAt != nil) или восстанавливает его (deletedAt == nil).
+// Если фото не найдено или уже в нужном состоянии, возвращает domain.ErrPhotoNotFound
+func (s *PhotoStorage) SetPhotoDeletedAt(ctx context.Context, id uuid.UUID, deletedAt *time.Time) error {
+	ctx, span := startSpan(ctx, "SetPhotoDeletedAt",
+		attribute.String("photo_id", id.String()), attribute.Bool("deleted", deletedAt != nil))
+	defer span.End()
+
+	if deletedAt == nil {
+		q := `UPDATE photos SET deleted_at = NULL, updated_at = ?1 WHERE id = ?2 AND deleted_at IS NOT NULL`
+		return s.execAffectingPhoto(ctx, span, "restore", q, id)
+	}
+	q := `UPDATE photos SET deleted_at = ?3, updated_at = ?1 WHERE id = ?2 AND deleted_at IS NULL AND status = 'active'`
+	return s.execAffectingPhoto(ctx, span, "soft delete", q, id, formatTime(*deletedAt))
+}
+
+// IncrementViewsCount атомарно увеличивает счётчик просмотров фото на 1.
+// Время просмотра запоминается в last_viewed_at, updated_at тоже обновляется, чтобы сменился ETag карточки фото
+func (s *PhotoStorage) IncrementViewsCount(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "IncrementViewsCount", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	q := `UPDATE photos SET views_count = views_count + 1, last_viewed_at = ?1, updated_at = ?1 WHERE id = ?2 AND deleted_at IS NULL AND status = 'active'`
+	return s.execAffectingPhoto(ctx, span, "record view of", q, id)
+}
+
+// IncrementDownloadsCount атомарно увеличивает счётчик скачиваний фото на 1
+func (s *PhotoStorage) IncrementDownloadsCount(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "IncrementDownloadsCount", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	q := `UPDATE photos SET downloads_count = downloads_count + 1, updated_at = ?1 WHERE id = ?2 AND deleted_at IS NULL AND status = 'active'`
+	return s.execAffectingPhoto(ctx, span, "record download of", q, id)
+}
+
+// AddViews прибавляет накопленные просмотры к нескольким фото в одной транзакции и обновляет last_viewed_at.
+// updated_at не трогаем: просмотр не меняет само фото, а ETag карточки учитывает views_count отдельно
+func (s *PhotoStorage) AddViews(ctx context.Context, views map[uuid.UUID]int64) error {
+	ctx, span := startSpan(ctx, "AddViews", attribute.Int("photos.count", len(views)))
+	defer span.End()
+
+	if len(views) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+
+	now := formatTime(time.Now())
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		for id, n := range views {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE photos SET views_count = views_count + ?, last_viewed_at = ? WHERE id = ? AND deleted_at IS NULL AND status = 'active'`, n, now, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to add photo views", "photos", len(views), "error", err)
+		return fmt.Errorf("ошибка при обновлении счётчиков просмотров: %w", err)
+	}
+
+	s.log(ctx).Debug("photo views added",
+		"photos", len(views),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// UpdatePopularityScores пересчитывает оценку популярности всех фото вне корзины.
+// Функция popularity_score зарегистрирована в db.go; возраст считается через julianday
+func (s *PhotoStorage) UpdatePopularityScores(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "UpdatePopularityScores")
+	defer span.End()
+
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `
+	UPDATE photos SET popularity_score = popularity_score(views_count, likes_count, downloads_count,
+		julianday(?) - julianday(uploaded_at))
+	WHERE deleted_at IS NULL AND status = 'active'`, formatTime(time.Now()))
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to update popularity scores", "error", err)
+		return 0, fmt.Errorf("ошибка при пересчёте популярности фото: %w", err)
+	}
+
+	updated, _ := res.RowsAffected()
+	s.log(ctx).Info("popularity scores updated",
+		"updated", updated,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return updated, nil
+}
+
+// ListTrendingPhotos получает самые популярные фото по popularity_score
+func (s *PhotoStorage) ListTrendingPhotos(ctx context.Context, limit int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListTrendingPhotos", attribute.Int("limit", limit))
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, "deleted_at IS NULL AND status = 'active'", "popularity_score DESC, id", 1, limit)
+}
+
+// ListRecentlyViewedPhotos получает фото источника source по убыванию времени последнего просмотра
+func (s *PhotoStorage) ListRecentlyViewedPhotos(ctx context.Context, source string, limit int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListRecentlyViewedPhotos", attribute.String("source", source), attribute.Int("limit", limit))
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, "deleted_at IS NULL AND status = 'active' AND last_viewed_at IS NOT NULL AND external_source = ?",
+		"last_viewed_at DESC", 1, limit, source)
+}
+
+// execAffectingPhoto выполняет UPDATE одного фото (?1 — текущее время, ?2 — id, дальше — args)
+// и превращает 0 затронутых строк в domain.ErrPhotoNotFound
+func (s *PhotoStorage) execAffectingPhoto(ctx context.Context, span trace.Span, action, q string, id uuid.UUID, args ...any) error {
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, q, append([]any{formatTime(time.Now()), id}, args...)...)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to "+action+" photo", "id", id, "error", err)
+		return fmt.Errorf("ошибка при изменении фото %s (%s): %w", id, action, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка при изменении фото %s (%s): %w", id, action, err)
+	}
+	if affected == 0 {
+		s.log(ctx).Warn("photo not found for "+action, "id", id)
+		return domain.ErrPhotoNotFound
+	}
+
+	s.log(ctx).Info("photo "+action+" completed",
+		"id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// PurgeDeletedPhotos окончательно удаляет фото, помеченные удалёнными раньше olderThan.
+// Возвращает удалённые фото (id, unsplash_id, источник и s3_url), чтобы вызывающий код удалил их файлы
+func (s *PhotoStorage) PurgeDeletedPhotos(ctx context.Context, olderThan time.Time) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "PurgeDeletedPhotos")
+	defer span.End()
+
+	start := time.Now()
+
+	var purged []domain.Photo
+	err := s.db.SelectContext(ctx, &purged,
+		`DELETE FROM photos WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		RETURNING id, COALESCE(unsplash_id, '') AS unsplash_id, external_source, s3_url`, formatTime(olderThan))
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to purge deleted photos", "older_than", olderThan, "error", err)
+		return nil, fmt.Errorf("ошибка при окончательном удалении фото: %w", err)
+	}
+
+	s.log(ctx).Info("deleted photos purged",
+		"older_than", olderThan,
+		"count", len(purged),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return purged, nil
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (s *PhotoStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// benchRun нумерует пачки benchPhotos: повторные запуски бенчмарка не должны упираться в конфликт unsplash_id
+var benchRun atomic.Int64
+
+// benchPhotos возвращает n новых фото с уникальными unsplash_id
+func benchPhotos(userID uuid.UUID, n int) []*domain.Photo {
+	run := benchRun.Add(1)
+	photos := make([]*domain.Photo, n)
+	for i := range photos {
+		photos[i] = testPhoto(userID, fmt.Sprintf("bench-%d-%d", run, i), "bench")
+	}
+	return photos
+}
+
+// BenchmarkSavePhotos сравнивает 100 отдельных SavePhotoTx с одним SavePhotos
+func BenchmarkSavePhotos(b *testing.B) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(b)
+
+	const batchSize = 100
+	b.Run("single", func(b *testing.B) {
+		for range b.N {
+			for _, photo := range benchPhotos(userID, batchSize) {
+				if _, err := s.SavePhotoTx(ctx, photo); err != nil {
+					b.Fatalf("SavePhotoTx: %v", err)
+				}
+			}
+		}
+		b.ReportMetric(float64(b.N*batchSize)/b.Elapsed().Seconds(), "rows/s")
+	})
+	b.Run("batch", func(b *testing.B) {
+		for range b.N {
+			if _, err := s.SavePhotos(ctx, benchPhotos(userID, batchSize)); err != nil {
+				b.Fatalf("SavePhotos: %v", err)
+			}
+		}
+		b.ReportMetric(float64(b.N*batchSize)/b.Elapsed().Seconds(), "rows/s")
+	})
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+)
+
+// countingConnector открывает соединения SQLite, которые считают выполненные SELECT
+type countingConnector struct {
+	dsn     string
+	queries *atomic.Int64
+}
+
+func (c countingConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.Driver().Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return countingConn{Conn: conn, queries: c.queries}, nil
+}
+
+func (c countingConnector) Driver() driver.Driver { return &sqlite.Driver{} }
+
+// countingConn пропускает запросы к соединению modernc и считает QueryContext
+type countingConn struct {
+	driver.Conn
+	queries *atomic.Int64
+}
+
+func (c countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.queries.Add(1)
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c countingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+// openCountingDB открывает пустую базу, queries считает выполненные на ней запросы
+func openCountingDB(t *testing.T, queries *atomic.Int64) *sqlx.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_pragma=foreign_keys(1)"
+	db := sqlx.NewDb(sql.OpenDB(countingConnector{dsn: dsn, queries: queries}), driverName)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	return db
+}
+
+func TestListPhotosWithTagsInDB_TwoQueriesPerPage(t *testing.T) {
+	ctx := context.Background()
+	var queries atomic.Int64
+	db := openCountingDB(t, &queries)
+	userID, err := NewUserStorage(db, discardLogger()).GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("create system user: %v", err)
+	}
+	s := NewPhotoStorage(db, discardLogger())
+
+	want := map[string][]string{
+		"a": {"cats", "dogs"},
+		"b": {"cats"},
+		"c": nil,
+		"d": {"birds", "sea", "sunset"},
+	}
+	for unsplashID, tags := range want {
+		if _, err := s.SavePhotoTx(ctx, testPhoto(userID, unsplashID, tags...)); err != nil {
+			t.Fatalf("save %s: %v", unsplashID, err)
+		}
+	}
+
+	queries.Store(0)
+	photos, err := s.ListPhotosWithTagsInDB(ctx, 1, 10, domain.PhotoSort{})
+	if err != nil {
+		t.Fatalf("ListPhotosWithTagsInDB: %v", err)
+	}
+	if n := queries.Load(); n != 2 {
+		t.Errorf("queries = %d, want 2: one for the page and one for all its tags", n)
+	}
+
+	if len(photos) != len(want) {
+		t.Fatalf("photos = %d, want %d", len(photos), len(want))
+	}
+	for _, photo := range photos {
+		var got []string
+		for _, tag := range photo.Tags {
+			got = append(got, tag.Name)
+		}
+		sort.Strings(got)
+		if wantTags := want[photo.UnsplashID]; !slices.Equal(got, wantTags) {
+			t.Errorf("photo %s tags = %v, want %v", photo.UnsplashID, got, wantTags)
+		}
+	}
+}
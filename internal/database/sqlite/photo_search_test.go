@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+func TestSearchConditions(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  domain.PhotoSearchFilter
+		where   string // условие после текстового
+		args    []interface{}
+		wantErr bool
+	}{
+		{name: "empty filter", args: []interface{}{"%cats%"}},
+		{name: "date range, author, orientation and min likes",
+			filter: domain.PhotoSearchFilter{MinLikes: 10, Author: "Jane Doe", Orientation: domain.OrientationLandscape, From: &from, To: &to},
+			where: " AND likes_count >= ?2 AND LOWER(author_name) = LOWER(?3) AND width > height" +
+				" AND uploaded_at >= ?4 AND uploaded_at < ?5",
+			args: []interface{}{"%cats%", 10, "Jane Doe", formatTime(from), formatTime(to)}},
+		{name: "open-ended range", filter: domain.PhotoSearchFilter{To: &to},
+			where: " AND uploaded_at < ?2", args: []interface{}{"%cats%", formatTime(to)}},
+		{name: "range ends before it starts", filter: domain.PhotoSearchFilter{From: &to, To: &from}, wantErr: true},
+		{name: "empty range", filter: domain.PhotoSearchFilter{From: &from, To: &from}, wantErr: true},
+		{name: "unknown orientation", filter: domain.PhotoSearchFilter{Orientation: "diagonal"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args, err := searchConditions("cats", tt.filter)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidFilter) {
+					t.Fatalf("err = %v, want domain.ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("searchConditions: %v", err)
+			}
+			empty, _, _ := searchConditions("cats", domain.PhotoSearchFilter{})
+			if want := empty + tt.where; where != want {
+				t.Errorf("where =\n%s\nwant\n%s", where, want)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestSearchPhotosInDB_Filter(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+	jan := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, p := range []struct {
+		id            string
+		author        string
+		width, height int
+		likes         int
+		uploaded      time.Time
+	}{
+		{id: "wide", author: "Jane", width: 1600, height: 900, likes: 50, uploaded: jan},
+		{id: "tall", author: "jane", width: 900, height: 1600, likes: 50, uploaded: jan},
+		{id: "square", author: "Jane", width: 1000, height: 1000, likes: 5, uploaded: jan},
+		{id: "old", author: "Jane", width: 1600, height: 900, likes: 50, uploaded: jan.AddDate(-1, 0, 0)},
+		{id: "other", author: "Bob", width: 1600, height: 900, likes: 50, uploaded: jan},
+	} {
+		photo := testPhoto(userID, p.id)
+		photo.Title = "cat " + p.id
+		photo.AuthorName, photo.Width, photo.Height, photo.LikesCount, photo.UploadedAt = p.author, p.width, p.height, p.likes, p.uploaded
+		if _, err := s.SavePhotoTx(ctx, photo); err != nil {
+			t.Fatalf("save %s: %v", p.id, err)
+		}
+	}
+	from, to := jan.AddDate(0, 0, -14), jan.AddDate(0, 0, 17)
+
+	tests := []struct {
+		name   string
+		filter domain.PhotoSearchFilter
+		want   []string
+	}{
+		{name: "empty filter", want: []string{"old", "other", "square", "tall", "wide"}},
+		{name: "author is case insensitive", filter: domain.PhotoSearchFilter{Author: "JANE"},
+			want: []string{"old", "square", "tall", "wide"}},
+		{name: "landscape", filter: domain.PhotoSearchFilter{Orientation: domain.OrientationLandscape},
+			want: []string{"old", "other", "wide"}},
+		{name: "squarish", filter: domain.PhotoSearchFilter{Orientation: domain.OrientationSquarish}, want: []string{"square"}},
+		{name: "date range", filter: domain.PhotoSearchFilter{From: &from, To: &to},
+			want: []string{"other", "square", "tall", "wide"}},
+		{name: "date range, author, orientation and min likes",
+			filter: domain.PhotoSearchFilter{MinLikes: 10, Author: "jane", Orientation: domain.OrientationLandscape, From: &from, To: &to},
+			want:   []string{"wide"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			photos, err := s.SearchPhotosInDB(ctx, "cat", 1, 10, tt.filter, domain.PhotoSort{})
+			if err != nil {
+				t.Fatalf("SearchPhotosInDB: %v", err)
+			}
+			var got []string
+			for _, photo := range photos {
+				got = append(got, photo.UnsplashID)
+			}
+			sort.Strings(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("results = %v, want %v", got, tt.want)
+			}
+			if n, err := s.CountSearchResults(ctx, "cat", tt.filter); err != nil || n != int64(len(tt.want)) {
+				t.Errorf("CountSearchResults = %d, %v; want %d", n, err, len(tt.want))
+			}
+		})
+	}
+
+	if _, err := s.SearchPhotosInDB(ctx, "cat", 1, 10, domain.PhotoSearchFilter{From: &to, To: &from}, domain.PhotoSort{}); !errors.Is(err, domain.ErrInvalidFilter) {
+		t.Errorf("inverted range: err = %v, want domain.ErrInvalidFilter", err)
+	}
+}
@@ -0,0 +1,298 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestSavePhotoTx_Created(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+
+	photo := testPhoto(userID, "abc", "cats", "dogs")
+	created, err := s.SavePhotoTx(ctx, photo)
+	if err != nil {
+		t.Fatalf("SavePhotoTx: %v", err)
+	}
+	if !created {
+		t.Fatal("created = false, want true for a new photo")
+	}
+	if len(photo.Tags) != 2 {
+		t.Errorf("tags = %v, want 2 saved tags", photo.Tags)
+	}
+}
+
+func TestSavePhotoTx_SameNumericIDAcrossSources(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+
+	for _, source := range []struct{ name, prefix string }{
+		{domain.SourcePexels, domain.PexelsIDPrefix},
+		{domain.SourcePixabay, domain.PixabayIDPrefix},
+	} {
+		photo := testPhoto(userID, source.prefix+"42")
+		photo.ExternalSource = source.name
+		created, err := s.SavePhotoTx(ctx, photo)
+		if err != nil || !created {
+			t.Fatalf("save %s photo: created = %t, err = %v; want a new row", source.name, created, err)
+		}
+
+		stored, err := s.GetPhotosByUnsplashIDFromDB(ctx, source.prefix+"42")
+		if err != nil {
+			t.Fatalf("get %s photo: %v", source.name, err)
+		}
+		if stored.ExternalID != "42" {
+			t.Errorf("%s external_id = %q, want the ID without the prefix", source.name, stored.ExternalID)
+		}
+	}
+}
+
+func TestSavePhotoTx_ConflictReturnsStoredRow(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+
+	winner := testPhoto(userID, "abc", "cats")
+	if _, err := s.SavePhotoTx(ctx, winner); err != nil {
+		t.Fatalf("save winner: %v", err)
+	}
+
+	loser := testPhoto(userID, "abc", "dogs")
+	loser.ID = uuid.New()
+	loser.Title = "loser title"
+	created, err := s.SavePhotoTx(ctx, loser)
+	if err != nil {
+		t.Fatalf("save loser: %v", err)
+	}
+	if created {
+		t.Fatal("created = true, want false on unsplash_id conflict")
+	}
+	if loser.ID != winner.ID {
+		t.Errorf("ID = %s, want stored row ID %s", loser.ID, winner.ID)
+	}
+	if loser.Title != winner.Title {
+		t.Errorf("title = %q, want stored %q", loser.Title, winner.Title)
+	}
+	if len(loser.Tags) != 1 || loser.Tags[0].Name != "cats" {
+		t.Errorf("tags = %v, want stored tags [cats]", loser.Tags)
+	}
+
+	var links int
+	if err := s.db.GetContext(ctx, &links, `SELECT COUNT(*) FROM photo_tags`); err != nil {
+		t.Fatalf("count photo_tags: %v", err)
+	}
+	if links != 1 {
+		t.Errorf("photo_tags rows = %d, want 1: loser tags must not be linked", links)
+	}
+}
+
+func TestSavePhotoTx_RollbackLeavesNoPartialRows(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+	if _, err := s.SavePhotoTx(ctx, testPhoto(userID, "existing", "cats")); err != nil {
+		t.Fatalf("save existing photo: %v", err)
+	}
+
+	// связи photo_tags пишутся последними: к этому моменту фото и новые теги уже вставлены
+	if _, err := s.db.Exec(`CREATE TRIGGER fail_photo_tags BEFORE INSERT ON photo_tags
+	BEGIN SELECT RAISE(ABORT, 'injected failure'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	countRows := func() map[string]int {
+		counts := make(map[string]int)
+		for _, table := range []string{"photos", "tags", "photo_tags"} {
+			var n int
+			if err := s.db.Get(&n, `SELECT COUNT(*) FROM `+table); err != nil {
+				t.Fatalf("count %s: %v", table, err)
+			}
+			counts[table] = n
+		}
+		return counts
+	}
+	before := countRows()
+
+	if _, err := s.SavePhotoTx(ctx, testPhoto(userID, "abc", "dogs", "birds")); err == nil {
+		t.Fatal("err = nil, want the injected failure")
+	}
+
+	if after := countRows(); !reflect.DeepEqual(after, before) {
+		t.Errorf("row counts = %v after the failed save, want unchanged %v", after, before)
+	}
+	if photo, err := s.GetPhotosByUnsplashIDFromDB(ctx, "abc"); err == nil && photo != nil {
+		t.Errorf("photo %s is visible after the rollback", photo.ID)
+	}
+}
+
+func TestSetPhotoDeletedAt(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+	photo := testPhoto(userID, "abc")
+	if _, err := s.SavePhotoTx(ctx, photo); err != nil {
+		t.Fatalf("SavePhotoTx: %v", err)
+	}
+
+	if err := s.SetPhotoDeletedAt(ctx, photo.ID, nil); !errors.Is(err, domain.ErrPhotoNotFound) {
+		t.Fatalf("restore of a photo that was never deleted: err = %v, want ErrPhotoNotFound", err)
+	}
+
+	deletedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := s.SetPhotoDeletedAt(ctx, photo.ID, &deletedAt); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if got, err := s.GetPhotoByIDFromDB(ctx, photo.ID); err != nil || got != nil {
+		t.Fatalf("GetPhotoByIDFromDB after delete = %v, %v; want nil, nil", got, err)
+	}
+	trashed, err := s.GetPhotoByIDIncludingDeleted(ctx, photo.ID)
+	if err != nil {
+		t.Fatalf("GetPhotoByIDIncludingDeleted: %v", err)
+	}
+	if trashed.DeletedAt == nil || !trashed.DeletedAt.Equal(deletedAt) {
+		t.Errorf("deleted_at = %v, want %v", trashed.DeletedAt, deletedAt)
+	}
+	if err := s.SetPhotoDeletedAt(ctx, photo.ID, &deletedAt); !errors.Is(err, domain.ErrPhotoNotFound) {
+		t.Errorf("second delete: err = %v, want ErrPhotoNotFound", err)
+	}
+
+	if err := s.SetPhotoDeletedAt(ctx, photo.ID, nil); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restored, err := s.GetPhotoByIDFromDB(ctx, photo.ID)
+	if err != nil || restored == nil {
+		t.Fatalf("GetPhotoByIDFromDB after restore = %v, %v; want the photo", restored, err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("deleted_at after restore = %v, want nil", restored.DeletedAt)
+	}
+
+	if err := s.SetPhotoDeletedAt(ctx, uuid.New(), nil); !errors.Is(err, domain.ErrPhotoNotFound) {
+		t.Errorf("restore of a missing photo: err = %v, want ErrPhotoNotFound", err)
+	}
+}
+
+func TestSavePhoto_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+
+	lat, lon := 55.7558, 37.6173
+	uploaded := time.Date(2025, 3, 4, 5, 6, 7, 123456789, time.UTC)
+	created := uploaded.Add(time.Hour)
+	photo := &domain.Photo{
+		ID:             uuid.New(),
+		UnsplashID:     "abc",
+		ExternalSource: domain.SourceUnsplash,
+		UserID:         userID,
+		S3URL:          "http://localhost:9000/photos/unsplash-photos/abc",
+		Title:          "title",
+		Description:    "description",
+		AuthorName:     "author",
+		Width:          640,
+		Height:         480,
+		LikesCount:     11,
+		OriginalURL:    "https://images.example.com/abc",
+		UploadedAt:     uploaded,
+		ViewsCount:     22,
+		DownloadsCount: 33,
+		CreatedAt:      created,
+		UpdatedAt:      created.Add(time.Minute),
+		SizeBytes:      4096,
+		MimeType:       "image/jpeg",
+		DominantColors: domain.ColorPalette{"#112233", "#445566"},
+		Latitude:       &lat,
+		Longitude:      &lon,
+		BlurHash:       "LEHV6nWB2yk8pyo0adR*.7kCMdnj",
+		DominantColor:  "#aabbcc",
+	}
+	want := *photo
+	if created, err := s.SavePhoto(ctx, photo); err != nil || !created {
+		t.Fatalf("SavePhoto: created = %v, err = %v", created, err)
+	}
+
+	got, err := s.GetPhotoByIDFromDB(ctx, want.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetPhotoByIDFromDB: photo = %v, err = %v", got, err)
+	}
+	if got.Latitude == nil || got.Longitude == nil {
+		t.Fatalf("coordinates = %v, %v, want %v, %v", got.Latitude, got.Longitude, lat, lon)
+	}
+	fields := []struct {
+		name      string
+		got, want any
+	}{
+		{"id", got.ID, want.ID},
+		{"unsplash_id", got.UnsplashID, want.UnsplashID},
+		{"external_source", got.ExternalSource, want.ExternalSource},
+		{"external_id", got.ExternalID, want.UnsplashID},
+		{"user_id", got.UserID, want.UserID},
+		{"s3_url", got.S3URL, want.S3URL},
+		{"title", got.Title, want.Title},
+		{"description", got.Description, want.Description},
+		{"author_name", got.AuthorName, want.AuthorName},
+		{"width", got.Width, want.Width},
+		{"height", got.Height, want.Height},
+		{"likes_count", got.LikesCount, want.LikesCount},
+		{"original_url", got.OriginalURL, want.OriginalURL},
+		{"uploaded_at", got.UploadedAt.UTC(), want.UploadedAt},
+		{"views_count", got.ViewsCount, want.ViewsCount},
+		{"downloads_count", got.DownloadsCount, want.DownloadsCount},
+		{"created_at", got.CreatedAt.UTC(), want.CreatedAt},
+		{"updated_at", got.UpdatedAt.UTC(), want.UpdatedAt},
+		{"deleted_at", got.DeletedAt, (*time.Time)(nil)},
+		{"status", got.Status, domain.PhotoStatusActive},
+		{"size_bytes", got.SizeBytes, want.SizeBytes},
+		{"mime_type", got.MimeType, want.MimeType},
+		{"dominant_colors", got.DominantColors, want.DominantColors},
+		{"latitude", *got.Latitude, lat},
+		{"longitude", *got.Longitude, lon},
+		{"blur_hash", got.BlurHash, want.BlurHash},
+		{"dominant_color", got.DominantColor, want.DominantColor},
+	}
+	for _, f := range fields {
+		if !reflect.DeepEqual(f.got, f.want) {
+			t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+}
+
+func TestUpdatePopularityScores_MatchesDomainFormula(t *testing.T) {
+	ctx := context.Background()
+	s, userID := newTestPhotoStorage(t)
+
+	fresh := testPhoto(userID, "fresh")
+	fresh.ViewsCount, fresh.LikesCount, fresh.DownloadsCount = 120, 7, 3
+	old := testPhoto(userID, "old")
+	old.ViewsCount, old.LikesCount, old.DownloadsCount = 50_000, 900, 400
+	old.UploadedAt = time.Now().Add(-10 * 24 * time.Hour)
+	for _, photo := range []*domain.Photo{fresh, old} {
+		if _, err := s.SavePhoto(ctx, photo); err != nil {
+			t.Fatalf("SavePhoto: %v", err)
+		}
+	}
+
+	updated, err := s.UpdatePopularityScores(ctx)
+	if err != nil {
+		t.Fatalf("UpdatePopularityScores: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("updated = %d, want 2", updated)
+	}
+
+	for _, photo := range []*domain.Photo{fresh, old} {
+		got, err := s.GetPhotoByIDFromDB(ctx, photo.ID)
+		if err != nil || got == nil {
+			t.Fatalf("GetPhotoByIDFromDB: %v", err)
+		}
+		ageDays := time.Since(photo.UploadedAt).Hours() / 24
+		want := domain.PopularityScore(photo.ViewsCount, int64(photo.LikesCount), photo.DownloadsCount, ageDays)
+		// возраст считается в момент UPDATE, поэтому допускаем расхождение в доли секунды
+		if math.Abs(got.PopularityScore-want) > 1e-3 {
+			t.Errorf("%s: popularity_score = %v, want %v", photo.UnsplashID, got.PopularityScore, want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+-- Схема для локальной разработки на SQLite. Повторяет итоговое состояние миграций PostgreSQL
+-- (internal/database/migrations) без полнотекстового поиска и индексов, нужных только Postgres.
+-- UUID хранятся строками, время — строками RFC 3339 в UTC фиксированной ширины (см. formatTime),
+-- чтобы сравнение и сортировка строк совпадали с порядком времени. Тип TIMESTAMP нужен драйверу:
+-- такие колонки он сам разбирает в time.Time при чтении
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    username TEXT UNIQUE NOT NULL,
+    email TEXT UNIQUE NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS photos (
+    id TEXT PRIMARY KEY,
+    unsplash_id TEXT UNIQUE,
+    external_source TEXT NOT NULL DEFAULT 'unsplash',
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    s3_url TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    author_name TEXT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    likes_count INTEGER NOT NULL DEFAULT 0,
+    original_url TEXT NOT NULL,
+    uploaded_at TIMESTAMP NOT NULL,
+    views_count INTEGER NOT NULL DEFAULT 0,
+    downloads_count INTEGER NOT NULL DEFAULT 0,
+    -- статистика Unsplash отдельно от собственных счётчиков (миграция 028)
+    unsplash_views INTEGER NOT NULL DEFAULT 0,
+    unsplash_downloads INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    mime_type TEXT NOT NULL DEFAULT '',
+    dominant_colors TEXT NOT NULL DEFAULT '{}',
+    popularity_score REAL NOT NULL DEFAULT 0,
+    latitude REAL,
+    longitude REAL,
+    blur_hash TEXT NOT NULL DEFAULT '',
+    dominant_color TEXT NOT NULL DEFAULT '',
+    embedding TEXT, -- литерал вектора "[...]", как у pgvector; NULL, пока эмбеддинг не вычислен
+    last_viewed_at TIMESTAMP,
+    -- pending — фото загрузки напрямую в S3 до подтверждения, в выдачу не попадает (миграция 027)
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('pending', 'active')),
+    -- ID в источнике без префикса "pexels-" или "pixabay-"; unsplash_id оставлен для обратной совместимости
+    external_id TEXT GENERATED ALWAYS AS (
+        CASE WHEN external_source = 'pexels' AND unsplash_id LIKE 'pexels-%'
+             THEN substr(unsplash_id, length('pexels-') + 1)
+             WHEN external_source = 'pixabay' AND unsplash_id LIKE 'pixabay-%'
+             THEN substr(unsplash_id, length('pixabay-') + 1)
+             ELSE unsplash_id
+        END
+    ) VIRTUAL
+);
+
+CREATE INDEX IF NOT EXISTS idx_photos_user_id_created_at ON photos (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_photos_created_at ON photos (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_photos_deleted_at ON photos (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_photos_popularity ON photos (popularity_score DESC);
+CREATE INDEX IF NOT EXISTS idx_photos_likes_count ON photos (likes_count DESC);
+CREATE INDEX IF NOT EXISTS idx_photos_views_count ON photos (views_count DESC);
+CREATE INDEX IF NOT EXISTS idx_photos_downloads_count ON photos (downloads_count DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_photos_source_external_id ON photos (external_source, external_id);
+CREATE INDEX IF NOT EXISTS idx_photos_last_viewed_at ON photos (last_viewed_at DESC) WHERE last_viewed_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS tags (
+    id TEXT PRIMARY KEY,
+    name TEXT UNIQUE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS photo_tags (
+    photo_id TEXT NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+    tag_id TEXT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (photo_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_photo_tags_tag_id ON photo_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT,
+    old_value TEXT,
+    new_value TEXT,
+    ip_address TEXT,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity_type_created_at ON audit_logs (entity_type, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS search_queries (
+    id TEXT PRIMARY KEY,
+    query TEXT NOT NULL,
+    page INTEGER NOT NULL,
+    per_page INTEGER NOT NULL,
+    results_saved INTEGER NOT NULL DEFAULT 0,
+    source TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_queries_created_at ON search_queries (created_at DESC);
+
+CREATE TABLE IF NOT EXISTS idempotency_cache (
+    key TEXT PRIMARY KEY,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response TEXT,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_cache_created_at ON idempotency_cache (created_at);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_collections_user_created ON collections (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS collection_photos (
+    collection_id TEXT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    photo_id TEXT NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+    added_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (collection_id, photo_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_photos_added ON collection_photos (collection_id, added_at DESC);
+
+CREATE TABLE IF NOT EXISTS photo_series (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_photo_series_user_created ON photo_series (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS photo_series_members (
+    series_id TEXT NOT NULL REFERENCES photo_series(id) ON DELETE CASCADE,
+    photo_id TEXT NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (series_id, photo_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_photo_series_members_position ON photo_series_members (series_id, position);
+
+CREATE TABLE IF NOT EXISTS photo_likes (
+    photo_id TEXT NOT NULL REFERENCES photos(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (photo_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_photo_likes_user_created ON photo_likes (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL,
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_created ON webhooks (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    success INTEGER NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_created ON webhook_deliveries (webhook_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS upload_intents (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    s3_key TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_intents_expires_at ON upload_intents (expires_at);
@@ -0,0 +1,442 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// seriesColumns — явный список колонок photo_series
+const seriesColumns = `id, user_id, title, description, created_at, updated_at`
+
+// SeriesStorage реализует ports.SeriesStorage поверх таблиц photo_series и photo_series_members в SQLite
+type SeriesStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewSeriesStorage создает новый экземпляр SeriesStorage
+func NewSeriesStorage(db *sqlx.DB, logger *slog.Logger) *SeriesStorage {
+	return &SeriesStorage{db: db, logger: logger}
+}
+
+// CreateSeries сохраняет новую серию
+func (s *SeriesStorage) CreateSeries(ctx context.Context, series *domain.PhotoSeries) error {
+	ctx, span := startSpan(ctx, "CreateSeries", attribute.String("user_id", series.UserID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	if series.ID == uuid.Nil {
+		series.ID = uuid.New()
+	}
+	now := time.Now()
+	series.CreatedAt = now
+	series.UpdatedAt = now
+
+	_, err := s.db.ExecContext(ctx, `
+	INSERT INTO photo_series (id, user_id, title, description, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)`,
+		series.ID, series.UserID, series.Title, series.Description, formatTime(now), formatTime(now),
+	)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to create series", "user_id", series.UserID, "error", err)
+		return fmt.Errorf("ошибка при создании серии: %w", err)
+	}
+
+	s.log(ctx).Info("series created successfully",
+		"id", series.ID,
+		"user_id", series.UserID,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// GetSeriesByID получает серию по ID
+func (s *SeriesStorage) GetSeriesByID(ctx context.Context, id uuid.UUID) (*domain.PhotoSeries, error) {
+	ctx, span := startSpan(ctx, "GetSeriesByID", attribute.String("series_id", id.String()))
+	defer span.End()
+
+	var series domain.PhotoSeries
+	err := s.db.GetContext(ctx, &series, `SELECT `+seriesColumns+` FROM photo_series WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSeriesNotFound
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to get series by id", "id", id, "error", err)
+		return nil, fmt.Errorf("ошибка при получении серии по ID: %w", err)
+	}
+	return &series, nil
+}
+
+// ListSeriesByUser получает страницу серий пользователя, новые первыми
+func (s *SeriesStorage) ListSeriesByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.PhotoSeries, error) {
+	ctx, span := startSpan(ctx, "ListSeriesByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT ` + seriesColumns + ` FROM photo_series
+	WHERE user_id = ?
+	ORDER BY created_at DESC, id
+	LIMIT ? OFFSET ?
+	`
+
+	series := []domain.PhotoSeries{}
+	if err := s.db.SelectContext(ctx, &series, q, userID, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list series", "user_id", userID, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении списка серий: %w", err)
+	}
+
+	s.log(ctx).Info("listed series successfully",
+		"user_id", userID,
+		"page", page,
+		"per_page", perPage,
+		"count", len(series),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return series, nil
+}
+
+// CountSeriesByUser считает серии пользователя
+func (s *SeriesStorage) CountSeriesByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := startSpan(ctx, "CountSeriesByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	var total int64
+	err := s.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM photo_series WHERE user_id = ?`, userID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to count series", "user_id", userID, "error", err)
+		return 0, fmt.Errorf("ошибка при подсчёте серий: %w", err)
+	}
+	return total, nil
+}
+
+// UpdateSeries обновляет только заданные в updates поля серии
+func (s *SeriesStorage) UpdateSeries(ctx context.Context, id uuid.UUID, updates domain.PhotoSeriesUpdate) error {
+	ctx, span := startSpan(ctx, "UpdateSeries", attribute.String("series_id", id.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	sets := []string{"updated_at = :updated_at"}
+	args := map[string]interface{}{
+		"id":         id,
+		"updated_at": formatTime(time.Now()),
+	}
+	if updates.Title != nil {
+		sets = append(sets, "title = :title")
+		args["title"] = *updates.Title
+	}
+	if updates.Description != nil {
+		sets = append(sets, "description = :description")
+		args["description"] = *updates.Description
+	}
+
+	res, err := s.db.NamedExecContext(ctx, `UPDATE photo_series SET `+strings.Join(sets, ", ")+` WHERE id = :id`, args)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to update series", "id", id, "error", err)
+		return fmt.Errorf("ошибка при обновлении серии: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrSeriesNotFound
+	}
+
+	s.log(ctx).Info("series updated successfully",
+		"id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// DeleteSeries удаляет серию; связи с фото удаляются каскадно, сами фото остаются
+func (s *SeriesStorage) DeleteSeries(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "DeleteSeries", attribute.String("series_id", id.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM photo_series WHERE id = ?`, id)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to delete series", "id", id, "error", err)
+		return fmt.Errorf("ошибка при удалении серии: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrSeriesNotFound
+	}
+
+	s.log(ctx).Info("series deleted successfully",
+		"id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// AddPhotoToSeries вставляет фото на позицию position и сдвигает следующие фото на одну позицию
+func (s *SeriesStorage) AddPhotoToSeries(ctx context.Context, seriesID, photoID uuid.UUID, position int) (bool, error) {
+	ctx, span := startSpan(ctx, "AddPhotoToSeries",
+		attribute.String("series_id", seriesID.String()),
+		attribute.String("photo_id", photoID.String()),
+		attribute.Int("position", position),
+	)
+	defer span.End()
+
+	added := false
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		if err := lockSeries(ctx, tx, seriesID); err != nil {
+			return err
+		}
+
+		var present bool
+		if err := tx.GetContext(ctx, &present,
+			`SELECT EXISTS (SELECT 1 FROM photo_series_members WHERE series_id = ? AND photo_id = ?)`, seriesID, photoID); err != nil {
+			return fmt.Errorf("ошибка при проверке фото в серии: %w", err)
+		}
+		if present {
+			return nil
+		}
+
+		// конец серии — после последней позиции: окончательно удалённые фото могут оставить пропуски
+		var end int
+		if err := tx.GetContext(ctx, &end,
+			`SELECT COALESCE(MAX(position) + 1, 0) FROM photo_series_members WHERE series_id = ?`, seriesID); err != nil {
+			return fmt.Errorf("ошибка при подсчёте фото серии: %w", err)
+		}
+		if position < 0 || position > end {
+			position = end
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE photo_series_members SET position = position + 1 WHERE series_id = ? AND position >= ?`, seriesID, position); err != nil {
+			return fmt.Errorf("ошибка при сдвиге фото серии: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO photo_series_members (series_id, photo_id, position) VALUES (?, ?, ?)`, seriesID, photoID, position); err != nil {
+			return fmt.Errorf("ошибка при добавлении фото в серию: %w", err)
+		}
+		added = true
+		return touchSeries(ctx, tx, seriesID)
+	})
+	recordDBError(span, err)
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return false, err
+		}
+		s.log(ctx).Error("failed to add photo to series", "series_id", seriesID, "photo_id", photoID, "error", err)
+		return false, fmt.Errorf("ошибка при добавлении фото в серию: %w", err)
+	}
+
+	s.log(ctx).Info("photo added to series",
+		"series_id", seriesID,
+		"photo_id", photoID,
+		"position", position,
+		"already_present", !added,
+	)
+	return added, nil
+}
+
+// RemovePhotoFromSeries убирает фото из серии и сдвигает следующие фото на его место
+func (s *SeriesStorage) RemovePhotoFromSeries(ctx context.Context, seriesID, photoID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "RemovePhotoFromSeries",
+		attribute.String("series_id", seriesID.String()),
+		attribute.String("photo_id", photoID.String()),
+	)
+	defer span.End()
+
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		if err := lockSeries(ctx, tx, seriesID); err != nil {
+			return err
+		}
+
+		var position int
+		err := tx.GetContext(ctx, &position,
+			`DELETE FROM photo_series_members WHERE series_id = ? AND photo_id = ? RETURNING position`, seriesID, photoID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка при удалении фото из серии: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE photo_series_members SET position = position - 1 WHERE series_id = ? AND position > ?`, seriesID, position); err != nil {
+			return fmt.Errorf("ошибка при сдвиге фото серии: %w", err)
+		}
+		return touchSeries(ctx, tx, seriesID)
+	})
+	recordDBError(span, err)
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return err
+		}
+		s.log(ctx).Error("failed to remove photo from series", "series_id", seriesID, "photo_id", photoID, "error", err)
+		return fmt.Errorf("ошибка при удалении фото из серии: %w", err)
+	}
+
+	s.log(ctx).Info("photo removed from series", "series_id", seriesID, "photo_id", photoID)
+	return nil
+}
+
+// ReorderSeriesPhotos проверяет новый порядок и записывает все позиции серии в одной транзакции
+func (s *SeriesStorage) ReorderSeriesPhotos(ctx context.Context, seriesID uuid.UUID, photoIDs []uuid.UUID) error {
+	ctx, span := startSpan(ctx, "ReorderSeriesPhotos",
+		attribute.String("series_id", seriesID.String()),
+		attribute.Int("photos", len(photoIDs)),
+	)
+	defer span.End()
+
+	start := time.Now()
+
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		if err := lockSeries(ctx, tx, seriesID); err != nil {
+			return err
+		}
+
+		var members []struct {
+			PhotoID uuid.UUID `db:"photo_id"`
+			Trashed bool      `db:"trashed"`
+		}
+		if err := tx.SelectContext(ctx, &members, `
+		SELECT m.photo_id, p.deleted_at IS NOT NULL AS trashed
+		FROM photo_series_members m
+		JOIN photos p ON p.id = m.photo_id
+		WHERE m.series_id = ?
+		ORDER BY m.position, m.photo_id`, seriesID); err != nil {
+			return fmt.Errorf("ошибка при получении фото серии: %w", err)
+		}
+		current := make([]uuid.UUID, 0, len(members))
+		trashed := make(map[uuid.UUID]bool)
+		for _, m := range members {
+			current = append(current, m.PhotoID)
+			if m.Trashed {
+				trashed[m.PhotoID] = true
+			}
+		}
+
+		order, err := domain.ReorderSeries(current, trashed, photoIDs)
+		if err != nil {
+			return err
+		}
+		// SQLite не умеет UPDATE ... FROM unnest, поэтому позиции пишутся подготовленным запросом в той же транзакции
+		stmt, err := tx.PrepareContext(ctx, `UPDATE photo_series_members SET position = ? WHERE series_id = ? AND photo_id = ?`)
+		if err != nil {
+			return fmt.Errorf("ошибка при подготовке обновления порядка фото серии: %w", err)
+		}
+		defer stmt.Close()
+		for position, photoID := range order {
+			if _, err := stmt.ExecContext(ctx, position, seriesID, photoID); err != nil {
+				return fmt.Errorf("ошибка при обновлении порядка фото серии: %w", err)
+			}
+		}
+		return touchSeries(ctx, tx, seriesID)
+	})
+	recordDBError(span, err)
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) || errors.Is(err, domain.ErrInvalidSeriesOrder) {
+			return err
+		}
+		s.log(ctx).Error("failed to reorder series photos", "series_id", seriesID, "error", err)
+		return fmt.Errorf("ошибка при изменении порядка фото серии: %w", err)
+	}
+
+	s.log(ctx).Info("series photos reordered",
+		"series_id", seriesID,
+		"photos", len(photoIDs),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// ListPhotosInSeries получает страницу неудалённых фото серии в порядке серии
+func (s *SeriesStorage) ListPhotosInSeries(ctx context.Context, seriesID uuid.UUID, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListPhotosInSeries", attribute.String("series_id", seriesID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	// у photo_series_members нет колонок с именами из photoColumns, поэтому их можно не уточнять таблицей
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	JOIN photo_series_members m ON m.photo_id = photos.id
+	WHERE m.series_id = ? AND photos.deleted_at IS NULL AND photos.status = 'active'
+	ORDER BY m.position, photos.id
+	LIMIT ? OFFSET ?
+	`
+
+	photos := []domain.Photo{}
+	if err := s.db.SelectContext(ctx, &photos, q, seriesID, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list series photos", "series_id", seriesID, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото серии: %w", err)
+	}
+
+	s.log(ctx).Info("listed series photos successfully",
+		"series_id", seriesID,
+		"page", page,
+		"per_page", perPage,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountPhotosInSeries считает неудалённые фото серии
+func (s *SeriesStorage) CountPhotosInSeries(ctx context.Context, seriesID uuid.UUID) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosInSeries", attribute.String("series_id", seriesID.String()))
+	defer span.End()
+
+	var total int64
+	err := s.db.GetContext(ctx, &total, `
+	SELECT COUNT(*) FROM photo_series_members m
+	JOIN photos ON photos.id = m.photo_id
+	WHERE m.series_id = ? AND photos.deleted_at IS NULL AND photos.status = 'active'`, seriesID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to count series photos", "series_id", seriesID, "error", err)
+		return 0, fmt.Errorf("ошибка при подсчёте фото серии: %w", err)
+	}
+	return total, nil
+}
+
+// lockSeries проверяет, что серия существует. Отдельная блокировка строки не нужна:
+// SQLite пропускает только одну пишущую транзакцию. Возвращает domain.ErrSeriesNotFound, если серии нет
+func lockSeries(ctx context.Context, tx *sqlx.Tx, seriesID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.GetContext(ctx, &id, `SELECT id FROM photo_series WHERE id = ?`, seriesID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrSeriesNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка при блокировке серии: %w", err)
+	}
+	return nil
+}
+
+// touchSeries обновляет updated_at серии после изменения её состава
+func touchSeries(ctx context.Context, tx *sqlx.Tx, seriesID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE photo_series SET updated_at = ? WHERE id = ?`, formatTime(time.Now()), seriesID); err != nil {
+		return fmt.Errorf("ошибка при обновлении времени изменения серии: %w", err)
+	}
+	return nil
+}
+
+func (s *SeriesStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
@@ -0,0 +1,153 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// uploadIntentColumns — явный список колонок upload_intents
+const uploadIntentColumns = `id, user_id, s3_key, content_type, size_bytes, title, description, created_at, expires_at`
+
+// CreateUploadIntent сохраняет ожидающую загрузку и в той же транзакции фото pending с её ID
+func (s *PhotoStorage) CreateUploadIntent(ctx context.Context, intent *domain.UploadIntent, pending *domain.Photo) error {
+	ctx, span := startSpan(ctx, "CreateUploadIntent", attribute.String("user_id", intent.UserID.String()))
+	defer span.End()
+
+	if intent.ID == uuid.Nil {
+		intent.ID = uuid.New()
+	}
+	if intent.CreatedAt.IsZero() {
+		intent.CreatedAt = time.Now()
+	}
+	pending.ID = intent.ID
+	pending.Status = domain.PhotoStatusPending
+	pending.UploadedAt, pending.CreatedAt, pending.UpdatedAt = intent.CreatedAt, intent.CreatedAt, intent.CreatedAt
+
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+		INSERT INTO upload_intents (`+uploadIntentColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			intent.ID, intent.UserID, intent.S3Key, intent.ContentType, intent.SizeBytes,
+			intent.Title, intent.Description, formatTime(intent.CreatedAt), formatTime(intent.ExpiresAt),
+		); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+		INSERT INTO photos (id, external_source, user_id, s3_url, title, description, author_name, width, height,
+			original_url, uploaded_at, created_at, updated_at, size_bytes, mime_type, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			pending.ID, pending.ExternalSource, pending.UserID, pending.S3URL, pending.Title, pending.Description,
+			pending.AuthorName, pending.Width, pending.Height, pending.OriginalURL, formatTime(pending.UploadedAt),
+			formatTime(pending.CreatedAt), formatTime(pending.UpdatedAt), pending.SizeBytes, pending.MimeType, pending.Status,
+		)
+		return err
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to create upload intent", "user_id", intent.UserID, "error", err)
+		return fmt.Errorf("ошибка при сохранении ожидающей загрузки: %w", err)
+	}
+
+	s.log(ctx).Info("upload intent created", "id", intent.ID, "user_id", intent.UserID, "s3_key", intent.S3Key)
+	return nil
+}
+
+// GetUploadIntent получает ожидающую загрузку по ID
+func (s *PhotoStorage) GetUploadIntent(ctx context.Context, id uuid.UUID) (*domain.UploadIntent, error) {
+	ctx, span := startSpan(ctx, "GetUploadIntent", attribute.String("upload_intent_id", id.String()))
+	defer span.End()
+
+	var intent domain.UploadIntent
+	err := s.db.GetContext(ctx, &intent, `SELECT `+uploadIntentColumns+` FROM upload_intents WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUploadIntentNotFound
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to get upload intent", "id", id, "error", err)
+		return nil, fmt.Errorf("ошибка при получении ожидающей загрузки: %w", err)
+	}
+	return &intent, nil
+}
+
+// ActivateUploadedPhoto заполняет pending-фото photo.ID данными загруженного файла, переводит его
+// в active и удаляет запись загрузки. Условие status = 'pending' в UPDATE гарантирует, что из
+// параллельных вызовов фото активирует только один; photo заполняется сохранённой строкой
+func (s *PhotoStorage) ActivateUploadedPhoto(ctx context.Context, photo *domain.Photo) error {
+	ctx, span := startSpan(ctx, "ActivateUploadedPhoto", attribute.String("photo_id", photo.ID.String()))
+	defer span.End()
+
+	now := time.Now()
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, photo, `
+		UPDATE photos
+		SET s3_url = ?1, original_url = ?2, width = ?3, height = ?4, size_bytes = ?5, mime_type = ?6,
+			dominant_colors = ?7, status = 'active', uploaded_at = ?8, created_at = ?8, updated_at = ?8
+		WHERE id = ?9 AND status = 'pending'
+		RETURNING `+photoColumns,
+			photo.S3URL, photo.OriginalURL, photo.Width, photo.Height, photo.SizeBytes, photo.MimeType,
+			photo.DominantColors, formatTime(now), photo.ID,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUploadIntentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM upload_intents WHERE id = ?`, photo.ID)
+		return err
+	})
+	if errors.Is(err, domain.ErrUploadIntentNotFound) {
+		return err
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to activate uploaded photo", "photo_id", photo.ID, "error", err)
+		return fmt.Errorf("ошибка при подтверждении загрузки фото: %w", err)
+	}
+
+	s.log(ctx).Info("uploaded photo activated", "photo_id", photo.ID)
+	return nil
+}
+
+// DeleteExpiredUploadIntents удаляет загрузки с expires_at раньше before вместе с их pending-фото
+// и возвращает загрузки
+func (s *PhotoStorage) DeleteExpiredUploadIntents(ctx context.Context, before time.Time) ([]domain.UploadIntent, error) {
+	ctx, span := startSpan(ctx, "DeleteExpiredUploadIntents")
+	defer span.End()
+
+	start := time.Now()
+
+	var expired []domain.UploadIntent
+	err := withTx(ctx, s.db, func(tx *sqlx.Tx) error {
+		if err := tx.SelectContext(ctx, &expired,
+			`DELETE FROM upload_intents WHERE expires_at < ? RETURNING `+uploadIntentColumns, formatTime(before)); err != nil {
+			return err
+		}
+		// pending-фото без загрузки остаются только от удалённых здесь: загрузка и её фото
+		// создаются в одной транзакции, а при подтверждении фото перестаёт быть pending
+		_, err := tx.ExecContext(ctx,
+			`DELETE FROM photos WHERE status = 'pending' AND id NOT IN (SELECT id FROM upload_intents)`)
+		return err
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to delete expired upload intents", "before", before, "error", err)
+		return nil, fmt.Errorf("ошибка при удалении истёкших ожидающих загрузок: %w", err)
+	}
+
+	s.log(ctx).Info("expired upload intents deleted",
+		"before", before,
+		"count", len(expired),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return expired, nil
+}
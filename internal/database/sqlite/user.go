@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const systemUsername = "system_user"
+
+// UserStorage реализует ports.UserStorage поверх SQLite
+type UserStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+
+	// ID системного пользователя не меняется, поэтому после первого запроса берётся из памяти
+	systemUserMu sync.Mutex
+	systemUserID uuid.UUID
+}
+
+// NewUserStorage создает новый экземпляр UserStorage
+func NewUserStorage(db *sqlx.DB, logger *slog.Logger) *UserStorage {
+	return &UserStorage{db: db, logger: logger}
+}
+
+// GetOrCreateSystemUser получает или создает системного пользователя в БД
+func (s *UserStorage) GetOrCreateSystemUser(ctx context.Context) (uuid.UUID, error) {
+	s.systemUserMu.Lock()
+	defer s.systemUserMu.Unlock()
+
+	if s.systemUserID != uuid.Nil {
+		return s.systemUserID, nil
+	}
+
+	ctx, span := startSpan(ctx, "GetOrCreateSystemUser")
+	defer span.End()
+
+	start := time.Now()
+
+	id := uuid.New()
+	now := formatTime(time.Now())
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, created_at, updated_at)
+		VALUES (?, ?, 'system@example.com', 'dummy_hash', ?, ?)
+		ON CONFLICT DO NOTHING
+	`, id, systemUsername, now, now)
+	if err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to insert system user", "error", err)
+		return uuid.Nil, fmt.Errorf("insert system user: %w", err)
+	}
+
+	// перечитываем: если пользователь уже был, вставка ничего не сделала
+	err = s.db.GetContext(ctx, &id, `SELECT id FROM users WHERE username = ?`, systemUsername)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.log(ctx).Error("system user email is taken by another user")
+		return uuid.Nil, fmt.Errorf("select system user: email system@example.com is already used by another user")
+	}
+	if err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to select system user", "error", err)
+		return uuid.Nil, fmt.Errorf("select system user: %w", err)
+	}
+
+	s.log(ctx).Info("system user ready",
+		"user_id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	s.systemUserID = id
+	return id, nil
+}
+
+// CreateUser сохраняет нового пользователя.
+// При нарушении уникальности username/email возвращает domain.ErrUserAlreadyExists
+func (s *UserStorage) CreateUser(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, user.ID, user.Username, user.Email, user.PasswordHash, formatTime(now), formatTime(now))
+	if err != nil {
+		if isUniqueViolation(err) {
+			s.log(ctx).Warn("user already exists", "username", user.Username)
+			return domain.ErrUserAlreadyExists
+		}
+		s.log(ctx).Error("failed to insert user", "username", user.Username, "error", err)
+		return fmt.Errorf("insert user: %w", err)
+	}
+
+	s.log(ctx).Info("user created successfully",
+		"user_id", user.ID,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// GetUserByEmail получает пользователя по email.
+// Если пользователь не найден, возвращает domain.ErrUserNotFound
+func (s *UserStorage) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+	err := s.db.GetContext(ctx, &user, `SELECT * FROM users WHERE email = ?`, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		s.log(ctx).Error("failed to select user by email", "error", err)
+		return nil, fmt.Errorf("select user by email: %w", err)
+	}
+	return &user, nil
+}
+
+// GetUserByID получает пользователя по ID.
+// Если пользователь не найден, возвращает domain.ErrUserNotFound
+func (s *UserStorage) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	var user domain.User
+	err := s.db.GetContext(ctx, &user, `SELECT * FROM users WHERE id = ?`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		s.log(ctx).Error("failed to select user by id", "user_id", id, "error", err)
+		return nil, fmt.Errorf("select user by id: %w", err)
+	}
+	return &user, nil
+}
+
+// UpdateUser обновляет только заданные в updates поля пользователя
+func (s *UserStorage) UpdateUser(ctx context.Context, id uuid.UUID, updates domain.UserUpdate) error {
+	start := time.Now()
+
+	sets := []string{"updated_at = ?"}
+	args := []interface{}{formatTime(time.Now())}
+	if updates.Username != nil {
+		sets = append(sets, "username = ?")
+		args = append(args, *updates.Username)
+	}
+	if updates.Email != nil {
+		sets = append(sets, "email = ?")
+		args = append(args, *updates.Email)
+	}
+	if updates.PasswordHash != nil {
+		sets = append(sets, "password_hash = ?")
+		args = append(args, *updates.PasswordHash)
+	}
+	args = append(args, id)
+
+	res, err := s.db.ExecContext(ctx, `UPDATE users SET `+strings.Join(sets, ", ")+` WHERE id = ?`, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			s.log(ctx).Warn("user update conflicts with existing user", "user_id", id)
+			return domain.ErrUserAlreadyExists
+		}
+		s.log(ctx).Error("failed to update user", "user_id", id, "error", err)
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	s.log(ctx).Info("user updated successfully",
+		"user_id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (s *UserStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGetOrCreateSystemUser_Concurrent(t *testing.T) {
+	const workers = 20
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	// у каждой горутины своё подключение и хранилище, как у сервера и воркера,
+	// которые стартуют одновременно: кэш в памяти одного хранилища гонку не скрывает
+	storages := make([]*UserStorage, workers)
+	for i := range storages {
+		db, err := Open(path, discardLogger())
+		if err != nil {
+			t.Fatalf("open sqlite: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		storages[i] = NewUserStorage(db, discardLogger())
+	}
+
+	ids := make([]uuid.UUID, workers)
+	errs := make([]error, workers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, s := range storages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			ids[i], errs[i] = s.GetOrCreateSystemUser(ctx)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("goroutine %d: %v", i, err)
+		}
+		if ids[i] != ids[0] {
+			t.Errorf("goroutine %d got system user %s, want %s", i, ids[i], ids[0])
+		}
+	}
+
+	var count int
+	if err := storages[0].db.GetContext(ctx, &count, `SELECT COUNT(*) FROM users WHERE username = ?`, systemUsername); err != nil {
+		t.Fatalf("count system users: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("system users = %d, want 1", count)
+	}
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PostgresAuditStorage реализует ports.AuditStorage поверх таблицы audit_logs
+type PostgresAuditStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewPostgresAuditStorage создает новый экземпляр PostgresAuditStorage
+func NewPostgresAuditStorage(db *sqlx.DB, logger *slog.Logger) *PostgresAuditStorage {
+	return &PostgresAuditStorage{db: db, logger: logger}
+}
+
+// RecordAuditEvent сохраняет событие аудита
+func (s *PostgresAuditStorage) RecordAuditEvent(ctx context.Context, event *domain.AuditLog) error {
+	ctx, span := startSpan(ctx, "RecordAuditEvent", attribute.String("action", event.Action))
+	defer span.End()
+
+	start := time.Now()
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+	INSERT INTO audit_logs (id, user_id, action, entity_type, entity_id, old_value, new_value, ip_address, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7::jsonb, $8, $9)`,
+		event.ID, nullableUUID(event.UserID), event.Action, event.EntityType, nullableUUID(event.EntityID),
+		nullableJSON(event.OldValue), nullableJSON(event.NewValue), event.IPAddress, event.CreatedAt,
+	)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to record audit event", "action", event.Action, "error", err)
+		return fmt.Errorf("ошибка при сохранении события аудита: %w", err)
+	}
+
+	s.log(ctx).Debug("audit event recorded",
+		"id", event.ID,
+		"action", event.Action,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// ListAuditEvents возвращает последние limit событий, новые первыми.
+// Пустой entityType — события по всем сущностям
+func (s *PostgresAuditStorage) ListAuditEvents(ctx context.Context, entityType string, limit int) ([]domain.AuditLog, error) {
+	ctx, span := startSpan(ctx, "ListAuditEvents", attribute.String("entity_type", entityType), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT id, user_id, action, entity_type, entity_id, old_value, new_value, COALESCE(ip_address, '') AS ip_address, created_at
+	FROM audit_logs
+	WHERE $1 = '' OR entity_type = $1
+	ORDER BY created_at DESC
+	LIMIT $2
+	`
+
+	events := []domain.AuditLog{}
+	if err := s.db.SelectContext(ctx, &events, q, entityType, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list audit events", "entity_type", entityType, "error", err)
+		return nil, fmt.Errorf("ошибка при получении журнала аудита: %w", err)
+	}
+
+	s.log(ctx).Info("listed audit events successfully",
+		"entity_type", entityType,
+		"count", len(events),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return events, nil
+}
+
+func (s *PostgresAuditStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
+
+// nullableUUID превращает uuid.Nil в NULL
+func nullableUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
+// nullableJSON передаёт JSON строкой (lib/pq шлёт []byte как bytea), пустой — как NULL
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
+
+// AsyncAuditStorage — обёртка над ports.AuditStorage, которая пишет события в фоне.
+// RecordAuditEvent только кладёт событие в буферизованный канал и никогда не блокирует запрос:
+// при переполненном буфере событие отбрасывается с предупреждением в логе
+type AsyncAuditStorage struct {
+	next   ports.AuditStorage
+	events chan auditJob
+	wg     sync.WaitGroup
+	once   sync.Once
+	logger *slog.Logger
+}
+
+// auditJob — событие в очереди вместе с ID запроса, чтобы фоновая запись попала в его логи
+type auditJob struct {
+	requestID string
+	event     *domain.AuditLog
+}
+
+// auditWriteTimeout ограничивает запись одного события в фоне
+const auditWriteTimeout = 5 * time.Second
+
+// NewAsyncAuditStorage запускает фоновую запись событий в next. Остановка — Close
+func NewAsyncAuditStorage(next ports.AuditStorage, bufferSize int, logger *slog.Logger) *AsyncAuditStorage {
+	a := &AsyncAuditStorage{
+		next:   next,
+		events: make(chan auditJob, bufferSize),
+		logger: logger,
+	}
+	a.wg.Add(1)
+	go a.run()
+	return a
+}
+
+// RecordAuditEvent ставит событие в очередь на запись. Ошибка не возвращается никогда:
+// аудит не должен ломать основной запрос
+func (a *AsyncAuditStorage) RecordAuditEvent(ctx context.Context, event *domain.AuditLog) error {
+	select {
+	case a.events <- auditJob{requestID: logger.RequestIDFromContext(ctx), event: event}:
+	default:
+		logger.FromContext(ctx, a.logger).Warn("audit buffer is full, event dropped", "action", event.Action)
+	}
+	return nil
+}
+
+// ListAuditEvents читает журнал напрямую из next
+func (a *AsyncAuditStorage) ListAuditEvents(ctx context.Context, entityType string, limit int) ([]domain.AuditLog, error) {
+	return a.next.ListAuditEvents(ctx, entityType, limit)
+}
+
+// Close перестаёт принимать события и дожидается записи уже поставленных в очередь.
+// После Close RecordAuditEvent вызывать нельзя
+func (a *AsyncAuditStorage) Close() error {
+	a.once.Do(func() { close(a.events) })
+	a.wg.Wait()
+	return nil
+}
+
+func (a *AsyncAuditStorage) run() {
+	defer a.wg.Done()
+	for job := range a.events {
+		ctx := context.Background()
+		if job.requestID != "" {
+			ctx = logger.WithRequestID(ctx, job.requestID)
+		}
+		ctx, cancel := context.WithTimeout(ctx, auditWriteTimeout)
+		if err := a.next.RecordAuditEvent(ctx, job.event); err != nil {
+			logger.FromContext(ctx, a.logger).Error("failed to write audit event", "action", job.event.Action, "error", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/database/migrator"
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
+	"github.com/GoArmGo/MediaApp/internal/database/storagetest"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // драйвер "postgres"
+)
+
+// openTestPostgres подключается к Postgres из TEST_DATABASE_URL и применяет миграции;
+// без переменной тест пропускается
+func openTestPostgres(t testing.TB) (*sqlx.DB, *slog.Logger) {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := sqlx.Connect("postgres", url)
+	if err != nil {
+		t.Fatalf("connect postgres: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := migrator.New(context.Background(), db, logger)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	if err := m.Up(false); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("close migrator: %v", err)
+	}
+	return db, logger
+}
+
+// TestStorageConformance прогоняет общий набор на Postgres из TEST_DATABASE_URL.
+// База очищается перед каждым тестом набора, поэтому рабочую указывать нельзя
+func TestStorageConformance(t *testing.T) {
+	db, logger := openTestPostgres(t)
+
+	storagetest.Run(t, func(t *testing.T) (ports.PhotoStorage, ports.UserStorage) {
+		if _, err := db.Exec(`TRUNCATE users, tags CASCADE`); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		slowQueryDB := monitor.NewSlowQueryDB(db, 0, logger)
+		return NewPostgresStorage(slowQueryDB, logger), NewUserStorage(slowQueryDB, logger)
+	})
+}
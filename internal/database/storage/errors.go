@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// pgUniqueViolation — код ошибки PostgreSQL при нарушении уникального ограничения
+const pgUniqueViolation = "23505"
+
+// isUniqueViolation проверяет, что ошибка вызвана нарушением уникальности
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PostgresIdempotencyStorage реализует ports.IdempotencyStorage поверх таблицы idempotency_cache
+type PostgresIdempotencyStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewPostgresIdempotencyStorage создает новый экземпляр PostgresIdempotencyStorage
+func NewPostgresIdempotencyStorage(db *sqlx.DB, logger *slog.Logger) *PostgresIdempotencyStorage {
+	return &PostgresIdempotencyStorage{db: db, logger: logger}
+}
+
+// ReserveIdempotencyKey занимает ключ одним INSERT ... ON CONFLICT: из параллельных запросов
+// с одинаковым ключом его получает только один. Истёкшая запись перезаписывается
+func (s *PostgresIdempotencyStorage) ReserveIdempotencyKey(ctx context.Context, key string, expiresBefore time.Time) (*domain.IdempotencyRecord, error) {
+	ctx, span := startSpan(ctx, "ReserveIdempotencyKey")
+	defer span.End()
+
+	// между неудачным INSERT и SELECT ключ могут освободить — тогда пробуем занять его ещё раз
+	for attempt := 0; attempt < 2; attempt++ {
+		var reserved string
+		err := s.db.GetContext(ctx, &reserved, `
+		INSERT INTO idempotency_cache (key, status_code, created_at) VALUES ($1, 0, NOW())
+		ON CONFLICT (key) DO UPDATE SET status_code = 0, response = NULL, created_at = NOW()
+		WHERE idempotency_cache.created_at < $2
+		RETURNING key`, key, expiresBefore)
+		if err == nil {
+			s.log(ctx).Debug("idempotency key reserved", "key", key)
+			return nil, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			recordDBError(span, err)
+			s.log(ctx).Error("failed to reserve idempotency key", "key", key, "error", err)
+			return nil, fmt.Errorf("ошибка при резервировании ключа идемпотентности: %w", err)
+		}
+
+		var record domain.IdempotencyRecord
+		err = s.db.GetContext(ctx, &record,
+			`SELECT key, status_code, response, created_at FROM idempotency_cache WHERE key = $1`, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		recordDBError(span, err)
+		if err != nil {
+			s.log(ctx).Error("failed to get idempotency record", "key", key, "error", err)
+			return nil, fmt.Errorf("ошибка при получении ключа идемпотентности: %w", err)
+		}
+		span.SetAttributes(attribute.Bool("idempotency.replay", true))
+		return &record, nil
+	}
+	return nil, fmt.Errorf("не удалось зарезервировать ключ идемпотентности %q", key)
+}
+
+// SaveIdempotentResponse сохраняет ответ для занятого ключа
+func (s *PostgresIdempotencyStorage) SaveIdempotentResponse(ctx context.Context, key string, statusCode int, response []byte) error {
+	ctx, span := startSpan(ctx, "SaveIdempotentResponse", attribute.Int("status_code", statusCode))
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE idempotency_cache SET status_code = $2, response = $3::jsonb WHERE key = $1`,
+		key, statusCode, nullableJSON(response))
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to save idempotent response", "key", key, "error", err)
+		return fmt.Errorf("ошибка при сохранении ответа по ключу идемпотентности: %w", err)
+	}
+
+	s.log(ctx).Debug("idempotent response saved", "key", key, "status_code", statusCode)
+	return nil
+}
+
+// ReleaseIdempotencyKey удаляет ещё не завершённую запись ключа
+func (s *PostgresIdempotencyStorage) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	ctx, span := startSpan(ctx, "ReleaseIdempotencyKey")
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_cache WHERE key = $1 AND status_code = 0`, key)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to release idempotency key", "key", key, "error", err)
+		return fmt.Errorf("ошибка при освобождении ключа идемпотентности: %w", err)
+	}
+
+	s.log(ctx).Debug("idempotency key released", "key", key)
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys удаляет записи старше olderThan
+func (s *PostgresIdempotencyStorage) DeleteExpiredIdempotencyKeys(ctx context.Context, olderThan time.Time) (int64, error) {
+	ctx, span := startSpan(ctx, "DeleteExpiredIdempotencyKeys")
+	defer span.End()
+
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_cache WHERE created_at < $1`, olderThan)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to delete expired idempotency keys", "error", err)
+		return 0, fmt.Errorf("ошибка при удалении истёкших ключей идемпотентности: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при удалении истёкших ключей идемпотентности: %w", err)
+	}
+
+	s.log(ctx).Info("expired idempotency keys deleted",
+		"deleted", deleted,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return deleted, nil
+}
+
+func (s *PostgresIdempotencyStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
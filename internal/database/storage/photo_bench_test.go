@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// benchRun нумерует пачки benchPhotos: повторные запуски бенчмарка не должны упираться в конфликт unsplash_id
+var benchRun atomic.Int64
+
+// benchPhotos возвращает n новых фото с уникальными unsplash_id
+func benchPhotos(userID uuid.UUID, n int) []*domain.Photo {
+	run := benchRun.Add(1)
+	photos := make([]*domain.Photo, n)
+	for i := range photos {
+		unsplashID := fmt.Sprintf("bench-%d-%d", run, i)
+		photos[i] = &domain.Photo{
+			UnsplashID:  unsplashID,
+			UserID:      userID,
+			Title:       "photo " + unsplashID,
+			OriginalURL: "https://images.example.com/" + unsplashID,
+			Tags:        []domain.Tag{{Name: "bench"}},
+		}
+	}
+	return photos
+}
+
+// BenchmarkSavePhotos сравнивает 100 отдельных SavePhotoTx с одним многострочным SavePhotos
+// на Postgres из TEST_DATABASE_URL
+func BenchmarkSavePhotos(b *testing.B) {
+	db, logger := openTestPostgres(b)
+	ctx := context.Background()
+	if _, err := db.Exec(`TRUNCATE users, tags CASCADE`); err != nil {
+		b.Fatalf("truncate: %v", err)
+	}
+	slowQueryDB := monitor.NewSlowQueryDB(db, 0, logger)
+	s := NewPostgresStorage(slowQueryDB, logger)
+	userID, err := NewUserStorage(slowQueryDB, logger).GetOrCreateSystemUser(ctx)
+	if err != nil {
+		b.Fatalf("create system user: %v", err)
+	}
+
+	const batchSize = 100
+	b.Run("single", func(b *testing.B) {
+		for range b.N {
+			for _, photo := range benchPhotos(userID, batchSize) {
+				if _, err := s.SavePhotoTx(ctx, photo); err != nil {
+					b.Fatalf("SavePhotoTx: %v", err)
+				}
+			}
+		}
+		b.ReportMetric(float64(b.N*batchSize)/b.Elapsed().Seconds(), "rows/s")
+	})
+	b.Run("batch", func(b *testing.B) {
+		for range b.N {
+			if _, err := s.SavePhotos(ctx, benchPhotos(userID, batchSize)); err != nil {
+				b.Fatalf("SavePhotos: %v", err)
+			}
+		}
+		b.ReportMetric(float64(b.N*batchSize)/b.Elapsed().Seconds(), "rows/s")
+	})
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LikePhoto добавляет фото в избранное пользователя. Лайк вставляется только для неудалённого фото,
+// поэтому 0 вставленных строк означает либо повторный лайк, либо отсутствующее фото
+func (s *PostgresStorage) LikePhoto(ctx context.Context, photoID, userID uuid.UUID) (bool, error) {
+	ctx, span := startSpan(ctx, "LikePhoto",
+		attribute.String("photo_id", photoID.String()),
+		attribute.String("user_id", userID.String()),
+	)
+	defer span.End()
+
+	res, err := s.db.ExecContext(ctx, `
+	INSERT INTO photo_likes (photo_id, user_id, created_at)
+	SELECT id, $2, NOW() FROM photos WHERE id = $1 AND deleted_at IS NULL AND status = 'active'
+	ON CONFLICT (photo_id, user_id) DO NOTHING`, photoID, userID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to like photo", "photo_id", photoID, "user_id", userID, "error", err)
+		return false, fmt.Errorf("ошибка при добавлении фото в избранное: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		s.log(ctx).Info("photo liked", "photo_id", photoID, "user_id", userID)
+		return true, nil
+	}
+
+	var exists bool
+	err = s.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM photos WHERE id = $1 AND deleted_at IS NULL AND status = 'active')`, photoID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to check photo existence", "photo_id", photoID, "error", err)
+		return false, fmt.Errorf("ошибка при добавлении фото в избранное: %w", err)
+	}
+	if !exists {
+		s.log(ctx).Warn("photo not found for like", "photo_id", photoID)
+		return false, domain.ErrPhotoNotFound
+	}
+	return false, nil
+}
+
+// UnlikePhoto убирает фото из избранного пользователя
+func (s *PostgresStorage) UnlikePhoto(ctx context.Context, photoID, userID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "UnlikePhoto",
+		attribute.String("photo_id", photoID.String()),
+		attribute.String("user_id", userID.String()),
+	)
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM photo_likes WHERE photo_id = $1 AND user_id = $2`, photoID, userID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to unlike photo", "photo_id", photoID, "user_id", userID, "error", err)
+		return fmt.Errorf("ошибка при удалении фото из избранного: %w", err)
+	}
+
+	s.log(ctx).Info("photo unliked", "photo_id", photoID, "user_id", userID)
+	return nil
+}
+
+// ListLikedPhotos получает страницу избранного пользователя, последние лайки первыми.
+// Лайки выбираются подзапросом: у photo_likes есть колонки user_id и created_at, совпадающие с photoColumns
+func (s *PostgresStorage) ListLikedPhotos(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListLikedPhotos", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	JOIN (SELECT photo_id, created_at AS liked_at FROM photo_likes WHERE user_id = $1) pl ON pl.photo_id = photos.id
+	WHERE photos.deleted_at IS NULL AND photos.status = 'active'
+	ORDER BY pl.liked_at DESC, photos.id
+	LIMIT $2 OFFSET $3
+	`
+
+	photos := []domain.Photo{}
+	if err := s.db.SelectContext(ctx, &photos, q, userID, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list liked photos", "user_id", userID, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении избранного: %w", err)
+	}
+
+	s.log(ctx).Info("listed liked photos successfully",
+		"user_id", userID,
+		"page", page,
+		"per_page", perPage,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountLikedPhotos считает неудалённые фото в избранном пользователя
+func (s *PostgresStorage) CountLikedPhotos(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := startSpan(ctx, "CountLikedPhotos", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	return s.count(ctx, span, `
+	SELECT COUNT(*) FROM photo_likes pl
+	JOIN photos p ON p.id = pl.photo_id
+	WHERE pl.user_id = $1 AND p.deleted_at IS NULL AND p.status = 'active'`, userID)
+}
+
+// GetPhotoLikes считает лайки фото и проверяет лайк userID одним запросом
+func (s *PostgresStorage) GetPhotoLikes(ctx context.Context, photoID, userID uuid.UUID) (domain.PhotoLikes, error) {
+	ctx, span := startSpan(ctx, "GetPhotoLikes", attribute.String("photo_id", photoID.String()))
+	defer span.End()
+
+	var likes domain.PhotoLikes
+	err := s.db.GetContext(ctx, &likes, `
+	SELECT COUNT(*) AS count, COUNT(*) FILTER (WHERE user_id = $2) > 0 AS liked
+	FROM photo_likes WHERE photo_id = $1`, photoID, userID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to get photo likes", "photo_id", photoID, "error", err)
+		return domain.PhotoLikes{}, fmt.Errorf("ошибка при получении лайков фото: %w", err)
+	}
+	return likes, nil
+}
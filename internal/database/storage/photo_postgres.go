@@ -6,43 +6,337 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
 	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// photoColumns — явный список колонок photos вместо SELECT *.
+// Nullable-колонки приводятся к пустой строке, чтобы сканироваться в string-поля domain.Photo
+const photoColumns = `id, COALESCE(unsplash_id, '') AS unsplash_id, external_source, COALESCE(external_id, '') AS external_id, user_id, s3_url,
+	COALESCE(title, '') AS title, COALESCE(description, '') AS description, author_name, width, height,
+	COALESCE(likes_count, 0) AS likes_count, original_url, uploaded_at,
+	COALESCE(views_count, 0) AS views_count, COALESCE(downloads_count, 0) AS downloads_count, unsplash_views, unsplash_downloads,
+	created_at, updated_at, deleted_at, size_bytes, mime_type, dominant_colors, popularity_score,
+	latitude, longitude, blur_hash, dominant_color, status`
+
 type PostgresStorage struct {
-	db     *sqlx.DB
+	db     *monitor.SlowQueryDB
 	logger *slog.Logger
 }
 
-func NewPostgresStorage(db *sqlx.DB, logger *slog.Logger) *PostgresStorage {
+func NewPostgresStorage(db *monitor.SlowQueryDB, logger *slog.Logger) *PostgresStorage {
 	return &PostgresStorage{db: db, logger: logger}
 }
 
-// SavePhoto сохраняет метаданные фотографии в базе данных
-func (s *PostgresStorage) SavePhoto(ctx context.Context, photo *domain.Photo) error {
+// SavePhoto сохраняет метаданные фотографии в базе данных. INSERT ... ON CONFLICT DO NOTHING
+// не вставляет повтор по unsplash_id, а следующий SELECT возвращает строку, которая победила
+// в гонке, поэтому параллельные сохранения одного фото получают одну и ту же запись
+func (s *PostgresStorage) SavePhoto(ctx context.Context, photo *domain.Photo) (bool, error) {
+	ctx, span := startSpan(ctx, "SavePhoto", attribute.String("unsplash_id", photo.UnsplashID))
+	defer span.End()
+
 	start := time.Now()
 
 	if photo.ID == uuid.Nil {
 		photo.ID = uuid.New()
 	}
 
+	setPhotoDefaults(photo)
+
 	query := `
-	INSERT INTO photos (id, unsplash_id, title, description, author_name, width, height, url_full, url_thumb, uploaded_at, created_at, updated_at)
-	VALUES (:id, :unsplash_id, :title, :description, :author_name, :width, :height, :url_full, :url_thumb, :uploaded_at, :created_at, :updated_at)
+	INSERT INTO photos (id, unsplash_id, external_source, user_id, s3_url, title, description, author_name, width, height,
+	                    likes_count, original_url, uploaded_at, views_count, downloads_count, created_at, updated_at,
+	                    size_bytes, mime_type, dominant_colors, latitude, longitude, blur_hash, dominant_color)
+	VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 	ON CONFLICT (unsplash_id) DO NOTHING
-	`
+	RETURNING id`
+
+	var id uuid.UUID
+	err := s.db.GetContext(ctx, &id, query,
+		photo.ID, photo.UnsplashID, photo.ExternalSource, photo.UserID, photo.S3URL, photo.Title, photo.Description, photo.AuthorName,
+		photo.Width, photo.Height, photo.LikesCount, photo.OriginalURL, photo.UploadedAt,
+		photo.ViewsCount, photo.DownloadsCount, photo.CreatedAt, photo.UpdatedAt,
+		photo.SizeBytes, photo.MimeType, photo.DominantColors, photo.Latitude, photo.Longitude, photo.BlurHash, photo.DominantColor,
+	)
+	created := err == nil
+	if errors.Is(err, sql.ErrNoRows) {
+		err = s.db.GetContext(ctx, photo, `SELECT `+photoColumns+` FROM photos WHERE unsplash_id = $1`, photo.UnsplashID)
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to save photo", "unsplash_id", photo.UnsplashID, "error", err)
+		return false, fmt.Errorf("ошибка при сохранении фото: %w", err)
+	}
+
+	span.SetAttributes(attribute.Bool("photo.created", created))
+	s.log(ctx).Info("photo saved successfully",
+		"id", photo.ID,
+		"unsplash_id", photo.UnsplashID,
+		"created", created,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return created, nil
+}
+
+// savePhotosChunkSize — сколько фото вставляется одним INSERT (по 24 параметра на строку)
+const savePhotosChunkSize = 100
+
+// SavePhotos сохраняет пачку фото вместе с тегами в одной транзакции.
+// Фото вставляются многострочными INSERT ... ON CONFLICT DO NOTHING по savePhotosChunkSize строк,
+// теги и связи photo_tags — одним запросом на всю пачку.
+// Возвращает ID вставленных фото; у них заполняются Tags. Остальные (конфликт по unsplash_id)
+// не вставляются: в той же транзакции они заполняются уже сохранёнными строками
+func (s *PostgresStorage) SavePhotos(ctx context.Context, photos []*domain.Photo) ([]uuid.UUID, error) {
+	ctx, span := startSpan(ctx, "SavePhotos", attribute.Int("photos.count", len(photos)))
+	defer span.End()
+
+	if len(photos) == 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+
+	for _, photo := range photos {
+		if photo.ID == uuid.Nil {
+			photo.ID = uuid.New()
+		}
+		setPhotoDefaults(photo)
+	}
+
+	var inserted []uuid.UUID
+	err := withTx(ctx, s.db, func(tx *monitor.SlowQueryTx) error {
+		inserted = inserted[:0]
+		for from := 0; from < len(photos); from += savePhotosChunkSize {
+			chunk := photos[from:min(from+savePhotosChunkSize, len(photos))]
+			ids, err := insertPhotosChunk(ctx, tx, chunk)
+			if err != nil {
+				return err
+			}
+			inserted = append(inserted, ids...)
+		}
+		if err := saveTagsForPhotos(ctx, tx, photos, inserted); err != nil {
+			return err
+		}
+		return loadConflictingPhotos(ctx, tx, photos, inserted)
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to save photos batch", "count", len(photos), "error", err)
+		return nil, err
+	}
+
+	duration := time.Since(start)
+	span.SetAttributes(attribute.Int("photos.inserted", len(inserted)))
+	s.log(ctx).Info("photos batch saved",
+		"count", len(photos),
+		"inserted", len(inserted),
+		"skipped", len(photos)-len(inserted),
+		"duration_ms", duration.Milliseconds(),
+		"rows_per_sec", float64(len(photos))/max(duration.Seconds(), 1e-9),
+	)
+	return inserted, nil
+}
+
+// insertPhotosChunk вставляет фото одним многострочным INSERT и возвращает ID вставленных
+func insertPhotosChunk(ctx context.Context, tx *monitor.SlowQueryTx, photos []*domain.Photo) ([]uuid.UUID, error) {
+	const columns = 24
+
+	var b strings.Builder
+	b.WriteString(`
+	INSERT INTO photos (id, unsplash_id, external_source, user_id, s3_url, title, description, author_name, width, height,
+	                    likes_count, original_url, uploaded_at, views_count, downloads_count, created_at, updated_at,
+	                    size_bytes, mime_type, dominant_colors, latitude, longitude, blur_hash, dominant_color)
+	VALUES `)
+	args := make([]interface{}, 0, len(photos)*columns)
+	for i, photo := range photos {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		n := i * columns
+		fmt.Fprintf(&b, "($%d, NULLIF($%d, ''), $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14, n+15, n+16, n+17, n+18, n+19, n+20, n+21, n+22, n+23, n+24)
+		args = append(args,
+			photo.ID, photo.UnsplashID, photo.ExternalSource, photo.UserID, photo.S3URL, photo.Title, photo.Description, photo.AuthorName,
+			photo.Width, photo.Height, photo.LikesCount, photo.OriginalURL, photo.UploadedAt,
+			photo.ViewsCount, photo.DownloadsCount, photo.CreatedAt, photo.UpdatedAt,
+			photo.SizeBytes, photo.MimeType, photo.DominantColors, photo.Latitude, photo.Longitude, photo.BlurHash, photo.DominantColor,
+		)
+	}
+	b.WriteString(` ON CONFLICT (unsplash_id) DO NOTHING RETURNING id`)
+
+	var ids []uuid.UUID
+	if err := tx.SelectContext(ctx, &ids, b.String(), args...); err != nil {
+		return nil, fmt.Errorf("ошибка при пакетном сохранении фото: %w", err)
+	}
+	return ids, nil
+}
+
+// loadConflictingPhotos заполняет фото, которые не вставились из-за конфликта по unsplash_id,
+// сохранёнными строками — одним запросом на всю пачку
+func loadConflictingPhotos(ctx context.Context, tx *monitor.SlowQueryTx, photos []*domain.Photo, inserted []uuid.UUID) error {
+	if len(inserted) == len(photos) {
+		return nil
+	}
+	insertedSet := make(map[uuid.UUID]struct{}, len(inserted))
+	for _, id := range inserted {
+		insertedSet[id] = struct{}{}
+	}
+	var unsplashIDs []string
+	for _, photo := range photos {
+		if _, ok := insertedSet[photo.ID]; !ok {
+			unsplashIDs = append(unsplashIDs, photo.UnsplashID)
+		}
+	}
+
+	var stored []domain.Photo
+	if err := tx.SelectContext(ctx, &stored,
+		`SELECT `+photoColumns+` FROM photos WHERE unsplash_id = ANY($1)`, pq.Array(unsplashIDs)); err != nil {
+		return fmt.Errorf("ошибка при получении уже сохранённых фото: %w", err)
+	}
+	byUnsplashID := make(map[string]domain.Photo, len(stored))
+	for _, photo := range stored {
+		byUnsplashID[photo.UnsplashID] = photo
+	}
+	for _, photo := range photos {
+		if _, ok := insertedSet[photo.ID]; ok {
+			continue
+		}
+		if existing, ok := byUnsplashID[photo.UnsplashID]; ok {
+			*photo = existing
+		}
+	}
+	return nil
+}
+
+// saveTagsForPhotos сохраняет теги вставленных фото и связи photo_tags для всей пачки сразу
+func saveTagsForPhotos(ctx context.Context, tx *monitor.SlowQueryTx, photos []*domain.Photo, inserted []uuid.UUID) error {
+	insertedSet := make(map[uuid.UUID]struct{}, len(inserted))
+	for _, id := range inserted {
+		insertedSet[id] = struct{}{}
+	}
+
+	photoTagNames := make(map[uuid.UUID][]string, len(inserted))
+	var allNames []string
+	for _, photo := range photos {
+		if _, ok := insertedSet[photo.ID]; !ok {
+			continue
+		}
+		names := normalizeTagNames(photo.Tags)
+		photoTagNames[photo.ID] = names
+		allNames = append(allNames, names...)
+	}
+	if len(allNames) == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO tags (name) SELECT DISTINCT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`,
+		pq.Array(allNames)); err != nil {
+		return fmt.Errorf("ошибка при сохранении тегов: %w", err)
+	}
+
+	var tags []domain.Tag
+	if err := tx.SelectContext(ctx, &tags, `SELECT id, name FROM tags WHERE name = ANY($1)`, pq.Array(allNames)); err != nil {
+		return fmt.Errorf("ошибка при получении тегов: %w", err)
+	}
+	tagsByName := make(map[string]domain.Tag, len(tags))
+	for _, tag := range tags {
+		tagsByName[tag.Name] = tag
+	}
+
+	var linkPhotoIDs, linkTagIDs []string
+	for _, photo := range photos {
+		names, ok := photoTagNames[photo.ID]
+		if !ok {
+			continue
+		}
+		photo.Tags = make([]domain.Tag, 0, len(names))
+		for _, name := range names {
+			tag := tagsByName[name]
+			photo.Tags = append(photo.Tags, tag)
+			linkPhotoIDs = append(linkPhotoIDs, photo.ID.String())
+			linkTagIDs = append(linkTagIDs, tag.ID.String())
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO photo_tags (photo_id, tag_id) SELECT unnest($1::uuid[]), unnest($2::uuid[]) ON CONFLICT DO NOTHING`,
+		pq.Array(linkPhotoIDs), pq.Array(linkTagIDs)); err != nil {
+		return fmt.Errorf("ошибка при связывании фото с тегами: %w", err)
+	}
+	return nil
+}
+
+// UpsertPhoto сохраняет фото, а если фото с таким unsplash_id уже есть — обновляет только
+// изменяемые метаданные (лайки, статистику Unsplash, описание, updated_at), а также
+// blur_hash и dominant_color, если источник их вернул: у фото, сохранённых до миграции 019, их нет.
+// Собственные счётчики views_count и downloads_count не перезаписываются: их увеличивают
+// IncrementViewsCount, IncrementDownloadsCount и AddViews, и обновление из источника не должно их затирать.
+// id, created_at, s3_url, user_id и остальные поля существующей записи не перезаписываются.
+// photo заполняется итоговой строкой из бд. Для фото в корзине возвращает domain.ErrPhotoNotFound
+func (s *PostgresStorage) UpsertPhoto(ctx context.Context, photo *domain.Photo) error {
+	ctx, span := startSpan(ctx, "UpsertPhoto", attribute.String("unsplash_id", photo.UnsplashID))
+	defer span.End()
+
+	start := time.Now()
+
+	if photo.ID == uuid.Nil {
+		photo.ID = uuid.New()
+	}
+	setPhotoDefaults(photo)
 
-	_, err := s.db.NamedExecContext(ctx, query, photo)
+	query := `
+	INSERT INTO photos (id, unsplash_id, external_source, user_id, s3_url, title, description, author_name, width, height,
+	                    likes_count, original_url, uploaded_at, views_count, downloads_count, created_at, updated_at,
+	                    size_bytes, mime_type, dominant_colors, latitude, longitude, blur_hash, dominant_color)
+	VALUES (:id, NULLIF(:unsplash_id, ''), :external_source, :user_id, :s3_url, :title, :description, :author_name, :width, :height,
+	        :likes_count, :original_url, :uploaded_at, :views_count, :downloads_count, :created_at, :updated_at,
+	        :size_bytes, :mime_type, :dominant_colors, :latitude, :longitude, :blur_hash, :dominant_color)
+	ON CONFLICT (unsplash_id) DO UPDATE SET
+		likes_count        = EXCLUDED.likes_count,
+		unsplash_views     = :unsplash_views,
+		unsplash_downloads = :unsplash_downloads,
+		description        = EXCLUDED.description,
+		blur_hash          = COALESCE(NULLIF(EXCLUDED.blur_hash, ''), photos.blur_hash),
+		dominant_color     = COALESCE(NULLIF(EXCLUDED.dominant_color, ''), photos.dominant_color),
+		updated_at         = NOW()
+	WHERE photos.deleted_at IS NULL AND photos.status = 'active'
+	RETURNING ` + photoColumns
+
+	rows, err := s.db.NamedQueryContext(ctx, query, photo)
 	if err != nil {
-		s.logger.Error("failed to save photo", "unsplash_id", photo.UnsplashID, "error", err)
-		return fmt.Errorf("ошибка при сохранении фото: %w", err)
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to upsert photo", "unsplash_id", photo.UnsplashID, "error", err)
+		return fmt.Errorf("ошибка при сохранении или обновлении фото: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			recordDBError(span, err)
+			s.log(ctx).Error("failed to upsert photo", "unsplash_id", photo.UnsplashID, "error", err)
+			return fmt.Errorf("ошибка при сохранении или обновлении фото: %w", err)
+		}
+		// конфликт с фото из корзины: WHERE не дал обновить строку
+		s.log(ctx).Warn("photo is deleted, upsert skipped", "unsplash_id", photo.UnsplashID)
+		return domain.ErrPhotoNotFound
+	}
+	if err := rows.StructScan(photo); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to scan upserted photo", "unsplash_id", photo.UnsplashID, "error", err)
+		return fmt.Errorf("ошибка при чтении обновлённого фото: %w", err)
 	}
 
-	s.logger.Info("photo saved successfully",
+	s.log(ctx).Info("photo upserted",
 		"id", photo.ID,
 		"unsplash_id", photo.UnsplashID,
 		"duration_ms", time.Since(start).Milliseconds(),
@@ -50,25 +344,218 @@ func (s *PostgresStorage) SavePhoto(ctx context.Context, photo *domain.Photo) er
 	return nil
 }
 
+// SavePhotoTx сохраняет фото вместе с тегами в одной транзакции:
+// вставляет фото, добавляет недостающие теги и связи photo_tags.
+// При любой ошибке транзакция откатывается, и в бд не остаётся частично сохранённого фото.
+// Если фото с таким unsplash_id уже есть, ничего не меняется: возвращается false,
+// а photo заполняется сохранённой строкой вместе с её тегами
+func (s *PostgresStorage) SavePhotoTx(ctx context.Context, photo *domain.Photo) (bool, error) {
+	ctx, span := startSpan(ctx, "SavePhotoTx", attribute.String("unsplash_id", photo.UnsplashID))
+	defer span.End()
+
+	start := time.Now()
+
+	if photo.ID == uuid.Nil {
+		photo.ID = uuid.New()
+	}
+	setPhotoDefaults(photo)
+	tagNames := normalizeTagNames(photo.Tags)
+
+	inserted := true
+	err := withTx(ctx, s.db, func(tx *monitor.SlowQueryTx) error {
+		var id uuid.UUID
+		err := tx.GetContext(ctx, &id, `
+		INSERT INTO photos (id, unsplash_id, external_source, user_id, s3_url, title, description, author_name, width, height,
+		                    likes_count, original_url, uploaded_at, views_count, downloads_count, created_at, updated_at,
+	                    size_bytes, mime_type, dominant_colors, latitude, longitude, blur_hash, dominant_color)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		ON CONFLICT (unsplash_id) DO NOTHING
+		RETURNING id`,
+			photo.ID, photo.UnsplashID, photo.ExternalSource, photo.UserID, photo.S3URL, photo.Title, photo.Description, photo.AuthorName,
+			photo.Width, photo.Height, photo.LikesCount, photo.OriginalURL, photo.UploadedAt,
+			photo.ViewsCount, photo.DownloadsCount, photo.CreatedAt, photo.UpdatedAt,
+			photo.SizeBytes, photo.MimeType, photo.DominantColors, photo.Latitude, photo.Longitude, photo.BlurHash, photo.DominantColor,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			inserted = false
+			var existing domain.Photo
+			if err := tx.GetContext(ctx, &existing,
+				`SELECT `+photoColumns+` FROM photos WHERE unsplash_id = $1`, photo.UnsplashID); err != nil {
+				return fmt.Errorf("ошибка при получении уже сохранённого фото: %w", err)
+			}
+			*photo = existing
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка при сохранении фото: %w", err)
+		}
+
+		if len(tagNames) == 0 {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`,
+			pq.Array(tagNames)); err != nil {
+			return fmt.Errorf("ошибка при сохранении тегов: %w", err)
+		}
+
+		rows, err := tx.QueryxContext(ctx, `SELECT id, name FROM tags WHERE name = ANY($1)`, pq.Array(tagNames))
+		if err != nil {
+			return fmt.Errorf("ошибка при получении тегов: %w", err)
+		}
+		tags := make([]domain.Tag, 0, len(tagNames))
+		tagIDs := make([]uuid.UUID, 0, len(tagNames))
+		for rows.Next() {
+			var tag domain.Tag
+			if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+				rows.Close()
+				return fmt.Errorf("ошибка при чтении тегов: %w", err)
+			}
+			tags = append(tags, tag)
+			tagIDs = append(tagIDs, tag.ID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("ошибка при получении тегов: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO photo_tags (photo_id, tag_id) SELECT $1, unnest($2::uuid[]) ON CONFLICT DO NOTHING`,
+			photo.ID, pq.Array(tagIDs)); err != nil {
+			return fmt.Errorf("ошибка при связывании фото с тегами: %w", err)
+		}
+
+		photo.Tags = tags
+		return nil
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to save photo in transaction", "unsplash_id", photo.UnsplashID, "error", err)
+		return false, err
+	}
+
+	if !inserted {
+		existing := []domain.Photo{*photo}
+		if err := s.attachTags(ctx, existing); err != nil {
+			return false, err
+		}
+		*photo = existing[0]
+		s.log(ctx).Warn("photo already exists, nothing saved", "id", photo.ID, "unsplash_id", photo.UnsplashID)
+		return false, nil
+	}
+
+	s.log(ctx).Info("photo saved with tags",
+		"id", photo.ID,
+		"unsplash_id", photo.UnsplashID,
+		"tags", len(photo.Tags),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return true, nil
+}
+
+// setPhotoDefaults заполняет незаданные created_at, updated_at и uploaded_at текущим временем,
+// чтобы в бд и в возвращаемом фото были одни и те же значения; источник по умолчанию — Unsplash
+func setPhotoDefaults(photo *domain.Photo) {
+	if photo.ExternalSource == "" {
+		photo.ExternalSource = domain.SourceUnsplash
+	}
+	now := time.Now()
+	if photo.CreatedAt.IsZero() {
+		photo.CreatedAt = now
+	}
+	if photo.UpdatedAt.IsZero() {
+		photo.UpdatedAt = now
+	}
+	if photo.UploadedAt.IsZero() {
+		photo.UploadedAt = now
+	}
+	// колонка status в INSERT не передаётся и по умолчанию active; pending пишет только CreateUploadIntent
+	if photo.Status == "" {
+		photo.Status = domain.PhotoStatusActive
+	}
+}
+
+// normalizeTagNames приводит имена тегов к нижнему регистру, убирает пустые, дубликаты
+// и слишком длинные (tags.name — VARCHAR(50))
+func normalizeTagNames(tags []domain.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return normalizeNames(names)
+}
+
+// normalizeNames — normalizeTagNames для имён без обёртки domain.Tag
+func normalizeNames(raw []string) []string {
+	seen := make(map[string]struct{}, len(raw))
+	names := make([]string, 0, len(raw))
+	for _, name := range raw {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || utf8.RuneCountInString(name) > 50 {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
 // GetPhotoByIDFromDB получает детали фото по ID
 func (s *PostgresStorage) GetPhotoByIDFromDB(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotoByIDFromDB", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	var photo domain.Photo
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE id = $1 AND deleted_at IS NULL AND status = 'active' LIMIT 1`
+
+	err := s.db.GetContext(ctx, &photo, query, id)
+	recordDBError(span, err)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.log(ctx).Warn("photo not found by id", "id", id)
+			return nil, nil
+		}
+		s.log(ctx).Error("failed to get photo by id", "id", id, "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото по ID: %w", err)
+	}
+
+	s.log(ctx).Info("photo retrieved by id",
+		"id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return &photo, nil
+}
+
+// GetPhotoByIDIncludingDeleted получает фото по ID, в том числе из корзины
+func (s *PostgresStorage) GetPhotoByIDIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotoByIDIncludingDeleted", attribute.String("photo_id", id.String()))
+	defer span.End()
+
 	start := time.Now()
 
 	var photo domain.Photo
-	query := `SELECT * FROM photos WHERE id = $1 LIMIT 1`
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE id = $1 AND status = 'active' LIMIT 1`
 
 	err := s.db.GetContext(ctx, &photo, query, id)
+	recordDBError(span, err)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("photo not found by id", "id", id)
+			s.log(ctx).Warn("photo not found by id", "id", id, "include_deleted", true)
 			return nil, nil
 		}
-		s.logger.Error("failed to get photo by id", "id", id, "error", err)
+		s.log(ctx).Error("failed to get photo by id", "id", id, "include_deleted", true, "error", err)
 		return nil, fmt.Errorf("ошибка при получении фото по ID: %w", err)
 	}
 
-	s.logger.Info("photo retrieved by id",
+	s.log(ctx).Info("photo retrieved by id",
 		"id", id,
+		"deleted", photo.DeletedAt != nil,
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
 	return &photo, nil
@@ -76,111 +563,1226 @@ func (s *PostgresStorage) GetPhotoByIDFromDB(ctx context.Context, id uuid.UUID)
 
 // GetPhotosByUnsplashIDFromDB получает фото по Unsplash ID.
 func (s *PostgresStorage) GetPhotosByUnsplashIDFromDB(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotosByUnsplashIDFromDB", attribute.String("unsplash_id", unsplashID))
+	defer span.End()
+
 	start := time.Now()
 
 	var photo domain.Photo
-	query := `SELECT * FROM photos WHERE unsplash_id = $1 LIMIT 1`
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE unsplash_id = $1 AND deleted_at IS NULL AND status = 'active' LIMIT 1`
 
 	err := s.db.GetContext(ctx, &photo, query, unsplashID)
+	recordDBError(span, err)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Warn("photo not found by unsplash_id", "unsplash_id", unsplashID)
+			s.log(ctx).Warn("photo not found by unsplash_id", "unsplash_id", unsplashID)
 			return nil, nil
 		}
-		s.logger.Error("failed to get photo by unsplash_id", "unsplash_id", unsplashID, "error", err)
+		s.log(ctx).Error("failed to get photo by unsplash_id", "unsplash_id", unsplashID, "error", err)
 		return nil, fmt.Errorf("ошибка при получении фото по Unsplash ID: %w", err)
 	}
 
-	s.logger.Info("photo retrieved by unsplash_id",
+	s.log(ctx).Info("photo retrieved by unsplash_id",
 		"unsplash_id", unsplashID,
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
 	return &photo, nil
 }
 
-// SearchPhotosInDB ищет фото.
-func (s *PostgresStorage) SearchPhotosInDB(ctx context.Context, query string, page, perPage int) ([]domain.Photo, error) {
+// ExistsByUnsplashIDs одним запросом проверяет, какие из переданных Unsplash ID уже есть в БД.
+// Мягко удалённые фото тоже считаются существующими, чтобы они не загружались повторно.
+// Возвращает отображение unsplash_id -> внутренний id только для найденных фото
+func (s *PostgresStorage) ExistsByUnsplashIDs(ctx context.Context, unsplashIDs []string) (map[string]uuid.UUID, error) {
+	ctx, span := startSpan(ctx, "ExistsByUnsplashIDs", attribute.Int("ids.count", len(unsplashIDs)))
+	defer span.End()
+
 	start := time.Now()
 
-	offset := (page - 1) * perPage
-	q := `
-	SELECT * FROM photos
-	WHERE LOWER(title) LIKE LOWER($1)
-	   OR LOWER(description) LIKE LOWER($1)
-	   OR LOWER(author_name) LIKE LOWER($1)
-	ORDER BY uploaded_at DESC
-	LIMIT $2 OFFSET $3
-	`
+	existing := make(map[string]uuid.UUID, len(unsplashIDs))
+	if len(unsplashIDs) == 0 {
+		return existing, nil
+	}
 
-	searchTerm := "%" + query + "%"
-	var photos []domain.Photo
+	rows, err := s.db.QueryxContext(ctx, `SELECT unsplash_id, id FROM photos WHERE unsplash_id = ANY($1)`, pq.Array(unsplashIDs))
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to check photos existence", "count", len(unsplashIDs), "error", err)
+		return nil, fmt.Errorf("ошибка при проверке существования фото: %w", err)
+	}
+	defer rows.Close()
 
-	if err := s.db.SelectContext(ctx, &photos, q, searchTerm, perPage, offset); err != nil {
-		s.logger.Error("failed to search photos",
-			"query", query,
-			"page", page,
-			"per_page", perPage,
-			"error", err,
+	for rows.Next() {
+		var (
+			unsplashID string
+			id         uuid.UUID
 		)
-		return nil, fmt.Errorf("ошибка при поиске фото: %w", err)
+		if err := rows.Scan(&unsplashID, &id); err != nil {
+			s.log(ctx).Error("failed to scan photo existence row", "error", err)
+			return nil, fmt.Errorf("ошибка при чтении результата проверки существования фото: %w", err)
+		}
+		existing[unsplashID] = id
+	}
+	if err := rows.Err(); err != nil {
+		s.log(ctx).Error("failed to iterate photo existence rows", "error", err)
+		return nil, fmt.Errorf("ошибка при проверке существования фото: %w", err)
 	}
 
-	s.logger.Info("photos search completed",
-		"query", query,
+	s.log(ctx).Info("photos existence checked",
+		"requested", len(unsplashIDs),
+		"existing", len(existing),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return existing, nil
+}
+
+// FindPhotosByUnsplashIDs получает фото по списку Unsplash ID одним запросом, включая фото в корзине
+func (s *PostgresStorage) FindPhotosByUnsplashIDs(ctx context.Context, unsplashIDs []string) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "FindPhotosByUnsplashIDs", attribute.Int("ids.count", len(unsplashIDs)))
+	defer span.End()
+
+	start := time.Now()
+
+	var photos []domain.Photo
+	if len(unsplashIDs) == 0 {
+		return photos, nil
+	}
+
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE unsplash_id = ANY($1)`
+	if err := s.db.SelectContext(ctx, &photos, query, pq.Array(unsplashIDs)); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to find photos by unsplash_ids", "count", len(unsplashIDs), "error", err)
+		return nil, fmt.Errorf("ошибка при поиске фото по списку Unsplash ID: %w", err)
+	}
+
+	s.log(ctx).Info("photos found by unsplash_ids",
+		"requested", len(unsplashIDs),
 		"found", len(photos),
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
 	return photos, nil
 }
 
-// ListAllPhotosInDB получает все фото
-func (s *PostgresStorage) ListAllPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+// GetPhotosByUnsplashIDsFromDB получает фото по списку Unsplash ID одним запросом
+func (s *PostgresStorage) GetPhotosByUnsplashIDsFromDB(ctx context.Context, unsplashIDs []string) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotosByUnsplashIDsFromDB", attribute.Int("ids.count", len(unsplashIDs)))
+	defer span.End()
+
+	start := time.Now()
+
+	var photos []domain.Photo
+	if len(unsplashIDs) == 0 {
+		return photos, nil
+	}
+
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE unsplash_id = ANY($1) AND deleted_at IS NULL AND status = 'active'`
+	if err := s.db.SelectContext(ctx, &photos, query, pq.Array(unsplashIDs)); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to get photos by unsplash_ids", "count", len(unsplashIDs), "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото по списку Unsplash ID: %w", err)
+	}
+
+	s.log(ctx).Info("photos retrieved by unsplash_ids",
+		"requested", len(unsplashIDs),
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// likeEscaper экранирует спецсимволы LIKE, чтобы префикс искался буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// AutocompletePhotoTitles ищет подсказки по префиксу среди названий фото и имён авторов.
+// LOWER(...) LIKE 'префикс%' использует индексы text_pattern_ops из миграции 007
+func (s *PostgresStorage) AutocompletePhotoTitles(ctx context.Context, prefix string, limit int) ([]string, error) {
+	ctx, span := startSpan(ctx, "AutocompletePhotoTitles", attribute.String("prefix", prefix), attribute.Int("limit", limit))
+	defer span.End()
+
 	start := time.Now()
 
-	offset := (page - 1) * perPage
 	q := `
-	SELECT * FROM photos
-	ORDER BY uploaded_at DESC
-	LIMIT $1 OFFSET $2
+	SELECT suggestion FROM (
+		SELECT title AS suggestion FROM photos
+		WHERE deleted_at IS NULL AND status = 'active' AND LOWER(title) LIKE LOWER($1) || '%'
+		UNION
+		SELECT author_name FROM photos
+		WHERE deleted_at IS NULL AND status = 'active' AND LOWER(author_name) LIKE LOWER($1) || '%'
+	) s
+	ORDER BY suggestion
+	LIMIT $2
 	`
 
-	var photos []domain.Photo
-	if err := s.db.SelectContext(ctx, &photos, q, perPage, offset); err != nil {
-		s.logger.Error("failed to list all photos", "page", page, "per_page", perPage, "error", err)
-		return nil, fmt.Errorf("ошибка при получении всех фото: %w", err)
+	suggestions := []string{}
+	if err := s.db.SelectContext(ctx, &suggestions, q, likeEscaper.Replace(prefix), limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to autocomplete photo titles", "prefix", prefix, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске подсказок: %w", err)
 	}
 
-	s.logger.Info("listed all photos successfully",
-		"page", page,
-		"per_page", perPage,
-		"count", len(photos),
+	s.log(ctx).Info("autocomplete suggestions found",
+		"prefix", prefix,
+		"count", len(suggestions),
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
-	return photos, nil
+	return suggestions, nil
 }
 
-// ListPhotosInDB получает список фотографий из БД с пагинацией
-func (s *PostgresStorage) ListPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+// GetTagCloud считает фото вне корзины по каждому тегу, самые частые теги первыми.
+// Подсчёт по tag_id использует индекс idx_photo_tags_tag_id из миграции 021
+func (s *PostgresStorage) GetTagCloud(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	ctx, span := startSpan(ctx, "GetTagCloud", attribute.Int("limit", limit))
+	defer span.End()
+
 	start := time.Now()
 
-	offset := (page - 1) * perPage
 	q := `
-	SELECT * FROM photos
-	ORDER BY created_at DESC
-	LIMIT $1 OFFSET $2
+	SELECT t.id, t.name, COUNT(pt.photo_id) AS count
+	FROM tags t
+	JOIN photo_tags pt ON pt.tag_id = t.id
+	JOIN photos p ON p.id = pt.photo_id AND p.deleted_at IS NULL AND p.status = 'active'
+	GROUP BY t.id
+	ORDER BY count DESC, t.name
+	LIMIT $1
 	`
 
-	var photos []domain.Photo
-	if err := s.db.SelectContext(ctx, &photos, q, perPage, offset); err != nil {
-		s.logger.Error("failed to list photos", "page", page, "per_page", perPage, "error", err)
-		return nil, fmt.Errorf("ошибка при получении списка фото: %w", err)
+	tags := []domain.TagCount{}
+	if err := s.db.SelectContext(ctx, &tags, q, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to get tag cloud", "limit", limit, "error", err)
+		return nil, fmt.Errorf("ошибка при получении облака тегов: %w", err)
 	}
 
-	s.logger.Info("listed photos successfully",
-		"page", page,
-		"per_page", perPage,
-		"count", len(photos),
+	s.log(ctx).Info("tag cloud fetched",
+		"count", len(tags),
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
-	return photos, nil
+	return tags, nil
+}
+
+// SearchTagsByPrefix ищет теги по префиксу названия.
+// LOWER(name) LIKE 'префикс%' использует индекс text_pattern_ops из миграции 021
+func (s *PostgresStorage) SearchTagsByPrefix(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	ctx, span := startSpan(ctx, "SearchTagsByPrefix", attribute.String("prefix", prefix), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT id, name FROM tags
+	WHERE LOWER(name) LIKE LOWER($1) || '%'
+	ORDER BY name
+	LIMIT $2
+	`
+
+	tags := []domain.Tag{}
+	if err := s.db.SelectContext(ctx, &tags, q, likeEscaper.Replace(prefix), limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to search tags", "prefix", prefix, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске тегов: %w", err)
+	}
+
+	s.log(ctx).Info("tags found by prefix",
+		"prefix", prefix,
+		"count", len(tags),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return tags, nil
+}
+
+// BatchUpdateTags в одной транзакции снимает с фото теги removeTags и добавляет addTags.
+// Фото в корзине не меняются; у изменённых фото обновляется updated_at
+func (s *PostgresStorage) BatchUpdateTags(ctx context.Context, photoIDs []uuid.UUID, addTags, removeTags []string) (int64, error) {
+	ctx, span := startSpan(ctx, "BatchUpdateTags",
+		attribute.Int("ids.count", len(photoIDs)),
+		attribute.Int("tags.add", len(addTags)),
+		attribute.Int("tags.remove", len(removeTags)),
+	)
+	defer span.End()
+
+	start := time.Now()
+
+	add, remove := normalizeNames(addTags), normalizeNames(removeTags)
+	if len(photoIDs) == 0 || (len(add) == 0 && len(remove) == 0) {
+		return 0, nil
+	}
+
+	strIDs := make([]string, len(photoIDs))
+	for i, id := range photoIDs {
+		strIDs[i] = id.String()
+	}
+
+	var updated []string
+	err := withTx(ctx, s.db, func(tx *monitor.SlowQueryTx) error {
+		// UPDATE блокирует строки фото до конца транзакции и отсеивает фото в корзине
+		if err := tx.SelectContext(ctx, &updated, `
+		UPDATE photos SET updated_at = NOW()
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL AND status = 'active'
+		RETURNING id`, pq.Array(strIDs)); err != nil {
+			return fmt.Errorf("ошибка при обновлении фото: %w", err)
+		}
+		if len(updated) == 0 {
+			return nil
+		}
+
+		if len(remove) > 0 {
+			if _, err := tx.ExecContext(ctx, `
+			DELETE FROM photo_tags pt USING tags t
+			WHERE pt.tag_id = t.id AND t.name = ANY($1::text[]) AND pt.photo_id = ANY($2::uuid[])`,
+				pq.Array(remove), pq.Array(updated)); err != nil {
+				return fmt.Errorf("ошибка при удалении тегов фото: %w", err)
+			}
+		}
+
+		if len(add) > 0 {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO tags (name) SELECT DISTINCT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`,
+				pq.Array(add)); err != nil {
+				return fmt.Errorf("ошибка при сохранении тегов: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, `
+			INSERT INTO photo_tags (photo_id, tag_id)
+			SELECT p.id, t.id FROM unnest($1::uuid[]) AS p(id) CROSS JOIN tags t
+			WHERE t.name = ANY($2::text[])
+			ON CONFLICT DO NOTHING`,
+				pq.Array(updated), pq.Array(add)); err != nil {
+				return fmt.Errorf("ошибка при связывании фото с тегами: %w", err)
+			}
+		}
+		return nil
+	})
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to batch update tags", "count", len(photoIDs), "error", err)
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("photos.updated", len(updated)))
+	s.log(ctx).Info("photo tags batch updated",
+		"requested", len(photoIDs),
+		"updated", len(updated),
+		"added", len(add),
+		"removed", len(remove),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return int64(len(updated)), nil
+}
+
+// GetPhotosByIDs получает фото по списку внутренних ID одним запросом
+func (s *PostgresStorage) GetPhotosByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "GetPhotosByIDs", attribute.Int("ids.count", len(ids)))
+	defer span.End()
+
+	start := time.Now()
+
+	var photos []domain.Photo
+	if len(ids) == 0 {
+		return photos, nil
+	}
+
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = id.String()
+	}
+
+	query := `SELECT ` + photoColumns + ` FROM photos WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL AND status = 'active'`
+	if err := s.db.SelectContext(ctx, &photos, query, pq.Array(strIDs)); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to get photos by ids", "count", len(ids), "error", err)
+		return nil, fmt.Errorf("ошибка при получении фото по списку ID: %w", err)
+	}
+
+	s.log(ctx).Info("photos retrieved by ids",
+		"requested", len(ids),
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// SearchPhotosInDB ищет фото. Без явной сортировки самые релевантные идут первыми
+func (s *PostgresStorage) SearchPhotosInDB(ctx context.Context, query string, page, perPage int, filter domain.PhotoSearchFilter, sort domain.PhotoSort) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "SearchPhotosInDB", attribute.String("query", query), attribute.String("sort", sort.Field))
+	defer span.End()
+
+	start := time.Now()
+
+	orderBy, err := orderByClause(sort, "rank DESC NULLS LAST, uploaded_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * perPage
+	where, rank, args, err := searchConditions(query, filter)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, perPage, offset)
+	q := "SELECT " + photoColumns + ", " + rank + " AS rank FROM photos WHERE " + where +
+		fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", orderBy, len(args)-1, len(args))
+
+	var photos []domain.Photo
+
+	if err := s.db.SelectContext(ctx, &photos, q, args...); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to search photos",
+			"query", query,
+			"page", page,
+			"per_page", perPage,
+			"error", err,
+		)
+		return nil, fmt.Errorf("ошибка при поиске фото: %w", err)
+	}
+
+	s.log(ctx).Info("photos search completed",
+		"query", query,
+		"min_likes", filter.MinLikes,
+		"min_width", filter.MinWidth,
+		"min_height", filter.MinHeight,
+		"author", filter.Author,
+		"orientation", filter.Orientation,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// ListAllPhotosInDB получает все фото
+func (s *PostgresStorage) ListAllPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListAllPhotosInDB")
+	defer span.End()
+
+	start := time.Now()
+
+	offset := (page - 1) * perPage
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NULL AND status = 'active'
+	ORDER BY uploaded_at DESC
+	LIMIT $1 OFFSET $2
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, perPage, offset); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list all photos", "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении всех фото: %w", err)
+	}
+
+	s.log(ctx).Info("listed all photos successfully",
+		"page", page,
+		"per_page", perPage,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// minFullTextQueryLength — более короткие запросы ищутся подстрокой (ILIKE):
+// полнотекстовый поиск по одной-двум буквам почти ничего не находит
+const minFullTextQueryLength = 3
+
+// searchConditions собирает условие WHERE, выражение релевантности и аргументы для поиска
+// по тексту и фильтрам filter (каждый — отдельное параметризованное условие). Общее для SearchPhotosInDB и CountSearchResults,
+// чтобы счётчик совпадал с выдачей.
+// Обычно ищется по search_vector (GIN-индекс, миграция 008) с ранжированием ts_rank;
+// для коротких запросов — ILIKE по title, description и author_name без ранга (NULL).
+// Некорректный фильтр возвращает ошибку, оборачивающую domain.ErrInvalidFilter
+func searchConditions(query string, filter domain.PhotoSearchFilter) (string, string, []interface{}, error) {
+	if err := filter.Validate(); err != nil {
+		return "", "", nil, err
+	}
+
+	var where, rank string
+	var args []interface{}
+	if utf8.RuneCountInString(strings.TrimSpace(query)) >= minFullTextQueryLength {
+		where = `deleted_at IS NULL AND status = 'active' AND search_vector @@ plainto_tsquery('english', $1)`
+		rank = `ts_rank(search_vector, plainto_tsquery('english', $1))`
+		args = []interface{}{query}
+	} else {
+		where = `deleted_at IS NULL AND status = 'active'
+		  AND (title ILIKE $1
+		   OR description ILIKE $1
+		   OR author_name ILIKE $1)`
+		rank = `NULL::real`
+		args = []interface{}{"%" + likeEscaper.Replace(query) + "%"}
+	}
+
+	if filter.MinLikes > 0 {
+		args = append(args, filter.MinLikes)
+		where += fmt.Sprintf(" AND likes_count >= $%d", len(args))
+	}
+	if filter.MinWidth > 0 {
+		args = append(args, filter.MinWidth)
+		where += fmt.Sprintf(" AND width >= $%d", len(args))
+	}
+	if filter.MinHeight > 0 {
+		args = append(args, filter.MinHeight)
+		where += fmt.Sprintf(" AND height >= $%d", len(args))
+	}
+	if filter.Author != "" {
+		args = append(args, filter.Author)
+		where += fmt.Sprintf(" AND LOWER(author_name) = LOWER($%d)", len(args))
+	}
+	if filter.Orientation != "" {
+		where += " AND " + orientationConditions[filter.Orientation]
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where += fmt.Sprintf(" AND uploaded_at >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where += fmt.Sprintf(" AND uploaded_at < $%d", len(args))
+	}
+	return where, rank, args, nil
+}
+
+// orientationConditions — условие на размеры фото для каждой ориентации фильтра поиска
+var orientationConditions = map[string]string{
+	domain.OrientationLandscape: "width > height",
+	domain.OrientationPortrait:  "height > width",
+	domain.OrientationSquarish:  "width = height",
+}
+
+// CountSearchResults считает все фото, подходящие под поиск (без пагинации)
+func (s *PostgresStorage) CountSearchResults(ctx context.Context, query string, filter domain.PhotoSearchFilter) (int64, error) {
+	ctx, span := startSpan(ctx, "CountSearchResults", attribute.String("query", query))
+	defer span.End()
+
+	where, _, args, err := searchConditions(query, filter)
+	if err != nil {
+		return 0, err
+	}
+	return s.count(ctx, span, "SELECT COUNT(*) FROM photos WHERE "+where, args...)
+}
+
+// colorSearchCondition — фото с цветом палитры не дальше $2 от $1; palette_distance объявлена в миграции 012
+const colorSearchCondition = `deleted_at IS NULL AND status = 'active' AND palette_distance(dominant_colors, $1) <= $2`
+
+// SearchPhotosByColor ищет фото по цвету палитры, самые близкие по цвету первыми
+func (s *PostgresStorage) SearchPhotosByColor(ctx context.Context, color string, maxDistance float64, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "SearchPhotosByColor", attribute.String("color", color), attribute.Float64("max_distance", maxDistance))
+	defer span.End()
+
+	start := time.Now()
+
+	offset := (page - 1) * perPage
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE ` + colorSearchCondition + `
+	ORDER BY palette_distance(dominant_colors, $1), uploaded_at DESC, id
+	LIMIT $3 OFFSET $4
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, color, maxDistance, perPage, offset); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to search photos by color", "color", color, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске фото по цвету: %w", err)
+	}
+
+	s.log(ctx).Info("photos color search completed",
+		"color", color,
+		"max_distance", maxDistance,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountPhotosByColor считает фото, подходящие под SearchPhotosByColor
+func (s *PostgresStorage) CountPhotosByColor(ctx context.Context, color string, maxDistance float64) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosByColor", attribute.String("color", color))
+	defer span.End()
+
+	return s.count(ctx, span, "SELECT COUNT(*) FROM photos WHERE "+colorSearchCondition, color, maxDistance)
+}
+
+// nearCondition — фото не дальше $3 метров от точки ($1 — широта, $2 — долгота).
+// ST_DWithin по geography использует GIST-индекс idx_photos_geo_point из миграции 018
+const nearCondition = `deleted_at IS NULL AND status = 'active' AND ST_DWithin(geo_point, ST_SetSRID(ST_MakePoint($2, $1), 4326)::geography, $3)`
+
+// FindPhotosNear ищет фото рядом с точкой, ближайшие первыми
+func (s *PostgresStorage) FindPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "FindPhotosNear",
+		attribute.Float64("lat", lat), attribute.Float64("lon", lon), attribute.Float64("radius_km", radiusKm))
+	defer span.End()
+
+	start := time.Now()
+
+	offset := (page - 1) * perPage
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE ` + nearCondition + `
+	ORDER BY ST_Distance(geo_point, ST_SetSRID(ST_MakePoint($2, $1), 4326)::geography), id
+	LIMIT $4 OFFSET $5
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, lat, lon, radiusKm*1000, perPage, offset); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to find photos near point", "lat", lat, "lon", lon, "radius_km", radiusKm, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске фото рядом с точкой: %w", err)
+	}
+
+	s.log(ctx).Info("photos near point found",
+		"lat", lat,
+		"lon", lon,
+		"radius_km", radiusKm,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountPhotosNear считает фото, подходящие под FindPhotosNear
+func (s *PostgresStorage) CountPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosNear", attribute.Float64("radius_km", radiusKm))
+	defer span.End()
+
+	return s.count(ctx, span, "SELECT COUNT(*) FROM photos WHERE "+nearCondition, lat, lon, radiusKm*1000)
+}
+
+// UpdatePhotoEmbedding сохраняет эмбеддинг фото. updated_at не меняется: эмбеддинг не виден в карточке фото
+func (s *PostgresStorage) UpdatePhotoEmbedding(ctx context.Context, id uuid.UUID, vector []float32) error {
+	ctx, span := startSpan(ctx, "UpdatePhotoEmbedding", attribute.String("photo_id", id.String()), attribute.Int("dimensions", len(vector)))
+	defer span.End()
+
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `UPDATE photos SET embedding = $2::vector WHERE id = $1 AND deleted_at IS NULL AND status = 'active'`,
+		id, domain.Vector(vector))
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to update photo embedding", "id", id, "error", err)
+		return fmt.Errorf("ошибка при сохранении эмбеддинга фото %s: %w", id, err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		s.log(ctx).Warn("photo not found for embedding update", "id", id)
+		return domain.ErrPhotoNotFound
+	}
+
+	s.log(ctx).Info("photo embedding updated",
+		"id", id,
+		"dimensions", len(vector),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// GetPhotoEmbedding возвращает эмбеддинг фото или nil, если он ещё не вычислен
+func (s *PostgresStorage) GetPhotoEmbedding(ctx context.Context, id uuid.UUID) ([]float32, error) {
+	ctx, span := startSpan(ctx, "GetPhotoEmbedding", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	var vector domain.Vector
+	err := s.db.GetContext(ctx, &vector, `SELECT embedding::text FROM photos WHERE id = $1 AND deleted_at IS NULL AND status = 'active'`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPhotoNotFound
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to get photo embedding", "id", id, "error", err)
+		return nil, fmt.Errorf("ошибка при получении эмбеддинга фото %s: %w", id, err)
+	}
+	return vector, nil
+}
+
+// FindSimilarPhotos ищет фото, ближайшие к vector по евклидову расстоянию.
+// Оператор <-> из pgvector использует HNSW-индекс idx_photos_embedding из миграции 020
+func (s *PostgresStorage) FindSimilarPhotos(ctx context.Context, vector []float32, excludeID uuid.UUID, limit int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "FindSimilarPhotos", attribute.String("photo_id", excludeID.String()), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NULL AND status = 'active' AND embedding IS NOT NULL AND id <> $2
+	ORDER BY embedding <-> $1::vector
+	LIMIT $3
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, domain.Vector(vector), excludeID, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to find similar photos", "photo_id", excludeID, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске похожих фото: %w", err)
+	}
+
+	s.log(ctx).Info("similar photos found",
+		"photo_id", excludeID,
+		"found", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// CountPhotosInDB считает фото, не находящиеся в корзине
+func (s *PostgresStorage) CountPhotosInDB(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosInDB")
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NULL AND status = 'active'`)
+}
+
+// CountDeletedPhotosInDB считает фото в корзине
+func (s *PostgresStorage) CountDeletedPhotosInDB(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "CountDeletedPhotosInDB")
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NOT NULL`)
+}
+
+// count выполняет запрос SELECT COUNT(*) и логирует результат
+func (s *PostgresStorage) count(ctx context.Context, span trace.Span, q string, args ...interface{}) (int64, error) {
+	start := time.Now()
+
+	var total int64
+	err := s.db.GetContext(ctx, &total, q, args...)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to count photos", "error", err)
+		return 0, fmt.Errorf("ошибка при подсчёте фото: %w", err)
+	}
+
+	s.log(ctx).Debug("photos counted",
+		"total", total,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return total, nil
+}
+
+// ListPhotosInDB получает список фотографий из БД с пагинацией. Без явной сортировки — новые первыми
+func (s *PostgresStorage) ListPhotosInDB(ctx context.Context, page, perPage int, sort domain.PhotoSort) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListPhotosInDB", attribute.String("sort", sort.Field))
+	defer span.End()
+
+	start := time.Now()
+
+	orderBy, err := orderByClause(sort, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * perPage
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NULL AND status = 'active'
+	ORDER BY ` + orderBy + `
+	LIMIT $1 OFFSET $2
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, perPage, offset); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list photos", "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении списка фото: %w", err)
+	}
+
+	s.log(ctx).Info("listed photos successfully",
+		"page", page,
+		"per_page", perPage,
+		"sort", sort.Field,
+		"desc", sort.Desc,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// photoSortColumns — белый список колонок для ORDER BY: в запрос попадает только значение из карты,
+// а не строка от клиента
+var photoSortColumns = map[string]string{
+	domain.SortByCreatedAt:  "created_at",
+	domain.SortByUploadedAt: "uploaded_at",
+	domain.SortByLikes:      "likes_count",
+	domain.SortByViews:      "views_count",
+	domain.SortByDownloads:  "downloads_count",
+	domain.SortByPopularity: "popularity_score",
+}
+
+// orderByClause собирает выражение ORDER BY для sort или возвращает defaultOrder для нулевой сортировки.
+// id в конце делает порядок стабильным между страницами при равных значениях
+func orderByClause(sort domain.PhotoSort, defaultOrder string) (string, error) {
+	if sort.IsZero() {
+		return defaultOrder, nil
+	}
+	column, ok := photoSortColumns[sort.Field]
+	if !ok {
+		return "", fmt.Errorf("%w: неизвестное поле %q", domain.ErrInvalidSort, sort.Field)
+	}
+	direction := "ASC"
+	if sort.Desc {
+		direction = "DESC"
+	}
+	return column + " " + direction + " NULLS LAST, id " + direction, nil
+}
+
+// ListPhotosByAuthor получает страницу фото автора. Имя сравнивается целиком, без учёта регистра
+func (s *PostgresStorage) ListPhotosByAuthor(ctx context.Context, authorName string, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListPhotosByAuthor", attribute.String("author_name", authorName))
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, `LOWER(author_name) = LOWER($1)`, authorName, page, perPage)
+}
+
+// CountPhotosByAuthor считает фото автора, не находящиеся в корзине
+func (s *PostgresStorage) CountPhotosByAuthor(ctx context.Context, authorName string) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosByAuthor", attribute.String("author_name", authorName))
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NULL AND status = 'active' AND LOWER(author_name) = LOWER($1)`, authorName)
+}
+
+// ListPhotosByUser получает страницу фото, сохранённых пользователем
+func (s *PostgresStorage) ListPhotosByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListPhotosByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	return s.listPhotosPage(ctx, span, `user_id = $1`, userID, page, perPage)
+}
+
+// CountPhotosByUser считает фото пользователя, не находящиеся в корзине
+func (s *PostgresStorage) CountPhotosByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := startSpan(ctx, "CountPhotosByUser", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	return s.count(ctx, span, `SELECT COUNT(*) FROM photos WHERE deleted_at IS NULL AND status = 'active' AND user_id = $1`, userID)
+}
+
+// listPhotosPage получает страницу неудалённых фото по условию cond с единственным параметром $1
+func (s *PostgresStorage) listPhotosPage(ctx context.Context, span trace.Span, cond string, arg interface{}, page, perPage int) ([]domain.Photo, error) {
+	start := time.Now()
+
+	offset := (page - 1) * perPage
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NULL AND status = 'active' AND ` + cond + `
+	ORDER BY created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, arg, perPage, offset); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list photos", "filter", arg, "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении списка фото: %w", err)
+	}
+
+	s.log(ctx).Info("listed photos successfully",
+		"filter", arg,
+		"page", page,
+		"per_page", perPage,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// ListPhotosWithTagsInDB получает страницу фотографий вместе с тегами.
+// Теги загружаются одним дополнительным запросом на всю страницу, а не по запросу на фото
+func (s *PostgresStorage) ListPhotosWithTagsInDB(ctx context.Context, page, perPage int, sort domain.PhotoSort) ([]domain.Photo, error) {
+	photos, err := s.ListPhotosInDB(ctx, page, perPage, sort)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTags(ctx, photos); err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// exportRow — строка выгрузки: фото и имена его тегов, собранные подзапросом
+type exportRow struct {
+	domain.Photo
+	TagNames pq.StringArray `db:"tag_names"`
+}
+
+// StreamPhotosFromDB отправляет в out фото из выборки filter, читая строки курсором
+func (s *PostgresStorage) StreamPhotosFromDB(ctx context.Context, filter domain.PhotoFilter, out chan<- domain.Photo) error {
+	ctx, span := startSpan(ctx, "StreamPhotosFromDB", attribute.String("query", filter.Query))
+	defer span.End()
+
+	start := time.Now()
+
+	where, args := "deleted_at IS NULL AND status = 'active'", []interface{}{}
+	if filter.Query != "" {
+		// нулевой фильтр всегда корректен
+		where, _, args, _ = searchConditions(filter.Query, domain.PhotoSearchFilter{})
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where += fmt.Sprintf(" AND uploaded_at >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where += fmt.Sprintf(" AND uploaded_at < $%d", len(args))
+	}
+	q := `
+	SELECT ` + photoColumns + `,
+	       ARRAY(SELECT t.name FROM photo_tags pt JOIN tags t ON t.id = pt.tag_id
+	             WHERE pt.photo_id = photos.id ORDER BY t.name) AS tag_names
+	FROM photos
+	WHERE ` + where + `
+	ORDER BY uploaded_at, id
+	`
+
+	rows, err := s.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to start photo export", "error", err)
+		return fmt.Errorf("ошибка при выгрузке фото: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var row exportRow
+		if err := rows.StructScan(&row); err != nil {
+			recordDBError(span, err)
+			s.log(ctx).Error("failed to scan exported photo", "exported", count, "error", err)
+			return fmt.Errorf("ошибка при чтении выгружаемого фото: %w", err)
+		}
+		for _, name := range row.TagNames {
+			row.Photo.Tags = append(row.Photo.Tags, domain.Tag{Name: name})
+		}
+		select {
+		case out <- row.Photo:
+			count++
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("photo export interrupted", "exported", count, "error", err)
+		return fmt.Errorf("ошибка при выгрузке фото: %w", err)
+	}
+
+	s.log(ctx).Info("photos exported",
+		"query", filter.Query,
+		"count", count,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// attachTags загружает теги для всех переданных фото одним запросом и раскладывает их в памяти
+func (s *PostgresStorage) attachTags(ctx context.Context, photos []domain.Photo) error {
+	if len(photos) == 0 {
+		return nil
+	}
+	start := time.Now()
+
+	ids := make([]uuid.UUID, 0, len(photos))
+	index := make(map[uuid.UUID]int, len(photos))
+	for i, p := range photos {
+		ids = append(ids, p.ID)
+		index[p.ID] = i
+	}
+
+	q := `
+	SELECT pt.photo_id, t.id, t.name
+	FROM photo_tags pt
+	JOIN tags t ON t.id = pt.tag_id
+	WHERE pt.photo_id = ANY($1)
+	ORDER BY t.name
+	`
+
+	rows, err := s.db.QueryxContext(ctx, q, pq.Array(ids))
+	if err != nil {
+		s.log(ctx).Error("failed to load tags for photos", "count", len(photos), "error", err)
+		return fmt.Errorf("ошибка при получении тегов фото: %w", err)
+	}
+	defer rows.Close()
+
+	tagsCount := 0
+	for rows.Next() {
+		var (
+			photoID uuid.UUID
+			tag     domain.Tag
+		)
+		if err := rows.Scan(&photoID, &tag.ID, &tag.Name); err != nil {
+			s.log(ctx).Error("failed to scan photo tag row", "error", err)
+			return fmt.Errorf("ошибка при чтении тегов фото: %w", err)
+		}
+		if i, ok := index[photoID]; ok {
+			photos[i].Tags = append(photos[i].Tags, tag)
+			tagsCount++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		s.log(ctx).Error("failed to iterate photo tag rows", "error", err)
+		return fmt.Errorf("ошибка при получении тегов фото: %w", err)
+	}
+
+	s.log(ctx).Info("tags attached to photos",
+		"photos", len(photos),
+		"tags", tagsCount,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// SetPhotoDeletedAt перемещает фото в корзину (deletedAt != nil) или восстанавливает его (deletedAt == nil).
+// Если фото не найдено или уже в нужном состоянии, возвращает domain.ErrPhotoNotFound
+func (s *PostgresStorage) SetPhotoDeletedAt(ctx context.Context, id uuid.UUID, deletedAt *time.Time) error {
+	ctx, span := startSpan(ctx, "SetPhotoDeletedAt",
+		attribute.String("photo_id", id.String()), attribute.Bool("deleted", deletedAt != nil))
+	defer span.End()
+
+	if deletedAt == nil {
+		q := `UPDATE photos SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`
+		return s.execAffectingPhoto(ctx, span, "restore", q, id)
+	}
+	q := `UPDATE photos SET deleted_at = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL AND status = 'active'`
+	return s.execAffectingPhoto(ctx, span, "soft delete", q, id, *deletedAt)
+}
+
+// IncrementViewsCount атомарно увеличивает счётчик просмотров фото на 1 и запоминает время просмотра.
+// updated_at тоже обновляется, чтобы сменился ETag карточки фото
+func (s *PostgresStorage) IncrementViewsCount(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "IncrementViewsCount", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	q := `UPDATE photos SET views_count = views_count + 1, last_viewed_at = NOW(), updated_at = NOW()
+	WHERE id = $1 AND deleted_at IS NULL AND status = 'active'`
+	return s.execAffectingPhoto(ctx, span, "record view of", q, id)
+}
+
+// IncrementDownloadsCount атомарно увеличивает счётчик скачиваний фото на 1
+func (s *PostgresStorage) IncrementDownloadsCount(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "IncrementDownloadsCount", attribute.String("photo_id", id.String()))
+	defer span.End()
+
+	q := `UPDATE photos SET downloads_count = downloads_count + 1, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL AND status = 'active'`
+	return s.execAffectingPhoto(ctx, span, "record download of", q, id)
+}
+
+// AddViews прибавляет накопленные просмотры сразу к нескольким фото одним UPDATE и обновляет last_viewed_at.
+// updated_at не трогаем: просмотр не меняет само фото, а ETag карточки учитывает views_count отдельно
+func (s *PostgresStorage) AddViews(ctx context.Context, views map[uuid.UUID]int64) error {
+	ctx, span := startSpan(ctx, "AddViews", attribute.Int("photos.count", len(views)))
+	defer span.End()
+
+	if len(views) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+
+	ids := make([]string, 0, len(views))
+	counts := make([]int64, 0, len(views))
+	for id, n := range views {
+		ids = append(ids, id.String())
+		counts = append(counts, n)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+	UPDATE photos AS p SET views_count = p.views_count + v.n, last_viewed_at = NOW()
+	FROM unnest($1::uuid[], $2::bigint[]) AS v(id, n)
+	WHERE p.id = v.id AND p.deleted_at IS NULL AND p.status = 'active'`,
+		pq.Array(ids), pq.Array(counts))
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to add photo views", "photos", len(views), "error", err)
+		return fmt.Errorf("ошибка при обновлении счётчиков просмотров: %w", err)
+	}
+
+	updated, _ := res.RowsAffected()
+	s.log(ctx).Debug("photo views added",
+		"photos", len(views),
+		"updated", updated,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// UpdatePopularityScores пересчитывает оценку популярности всех фото вне корзины.
+// Функция popularity_score объявлена в миграции 017; updated_at не меняется, чтобы не сбивать ETag
+func (s *PostgresStorage) UpdatePopularityScores(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "UpdatePopularityScores")
+	defer span.End()
+
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `
+	UPDATE photos SET popularity_score = popularity_score(
+		COALESCE(views_count, 0), COALESCE(likes_count, 0), COALESCE(downloads_count, 0),
+		EXTRACT(EPOCH FROM NOW() - uploaded_at) / 86400)
+	WHERE deleted_at IS NULL AND status = 'active'`)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to update popularity scores", "error", err)
+		return 0, fmt.Errorf("ошибка при пересчёте популярности фото: %w", err)
+	}
+
+	updated, _ := res.RowsAffected()
+	s.log(ctx).Info("popularity scores updated",
+		"updated", updated,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return updated, nil
+}
+
+// ListTrendingPhotos получает самые популярные фото по popularity_score
+func (s *PostgresStorage) ListTrendingPhotos(ctx context.Context, limit int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListTrendingPhotos", attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NULL AND status = 'active'
+	ORDER BY popularity_score DESC, id
+	LIMIT $1
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list trending photos", "limit", limit, "error", err)
+		return nil, fmt.Errorf("ошибка при получении популярных фото: %w", err)
+	}
+
+	s.log(ctx).Info("listed trending photos successfully",
+		"limit", limit,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// ListRecentlyViewedPhotos получает фото источника source по убыванию времени последнего просмотра
+func (s *PostgresStorage) ListRecentlyViewedPhotos(ctx context.Context, source string, limit int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListRecentlyViewedPhotos", attribute.String("source", source), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NULL AND status = 'active' AND last_viewed_at IS NOT NULL AND external_source = $1
+	ORDER BY last_viewed_at DESC
+	LIMIT $2
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, source, limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list recently viewed photos", "source", source, "limit", limit, "error", err)
+		return nil, fmt.Errorf("ошибка при получении недавно просмотренных фото: %w", err)
+	}
+
+	s.log(ctx).Info("listed recently viewed photos successfully",
+		"source", source,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// execAffectingPhoto выполняет UPDATE одного фото ($1 — id, дальше — args)
+// и превращает 0 затронутых строк в domain.ErrPhotoNotFound
+func (s *PostgresStorage) execAffectingPhoto(ctx context.Context, span trace.Span, action, q string, id uuid.UUID, args ...any) error {
+	start := time.Now()
+
+	res, err := s.db.ExecContext(ctx, q, append([]any{id}, args...)...)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to "+action+" photo", "id", id, "error", err)
+		return fmt.Errorf("ошибка при изменении фото %s (%s): %w", id, action, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка при изменении фото %s (%s): %w", id, action, err)
+	}
+	if affected == 0 {
+		s.log(ctx).Warn("photo not found for "+action, "id", id)
+		return domain.ErrPhotoNotFound
+	}
+
+	s.log(ctx).Info("photo "+action+" completed",
+		"id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// ListDeletedPhotosInDB получает мягко удалённые фото (корзину), последние удалённые первыми
+func (s *PostgresStorage) ListDeletedPhotosInDB(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "ListDeletedPhotosInDB")
+	defer span.End()
+
+	start := time.Now()
+
+	offset := (page - 1) * perPage
+	q := `
+	SELECT ` + photoColumns + ` FROM photos
+	WHERE deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC
+	LIMIT $1 OFFSET $2
+	`
+
+	var photos []domain.Photo
+	if err := s.db.SelectContext(ctx, &photos, q, perPage, offset); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list deleted photos", "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении удалённых фото: %w", err)
+	}
+
+	s.log(ctx).Info("listed deleted photos successfully",
+		"page", page,
+		"per_page", perPage,
+		"count", len(photos),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return photos, nil
+}
+
+// PurgeDeletedPhotos окончательно удаляет фото, помеченные удалёнными раньше olderThan.
+// Возвращает удалённые фото (id, unsplash_id, источник и s3_url), чтобы вызывающий код удалил их файлы
+func (s *PostgresStorage) PurgeDeletedPhotos(ctx context.Context, olderThan time.Time) ([]domain.Photo, error) {
+	ctx, span := startSpan(ctx, "PurgeDeletedPhotos")
+	defer span.End()
+
+	start := time.Now()
+
+	rows, err := s.db.QueryxContext(ctx,
+		`DELETE FROM photos WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		RETURNING id, COALESCE(unsplash_id, ''), external_source, s3_url`, olderThan)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to purge deleted photos", "older_than", olderThan, "error", err)
+		return nil, fmt.Errorf("ошибка при окончательном удалении фото: %w", err)
+	}
+	defer rows.Close()
+
+	var purged []domain.Photo
+	for rows.Next() {
+		var photo domain.Photo
+		if err := rows.Scan(&photo.ID, &photo.UnsplashID, &photo.ExternalSource, &photo.S3URL); err != nil {
+			s.log(ctx).Error("failed to scan purged photo row", "error", err)
+			return nil, fmt.Errorf("ошибка при чтении удалённых фото: %w", err)
+		}
+		purged = append(purged, photo)
+	}
+	if err := rows.Err(); err != nil {
+		s.log(ctx).Error("failed to iterate purged photo rows", "error", err)
+		return nil, fmt.Errorf("ошибка при окончательном удалении фото: %w", err)
+	}
+
+	s.log(ctx).Info("deleted photos purged",
+		"older_than", olderThan,
+		"count", len(purged),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return purged, nil
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (s *PostgresStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
 }
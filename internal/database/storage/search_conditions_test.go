@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+func TestSearchConditions(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	const fullText = `deleted_at IS NULL AND status = 'active' AND search_vector @@ plainto_tsquery('english', $1)`
+
+	tests := []struct {
+		name    string
+		query   string
+		filter  domain.PhotoSearchFilter
+		where   string // условие после текстового
+		args    []interface{}
+		wantErr bool
+	}{
+		{name: "empty filter", query: "cats", args: []interface{}{"cats"}},
+		{name: "negative minimums are ignored", query: "cats",
+			filter: domain.PhotoSearchFilter{MinLikes: -1, MinWidth: -5, MinHeight: -5},
+			args:   []interface{}{"cats"}},
+		{name: "min likes", query: "cats", filter: domain.PhotoSearchFilter{MinLikes: 10},
+			where: " AND likes_count >= $2", args: []interface{}{"cats", 10}},
+		{name: "date range, author, orientation and min likes", query: "cats",
+			filter: domain.PhotoSearchFilter{MinLikes: 10, Author: "Jane Doe", Orientation: domain.OrientationPortrait, From: &from, To: &to},
+			where: " AND likes_count >= $2 AND LOWER(author_name) = LOWER($3) AND height > width" +
+				" AND uploaded_at >= $4 AND uploaded_at < $5",
+			args: []interface{}{"cats", 10, "Jane Doe", from, to}},
+		{name: "all filters", query: "cats",
+			filter: domain.PhotoSearchFilter{MinLikes: 1, MinWidth: 2, MinHeight: 3, Author: "jane",
+				Orientation: domain.OrientationSquarish, From: &from, To: &to},
+			where: " AND likes_count >= $2 AND width >= $3 AND height >= $4 AND LOWER(author_name) = LOWER($5)" +
+				" AND width = height AND uploaded_at >= $6 AND uploaded_at < $7",
+			args: []interface{}{"cats", 1, 2, 3, "jane", from, to}},
+		{name: "open-ended range", query: "cats", filter: domain.PhotoSearchFilter{From: &from},
+			where: " AND uploaded_at >= $2", args: []interface{}{"cats", from}},
+		{name: "orientation has no argument", query: "cats", filter: domain.PhotoSearchFilter{Orientation: domain.OrientationLandscape},
+			where: " AND width > height", args: []interface{}{"cats"}},
+		{name: "range ends before it starts", query: "cats", filter: domain.PhotoSearchFilter{From: &to, To: &from}, wantErr: true},
+		{name: "empty range", query: "cats", filter: domain.PhotoSearchFilter{From: &from, To: &from}, wantErr: true},
+		{name: "unknown orientation", query: "cats", filter: domain.PhotoSearchFilter{Orientation: "diagonal"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, rank, args, err := searchConditions(tt.query, tt.filter)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidFilter) {
+					t.Fatalf("err = %v, want domain.ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("searchConditions: %v", err)
+			}
+			if want := fullText + tt.where; where != want {
+				t.Errorf("where =\n%s\nwant\n%s", where, want)
+			}
+			if !strings.HasPrefix(rank, "ts_rank(") {
+				t.Errorf("rank = %q, want ts_rank for a full-text query", rank)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+
+	t.Run("short query keeps the filter numbering", func(t *testing.T) {
+		where, rank, args, err := searchConditions("ca", domain.PhotoSearchFilter{MinLikes: 5, Author: "jane"})
+		if err != nil {
+			t.Fatalf("searchConditions: %v", err)
+		}
+		if !strings.Contains(where, "title ILIKE $1") || !strings.HasSuffix(where, " AND likes_count >= $2 AND LOWER(author_name) = LOWER($3)") {
+			t.Errorf("where = %s", where)
+		}
+		if rank != "NULL::real" {
+			t.Errorf("rank = %q, want NULL::real for a short query", rank)
+		}
+		if want := []interface{}{"%ca%", 5, "jane"}; !reflect.DeepEqual(args, want) {
+			t.Errorf("args = %v, want %v", args, want)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PostgresSearchHistoryStorage реализует ports.SearchHistoryStorage поверх таблицы search_queries
+type PostgresSearchHistoryStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewPostgresSearchHistoryStorage создает новый экземпляр PostgresSearchHistoryStorage
+func NewPostgresSearchHistoryStorage(db *sqlx.DB, logger *slog.Logger) *PostgresSearchHistoryStorage {
+	return &PostgresSearchHistoryStorage{db: db, logger: logger}
+}
+
+// RecordSearchQuery сохраняет выполненный поиск
+func (s *PostgresSearchHistoryStorage) RecordSearchQuery(ctx context.Context, query *domain.SearchQuery) error {
+	ctx, span := startSpan(ctx, "RecordSearchQuery", attribute.String("source", query.Source))
+	defer span.End()
+
+	start := time.Now()
+
+	if query.ID == uuid.Nil {
+		query.ID = uuid.New()
+	}
+	if query.CreatedAt.IsZero() {
+		query.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.NamedExecContext(ctx, `
+	INSERT INTO search_queries (id, query, page, per_page, results_saved, source, created_at)
+	VALUES (:id, :query, :page, :per_page, :results_saved, :source, :created_at)`, query)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to record search query", "query", query.Query, "error", err)
+		return fmt.Errorf("ошибка при сохранении поискового запроса: %w", err)
+	}
+
+	s.log(ctx).Debug("search query recorded",
+		"id", query.ID,
+		"query", query.Query,
+		"source", query.Source,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// ListSearchQueries возвращает страницу истории поиска, последние запросы первыми
+func (s *PostgresSearchHistoryStorage) ListSearchQueries(ctx context.Context, page, perPage int) ([]domain.SearchQuery, error) {
+	ctx, span := startSpan(ctx, "ListSearchQueries")
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT id, query, page, per_page, results_saved, source, created_at
+	FROM search_queries
+	ORDER BY created_at DESC
+	LIMIT $1 OFFSET $2
+	`
+
+	queries := []domain.SearchQuery{}
+	if err := s.db.SelectContext(ctx, &queries, q, perPage, (page-1)*perPage); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to list search queries", "page", page, "per_page", perPage, "error", err)
+		return nil, fmt.Errorf("ошибка при получении истории поиска: %w", err)
+	}
+
+	s.log(ctx).Info("listed search queries successfully",
+		"page", page,
+		"per_page", perPage,
+		"count", len(queries),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return queries, nil
+}
+
+// CountSearchQueries считает все записи истории поиска
+func (s *PostgresSearchHistoryStorage) CountSearchQueries(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "CountSearchQueries")
+	defer span.End()
+
+	var total int64
+	err := s.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM search_queries`)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to count search queries", "error", err)
+		return 0, fmt.Errorf("ошибка при подсчёте истории поиска: %w", err)
+	}
+	return total, nil
+}
+
+// SuggestSearchQueries возвращает самые частые запросы, начинающиеся с prefix.
+// Запросы, отличающиеся только регистром, считаются одним и возвращаются в нижнем регистре
+func (s *PostgresSearchHistoryStorage) SuggestSearchQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	ctx, span := startSpan(ctx, "SuggestSearchQueries", attribute.String("prefix", prefix), attribute.Int("limit", limit))
+	defer span.End()
+
+	start := time.Now()
+
+	q := `
+	SELECT LOWER(query) AS query, COUNT(*) AS count
+	FROM search_queries
+	WHERE LOWER(query) LIKE LOWER($1) || '%'
+	GROUP BY LOWER(query)
+	ORDER BY count DESC, query
+	LIMIT $2
+	`
+
+	suggestions := []domain.SearchSuggestion{}
+	if err := s.db.SelectContext(ctx, &suggestions, q, likeEscaper.Replace(prefix), limit); err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to suggest search queries", "prefix", prefix, "error", err)
+		return nil, fmt.Errorf("ошибка при поиске подсказок по истории поиска: %w", err)
+	}
+
+	s.log(ctx).Info("search query suggestions found",
+		"prefix", prefix,
+		"count", len(suggestions),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return suggestions, nil
+}
+
+func (s *PostgresSearchHistoryStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
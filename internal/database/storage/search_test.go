@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// newTestPostgresStorage возвращает хранилище фото на очищенной базе из TEST_DATABASE_URL
+// и ID пользователя-владельца фото
+func newTestPostgresStorage(t *testing.T) (*PostgresStorage, *sqlx.DB, uuid.UUID) {
+	t.Helper()
+	db, logger := openTestPostgres(t)
+	if _, err := db.Exec(`TRUNCATE users, tags CASCADE`); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	slowQueryDB := monitor.NewSlowQueryDB(db, 0, logger)
+	userID, err := NewUserStorage(slowQueryDB, logger).GetOrCreateSystemUser(context.Background())
+	if err != nil {
+		t.Fatalf("create system user: %v", err)
+	}
+	return NewPostgresStorage(slowQueryDB, logger), db, userID
+}
+
+// searchFixtures сохраняет фото, в которых слово fox встречается в разных полях search_vector
+func searchFixtures(t *testing.T, s *PostgresStorage, userID uuid.UUID) {
+	t.Helper()
+	for _, photo := range []domain.Photo{
+		{UnsplashID: "by-author", Title: "river bank", AuthorName: "Fox Studio"},
+		{UnsplashID: "in-description", Title: "forest", Description: "a quiet fox between trees", AuthorName: "ann"},
+		{UnsplashID: "in-title", Title: "Foxes running in snow", AuthorName: "ann"},
+		{UnsplashID: "unrelated", Title: "blue whale", Description: "ocean", AuthorName: "ann"},
+	} {
+		photo.UserID = userID
+		photo.OriginalURL = "https://images.example.com/" + photo.UnsplashID
+		if _, err := s.SavePhotoTx(context.Background(), &photo); err != nil {
+			t.Fatalf("save %s: %v", photo.UnsplashID, err)
+		}
+	}
+}
+
+func TestSearchPhotosInDB_FullText(t *testing.T) {
+	s, _, userID := newTestPostgresStorage(t)
+	ctx := context.Background()
+	searchFixtures(t, s, userID)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		// название весит больше описания, описание — больше автора
+		{name: "ranked by field weight", query: "fox", want: []string{"in-title", "in-description", "by-author"}},
+		{name: "stemmed", query: "runs", want: []string{"in-title"}},
+		{name: "all words must match", query: "fox whale", want: nil},
+		// короткие запросы ищутся подстрокой без ранга
+		{name: "short query", query: "wh", want: []string{"unrelated"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			photos, err := s.SearchPhotosInDB(ctx, tt.query, 1, 10, domain.PhotoSearchFilter{}, domain.PhotoSort{})
+			if err != nil {
+				t.Fatalf("SearchPhotosInDB: %v", err)
+			}
+			var got []string
+			for _, photo := range photos {
+				got = append(got, photo.UnsplashID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("results = %v, want %v", got, tt.want)
+			}
+
+			count, err := s.CountSearchResults(ctx, tt.query, domain.PhotoSearchFilter{})
+			if err != nil || count != int64(len(tt.want)) {
+				t.Errorf("CountSearchResults = %d, %v; want %d", count, err, len(tt.want))
+			}
+		})
+	}
+}
+
+// TestSearchPhotosInDB_UsesGINIndex проверяет по плану запроса, что полнотекстовое условие
+// из searchConditions может идти через GIN-индекс idx_photos_search_vector из миграции 008
+func TestSearchPhotosInDB_UsesGINIndex(t *testing.T) {
+	s, db, userID := newTestPostgresStorage(t)
+	ctx := context.Background()
+	searchFixtures(t, s, userID)
+
+	// на нескольких строках последовательное чтение дешевле любого индекса,
+	// поэтому запрещаем его только этому соединению
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		t.Fatalf("conn: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SET enable_seqscan = off`); err != nil {
+		t.Fatalf("disable seqscan: %v", err)
+	}
+
+	where, _, args, err := searchConditions("fox", domain.PhotoSearchFilter{})
+	if err != nil {
+		t.Fatalf("searchConditions: %v", err)
+	}
+	var plan []string
+	if err := conn.SelectContext(ctx, &plan, "EXPLAIN SELECT id FROM photos WHERE "+where, args...); err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if text := strings.Join(plan, "\n"); !strings.Contains(text, "idx_photos_search_vector") {
+		t.Errorf("plan does not use idx_photos_search_vector:\n%s", text)
+	}
+}
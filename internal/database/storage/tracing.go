@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("storage")
+
+// startSpan открывает клиентский спан запроса к PostgreSQL
+func startSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", operation),
+	)
+	return tracer.Start(ctx, "Postgres."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// recordDBError отмечает ошибку на спане; sql.ErrNoRows ошибкой не считается
+func recordDBError(span trace.Span, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	tracing.RecordError(span, err)
+}
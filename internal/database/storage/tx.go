@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
+)
+
+// withTx выполняет fn в транзакции: коммитит, если fn вернула nil, иначе откатывает.
+// Медленные запросы внутри транзакции попадают в журнал так же, как запросы вне её
+func withTx(ctx context.Context, db *monitor.SlowQueryDB, fn func(tx *monitor.SlowQueryTx) error) (err error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка при открытии транзакции: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (ошибка отката транзакции: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка при фиксации транзакции: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+func TestSavePhotoTx_RollbackLeavesNoPartialRows(t *testing.T) {
+	db, logger := openTestPostgres(t)
+	ctx := context.Background()
+	if _, err := db.Exec(`TRUNCATE users, tags CASCADE`); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	slowQueryDB := monitor.NewSlowQueryDB(db, 0, logger)
+	s := NewPostgresStorage(slowQueryDB, logger)
+	userID, err := NewUserStorage(slowQueryDB, logger).GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("create system user: %v", err)
+	}
+
+	// связи photo_tags пишутся последними: к этому моменту фото и новые теги уже вставлены
+	for _, q := range []string{
+		`CREATE OR REPLACE FUNCTION fail_photo_tags() RETURNS trigger AS $$
+		BEGIN RAISE EXCEPTION 'injected failure'; END $$ LANGUAGE plpgsql`,
+		`CREATE TRIGGER fail_photo_tags BEFORE INSERT ON photo_tags FOR EACH ROW EXECUTE FUNCTION fail_photo_tags()`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("create trigger: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DROP TRIGGER IF EXISTS fail_photo_tags ON photo_tags`)
+		_, _ = db.Exec(`DROP FUNCTION IF EXISTS fail_photo_tags()`)
+	})
+
+	countRows := func() map[string]int {
+		counts := make(map[string]int)
+		for _, table := range []string{"photos", "tags", "photo_tags"} {
+			var n int
+			if err := db.Get(&n, `SELECT COUNT(*) FROM `+table); err != nil {
+				t.Fatalf("count %s: %v", table, err)
+			}
+			counts[table] = n
+		}
+		return counts
+	}
+	before := countRows()
+
+	photo := &domain.Photo{
+		UnsplashID:  "abc",
+		UserID:      userID,
+		S3URL:       "http://localhost:9000/photos/unsplash-photos/abc",
+		AuthorName:  "author",
+		Width:       640,
+		Height:      480,
+		OriginalURL: "https://images.example.com/abc",
+		Tags:        []domain.Tag{{Name: "dogs"}, {Name: "birds"}},
+	}
+	if _, err := s.SavePhotoTx(ctx, photo); err == nil {
+		t.Fatal("err = nil, want the injected failure")
+	}
+
+	if after := countRows(); !reflect.DeepEqual(after, before) {
+		t.Errorf("row counts = %v after the failed save, want unchanged %v", after, before)
+	}
+}
@@ -6,69 +6,211 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
 	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 )
 
 const systemUsername = "system_user"
 
 // GormUserStorage реализует интерфейс ports.UserStorage с использованием GORM
 type UserStorage struct {
-	db     *sqlx.DB
+	db     *monitor.SlowQueryDB
 	logger *slog.Logger
+
+	// ID системного пользователя не меняется, поэтому после первого запроса берётся из памяти
+	systemUserMu sync.Mutex
+	systemUserID uuid.UUID
 }
 
 // NewGormUserStorage создает новый экземпляр GormUserStorage
-func NewUserStorage(db *sqlx.DB, logger *slog.Logger) *UserStorage {
+func NewUserStorage(db *monitor.SlowQueryDB, logger *slog.Logger) *UserStorage {
 	return &UserStorage{db: db, logger: logger}
 }
 
 // GetOrCreateSystemUser получает или создает системного пользователя в БД.
+// Безопасен при одновременном вызове из нескольких процессов (сервер и воркер):
+// вставка не падает на конфликте, а ID затем перечитывается
 func (s *UserStorage) GetOrCreateSystemUser(ctx context.Context) (uuid.UUID, error) {
-	start := time.Now()
+	s.systemUserMu.Lock()
+	defer s.systemUserMu.Unlock()
 
-	var user domain.User
-	err := s.db.GetContext(ctx, &user, `SELECT * FROM users WHERE username = $1`, systemUsername)
+	if s.systemUserID != uuid.Nil {
+		return s.systemUserID, nil
+	}
 
-	if errors.Is(err, sql.ErrNoRows) {
-		s.logger.Warn("system user not found, creating new one", "username", systemUsername)
-
-		newUser := domain.User{
-			ID:           uuid.New(),
-			Username:     systemUsername,
-			Email:        "system@example.com",
-			PasswordHash: "dummy_hash",
-			CreatedAt:    time.Now(),
-			UpdatedAt:    time.Now(),
-		}
+	ctx, span := startSpan(ctx, "GetOrCreateSystemUser")
+	defer span.End()
 
-		_, err = s.db.NamedExecContext(ctx, `
-            INSERT INTO users (id, username, email, password_hash, created_at, updated_at)
-            VALUES (:id, :username, :email, :password_hash, :created_at, :updated_at)
-        `, &newUser)
-		if err != nil {
-			s.logger.Error("failed to insert system user", "error", err)
-			return uuid.Nil, fmt.Errorf("insert system user: %w", err)
-		}
+	start := time.Now()
 
-		s.logger.Info("system user created successfully",
+	now := time.Now()
+	newUser := domain.User{
+		ID:           uuid.New(),
+		Username:     systemUsername,
+		Email:        "system@example.com",
+		PasswordHash: "dummy_hash",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	// Без целевого столбца ON CONFLICT гасит конфликт и по username, и по email
+	res, err := s.db.NamedExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, created_at, updated_at)
+		VALUES (:id, :username, :email, :password_hash, :created_at, :updated_at)
+		ON CONFLICT DO NOTHING
+	`, &newUser)
+	if err != nil {
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to insert system user", "error", err)
+		return uuid.Nil, fmt.Errorf("insert system user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		s.log(ctx).Info("system user created successfully",
 			"user_id", newUser.ID,
 			"duration_ms", time.Since(start).Milliseconds(),
 		)
+		s.systemUserID = newUser.ID
 		return newUser.ID, nil
 	}
 
+	var id uuid.UUID
+	err = s.db.GetContext(ctx, &id, `SELECT id FROM users WHERE username = $1`, systemUsername)
+	if errors.Is(err, sql.ErrNoRows) {
+		// конфликт был не по username, а по email системного пользователя
+		s.log(ctx).Error("system user email is taken by another user", "email", newUser.Email)
+		return uuid.Nil, fmt.Errorf("select system user: email %s is already used by another user", newUser.Email)
+	}
 	if err != nil {
-		s.logger.Error("failed to select system user", "error", err)
+		recordDBError(span, err)
+		s.log(ctx).Error("failed to select system user", "error", err)
 		return uuid.Nil, fmt.Errorf("select system user: %w", err)
 	}
 
-	s.logger.Info("system user found",
+	s.log(ctx).Info("system user found",
+		"user_id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	s.systemUserID = id
+	return id, nil
+}
+
+// CreateUser сохраняет нового пользователя.
+// При нарушении уникальности username/email возвращает domain.ErrUserAlreadyExists
+func (s *UserStorage) CreateUser(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	_, err := s.db.NamedExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, created_at, updated_at)
+		VALUES (:id, :username, :email, :password_hash, :created_at, :updated_at)
+	`, user)
+	if err != nil {
+		if isUniqueViolation(err) {
+			s.log(ctx).Warn("user already exists", "username", user.Username)
+			return domain.ErrUserAlreadyExists
+		}
+		s.log(ctx).Error("failed to insert user", "username", user.Username, "error", err)
+		return fmt.Errorf("insert user: %w", err)
+	}
+
+	s.log(ctx).Info("user created successfully",
 		"user_id", user.ID,
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
-	return user.ID, nil
+	return nil
+}
+
+// GetUserByEmail получает пользователя по email.
+// Если пользователь не найден, возвращает domain.ErrUserNotFound
+func (s *UserStorage) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+	err := s.db.GetContext(ctx, &user, `SELECT * FROM users WHERE email = $1`, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		s.log(ctx).Error("failed to select user by email", "error", err)
+		return nil, fmt.Errorf("select user by email: %w", err)
+	}
+	return &user, nil
+}
+
+// GetUserByID получает пользователя по ID.
+// Если пользователь не найден, возвращает domain.ErrUserNotFound
+func (s *UserStorage) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	var user domain.User
+	err := s.db.GetContext(ctx, &user, `SELECT * FROM users WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		s.log(ctx).Error("failed to select user by id", "user_id", id, "error", err)
+		return nil, fmt.Errorf("select user by id: %w", err)
+	}
+	return &user, nil
+}
+
+// UpdateUser обновляет только заданные в updates поля пользователя
+func (s *UserStorage) UpdateUser(ctx context.Context, id uuid.UUID, updates domain.UserUpdate) error {
+	start := time.Now()
+
+	sets := []string{"updated_at = :updated_at"}
+	args := map[string]interface{}{
+		"id":         id,
+		"updated_at": time.Now(),
+	}
+	if updates.Username != nil {
+		sets = append(sets, "username = :username")
+		args["username"] = *updates.Username
+	}
+	if updates.Email != nil {
+		sets = append(sets, "email = :email")
+		args["email"] = *updates.Email
+	}
+	if updates.PasswordHash != nil {
+		sets = append(sets, "password_hash = :password_hash")
+		args["password_hash"] = *updates.PasswordHash
+	}
+
+	query := `UPDATE users SET ` + strings.Join(sets, ", ") + ` WHERE id = :id`
+	res, err := s.db.NamedExecContext(ctx, query, args)
+	if err != nil {
+		if isUniqueViolation(err) {
+			s.log(ctx).Warn("user update conflicts with existing user", "user_id", id)
+			return domain.ErrUserAlreadyExists
+		}
+		s.log(ctx).Error("failed to update user", "user_id", id, "error", err)
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	s.log(ctx).Info("user updated successfully",
+		"user_id", id,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (s *UserStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
 }
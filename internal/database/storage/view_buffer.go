@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+)
+
+// viewFlushTimeout ограничивает одну запись накопленных просмотров
+const viewFlushTimeout = 5 * time.Second
+
+// ViewCountBuffer копит просмотры фото в памяти и раз в interval записывает их одним запросом
+// через ports.PhotoStorage.AddViews, чтобы чтение фото не делало UPDATE на каждый запрос.
+// Не записанные из-за ошибки просмотры остаются в буфере до следующей попытки
+type ViewCountBuffer struct {
+	storage  ports.PhotoStorage
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]int64
+
+	stop   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+	logger *slog.Logger
+}
+
+// NewViewCountBuffer запускает фоновую запись просмотров в storage. Остановка — Close
+func NewViewCountBuffer(storage ports.PhotoStorage, interval time.Duration, logger *slog.Logger) *ViewCountBuffer {
+	b := &ViewCountBuffer{
+		storage:  storage,
+		interval: interval,
+		pending:  make(map[uuid.UUID]int64),
+		stop:     make(chan struct{}),
+		logger:   logger,
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b
+}
+
+// IncrementViews учитывает просмотр фото. Не блокируется на бд и не возвращает ошибку
+func (b *ViewCountBuffer) IncrementViews(_ context.Context, id uuid.UUID) {
+	b.mu.Lock()
+	b.pending[id]++
+	b.mu.Unlock()
+}
+
+// Close останавливает фоновую запись и записывает оставшиеся просмотры.
+// Просмотры, учтённые после Close, не записываются
+func (b *ViewCountBuffer) Close() error {
+	b.once.Do(func() { close(b.stop) })
+	b.wg.Wait()
+	return nil
+}
+
+func (b *ViewCountBuffer) run() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.flush()
+		case <-b.stop:
+			b.flush()
+			return
+		}
+	}
+}
+
+// flush забирает накопленные просмотры и записывает их; при ошибке возвращает их в буфер
+func (b *ViewCountBuffer) flush() {
+	b.mu.Lock()
+	views := b.pending
+	if len(views) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = make(map[uuid.UUID]int64, len(views))
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), viewFlushTimeout)
+	defer cancel()
+	if err := b.storage.AddViews(ctx, views); err != nil {
+		logger.FromContext(ctx, b.logger).Error("failed to flush photo views, will retry", "photos", len(views), "error", err)
+		b.mu.Lock()
+		for id, n := range views {
+			b.pending[id] += n
+		}
+		b.mu.Unlock()
+	}
+}
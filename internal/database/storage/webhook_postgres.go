@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// webhookColumns — явный список колонок webhooks
+const webhookColumns = `id, user_id, url, events, secret, active, created_at`
+
+// PostgresWebhookStorage реализует ports.WebhookStorage поверх таблиц webhooks и webhook_deliveries
+type PostgresWebhookStorage struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewPostgresWebhookStorage создает новый экземпляр PostgresWebhookStorage
+func NewPostgresWebhookStorage(db *sqlx.DB, logger *slog.Logger) *PostgresWebhookStorage {
+	return &PostgresWebhookStorage{db: db, logger: logger}
+}
+
+// CreateWebhook сохраняет новый вебхук
+func (s *PostgresWebhookStorage) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	ctx, span := startSpan(ctx, "CreateWebhook", attribute.String("user_id", webhook.UserID.String()))
+	defer span.End()
+
+	start := time.Now()
+
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	webhook.CreatedAt = time.Now()
+
+	_, err := s.db.NamedExecContext(ctx, `
+	INSERT INTO webhooks (id, user_id, url, events, secret, active, created_at)
+	VALUES (:id, :user_id, :url, :events, :secret, :active, :created_at)`, webhook)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to create webhook", "user_id", webhook.UserID, "error", err)
+		return fmt.Errorf("ошибка при создании вебхука: %w", err)
+	}
+
+	s.log(ctx).Info("webhook created successfully",
+		"id", webhook.ID,
+		"user_id", webhook.UserID,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+	return nil
+}
+
+// ListWebhooksByUserID получает вебхуки пользователя, новые первыми
+func (s *PostgresWebhookStorage) ListWebhooksByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	ctx, span := startSpan(ctx, "ListWebhooksByUserID", attribute.String("user_id", userID.String()))
+	defer span.End()
+
+	webhooks := []domain.Webhook{}
+	err := s.db.SelectContext(ctx, &webhooks, `
+	SELECT `+webhookColumns+` FROM webhooks
+	WHERE user_id = $1
+	ORDER BY created_at DESC, id`, userID)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to list webhooks", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("ошибка при получении списка вебхуков: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhookByID получает вебхук по ID
+func (s *PostgresWebhookStorage) GetWebhookByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	ctx, span := startSpan(ctx, "GetWebhookByID", attribute.String("webhook_id", id.String()))
+	defer span.End()
+
+	var webhook domain.Webhook
+	err := s.db.GetContext(ctx, &webhook, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to get webhook by id", "id", id, "error", err)
+		return nil, fmt.Errorf("ошибка при получении вебхука по ID: %w", err)
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook удаляет вебхук; журнал доставки удаляется каскадно
+func (s *PostgresWebhookStorage) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "DeleteWebhook", attribute.String("webhook_id", id.String()))
+	defer span.End()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to delete webhook", "id", id, "error", err)
+		return fmt.Errorf("ошибка при удалении вебхука: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	s.log(ctx).Info("webhook deleted successfully", "id", id)
+	return nil
+}
+
+// ListActiveWebhooksByEvent получает активные вебхуки, в events которых есть eventType
+func (s *PostgresWebhookStorage) ListActiveWebhooksByEvent(ctx context.Context, eventType string) ([]domain.Webhook, error) {
+	ctx, span := startSpan(ctx, "ListActiveWebhooksByEvent", attribute.String("event_type", eventType))
+	defer span.End()
+
+	webhooks := []domain.Webhook{}
+	err := s.db.SelectContext(ctx, &webhooks, `
+	SELECT `+webhookColumns+` FROM webhooks
+	WHERE active AND $1 = ANY(events)`, eventType)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to list webhooks for event", "event_type", eventType, "error", err)
+		return nil, fmt.Errorf("ошибка при получении вебхуков для события %s: %w", eventType, err)
+	}
+	return webhooks, nil
+}
+
+// RecordWebhookDelivery сохраняет попытку доставки события на вебхук
+func (s *PostgresWebhookStorage) RecordWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	ctx, span := startSpan(ctx, "RecordWebhookDelivery", attribute.String("webhook_id", delivery.WebhookID.String()))
+	defer span.End()
+
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	_, err := s.db.NamedExecContext(ctx, `
+	INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, attempt, status_code, error, success, duration_ms, created_at)
+	VALUES (:id, :webhook_id, :event_id, :event_type, :attempt, :status_code, :error, :success, :duration_ms, :created_at)`, delivery)
+	recordDBError(span, err)
+	if err != nil {
+		s.log(ctx).Error("failed to record webhook delivery", "webhook_id", delivery.WebhookID, "event_id", delivery.EventID, "error", err)
+		return fmt.Errorf("ошибка при сохранении попытки доставки вебхука: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresWebhookStorage) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
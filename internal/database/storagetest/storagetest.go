@@ -0,0 +1,327 @@
+// Package storagetest — общий набор тестов, который должна проходить каждая реализация
+// ports.PhotoStorage и ports.UserStorage: сохранение и чтение, конфликт по unsplash_id,
+// пакетное сохранение, поиск, подсказки, постраничный список и корзина
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Factory возвращает хранилища на пустой базе; вызывается для каждого теста набора
+type Factory func(t *testing.T) (ports.PhotoStorage, ports.UserStorage)
+
+// Run прогоняет набор на хранилищах из newStorages
+func Run(t *testing.T, newStorages Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID)
+	}{
+		{name: "SaveAndGet", fn: testSaveAndGet},
+		{name: "SaveConflict", fn: testSaveConflict},
+		{name: "SaveBatch", fn: testSaveBatch},
+		{name: "SaveBatchAllOrNothing", fn: testSaveBatchAllOrNothing},
+		{name: "Search", fn: testSearch},
+		{name: "Autocomplete", fn: testAutocomplete},
+		{name: "ListNewestFirst", fn: testListNewestFirst},
+		{name: "SoftDelete", fn: testSoftDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			photos, users := newStorages(t)
+			userID, err := users.GetOrCreateSystemUser(context.Background())
+			if err != nil {
+				t.Fatalf("GetOrCreateSystemUser: %v", err)
+			}
+			again, err := users.GetOrCreateSystemUser(context.Background())
+			if err != nil || again != userID {
+				t.Fatalf("second GetOrCreateSystemUser = %s, %v; want %s", again, err, userID)
+			}
+			tt.fn(t, photos, userID)
+		})
+	}
+}
+
+// newPhoto возвращает фото Unsplash с заполненными обязательными полями
+func newPhoto(userID uuid.UUID, unsplashID, title string, tags ...string) *domain.Photo {
+	photo := &domain.Photo{
+		UnsplashID:  unsplashID,
+		UserID:      userID,
+		S3URL:       "http://localhost:9000/photos/unsplash-photos/" + unsplashID,
+		Title:       title,
+		AuthorName:  "author",
+		Width:       640,
+		Height:      480,
+		OriginalURL: "https://images.example.com/" + unsplashID,
+	}
+	for _, name := range tags {
+		photo.Tags = append(photo.Tags, domain.Tag{Name: name})
+	}
+	return photo
+}
+
+func save(t *testing.T, photos ports.PhotoStorage, photo *domain.Photo) {
+	t.Helper()
+	if created, err := photos.SavePhotoTx(context.Background(), photo); err != nil || !created {
+		t.Fatalf("SavePhotoTx %s: created = %v, err = %v", photo.UnsplashID, created, err)
+	}
+}
+
+func testSaveAndGet(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	photo := newPhoto(userID, "abc", "mountain lake", "nature", "water")
+	save(t, photos, photo)
+	if photo.ID == uuid.Nil {
+		t.Fatal("SavePhotoTx did not assign an ID")
+	}
+
+	got, err := photos.GetPhotoByIDFromDB(ctx, photo.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetPhotoByIDFromDB: photo = %v, err = %v", got, err)
+	}
+	if got.UnsplashID != "abc" || got.UserID != userID || got.Title != photo.Title || got.S3URL != photo.S3URL {
+		t.Errorf("stored photo = %+v, want the saved one", got)
+	}
+	if got.CreatedAt.IsZero() || got.UploadedAt.IsZero() {
+		t.Errorf("timestamps not set: created_at %v, uploaded_at %v", got.CreatedAt, got.UploadedAt)
+	}
+
+	byUnsplash, err := photos.GetPhotosByUnsplashIDFromDB(ctx, "abc")
+	if err != nil || byUnsplash == nil || byUnsplash.ID != photo.ID {
+		t.Errorf("GetPhotosByUnsplashIDFromDB = %v, %v; want %s", byUnsplash, err, photo.ID)
+	}
+
+	missing, err := photos.GetPhotoByIDFromDB(ctx, uuid.New())
+	if err != nil || missing != nil {
+		t.Errorf("missing photo = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func testSaveConflict(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	winner := newPhoto(userID, "abc", "winner", "cats")
+	save(t, photos, winner)
+
+	loser := newPhoto(userID, "abc", "loser", "dogs")
+	loser.ID = uuid.New()
+	created, err := photos.SavePhotoTx(ctx, loser)
+	if err != nil {
+		t.Fatalf("save loser: %v", err)
+	}
+	if created {
+		t.Error("created = true, want false on unsplash_id conflict")
+	}
+	if loser.ID != winner.ID || loser.Title != "winner" {
+		t.Errorf("loser filled with %s %q, want stored %s %q", loser.ID, loser.Title, winner.ID, "winner")
+	}
+	if len(loser.Tags) != 1 || loser.Tags[0].Name != "cats" {
+		t.Errorf("loser tags = %v, want stored [cats]", loser.Tags)
+	}
+
+	if n, err := photos.CountPhotosInDB(ctx); err != nil || n != 1 {
+		t.Errorf("CountPhotosInDB = %d, %v; want 1", n, err)
+	}
+}
+
+func testSaveBatch(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	existing := newPhoto(userID, "existing", "stored earlier", "cats")
+	save(t, photos, existing)
+
+	fresh := newPhoto(userID, "fresh", "fresh", "dogs")
+	conflict := newPhoto(userID, "existing", "conflict", "birds")
+	twin := newPhoto(userID, "fresh", "twin in the same batch")
+	inserted, err := photos.SavePhotos(ctx, []*domain.Photo{fresh, conflict, twin})
+	if err != nil {
+		t.Fatalf("SavePhotos: %v", err)
+	}
+
+	if len(inserted) != 1 || inserted[0] != fresh.ID {
+		t.Errorf("inserted = %v, want only %s", inserted, fresh.ID)
+	}
+	if conflict.ID != existing.ID || conflict.Title != "stored earlier" {
+		t.Errorf("conflicting photo filled with %s %q, want stored %s %q", conflict.ID, conflict.Title, existing.ID, "stored earlier")
+	}
+	if twin.ID != fresh.ID || twin.Title != "fresh" {
+		t.Errorf("twin filled with %s %q, want %s %q saved earlier in the batch", twin.ID, twin.Title, fresh.ID, "fresh")
+	}
+	if n, err := photos.CountPhotosInDB(ctx); err != nil || n != 2 {
+		t.Errorf("CountPhotosInDB = %d, %v; want 2", n, err)
+	}
+
+	// теги сохраняются только у вставленных фото: у конфликтного "birds" не появляется
+	cloud, err := photos.GetTagCloud(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetTagCloud: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, tag := range cloud {
+		counts[tag.Name] = tag.Count
+	}
+	if len(counts) != 2 || counts["cats"] != 1 || counts["dogs"] != 1 {
+		t.Errorf("tag cloud = %v, want cats:1 dogs:1", counts)
+	}
+
+	if inserted, err := photos.SavePhotos(ctx, nil); err != nil || len(inserted) != 0 {
+		t.Errorf("SavePhotos(nil) = %v, %v; want nothing", inserted, err)
+	}
+}
+
+func testSaveBatchAllOrNothing(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	first := newPhoto(userID, "first", "first", "nature")
+	// владельца нет в users: внешний ключ проваливает вставку посреди пачки
+	orphan := newPhoto(uuid.New(), "orphan", "orphan")
+	last := newPhoto(userID, "last", "last")
+
+	if _, err := photos.SavePhotos(ctx, []*domain.Photo{first, orphan, last}); err == nil {
+		t.Fatal("SavePhotos with a missing owner: err = nil, want an error")
+	}
+	if n, err := photos.CountPhotosInDB(ctx); err != nil || n != 0 {
+		t.Errorf("CountPhotosInDB after a failed batch = %d, %v; want 0", n, err)
+	}
+	for _, unsplashID := range []string{"first", "last"} {
+		if got, err := photos.GetPhotosByUnsplashIDFromDB(ctx, unsplashID); err != nil || got != nil {
+			t.Errorf("%s after a failed batch = %v, %v; want nil, nil", unsplashID, got, err)
+		}
+	}
+
+	// после отката та же пачка без проблемного фото сохраняется целиком
+	inserted, err := photos.SavePhotos(ctx, []*domain.Photo{first, last})
+	if err != nil {
+		t.Fatalf("SavePhotos retry: %v", err)
+	}
+	if len(inserted) != 2 {
+		t.Errorf("inserted on retry = %v, want 2 photos", inserted)
+	}
+}
+
+func testSearch(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	fox := newPhoto(userID, "fox", "red fox in the forest")
+	save(t, photos, fox)
+	save(t, photos, newPhoto(userID, "whale", "blue whale"))
+
+	found, err := photos.SearchPhotosInDB(ctx, "fox", 1, 10, domain.PhotoSearchFilter{}, domain.PhotoSort{})
+	if err != nil {
+		t.Fatalf("SearchPhotosInDB: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != fox.ID {
+		t.Errorf("search fox = %v, want only %s", found, fox.ID)
+	}
+	if n, err := photos.CountSearchResults(ctx, "fox", domain.PhotoSearchFilter{}); err != nil || n != 1 {
+		t.Errorf("CountSearchResults = %d, %v; want 1", n, err)
+	}
+
+	none, err := photos.SearchPhotosInDB(ctx, "giraffe", 1, 10, domain.PhotoSearchFilter{}, domain.PhotoSort{})
+	if err != nil || len(none) != 0 {
+		t.Errorf("search giraffe = %v, %v; want nothing", none, err)
+	}
+}
+
+func testAutocomplete(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	lake := newPhoto(userID, "lake", "Mountain lake")
+	lake.AuthorName = "Moss Walker"
+	save(t, photos, lake)
+	save(t, photos, newPhoto(userID, "dawn", "mountains at dawn"))
+	save(t, photos, newPhoto(userID, "pct", "100% mountain"))
+	deleted := newPhoto(userID, "gone", "Mountain pass")
+	save(t, photos, deleted)
+	deletedAt := time.Now().UTC()
+	if err := photos.SetPhotoDeletedAt(ctx, deleted.ID, &deletedAt); err != nil {
+		t.Fatalf("SetPhotoDeletedAt: %v", err)
+	}
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{prefix: "mo", limit: 10, want: []string{"Moss Walker", "Mountain lake", "mountains at dawn"}},
+		{prefix: "MOUNT", limit: 10, want: []string{"Mountain lake", "mountains at dawn"}},
+		{prefix: "mo", limit: 2, want: []string{"Moss Walker", "Mountain lake"}},
+		// % в префиксе ищется буквально, а не как шаблон LIKE
+		{prefix: "100%", limit: 10, want: []string{"100% mountain"}},
+		{prefix: "1%m", limit: 10, want: []string{}},
+		{prefix: "giraffe", limit: 10, want: []string{}},
+	}
+	for _, tt := range tests {
+		got, err := photos.AutocompletePhotoTitles(ctx, tt.prefix, tt.limit)
+		if err != nil {
+			t.Fatalf("AutocompletePhotoTitles(%q): %v", tt.prefix, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("AutocompletePhotoTitles(%q, %d) = %q, want %q", tt.prefix, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func testListNewestFirst(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour).UTC()
+	var saved []*domain.Photo
+	for i, id := range []string{"old", "mid", "new"} {
+		photo := newPhoto(userID, id, id)
+		photo.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		photo.UpdatedAt = photo.CreatedAt
+		save(t, photos, photo)
+		saved = append(saved, photo)
+	}
+
+	first, err := photos.ListPhotosInDB(ctx, 1, 2, domain.PhotoSort{})
+	if err != nil {
+		t.Fatalf("ListPhotosInDB page 1: %v", err)
+	}
+	second, err := photos.ListPhotosInDB(ctx, 2, 2, domain.PhotoSort{})
+	if err != nil {
+		t.Fatalf("ListPhotosInDB page 2: %v", err)
+	}
+
+	var got []string
+	for _, p := range append(first, second...) {
+		got = append(got, p.UnsplashID)
+	}
+	if len(first) != 2 || len(second) != 1 || got[0] != "new" || got[1] != "mid" || got[2] != "old" {
+		t.Errorf("pages = %v, want [new mid] [old]", got)
+	}
+}
+
+func testSoftDelete(t *testing.T, photos ports.PhotoStorage, userID uuid.UUID) {
+	ctx := context.Background()
+	photo := newPhoto(userID, "abc", "photo")
+	save(t, photos, photo)
+
+	deletedAt := time.Now().UTC()
+	if err := photos.SetPhotoDeletedAt(ctx, photo.ID, &deletedAt); err != nil {
+		t.Fatalf("SetPhotoDeletedAt: %v", err)
+	}
+	if err := photos.SetPhotoDeletedAt(ctx, photo.ID, &deletedAt); !errors.Is(err, domain.ErrPhotoNotFound) {
+		t.Errorf("second delete: err = %v, want ErrPhotoNotFound", err)
+	}
+
+	if got, err := photos.GetPhotoByIDFromDB(ctx, photo.ID); err != nil || got != nil {
+		t.Errorf("deleted photo = %v, %v; want nil, nil", got, err)
+	}
+	trashed, err := photos.GetPhotoByIDIncludingDeleted(ctx, photo.ID)
+	if err != nil || trashed == nil || trashed.DeletedAt == nil {
+		t.Fatalf("GetPhotoByIDIncludingDeleted = %v, %v; want the photo with deleted_at", trashed, err)
+	}
+	if list, err := photos.ListPhotosInDB(ctx, 1, 10, domain.PhotoSort{}); err != nil || len(list) != 0 {
+		t.Errorf("list with a deleted photo = %v, %v; want empty", list, err)
+	}
+
+	if err := photos.SetPhotoDeletedAt(ctx, photo.ID, nil); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got, err := photos.GetPhotoByIDFromDB(ctx, photo.ID); err != nil || got == nil {
+		t.Errorf("restored photo = %v, %v; want it visible", got, err)
+	}
+}
@@ -1,92 +1,398 @@
 package di
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/adapter/cache/redis"
+	"github.com/GoArmGo/MediaApp/internal/adapter/embedding"
+	"github.com/GoArmGo/MediaApp/internal/adapter/fakefetcher"
+	"github.com/GoArmGo/MediaApp/internal/adapter/kafka"
+	"github.com/GoArmGo/MediaApp/internal/adapter/multisource"
+	"github.com/GoArmGo/MediaApp/internal/adapter/palette"
+	"github.com/GoArmGo/MediaApp/internal/adapter/pexels"
+	"github.com/GoArmGo/MediaApp/internal/adapter/pixabay"
+	rabbitmqmgmt "github.com/GoArmGo/MediaApp/internal/adapter/rabbitmq_mgmt"
 	"github.com/GoArmGo/MediaApp/internal/adapter/storage/minio"
 	"github.com/GoArmGo/MediaApp/internal/adapter/unsplash"
 	"github.com/GoArmGo/MediaApp/internal/app"
+	"github.com/GoArmGo/MediaApp/internal/auth"
 	"github.com/GoArmGo/MediaApp/internal/config"
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
 	"github.com/GoArmGo/MediaApp/internal/database/client"
+	"github.com/GoArmGo/MediaApp/internal/database/migrator"
+	"github.com/GoArmGo/MediaApp/internal/database/monitor"
+	"github.com/GoArmGo/MediaApp/internal/database/sqlite"
 	"github.com/GoArmGo/MediaApp/internal/database/storage"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/events"
+	"github.com/GoArmGo/MediaApp/internal/lock"
 	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+	"github.com/GoArmGo/MediaApp/internal/netguard"
 	"github.com/GoArmGo/MediaApp/internal/rabbitmq"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
 	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/GoArmGo/MediaApp/internal/webhook"
+	"github.com/jmoiron/sqlx"
 )
 
 // BuildApp инициализирует все зависимости и возвращает готовый объект App.
-func BuildApp() (*app.App, error) {
+// Отмена ctx прерывает долгие шаги старта (например, подготовку бакета MinIO)
+func BuildApp(ctx context.Context) (*app.App, error) {
 	// 1. Загрузка конфигурации
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	slogCfg := logger.SlogConfig{
-		Level:  cfg.LogLevel,
-		Format: cfg.LogFormat,
+	slogger, err := newLogger(cfg)
+	if err != nil {
+		return nil, err
 	}
-	slogger := logger.NewSlog(slogCfg)
-	slogger.Info("logger initialized", "level", cfg.LogLevel, "format", cfg.LogFormat)
 
-	// 2. Инициализация PostgreSQL клиента
-	slogger.Info("initializing PostgreSQL client", "db-URL", cfg.DatabaseURL)
-	dbClient, err := client.NewClient(cfg, slogger)
+	// Реестр Prometheus-метрик, общий для всех адаптеров
+	metricsRegistry := metrics.NewRegistry()
+	appMetrics := metrics.New(metricsRegistry)
+
+	// Трассировка OpenTelemetry (no-op, если OTLP endpoint не задан)
+	tracingShutdown, err := tracing.Init(context.Background(), tracing.Config{
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		ServiceName:  cfg.OTelServiceName,
+	}, slogger)
 	if err != nil {
-		slogger.Error("failed to initialize PostgreSQL client", "error", err)
+		slogger.Error("failed to initialize tracing", "error", err)
 		return nil, err
 	}
-	slogger.Info("PostgreSQL client initialized successfully")
 
-	// 3. Инициализация хранилищ
-	slogger.Info("initializing storages")
-	photoStorage := storage.NewPostgresStorage(dbClient.DB, slogger)
-	userStorage := storage.NewUserStorage(dbClient.DB, slogger)
-	slogger.Info("storages initialized successfully")
+	// 2-3. Инициализация базы данных и хранилищ
+	var (
+		db            *sqlx.DB
+		photoStorage  ports.PhotoStorage
+		userStorage   ports.UserStorage
+		auditStorage  ports.AuditStorage
+		searchHistory ports.SearchHistoryStorage
+		idempotency   ports.IdempotencyStorage
+		collections   ports.CollectionStorage
+		series        ports.SeriesStorage
+		webhooks      ports.WebhookStorage
+		schemaVersion ports.SchemaVersionReader // только у PostgreSQL: схема SQLite создаётся без миграций
+	)
+	switch cfg.StorageDriver {
+	case "sqlite":
+		slogger.Info("initializing SQLite storage", "path", cfg.SQLitePath)
+		db, err = sqlite.Open(cfg.SQLitePath, slogger)
+		if err != nil {
+			slogger.Error("failed to initialize SQLite storage", "error", err)
+			return nil, err
+		}
+		photoStorage = sqlite.NewPhotoStorage(db, slogger)
+		userStorage = sqlite.NewUserStorage(db, slogger)
+		auditStorage = sqlite.NewAuditStorage(db, slogger)
+		searchHistory = sqlite.NewSearchHistoryStorage(db, slogger)
+		idempotency = sqlite.NewIdempotencyStorage(db, slogger)
+		collections = sqlite.NewCollectionStorage(db, slogger)
+		series = sqlite.NewSeriesStorage(db, slogger)
+		webhooks = sqlite.NewWebhookStorage(db, slogger)
+	default:
+		slogger.Info("initializing PostgreSQL client", "db-URL", logger.RedactURL(cfg.DatabaseURL))
+		dbClient, err := client.ConnectWithRetry(ctx, cfg, slogger, cfg.DBConnectMaxAttempts,
+			time.Duration(cfg.DBConnectBaseDelayMs)*time.Millisecond)
+		if err != nil {
+			slogger.Error("failed to initialize PostgreSQL client", "error", err)
+			return nil, err
+		}
+		slogger.Info("PostgreSQL client initialized successfully")
+
+		if cfg.AutoMigrate {
+			if err := applyMigrations(ctx, dbClient, cfg.MigrationAutoRollback, slogger); err != nil {
+				return nil, err
+			}
+		} else {
+			slogger.Info("auto-migration disabled: run the binary with -mode=migrate")
+		}
+
+		db = dbClient.DB
+		// медленные запросы фото и пользователей пишутся в лог
+		slowQueryDB := monitor.NewSlowQueryDB(db, time.Duration(cfg.DBSlowQueryThresholdMs)*time.Millisecond, slogger)
+		photoStorage = storage.NewPostgresStorage(slowQueryDB, slogger)
+		userStorage = storage.NewUserStorage(slowQueryDB, slogger)
+		auditStorage = storage.NewPostgresAuditStorage(db, slogger)
+		searchHistory = storage.NewPostgresSearchHistoryStorage(db, slogger)
+		idempotency = storage.NewPostgresIdempotencyStorage(db, slogger)
+		collections = storage.NewPostgresCollectionStorage(db, slogger)
+		series = storage.NewPostgresSeriesStorage(slowQueryDB, slogger)
+		webhooks = storage.NewPostgresWebhookStorage(db, slogger)
+		schemaVersion = migrator.NewVersionReader(db)
+	}
+	// аудит пишется в фоне, чтобы не замедлять и не ломать запросы
+	auditStorage = storage.NewAsyncAuditStorage(auditStorage, cfg.AuditBufferSize, slogger)
+	slogger.Info("storages initialized successfully", "driver", cfg.StorageDriver)
 
 	// 4. Инициализация клиентов внешних сервисов
-	slogger.Info("initializing external clients: Unsplash, MinIO")
-	unsplashClient := unsplash.NewUnsplashAPIClient(cfg, slogger)
-	fileStorage, err := minio.NewMinioClient(cfg, slogger)
+	slogger.Info("initializing external clients", "photo_sources", cfg.PhotoSources, "mode", cfg.PhotoSourcesMode)
+	fetchers := make([]usecase.PhotoFetcher, 0, len(cfg.PhotoSources))
+	// коллекции, фото авторов и статистика есть только у Unsplash: без него они недоступны
+	var collectionFetcher usecase.CollectionFetcher
+	var authorFetcher usecase.AuthorFetcher
+	var statsFetcher usecase.StatsFetcher
+	for _, source := range cfg.PhotoSources {
+		switch source {
+		case "pixabay":
+			fetchers = append(fetchers, pixabay.NewPixabayAPIClient(cfg, slogger))
+		case "pexels":
+			fetchers = append(fetchers, pexels.NewPexelsAPIClient(cfg, slogger))
+		case "fake":
+			fakeFetcher, err := fakefetcher.NewFakePhotoFetcher(slogger)
+			if err != nil {
+				slogger.Error("failed to initialize fake photo fetcher", "error", err)
+				return nil, err
+			}
+			fetchers = append(fetchers, fakeFetcher)
+		default:
+			unsplashClient, err := unsplash.NewUnsplashAPIClient(cfg, nil, appMetrics, slogger)
+			if err != nil {
+				slogger.Error("failed to initialize Unsplash client", "error", err)
+				return nil, err
+			}
+			fetchers = append(fetchers, unsplashClient)
+			collectionFetcher = unsplashClient
+			authorFetcher = unsplashClient
+			statsFetcher = unsplashClient
+		}
+	}
+	var photoFetcher usecase.PhotoFetcher = fetchers[0]
+	if len(fetchers) > 1 {
+		breaker := multisource.Breaker{Failures: cfg.PhotoSourcesBreakerFailures, Cooldown: cfg.PhotoSourcesBreakerCooldown}
+		photoFetcher = multisource.NewMultiFetcher(fetchers, multisource.Mode(cfg.PhotoSourcesMode), breaker, slogger)
+	}
+	// Сервис эмбеддингов для поиска похожих фото (опционально)
+	var vectorEmbedder usecase.VectorEmbedder
+	if cfg.EmbeddingServiceURL != "" {
+		vectorEmbedder = embedding.NewClient(cfg.EmbeddingServiceURL, cfg.EmbeddingTimeout, slogger)
+	} else {
+		slogger.Info("photo embeddings disabled: EMBEDDING_SERVICE_URL is not set")
+	}
+	fileStorage, err := minio.NewMinioClient(ctx, cfg, appMetrics, slogger)
 	if err != nil {
 		slogger.Error("failed to initialize MinIO client", "error", err)
 		return nil, err
 	}
 
-	// 5. Инициализация RabbitMQ клиента
-	slogger.Info("initializing RabbitMQ client", "url", cfg.RabbitMQ.RabbitMQURL)
-	rabbitMQClient, err := rabbitmq.NewClient(cfg, slogger)
-	if err != nil {
-		slogger.Error("failed to initialize RabbitMQ client", "error", err)
-		return nil, err
+	// Кеш фото в Redis (опционально)
+	var photoCache ports.Cache
+	// тот же Redis служит распределённой блокировкой, чтобы экземпляры не обрабатывали одно фото дважды
+	var photoLocker lock.DistributedLocker
+	if cfg.RedisURL != "" {
+		redisClient, err := redis.NewClient(ctx, cfg.RedisURL, slogger)
+		if err != nil {
+			slogger.Error("failed to initialize Redis client", "error", err)
+			return nil, err
+		}
+		photoCache = redisClient
+		photoLocker = redisClient
+	} else {
+		slogger.Info("photo cache disabled: REDIS_URL is not set")
+	}
+
+	// Шина доменных событий: побочные эффекты сохранения и обновления фото выполняются в фоне
+	eventBus := events.NewAsyncEventBus(slogger)
+	if photoCache != nil {
+		cacheWarmer := usecase.NewPhotoCacheWarmer(photoCache, cfg.PhotoCacheTTL, slogger)
+		eventBus.Subscribe(domain.EventPhotoCreated, cacheWarmer)
+		eventBus.Subscribe(domain.EventPhotoUpdated, cacheWarmer)
+	}
+	photoAnalytics := events.NewPhotoAnalytics(appMetrics, slogger)
+	eventBus.Subscribe(domain.EventPhotoCreated, photoAnalytics)
+	eventBus.Subscribe(domain.EventPhotoUpdated, photoAnalytics)
+	// вебхуки пользователей: доставка с повторами, поэтому подписчик может работать долго;
+	// клиент подключается только к публичным адресам, даже если DNS хоста изменился после проверки
+	webhookDispatcher := webhook.NewDispatcher(webhooks, netguard.NewHTTPClient(cfg.WebhookTimeout),
+		cfg.WebhookMaxAttempts, cfg.WebhookRetryBaseDelay, slogger)
+	for _, eventType := range domain.PhotoEventTypes {
+		eventBus.Subscribe(eventType, webhookDispatcher.Handle)
 	}
-	slogger.Info("RabbitMQ client initialized successfully")
 
-	// 6. Инициализация Publisher / Consumer
-	slogger.Info("initializing publisher and consumer for photo search")
-	photoSearchPublisher := rabbitMQClient
-	photoSearchConsumer := rabbitMQClient
-	slogger.Info("publisher and consumer initialized")
+	// 5-6. Инициализация брокера сообщений и Publisher / Consumer
+	var photoSearchPublisher ports.PhotoSearchPublisher
+	var photoSearchConsumer ports.PhotoSearchConsumer
+	var embeddingPublisher ports.EmbeddingPublisher // только для RabbitMQ с настроенным сервисом эмбеддингов
+	var embeddingConsumer ports.EmbeddingConsumer
+	var queueStats ports.QueueStatsProvider // только для RabbitMQ с включённым management API
+	switch cfg.MessageBroker {
+	case "kafka":
+		slogger.Info("initializing Kafka client", "bootstrap_servers", cfg.Kafka.BootstrapServers)
+		kafkaClient, err := kafka.NewClient(ctx, cfg, appMetrics, slogger)
+		if err != nil {
+			slogger.Error("failed to initialize Kafka client", "error", err)
+			return nil, err
+		}
+		slogger.Info("Kafka client initialized successfully")
+		photoSearchPublisher = kafkaClient
+		photoSearchConsumer = kafkaClient
+		if vectorEmbedder != nil {
+			slogger.Warn("photo embeddings are not scheduled: embedding queue is supported only with RabbitMQ")
+		}
+	default:
+		slogger.Info("initializing RabbitMQ client", "url", logger.RedactURL(cfg.RabbitMQ.RabbitMQURL))
+		rabbitMQClient, err := rabbitmq.NewClient(cfg, appMetrics, slogger)
+		if err != nil {
+			slogger.Error("failed to initialize RabbitMQ client", "error", err)
+			return nil, err
+		}
+		slogger.Info("RabbitMQ client initialized successfully")
+		photoSearchPublisher = rabbitMQClient
+		photoSearchConsumer = rabbitMQClient
+		if vectorEmbedder != nil {
+			embeddingPublisher = rabbitMQClient
+			embeddingConsumer = rabbitMQClient
+		}
+
+		if cfg.RabbitMQ.RabbitMQManagementURL != "" {
+			mgmtClient, err := rabbitmqmgmt.NewRabbitMQManagementClient(cfg, slogger)
+			if err != nil {
+				slogger.Error("failed to initialize RabbitMQ management client", "error", err)
+				return nil, err
+			}
+			queueStats = mgmtClient
+		}
+	}
+	slogger.Info("publisher and consumer initialized", "broker", cfg.MessageBroker)
+	// эмбеддинг нового фото вычисляет воркер: подписчик только ставит задачу в очередь
+	if embeddingPublisher != nil {
+		eventBus.Subscribe(domain.EventPhotoCreated, usecase.NewEmbeddingScheduler(embeddingPublisher, slogger))
+	}
 
 	// 7. Инициализация бизнес-логики (usecases)
 	slogger.Info("initializing usecases")
-	photoUseCase := usecase.NewPhotoUseCase(photoStorage, userStorage, unsplashClient, fileStorage, slogger)
+	// просмотры при чтении фото копятся в памяти и пишутся в бд пачкой раз в ViewsFlushInterval
+	viewBuffer := storage.NewViewCountBuffer(photoStorage, cfg.ViewsFlushInterval, slogger)
+	photoUseCase := usecase.NewPhotoUseCase(photoStorage, userStorage, searchHistory, photoFetcher, collectionFetcher, authorFetcher, statsFetcher, fileStorage,
+		palette.NewExtractor(slogger), vectorEmbedder, viewBuffer, photoCache, cfg.PhotoCacheTTL,
+		usecase.StepTimeouts{Fetch: cfg.ExternalFetchTimeout, Download: cfg.PhotoDownloadTimeout, Upload: cfg.S3UploadTimeout},
+		photoLocker, eventBus, slogger)
+	userUseCase := usecase.NewUserUseCase(userStorage, slogger)
+	collectionUseCase := usecase.NewCollectionUseCase(collections, photoStorage, slogger)
+	seriesUseCase := usecase.NewSeriesUseCase(series, photoStorage, slogger)
+	webhookUseCase := usecase.NewWebhookUseCase(webhooks, slogger)
+	tokenManager := auth.NewTokenManager(cfg.JWTSecret, cfg.JWTTokenTTL)
+	requestValidator := validation.New()
 	slogger.Info("usecases initialized successfully")
 
 	// 8. Создание лимитера загрузок (например, ограничиваем 5 параллельных загрузок)
 	slogger.Info("creating upload limiter", "limit", 5)
 	uploadLimiter := make(chan struct{}, 5)
 
-	// 9. Сборка итогового приложения
+	// 9. Метрики пула соединений с БД. Запускаются последними, чтобы ранний выход
+	// из BuildApp не оставлял фоновый опрос; ctx живёт только до конца инициализации,
+	// поэтому опрос останавливает App.Shutdown
+	poolMonitorCtx, stopPoolMonitor := context.WithCancel(context.WithoutCancel(ctx))
+	if err := monitor.StartPoolMonitor(poolMonitorCtx, db, metricsRegistry); err != nil {
+		stopPoolMonitor()
+		slogger.Error("failed to start database pool monitor", "error", err)
+		return nil, err
+	}
+
+	// 10. Сборка итогового приложения
 	slogger.Info("building final application instance")
 	application := app.NewApp(
 		cfg,
 		slogger,
-		dbClient.DB,
+		db,
+		photoStorage,
+		auditStorage,
+		idempotency,
 		photoUseCase,
+		userUseCase,
+		collectionUseCase,
+		seriesUseCase,
+		webhookUseCase,
+		tokenManager,
+		requestValidator,
 		photoSearchPublisher,
 		photoSearchConsumer,
+		embeddingConsumer,
+		queueStats,
+		schemaVersion,
+		eventBus,
+		webhookDispatcher,
+		viewBuffer,
+		stopPoolMonitor,
 		uploadLimiter,
+		appMetrics,
+		metricsRegistry,
+		tracingShutdown,
 	)
 
 	slogger.Info("application built successfully — all dependencies initialized")
 	return application, nil
 }
+
+// RunMigrations выполняет -mode=migrate: поднимает только логгер и подключение к БД
+// и применяет действие args (up, down [N], version) к встроенным миграциям
+func RunMigrations(args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	slogger, err := newLogger(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.StorageDriver != "postgres" {
+		return fmt.Errorf("-mode=migrate поддерживается только для STORAGE_DRIVER=postgres: схема SQLite создаётся при старте")
+	}
+
+	m, err := migrator.Open(cfg.DatabaseURL, slogger)
+	if err != nil {
+		slogger.Error("failed to initialize migrator", "error", err)
+		return err
+	}
+	defer func() {
+		if err := m.Close(); err != nil {
+			slogger.Error("failed to close migrator", "error", err)
+		}
+	}()
+
+	return app.RunMigrations(m, args, cfg.MigrationAutoRollback, slogger)
+}
+
+// applyMigrations применяет новые миграции при старте (AUTO_MIGRATE=true);
+// при autoRollback неудачная миграция возвращает схему к версии до запуска
+func applyMigrations(ctx context.Context, dbClient *client.Client, autoRollback bool, slogger *slog.Logger) error {
+	slogger.Info("applying database migrations")
+	m, err := migrator.New(ctx, dbClient.DB, slogger)
+	if err != nil {
+		slogger.Error("failed to initialize migrator", "error", err)
+		return err
+	}
+	defer func() {
+		if err := m.Close(); err != nil {
+			slogger.Error("failed to close migrator", "error", err)
+		}
+	}()
+	return m.Up(autoRollback)
+}
+
+// newLogger создает основной логгер приложения по конфигурации
+func newLogger(cfg *config.Config) (*slog.Logger, error) {
+	slogger, err := logger.NewSlog(logger.SlogConfig{
+		Level:      cfg.LogLevel,
+		Format:     cfg.LogFormat,
+		Output:     cfg.LogOutput,
+		FilePath:   cfg.LogFilePath,
+		MaxSizeMB:  cfg.LogFileMaxSizeMB,
+		MaxBackups: cfg.LogFileMaxBackups,
+		MaxAgeDays: cfg.LogFileMaxAgeDays,
+		Compress:   cfg.LogFileCompress,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка настройки логгера: %w", err)
+	}
+	slogger.Info("logger initialized", "level", cfg.LogLevel, "format", cfg.LogFormat, "output", cfg.LogOutput)
+	return slogger, nil
+}
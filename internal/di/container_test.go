@@ -0,0 +1,95 @@
+package di
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newFakeS3 отвечает 200 на любой запрос: для BuildApp бакет уже существует
+func newFakeS3(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+// newFakeKafka принимает TCP-соединения: клиент Kafka при создании только проверяет доступность брокера
+func newFakeKafka(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestBuildApp_LoggerWorks(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	logPath := filepath.Join(dir, "app.log")
+	for key, value := range map[string]string{
+		"STORAGE_DRIVER":              "sqlite",
+		"SQLITE_PATH":                 filepath.Join(dir, "media.db"),
+		"PHOTO_SOURCE":                "fake",
+		"PHOTO_PROVIDER":              "",
+		"PHOTO_SOURCES":               "fake",
+		"MINIO_ENDPOINT":              newFakeS3(t),
+		"MINIO_ACCESS_KEY_ID":         "minio",
+		"MINIO_SECRET_ACCESS_KEY":     "minio-secret",
+		"MINIO_BUCKET_NAME":           "photos",
+		"MINIO_REGION":                "us-east-1",
+		"MINIO_USE_SSL":               "false",
+		"JWT_SECRET":                  "jwt-secret",
+		"MESSAGE_BROKER":              "kafka",
+		"KAFKA_BOOTSTRAP_SERVERS":     newFakeKafka(t),
+		"REDIS_URL":                   "",
+		"EMBEDDING_SERVICE_URL":       "",
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "",
+		"LOG_LEVEL":                   "debug",
+		"LOG_FORMAT":                  "json",
+		"LOG_OUTPUT":                  "file",
+		"LOG_FILE_PATH":               logPath,
+	} {
+		t.Setenv(key, value)
+	}
+
+	application, err := BuildApp(context.Background())
+	if err != nil {
+		t.Fatalf("BuildApp: %v", err)
+	}
+	if application.Logger == nil || application.LoggerIns() == nil {
+		t.Fatal("application logger is nil")
+	}
+	application.Logger.Info("test record from the built app")
+	if err := application.Shutdown(); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+
+	out, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	for _, want := range []string{"application built successfully", "test record from the built app", "closing database connection"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("log has no %q record", want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog — запись журнала аудита об изменяющем запросе,
+// соответствует таблице audit_logs в бд
+type AuditLog struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	UserID     uuid.UUID       `json:"user_id" db:"user_id"` // uuid.Nil — анонимный запрос
+	Action     string          `json:"action" db:"action"`   // метод и маршрут, например "DELETE /photos/{id}"
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id" db:"entity_id"` // uuid.Nil, если сущность не определить
+	OldValue   json.RawMessage `json:"old_value,omitempty" db:"old_value"`
+	NewValue   json.RawMessage `json:"new_value,omitempty" db:"new_value"`
+	IPAddress  string          `json:"ip_address" db:"ip_address"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collection — именованная подборка фото пользователя,
+// соответствует таблице collections в бд. Фото подборки хранятся в collection_photos
+type Collection struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CollectionUpdate описывает частичное обновление подборки:
+// nil-поля не изменяются
+type CollectionUpdate struct {
+	Name        *string
+	Description *string
+}
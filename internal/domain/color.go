@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ColorPalette — доминирующие цвета фото в виде "#rrggbb", самый частый первым.
+// В Postgres хранится в колонке TEXT[], в SQLite — текстом в том же формате ("{#aabbcc,#ddeeff}")
+type ColorPalette []string
+
+// Value записывает палитру литералом массива Postgres
+func (p ColorPalette) Value() (driver.Value, error) {
+	return "{" + strings.Join(p, ",") + "}", nil
+}
+
+// Scan читает палитру из литерала массива Postgres
+func (p *ColorPalette) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("палитра: неподдерживаемый тип %T", src)
+	}
+
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	if s == "" {
+		*p = ColorPalette{}
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	for i, part := range parts {
+		parts[i] = strings.Trim(part, `"`)
+	}
+	*p = parts
+	return nil
+}
+
+// NormalizeHexColor приводит цвет вида "FF5733" или "#ff5733" к "#ff5733"
+func NormalizeHexColor(s string) (string, error) {
+	hex := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "#"))
+	if len(hex) != 6 {
+		return "", fmt.Errorf("некорректный цвет %q: ожидается RRGGBB", s)
+	}
+	if _, err := strconv.ParseUint(hex, 16, 32); err != nil {
+		return "", fmt.Errorf("некорректный цвет %q: ожидается RRGGBB", s)
+	}
+	return "#" + hex, nil
+}
+
+// HexColorRGB разбирает цвет "#rrggbb" (решётка необязательна) на компоненты
+func HexColorRGB(s string) (r, g, b uint8, err error) {
+	hex, err := NormalizeHexColor(s)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	v, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), nil
+}
+
+// PaletteDistance возвращает евклидово расстояние в RGB от target до ближайшего цвета палитры.
+// ok == false, если в палитре нет ни одного корректного цвета
+func PaletteDistance(palette ColorPalette, target string) (distance float64, ok bool) {
+	tr, tg, tb, err := HexColorRGB(target)
+	if err != nil {
+		return 0, false
+	}
+	distance = math.Inf(1)
+	for _, c := range palette {
+		r, g, b, err := HexColorRGB(c)
+		if err != nil {
+			continue
+		}
+		dr, dg, db := float64(r)-float64(tr), float64(g)-float64(tg), float64(b)-float64(tb)
+		distance = min(distance, math.Sqrt(dr*dr+dg*dg+db*db))
+		ok = true
+	}
+	return distance, ok
+}
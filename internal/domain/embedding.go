@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EmbeddingDimensions — размерность эмбеддинга фото, как у колонки photos.embedding vector(512)
+const EmbeddingDimensions = 512
+
+// Vector — эмбеддинг фото. Хранится текстовым литералом pgvector "[0.1,0.2,...]":
+// в Postgres в колонке vector, в SQLite — в TEXT
+type Vector []float32
+
+// Value записывает вектор литералом pgvector; пустой вектор записывается как NULL
+func (v Vector) Value() (driver.Value, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String(), nil
+}
+
+// Scan читает вектор из литерала pgvector; NULL даёт nil
+func (v *Vector) Scan(src any) error {
+	var s string
+	switch val := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		s = string(val)
+	case string:
+		s = val
+	default:
+		return fmt.Errorf("вектор: неподдерживаемый тип %T", src)
+	}
+
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "["), "]")
+	if s == "" {
+		*v = Vector{}
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	vec := make(Vector, len(parts))
+	for i, part := range parts {
+		x, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return fmt.Errorf("вектор: некорректный элемент %q: %w", part, err)
+		}
+		vec[i] = float32(x)
+	}
+	*v = vec
+	return nil
+}
+
+// L2Distance возвращает евклидово расстояние между векторами, как оператор <-> в pgvector.
+// ok == false, если размерности не совпадают
+func L2Distance(a, b []float32) (distance float64, ok bool) {
+	if len(a) != len(b) {
+		return 0, false
+	}
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum), true
+}
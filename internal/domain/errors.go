@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrPhotoNotFound возвращается хранилищем, если фото не найдено (или уже/ещё не удалено для операций корзины)
+	ErrPhotoNotFound = errors.New("фото не найдено")
+
+	// ErrExternalPhotoNotFound возвращается клиентом внешнего API, если в источнике нет фото с таким ID.
+	// Оборачивает ErrPhotoNotFound; отличить от фото в корзине можно проверкой этой ошибки первой
+	ErrExternalPhotoNotFound = fmt.Errorf("фото не найдено во внешнем источнике: %w", ErrPhotoNotFound)
+
+	// ErrExternalCollectionNotFound возвращается, если коллекции нет во внешнем источнике (Unsplash)
+	ErrExternalCollectionNotFound = errors.New("коллекция не найдена во внешнем источнике")
+
+	// ErrExternalAuthorNotFound возвращается, если автора (пользователя) нет во внешнем источнике (Unsplash)
+	ErrExternalAuthorNotFound = errors.New("автор не найден во внешнем источнике")
+
+	// ErrUserNotFound возвращается хранилищем, если пользователь не найден
+	ErrUserNotFound = errors.New("пользователь не найден")
+
+	// ErrUserAlreadyExists возвращается при нарушении уникальности username или email
+	ErrUserAlreadyExists = errors.New("пользователь с таким username или email уже существует")
+
+	// ErrCollectionNotFound возвращается, если подборка не найдена или принадлежит другому пользователю
+	ErrCollectionNotFound = errors.New("подборка не найдена")
+
+	// ErrSeriesNotFound возвращается, если серия фото не найдена или принадлежит другому пользователю
+	ErrSeriesNotFound = errors.New("серия не найдена")
+
+	// ErrInvalidSeriesOrder возвращается при изменении порядка серии, если новый порядок
+	// не содержит каждое фото серии ровно один раз
+	ErrInvalidSeriesOrder = errors.New("порядок должен содержать каждое фото серии ровно один раз")
+
+	// ErrUploadIntentNotFound возвращается, если ожидающей загрузки нет: она не создавалась,
+	// уже подтверждена, удалена очисткой или принадлежит другому пользователю
+	ErrUploadIntentNotFound = errors.New("ожидающая загрузка не найдена")
+
+	// ErrWebhookNotFound возвращается, если вебхук не найден или принадлежит другому пользователю
+	ErrWebhookNotFound = errors.New("вебхук не найден")
+
+	// ErrSourceUnavailable возвращается клиентом внешнего API при сетевой ошибке или ответе 5xx:
+	// запрос стоит повторить позже или в другом источнике
+	ErrSourceUnavailable = errors.New("внешний источник фото недоступен")
+
+	// ErrQueueNotFound возвращается клиентом API брокера, если очереди с таким именем нет
+	ErrQueueNotFound = errors.New("очередь не найдена")
+
+	// ErrEmbeddingNotReady возвращается, если эмбеддинг фото ещё не вычислен и похожие фото искать не по чему
+	ErrEmbeddingNotReady = errors.New("эмбеддинг фото ещё не вычислен")
+
+	// ErrInvalidSort возвращается при сортировке по полю не из белого списка
+	ErrInvalidSort = errors.New("некорректная сортировка")
+
+	// ErrInvalidFilter возвращается при фильтре поиска с неизвестной ориентацией или пустым периодом
+	ErrInvalidFilter = errors.New("некорректный фильтр поиска")
+)
+
+// ErrRateLimited возвращается клиентом внешнего API, когда лимит запросов исчерпан.
+// ResetAt — момент, после которого запросы снова имеют смысл; до него повторять бесполезно
+type ErrRateLimited struct {
+	Source  string
+	ResetAt time.Time
+}
+
+func (e *ErrRateLimited) Error() string {
+	return fmt.Sprintf("лимит запросов к %s исчерпан до %s", e.Source, e.ResetAt.Format(time.RFC3339))
+}
+
+// RetryAfter возвращает, сколько осталось ждать до ResetAt (не меньше нуля)
+func (e *ErrRateLimited) RetryAfter() time.Duration {
+	return max(time.Until(e.ResetAt), 0)
+}
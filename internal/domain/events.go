@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий (ключи подписки в шине событий)
+const (
+	EventPhotoCreated = "photo.created"
+	EventPhotoUpdated = "photo.updated"
+	EventPhotoDeleted = "photo.deleted"
+)
+
+// PhotoEventTypes — типы событий фото, на которые можно подписать вебхук
+var PhotoEventTypes = []string{EventPhotoCreated, EventPhotoUpdated, EventPhotoDeleted}
+
+// EventMeta — общие метаданные доменного события
+type EventMeta struct {
+	ID         uuid.UUID `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewEventMeta создаёт метаданные события с новым ID и текущим временем
+func NewEventMeta() EventMeta {
+	return EventMeta{ID: uuid.New(), OccurredAt: time.Now()}
+}
+
+// Meta возвращает метаданные события; через встраивание EventMeta доступен у всех событий
+func (m EventMeta) Meta() EventMeta {
+	return m
+}
+
+// PhotoCreatedEvent публикуется после того, как новое фото сохранено в бд
+type PhotoCreatedEvent struct {
+	EventMeta
+	Photo Photo `json:"photo"`
+}
+
+// EventType возвращает EventPhotoCreated
+func (PhotoCreatedEvent) EventType() string {
+	return EventPhotoCreated
+}
+
+// PhotoUpdatedEvent публикуется после обновления метаданных уже сохранённого фото
+type PhotoUpdatedEvent struct {
+	EventMeta
+	Photo Photo `json:"photo"`
+}
+
+// EventType возвращает EventPhotoUpdated
+func (PhotoUpdatedEvent) EventType() string {
+	return EventPhotoUpdated
+}
+
+// PhotoDeletedEvent публикуется после перемещения фото в корзину.
+// Photo — состояние фото до удаления
+type PhotoDeletedEvent struct {
+	EventMeta
+	Photo Photo `json:"photo"`
+}
+
+// EventType возвращает EventPhotoDeleted
+func (PhotoDeletedEvent) EventType() string {
+	return EventPhotoDeleted
+}
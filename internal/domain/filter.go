@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Ориентации фото для PhotoSearchFilter.Orientation
+const (
+	OrientationLandscape = "landscape" // ширина больше высоты
+	OrientationPortrait  = "portrait"  // высота больше ширины
+	OrientationSquarish  = "squarish"  // ширина равна высоте
+)
+
+// PhotoSearchFilter — необязательные фильтры поиска по бд.
+// Нулевое (или отрицательное) поле — без ограничения, нулевой фильтр не меняет выдачу.
+// Author сравнивается с именем автора целиком, без учёта регистра.
+// From и To ограничивают дату загрузки (uploaded_at): From включительно, To — не включая
+type PhotoSearchFilter struct {
+	MinLikes    int
+	MinWidth    int
+	MinHeight   int
+	Author      string
+	Orientation string
+	From        *time.Time
+	To          *time.Time
+}
+
+// Validate проверяет ориентацию и период. Ошибка оборачивает ErrInvalidFilter
+func (f PhotoSearchFilter) Validate() error {
+	switch f.Orientation {
+	case "", OrientationLandscape, OrientationPortrait, OrientationSquarish:
+	default:
+		return fmt.Errorf("%w: неизвестная ориентация %q", ErrInvalidFilter, f.Orientation)
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return fmt.Errorf("%w: начало периода должно быть раньше конца", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// PhotoFilter — выборка фото для выгрузки. Пустой Query — все фото, иначе те же правила,
+// что у поиска по бд. From и To ограничивают дату загрузки (uploaded_at):
+// From включительно, To — не включая; nil — без ограничения
+type PhotoFilter struct {
+	Query string
+	From  *time.Time
+	To    *time.Time
+}
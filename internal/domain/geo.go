@@ -0,0 +1,17 @@
+package domain
+
+import "math"
+
+// earthRadiusKm — средний радиус Земли
+const earthRadiusKm = 6371.0088
+
+// DistanceKm возвращает расстояние по поверхности Земли между двумя точками (формула гаверсинуса).
+// Точность — доли процента, как у сферической модели; для поиска «рядом» этого достаточно
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(min(a, 1)))
+}
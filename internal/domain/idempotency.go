@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyRecord — сохранённый ответ на запрос с заголовком X-Idempotency-Key,
+// соответствует таблице idempotency_cache в бд
+type IdempotencyRecord struct {
+	Key        string          `json:"key" db:"key"`
+	StatusCode int             `json:"status_code" db:"status_code"` // 0 — запрос с этим ключом ещё выполняется
+	Response   json.RawMessage `json:"response,omitempty" db:"response"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// InProgress сообщает, что ответ на запрос с этим ключом ещё не сохранён
+func (r *IdempotencyRecord) InProgress() bool {
+	return r.StatusCode == 0
+}
@@ -0,0 +1,7 @@
+package domain
+
+// PhotoLikes — лайки фото в избранном пользователей
+type PhotoLikes struct {
+	Count     int64 `json:"count" db:"count"`
+	LikedByMe bool  `json:"liked_by_me" db:"liked"` // false для анонимного запроса
+}
@@ -1,53 +1,166 @@
 package domain
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Источники фото (ExternalSource)
+const (
+	SourceUnsplash = "unsplash"
+	SourcePixabay  = "pixabay"
+	SourcePexels   = "pexels"
+	SourceUser     = "user" // загружено пользователем напрямую
+	SourceFake     = "fake" // встроенный набор фото для разработки без сети
+)
+
+// Статусы фото (Status). Фото загрузки напрямую в хранилище создаётся в статусе pending
+// и становится active после подтверждения; pending-фото хранилище не возвращает ни в каких выборках
+const (
+	PhotoStatusPending = "pending"
+	PhotoStatusActive  = "active"
+)
+
+// PexelsIDPrefix и PixabayIDPrefix — префиксы, с которыми числовые ID Pexels и Pixabay хранятся
+// в Photo.UnsplashID (например "pexels-2014422"): числовые ID источников пересекаются, а колонка unsplash_id уникальна
+const (
+	PexelsIDPrefix  = "pexels-"
+	PixabayIDPrefix = "pixabay-"
+)
+
+// FakeIDPrefix — префикс ID фото из встроенного набора (SourceFake), чтобы они не совпали с ID Unsplash
+const FakeIDPrefix = "fake-"
+
 // Photo представляет модель фотографии в системе,
 // соответствует таблице photos в бд
 type Photo struct {
-	ID             uuid.UUID `json:"id"`
-	UnsplashID     string    `json:"unsplash_id"`
-	UserID         uuid.UUID `json:"user_id"`
-	S3URL          string    `json:"s3_url"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description"`
-	AuthorName     string    `json:"author_name"`
-	Width          int       `json:"width"`
-	Height         int       `json:"height"`
-	LikesCount     int       `json:"likes_count"`
-	OriginalURL    string    `json:"original_url"`
-	UploadedAt     time.Time `json:"uploaded_at"`
-	ViewsCount     int64     `json:"views_count"`
-	DownloadsCount int64     `json:"downloads_count"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	Tags           []Tag     `json:"tags,omitempty"`
+	ID                uuid.UUID    `json:"id" db:"id"`
+	UnsplashID        string       `json:"unsplash_id" db:"unsplash_id"` // ID во внешнем источнике (ExternalSource); пусто у фото, загруженных пользователем
+	ExternalSource    string       `json:"external_source" db:"external_source"`
+	ExternalID        string       `json:"external_id" db:"external_id"` // ID в ExternalSource как есть (у Pexels и Pixabay — без префикса); в бд вычисляется из unsplash_id
+	UserID            uuid.UUID    `json:"user_id" db:"user_id"`
+	S3URL             string       `json:"s3_url" db:"s3_url"`
+	Title             string       `json:"title" db:"title"`
+	Description       string       `json:"description" db:"description"`
+	AuthorName        string       `json:"author_name" db:"author_name"`
+	Width             int          `json:"width" db:"width"`
+	Height            int          `json:"height" db:"height"`
+	LikesCount        int          `json:"likes_count" db:"likes_count"`
+	OriginalURL       string       `json:"original_url" db:"original_url"`
+	UploadedAt        time.Time    `json:"uploaded_at" db:"uploaded_at"`
+	ViewsCount        int64        `json:"views_count" db:"views_count"`
+	DownloadsCount    int64        `json:"downloads_count" db:"downloads_count"`
+	UnsplashViews     int64        `json:"unsplash_views" db:"unsplash_views"` // статистика источника; views_count и downloads_count считает само приложение
+	UnsplashDownloads int64        `json:"unsplash_downloads" db:"unsplash_downloads"`
+	CreatedAt         time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at" db:"updated_at"`
+	DeletedAt         *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"`
+	Status            string       `json:"status" db:"status"`         // PhotoStatusActive или PhotoStatusPending
+	SizeBytes         int64        `json:"size_bytes" db:"size_bytes"` // размер файла в S3; 0 у фото, сохранённых до миграции 009
+	MimeType          string       `json:"mime_type" db:"mime_type"`
+	DominantColors    ColorPalette `json:"dominant_colors" db:"dominant_colors"`   // пусто, если палитру не удалось извлечь
+	PopularityScore   float64      `json:"popularity_score" db:"popularity_score"` // пересчитывается фоновой задачей, см. PopularityScore
+	Latitude          *float64     `json:"latitude,omitempty" db:"latitude"`       // координаты съёмки; nil, если источник их не знает
+	Longitude         *float64     `json:"longitude,omitempty" db:"longitude"`
+	BlurHash          string       `json:"blur_hash,omitempty" db:"blur_hash"`           // BlurHash для размытой заглушки до загрузки фото
+	DominantColor     string       `json:"dominant_color,omitempty" db:"dominant_color"` // средний цвет "#rrggbb" по данным источника
+	EmbeddingVector   []float32    `json:"embedding,omitempty" db:"-"`                   // эмбеддинг для поиска похожих; из бд читается только по запросу
+	Tags              []Tag        `json:"tags,omitempty" db:"-"`
+
+	// Rank — релевантность в результатах поиска по бд; nil вне поиска и если ранг не запрошен
+	Rank *float64 `json:"rank,omitempty" db:"rank"`
+
+	// LocalLikesCount — сколько пользователей добавили фото в избранное (в отличие от LikesCount из источника),
+	// LikedByMe — есть ли среди них текущий пользователь. Заполняются только в карточке фото
+	LocalLikesCount *int64 `json:"local_likes_count,omitempty" db:"-"`
+	LikedByMe       *bool  `json:"liked_by_me,omitempty" db:"-"`
 }
 
 func (Photo) TableName() string {
 	return "photos"
 }
 
+// MarshalJSON добавляет к фото вычисляемое поле attribution (в бд не хранится)
+func (p Photo) MarshalJSON() ([]byte, error) {
+	type photoAlias Photo // без методов, чтобы не уйти в рекурсию
+	return json.Marshal(struct {
+		photoAlias
+		Attribution string `json:"attribution"`
+	}{
+		photoAlias:  photoAlias(p),
+		Attribution: GenerateAttribution(&p),
+	})
+}
+
+// GenerateAttribution формирует подпись к фото по правилам источника, например:
+// "Photo by Jane Doe on Unsplash (https://unsplash.com/photos/abc123)".
+// Пустые AuthorName или UnsplashID опускаются, а не дают "Photo by  on Unsplash".
+// Для фото, загруженных пользователем, упоминания источника нет: "Photo by username"
+func GenerateAttribution(photo *Photo) string {
+	if photo == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Photo")
+	if author := strings.TrimSpace(photo.AuthorName); author != "" {
+		b.WriteString(" by ")
+		b.WriteString(author)
+	}
+
+	var site, pageURL string
+	switch photo.ExternalSource {
+	case SourceUser, SourceFake:
+		return b.String()
+	case SourcePixabay:
+		site, pageURL = "Pixabay", "https://pixabay.com/photos/id-%s/"
+	case SourcePexels:
+		site, pageURL = "Pexels", "https://www.pexels.com/photo/%s/"
+	default:
+		site, pageURL = "Unsplash", "https://unsplash.com/photos/%s"
+	}
+	b.WriteString(" on ")
+	b.WriteString(site)
+	if externalID := strings.TrimSpace(photo.UnsplashID); externalID != "" {
+		switch photo.ExternalSource {
+		case SourcePexels:
+			externalID = strings.TrimPrefix(externalID, PexelsIDPrefix)
+		case SourcePixabay:
+			externalID = strings.TrimPrefix(externalID, PixabayIDPrefix)
+		}
+		b.WriteString(" (")
+		b.WriteString(fmt.Sprintf(pageURL, externalID))
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
 // Tag представляет модель тега,
 // соответствует таблице tags в бд
 type Tag struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	ID   uuid.UUID `json:"id" db:"id"`
+	Name string    `json:"name" db:"name"`
 }
 
 func (Tag) TableName() string {
 	return "tags"
 }
 
+// TagCount — тег и число фото с ним (вне корзины), элемент облака тегов
+type TagCount struct {
+	Tag
+	Count int `json:"count" db:"count"`
+}
+
 // PhotoTag представляет связующую модель для отношения Many-to-Many между Photo и Tag,
 // соответствует таблице photo_tags в бд
 type PhotoTag struct {
-	PhotoID uuid.UUID `json:"photo_id"`
-	TagID   uuid.UUID `json:"tag_id"`
+	PhotoID uuid.UUID `json:"photo_id" db:"photo_id"`
+	TagID   uuid.UUID `json:"tag_id" db:"tag_id"`
 }
 
 func (PhotoTag) TableName() string {
@@ -0,0 +1,19 @@
+package domain
+
+// PhotoPage — страница фото из внешнего источника и сколько всего результатов у источника.
+// Нулевые Total и TotalPages означают, что источник их не сообщил. Sources — источники,
+// чьи фото попали в страницу; пусто, если страницу собрал один источник и он это не указал
+type PhotoPage struct {
+	Photos     []Photo
+	Total      int
+	TotalPages int
+	Sources    []string
+}
+
+// TotalPagesFor считает число страниц по perPage элементов для total элементов
+func TotalPagesFor(total, perPage int) int {
+	if total <= 0 || perPage <= 0 {
+		return 0
+	}
+	return (total + perPage - 1) / perPage
+}
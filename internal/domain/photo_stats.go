@@ -0,0 +1,7 @@
+package domain
+
+// PhotoStats — просмотры и скачивания фото за всё время по данным внешнего источника
+type PhotoStats struct {
+	Views     int64
+	Downloads int64
+}
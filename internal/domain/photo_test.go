@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGenerateAttribution(t *testing.T) {
+	tests := []struct {
+		name  string
+		photo *Photo
+		want  string
+	}{
+		{name: "nil photo", photo: nil, want: ""},
+		{name: "author and link", photo: &Photo{AuthorName: "Jane Doe", UnsplashID: "abc123"},
+			want: "Photo by Jane Doe on Unsplash (https://unsplash.com/photos/abc123)"},
+		{name: "empty author", photo: &Photo{UnsplashID: "abc123"},
+			want: "Photo on Unsplash (https://unsplash.com/photos/abc123)"},
+		{name: "blank author", photo: &Photo{AuthorName: "  ", UnsplashID: "abc123"},
+			want: "Photo on Unsplash (https://unsplash.com/photos/abc123)"},
+		{name: "empty link", photo: &Photo{AuthorName: "Jane Doe"},
+			want: "Photo by Jane Doe on Unsplash"},
+		{name: "blank link", photo: &Photo{AuthorName: "Jane Doe", UnsplashID: " "},
+			want: "Photo by Jane Doe on Unsplash"},
+		{name: "empty author and link", photo: &Photo{}, want: "Photo on Unsplash"},
+		{name: "pexels without the ID prefix", photo: &Photo{AuthorName: "Ann", UnsplashID: PexelsIDPrefix + "2014422", ExternalSource: SourcePexels},
+			want: "Photo by Ann on Pexels (https://www.pexels.com/photo/2014422/)"},
+		{name: "pixabay without the ID prefix", photo: &Photo{AuthorName: "Ann", UnsplashID: PixabayIDPrefix + "736877", ExternalSource: SourcePixabay},
+			want: "Photo by Ann on Pixabay (https://pixabay.com/photos/id-736877/)"},
+		{name: "user upload has no source", photo: &Photo{AuthorName: "Ann", ExternalSource: SourceUser},
+			want: "Photo by Ann"},
+		{name: "user upload without author", photo: &Photo{ExternalSource: SourceUser}, want: "Photo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerateAttribution(tt.photo); got != tt.want {
+				t.Errorf("GenerateAttribution = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhotoMarshalJSON_Attribution(t *testing.T) {
+	data, err := json.Marshal(Photo{UnsplashID: "abc123"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got struct {
+		UnsplashID  string `json:"unsplash_id"`
+		Attribution string `json:"attribution"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.UnsplashID != "abc123" || got.Attribution != "Photo on Unsplash (https://unsplash.com/photos/abc123)" {
+		t.Errorf("json = %s, want the photo fields and a computed attribution", data)
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "math"
+
+// Веса формулы популярности фото. Счётчики берутся по логарифму, чтобы тысяча просмотров
+// не перевешивала всё остальное, а возраст линейно снижает оценку, уступая место новым фото
+const (
+	PopularityViewsWeight     = 0.3
+	PopularityLikesWeight     = 0.5
+	PopularityDownloadsWeight = 0.2
+	PopularityAgeWeight       = 0.1 // штраф за каждые сутки с момента загрузки
+)
+
+// PopularityScore считает оценку популярности фото:
+// ln(1+views)*0.3 + ln(1+likes)*0.5 + ln(1+downloads)*0.2 - ageDays*0.1.
+// Та же формула объявлена функцией popularity_score в миграции 017 и в SQLite.
+// Отрицательные счётчики и возраст считаются нулём
+func PopularityScore(views, likes, downloads int64, ageDays float64) float64 {
+	return math.Log1p(float64(max(views, 0)))*PopularityViewsWeight +
+		math.Log1p(float64(max(likes, 0)))*PopularityLikesWeight +
+		math.Log1p(float64(max(downloads, 0)))*PopularityDownloadsWeight -
+		max(ageDays, 0)*PopularityAgeWeight
+}
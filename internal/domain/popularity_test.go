@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPopularityScore(t *testing.T) {
+	ln := math.Log
+	tests := []struct {
+		name                    string
+		views, likes, downloads int64
+		ageDays                 float64
+		want                    float64
+	}{
+		{name: "zero counts, brand new", want: 0},
+		{name: "zero counts, one day old", ageDays: 1, want: -0.1},
+		{name: "views only", views: 99, want: ln(100) * 0.3},
+		{name: "likes only", likes: 99, want: ln(100) * 0.5},
+		{name: "downloads only", downloads: 99, want: ln(100) * 0.2},
+		{name: "all counters", views: 999, likes: 9, downloads: 4, ageDays: 2.5,
+			want: ln(1000)*0.3 + ln(10)*0.5 + ln(5)*0.2 - 0.25},
+		{name: "very old photo", views: 1_000_000, likes: 50_000, downloads: 10_000, ageDays: 3650,
+			want: ln(1_000_001)*0.3 + ln(50_001)*0.5 + ln(10_001)*0.2 - 365},
+		{name: "negative counters count as zero", views: -5, likes: -1, downloads: -100, want: 0},
+		{name: "negative age counts as zero", views: 99, ageDays: -3, want: ln(100) * 0.3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PopularityScore(tt.views, tt.likes, tt.downloads, tt.ageDays)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("PopularityScore(%d, %d, %d, %v) = %v, want %v", tt.views, tt.likes, tt.downloads, tt.ageDays, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopularityScore_AgeOutweighsCounters(t *testing.T) {
+	fresh := PopularityScore(10, 1, 0, 0)
+	old := PopularityScore(1_000_000, 100_000, 10_000, 365)
+	if old >= fresh {
+		t.Errorf("year-old viral photo = %v, fresh photo = %v; want the fresh one ranked higher", old, fresh)
+	}
+	if huge := PopularityScore(math.MaxInt64, math.MaxInt64, math.MaxInt64, 0); math.IsInf(huge, 0) || math.IsNaN(huge) {
+		t.Errorf("score for max counters = %v, want a finite number", huge)
+	}
+}
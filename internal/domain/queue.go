@@ -0,0 +1,16 @@
+package domain
+
+// QueueStats — состояние очереди брокера сообщений для мониторинга
+type QueueStats struct {
+	Name  string `json:"name"`
+	VHost string `json:"vhost"`
+	// Messages — глубина очереди: готовые к выдаче и выданные, но не подтверждённые сообщения
+	Messages               int64 `json:"messages"`
+	MessagesReady          int64 `json:"messages_ready"` // отставание потребителей: ещё никому не выданы
+	MessagesUnacknowledged int64 `json:"messages_unacknowledged"`
+	Consumers              int   `json:"consumers"`
+	// Скорости в сообщениях в секунду, усреднённые брокером за последние секунды
+	PublishRate float64 `json:"publish_rate"`
+	DeliverRate float64 `json:"deliver_rate"`
+	AckRate     float64 `json:"ack_rate"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Откуда пришёл поисковый запрос (SearchQuery.Source)
+const (
+	SearchSourceServer = "server" // синхронный поиск через HTTP
+	SearchSourceWorker = "worker" // задача из очереди, обработанная воркером
+	SearchSourceGRPC   = "grpc"   // синхронный поиск через gRPC API
+)
+
+// SearchQuery — выполненный поиск во внешнем источнике,
+// соответствует таблице search_queries в бд
+type SearchQuery struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	Query        string    `json:"query" db:"query"`
+	Page         int       `json:"page" db:"page"`
+	PerPage      int       `json:"per_page" db:"per_page"`
+	ResultsSaved int       `json:"results_saved" db:"results_saved"` // сколько фото вернул поиск после сохранения
+	Source       string    `json:"source" db:"source"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// SearchSuggestion — часто встречающийся поисковый запрос и сколько раз его искали
+type SearchSuggestion struct {
+	Query string `json:"query" db:"query"`
+	Count int64  `json:"count" db:"count"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhotoSeries — серия связанных снимков пользователя, соответствует таблице photo_series в бд.
+// В отличие от подборки фото серии упорядочены: порядок хранится в photo_series_members.position
+type PhotoSeries struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PhotoSeriesUpdate описывает частичное обновление серии:
+// nil-поля не изменяются
+type PhotoSeriesUpdate struct {
+	Title       *string
+	Description *string
+}
+
+// PhotoSeriesWithPhotos — серия вместе с первыми фото в порядке серии.
+// PhotoCount — сколько всего фото в серии, даже если в Photos попали не все
+type PhotoSeriesWithPhotos struct {
+	PhotoSeries
+	Photos     []Photo `json:"photos"`
+	PhotoCount int64   `json:"photo_count"`
+}
+
+// ReorderSeries возвращает новый полный порядок серии: сначала фото в порядке requested,
+// затем фото из корзины (trashed) в прежнем порядке. current — все фото серии в текущем порядке.
+// Если requested не содержит каждое фото из current, кроме trashed, ровно один раз, возвращает
+// ErrInvalidSeriesOrder
+func ReorderSeries(current []uuid.UUID, trashed map[uuid.UUID]bool, requested []uuid.UUID) ([]uuid.UUID, error) {
+	if len(requested) != len(current)-len(trashed) {
+		return nil, ErrInvalidSeriesOrder
+	}
+	members := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		members[id] = false
+	}
+
+	order := make([]uuid.UUID, 0, len(current))
+	for _, id := range requested {
+		seen, ok := members[id]
+		if !ok || seen || trashed[id] {
+			return nil, ErrInvalidSeriesOrder
+		}
+		members[id] = true
+		order = append(order, id)
+	}
+	for _, id := range current {
+		if trashed[id] {
+			order = append(order, id)
+		}
+	}
+	return order, nil
+}
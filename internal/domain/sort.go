@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Поля, по которым можно сортировать списки фото (параметр sort)
+const (
+	SortByCreatedAt  = "created_at"
+	SortByUploadedAt = "uploaded_at"
+	SortByLikes      = "likes_count"
+	SortByViews      = "views_count"
+	SortByDownloads  = "downloads_count"
+	SortByPopularity = "popularity_score"
+)
+
+// PhotoSortFields — все допустимые значения sort в порядке для сообщений об ошибке
+var PhotoSortFields = []string{SortByCreatedAt, SortByUploadedAt, SortByLikes, SortByViews, SortByDownloads, SortByPopularity}
+
+// PhotoSort задаёт порядок выдачи списка фото. Нулевое значение — порядок по умолчанию
+// для конкретного списка (для последних фото — новые первыми, для поиска — по релевантности)
+type PhotoSort struct {
+	Field string
+	Desc  bool
+}
+
+// IsZero сообщает, что сортировка не задана
+func (s PhotoSort) IsZero() bool {
+	return s.Field == ""
+}
+
+// ParsePhotoSort проверяет параметры sort и order по белому списку.
+// Пустой sort — сортировка по умолчанию; пустой order — по убыванию.
+// Ошибка оборачивает ErrInvalidSort
+func ParsePhotoSort(field, order string) (PhotoSort, error) {
+	field = strings.ToLower(strings.TrimSpace(field))
+	order = strings.ToLower(strings.TrimSpace(order))
+
+	if field == "" {
+		if order != "" {
+			return PhotoSort{}, fmt.Errorf("%w: order без sort", ErrInvalidSort)
+		}
+		return PhotoSort{}, nil
+	}
+
+	switch field {
+	case SortByCreatedAt, SortByUploadedAt, SortByLikes, SortByViews, SortByDownloads, SortByPopularity:
+	default:
+		return PhotoSort{}, fmt.Errorf("%w: неизвестное поле %q", ErrInvalidSort, field)
+	}
+
+	switch order {
+	case "", "desc":
+		return PhotoSort{Field: field, Desc: true}, nil
+	case "asc":
+		return PhotoSort{Field: field}, nil
+	default:
+		return PhotoSort{}, fmt.Errorf("%w: order должен быть asc или desc, получено %q", ErrInvalidSort, order)
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadIntent — ожидающая загрузка фото напрямую в хранилище по подписанной ссылке,
+// соответствует таблице upload_intents в бд. Вместе с ней создаётся фото с тем же ID в статусе
+// PhotoStatusPending; после подтверждения фото становится PhotoStatusActive, а запись удаляется
+type UploadIntent struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	S3Key       string    `json:"-" db:"s3_key"`
+	ContentType string    `json:"content_type" db:"content_type"` // MIME-тип, заявленный клиентом
+	SizeBytes   int64     `json:"size_bytes" db:"size_bytes"`     // размер файла, заявленный клиентом
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"` // до этого момента действует ссылка на загрузку
+}
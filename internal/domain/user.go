@@ -21,3 +21,11 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// UserUpdate описывает частичное обновление пользователя:
+// nil-поля не изменяются
+type UserUpdate struct {
+	Username     *string
+	Email        *string
+	PasswordHash *string
+}
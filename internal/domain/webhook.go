@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook — подписка пользователя на события фото, соответствует таблице webhooks в бд.
+// На URL отправляется POST с JSON события, подписанный HMAC-SHA256 по Secret
+type Webhook struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	URL       string     `json:"url" db:"url"`
+	Events    EventTypes `json:"events" db:"events"`
+	Secret    string     `json:"-" db:"secret"` // отдаётся клиенту только при создании
+	Active    bool       `json:"active" db:"active"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// EventTypes — типы событий, на которые подписан вебхук.
+// Хранится так же, как ColorPalette: TEXT[] в Postgres и литерал массива текстом в SQLite
+type EventTypes []string
+
+// Value записывает типы событий литералом массива Postgres
+func (e EventTypes) Value() (driver.Value, error) {
+	return ColorPalette(e).Value()
+}
+
+// Scan читает типы событий из литерала массива Postgres
+func (e *EventTypes) Scan(src any) error {
+	var p ColorPalette
+	if err := p.Scan(src); err != nil {
+		return err
+	}
+	*e = EventTypes(p)
+	return nil
+}
+
+// WebhookDelivery — одна попытка доставки события на вебхук, соответствует таблице webhook_deliveries
+type WebhookDelivery struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	WebhookID  uuid.UUID `json:"webhook_id" db:"webhook_id"`
+	EventID    uuid.UUID `json:"event_id" db:"event_id"`
+	EventType  string    `json:"event_type" db:"event_type"`
+	Attempt    int       `json:"attempt" db:"attempt"`
+	StatusCode int       `json:"status_code" db:"status_code"` // 0, если ответ не получен
+	Error      string    `json:"error" db:"error"`
+	Success    bool      `json:"success" db:"success"`
+	DurationMs int64     `json:"duration_ms" db:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+)
+
+// NewPhotoAnalytics возвращает подписчика, который учитывает события фото в метриках
+// (mediaapp_photo_events_total) и пишет их в лог для последующего анализа
+func NewPhotoAnalytics(m *metrics.Metrics, log *slog.Logger) Handler {
+	return func(ctx context.Context, event Event) {
+		var photo domain.Photo
+		switch e := event.(type) {
+		case domain.PhotoCreatedEvent:
+			photo = e.Photo
+		case domain.PhotoUpdatedEvent:
+			photo = e.Photo
+		default:
+			return
+		}
+
+		m.IncPhotoEvents(event.EventType(), photo.ExternalSource)
+		logger.FromContext(ctx, log).Info("photo event recorded",
+			"event_type", event.EventType(),
+			"photo_id", photo.ID,
+			"source", photo.ExternalSource,
+			"user_id", photo.UserID,
+		)
+	}
+}
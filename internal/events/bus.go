@@ -0,0 +1,119 @@
+// Package events — шина доменных событий внутри процесса.
+// Побочные эффекты (кеш, аналитика, уведомления) подписываются на события,
+// а бизнес-логика только публикует их и ничего о подписчиках не знает
+package events
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+
+	"github.com/GoArmGo/MediaApp/internal/logger"
+)
+
+// Event — доменное событие; EventType — ключ, по которому на него подписываются
+type Event interface {
+	EventType() string
+}
+
+// Handler обрабатывает событие. Ошибки подписчик обрабатывает сам:
+// публикующий код о них не узнаёт
+type Handler func(ctx context.Context, event Event)
+
+// EventBus — синхронная шина: Publish вызывает подписчиков по очереди в горутине публикующего
+// и возвращается, когда все они отработали. Паника подписчика не перехватывается
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	logger   *slog.Logger
+}
+
+// NewEventBus создаёт пустую синхронную шину событий
+func NewEventBus(logger *slog.Logger) *EventBus {
+	return &EventBus{handlers: make(map[string][]Handler), logger: logger}
+}
+
+// Subscribe подписывает handler на события типа eventType.
+// Подписчики вызываются в порядке подписки
+func (b *EventBus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish синхронно передаёт событие всем подписчикам его типа
+func (b *EventBus) Publish(ctx context.Context, event Event) {
+	handlers := b.handlersFor(event.EventType())
+	logger.FromContext(ctx, b.logger).Debug("publishing event", "event_type", event.EventType(), "handlers", len(handlers))
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
+
+// handlersFor возвращает копию списка подписчиков, чтобы вызывать их без блокировки
+func (b *EventBus) handlersFor(eventType string) []Handler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]Handler(nil), b.handlers[eventType]...)
+}
+
+// AsyncEventBus — шина, которая запускает каждого подписчика в отдельной горутине.
+// Publish не ждёт подписчиков, а паника подписчика перехватывается и логируется,
+// чтобы не уронить запрос, опубликовавший событие. Close дожидается запущенных подписчиков
+type AsyncEventBus struct {
+	*EventBus
+
+	closeMu sync.RWMutex
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewAsyncEventBus создаёт пустую асинхронную шину событий
+func NewAsyncEventBus(logger *slog.Logger) *AsyncEventBus {
+	return &AsyncEventBus{EventBus: NewEventBus(logger)}
+}
+
+// Publish запускает подписчиков события в фоне. Контекст подписчиков не отменяется вместе с ctx
+// (запрос обычно завершается раньше), но сохраняет его значения, например request_id.
+// После Close события отбрасываются
+func (b *AsyncEventBus) Publish(ctx context.Context, event Event) {
+	b.closeMu.RLock()
+	defer b.closeMu.RUnlock()
+
+	log := logger.FromContext(ctx, b.logger)
+	if b.closed {
+		log.Warn("event bus is closed, event dropped", "event_type", event.EventType())
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	handlers := b.handlersFor(event.EventType())
+	log.Debug("publishing event asynchronously", "event_type", event.EventType(), "handlers", len(handlers))
+	for _, handler := range handlers {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			defer func() {
+				if p := recover(); p != nil {
+					log.Error("event handler panicked",
+						"event_type", event.EventType(),
+						"panic", p,
+						"stack", string(debug.Stack()),
+					)
+				}
+			}()
+			handler(ctx, event)
+		}()
+	}
+}
+
+// Close перестаёт принимать события и дожидается завершения уже запущенных подписчиков
+func (b *AsyncEventBus) Close() error {
+	b.closeMu.Lock()
+	b.closed = true
+	b.closeMu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
@@ -0,0 +1,71 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// statusFromError переводит ошибку usecase в статус gRPC по тем же правилам, что и HTTP-обработчики.
+// Текст внутренних ошибок клиенту не отдаётся — он есть в логах
+func statusFromError(err error, internalMessage string) error {
+	var rateLimited *domain.ErrRateLimited
+	switch {
+	case errors.Is(err, domain.ErrExternalPhotoNotFound):
+		return status.Error(codes.NotFound, "Фото не найдено во внешнем источнике")
+	case errors.Is(err, domain.ErrPhotoNotFound):
+		return status.Error(codes.NotFound, "Фото не найдено")
+	case errors.Is(err, domain.ErrInvalidSort), errors.Is(err, domain.ErrInvalidFilter):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &rateLimited):
+		return withDetails(codes.ResourceExhausted, "Превышен лимит запросов к внешнему API, повторите позже",
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(rateLimited.RetryAfter().Round(time.Second))})
+	case errors.Is(err, domain.ErrSourceUnavailable), errors.Is(err, usecase.ErrDownloadNotImage):
+		return status.Error(codes.Unavailable, "Внешний источник фото недоступен, повторите позже")
+	case errors.Is(err, lock.ErrNotAcquired):
+		return withDetails(codes.Unavailable, "Фото сейчас обрабатывается, повторите позже",
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "Время обработки запроса истекло")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "Запрос отменён")
+	default:
+		return status.Error(codes.Internal, internalMessage)
+	}
+}
+
+// invalidArgument возвращает InvalidArgument с описанием каждого некорректного поля в BadRequest
+func invalidArgument(fieldErrors []validation.FieldError) error {
+	details := &errdetails.BadRequest{}
+	for _, fe := range fieldErrors {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Field,
+			Description: fe.Message,
+		})
+	}
+	message := "Некорректные параметры запроса"
+	if len(fieldErrors) > 0 {
+		message = fmt.Sprintf("%s: %s", message, fieldErrors[0].Message)
+	}
+	return withDetails(codes.InvalidArgument, message, details)
+}
+
+// withDetails добавляет к статусу подробности; если их не удалось закодировать, возвращает статус без них
+func withDetails(code codes.Code, message string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, message)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,91 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/handler"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey — ключ метаданных с ID запроса; то же, что заголовок X-Request-ID в HTTP API
+const requestIDKey = "x-request-id"
+
+// UnaryInterceptors возвращает цепочку перехватчиков сервера: ID запроса, журнал,
+// ограничение времени обработки (timeout <= 0 — без ограничения) и перехват паник
+func UnaryInterceptors(timeout time.Duration, log *slog.Logger) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(
+		requestIDInterceptor(),
+		loggingInterceptor(log),
+		timeoutInterceptor(timeout),
+		recoveryInterceptor(log),
+	)
+}
+
+// requestIDInterceptor берёт ID запроса из метаданных или создаёт новый и возвращает его клиенту в заголовке
+func requestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(requestIDKey); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if !handler.ValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+		return next(logger.WithRequestID(ctx, requestID), req)
+	}
+}
+
+// loggingInterceptor пишет в журнал каждый вызов с кодом ответа и длительностью
+func loggingInterceptor(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		logger.FromContext(ctx, log).Info("grpc request",
+			"method", info.FullMethod,
+			"code", status.Code(err).String(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+		return resp, err
+	}
+}
+
+// timeoutInterceptor ограничивает обработку вызова так же, как REQUEST_TIMEOUT в HTTP API.
+// Более ранний дедлайн клиента сохраняется
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		if timeout <= 0 {
+			return next(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return next(ctx, req)
+	}
+}
+
+// recoveryInterceptor превращает панику обработчика в Internal, чтобы она не уронила процесс
+func recoveryInterceptor(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.FromContext(ctx, log).Error("grpc handler panic",
+					"method", info.FullMethod,
+					"panic", p,
+					"stack", string(debug.Stack()),
+				)
+				err = status.Error(codes.Internal, "Внутренняя ошибка сервера")
+			}
+		}()
+		return next(ctx, req)
+	}
+}
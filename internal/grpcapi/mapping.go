@@ -0,0 +1,62 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/grpcapi/photov1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// photoToProto переводит фото в сообщение gRPC; используется всеми методами сервиса
+func photoToProto(p *domain.Photo) *photov1.Photo {
+	out := &photov1.Photo{
+		Id:              p.ID.String(),
+		ExternalId:      p.UnsplashID,
+		ExternalSource:  p.ExternalSource,
+		UserId:          p.UserID.String(),
+		S3Url:           p.S3URL,
+		Title:           p.Title,
+		Description:     p.Description,
+		AuthorName:      p.AuthorName,
+		Width:           int32(p.Width),
+		Height:          int32(p.Height),
+		LikesCount:      int32(p.LikesCount),
+		OriginalUrl:     p.OriginalURL,
+		UploadedAt:      timestampOrNil(p.UploadedAt),
+		ViewsCount:      p.ViewsCount,
+		DownloadsCount:  p.DownloadsCount,
+		CreatedAt:       timestampOrNil(p.CreatedAt),
+		UpdatedAt:       timestampOrNil(p.UpdatedAt),
+		SizeBytes:       p.SizeBytes,
+		MimeType:        p.MimeType,
+		DominantColors:  p.DominantColors,
+		PopularityScore: p.PopularityScore,
+		Latitude:        p.Latitude,
+		Longitude:       p.Longitude,
+		BlurHash:        p.BlurHash,
+		DominantColor:   p.DominantColor,
+		Attribution:     domain.GenerateAttribution(p),
+	}
+	for _, tag := range p.Tags {
+		out.Tags = append(out.Tags, &photov1.Tag{Id: tag.ID.String(), Name: tag.Name})
+	}
+	return out
+}
+
+// photosToProto переводит список фото; пустой список остаётся пустым, а не nil
+func photosToProto(photos []domain.Photo) []*photov1.Photo {
+	out := make([]*photov1.Photo, 0, len(photos))
+	for i := range photos {
+		out = append(out, photoToProto(&photos[i]))
+	}
+	return out
+}
+
+// timestampOrNil не передаёт нулевое время: клиент увидит отсутствующее поле, а не 0001-01-01
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,161 @@
+// Package grpcapi — gRPC API фото для внутренних вызовов между сервисами.
+// Методы делегируют в тот же usecase.PhotoUseCase, что и HTTP-обработчики
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/grpcapi/photov1"
+	"github.com/GoArmGo/MediaApp/internal/handler"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPerPage — размер страницы, если per_page не указан (как в HTTP API)
+const defaultPerPage = 10
+
+// getPhotoRequest — правила проверки GetPhotoRequest
+type getPhotoRequest struct {
+	ExternalID string `json:"external_id" validate:"required,max=64"`
+}
+
+// PhotoServer реализует photov1.PhotoServiceServer
+type PhotoServer struct {
+	photov1.UnimplementedPhotoServiceServer
+
+	photoUseCase usecase.PhotoUseCase
+	validator    *validation.Validator
+	maxPerPage   int
+	logger       *slog.Logger
+}
+
+// NewPhotoServer создает новый экземпляр PhotoServer. per_page больше maxPerPage урезается до него
+func NewPhotoServer(photoUseCase usecase.PhotoUseCase, validator *validation.Validator, maxPerPage int, logger *slog.Logger) *PhotoServer {
+	return &PhotoServer{
+		photoUseCase: photoUseCase,
+		validator:    validator,
+		maxPerPage:   maxPerPage,
+		logger:       logger,
+	}
+}
+
+// GetPhoto реализует метод PhotoServiceServer
+func (s *PhotoServer) GetPhoto(ctx context.Context, req *photov1.GetPhotoRequest) (*photov1.Photo, error) {
+	if errs := s.validator.Validate(&getPhotoRequest{ExternalID: req.GetExternalId()}); len(errs) > 0 {
+		return nil, invalidArgument(errs)
+	}
+
+	photo, err := s.photoUseCase.GetOrCreatePhotoByUnsplashID(ctx, req.GetExternalId())
+	if err != nil {
+		s.log(ctx).Error("failed to get or create photo", "external_id", req.GetExternalId(), "error", err)
+		return nil, statusFromError(err, "Ошибка при получении или создании фото")
+	}
+	return photoToProto(photo), nil
+}
+
+// SearchAndSave реализует метод PhotoServiceServer. Параметры проверяются по тем же правилам,
+// что и у GET /photos/search; нулевые page и per_page заменяются значениями по умолчанию
+func (s *PhotoServer) SearchAndSave(ctx context.Context, req *photov1.SearchAndSaveRequest) (*photov1.SearchAndSaveResponse, error) {
+	params := handler.SearchPhotosRequest{
+		Query:       req.GetQuery(),
+		Page:        int(req.GetPage()),
+		PerPage:     int(req.GetPerPage()),
+		Orientation: req.GetOrientation(),
+		Color:       req.GetColor(),
+		OrderBy:     req.GetOrderBy(),
+		MinWidth:    int(req.GetMinWidth()),
+		MinHeight:   int(req.GetMinHeight()),
+	}
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PerPage == 0 {
+		params.PerPage = defaultPerPage
+	}
+	if errs := s.validator.Validate(&params); len(errs) > 0 {
+		return nil, invalidArgument(errs)
+	}
+
+	result, err := s.photoUseCase.SearchAndSavePhotos(ctx, params.Query, params.Page, params.PerPage,
+		params.Orientation, params.Color, params.OrderBy, params.MinWidth, params.MinHeight, domain.SearchSourceGRPC)
+	if err != nil {
+		s.log(ctx).Error("failed to search and save photos", "query", params.Query, "error", err)
+		return nil, statusFromError(err, "Ошибка поиска фото")
+	}
+
+	return &photov1.SearchAndSaveResponse{
+		Photos:     photosToProto(result.Photos),
+		Page:       int32(params.Page),
+		PerPage:    int32(params.PerPage),
+		Total:      int64(result.Total),
+		TotalPages: int32(result.TotalPages),
+		HasNext:    params.Page < result.TotalPages,
+	}, nil
+}
+
+// GetRecent реализует метод PhotoServiceServer
+func (s *PhotoServer) GetRecent(ctx context.Context, req *photov1.GetRecentRequest) (*photov1.GetRecentResponse, error) {
+	page, perPage := s.pagination(req.GetPage(), req.GetPerPage())
+	sort, err := domain.ParsePhotoSort(req.GetSort(), req.GetOrder())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Некорректная сортировка: допустимо sort=%s и order=asc|desc",
+			strings.Join(domain.PhotoSortFields, "|"))
+	}
+
+	photos, total, err := s.photoUseCase.GetRecentPhotosFromDB(ctx, page, perPage, req.GetIncludeTags(), sort)
+	if err != nil {
+		s.log(ctx).Error("failed to fetch recent photos", "error", err)
+		return nil, statusFromError(err, "Ошибка получения последних фото")
+	}
+
+	return &photov1.GetRecentResponse{
+		Photos:     photosToProto(photos),
+		Page:       int32(page),
+		PerPage:    int32(perPage),
+		Total:      total,
+		TotalPages: int32(domain.TotalPagesFor(int(total), perPage)),
+	}, nil
+}
+
+// GetDetails реализует метод PhotoServiceServer
+func (s *PhotoServer) GetDetails(ctx context.Context, req *photov1.GetDetailsRequest) (*photov1.Photo, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Некорректный id")
+	}
+
+	photo, err := s.photoUseCase.GetPhotoDetailsFromDB(ctx, id)
+	if err != nil {
+		s.log(ctx).Error("failed to fetch photo details", "photo_id", id, "error", err)
+		return nil, statusFromError(err, "Ошибка получения информации о фото")
+	}
+	return photoToProto(photo), nil
+}
+
+// pagination заменяет неположительные page и per_page значениями по умолчанию
+// и урезает per_page до maxPerPage (maxPerPage <= 0 — без ограничения)
+func (s *PhotoServer) pagination(page, perPage int32) (int, int) {
+	p, pp := int(page), int(perPage)
+	if p <= 0 {
+		p = 1
+	}
+	if pp <= 0 {
+		pp = defaultPerPage
+	}
+	if s.maxPerPage > 0 && pp > s.maxPerPage {
+		pp = s.maxPerPage
+	}
+	return p, pp
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (s *PhotoServer) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminHandler обрабатывает служебные маршруты мониторинга
+type AdminHandler struct {
+	queueStats    ports.QueueStatsProvider  // nil, если мониторинг очередей не настроен
+	schemaVersion ports.SchemaVersionReader // nil у SQLite: её схема создаётся без миграций
+	tasks         ports.PhotoSearchPublisher
+	photoUseCase  usecase.PhotoUseCase
+	queueName     string
+	dlqName       string
+	// authorMaxPhotos — предел фото на один импорт автора; 0 — без ограничения
+	authorMaxPhotos int
+	validator       *validation.Validator
+	logger          *slog.Logger
+}
+
+// NewAdminHandler — конструктор для AdminHandler. dlqName может быть пустым
+func NewAdminHandler(queueStats ports.QueueStatsProvider, schemaVersion ports.SchemaVersionReader,
+	tasks ports.PhotoSearchPublisher, photoUseCase usecase.PhotoUseCase, queueName, dlqName string, authorMaxPhotos int,
+	validator *validation.Validator, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		queueStats:      queueStats,
+		schemaVersion:   schemaVersion,
+		tasks:           tasks,
+		photoUseCase:    photoUseCase,
+		queueName:       queueName,
+		dlqName:         dlqName,
+		authorMaxPhotos: authorMaxPhotos,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// queuesResponse — ответ GET /admin/queues; DeadLetterQueue — null, если DLQ не настроена или не создана
+type queuesResponse struct {
+	Queue           *domain.QueueStats `json:"queue"`
+	DeadLetterQueue *domain.QueueStats `json:"dead_letter_queue"`
+}
+
+// GetQueueStats — глубина очереди задач воркера, число потребителей, скорости и глубина DLQ (GET /admin/queues).
+func (h *AdminHandler) GetQueueStats(w http.ResponseWriter, r *http.Request) {
+	if h.queueStats == nil {
+		respondWithErrorCode(w, http.StatusServiceUnavailable, CodeFeatureUnavailable, "Мониторинг очередей не настроен", h.logger)
+		return
+	}
+
+	var resp queuesResponse
+	var err error
+	resp.Queue, err = h.queueStats.GetQueueStats(r.Context(), h.queueName)
+	if err != nil {
+		if errors.Is(err, domain.ErrQueueNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodeQueueNotFound, "Очередь не найдена", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to fetch queue stats", "queue", h.queueName, "error", err)
+		respondWithError(w, http.StatusBadGateway, "Ошибка получения состояния очереди", h.logger)
+		return
+	}
+
+	if h.dlqName != "" {
+		resp.DeadLetterQueue, err = h.queueStats.GetQueueStats(r.Context(), h.dlqName)
+		if err != nil {
+			// без DLQ ответ всё равно полезен: глубину основной очереди отдаём
+			h.log(r.Context()).Warn("failed to fetch dead letter queue stats", "queue", h.dlqName, "error", err)
+		}
+	}
+
+	h.log(r.Context()).Info("queue stats fetched", "queue", h.queueName, "messages", resp.Queue.Messages, "consumers", resp.Queue.Consumers)
+	respondWithJSON(w, http.StatusOK, resp, h.logger)
+}
+
+// schemaVersionResponse — ответ GET /admin/migration-version
+type schemaVersionResponse struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+}
+
+// GetMigrationVersion — текущая версия схемы бд и признак dirty (GET /admin/migration-version).
+func (h *AdminHandler) GetMigrationVersion(w http.ResponseWriter, r *http.Request) {
+	if h.schemaVersion == nil {
+		respondWithErrorCode(w, http.StatusServiceUnavailable, CodeFeatureUnavailable, "Версия схемы доступна только для PostgreSQL", h.logger)
+		return
+	}
+
+	version, dirty, err := h.schemaVersion.SchemaVersion(r.Context())
+	if err != nil {
+		h.log(r.Context()).Error("failed to fetch schema version", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения версии схемы", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("schema version fetched", "version", version, "dirty", dirty)
+	respondWithJSON(w, http.StatusOK, schemaVersionResponse{Version: version, Dirty: dirty}, h.logger)
+}
+
+// ImportCollection ставит в очередь импорт коллекции Unsplash (POST /admin/collections/{id}/import).
+// Воркер проходит коллекцию страница за страницей и пропускает уже сохранённые фото
+func (h *AdminHandler) ImportCollection(w http.ResponseWriter, r *http.Request) {
+	collectionID := chi.URLParam(r, "id")
+	if collectionID == "" || len(collectionID) > 64 {
+		h.log(r.Context()).Warn("invalid collection id", "id", collectionID)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID коллекции", h.logger)
+		return
+	}
+
+	req := ImportCollectionRequest{PerPage: 30}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	err := h.tasks.PublishPhotoSearchRequest(r.Context(), payloads.PhotoSearchPayload{
+		Type:         payloads.TaskSyncCollection,
+		CollectionID: collectionID,
+		Page:         1,
+		PerPage:      req.PerPage,
+	})
+	if err != nil {
+		h.log(r.Context()).Error("failed to publish collection import task", "collection_id", collectionID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка постановки задачи в очередь", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("collection import queued", "collection_id", collectionID, "per_page", req.PerPage)
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "Импорт коллекции поставлен в очередь"}, h.logger)
+}
+
+// unsplashUsernamePattern — имя пользователя Unsplash: латиница, цифры и подчёркивание
+var unsplashUsernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// ImportAuthor ставит в очередь импорт фото автора Unsplash (POST /admin/authors/{username}/import).
+// Существование автора проверяется сразу, чтобы ответить 404, а не потерять задачу в воркере.
+// max_photos больше настроенного предела урезается до него
+func (h *AdminHandler) ImportAuthor(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+	if !unsplashUsernamePattern.MatchString(username) {
+		h.log(r.Context()).Warn("invalid author username", "username", username)
+		respondWithError(w, http.StatusBadRequest, "Некорректное имя автора", h.logger)
+		return
+	}
+
+	req := ImportAuthorRequest{PerPage: 30}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+	maxPhotos := req.MaxPhotos
+	if h.authorMaxPhotos > 0 && (maxPhotos == 0 || maxPhotos > h.authorMaxPhotos) {
+		maxPhotos = h.authorMaxPhotos
+	}
+
+	if err := h.photoUseCase.CheckUnsplashAuthor(r.Context(), username); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrExternalAuthorNotFound):
+			h.log(r.Context()).Warn("author not found in Unsplash", "username", username)
+			respondWithErrorCode(w, http.StatusNotFound, CodeExternalAuthorNotFound, "Автор не найден в Unsplash", h.logger)
+		case errors.Is(err, usecase.ErrAuthorImportUnavailable):
+			h.log(r.Context()).Warn("author import requested without Unsplash source", "username", username)
+			respondWithErrorCode(w, http.StatusServiceUnavailable, CodeFeatureUnavailable, "Импорт фото автора недоступен: источник Unsplash не настроен", h.logger)
+		default:
+			if respondIfRateLimited(w, err, h.logger) {
+				return
+			}
+			h.log(r.Context()).Error("failed to check author", "username", username, "error", err)
+			respondWithError(w, http.StatusBadGateway, "Ошибка проверки автора в Unsplash", h.logger)
+		}
+		return
+	}
+
+	err := h.tasks.PublishPhotoSearchRequest(r.Context(), payloads.PhotoSearchPayload{
+		Type:           payloads.TaskSyncAuthor,
+		AuthorUsername: username,
+		PerPage:        req.PerPage,
+		MaxPhotos:      maxPhotos,
+	})
+	if err != nil {
+		h.log(r.Context()).Error("failed to publish author import task", "username", username, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка постановки задачи в очередь", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("author import queued", "username", username, "per_page", req.PerPage, "max_photos", maxPhotos)
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "Импорт фото автора поставлен в очередь"}, h.logger)
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (h *AdminHandler) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
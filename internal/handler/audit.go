@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxAuditBodyBytes — ответы больше этого в журнал аудита не сохраняются
+	maxAuditBodyBytes = 64 << 10
+
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// auditSkipRoutes — изменяющие по методу маршруты, которые ничего не меняют
+var auditSkipRoutes = map[string]bool{
+	"/users/login": true,
+}
+
+// auditSensitiveFields удаляются из сохраняемого ответа
+var auditSensitiveFields = []string{"token", "password", "password_hash"}
+
+// auditState передаётся вниз по цепочке через контекст, чтобы JWTAuth,
+// стоящий глубже AuditMiddleware, мог сообщить ему ID пользователя
+type auditState struct {
+	userID uuid.UUID
+}
+
+const auditStateKey ctxKey = "audit_state"
+
+// setAuditUser запоминает аутентифицированного пользователя для журнала аудита
+func setAuditUser(ctx context.Context, userID uuid.UUID) {
+	if state, ok := ctx.Value(auditStateKey).(*auditState); ok {
+		state.userID = userID
+	}
+}
+
+// AuditMiddleware — middleware, записывающее в журнал аудита каждый успешный изменяющий запрос
+// (POST/PUT/PATCH/DELETE): кто, что, над какой сущностью и с какого IP. Новое значение берётся
+// из JSON-ответа. Запись не блокирует запрос и не влияет на его результат: storage должен быть
+// асинхронным (см. storage.AsyncAuditStorage), а его ошибки только логируются
+func AuditMiddleware(storage ports.AuditStorage, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isWriteMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			state := &auditState{}
+			ctx := context.WithValue(r.Context(), auditStateKey, state)
+			aw := &auditResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(aw, r.WithContext(ctx))
+
+			route := r.URL.Path
+			if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			if aw.statusCode >= http.StatusBadRequest || auditSkipRoutes[route] {
+				return
+			}
+
+			newValue := auditValue(aw)
+			event := &domain.AuditLog{
+				UserID:     state.userID,
+				Action:     r.Method + " " + route,
+				EntityType: auditEntityType(route),
+				EntityID:   auditEntityID(r, route, state.userID, newValue),
+				NewValue:   newValue,
+				IPAddress:  clientIP(r),
+			}
+			if err := storage.RecordAuditEvent(r.Context(), event); err != nil {
+				logger.FromContext(r.Context(), log).Error("failed to record audit event", "action", event.Action, "error", err)
+			}
+		})
+	}
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditEntityType — первый сегмент маршрута: "/photos/{id}/restore" -> "photos"
+func auditEntityType(route string) string {
+	entity, _, _ := strings.Cut(strings.TrimPrefix(route, "/"), "/")
+	return entity
+}
+
+// auditEntityID берёт ID сущности из пути ({id}), для /users/me — ID пользователя,
+// иначе — поле "id" из ответа (например, у только что созданного фото)
+func auditEntityID(r *http.Request, route string, userID uuid.UUID, newValue json.RawMessage) uuid.UUID {
+	if id, err := uuid.Parse(chi.URLParam(r, "id")); err == nil {
+		return id
+	}
+	if strings.HasPrefix(route, "/users/me") {
+		return userID
+	}
+	var body struct {
+		ID   uuid.UUID `json:"id"`
+		User struct {
+			ID uuid.UUID `json:"id"`
+		} `json:"user"`
+	}
+	if len(newValue) > 0 && json.Unmarshal(newValue, &body) == nil {
+		if body.ID != uuid.Nil {
+			return body.ID
+		}
+		return body.User.ID
+	}
+	return uuid.Nil
+}
+
+// auditValue возвращает JSON-ответ без чувствительных полей или nil, если ответ не JSON-объект
+func auditValue(aw *auditResponseWriter) json.RawMessage {
+	if aw.truncated || aw.body.Len() == 0 ||
+		!strings.HasPrefix(aw.Header().Get("Content-Type"), "application/json") {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(aw.body.Bytes(), &fields); err != nil {
+		return nil
+	}
+	for _, name := range auditSensitiveFields {
+		delete(fields, name)
+	}
+	value, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// clientIP — IP клиента из RemoteAddr без порта
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// auditResponseWriter запоминает статус и начало тела ответа (не больше maxAuditBodyBytes)
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+	truncated  bool
+}
+
+func (aw *auditResponseWriter) WriteHeader(code int) {
+	aw.statusCode = code
+	aw.ResponseWriter.WriteHeader(code)
+}
+
+func (aw *auditResponseWriter) Write(p []byte) (int, error) {
+	if !aw.truncated {
+		if aw.body.Len()+len(p) > maxAuditBodyBytes {
+			aw.truncated = true
+			aw.body.Reset()
+		} else {
+			aw.body.Write(p)
+		}
+	}
+	return aw.ResponseWriter.Write(p)
+}
+
+// AuditHandler — обработчик HTTP-запросов к журналу аудита.
+type AuditHandler struct {
+	storage ports.AuditStorage
+	logger  *slog.Logger
+}
+
+// NewAuditHandler — конструктор для AuditHandler.
+func NewAuditHandler(storage ports.AuditStorage, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{storage: storage, logger: logger}
+}
+
+// ListAuditEvents — последние события аудита (GET /admin/audit?entity_type=photos&limit=100).
+func (h *AuditHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	entityType := r.URL.Query().Get("entity_type")
+
+	limit := defaultAuditLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			respondWithError(w, http.StatusBadRequest, "Некорректный limit", h.logger)
+			return
+		}
+		limit = min(parsed, maxAuditLimit)
+	}
+
+	events, err := h.storage.ListAuditEvents(r.Context(), entityType, limit)
+	if err != nil {
+		logger.FromContext(r.Context(), h.logger).Error("failed to list audit events", "entity_type", entityType, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения журнала аудита", h.logger)
+		return
+	}
+
+	logger.FromContext(r.Context(), h.logger).Info("audit events fetched successfully", "entity_type", entityType, "count", len(events))
+	respondWithJSON(w, http.StatusOK, events, h.logger)
+}
+
+// AdminOnly — middleware, пропускающее только пользователей из списка администраторов.
+// Должно стоять после JWTAuth
+func AdminOnly(adminIDs []uuid.UUID, log *slog.Logger) func(next http.Handler) http.Handler {
+	admins := adminSet(adminIDs)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := UserIDFromContext(r.Context())
+			if !ok || !admins[userID] {
+				logger.FromContext(r.Context(), log).Warn("admin access denied", "user_id", userID, "path", r.URL.Path)
+				respondWithError(w, http.StatusForbidden, "Доступ запрещён", log)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+const adminKey ctxKey = "admin"
+
+// MarkAdmin — middleware для публичных маршрутов: отмечает в контексте запросы администраторов
+// (см. isAdmin), остальные пропускает как есть. Должно стоять после OptionalJWTAuth
+func MarkAdmin(adminIDs []uuid.UUID) func(next http.Handler) http.Handler {
+	admins := adminSet(adminIDs)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := UserIDFromContext(r.Context())
+			if !ok || !admins[userID] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminKey, true)))
+		})
+	}
+}
+
+// isAdmin сообщает, что запрос сделан администратором (отмечен MarkAdmin)
+func isAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey).(bool)
+	return admin
+}
+
+// adminSet превращает список администраторов в множество для быстрой проверки
+func adminSet(adminIDs []uuid.UUID) map[uuid.UUID]bool {
+	admins := make(map[uuid.UUID]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+	return admins
+}
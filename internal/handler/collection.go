@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxCollectionNameLength — предел длины названия подборки (в символах), как у колонки collections.name
+const maxCollectionNameLength = 255
+
+// CollectionHandler — обработчик HTTP-запросов для работы с подборками фото.
+// Все маршруты требуют авторизации: пользователь видит только свои подборки
+type CollectionHandler struct {
+	collectionUseCase usecase.CollectionUseCase
+	maxPerPage        int // предел per_page для списков
+	logger            *slog.Logger
+}
+
+// NewCollectionHandler создаёт новый экземпляр CollectionHandler.
+func NewCollectionHandler(uc usecase.CollectionUseCase, maxPerPage int, logger *slog.Logger) *CollectionHandler {
+	return &CollectionHandler{
+		collectionUseCase: uc,
+		maxPerPage:        maxPerPage,
+		logger:            logger,
+	}
+}
+
+type createCollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type updateCollectionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// CreateCollection — создаёт подборку текущего пользователя.
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	var req createCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid create collection request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if msg := validateCollectionName(req.Name); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg, h.logger)
+		return
+	}
+
+	collection, err := h.collectionUseCase.CreateCollection(r.Context(), userID, req.Name, strings.TrimSpace(req.Description))
+	if err != nil {
+		h.log(r.Context()).Error("failed to create collection", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка при создании подборки", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, collection, h.logger)
+}
+
+// ListCollections — возвращает подборки текущего пользователя с пагинацией.
+func (h *CollectionHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+	collections, total, err := h.collectionUseCase.ListCollections(r.Context(), userID, page, perPage)
+	if err != nil {
+		h.log(r.Context()).Error("failed to list collections", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения подборок", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(collections, page, perPage, total), h.logger)
+}
+
+// GetCollection — возвращает подборку по ID.
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionUseCase.GetCollection(r.Context(), userID, collectionID)
+	if err != nil {
+		h.respondWithCollectionError(w, r, err, "Ошибка получения подборки")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, collection, h.logger)
+}
+
+// UpdateCollection — переименовывает подборку и/или меняет её описание.
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid update collection request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+		if msg := validateCollectionName(*req.Name); msg != "" {
+			respondWithError(w, http.StatusBadRequest, msg, h.logger)
+			return
+		}
+	}
+	if req.Description != nil {
+		*req.Description = strings.TrimSpace(*req.Description)
+	}
+
+	collection, err := h.collectionUseCase.UpdateCollection(r.Context(), userID, collectionID, domain.CollectionUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.respondWithCollectionError(w, r, err, "Ошибка обновления подборки")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, collection, h.logger)
+}
+
+// DeleteCollection — удаляет подборку. Фото из подборки не удаляются.
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionUseCase.DeleteCollection(r.Context(), userID, collectionID); err != nil {
+		h.respondWithCollectionError(w, r, err, "Ошибка удаления подборки")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListCollectionPhotos — возвращает фото подборки с пагинацией, последние добавленные первыми.
+func (h *CollectionHandler) ListCollectionPhotos(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+	photos, total, err := h.collectionUseCase.ListPhotos(r.Context(), userID, collectionID, page, perPage)
+	if err != nil {
+		h.respondWithCollectionError(w, r, err, "Ошибка получения фото подборки")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// AddCollectionPhoto — добавляет фото в подборку. Повторное добавление отвечает 200 вместо 201.
+func (h *CollectionHandler) AddCollectionPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+	photoID, ok := h.photoIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	added, err := h.collectionUseCase.AddPhoto(r.Context(), userID, collectionID, photoID)
+	if err != nil {
+		h.respondWithCollectionError(w, r, err, "Ошибка добавления фото в подборку")
+		return
+	}
+
+	status := http.StatusOK
+	if added {
+		status = http.StatusCreated
+	}
+	respondWithJSON(w, status, map[string]interface{}{
+		"collection_id": collectionID,
+		"photo_id":      photoID,
+		"added":         added,
+	}, h.logger)
+}
+
+// RemoveCollectionPhoto — убирает фото из подборки, само фото остаётся.
+func (h *CollectionHandler) RemoveCollectionPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+	photoID, ok := h.photoIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionUseCase.RemovePhoto(r.Context(), userID, collectionID, photoID); err != nil {
+		h.respondWithCollectionError(w, r, err, "Ошибка удаления фото из подборки")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// collectionFromRequest достаёт текущего пользователя и ID подборки из пути.
+// При ошибке сам отвечает клиенту и возвращает false
+func (h *CollectionHandler) collectionFromRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	raw := chi.URLParam(r, "id")
+	collectionID, err := uuid.Parse(raw)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid collection id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID подборки", h.logger)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, collectionID, true
+}
+
+// photoIDFromPath разбирает ID фото из пути /collections/{id}/photos/{photoID}
+func (h *CollectionHandler) photoIDFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	raw := chi.URLParam(r, "photoID")
+	photoID, err := uuid.Parse(raw)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return uuid.Nil, false
+	}
+	return photoID, true
+}
+
+// respondWithCollectionError отвечает 404 для ненайденной подборки или фото и 500 для остальных ошибок
+func (h *CollectionHandler) respondWithCollectionError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrCollectionNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, CodeCollectionNotFound, "Подборка не найдена", h.logger)
+	case errors.Is(err, domain.ErrPhotoNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+	default:
+		h.log(r.Context()).Error("collection request failed", "path", r.URL.Path, "error", err)
+		respondWithError(w, http.StatusInternalServerError, msg, h.logger)
+	}
+}
+
+// validateCollectionName возвращает текст ошибки для некорректного названия подборки
+func validateCollectionName(name string) string {
+	if name == "" {
+		return "Название подборки не может быть пустым"
+	}
+	if utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return "Название подборки должно быть не длиннее 255 символов"
+	}
+	return ""
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (h *CollectionHandler) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// maxDownloadPhotos — сколько фото можно скачать одним архивом
+const maxDownloadPhotos = 20
+
+// DownloadPhotos — отдаёт ZIP-архив с файлами нескольких фото (GET /photos/download?ids=uuid1,uuid2,...).
+// Архив пишется в ответ потоком: файлы из S3 по одному копируются в zip.Writer без буферизации в памяти.
+func (h *PhotoHandler) DownloadPhotos(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseIDList(r.URL.Query().Get("ids"))
+	if err != nil {
+		h.log(r.Context()).Warn("invalid ids parameter", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный список ids", h.logger)
+		return
+	}
+	if len(ids) == 0 {
+		respondWithError(w, http.StatusBadRequest, "Не указан параметр ids", h.logger)
+		return
+	}
+	if len(ids) > maxDownloadPhotos {
+		respondWithError(w, http.StatusBadRequest, "Можно скачать не больше 20 фото за раз", h.logger)
+		return
+	}
+
+	photos, err := h.photoUseCase.GetPhotosByIDs(r.Context(), ids)
+	if err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to fetch photos for download", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения фото", h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="photos.zip"`)
+
+	cw := &countingWriter{w: w}
+	zw := zip.NewWriter(cw)
+	for _, photo := range photos {
+		if err := h.writePhotoToZip(r, zw, photo); err != nil {
+			h.log(r.Context()).Error("failed to write photo to zip", "photo_id", photo.ID, "bytes_written", cw.n, "error", err)
+			if cw.n == 0 {
+				// клиенту ещё ничего не ушло — можно ответить ошибкой
+				w.Header().Del("Content-Disposition")
+				respondWithError(w, http.StatusInternalServerError, "Ошибка получения файла фото", h.logger)
+			}
+			// иначе архив уже частично отправлен: обрываем его, клиент получит битый ZIP
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		h.log(r.Context()).Error("failed to finish zip", "bytes_written", cw.n, "error", err)
+		return
+	}
+
+	h.log(r.Context()).Info("photos zip sent", "count", len(photos), "bytes_written", cw.n)
+}
+
+// writePhotoToZip копирует файл фото из S3 в очередную запись архива и учитывает просмотр фото
+func (h *PhotoHandler) writePhotoToZip(r *http.Request, zw *zip.Writer, photo domain.Photo) error {
+	file, err := h.photoUseCase.OpenPhotoFile(r.Context(), photo)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	h.photoUseCase.CountView(r.Context(), photo.ID)
+
+	// JPEG/PNG уже сжаты — храним без сжатия, чтобы не тратить CPU
+	entry, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     zipEntryName(photo),
+		Method:   zip.Store,
+		Modified: photo.UploadedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// zipEntryName — имя файла в архиве: ID фото и расширение исходного файла (у фото из Unsplash его нет — .jpg)
+func zipEntryName(photo domain.Photo) string {
+	ext := path.Ext(path.Base(photo.S3URL))
+	if ext == "" {
+		ext = ".jpg"
+	}
+	return photo.ID.String() + ext
+}
+
+// parseIDList разбирает список UUID через запятую, пропуская пустые элементы и повторы
+func parseIDList(raw string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]struct{})
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := uuid.Parse(part)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// countingWriter подсчитывает количество записанных байт
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
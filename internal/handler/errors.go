@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Коды ошибок API — стабильные значения поля code, по которым клиент различает ошибки.
+// Текст message может меняться, code — нет
+const (
+	// Общие коды; их выбирает respondWithError по HTTP-статусу, если точного кода нет
+	CodeBadRequest         = "BAD_REQUEST"         // 400
+	CodeUnauthorized       = "UNAUTHORIZED"        // 401
+	CodeForbidden          = "FORBIDDEN"           // 403
+	CodeNotFound           = "NOT_FOUND"           // 404
+	CodeConflict           = "CONFLICT"            // 409
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"   // 413
+	CodeUnsupportedMedia   = "UNSUPPORTED_MEDIA"   // 415
+	CodeUnprocessable      = "UNPROCESSABLE"       // 422
+	CodeRateLimited        = "RATE_LIMITED"        // 429, лимит запросов к нашему API
+	CodeInternal           = "INTERNAL_ERROR"      // 500
+	CodeUpstreamError      = "UPSTREAM_ERROR"      // 502
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE" // 503
+	CodeTimeout            = "TIMEOUT"             // 504
+
+	// CodeValidation — параметры или тело запроса не прошли проверку; details — ошибки по полям
+	CodeValidation = "VALIDATION_ERROR"
+
+	CodePhotoNotFound      = "PHOTO_NOT_FOUND"      // фото нет в бд или оно в корзине
+	CodeUserNotFound       = "USER_NOT_FOUND"       // пользователя нет
+	CodeCollectionNotFound = "COLLECTION_NOT_FOUND" // подборки нет или она чужая
+	CodeWebhookNotFound    = "WEBHOOK_NOT_FOUND"    // вебхука нет или он чужой
+	CodeSeriesNotFound     = "SERIES_NOT_FOUND"     // серии нет или она чужая
+	CodeQueueNotFound      = "QUEUE_NOT_FOUND"      // очереди брокера нет
+	CodeUploadNotFound     = "UPLOAD_NOT_FOUND"     // ожидающей загрузки нет, она чужая или истекла
+
+	// CodeExternalPhotoNotFound — фото нет во внешнем источнике; details — source и unsplash_id
+	CodeExternalPhotoNotFound = "EXTERNAL_PHOTO_NOT_FOUND"
+	// CodeExternalAuthorNotFound — автора нет во внешнем источнике
+	CodeExternalAuthorNotFound = "EXTERNAL_AUTHOR_NOT_FOUND"
+	// CodeExternalRateLimited — исчерпан лимит внешнего API (Unsplash, Pixabay, Pexels);
+	// details — source, заголовок Retry-After — сколько ждать
+	CodeExternalRateLimited = "EXTERNAL_RATE_LIMITED"
+	// CodeExternalNotImage — источник вернул вместо изображения что-то другое
+	CodeExternalNotImage = "EXTERNAL_NOT_IMAGE"
+
+	// CodeInvalidSeriesOrder — новый порядок серии содержит не все её фото или повторы
+	CodeInvalidSeriesOrder = "INVALID_SERIES_ORDER"
+
+	CodeUserAlreadyExists  = "USER_ALREADY_EXISTS" // username или email заняты
+	CodeInvalidCredentials = "INVALID_CREDENTIALS" // неверный email или пароль
+	CodePhotoLocked        = "PHOTO_LOCKED"        // фото обрабатывает другой запрос, можно повторить
+	CodeEmbeddingNotReady  = "EMBEDDING_NOT_READY" // эмбеддинг фото ещё не вычислен
+	CodeInvalidColor       = "INVALID_COLOR"       // цвет не в формате RRGGBB
+	CodeInvalidDateRange   = "INVALID_DATE_RANGE"  // from позже to
+	CodeUnsupportedImage   = "UNSUPPORTED_IMAGE"   // формат загруженного файла не поддерживается
+	CodeFileTooLarge       = "FILE_TOO_LARGE"      // загруженный файл больше предела
+	CodeFeatureUnavailable = "FEATURE_UNAVAILABLE" // возможность выключена в настройках сервера
+	CodeUploadNotReceived  = "UPLOAD_NOT_RECEIVED" // файл ещё не загружен по подписанной ссылке
+	CodeUploadMismatch     = "UPLOAD_MISMATCH"     // загруженный файл другого размера или типа, чем заявлен
+	CodeWebhookURLDenied   = "WEBHOOK_URL_DENIED"  // адрес вебхука не http(s) или во внутренней сети
+)
+
+// ErrorResponse — тело ответа с ошибкой
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+// respondWithError — отправляет JSON-ответ с ошибкой и общим кодом для HTTP-статуса.
+func respondWithError(w http.ResponseWriter, status int, message string, logger *slog.Logger) {
+	respondWithErrorCode(w, status, codeForStatus(status), message, logger)
+}
+
+// respondWithErrorCode — отправляет JSON-ответ с ошибкой и точным кодом из каталога выше.
+func respondWithErrorCode(w http.ResponseWriter, status int, code, message string, logger *slog.Logger) {
+	respondWithErrorDetails(w, status, code, message, nil, logger)
+}
+
+// respondWithErrorDetails — как respondWithErrorCode, но с подробностями в поле details.
+func respondWithErrorDetails(w http.ResponseWriter, status int, code, message string, details any, logger *slog.Logger) {
+	respondWithJSON(w, status, ErrorResponse{Code: code, Message: message, Details: details}, logger)
+}
+
+// codeForStatus возвращает общий код ошибки для HTTP-статуса
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMedia
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusBadGateway:
+		return CodeUpstreamError
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	default:
+		if status >= 500 {
+			return CodeInternal
+		}
+		return CodeBadRequest
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// errorsTestUseCase — PhotoUseCase, который на любой запрос фото возвращает err
+type errorsTestUseCase struct {
+	stubPhotoUseCase
+	err error
+}
+
+func (s *errorsTestUseCase) SearchAndSavePhotos(context.Context, string, int, int, string, string, string, int, int, string) (domain.PhotoPage, error) {
+	return domain.PhotoPage{}, s.err
+}
+
+func newErrorsTestRouter(err error) chi.Router {
+	uc := &errorsTestUseCase{err: err}
+	uc.getOrCreate = func(context.Context, string) (*domain.Photo, error) { return nil, uc.err }
+	uc.getDetails = func(context.Context, uuid.UUID) (*domain.Photo, error) { return nil, uc.err }
+	uc.searchInDB = func(_ context.Context, _ string, filter domain.PhotoSearchFilter) ([]domain.Photo, int64, error) {
+		if err := filter.Validate(); err != nil {
+			return nil, 0, fmt.Errorf("usecase: ошибка при поиске фото в БД: %w", err)
+		}
+		return nil, 0, uc.err
+	}
+
+	h := newTestPhotoHandler(uc)
+	r := chi.NewRouter()
+	r.Get("/photos/unsplash/{unsplashID}", h.GetOrCreatePhotoByUnsplashID)
+	r.Get("/photos/search", h.SearchAndSavePhotos)
+	r.Get("/photos/search/local", h.SearchPhotosInDB)
+	r.Get("/photos/{id}", h.GetPhotoDetailsFromDB)
+	return r
+}
+
+func TestErrorResponses_StatusAndCode(t *testing.T) {
+	rateLimited := &domain.ErrRateLimited{Source: domain.SourceUnsplash, ResetAt: time.Now().Add(time.Minute)}
+	photoPath := "/photos/" + uuid.NewString() + "?photo_id="
+
+	tests := []struct {
+		name   string
+		path   string
+		err    error // ответ usecase
+		status int
+		code   string
+	}{
+		{name: "external photo not found", path: "/photos/unsplash/abc",
+			err: fmt.Errorf("usecase: %w", domain.ErrExternalPhotoNotFound), status: http.StatusNotFound, code: CodeExternalPhotoNotFound},
+		{name: "photo deleted", path: "/photos/unsplash/abc",
+			err: fmt.Errorf("usecase: %w", domain.ErrPhotoNotFound), status: http.StatusNotFound, code: CodePhotoNotFound},
+		{name: "source rate limited", path: "/photos/unsplash/abc",
+			err: fmt.Errorf("usecase: %w", rateLimited), status: http.StatusTooManyRequests, code: CodeExternalRateLimited},
+		{name: "source returned not an image", path: "/photos/unsplash/abc",
+			err: fmt.Errorf("usecase: %w", usecase.ErrDownloadNotImage), status: http.StatusBadGateway, code: CodeExternalNotImage},
+		{name: "photo locked", path: "/photos/unsplash/abc",
+			err: fmt.Errorf("usecase: %w", lock.ErrNotAcquired), status: http.StatusServiceUnavailable, code: CodePhotoLocked},
+		{name: "unexpected error", path: "/photos/unsplash/abc",
+			err: errors.New("db is down"), status: http.StatusInternalServerError, code: CodeInternal},
+
+		{name: "search rate limited", path: "/photos/search?query=cats",
+			err: rateLimited, status: http.StatusTooManyRequests, code: CodeExternalRateLimited},
+		{name: "search without query", path: "/photos/search", status: http.StatusBadRequest, code: CodeValidation},
+		{name: "search per_page above maximum", path: "/photos/search?query=cats&per_page=101",
+			status: http.StatusBadRequest, code: CodeValidation},
+		{name: "search with sort", path: "/photos/search?query=cats&sort=views_count",
+			status: http.StatusBadRequest, code: CodeValidation},
+
+		{name: "local search bad page", path: "/photos/search/local?query=cats&page=abc",
+			status: http.StatusBadRequest, code: CodeValidation},
+		{name: "local search bad sort", path: "/photos/search/local?query=cats&sort=password",
+			status: http.StatusBadRequest, code: CodeValidation},
+		{name: "local search bad orientation", path: "/photos/search/local?query=cats&orientation=diagonal",
+			status: http.StatusBadRequest, code: CodeValidation},
+		{name: "local search empty range", path: "/photos/search/local?query=cats&from=2024-02-01&to=2024-01-01",
+			status: http.StatusBadRequest, code: CodeInvalidDateRange},
+
+		{name: "details missing photo", path: photoPath + uuid.NewString(),
+			err: fmt.Errorf("usecase: %w", domain.ErrPhotoNotFound), status: http.StatusNotFound, code: CodePhotoNotFound},
+		{name: "details bad photo_id", path: photoPath + "not-a-uuid", status: http.StatusBadRequest, code: CodeBadRequest},
+		{name: "details trash for non-admin", path: photoPath + uuid.NewString() + "&include_deleted=true",
+			status: http.StatusForbidden, code: CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newErrorsTestRouter(tt.err).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Code != tt.code || resp.Message == "" {
+				t.Errorf("code = %q (message %q), want %q with a message", resp.Code, resp.Message, tt.code)
+			}
+			if tt.status == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+				t.Error("429 without Retry-After")
+			}
+		})
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusBadRequest, CodeBadRequest},
+		{http.StatusUnauthorized, CodeUnauthorized},
+		{http.StatusForbidden, CodeForbidden},
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusConflict, CodeConflict},
+		{http.StatusRequestEntityTooLarge, CodePayloadTooLarge},
+		{http.StatusUnsupportedMediaType, CodeUnsupportedMedia},
+		{http.StatusUnprocessableEntity, CodeUnprocessable},
+		{http.StatusTooManyRequests, CodeRateLimited},
+		{http.StatusInternalServerError, CodeInternal},
+		{http.StatusBadGateway, CodeUpstreamError},
+		{http.StatusServiceUnavailable, CodeServiceUnavailable},
+		{http.StatusGatewayTimeout, CodeTimeout},
+		// статусы без своего кода получают общий код своего класса
+		{http.StatusNotImplemented, CodeInternal},
+		{http.StatusMethodNotAllowed, CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			if got := codeForStatus(tt.status); got != tt.want {
+				t.Errorf("codeForStatus(%d) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/go-chi/chi/v5"
+)
+
+// etagCacheControl — сколько клиент может не перепроверять ответ
+const etagCacheControl = "max-age=60"
+
+// ETagMiddleware выставляет ETag для /photos/{id} и /photos/recent и отвечает 304 Not Modified,
+// если If-None-Match совпал. ETag считается по updated_at и счётчикам фото, поэтому тело ответа
+// при совпадении даже не собирается. Подключается к конкретным маршрутам через r.With, когда шаблон маршрута уже известен.
+// На остальных маршрутах, а также если ETag посчитать не удалось, запрос просто передаётся дальше.
+// maxPerPage должен совпадать с пределом per_page у обработчика, иначе ETag посчитается не по той странице
+func ETagMiddleware(storage ports.PhotoStorage, maxPerPage int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var (
+				etag string
+				ok   bool
+			)
+			switch rctx.RoutePattern() {
+			case "/photos/recent":
+				etag, ok = recentPhotosETag(r, storage, maxPerPage)
+			case "/photos/{id}":
+				etag, ok = photoDetailsETag(r, storage)
+			}
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Cache-Control", etagCacheControl)
+			// ответ карточки зависит от токена (liked_by_me)
+			w.Header().Add("Vary", "Authorization")
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// photoDetailsETag — ETag одного фото: SHA-256 от его updated_at, счётчиков и локальных лайков.
+// Лайки и счётчики не меняют updated_at, но есть в ответе (liked_by_me — свой у каждого пользователя)
+func photoDetailsETag(r *http.Request, storage ports.PhotoStorage) (string, bool) {
+	id, _, err := photoIDFromRequest(r)
+	if err != nil {
+		return "", false
+	}
+	photo, err := storage.GetPhotoByIDFromDB(r.Context(), id)
+	if err != nil || photo == nil {
+		return "", false
+	}
+	userID, _ := UserIDFromContext(r.Context())
+	likes, err := storage.GetPhotoLikes(r.Context(), id, userID)
+	if err != nil {
+		return "", false
+	}
+	return hashETag(strconv.FormatInt(photo.UpdatedAt.UnixNano(), 10) + "/" + photoCountersETagPart(*photo) + "/" +
+		strconv.FormatInt(likes.Count, 10) + "/" + userID.String() + "/" + strconv.FormatBool(likes.LikedByMe)), true
+}
+
+// recentPhotosETag — ETag страницы последних фото: SHA-256 от самого свежего updated_at на странице.
+// ID фото и общее количество тоже входят в хеш: удаление фото (в том числе с другой страницы)
+// должно менять ETag, ведь в ответе есть total. Счётчики каждого фото входят в хеш по той же причине,
+// что и в photoDetailsETag
+func recentPhotosETag(r *http.Request, storage ports.PhotoStorage, maxPerPage int) (string, bool) {
+	page, perPage, err := parsePagination(r.URL.Query(), maxPerPage)
+	if err != nil {
+		return "", false
+	}
+	sort, err := domain.ParsePhotoSort(sortParams(r.URL.Query()))
+	if err != nil {
+		return "", false
+	}
+	photos, err := storage.ListPhotosInDB(r.Context(), page, perPage, sort)
+	if err != nil {
+		return "", false
+	}
+	total, err := storage.CountPhotosInDB(r.Context())
+	if err != nil {
+		return "", false
+	}
+
+	var latest time.Time
+	var b strings.Builder
+	for _, photo := range photos {
+		if photo.UpdatedAt.After(latest) {
+			latest = photo.UpdatedAt
+		}
+		b.WriteString(photo.ID.String())
+		b.WriteString(photoCountersETagPart(photo))
+	}
+	return hashETag(strconv.FormatInt(latest.UnixNano(), 10) + "/" + strconv.FormatInt(total, 10) + "/" + b.String()), true
+}
+
+// photoCountersETagPart — счётчики фото для ETag. Просмотры из буфера (AddViews) и пересчёт
+// популярности не меняют updated_at, а прямые IncrementViewsCount и IncrementDownloadsCount меняют,
+// поэтому ETag не может полагаться только на updated_at
+func photoCountersETagPart(photo domain.Photo) string {
+	return strconv.FormatInt(photo.ViewsCount, 10) + ":" +
+		strconv.FormatInt(photo.DownloadsCount, 10) + ":" +
+		strconv.FormatFloat(photo.PopularityScore, 'g', -1, 64)
+}
+
+func hashETag(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// etagMatches проверяет If-None-Match: список через запятую, "*" или слабые W/"..." значения
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
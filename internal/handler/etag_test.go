@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// sortRecordingStorage запоминает сортировку, с которой запрошена страница последних фото
+type sortRecordingStorage struct {
+	ports.PhotoStorage
+	sorts []domain.PhotoSort
+}
+
+func (s *sortRecordingStorage) ListPhotosInDB(_ context.Context, _, _ int, sort domain.PhotoSort) ([]domain.Photo, error) {
+	s.sorts = append(s.sorts, sort)
+	return nil, nil
+}
+
+func (s *sortRecordingStorage) CountPhotosInDB(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestETagMiddleware_RecentPhotosSortAliases(t *testing.T) {
+	want := domain.PhotoSort{Field: domain.SortByViews, Desc: false}
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "sort and order", query: "?sort=views_count&order=asc"},
+		{name: "sort_by and sort_order", query: "?sort_by=views_count&sort_order=asc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &sortRecordingStorage{}
+			r := chi.NewRouter()
+			r.With(ETagMiddleware(storage, 100)).Get("/photos/recent", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/photos/recent"+tt.query, nil))
+
+			if rec.Header().Get("ETag") == "" {
+				t.Fatal("ETag header is missing")
+			}
+			if len(storage.sorts) != 1 || storage.sorts[0] != want {
+				t.Errorf("ETag computed with sorts %v, want [%v]", storage.sorts, want)
+			}
+		})
+	}
+}
+
+// detailsStorage отдаёт одно фото и его лайки для подсчёта ETag карточки
+type detailsStorage struct {
+	ports.PhotoStorage
+	photo domain.Photo
+	likes int64
+}
+
+func (s *detailsStorage) GetPhotoByIDFromDB(_ context.Context, id uuid.UUID) (*domain.Photo, error) {
+	if id != s.photo.ID {
+		return nil, nil
+	}
+	photo := s.photo
+	return &photo, nil
+}
+
+func (s *detailsStorage) GetPhotoLikes(context.Context, uuid.UUID, uuid.UUID) (domain.PhotoLikes, error) {
+	return domain.PhotoLikes{Count: s.likes}, nil
+}
+
+func TestETagMiddleware_PhotoDetails(t *testing.T) {
+	storage := &detailsStorage{photo: domain.Photo{ID: uuid.New(), Title: "photo", UpdatedAt: time.Now()}}
+	uc := &stubPhotoUseCase{
+		getDetails: func(context.Context, uuid.UUID) (*domain.Photo, error) {
+			photo := storage.photo
+			return &photo, nil
+		},
+	}
+	h := newTestPhotoHandler(uc)
+	r := chi.NewRouter()
+	r.With(ETagMiddleware(storage, 100)).Get("/photos/{id}", h.GetPhotoDetailsFromDB)
+	path := "/photos/" + storage.photo.ID.String() + "?photo_id=" + storage.photo.ID.String()
+
+	get := func(t *testing.T, ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get(t, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Body.Len() == 0 {
+		t.Fatalf("first GET = %d, ETag %q, %d bytes; want 200 with ETag and body", first.Code, etag, first.Body.Len())
+	}
+
+	matches := []struct {
+		name        string
+		ifNoneMatch string
+	}{
+		{name: "exact", ifNoneMatch: etag},
+		{name: "weak", ifNoneMatch: "W/" + etag},
+		{name: "in list", ifNoneMatch: `"other", ` + etag},
+	}
+	for _, tt := range matches {
+		t.Run(tt.name, func(t *testing.T) {
+			uc.views = nil
+			rec := get(t, tt.ifNoneMatch)
+			if rec.Code != http.StatusNotModified {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotModified)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("304 body = %q, want empty", rec.Body)
+			}
+			if rec.Header().Get("ETag") != etag {
+				t.Errorf("304 ETag = %q, want %q", rec.Header().Get("ETag"), etag)
+			}
+			if n := uc.views[storage.photo.ID]; n != 0 {
+				t.Errorf("views = %d, want 0 for a 304", n)
+			}
+		})
+	}
+
+	changes := []struct {
+		name   string
+		change func()
+	}{
+		{name: "photo updated", change: func() { storage.photo.UpdatedAt = storage.photo.UpdatedAt.Add(time.Second) }},
+		{name: "photo liked", change: func() { storage.likes++ }},
+		{name: "buffered views flushed", change: func() { storage.photo.ViewsCount += 3 }},
+		{name: "photo downloaded", change: func() { storage.photo.DownloadsCount++ }},
+		{name: "popularity recomputed", change: func() { storage.photo.PopularityScore += 0.5 }},
+	}
+	for _, tt := range changes {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+			rec := get(t, etag)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d after the change", rec.Code, http.StatusOK)
+			}
+			newETag := rec.Header().Get("ETag")
+			if newETag == "" || newETag == etag {
+				t.Errorf("ETag = %q, want a new one", newETag)
+			}
+			etag = newETag
+		})
+	}
+}
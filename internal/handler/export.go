@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// exportFlushEvery — через сколько строк CSV отправляется клиенту, не дожидаясь конца выгрузки
+const exportFlushEvery = 100
+
+// exportDateLayout — формат параметров from и to
+const exportDateLayout = "2006-01-02"
+
+// exportCSVHeader — колонки CSV-выгрузки
+var exportCSVHeader = []string{"id", "unsplash_id", "title", "author_name", "width", "height", "likes_count",
+	"views_count", "downloads_count", "uploaded_at", "s3_url", "tags"}
+
+// ExportPhotos — выгружает метаданные фото в CSV
+// (GET /photos/export?format=csv&query=cats&from=2024-01-01&to=2024-12-31).
+// query отбирает фото так же, как поиск по бд, from и to — даты загрузки включительно.
+// Строки пишутся в ответ по мере чтения из бд, теги перечисляются через точку с запятой
+func (h *PhotoHandler) ExportPhotos(w http.ResponseWriter, r *http.Request) {
+	req := ExportPhotosRequest{Format: "csv"}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	var filter domain.PhotoFilter
+	filter.Query = req.Query
+	if req.From != "" {
+		from, _ := time.Parse(exportDateLayout, req.From) // формат уже проверен валидатором
+		filter.From = &from
+	}
+	if req.To != "" {
+		// to включительно: берём всё до начала следующего дня
+		to, _ := time.Parse(exportDateLayout, req.To)
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	h.log(r.Context()).Info("exporting photos",
+		"endpoint", "ExportPhotos",
+		"format", req.Format,
+		"query", req.Query,
+		"from", req.From,
+		"to", req.To,
+	)
+
+	photos, err := h.photoUseCase.ExportPhotos(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidDateRange) {
+			respondWithErrorCode(w, http.StatusBadRequest, CodeInvalidDateRange, "Дата from не может быть позже to", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to export photos", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка выгрузки фото", h.logger)
+		return
+	}
+
+	filename := fmt.Sprintf("photos_%s.csv", time.Now().UTC().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	rc := http.NewResponseController(w)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		h.log(r.Context()).Error("failed to write csv header", "error", err)
+		return
+	}
+
+	rows := 0
+	for photo := range photos {
+		if err := cw.Write(photoCSVRecord(photo)); err != nil {
+			// клиент ушёл: контекст запроса отменится, и выгрузка из бд остановится
+			h.log(r.Context()).Warn("failed to write csv row", "rows_written", rows, "error", err)
+			return
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			cw.Flush()
+			if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				h.log(r.Context()).Warn("failed to flush csv export", "rows_written", rows, "error", err)
+				return
+			}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		h.log(r.Context()).Warn("failed to finish csv export", "rows_written", rows, "error", err)
+		return
+	}
+
+	h.log(r.Context()).Info("photos exported", "rows", rows, "filename", filename)
+}
+
+// photoCSVRecord — строка CSV-выгрузки в порядке exportCSVHeader
+func photoCSVRecord(photo domain.Photo) []string {
+	tags := make([]string, 0, len(photo.Tags))
+	for _, tag := range photo.Tags {
+		tags = append(tags, tag.Name)
+	}
+	return []string{
+		photo.ID.String(),
+		photo.UnsplashID,
+		photo.Title,
+		photo.AuthorName,
+		strconv.Itoa(photo.Width),
+		strconv.Itoa(photo.Height),
+		strconv.Itoa(photo.LikesCount),
+		strconv.FormatInt(photo.ViewsCount, 10),
+		strconv.FormatInt(photo.DownloadsCount, 10),
+		photo.UploadedAt.UTC().Format(time.RFC3339),
+		photo.S3URL,
+		strings.Join(tags, ";"),
+	}
+}
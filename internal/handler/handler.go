@@ -1,14 +1,27 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
 	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
@@ -17,6 +30,11 @@ type PhotoHandler struct {
 	photoUseCase         usecase.PhotoUseCase
 	photoSearchPublisher ports.PhotoSearchPublisher
 	uploadLimiter        chan struct{}
+	maxUploadBytes       int64
+	uploadIntentTTL      time.Duration // срок подписанной ссылки для загрузки напрямую в S3
+	colorMatchDistance   float64       // порог совпадения цвета для поиска ?color=RRGGBB
+	maxPerPage           int           // предел per_page для списков
+	validator            *validation.Validator
 	logger               *slog.Logger
 }
 
@@ -25,12 +43,22 @@ func NewPhotoHandler(
 	uc usecase.PhotoUseCase,
 	publisher ports.PhotoSearchPublisher,
 	limiter chan struct{},
+	maxUploadBytes int64,
+	uploadIntentTTL time.Duration,
+	colorMatchDistance float64,
+	maxPerPage int,
+	validator *validation.Validator,
 	logger *slog.Logger,
 ) *PhotoHandler {
 	return &PhotoHandler{
 		photoUseCase:         uc,
 		photoSearchPublisher: publisher,
 		uploadLimiter:        limiter,
+		maxUploadBytes:       maxUploadBytes,
+		uploadIntentTTL:      uploadIntentTTL,
+		colorMatchDistance:   colorMatchDistance,
+		maxPerPage:           maxPerPage,
+		validator:            validator,
 		logger:               logger,
 	}
 }
@@ -51,125 +79,825 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}, logge
 	}
 }
 
-// respondWithError — отправляет JSON-ответ с ошибкой.
-func respondWithError(w http.ResponseWriter, code int, message string, logger *slog.Logger) {
-	respondWithJSON(w, code, map[string]string{"error": message}, logger)
+// respondIfRateLimited отвечает 429 с Retry-After, если err вызвана исчерпанным лимитом внешнего API.
+// Возвращает false, если err другая и ответ ещё не отправлен
+func respondIfRateLimited(w http.ResponseWriter, err error, logger *slog.Logger) bool {
+	var rateLimited *domain.ErrRateLimited
+	if !errors.As(err, &rateLimited) {
+		return false
+	}
+	retryAfter := int(math.Ceil(rateLimited.RetryAfter().Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	respondWithErrorDetails(w, http.StatusTooManyRequests, CodeExternalRateLimited, "Превышен лимит запросов к внешнему API, повторите позже",
+		map[string]string{"source": rateLimited.Source}, logger)
+	return true
+}
+
+// externalNotFoundDetails — details ответа 404, когда фото нет во внешнем источнике
+type externalNotFoundDetails struct {
+	Source     string `json:"source"`
+	UnsplashID string `json:"unsplash_id"`
+}
+
+// respondIfExternalNotFound отвечает 404 с ID фото, если err означает, что фото нет в Unsplash.
+// Возвращает false, если err другая и ответ ещё не отправлен
+func respondIfExternalNotFound(w http.ResponseWriter, err error, unsplashID string, logger *slog.Logger) bool {
+	if !errors.Is(err, domain.ErrExternalPhotoNotFound) {
+		return false
+	}
+	respondWithErrorDetails(w, http.StatusNotFound, CodeExternalPhotoNotFound, "Фото не найдено в Unsplash", externalNotFoundDetails{
+		Source:     domain.SourceUnsplash,
+		UnsplashID: unsplashID,
+	}, logger)
+	return true
 }
 
 // GetOrCreatePhotoByUnsplashID — получает фото по unsplash_id или создаёт новое.
 func (h *PhotoHandler) GetOrCreatePhotoByUnsplashID(w http.ResponseWriter, r *http.Request) {
-	unsplashID := r.URL.Query().Get("unsplash_id")
-	if unsplashID == "" {
-		h.logger.Warn("missing required parameter", "param", "unsplash_id")
-		respondWithError(w, http.StatusBadRequest, "Не указан unsplash_id", h.logger)
+	req := GetPhotoRequest{UnsplashID: chi.URLParam(r, "unsplashID")}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
 		return
 	}
+	unsplashID := req.UnsplashID
 
-	h.logger.Info("processing request", "endpoint", "GetOrCreatePhotoByUnsplashID", "unsplash_id", unsplashID)
+	h.log(r.Context()).Info("processing request", "endpoint", "GetOrCreatePhotoByUnsplashID", "unsplash_id", unsplashID)
 
 	photo, err := h.photoUseCase.GetOrCreatePhotoByUnsplashID(r.Context(), unsplashID)
 	if err != nil {
-		h.logger.Error("failed to get or create photo", "unsplash_id", unsplashID, "error", err)
+		if respondIfExternalNotFound(w, err, unsplashID, h.logger) {
+			h.log(r.Context()).Warn("photo not found in source", "unsplash_id", unsplashID)
+			return
+		}
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			h.log(r.Context()).Warn("photo is deleted", "unsplash_id", unsplashID)
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото удалено", h.logger)
+			return
+		}
+		if respondIfRateLimited(w, err, h.logger) {
+			h.log(r.Context()).Warn("photo source rate limit exceeded", "unsplash_id", unsplashID, "error", err)
+			return
+		}
+		if errors.Is(err, usecase.ErrDownloadNotImage) {
+			h.log(r.Context()).Warn("photo source returned a non-image file", "unsplash_id", unsplashID, "error", err)
+			respondWithErrorCode(w, http.StatusBadGateway, CodeExternalNotImage, "Источник фото вернул не изображение, повторите позже", h.logger)
+			return
+		}
+		if errors.Is(err, lock.ErrNotAcquired) {
+			h.log(r.Context()).Warn("photo is being processed by another instance", "unsplash_id", unsplashID)
+			w.Header().Set("Retry-After", "1")
+			respondWithErrorCode(w, http.StatusServiceUnavailable, CodePhotoLocked, "Фото сейчас обрабатывается, повторите позже", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to get or create photo", "unsplash_id", unsplashID, "error", err)
 		respondWithError(w, http.StatusInternalServerError, "Ошибка при получении или создании фото", h.logger)
 		return
 	}
 
-	h.logger.Info("photo processed successfully", "unsplash_id", unsplashID)
+	h.log(r.Context()).Info("photo processed successfully", "unsplash_id", unsplashID)
 	respondWithJSON(w, http.StatusOK, photo, h.logger)
 }
 
+// hexColorPattern — цвет для поиска по палитре: RRGGBB, решётка необязательна
+var hexColorPattern = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+
 // SearchAndSavePhotos — выполняет поиск фото и сохраняет их.
+// С ?color=RRGGBB вместо этого ищет среди сохранённых фото по палитре (см. searchPhotosByColor).
+// Параметров sort и sort_by здесь нет: страницу выдачи формирует внешний источник, а локальные счётчики
+// (просмотры, популярность) у найденных фото ещё не накоплены, так что сортировка по ним переставляла бы
+// только одну страницу чужой выдачи. Порядок задаёт order_by (latest или relevant); отсортировать
+// сохранённые фото можно в /photos/search/local. Переданный sort отклоняется с 400, а не игнорируется
 func (h *PhotoHandler) SearchAndSavePhotos(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("query")
-	if query == "" {
-		h.logger.Warn("missing required parameter", "param", "query")
-		respondWithError(w, http.StatusBadRequest, "Не указан параметр запроса", h.logger)
+	if color := r.URL.Query().Get("color"); hexColorPattern.MatchString(color) {
+		h.searchPhotosByColor(w, r, color)
 		return
 	}
-
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
+	if field, _ := sortParams(r.URL.Query()); field != "" {
+		h.log(r.Context()).Warn("sort requested for external search", "sort", field)
+		respondWithErrorCode(w, http.StatusBadRequest, CodeValidation,
+			"Поиск во внешнем источнике не поддерживает sort: используйте order_by=latest|relevant или /photos/search/local", h.logger)
+		return
 	}
-	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
-	if perPage <= 0 {
-		perPage = 10
+
+	req := SearchPhotosRequest{Page: 1, PerPage: defaultPerPage}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
 	}
+	query, page, perPage := req.Query, req.Page, req.PerPage
+	orientation, color, orderBy, minWidth, minHeight := req.Orientation, req.Color, req.OrderBy, req.MinWidth, req.MinHeight
 
-	h.logger.Info("searching and saving photos",
+	h.log(r.Context()).Info("searching and saving photos",
 		"endpoint", "SearchAndSavePhotos",
 		"query", query,
 		"page", page,
 		"per_page", perPage,
+		"orientation", orientation,
+		"color", color,
+		"order_by", orderBy,
+		"min_width", minWidth,
+		"min_height", minHeight,
 	)
 
-	_, err := h.photoUseCase.SearchAndSavePhotos(r.Context(), query, page, perPage)
+	result, err := h.photoUseCase.SearchAndSavePhotos(r.Context(), query, page, perPage, orientation, color, orderBy, minWidth, minHeight, domain.SearchSourceServer)
 	if err != nil {
-		h.logger.Error("failed to search and save photos", "query", query, "error", err)
+		if respondIfRateLimited(w, err, h.logger) {
+			h.log(r.Context()).Warn("photo source rate limit exceeded", "query", query, "error", err)
+			return
+		}
+		h.log(r.Context()).Error("failed to search and save photos", "query", query, "error", err)
 		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Ошибка поиска фото: %v", err), h.logger)
 		return
 	}
 
-	h.logger.Info("photos search and save completed", "query", query, "page", page)
-	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Фотографии успешно сохранены"}, h.logger)
+	h.log(r.Context()).Info("photos search and save completed",
+		"query", query,
+		"page", page,
+		"saved", len(result.Photos),
+		"total", result.Total,
+		"total_pages", result.TotalPages,
+		"sources", result.Sources,
+	)
+	photos := result.Photos
+	if photos == nil {
+		photos = []domain.Photo{}
+	}
+	respondWithJSON(w, http.StatusOK, ExternalSearchResponse{
+		Message:    "Фотографии успешно сохранены",
+		Data:       photos,
+		Page:       page,
+		PerPage:    perPage,
+		Total:      result.Total,
+		TotalPages: result.TotalPages,
+		HasNext:    page < result.TotalPages,
+		Sources:    result.Sources,
+	}, h.logger)
+}
+
+// searchPhotosByColor — ищет сохранённые фото, в палитре которых есть цвет,
+// близкий к color (не дальше colorMatchDistance в RGB); самые близкие первыми.
+func (h *PhotoHandler) searchPhotosByColor(w http.ResponseWriter, r *http.Request, color string) {
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+
+	h.log(r.Context()).Info("searching photos by color",
+		"endpoint", "SearchAndSavePhotos",
+		"color", color,
+		"max_distance", h.colorMatchDistance,
+		"page", page,
+		"per_page", perPage,
+	)
+
+	photos, total, err := h.photoUseCase.SearchPhotosByColor(r.Context(), color, h.colorMatchDistance, page, perPage)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidColor) {
+			respondWithErrorCode(w, http.StatusBadRequest, CodeInvalidColor, "Некорректный color", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to search photos by color", "color", color, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка поиска фото по цвету", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("photos color search completed", "color", color, "count", len(photos), "total", total)
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
 }
 
 // GetRecentPhotosFromDB — получает последние фото из БД.
 func (h *PhotoHandler) GetRecentPhotosFromDB(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
 	}
-	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
-	if perPage <= 0 {
-		perPage = 10
+	sort, ok := h.sortFromRequest(w, r)
+	if !ok {
+		return
 	}
 
-	h.logger.Info("fetching recent photos",
+	includeTags, _ := strconv.ParseBool(r.URL.Query().Get("include_tags"))
+
+	h.log(r.Context()).Info("fetching recent photos",
 		"endpoint", "GetRecentPhotosFromDB",
 		"page", page,
 		"per_page", perPage,
+		"include_tags", includeTags,
+		"sort", sort.Field,
+		"desc", sort.Desc,
 	)
 
-	photos, err := h.photoUseCase.GetRecentPhotosFromDB(r.Context(), page, perPage)
+	photos, total, err := h.photoUseCase.GetRecentPhotosFromDB(r.Context(), page, perPage, includeTags, sort)
 	if err != nil {
-		h.logger.Error("failed to fetch recent photos", "error", err)
+		h.log(r.Context()).Error("failed to fetch recent photos", "error", err)
 		respondWithError(w, http.StatusInternalServerError, "Ошибка получения последних фото", h.logger)
 		return
 	}
 
-	h.logger.Info("recent photos fetched successfully", "count", len(photos))
-	respondWithJSON(w, http.StatusOK, photos, h.logger)
+	h.log(r.Context()).Info("recent photos fetched successfully", "count", len(photos), "total", total)
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
 }
 
-// GetPhotoDetailsFromDB — получает детальную информацию о фото.
+// GetTrendingPhotos — самые популярные фото по popularity_score (GET /photos/trending?limit=20).
+// Оценка пересчитывается воркером периодически, поэтому новые просмотры и лайки учитываются с задержкой
+func (h *PhotoHandler) GetTrendingPhotos(w http.ResponseWriter, r *http.Request) {
+	req := TrendingPhotosRequest{Limit: 20}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	photos, err := h.photoUseCase.GetTrendingPhotos(r.Context(), req.Limit)
+	if err != nil {
+		h.log(r.Context()).Error("failed to fetch trending photos", "limit", req.Limit, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения популярных фото", h.logger)
+		return
+	}
+	if photos == nil {
+		photos = []domain.Photo{}
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string][]domain.Photo{"photos": photos}, h.logger)
+}
+
+// FindPhotosNear — фото, снятые рядом с точкой, ближайшие первыми
+// (GET /photos/nearby?lat=48.8&lon=2.3&radius=50&page=1&per_page=10, radius в км, по умолчанию 50).
+// Фото без координат в выдачу не попадают
+func (h *PhotoHandler) FindPhotosNear(w http.ResponseWriter, r *http.Request) {
+	req := NearbyPhotosRequest{Radius: 50}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+	lat, lon := *req.Lat, *req.Lon
+
+	h.log(r.Context()).Info("searching photos near point",
+		"endpoint", "FindPhotosNear",
+		"lat", lat,
+		"lon", lon,
+		"radius_km", req.Radius,
+		"page", page,
+		"per_page", perPage,
+	)
+
+	photos, total, err := h.photoUseCase.FindPhotosNear(r.Context(), lat, lon, req.Radius, page, perPage)
+	if err != nil {
+		h.log(r.Context()).Error("failed to find photos near point", "lat", lat, "lon", lon, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка поиска фото рядом с точкой", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("photos near point found", "count", len(photos), "total", total)
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// AutocompletePhotos — подсказки для строки поиска (GET /photos/autocomplete?q=<префикс>&limit=10).
+func (h *PhotoHandler) AutocompletePhotos(w http.ResponseWriter, r *http.Request) {
+	req := AutocompleteRequest{Limit: 10}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+	prefix, limit := req.Prefix, req.Limit
+
+	suggestions, err := h.photoUseCase.AutocompletePhotos(r.Context(), prefix, limit)
+	if err != nil {
+		h.log(r.Context()).Error("failed to autocomplete", "prefix", prefix, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения подсказок", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string][]string{"suggestions": suggestions}, h.logger)
+}
+
+// SearchPhotosInDB — ищет среди уже сохранённых фото без обращения к Unsplash, самые релевантные первыми.
+// ?min_likes, ?min_width и ?min_height отсекают фото с меньшими значениями,
+// ?author, ?orientation и ?from/?to (даты загрузки включительно) сужают выдачу.
+// ?include_rank=true добавляет в ответ поле rank
+func (h *PhotoHandler) SearchPhotosInDB(w http.ResponseWriter, r *http.Request) {
+	var req LocalSearchRequest
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+	sort, ok := h.sortFromRequest(w, r)
+	if !ok {
+		return
+	}
+	query, includeRank := req.Query, req.IncludeRank
+	filter := domain.PhotoSearchFilter{MinLikes: req.MinLikes, MinWidth: req.MinWidth, MinHeight: req.MinHeight,
+		Author: req.Author, Orientation: req.Orientation}
+	if req.From != "" {
+		from, _ := time.Parse(exportDateLayout, req.From) // формат уже проверен валидатором
+		filter.From = &from
+	}
+	if req.To != "" {
+		// to включительно: берём всё до начала следующего дня
+		to, _ := time.Parse(exportDateLayout, req.To)
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	h.log(r.Context()).Info("searching photos in DB",
+		"endpoint", "SearchPhotosInDB",
+		"query", query,
+		"page", page,
+		"per_page", perPage,
+		"min_likes", filter.MinLikes,
+		"min_width", filter.MinWidth,
+		"min_height", filter.MinHeight,
+		"author", filter.Author,
+		"orientation", filter.Orientation,
+		"from", req.From,
+		"to", req.To,
+	)
+
+	photos, total, err := h.photoUseCase.SearchPhotosInDB(r.Context(), query, page, perPage, filter, sort)
+	if err != nil {
+		// неизвестная ориентация отсекается валидатором, остаётся только пустой период
+		if errors.Is(err, domain.ErrInvalidFilter) {
+			respondWithErrorCode(w, http.StatusBadRequest, CodeInvalidDateRange, "Дата from не может быть позже to", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to search photos in DB", "query", query, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка поиска фото", h.logger)
+		return
+	}
+	// Релевантность отдаём только по запросу (?include_rank=true)
+	if !includeRank {
+		for i := range photos {
+			photos[i].Rank = nil
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// GetPhotoDetailsFromDB — получает детальную информацию о фото вместе с локальными лайками и учитывает просмотр;
+// views_count в ответе — значение до этого просмотра. Токен необязателен: с ним в ответ добавляется liked_by_me
 func (h *PhotoHandler) GetPhotoDetailsFromDB(w http.ResponseWriter, r *http.Request) {
 	photoIDStr := r.URL.Query().Get("photo_id")
 	if photoIDStr == "" {
-		h.logger.Warn("missing required parameter", "param", "photo_id")
+		h.log(r.Context()).Warn("missing required parameter", "param", "photo_id")
 		respondWithError(w, http.StatusBadRequest, "Не указан photo_id", h.logger)
 		return
 	}
 
 	photoUUID, err := uuid.Parse(photoIDStr)
 	if err != nil {
-		h.logger.Error("invalid photo_id parameter", "photo_id", photoIDStr, "error", err)
+		h.log(r.Context()).Error("invalid photo_id parameter", "photo_id", photoIDStr, "error", err)
 		respondWithError(w, http.StatusBadRequest, "Некорректный photo_id", h.logger)
 		return
 	}
 
-	h.logger.Info("fetching photo details",
+	// include_deleted=true показывает и фото из корзины, но только администраторам
+	includeDeleted, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted"))
+	if includeDeleted && !isAdmin(r.Context()) {
+		h.log(r.Context()).Warn("include_deleted requested by non-admin", "photo_id", photoUUID)
+		respondWithError(w, http.StatusForbidden, "Фото из корзины доступны только администраторам", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("fetching photo details",
 		"endpoint", "GetPhotoDetailsFromDB",
 		"photo_id", photoUUID,
+		"include_deleted", includeDeleted,
 	)
 
+	var photo *domain.Photo
+	if includeDeleted {
+		photo, err = h.photoUseCase.GetPhotoDetailsIncludingDeleted(r.Context(), photoUUID)
+	} else {
+		photo, err = h.photoUseCase.GetPhotoDetailsFromDB(r.Context(), photoUUID)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			h.log(r.Context()).Warn("photo not found", "photo_id", photoUUID)
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to fetch photo details", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения информации о фото", h.logger)
+		return
+	}
+
+	// просмотр засчитывается только при показе фото клиенту; администратор, смотрящий корзину, его не делает
+	if !includeDeleted {
+		h.photoUseCase.CountView(r.Context(), photo.ID)
+	}
+	h.attachLikes(r, photo)
+
+	h.log(r.Context()).Info("photo details fetched successfully", "photo_id", photoUUID)
+	respondWithJSON(w, http.StatusOK, photo, h.logger)
+}
+
+// sortFromRequest разбирает параметры sort и order (?sort=likes_count&order=desc).
+// sort_by и sort_order — их синонимы; если переданы оба имени, побеждают sort и order.
+// Для поля не из белого списка отвечает 400 и возвращает false
+func (h *PhotoHandler) sortFromRequest(w http.ResponseWriter, r *http.Request) (domain.PhotoSort, bool) {
+	field, order := sortParams(r.URL.Query())
+	sort, err := domain.ParsePhotoSort(field, order)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid sort parameters", "sort", field, "order", order, "error", err)
+		respondWithErrorCode(w, http.StatusBadRequest, CodeValidation, fmt.Sprintf("Некорректная сортировка: допустимо sort=%s и order=asc|desc",
+			strings.Join(domain.PhotoSortFields, "|")), h.logger)
+		return domain.PhotoSort{}, false
+	}
+	return sort, true
+}
+
+// sortParams возвращает поле и направление сортировки из query с учётом синонимов sort_by и sort_order.
+// Общая для sortFromRequest и ETagMiddleware, чтобы ETag считался по той же странице, что и ответ
+func sortParams(query url.Values) (field, order string) {
+	field, order = query.Get("sort"), query.Get("order")
+	if field == "" {
+		field = query.Get("sort_by")
+	}
+	if order == "" {
+		order = query.Get("sort_order")
+	}
+	return field, order
+}
+
+// photoIDFromRequest достаёт ID фото из пути (/photos/{id}/...) или, если его нет, из параметра photo_id
+func photoIDFromRequest(r *http.Request) (uuid.UUID, string, error) {
+	raw := chi.URLParam(r, "id")
+	if raw == "" {
+		raw = r.URL.Query().Get("photo_id")
+	}
+	id, err := uuid.Parse(raw)
+	return id, raw, err
+}
+
+// GetPhotoAttribution — возвращает подпись к фото в виде обычного текста для копирования.
+func (h *PhotoHandler) GetPhotoAttribution(w http.ResponseWriter, r *http.Request) {
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
 	photo, err := h.photoUseCase.GetPhotoDetailsFromDB(r.Context(), photoUUID)
 	if err != nil {
-		h.logger.Error("failed to fetch photo details", "photo_id", photoUUID, "error", err)
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to fetch photo for attribution", "photo_id", photoUUID, "error", err)
 		respondWithError(w, http.StatusInternalServerError, "Ошибка получения информации о фото", h.logger)
 		return
 	}
 
-	h.logger.Info("photo details fetched successfully", "photo_id", photoUUID)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(domain.GenerateAttribution(photo))); err != nil {
+		h.log(r.Context()).Error("failed to write HTTP response", "error", err)
+	}
+}
+
+// GetSimilarPhotos — фото, похожие на данное по эмбеддингу изображения, самые похожие первыми
+// (GET /photos/{id}/similar?limit=10). Эмбеддинг вычисляется воркером после сохранения фото,
+// до этого отвечает 409
+func (h *PhotoHandler) GetSimilarPhotos(w http.ResponseWriter, r *http.Request) {
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+	req := SimilarPhotosRequest{Limit: 10}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	photos, err := h.photoUseCase.FindSimilarPhotos(r.Context(), photoUUID, req.Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPhotoNotFound):
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+		case errors.Is(err, domain.ErrEmbeddingNotReady):
+			respondWithErrorCode(w, http.StatusConflict, CodeEmbeddingNotReady, "Фото ещё обрабатывается, похожие фото пока недоступны", h.logger)
+		default:
+			h.log(r.Context()).Error("failed to find similar photos", "photo_id", photoUUID, "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Ошибка поиска похожих фото", h.logger)
+		}
+		return
+	}
+	if photos == nil {
+		photos = []domain.Photo{}
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string][]domain.Photo{"photos": photos}, h.logger)
+}
+
+// DeletePhoto — перемещает фото в корзину.
+func (h *PhotoHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	if err := h.photoUseCase.SoftDeletePhoto(r.Context(), photoUUID); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to delete photo", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка удаления фото", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("photo moved to trash", "photo_id", photoUUID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListPhotosByAuthor — фото автора (GET /authors/{name}/photos), имя без учёта регистра.
+func (h *PhotoHandler) ListPhotosByAuthor(w http.ResponseWriter, r *http.Request) {
+	authorName := strings.TrimSpace(chi.URLParam(r, "name"))
+	if authorName == "" {
+		respondWithError(w, http.StatusBadRequest, "Не указано имя автора", h.logger)
+		return
+	}
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+
+	photos, total, err := h.photoUseCase.ListPhotosByAuthor(r.Context(), authorName, page, perPage)
+	if err != nil {
+		h.log(r.Context()).Error("failed to list author photos", "author_name", authorName, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения фото автора", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("author photos fetched successfully", "author_name", authorName, "count", len(photos), "total", total)
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// ListPhotosByUser — фото, сохранённые пользователем (GET /users/{id}/photos).
+func (h *PhotoHandler) ListPhotosByUser(w http.ResponseWriter, r *http.Request) {
+	raw := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid user id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID пользователя", h.logger)
+		return
+	}
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+
+	photos, total, err := h.photoUseCase.ListPhotosByUser(r.Context(), userID, page, perPage)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodeUserNotFound, "Пользователь не найден", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to list user photos", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения фото пользователя", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("user photos fetched successfully", "user_id", userID, "count", len(photos), "total", total)
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// RefreshPhoto — заново запрашивает метаданные фото из Unsplash (лайки, просмотры, скачивания, описание).
+// С ?async=true задача ставится в очередь воркеру и сразу возвращается 202
+func (h *PhotoHandler) RefreshPhoto(w http.ResponseWriter, r *http.Request) {
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	photo, err := h.photoUseCase.GetPhotoDetailsFromDB(r.Context(), photoUUID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to fetch photo details", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения информации о фото", h.logger)
+		return
+	}
+	if photo.UnsplashID == "" {
+		h.log(r.Context()).Warn("refresh requested for non-unsplash photo", "photo_id", photoUUID, "source", photo.ExternalSource)
+		respondWithError(w, http.StatusUnprocessableEntity, "Фото загружено пользователем, обновлять нечего", h.logger)
+		return
+	}
+
+	if r.URL.Query().Get("async") == "true" {
+		err := h.photoSearchPublisher.PublishPhotoSearchRequest(r.Context(), payloads.PhotoSearchPayload{
+			Type:       payloads.TaskRefreshPhoto,
+			UnsplashID: photo.UnsplashID,
+		})
+		if err != nil {
+			h.log(r.Context()).Error("failed to publish refresh task", "photo_id", photoUUID, "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Ошибка постановки задачи в очередь", h.logger)
+			return
+		}
+		h.log(r.Context()).Info("photo refresh queued", "photo_id", photoUUID, "unsplash_id", photo.UnsplashID)
+		respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "Обновление фото поставлено в очередь"}, h.logger)
+		return
+	}
+
+	refreshed, err := h.photoUseCase.RefreshPhotoMetadata(r.Context(), photo.UnsplashID)
+	if err != nil {
+		if respondIfExternalNotFound(w, err, photo.UnsplashID, h.logger) {
+			h.log(r.Context()).Warn("photo not found in source", "photo_id", photoUUID, "unsplash_id", photo.UnsplashID)
+			return
+		}
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		if respondIfRateLimited(w, err, h.logger) {
+			h.log(r.Context()).Warn("photo source rate limit exceeded", "photo_id", photoUUID, "error", err)
+			return
+		}
+		h.log(r.Context()).Error("failed to refresh photo", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusBadGateway, "Ошибка обновления фото из Unsplash", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("photo refreshed", "photo_id", photoUUID, "unsplash_id", photo.UnsplashID)
+	respondWithJSON(w, http.StatusOK, refreshed, h.logger)
+}
+
+// RefreshPhotoStats — ставит в очередь обновление просмотров и скачиваний фото
+// из статистики Unsplash (POST /photos/{id}/refresh-stats) и сразу возвращает 202.
+func (h *PhotoHandler) RefreshPhotoStats(w http.ResponseWriter, r *http.Request) {
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	photo, err := h.photoUseCase.GetPhotoDetailsFromDB(r.Context(), photoUUID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to fetch photo details", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения информации о фото", h.logger)
+		return
+	}
+	if photo.ExternalSource != domain.SourceUnsplash {
+		h.log(r.Context()).Warn("stats refresh requested for non-unsplash photo", "photo_id", photoUUID, "source", photo.ExternalSource)
+		respondWithError(w, http.StatusUnprocessableEntity, "Статистика есть только у фото из Unsplash", h.logger)
+		return
+	}
+
+	err = h.photoSearchPublisher.PublishPhotoSearchRequest(r.Context(), payloads.NewStatsRefreshTask(payloads.PhotoStatsRefreshPayload{
+		UnsplashID: photo.UnsplashID,
+	}))
+	if err != nil {
+		h.log(r.Context()).Error("failed to publish stats refresh task", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка постановки задачи в очередь", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("photo stats refresh queued", "photo_id", photoUUID, "unsplash_id", photo.UnsplashID)
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "Обновление статистики фото поставлено в очередь"}, h.logger)
+}
+
+// RestorePhoto — возвращает фото из корзины.
+func (h *PhotoHandler) RestorePhoto(w http.ResponseWriter, r *http.Request) {
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	photo, err := h.photoUseCase.RestorePhoto(r.Context(), photoUUID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено в корзине", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to restore photo", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка восстановления фото", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("photo restored", "photo_id", photoUUID)
 	respondWithJSON(w, http.StatusOK, photo, h.logger)
 }
+
+// RecordView — учитывает просмотр фото (POST /photos/{id}/view).
+func (h *PhotoHandler) RecordView(w http.ResponseWriter, r *http.Request) {
+	h.recordEngagement(w, r, "view", h.photoUseCase.RecordView)
+}
+
+// RecordDownload — учитывает скачивание фото (POST /photos/{id}/download).
+func (h *PhotoHandler) RecordDownload(w http.ResponseWriter, r *http.Request) {
+	h.recordEngagement(w, r, "download", h.photoUseCase.RecordDownload)
+}
+
+// recordEngagement разбирает ID фото и вызывает record; отвечает 204, а для неизвестного фото — 404
+func (h *PhotoHandler) recordEngagement(w http.ResponseWriter, r *http.Request, kind string, record func(context.Context, uuid.UUID) error) {
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	if err := record(r.Context(), photoUUID); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to record photo "+kind, "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка обновления счётчика фото", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Debug("photo "+kind+" recorded", "photo_id", photoUUID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeletedPhotos — получает фото из корзины. Маршрут только для администраторов (см. AdminOnly).
+func (h *PhotoHandler) ListDeletedPhotos(w http.ResponseWriter, r *http.Request) {
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+
+	h.log(r.Context()).Info("fetching deleted photos", "endpoint", "ListDeletedPhotos", "page", page, "per_page", perPage)
+
+	photos, total, err := h.photoUseCase.ListDeletedPhotos(r.Context(), page, perPage)
+	if err != nil {
+		h.log(r.Context()).Error("failed to fetch deleted photos", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения фото из корзины", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("deleted photos fetched successfully", "count", len(photos), "total", total)
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// SearchHistory — получает историю поисков во внешнем источнике, новые первыми.
+func (h *PhotoHandler) SearchHistory(w http.ResponseWriter, r *http.Request) {
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+
+	h.log(r.Context()).Info("fetching search history", "endpoint", "SearchHistory", "page", page, "per_page", perPage)
+
+	queries, total, err := h.photoUseCase.ListSearchHistory(r.Context(), page, perPage)
+	if err != nil {
+		h.log(r.Context()).Error("failed to fetch search history", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения истории поиска", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(queries, page, perPage, total), h.logger)
+}
+
+// SuggestSearches — подсказки по истории поиска: самые частые прошлые запросы,
+// начинающиеся с ?prefix. ?limit — от 1 до usecase.MaxSearchSuggestLimit, по умолчанию 10
+func (h *PhotoHandler) SuggestSearches(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.TrimSpace(r.URL.Query().Get("prefix"))
+	if prefix == "" {
+		respondWithError(w, http.StatusBadRequest, "Не указан параметр prefix", h.logger)
+		return
+	}
+
+	limit := 10
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > usecase.MaxSearchSuggestLimit {
+			respondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("Некорректный limit: допустимо от 1 до %d", usecase.MaxSearchSuggestLimit), h.logger)
+			return
+		}
+		limit = parsed
+	}
+
+	suggestions, err := h.photoUseCase.SuggestSearchQueries(r.Context(), prefix, limit)
+	if err != nil {
+		h.log(r.Context()).Error("failed to suggest searches", "prefix", prefix, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения подсказок", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string][]domain.SearchSuggestion{"suggestions": suggestions}, h.logger)
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (h *PhotoHandler) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
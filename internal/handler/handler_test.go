@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// stubPhotoUseCase подменяет нужные тесту методы PhotoUseCase; вызов остальных паникует
+type stubPhotoUseCase struct {
+	usecase.PhotoUseCase
+	getOrCreate func(ctx context.Context, unsplashID string) (*domain.Photo, error)
+	getDetails  func(ctx context.Context, id uuid.UUID) (*domain.Photo, error)
+	restore     func(ctx context.Context, id uuid.UUID) (*domain.Photo, error)
+	complete    func(ctx context.Context, userID, id uuid.UUID) (*domain.Photo, error)
+	batchTag    func(ctx context.Context, ids []uuid.UUID, add, remove []string) (usecase.BatchTagResult, error)
+	searchInDB  func(ctx context.Context, query string, filter domain.PhotoSearchFilter) ([]domain.Photo, int64, error)
+	views       map[uuid.UUID]int // просмотры, учтённые через CountView
+}
+
+func (s *stubPhotoUseCase) RestorePhoto(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	return s.restore(ctx, id)
+}
+
+func (s *stubPhotoUseCase) CompleteUpload(ctx context.Context, userID, id uuid.UUID) (*domain.Photo, error) {
+	return s.complete(ctx, userID, id)
+}
+
+func (s *stubPhotoUseCase) BatchUpdateTags(ctx context.Context, ids []uuid.UUID, add, remove []string) (usecase.BatchTagResult, error) {
+	return s.batchTag(ctx, ids, add, remove)
+}
+
+func (s *stubPhotoUseCase) SearchPhotosInDB(ctx context.Context, query string, _, _ int, filter domain.PhotoSearchFilter, _ domain.PhotoSort) ([]domain.Photo, int64, error) {
+	return s.searchInDB(ctx, query, filter)
+}
+
+func (s *stubPhotoUseCase) GetOrCreatePhotoByUnsplashID(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+	return s.getOrCreate(ctx, unsplashID)
+}
+
+func (s *stubPhotoUseCase) GetPhotoDetailsFromDB(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	return s.getDetails(ctx, id)
+}
+
+func (s *stubPhotoUseCase) CountView(_ context.Context, id uuid.UUID) {
+	if s.views == nil {
+		s.views = make(map[uuid.UUID]int)
+	}
+	s.views[id]++
+}
+
+func (s *stubPhotoUseCase) GetPhotoLikes(context.Context, uuid.UUID, uuid.UUID) (domain.PhotoLikes, error) {
+	return domain.PhotoLikes{}, nil
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestPhotoHandler(uc usecase.PhotoUseCase) *PhotoHandler {
+	return NewPhotoHandler(uc, nil, make(chan struct{}, 1), 10<<20, 0, 0, 100, validation.New(), discardLogger())
+}
+
+func TestGetOrCreatePhotoByUnsplashID_NotFound(t *testing.T) {
+	var gotID string
+	h := newTestPhotoHandler(&stubPhotoUseCase{
+		getOrCreate: func(_ context.Context, unsplashID string) (*domain.Photo, error) {
+			gotID = unsplashID
+			return nil, domain.ErrExternalPhotoNotFound
+		},
+	})
+	r := chi.NewRouter()
+	r.Get("/photos/unsplash/{unsplashID}", h.GetOrCreatePhotoByUnsplashID)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/photos/unsplash/abc123", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusNotFound, rec.Body)
+	}
+	if gotID != "abc123" {
+		t.Errorf("usecase got unsplash ID %q, want %q", gotID, "abc123")
+	}
+	var resp struct {
+		Code    string                  `json:"code"`
+		Details externalNotFoundDetails `json:"details"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Code != CodeExternalPhotoNotFound {
+		t.Errorf("code = %q, want %q", resp.Code, CodeExternalPhotoNotFound)
+	}
+	if resp.Details.UnsplashID != "abc123" {
+		t.Errorf("details.unsplash_id = %q, want %q", resp.Details.UnsplashID, "abc123")
+	}
+}
+
+func TestGetOrCreatePhotoByUnsplashID_Deleted(t *testing.T) {
+	h := newTestPhotoHandler(&stubPhotoUseCase{
+		getOrCreate: func(context.Context, string) (*domain.Photo, error) {
+			return nil, domain.ErrPhotoNotFound
+		},
+	})
+	r := chi.NewRouter()
+	r.Get("/photos/unsplash/{unsplashID}", h.GetOrCreatePhotoByUnsplashID)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/photos/unsplash/abc123", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusNotFound, rec.Body)
+	}
+}
+
+func TestPhotoViews_CountedOnlyWhenShownToClient(t *testing.T) {
+	photo := &domain.Photo{ID: uuid.New(), Title: "photo", AuthorName: "author"}
+	uc := &stubPhotoUseCase{
+		getDetails: func(context.Context, uuid.UUID) (*domain.Photo, error) {
+			p := *photo
+			return &p, nil
+		},
+	}
+	h := newTestPhotoHandler(uc)
+	r := chi.NewRouter()
+	r.Get("/photos/{id}", h.GetPhotoDetailsFromDB)
+	r.Get("/photos/{id}/attribution", h.GetPhotoAttribution)
+
+	tests := []struct {
+		name  string
+		path  string
+		views int
+	}{
+		{name: "attribution", path: "/photos/" + photo.ID.String() + "/attribution", views: 0},
+		{name: "details", path: "/photos/" + photo.ID.String() + "?photo_id=" + photo.ID.String(), views: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc.views = nil
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			if got := uc.views[photo.ID]; got != tt.views {
+				t.Errorf("views = %d, want %d", got, tt.views)
+			}
+		})
+	}
+}
+
+func TestSearchAndSavePhotos_RejectsSort(t *testing.T) {
+	h := newTestPhotoHandler(&stubPhotoUseCase{})
+	for _, query := range []string{"sort=views_count", "sort_by=popularity_score"} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.SearchAndSavePhotos(rec, httptest.NewRequest(http.MethodGet, "/photos/search?query=cats&"+query, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+		})
+	}
+}
+
+func TestSearchPhotosInDB_Filter(t *testing.T) {
+	var got domain.PhotoSearchFilter
+	h := newTestPhotoHandler(&stubPhotoUseCase{
+		searchInDB: func(_ context.Context, _ string, filter domain.PhotoSearchFilter) ([]domain.Photo, int64, error) {
+			got = filter
+			if err := filter.Validate(); err != nil {
+				return nil, 0, fmt.Errorf("usecase: ошибка при поиске фото в БД: %w", err)
+			}
+			return []domain.Photo{}, 0, nil
+		},
+	})
+	search := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.SearchPhotosInDB(rec, httptest.NewRequest(http.MethodGet, "/photos/search/local?query=cats&"+query, nil))
+		return rec
+	}
+
+	rec := search("author=Jane&orientation=portrait&min_likes=10&from=2024-01-01&to=2024-01-31")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if got.Author != "Jane" || got.Orientation != domain.OrientationPortrait || got.MinLikes != 10 {
+		t.Errorf("filter = %+v, want author Jane, portrait, min likes 10", got)
+	}
+	// to включительно: фото, загруженные 31 января, попадают в выдачу
+	if got.From == nil || got.To == nil || got.From.Format("2006-01-02") != "2024-01-01" || got.To.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("range = %v..%v, want 2024-01-01..2024-02-01", got.From, got.To)
+	}
+
+	for _, query := range []string{"from=2024-02-01&to=2024-01-01", "orientation=diagonal", "from=01.02.2024"} {
+		if rec := search(query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d; body: %s", query, rec.Code, http.StatusBadRequest, rec.Body)
+		}
+	}
+}
+
+func TestRestorePhoto_NotInTrash(t *testing.T) {
+	h := newTestPhotoHandler(&stubPhotoUseCase{
+		restore: func(_ context.Context, id uuid.UUID) (*domain.Photo, error) {
+			return nil, fmt.Errorf("usecase: ошибка при восстановлении фото %s: %w", id, domain.ErrPhotoNotFound)
+		},
+	})
+	r := chi.NewRouter()
+	r.Post("/photos/{id}/restore", h.RestorePhoto)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/photos/"+uuid.NewString()+"/restore", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusNotFound, rec.Body)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Code != CodePhotoNotFound {
+		t.Errorf("code = %q, want %q", resp.Code, CodePhotoNotFound)
+	}
+}
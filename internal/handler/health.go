@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+)
+
+// HealthHandler обрабатывает проверки состояния сервиса для оркестратора
+type HealthHandler struct {
+	schemaVersion ports.SchemaVersionReader // nil у SQLite
+	logger        *slog.Logger
+}
+
+// NewHealthHandler — конструктор для HealthHandler.
+func NewHealthHandler(schemaVersion ports.SchemaVersionReader, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{schemaVersion: schemaVersion, logger: logger}
+}
+
+// readinessResponse — ответ GET /readyz
+type readinessResponse struct {
+	Status        string `json:"status"`
+	SchemaVersion uint   `json:"schema_version,omitempty"`
+}
+
+// Ready — проверка готовности принимать трафик (GET /readyz): 503, если бд недоступна
+// или схема осталась в состоянии dirty после упавшей миграции.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.schemaVersion == nil {
+		respondWithJSON(w, http.StatusOK, readinessResponse{Status: "ok"}, h.logger)
+		return
+	}
+
+	version, dirty, err := h.schemaVersion.SchemaVersion(r.Context())
+	if err != nil {
+		logger.FromContext(r.Context(), h.logger).Warn("readiness check failed: database unavailable", "error", err)
+		respondWithJSON(w, http.StatusServiceUnavailable, readinessResponse{Status: "database_unavailable"}, h.logger)
+		return
+	}
+	if dirty {
+		logger.FromContext(r.Context(), h.logger).Warn("readiness check failed: schema is dirty", "version", version)
+		respondWithJSON(w, http.StatusServiceUnavailable, readinessResponse{Status: "schema_dirty", SchemaVersion: version}, h.logger)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, readinessResponse{Status: "ok", SchemaVersion: version}, h.logger)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+)
+
+const (
+	// IdempotencyKeyHeader — заголовок с ключом идемпотентности от клиента
+	IdempotencyKeyHeader = "X-Idempotency-Key"
+
+	// idempotentReplayHeader отмечает ответ, взятый из сохранённых, а не выполненный заново
+	idempotentReplayHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength ограничивает длину ключа от клиента
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyMiddleware делает запросы с заголовком X-Idempotency-Key идемпотентными:
+// первый запрос с ключом выполняется, и его успешный (2xx) ответ сохраняется на ttl,
+// а повтор с тем же ключом получает сохранённый ответ с исходным кодом без повторного выполнения.
+// Пока первый запрос не завершён, повтор получает 409. Неуспешный ответ не сохраняется,
+// и запрос с тем же ключом можно повторить; то же после паники обработчика. Ключи разных пользователей и маршрутов не пересекаются,
+// поэтому middleware ставится после JWTAuth. Запросы без заголовка проходят как обычно
+func IdempotencyMiddleware(storage ports.IdempotencyStorage, ttl time.Duration, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientKey := r.Header.Get(IdempotencyKeyHeader)
+			if clientKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(clientKey) > maxIdempotencyKeyLength {
+				respondWithError(w, http.StatusBadRequest, "Слишком длинный X-Idempotency-Key", log)
+				return
+			}
+			key := idempotencyStorageKey(r, clientKey)
+
+			record, err := storage.ReserveIdempotencyKey(r.Context(), key, time.Now().Add(-ttl))
+			if err != nil {
+				logger.FromContext(r.Context(), log).Error("failed to check idempotency key", "error", err)
+				respondWithError(w, http.StatusInternalServerError, "Ошибка проверки ключа идемпотентности", log)
+				return
+			}
+			if record != nil {
+				if record.InProgress() {
+					logger.FromContext(r.Context(), log).Warn("idempotent request is still in progress", "path", r.URL.Path)
+					respondWithError(w, http.StatusConflict, "Запрос с этим X-Idempotency-Key ещё выполняется", log)
+					return
+				}
+				logger.FromContext(r.Context(), log).Info("replaying idempotent response", "path", r.URL.Path, "status", record.StatusCode)
+				w.Header().Set(idempotentReplayHeader, "true")
+				if len(record.Response) > 0 {
+					w.Header().Set("Content-Type", "application/json")
+				}
+				w.WriteHeader(record.StatusCode)
+				_, _ = w.Write(record.Response)
+				return
+			}
+
+			// запрос мог быть отменён клиентом, а запись ключа должна завершиться в любом случае
+			ctx := context.WithoutCancel(r.Context())
+			release := func() {
+				if err := storage.ReleaseIdempotencyKey(ctx, key); err != nil {
+					logger.FromContext(ctx, log).Error("failed to release idempotency key", "error", err)
+				}
+			}
+
+			rec := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			completed := false
+			defer func() {
+				if !completed {
+					// обработчик запаниковал: без освобождения ключа повторы получали бы 409 до истечения ttl.
+					// Паника не перехватывается и доходит до Recoverer
+					release()
+				}
+			}()
+			next.ServeHTTP(rec, r)
+			completed = true
+
+			if rec.statusCode >= 200 && rec.statusCode < 300 {
+				if err := storage.SaveIdempotentResponse(ctx, key, rec.statusCode, rec.body.Bytes()); err == nil {
+					return
+				}
+				// ответ не сохранился: освобождаем ключ, чтобы повтор не получал вечный 409
+			}
+			release()
+		})
+	}
+}
+
+// idempotencyStorageKey привязывает ключ клиента к пользователю и маршруту
+func idempotencyStorageKey(r *http.Request, clientKey string) string {
+	user := "anonymous"
+	if userID, ok := UserIDFromContext(r.Context()); ok {
+		user = userID.String()
+	}
+	return user + " " + r.Method + " " + r.URL.Path + " " + clientKey
+}
+
+// recordingResponseWriter передаёт ответ клиенту и заодно запоминает код и тело
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
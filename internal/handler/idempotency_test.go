@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// memIdempotencyStorage — IdempotencyStorage в памяти без истечения ключей
+type memIdempotencyStorage struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func (s *memIdempotencyStorage) ReserveIdempotencyKey(_ context.Context, key string, _ time.Time) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok {
+		return &record, nil
+	}
+	if s.records == nil {
+		s.records = make(map[string]domain.IdempotencyRecord)
+	}
+	s.records[key] = domain.IdempotencyRecord{Key: key, CreatedAt: time.Now()}
+	return nil, nil
+}
+
+func (s *memIdempotencyStorage) SaveIdempotentResponse(_ context.Context, key string, statusCode int, response []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[key]
+	record.StatusCode = statusCode
+	record.Response = response
+	s.records[key] = record
+	return nil
+}
+
+func (s *memIdempotencyStorage) ReleaseIdempotencyKey(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok && record.InProgress() {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+func (s *memIdempotencyStorage) DeleteExpiredIdempotencyKeys(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	storage := &memIdempotencyStorage{}
+	calls := 0
+	panics := true
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.With(IdempotencyMiddleware(storage, time.Hour, discardLogger())).
+		Post("/admin/authors/{username}/import", func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			if panics {
+				panic("import failed")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"imported":3}`))
+		})
+
+	post := func(t *testing.T) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/admin/authors/jane/import", nil)
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(t); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("panicking request = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	// после паники ключ свободен: повтор выполняется, а не получает 409
+	panics = false
+	first := post(t)
+	if first.Code != http.StatusAccepted || calls != 2 {
+		t.Fatalf("retry after panic = %d after %d calls, want %d after 2", first.Code, calls, http.StatusAccepted)
+	}
+
+	replay := post(t)
+	if replay.Code != http.StatusAccepted || replay.Body.String() != `{"imported":3}` {
+		t.Errorf("replay = %d %q, want %d with the saved body", replay.Code, replay.Body, http.StatusAccepted)
+	}
+	if replay.Header().Get(idempotentReplayHeader) != "true" {
+		t.Errorf("replay is missing the %s header", idempotentReplayHeader)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2: the replay must not run the import again", calls)
+	}
+}
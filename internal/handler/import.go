@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// batchImportFailure — фото, которое не удалось импортировать, с кодом ошибки из каталога
+type batchImportFailure struct {
+	UnsplashID string `json:"unsplash_id"`
+	Code       string `json:"code"`
+}
+
+// batchImportResponse — ответ POST /photos/batch-import
+type batchImportResponse struct {
+	Imported []domain.Photo       `json:"imported"`
+	Failed   []batchImportFailure `json:"failed"`
+}
+
+// BatchImportPhotos — сохраняет сразу несколько фото Unsplash по их ID (POST /photos/batch-import).
+// Фото импортируются по очереди, как в GET /photos/unsplash/{unsplashID}; уже сохранённые просто возвращаются.
+// Если импортировать удалось не все, ответ 207 со списком failed. После исчерпания лимита источника
+// или отмены запроса оставшиеся ID не запрашиваются и тоже попадают в failed
+func (h *PhotoHandler) BatchImportPhotos(w http.ResponseWriter, r *http.Request) {
+	var req BatchImportRequest
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	h.log(r.Context()).Info("processing request", "endpoint", "BatchImportPhotos", "count", len(req.UnsplashIDs))
+
+	resp := batchImportResponse{
+		Imported: make([]domain.Photo, 0, len(req.UnsplashIDs)),
+		Failed:   []batchImportFailure{},
+	}
+	stopCode := "" // код для оставшихся ID, когда продолжать импорт бессмысленно
+	for _, unsplashID := range req.UnsplashIDs {
+		if stopCode == "" && r.Context().Err() != nil {
+			stopCode = CodeTimeout
+		}
+		if stopCode != "" {
+			resp.Failed = append(resp.Failed, batchImportFailure{UnsplashID: unsplashID, Code: stopCode})
+			continue
+		}
+
+		photo, err := h.photoUseCase.GetOrCreatePhotoByUnsplashID(r.Context(), unsplashID)
+		if err != nil {
+			code := batchImportErrorCode(err)
+			if code == CodeInternal {
+				h.log(r.Context()).Error("failed to import photo", "unsplash_id", unsplashID, "error", err)
+			} else {
+				h.log(r.Context()).Warn("photo not imported", "unsplash_id", unsplashID, "code", code, "error", err)
+			}
+			if code == CodeExternalRateLimited {
+				stopCode = code
+			}
+			resp.Failed = append(resp.Failed, batchImportFailure{UnsplashID: unsplashID, Code: code})
+			continue
+		}
+		resp.Imported = append(resp.Imported, *photo)
+	}
+
+	h.log(r.Context()).Info("batch import completed", "imported", len(resp.Imported), "failed", len(resp.Failed))
+	status := http.StatusOK
+	if len(resp.Failed) > 0 {
+		status = http.StatusMultiStatus
+	}
+	respondWithJSON(w, status, resp, h.logger)
+}
+
+// batchImportErrorCode возвращает код ошибки импорта одного фото, как его вернул бы
+// GET /photos/unsplash/{unsplashID}
+func batchImportErrorCode(err error) string {
+	var rateLimited *domain.ErrRateLimited
+	switch {
+	case errors.Is(err, domain.ErrExternalPhotoNotFound):
+		return CodeExternalPhotoNotFound
+	case errors.Is(err, domain.ErrPhotoNotFound):
+		return CodePhotoNotFound
+	case errors.As(err, &rateLimited):
+		return CodeExternalRateLimited
+	case errors.Is(err, usecase.ErrDownloadNotImage):
+		return CodeExternalNotImage
+	case errors.Is(err, lock.ErrNotAcquired):
+		return CodePhotoLocked
+	default:
+		return CodeInternal
+	}
+}
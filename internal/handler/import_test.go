@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestBatchImportPhotos(t *testing.T) {
+	rateLimited := &domain.ErrRateLimited{Source: domain.SourceUnsplash, ResetAt: time.Now().Add(time.Minute)}
+	results := map[string]error{
+		"ok1":     nil,
+		"ok2":     nil,
+		"missing": fmt.Errorf("usecase: %w", domain.ErrExternalPhotoNotFound),
+		"trashed": fmt.Errorf("usecase: %w", domain.ErrPhotoNotFound),
+		"limited": fmt.Errorf("usecase: %w", rateLimited),
+	}
+
+	tests := []struct {
+		name     string
+		ids      []string
+		status   int
+		imported []string
+		failed   []batchImportFailure
+		calls    []string
+	}{
+		{name: "all imported", ids: []string{"ok1", "ok2"}, status: http.StatusOK,
+			imported: []string{"ok1", "ok2"}, failed: []batchImportFailure{}, calls: []string{"ok1", "ok2"}},
+		{name: "some failed", ids: []string{"ok1", "missing", "trashed"}, status: http.StatusMultiStatus,
+			imported: []string{"ok1"},
+			failed: []batchImportFailure{
+				{UnsplashID: "missing", Code: CodeExternalPhotoNotFound},
+				{UnsplashID: "trashed", Code: CodePhotoNotFound},
+			},
+			calls: []string{"ok1", "missing", "trashed"}},
+		// после исчерпания лимита источник больше не запрашивается
+		{name: "rate limited", ids: []string{"ok1", "limited", "ok2"}, status: http.StatusMultiStatus,
+			imported: []string{"ok1"},
+			failed: []batchImportFailure{
+				{UnsplashID: "limited", Code: CodeExternalRateLimited},
+				{UnsplashID: "ok2", Code: CodeExternalRateLimited},
+			},
+			calls: []string{"ok1", "limited"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			h := newTestPhotoHandler(&stubPhotoUseCase{
+				getOrCreate: func(_ context.Context, unsplashID string) (*domain.Photo, error) {
+					calls = append(calls, unsplashID)
+					if err := results[unsplashID]; err != nil {
+						return nil, err
+					}
+					return &domain.Photo{ID: uuid.New(), UnsplashID: unsplashID}, nil
+				},
+			})
+			body, _ := json.Marshal(BatchImportRequest{UnsplashIDs: tt.ids})
+			req := httptest.NewRequest(http.MethodPost, "/photos/batch-import", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.BatchImportPhotos(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+			var resp batchImportResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			var imported []string
+			for _, photo := range resp.Imported {
+				imported = append(imported, photo.UnsplashID)
+			}
+			if !slices.Equal(imported, tt.imported) || !slices.Equal(resp.Failed, tt.failed) {
+				t.Errorf("imported = %v, failed = %v; want %v, %v", imported, resp.Failed, tt.imported, tt.failed)
+			}
+			if !slices.Equal(calls, tt.calls) {
+				t.Errorf("usecase calls = %v, want %v", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestBatchImportPhotos_Validation(t *testing.T) {
+	tooMany := make([]string, 21)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("id%d", i)
+	}
+	h := newTestPhotoHandler(&stubPhotoUseCase{})
+	for name, body := range map[string]string{
+		"no ids":     `{"unsplash_ids": []}`,
+		"empty id":   `{"unsplash_ids": ["abc", ""]}`,
+		"duplicates": `{"unsplash_ids": ["abc", "abc"]}`,
+		"too many":   `{"unsplash_ids": ["` + strings.Join(tooMany, `","`) + `"]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/photos/batch-import", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.BatchImportPhotos(rec, req)
+			if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), CodeValidation) {
+				t.Errorf("status = %d, want %d with %s; body: %s", rec.Code, http.StatusBadRequest, CodeValidation, rec.Body)
+			}
+		})
+	}
+}
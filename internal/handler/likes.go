@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// LikePhoto — добавляет фото в избранное текущего пользователя (PUT /photos/{id}/like).
+// Повторный лайк ничего не меняет и тоже отвечает 204
+func (h *PhotoHandler) LikePhoto(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	if _, err := h.photoUseCase.LikePhoto(r.Context(), userID, photoUUID); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to like photo", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка добавления фото в избранное", h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlikePhoto — убирает фото из избранного текущего пользователя (DELETE /photos/{id}/like).
+func (h *PhotoHandler) UnlikePhoto(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+	photoUUID, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	if err := h.photoUseCase.UnlikePhoto(r.Context(), userID, photoUUID); err != nil {
+		h.log(r.Context()).Error("failed to unlike photo", "photo_id", photoUUID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка удаления фото из избранного", h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMyLikes — возвращает избранное текущего пользователя с пагинацией (GET /users/me/likes).
+func (h *PhotoHandler) ListMyLikes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+
+	photos, total, err := h.photoUseCase.ListLikedPhotos(r.Context(), userID, page, perPage)
+	if err != nil {
+		h.log(r.Context()).Error("failed to list liked photos", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения избранного", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// attachLikes дополняет карточку фото локальными лайками; liked_by_me — только для авторизованного запроса.
+// Ошибка только логируется: карточка отдаётся без этих полей
+func (h *PhotoHandler) attachLikes(r *http.Request, photo *domain.Photo) {
+	userID, authenticated := UserIDFromContext(r.Context())
+	likes, err := h.photoUseCase.GetPhotoLikes(r.Context(), photo.ID, userID)
+	if err != nil {
+		h.log(r.Context()).Warn("failed to get photo likes", "photo_id", photo.ID, "error", err)
+		return
+	}
+	photo.LocalLikesCount = &likes.Count
+	if authenticated {
+		photo.LikedByMe = &likes.LikedByMe
+	}
+}
@@ -1,13 +1,69 @@
 package handler
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/auth"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// RequestIDHeader — заголовок с ID запроса, по которому связываются все логи одного запроса
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает длину принятого от клиента ID, чтобы не тащить в логи что угодно
+const maxRequestIDLength = 128
+
+// RequestID — middleware, которое берёт ID запроса из X-Request-ID или генерирует новый,
+// кладёт его в контекст (см. logger.FromContext) и возвращает клиенту в том же заголовке.
+// Должно стоять первым, чтобы ID попал в логи всех остальных middleware
+func RequestID() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !ValidRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+
+			w.Header().Set(RequestIDHeader, requestID)
+			ctx := logger.WithRequestID(r.Context(), requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ValidRequestID допускает только непустые ID разумной длины из печатных ASCII-символов
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 // RequestLogger — middleware для логирования HTTP-запросов.
-func RequestLogger(logger *slog.Logger) func(next http.Handler) http.Handler {
+func RequestLogger(log *slog.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -17,7 +73,7 @@ func RequestLogger(logger *slog.Logger) func(next http.Handler) http.Handler {
 			next.ServeHTTP(ww, r)
 
 			duration := time.Since(start)
-			logger.Info("http request",
+			logger.FromContext(r.Context(), log).Info("http request",
 				"method", r.Method,
 				"path", r.URL.Path,
 				"status", ww.statusCode,
@@ -27,6 +83,286 @@ func RequestLogger(logger *slog.Logger) func(next http.Handler) http.Handler {
 	}
 }
 
+// MetricsMiddleware — middleware для учёта HTTP-запросов в Prometheus.
+// Маршрут берётся из шаблона chi (например, /photos/{id}), чтобы не плодить метки на каждый ID
+func MetricsMiddleware(m *metrics.Metrics) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(ww, r)
+
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			m.ObserveHTTPRequest(route, r.Method, ww.statusCode, time.Since(start))
+		})
+	}
+}
+
+// TracingMiddleware — middleware, открывающее серверный спан OpenTelemetry на каждый запрос.
+// Входящий контекст трассировки (traceparent) извлекается из заголовков
+func TracingMiddleware() func(next http.Handler) http.Handler {
+	tracer := tracing.Tracer("handler")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.method", r.Method),
+					attribute.String("http.target", r.URL.RequestURI()),
+				),
+			)
+			defer span.End()
+
+			ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+				span.SetName(r.Method + " " + rctx.RoutePattern())
+				span.SetAttributes(attribute.String("http.route", rctx.RoutePattern()))
+			}
+			span.SetAttributes(attribute.Int("http.status_code", ww.statusCode))
+			if ww.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(ww.statusCode))
+			}
+		})
+	}
+}
+
+// MaxBodySize — middleware, ограничивающее тело запроса maxBytes байтами. Запрос с большим Content-Length
+// отклоняется сразу, не читая тело. Тело без длины (chunked) оборачивается http.MaxBytesReader: если
+// обработчик упёрся в предел, вместо его ответа об ошибке (обычно 400 о некорректном теле) клиент
+// получает 413
+func MaxBodySize(maxBytes int64, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				logger.FromContext(r.Context(), log).Warn("request body too large",
+					"path", r.URL.Path, "content_length", r.ContentLength, "limit_bytes", maxBytes)
+				respondBodyTooLarge(w, maxBytes, log)
+				return
+			}
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes)}
+			r.Body = body
+			next.ServeHTTP(&bodyLimitWriter{ResponseWriter: w, body: body, limit: maxBytes, log: log, r: r}, r)
+		})
+	}
+}
+
+// respondBodyTooLarge отвечает 413 с пределом в details
+func respondBodyTooLarge(w http.ResponseWriter, maxBytes int64, log *slog.Logger) {
+	respondWithErrorDetails(w, http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "Тело запроса слишком большое",
+		map[string]int64{"limit_bytes": maxBytes}, log)
+}
+
+// limitedBody — тело запроса под http.MaxBytesReader, запоминающее, что предел превышен
+type limitedBody struct {
+	io.ReadCloser
+	exceeded atomic.Bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded.Store(true)
+	}
+	return n, err
+}
+
+// bodyLimitWriter подменяет ответ обработчика на 413, если тело запроса оказалось больше предела.
+// Ответ 413 самого обработчика (например, FILE_TOO_LARGE при загрузке) проходит как есть
+type bodyLimitWriter struct {
+	http.ResponseWriter
+	body        *limitedBody
+	limit       int64
+	log         *slog.Logger
+	r           *http.Request
+	wroteHeader bool
+	replaced    bool
+}
+
+func (bw *bodyLimitWriter) WriteHeader(code int) {
+	if bw.wroteHeader || bw.replaced {
+		return
+	}
+	if bw.body.exceeded.Load() && code != http.StatusRequestEntityTooLarge {
+		bw.replaced = true
+		logger.FromContext(bw.r.Context(), bw.log).Warn("request body too large",
+			"path", bw.r.URL.Path, "limit_bytes", bw.limit, "handler_status", code)
+		respondBodyTooLarge(bw.ResponseWriter, bw.limit, bw.log)
+		return
+	}
+	bw.wroteHeader = true
+	bw.ResponseWriter.WriteHeader(code)
+}
+
+func (bw *bodyLimitWriter) Write(b []byte) (int, error) {
+	if !bw.wroteHeader && !bw.replaced {
+		bw.WriteHeader(http.StatusOK)
+	}
+	if bw.replaced {
+		// тело ответа обработчика отбрасывается: клиент уже получил 413
+		return len(b), nil
+	}
+	return bw.ResponseWriter.Write(b)
+}
+
+// Unwrap даёт http.ResponseController добраться до исходного ResponseWriter
+func (bw *bodyLimitWriter) Unwrap() http.ResponseWriter {
+	return bw.ResponseWriter
+}
+
+// ctxKey — тип ключей контекста, чтобы не пересекаться с другими пакетами
+type ctxKey string
+
+const userIDKey ctxKey = "user_id"
+
+// JWTAuth — middleware, требующее валидный JWT в заголовке Authorization: Bearer <token>.
+// ID пользователя из токена кладётся в контекст запроса
+func JWTAuth(tokens *auth.TokenManager, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", log)
+				return
+			}
+
+			userID, err := tokens.Parse(token)
+			if err != nil {
+				logger.FromContext(r.Context(), log).Warn("invalid JWT", "error", err)
+				respondWithError(w, http.StatusUnauthorized, "Недействительный токен", log)
+				return
+			}
+
+			setAuditUser(r.Context(), userID)
+			ctx := context.WithValue(r.Context(), userIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalJWTAuth — как JWTAuth, но для публичных маршрутов: запрос без токена
+// или с недействительным токеном проходит дальше как анонимный
+func OptionalJWTAuth(tokens *auth.TokenManager, log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := tokens.Parse(token)
+			if err != nil {
+				logger.FromContext(r.Context(), log).Debug("ignoring invalid JWT on public route", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setAuditUser(r.Context(), userID)
+			ctx := context.WithValue(r.Context(), userIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// APIKeyHeader — заголовок с API-ключом; ключ также принимается как Authorization: Bearer <key>
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth — middleware для дорогих и изменяющих данные маршрутов: пропускает запрос,
+// только если в X-API-Key или Authorization: Bearer передан один из keys.
+// Несколько ключей позволяют менять ключ без простоя. Пустой keys отключает проверку
+func APIKeyAuth(keys []string, log *slog.Logger) func(next http.Handler) http.Handler {
+	// ключи сравниваются по хешу: так сравнение занимает одно и то же время при любой длине ключа
+	hashes := make([][32]byte, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			hashes = append(hashes, sha256.Sum256([]byte(key)))
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		if len(hashes) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apiKeyFromRequest(r)
+			if key == "" {
+				respondWithError(w, http.StatusUnauthorized, "Требуется API-ключ", log)
+				return
+			}
+
+			sum := sha256.Sum256([]byte(key))
+			valid := 0
+			for _, h := range hashes {
+				valid |= subtle.ConstantTimeCompare(sum[:], h[:])
+			}
+			if valid != 1 {
+				logger.FromContext(r.Context(), log).Warn("invalid API key", "path", r.URL.Path)
+				respondWithError(w, http.StatusUnauthorized, "Недействительный API-ключ", log)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AdminAPIKeyHeader — заголовок с ключом служебных маршрутов мониторинга
+const AdminAPIKeyHeader = "X-Admin-API-Key"
+
+// AdminAPIKeyAuth — middleware для служебных маршрутов мониторинга: пропускает запрос,
+// только если в X-Admin-API-Key передан key. В отличие от APIKeyAuth пустой key
+// не отключает проверку, а закрывает маршруты: мониторинг не должен оказаться открытым по ошибке
+func AdminAPIKeyAuth(key string, log *slog.Logger) func(next http.Handler) http.Handler {
+	key = strings.TrimSpace(key)
+	want := sha256.Sum256([]byte(key))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				respondWithErrorCode(w, http.StatusForbidden, CodeFeatureUnavailable, "Служебный API отключён: не задан ADMIN_API_KEY", log)
+				return
+			}
+			got := r.Header.Get(AdminAPIKeyHeader)
+			if got == "" {
+				respondWithError(w, http.StatusUnauthorized, "Требуется ключ служебного API", log)
+				return
+			}
+			sum := sha256.Sum256([]byte(got))
+			if subtle.ConstantTimeCompare(sum[:], want[:]) != 1 {
+				logger.FromContext(r.Context(), log).Warn("invalid admin API key", "path", r.URL.Path)
+				respondWithError(w, http.StatusUnauthorized, "Недействительный ключ служебного API", log)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiKeyFromRequest достаёт API-ключ из X-API-Key или Authorization: Bearer
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	key, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return key
+}
+
+// UserIDFromContext возвращает ID аутентифицированного пользователя из контекста
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDKey).(uuid.UUID)
+	return userID, ok
+}
+
 // responseWriter нужен, чтобы перехватывать код ответа
 type responseWriter struct {
 	http.ResponseWriter
@@ -37,3 +373,8 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap даёт http.ResponseController добраться до исходного ResponseWriter (например, для Flush)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
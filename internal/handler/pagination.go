@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/GoArmGo/MediaApp/internal/logger"
+)
+
+// defaultPerPage — размер страницы, если per_page не указан
+const defaultPerPage = 10
+
+// parsePagination разбирает page и per_page. Отсутствующие, нулевые и отрицательные значения
+// заменяются на 1 и defaultPerPage, per_page больше maxPerPage урезается до maxPerPage
+// (maxPerPage <= 0 — без ограничения). Ошибка — только если значение передано, но это не число
+func parsePagination(query url.Values, maxPerPage int) (page, perPage int, err error) {
+	page, err = parsePaginationParam(query, "page", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	perPage, err = parsePaginationParam(query, "per_page", defaultPerPage)
+	if err != nil {
+		return 0, 0, err
+	}
+	if maxPerPage > 0 && perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	return page, perPage, nil
+}
+
+// parsePaginationParam читает положительное целое name или возвращает def
+func parsePaginationParam(query url.Values, name string, def int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("некорректный %s %q: ожидается целое число", name, raw)
+	}
+	if v <= 0 {
+		return def, nil
+	}
+	return v, nil
+}
+
+// paginationFromRequest читает page и per_page из запроса (см. parsePagination).
+// Для нечислового значения отвечает 400 и возвращает false
+func paginationFromRequest(w http.ResponseWriter, r *http.Request, maxPerPage int, log *slog.Logger) (page, perPage int, ok bool) {
+	page, perPage, err := parsePagination(r.URL.Query(), maxPerPage)
+	if err != nil {
+		logger.FromContext(r.Context(), log).Warn("invalid pagination parameters",
+			"page", r.URL.Query().Get("page"),
+			"per_page", r.URL.Query().Get("per_page"),
+			"error", err,
+		)
+		respondWithErrorCode(w, http.StatusBadRequest, CodeValidation, "Некорректная пагинация: page и per_page должны быть целыми числами", log)
+		return 0, 0, false
+	}
+	return page, perPage, true
+}
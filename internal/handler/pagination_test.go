@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParsePagination(t *testing.T) {
+	const maxPerPage = 100
+	tests := []struct {
+		name        string
+		query       string
+		maxPerPage  int
+		wantPage    int
+		wantPerPage int
+		wantErr     bool
+	}{
+		{name: "defaults", query: "", maxPerPage: maxPerPage, wantPage: 1, wantPerPage: defaultPerPage},
+		{name: "explicit values", query: "page=3&per_page=25", maxPerPage: maxPerPage, wantPage: 3, wantPerPage: 25},
+		{name: "zero page", query: "page=0", maxPerPage: maxPerPage, wantPage: 1, wantPerPage: defaultPerPage},
+		{name: "negative page", query: "page=-2", maxPerPage: maxPerPage, wantPage: 1, wantPerPage: defaultPerPage},
+		{name: "zero per_page", query: "per_page=0", maxPerPage: maxPerPage, wantPage: 1, wantPerPage: defaultPerPage},
+		{name: "negative per_page", query: "per_page=-5", maxPerPage: maxPerPage, wantPage: 1, wantPerPage: defaultPerPage},
+		{name: "per_page at the cap", query: "per_page=100", maxPerPage: maxPerPage, wantPage: 1, wantPerPage: 100},
+		{name: "per_page over the cap", query: "per_page=1000000", maxPerPage: maxPerPage, wantPage: 1, wantPerPage: maxPerPage},
+		{name: "no cap", query: "per_page=1000", maxPerPage: 0, wantPage: 1, wantPerPage: 1000},
+		{name: "non-numeric page", query: "page=abc", maxPerPage: maxPerPage, wantErr: true},
+		{name: "non-numeric per_page", query: "per_page=ten", maxPerPage: maxPerPage, wantErr: true},
+		{name: "fractional page", query: "page=1.5", maxPerPage: maxPerPage, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parse query: %v", err)
+			}
+			page, perPage, err := parsePagination(query, tt.maxPerPage)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePagination(%q) = %d, %d, want an error", tt.query, page, perPage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePagination(%q): %v", tt.query, err)
+			}
+			if page != tt.wantPage || perPage != tt.wantPerPage {
+				t.Errorf("parsePagination(%q) = %d, %d, want %d, %d", tt.query, page, perPage, tt.wantPage, tt.wantPerPage)
+			}
+		})
+	}
+}
+
+func TestPaginationFromRequest(t *testing.T) {
+	tests := []struct {
+		query  string
+		ok     bool
+		status int
+	}{
+		{query: "page=2&per_page=500", ok: true, status: http.StatusOK},
+		{query: "page=two", ok: false, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			page, perPage, ok := paginationFromRequest(rec, httptest.NewRequest(http.MethodGet, "/photos?"+tt.query, nil), 100, discardLogger())
+			if ok != tt.ok || rec.Code != tt.status {
+				t.Fatalf("ok = %v, status = %d; want %v, %d", ok, rec.Code, tt.ok, tt.status)
+			}
+			if ok && (page != 2 || perPage != 100) {
+				t.Errorf("page, per_page = %d, %d; want 2, 100", page, perPage)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter ограничивает частоту запросов каждого клиента отдельным token bucket:
+// rps запросов в секунду в среднем и до burst подряд.
+// Клиент — это API-ключ из списка разрешённых, а без него — IP-адрес соединения.
+// Корзины клиентов, не приходивших дольше idleTTL, удаляются, чтобы карта не росла бесконечно
+type RateLimiter struct {
+	rps     rate.Limit
+	burst   int
+	idleTTL time.Duration
+	apiKeys map[[32]byte]bool
+
+	mu        sync.Mutex
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+
+	logger *slog.Logger
+}
+
+// clientBucket — корзина токенов одного клиента
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter создаёт ограничитель. apiKeys — ключи, по которым клиент учитывается
+// отдельно от своего IP; неизвестный ключ игнорируется, чтобы им нельзя было обойти лимит
+func NewRateLimiter(rps float64, burst int, idleTTL time.Duration, apiKeys []string, logger *slog.Logger) *RateLimiter {
+	keys := make(map[[32]byte]bool, len(apiKeys))
+	for _, key := range apiKeys {
+		if key != "" {
+			keys[sha256.Sum256([]byte(key))] = true
+		}
+	}
+	return &RateLimiter{
+		rps:       rate.Limit(rps),
+		burst:     max(burst, 1),
+		idleTTL:   idleTTL,
+		apiKeys:   keys,
+		clients:   make(map[string]*clientBucket),
+		lastSweep: time.Now(),
+		logger:    logger,
+	}
+}
+
+// Middleware отвечает 429 с Retry-After, если клиент исчерпал свою корзину.
+// Нулевой rps отключает ограничение
+func (l *RateLimiter) Middleware() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l.rps <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := l.clientKey(r)
+			reservation := l.bucket(client, time.Now()).Reserve()
+			if delay := reservation.Delay(); delay > 0 {
+				// запрос не выполняется, поэтому токен возвращаем в корзину
+				reservation.Cancel()
+				logger.FromContext(r.Context(), l.logger).Warn("rate limit exceeded",
+					"client", client,
+					"path", r.URL.Path,
+					"retry_after", delay,
+				)
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				respondWithError(w, http.StatusTooManyRequests, "Слишком много запросов, повторите позже", l.logger)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bucket возвращает корзину клиента, создавая её при первом запросе,
+// и заодно не чаще раза в idleTTL удаляет корзины простаивающих клиентов
+func (l *RateLimiter) bucket(client string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.idleTTL > 0 && now.Sub(l.lastSweep) >= l.idleTTL {
+		for key, b := range l.clients {
+			if now.Sub(b.lastSeen) >= l.idleTTL {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &clientBucket{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.clients[client] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// clientKey определяет клиента: известный API-ключ (в логах — только префикс его хеша) или IP
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if key := apiKeyFromRequest(r); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		if l.apiKeys[sum] {
+			return "key:" + hex.EncodeToString(sum[:4])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	const burst = 5
+	// один токен в минуту: за время теста корзина не пополнится
+	limiter := NewRateLimiter(1.0/60, burst, time.Hour, nil, discardLogger())
+	h := limiter.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	get := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/photos/recent", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := range burst {
+		if rec := get("203.0.113.1:1234"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d = %d, want %d within the burst", i+1, rec.Code, http.StatusOK)
+		}
+	}
+
+	// другой порт того же IP — тот же клиент
+	rec := get("203.0.113.1:5678")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request %d = %d, want %d", burst+1, rec.Code, http.StatusTooManyRequests)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Retry-After = %q, want 1..60 seconds", rec.Header().Get("Retry-After"))
+	}
+
+	if rec := get("198.51.100.7:1234"); rec.Code != http.StatusOK {
+		t.Errorf("second IP = %d, want %d: it has its own bucket", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRateLimiter_EvictsIdleBuckets(t *testing.T) {
+	const idleTTL = time.Minute
+	limiter := NewRateLimiter(1, 1, idleTTL, nil, discardLogger())
+	start := limiter.lastSweep
+
+	limiter.bucket("ip:203.0.113.1", start)
+	limiter.bucket("ip:198.51.100.7", start.Add(idleTTL/2))
+
+	// первый клиент простаивает idleTTL, второй — только половину
+	limiter.bucket("ip:192.0.2.9", start.Add(idleTTL))
+
+	if _, ok := limiter.clients["ip:203.0.113.1"]; ok {
+		t.Error("idle bucket was not evicted")
+	}
+	if _, ok := limiter.clients["ip:198.51.100.7"]; !ok {
+		t.Error("recently used bucket was evicted")
+	}
+	if len(limiter.clients) != 2 {
+		t.Errorf("buckets = %d, want 2", len(limiter.clients))
+	}
+}
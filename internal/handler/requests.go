@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+)
+
+// Структуры query-параметров эндпоинтов. Заполняются и проверяются validation.Validator:
+// тег query — имя параметра, validate — правила. Значения по умолчанию задаются до вызова validateRequest
+
+// GetPhotoRequest — параметры GET /photos/unsplash/{unsplashID}. UnsplashID берётся из пути
+type GetPhotoRequest struct {
+	UnsplashID string `path:"unsplashID" validate:"required,max=64"`
+}
+
+// SearchPhotosRequest — параметры GET /photos/search. Сортировки по локальным полям нет,
+// порядок выдачи источника задаёт OrderBy (см. PhotoHandler.SearchAndSavePhotos)
+type SearchPhotosRequest struct {
+	Query       string `query:"query" validate:"required,min=2,max=200"`
+	Page        int    `query:"page" validate:"min=1"`
+	PerPage     int    `query:"per_page" validate:"min=1,max=100"`
+	Orientation string `query:"orientation" validate:"omitempty,oneof=landscape portrait squarish"`
+	Color       string `query:"color" validate:"omitempty,oneof=black_and_white black white yellow orange red purple magenta green teal blue"`
+	OrderBy     string `query:"order_by" validate:"omitempty,oneof=latest relevant"`
+	MinWidth    int    `query:"min_width" validate:"min=0"`
+	MinHeight   int    `query:"min_height" validate:"min=0"`
+}
+
+// LocalSearchRequest — параметры GET /photos/search/local без пагинации и сортировки,
+// которые разбираются общими paginationFromRequest и sortFromRequest.
+// from и to — даты загрузки фото включительно, как у выгрузки
+type LocalSearchRequest struct {
+	Query       string `query:"query" validate:"required,max=200"`
+	MinLikes    int    `query:"min_likes" validate:"min=0"`
+	MinWidth    int    `query:"min_width" validate:"min=0"`
+	MinHeight   int    `query:"min_height" validate:"min=0"`
+	Author      string `query:"author" validate:"max=100"`
+	Orientation string `query:"orientation" validate:"omitempty,oneof=landscape portrait squarish"`
+	From        string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To          string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	IncludeRank bool   `query:"include_rank"`
+}
+
+// AutocompleteRequest — параметры GET /photos/autocomplete; предел limit — usecase.MaxAutocompleteLimit
+type AutocompleteRequest struct {
+	Prefix string `query:"q" validate:"required,max=100"`
+	Limit  int    `query:"limit" validate:"min=1,max=20"`
+}
+
+// TagCloudRequest — параметры GET /tags/cloud; предел limit — usecase.MaxTagCloudLimit
+type TagCloudRequest struct {
+	Limit int `query:"limit" validate:"min=1,max=200"`
+}
+
+// TagSearchRequest — параметры GET /tags/search; предел limit — usecase.MaxTagSearchLimit
+type TagSearchRequest struct {
+	Prefix string `query:"q" validate:"required,max=50"`
+	Limit  int    `query:"limit" validate:"min=1,max=20"`
+}
+
+// TrendingPhotosRequest — параметры GET /photos/trending
+type TrendingPhotosRequest struct {
+	Limit int `query:"limit" validate:"min=1,max=100"`
+}
+
+// SimilarPhotosRequest — параметры GET /photos/{id}/similar
+type SimilarPhotosRequest struct {
+	Limit int `query:"limit" validate:"min=1,max=50"`
+}
+
+// NearbyPhotosRequest — параметры GET /photos/nearby. Radius — в километрах.
+// Координаты — указатели, чтобы lat=0 (экватор) не считался отсутствующим параметром
+type NearbyPhotosRequest struct {
+	Lat    *float64 `query:"lat" validate:"required,latitude"`
+	Lon    *float64 `query:"lon" validate:"required,longitude"`
+	Radius float64  `query:"radius" validate:"gt=0,max=20000"`
+}
+
+// ExportPhotosRequest — параметры GET /photos/export. from и to — даты загрузки фото включительно
+type ExportPhotosRequest struct {
+	Format string `query:"format" validate:"oneof=csv"`
+	Query  string `query:"query" validate:"max=200"`
+	From   string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To     string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ImportCollectionRequest — параметры POST /admin/collections/{id}/import.
+// 30 — максимальный размер страницы Unsplash
+type ImportCollectionRequest struct {
+	PerPage int `query:"per_page" json:"per_page" validate:"min=1,max=30"`
+}
+
+// ImportAuthorRequest — параметры POST /admin/authors/{username}/import.
+// MaxPhotos 0 означает предел из настроек сервера
+type ImportAuthorRequest struct {
+	PerPage   int `query:"per_page" json:"per_page" validate:"min=1,max=30"`
+	MaxPhotos int `query:"max_photos" json:"max_photos" validate:"min=0"`
+}
+
+// CreateWebhookRequest — JSON-тело POST /webhooks. Secret необязателен: без него секрет генерируется.
+// Адрес во внутренней сети отклоняет usecase (ответ 422 WEBHOOK_URL_DENIED)
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,http_url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=photo.created photo.updated photo.deleted"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=256"`
+}
+
+// BatchImportRequest — JSON-тело POST /photos/batch-import: до 20 ID фото Unsplash за запрос
+type BatchImportRequest struct {
+	UnsplashIDs []string `json:"unsplash_ids" validate:"required,min=1,max=20,unique,dive,required,max=64"`
+}
+
+// BatchTagRequest — JSON-тело POST /photos/batch-tag. Нужен хотя бы один из списков add_tags и remove_tags;
+// тег из обоих списков у фото остаётся
+type BatchTagRequest struct {
+	PhotoIDs   []string `json:"photo_ids" validate:"required,min=1,max=500,unique,dive,uuid"`
+	AddTags    []string `json:"add_tags" validate:"max=50,dive,required,max=50"`
+	RemoveTags []string `json:"remove_tags" validate:"max=50,dive,required,max=50"`
+}
+
+// UploadIntentRequest — JSON-тело POST /photos/upload-intent. Допустимые типы проверяет usecase,
+// предел size_bytes (MAX_UPLOAD_SIZE_MB) — обработчик
+type UploadIntentRequest struct {
+	ContentType string `json:"content_type" validate:"required,max=100"`
+	SizeBytes   int64  `json:"size_bytes" validate:"required,min=1"`
+	Title       string `json:"title" validate:"max=255"`
+	Description string `json:"description" validate:"max=2000"`
+}
+
+// validateRequest заполняет dst из запроса и проверяет его.
+// Если запрос некорректен, отвечает 400 со списком ошибок по полям и возвращает false
+func validateRequest(w http.ResponseWriter, r *http.Request, v *validation.Validator, dst any, log *slog.Logger) bool {
+	errs := v.ValidateRequest(r, dst)
+	if len(errs) == 0 {
+		return true
+	}
+	logger.FromContext(r.Context(), log).Warn("request validation failed", "path", r.URL.Path, "errors", errs)
+	respondWithErrorDetails(w, http.StatusBadRequest, CodeValidation, "Некорректные параметры запроса", errs, log)
+	return false
+}
@@ -0,0 +1,46 @@
+package handler
+
+import "github.com/GoArmGo/MediaApp/internal/domain"
+
+// PaginatedResponse — ответ списка с метаданными пагинации
+type PaginatedResponse[T any] struct {
+	Data       []T   `json:"data"`
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+}
+
+// newPaginatedResponse считает total_pages и has_next по общему количеству элементов
+func newPaginatedResponse[T any](data []T, page, perPage int, total int64) PaginatedResponse[T] {
+	if data == nil {
+		data = []T{} // пустой список отдаём как [], а не null
+	}
+	totalPages := 0
+	if perPage > 0 {
+		totalPages = int((total + int64(perPage) - 1) / int64(perPage))
+	}
+	return PaginatedResponse[T]{
+		Data:       data,
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+	}
+}
+
+// ExternalSearchResponse — ответ поиска во внешнем источнике: сохранённые фото страницы
+// и сколько всего результатов и страниц у источника, чтобы клиент знал, где остановиться.
+// Sources — источники, чьи фото вошли в ответ источника
+type ExternalSearchResponse struct {
+	Message    string         `json:"message"`
+	Data       []domain.Photo `json:"data"`
+	Page       int            `json:"page"`
+	PerPage    int            `json:"per_page"`
+	Total      int            `json:"total"`
+	TotalPages int            `json:"total_pages"`
+	HasNext    bool           `json:"has_next"`
+	Sources    []string       `json:"sources,omitempty"`
+}
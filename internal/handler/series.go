@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxSeriesTitleLength — предел длины названия серии (в символах), как у колонки photo_series.title
+const maxSeriesTitleLength = 255
+
+// SeriesHandler — обработчик HTTP-запросов для работы с сериями фото.
+// Все маршруты требуют авторизации: пользователь видит только свои серии
+type SeriesHandler struct {
+	seriesUseCase usecase.SeriesUseCase
+	maxPerPage    int // предел per_page для списков
+	logger        *slog.Logger
+}
+
+// NewSeriesHandler создаёт новый экземпляр SeriesHandler.
+func NewSeriesHandler(uc usecase.SeriesUseCase, maxPerPage int, logger *slog.Logger) *SeriesHandler {
+	return &SeriesHandler{
+		seriesUseCase: uc,
+		maxPerPage:    maxPerPage,
+		logger:        logger,
+	}
+}
+
+type createSeriesRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type updateSeriesRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+}
+
+// addSeriesPhotoRequest — тело POST /series/{id}/photos; без position фото добавляется в конец
+type addSeriesPhotoRequest struct {
+	PhotoID  uuid.UUID `json:"photo_id"`
+	Position *int      `json:"position"`
+}
+
+// reorderSeriesRequest — тело PATCH /series/{id}/photos: все фото серии в новом порядке
+type reorderSeriesRequest struct {
+	PhotoIDs []uuid.UUID `json:"photo_ids"`
+}
+
+// CreateSeries — создаёт серию текущего пользователя.
+func (h *SeriesHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	var req createSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid create series request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if msg := validateSeriesTitle(req.Title); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg, h.logger)
+		return
+	}
+
+	series, err := h.seriesUseCase.CreateSeries(r.Context(), userID, req.Title, strings.TrimSpace(req.Description))
+	if err != nil {
+		h.log(r.Context()).Error("failed to create series", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка при создании серии", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, series, h.logger)
+}
+
+// ListSeries — возвращает серии текущего пользователя с пагинацией.
+func (h *SeriesHandler) ListSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+	series, total, err := h.seriesUseCase.ListSeries(r.Context(), userID, page, perPage)
+	if err != nil {
+		h.log(r.Context()).Error("failed to list series", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения серий", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(series, page, perPage, total), h.logger)
+}
+
+// GetSeries — возвращает серию вместе с первыми фото в порядке серии.
+func (h *SeriesHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	userID, seriesID, ok := h.seriesFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	series, err := h.seriesUseCase.GetSeriesWithPhotos(r.Context(), userID, seriesID)
+	if err != nil {
+		h.respondWithSeriesError(w, r, err, "Ошибка получения серии")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, series, h.logger)
+}
+
+// UpdateSeries — меняет название и/или описание серии.
+func (h *SeriesHandler) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	userID, seriesID, ok := h.seriesFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid update series request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+	if req.Title != nil {
+		*req.Title = strings.TrimSpace(*req.Title)
+		if msg := validateSeriesTitle(*req.Title); msg != "" {
+			respondWithError(w, http.StatusBadRequest, msg, h.logger)
+			return
+		}
+	}
+	if req.Description != nil {
+		*req.Description = strings.TrimSpace(*req.Description)
+	}
+
+	series, err := h.seriesUseCase.UpdateSeries(r.Context(), userID, seriesID, domain.PhotoSeriesUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.respondWithSeriesError(w, r, err, "Ошибка обновления серии")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, series, h.logger)
+}
+
+// DeleteSeries — удаляет серию. Фото из серии не удаляются.
+func (h *SeriesHandler) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	userID, seriesID, ok := h.seriesFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.seriesUseCase.DeleteSeries(r.Context(), userID, seriesID); err != nil {
+		h.respondWithSeriesError(w, r, err, "Ошибка удаления серии")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSeriesPhotos — возвращает фото серии с пагинацией в порядке серии.
+func (h *SeriesHandler) ListSeriesPhotos(w http.ResponseWriter, r *http.Request) {
+	userID, seriesID, ok := h.seriesFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	page, perPage, ok := paginationFromRequest(w, r, h.maxPerPage, h.logger)
+	if !ok {
+		return
+	}
+	photos, total, err := h.seriesUseCase.ListPhotos(r.Context(), userID, seriesID, page, perPage)
+	if err != nil {
+		h.respondWithSeriesError(w, r, err, "Ошибка получения фото серии")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, newPaginatedResponse(photos, page, perPage, total), h.logger)
+}
+
+// AddSeriesPhoto — добавляет фото в серию на позицию position (с нуля) или в конец.
+// Повторное добавление отвечает 200 вместо 201 и позицию не меняет.
+func (h *SeriesHandler) AddSeriesPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, seriesID, ok := h.seriesFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req addSeriesPhotoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid add series photo request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+	if req.PhotoID == uuid.Nil {
+		respondWithError(w, http.StatusBadRequest, "Не указан photo_id", h.logger)
+		return
+	}
+	position := -1
+	if req.Position != nil {
+		if *req.Position < 0 {
+			respondWithError(w, http.StatusBadRequest, "position не может быть отрицательным", h.logger)
+			return
+		}
+		position = *req.Position
+	}
+
+	added, err := h.seriesUseCase.AddPhotoToSeries(r.Context(), userID, seriesID, req.PhotoID, position)
+	if err != nil {
+		h.respondWithSeriesError(w, r, err, "Ошибка добавления фото в серию")
+		return
+	}
+
+	status := http.StatusOK
+	if added {
+		status = http.StatusCreated
+	}
+	respondWithJSON(w, status, map[string]interface{}{
+		"series_id": seriesID,
+		"photo_id":  req.PhotoID,
+		"added":     added,
+	}, h.logger)
+}
+
+// ReorderSeriesPhotos — расставляет фото серии в переданном порядке.
+// В photo_ids должно быть каждое фото серии ровно один раз.
+func (h *SeriesHandler) ReorderSeriesPhotos(w http.ResponseWriter, r *http.Request) {
+	userID, seriesID, ok := h.seriesFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req reorderSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid reorder series request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+
+	if err := h.seriesUseCase.ReorderPhotos(r.Context(), userID, seriesID, req.PhotoIDs); err != nil {
+		h.respondWithSeriesError(w, r, err, "Ошибка изменения порядка фото серии")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveSeriesPhoto — убирает фото из серии, само фото остаётся.
+func (h *SeriesHandler) RemoveSeriesPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, seriesID, ok := h.seriesFromRequest(w, r)
+	if !ok {
+		return
+	}
+	raw := chi.URLParam(r, "photoID")
+	photoID, err := uuid.Parse(raw)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid photo id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID фото", h.logger)
+		return
+	}
+
+	if err := h.seriesUseCase.RemovePhotoFromSeries(r.Context(), userID, seriesID, photoID); err != nil {
+		h.respondWithSeriesError(w, r, err, "Ошибка удаления фото из серии")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// seriesFromRequest достаёт текущего пользователя и ID серии из пути.
+// При ошибке сам отвечает клиенту и возвращает false
+func (h *SeriesHandler) seriesFromRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	raw := chi.URLParam(r, "id")
+	seriesID, err := uuid.Parse(raw)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid series id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID серии", h.logger)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, seriesID, true
+}
+
+// respondWithSeriesError отвечает 404 для ненайденной серии или фото, 400 для неверного порядка
+// и 500 для остальных ошибок
+func (h *SeriesHandler) respondWithSeriesError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrSeriesNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, CodeSeriesNotFound, "Серия не найдена", h.logger)
+	case errors.Is(err, domain.ErrPhotoNotFound):
+		respondWithErrorCode(w, http.StatusNotFound, CodePhotoNotFound, "Фото не найдено", h.logger)
+	case errors.Is(err, domain.ErrInvalidSeriesOrder):
+		respondWithErrorCode(w, http.StatusBadRequest, CodeInvalidSeriesOrder,
+			"photo_ids должен содержать каждое фото серии ровно один раз", h.logger)
+	default:
+		h.log(r.Context()).Error("series request failed", "path", r.URL.Path, "error", err)
+		respondWithError(w, http.StatusInternalServerError, msg, h.logger)
+	}
+}
+
+// validateSeriesTitle возвращает текст ошибки для некорректного названия серии
+func validateSeriesTitle(title string) string {
+	if title == "" {
+		return "Название серии не может быть пустым"
+	}
+	if utf8.RuneCountInString(title) > maxSeriesTitleLength {
+		return "Название серии должно быть не длиннее 255 символов"
+	}
+	return ""
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (h *SeriesHandler) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
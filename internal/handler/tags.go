@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// GetTagCloud — самые частые теги с числом фото (GET /tags/cloud?limit=50), самые частые первыми.
+func (h *PhotoHandler) GetTagCloud(w http.ResponseWriter, r *http.Request) {
+	req := TagCloudRequest{Limit: 50}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	tags, err := h.photoUseCase.GetTagCloud(r.Context(), req.Limit)
+	if err != nil {
+		h.log(r.Context()).Error("failed to get tag cloud", "limit", req.Limit, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения облака тегов", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tags, h.logger)
+}
+
+// SearchTags — автодополнение названий тегов (GET /tags/search?q=<префикс>&limit=10).
+func (h *PhotoHandler) SearchTags(w http.ResponseWriter, r *http.Request) {
+	req := TagSearchRequest{Limit: 10}
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	tags, err := h.photoUseCase.SearchTags(r.Context(), req.Prefix, req.Limit)
+	if err != nil {
+		h.log(r.Context()).Error("failed to search tags", "prefix", req.Prefix, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка поиска тегов", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string][]domain.Tag{"tags": tags}, h.logger)
+}
+
+// batchTagResponse — ответ POST /photos/batch-tag
+type batchTagResponse struct {
+	Updated  int64       `json:"updated"`
+	NotFound []uuid.UUID `json:"not_found"`
+}
+
+// BatchTagPhotos — добавляет и снимает теги сразу у нескольких фото (POST /photos/batch-tag).
+// Если часть фото не найдена, отвечает 207 Multi-Status: остальные фото всё равно изменены
+func (h *PhotoHandler) BatchTagPhotos(w http.ResponseWriter, r *http.Request) {
+	var req BatchTagRequest
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+	if len(req.AddTags) == 0 && len(req.RemoveTags) == 0 {
+		respondWithError(w, http.StatusBadRequest, "Нужно передать add_tags или remove_tags", h.logger)
+		return
+	}
+
+	// Формат ID уже проверен валидатором
+	photoIDs := make([]uuid.UUID, len(req.PhotoIDs))
+	for i, raw := range req.PhotoIDs {
+		photoIDs[i] = uuid.MustParse(raw)
+	}
+
+	result, err := h.photoUseCase.BatchUpdateTags(r.Context(), photoIDs, req.AddTags, req.RemoveTags)
+	if err != nil {
+		h.log(r.Context()).Error("failed to batch update tags", "count", len(photoIDs), "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка изменения тегов фото", h.logger)
+		return
+	}
+
+	status := http.StatusOK
+	if len(result.NotFound) > 0 {
+		status = http.StatusMultiStatus
+	}
+	respondWithJSON(w, status, batchTagResponse{Updated: result.Updated, NotFound: result.NotFound}, h.logger)
+}
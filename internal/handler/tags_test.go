@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/google/uuid"
+)
+
+func TestBatchTagPhotos_Status(t *testing.T) {
+	found, missing := uuid.New(), uuid.New()
+	body := `{"photo_ids": ["` + found.String() + `", "` + missing.String() + `"], "add_tags": ["nature"], "remove_tags": ["indoor"]}`
+
+	tests := []struct {
+		name     string
+		notFound []uuid.UUID
+		status   int
+	}{
+		{name: "all found", notFound: []uuid.UUID{}, status: http.StatusOK},
+		{name: "some missing", notFound: []uuid.UUID{missing}, status: http.StatusMultiStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIDs []uuid.UUID
+			var gotAdd, gotRemove []string
+			h := newTestPhotoHandler(&stubPhotoUseCase{
+				batchTag: func(_ context.Context, ids []uuid.UUID, add, remove []string) (usecase.BatchTagResult, error) {
+					gotIDs, gotAdd, gotRemove = ids, add, remove
+					return usecase.BatchTagResult{Updated: int64(len(ids) - len(tt.notFound)), NotFound: tt.notFound}, nil
+				},
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/photos/batch-tag", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			h.BatchTagPhotos(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+			if len(gotIDs) != 2 || gotIDs[0] != found || gotIDs[1] != missing {
+				t.Errorf("usecase got IDs %v, want [%s %s]", gotIDs, found, missing)
+			}
+			if len(gotAdd) != 1 || gotAdd[0] != "nature" || len(gotRemove) != 1 || gotRemove[0] != "indoor" {
+				t.Errorf("usecase got add %v, remove %v; want [nature], [indoor]", gotAdd, gotRemove)
+			}
+			var resp batchTagResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Updated != int64(2-len(tt.notFound)) || len(resp.NotFound) != len(tt.notFound) {
+				t.Errorf("response = %+v, want %d updated and not_found %v", resp, 2-len(tt.notFound), tt.notFound)
+			}
+		})
+	}
+}
+
+func TestBatchTagPhotos_RequiresTags(t *testing.T) {
+	h := newTestPhotoHandler(&stubPhotoUseCase{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/photos/batch-tag", strings.NewReader(`{"photo_ids": ["`+uuid.NewString()+`"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	h.BatchTagPhotos(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d; body: %s", rec.Code, http.StatusBadRequest, rec.Body)
+	}
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+// maxUploadRequestBytes — запрос загрузки с Content-Length больше 200 МБ отклоняется, не читая тело,
+// какой бы лимит загрузки ни был настроен
+const maxUploadRequestBytes = 200 << 20
+
+// uploadFormOverhead — запас на поля формы и заголовки multipart сверх размера самого файла.
+// Тело запроса загрузки ограничено maxUploadBytes+uploadFormOverhead (но не больше maxUploadRequestBytes),
+// что строже общего MaxBodySize
+const uploadFormOverhead = 1 << 20
+
+// UploadPhoto — принимает фото от пользователя (multipart/form-data: file, title, description).
+// Требует JWT; одновременных загрузок не больше, чем вмещает uploadLimiter.
+func (h *PhotoHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	// запрос с большим Content-Length отклоняется, не читая тело
+	limit := min(h.maxUploadBytes+uploadFormOverhead, maxUploadRequestBytes)
+	if r.ContentLength > limit {
+		h.log(r.Context()).Warn("upload request too large", "content_length", r.ContentLength, "limit_bytes", limit)
+		respondWithErrorCode(w, http.StatusRequestEntityTooLarge, CodeFileTooLarge, "Файл слишком большой", h.logger)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	if err := r.ParseMultipartForm(h.maxUploadBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.log(r.Context()).Warn("upload too large", "limit_bytes", h.maxUploadBytes)
+			respondWithErrorCode(w, http.StatusRequestEntityTooLarge, CodeFileTooLarge, "Файл слишком большой", h.logger)
+			return
+		}
+		h.log(r.Context()).Warn("invalid multipart form", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректная форма загрузки", h.logger)
+		return
+	}
+	defer func() {
+		if err := r.MultipartForm.RemoveAll(); err != nil {
+			h.log(r.Context()).Warn("failed to remove multipart temp files", "error", err)
+		}
+	}()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.log(r.Context()).Warn("missing upload file", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Не передан файл", h.logger)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.maxUploadBytes {
+		h.log(r.Context()).Warn("upload too large", "size", header.Size, "limit_bytes", h.maxUploadBytes)
+		respondWithErrorCode(w, http.StatusRequestEntityTooLarge, CodeFileTooLarge, "Файл слишком большой", h.logger)
+		return
+	}
+
+	// Ограничиваем число одновременных загрузок
+	select {
+	case h.uploadLimiter <- struct{}{}:
+		defer func() { <-h.uploadLimiter }()
+	case <-r.Context().Done():
+		respondWithError(w, http.StatusServiceUnavailable, "Сервер занят, попробуйте позже", h.logger)
+		return
+	}
+
+	h.log(r.Context()).Info("uploading photo", "user_id", userID, "filename", header.Filename, "size", header.Size)
+
+	photo, err := h.photoUseCase.UploadPhoto(r.Context(), usecase.UploadPhotoInput{
+		UserID:      userID,
+		Title:       r.FormValue("title"),
+		Description: r.FormValue("description"),
+		File:        file,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnsupportedImageType):
+			respondWithErrorCode(w, http.StatusUnsupportedMediaType, CodeUnsupportedImage, "Допустимы только изображения JPEG, PNG, WebP и GIF", h.logger)
+		case errors.Is(err, domain.ErrUserNotFound):
+			respondWithErrorCode(w, http.StatusUnauthorized, CodeUserNotFound, "Пользователь не найден", h.logger)
+		default:
+			h.log(r.Context()).Error("failed to upload photo", "user_id", userID, "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Ошибка загрузки фото", h.logger)
+		}
+		return
+	}
+
+	h.log(r.Context()).Info("photo uploaded", "photo_id", photo.ID, "user_id", userID)
+	respondWithJSON(w, http.StatusCreated, photo, h.logger)
+}
+
+// uploadIntentResponse — ответ POST /photos/upload-intent: ожидающая загрузка, её фото в статусе
+// pending и как загрузить файл. Клиент отправляет файл запросом upload_method на upload_url
+// с заголовками upload_headers до expires_at, затем вызывает POST /photos/{id}/complete
+type uploadIntentResponse struct {
+	domain.UploadIntent
+	Status        string            `json:"status"`
+	Photo         domain.Photo      `json:"photo"`
+	UploadURL     string            `json:"upload_url"`
+	UploadMethod  string            `json:"upload_method"`
+	UploadHeaders map[string]string `json:"upload_headers"`
+}
+
+// CreateUploadIntent — начинает загрузку фото напрямую в хранилище (POST /photos/upload-intent).
+// Файл не проходит через сервер: в ответе подписанная ссылка для PUT, в подпись которой входят
+// заявленные content_type и size_bytes. Требует JWT.
+func (h *PhotoHandler) CreateUploadIntent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	var req UploadIntentRequest
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+	if req.SizeBytes > h.maxUploadBytes {
+		h.log(r.Context()).Warn("upload intent too large", "size", req.SizeBytes, "limit_bytes", h.maxUploadBytes)
+		respondWithErrorCode(w, http.StatusRequestEntityTooLarge, CodeFileTooLarge, "Файл слишком большой", h.logger)
+		return
+	}
+
+	result, err := h.photoUseCase.CreateUploadIntent(r.Context(), usecase.UploadIntentInput{
+		UserID:      userID,
+		Title:       req.Title,
+		Description: req.Description,
+		ContentType: req.ContentType,
+		SizeBytes:   req.SizeBytes,
+		TTL:         h.uploadIntentTTL,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnsupportedImageType):
+			respondWithErrorCode(w, http.StatusUnsupportedMediaType, CodeUnsupportedImage, "Допустимы только изображения JPEG, PNG, WebP и GIF", h.logger)
+		case errors.Is(err, domain.ErrUserNotFound):
+			respondWithErrorCode(w, http.StatusUnauthorized, CodeUserNotFound, "Пользователь не найден", h.logger)
+		default:
+			h.log(r.Context()).Error("failed to create upload intent", "user_id", userID, "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Ошибка создания загрузки", h.logger)
+		}
+		return
+	}
+
+	h.log(r.Context()).Info("upload intent created", "id", result.Intent.ID, "user_id", userID)
+	respondWithJSON(w, http.StatusCreated, uploadIntentResponse{
+		UploadIntent: result.Intent,
+		Status:       result.Photo.Status,
+		Photo:        result.Photo,
+		UploadURL:    result.UploadURL,
+		UploadMethod: http.MethodPut,
+		UploadHeaders: map[string]string{
+			"Content-Type":   result.Intent.ContentType,
+			"Content-Length": strconv.FormatInt(result.Intent.SizeBytes, 10),
+		},
+	}, h.logger)
+}
+
+// CompleteUpload — подтверждает загрузку напрямую в хранилище (POST /photos/{id}/complete):
+// проверяет, что файл загружен и совпадает с заявленным, и делает фото загрузки видимым. Требует JWT.
+func (h *PhotoHandler) CompleteUpload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+	id, raw, err := photoIDFromRequest(r)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid upload id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID загрузки", h.logger)
+		return
+	}
+
+	photo, err := h.photoUseCase.CompleteUpload(r.Context(), userID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUploadIntentNotFound):
+			respondWithErrorCode(w, http.StatusNotFound, CodeUploadNotFound, "Загрузка не найдена или истекла", h.logger)
+		case errors.Is(err, usecase.ErrUploadNotReceived):
+			respondWithErrorCode(w, http.StatusConflict, CodeUploadNotReceived, "Файл ещё не загружен по ссылке", h.logger)
+		case errors.Is(err, usecase.ErrUploadMismatch):
+			respondWithErrorCode(w, http.StatusUnprocessableEntity, CodeUploadMismatch, "Загруженный файл не совпадает с заявленным типом или размером", h.logger)
+		case errors.Is(err, usecase.ErrUnsupportedImageType):
+			respondWithErrorCode(w, http.StatusUnsupportedMediaType, CodeUnsupportedImage, "Допустимы только изображения JPEG, PNG, WebP и GIF", h.logger)
+		case errors.Is(err, domain.ErrUserNotFound):
+			respondWithErrorCode(w, http.StatusUnauthorized, CodeUserNotFound, "Пользователь не найден", h.logger)
+		default:
+			h.log(r.Context()).Error("failed to complete upload", "id", id, "user_id", userID, "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Ошибка подтверждения загрузки", h.logger)
+		}
+		return
+	}
+
+	h.log(r.Context()).Info("photo uploaded directly to storage", "photo_id", photo.ID, "user_id", userID)
+	respondWithJSON(w, http.StatusCreated, photo, h.logger)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func TestCompleteUpload_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "intent not found", err: domain.ErrUploadIntentNotFound, status: http.StatusNotFound, code: CodeUploadNotFound},
+		{name: "file not received", err: usecase.ErrUploadNotReceived, status: http.StatusConflict, code: CodeUploadNotReceived},
+		{name: "file mismatch", err: usecase.ErrUploadMismatch, status: http.StatusUnprocessableEntity, code: CodeUploadMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestPhotoHandler(&stubPhotoUseCase{
+				complete: func(_ context.Context, _, id uuid.UUID) (*domain.Photo, error) {
+					return nil, fmt.Errorf("usecase: загрузка %s: %w", id, tt.err)
+				},
+			})
+			r := chi.NewRouter()
+			r.Post("/photos/{id}/complete", h.CompleteUpload)
+
+			req := httptest.NewRequest(http.MethodPost, "/photos/"+uuid.NewString()+"/complete", nil)
+			req = req.WithContext(context.WithValue(req.Context(), userIDKey, uuid.New()))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Code != tt.code {
+				t.Errorf("code = %q, want %q", resp.Code, tt.code)
+			}
+		})
+	}
+}
+
+// unreadBody — тело запроса, чтение которого проваливает тест
+type unreadBody struct{ t *testing.T }
+
+func (b unreadBody) Read([]byte) (int, error) {
+	b.t.Error("handler read the body of an oversized upload")
+	return 0, io.EOF
+}
+
+func TestUploadPhoto_RejectsLargeContentLength(t *testing.T) {
+	small := newTestPhotoHandler(&stubPhotoUseCase{})
+	limit := small.maxUploadBytes + uploadFormOverhead
+	// лимит загрузки больше 200 МБ не отменяет отказа по Content-Length
+	large := NewPhotoHandler(&stubPhotoUseCase{}, nil, make(chan struct{}, 1), 500<<20, 0, 0, 100, validation.New(), discardLogger())
+
+	tests := []struct {
+		name          string
+		h             *PhotoHandler
+		contentLength int64
+		status        int
+	}{
+		{name: "over the upload limit", h: small, contentLength: limit + 1, status: http.StatusRequestEntityTooLarge},
+		{name: "at the upload limit", h: small, contentLength: limit, status: http.StatusBadRequest},
+		{name: "over 200 MB", h: large, contentLength: maxUploadRequestBytes + 1, status: http.StatusRequestEntityTooLarge},
+		{name: "at 200 MB", h: large, contentLength: maxUploadRequestBytes, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader("")
+			if tt.status == http.StatusRequestEntityTooLarge {
+				body = unreadBody{t}
+			}
+			req := httptest.NewRequest(http.MethodPost, "/photos/upload", body)
+			req.ContentLength = tt.contentLength
+			req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+			req = req.WithContext(context.WithValue(req.Context(), userIDKey, uuid.New()))
+			rec := httptest.NewRecorder()
+			tt.h.UploadPhoto(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+}
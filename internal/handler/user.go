@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/GoArmGo/MediaApp/internal/auth"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+)
+
+const minPasswordLength = 8
+
+// UserHandler — обработчик HTTP-запросов для работы с пользователями.
+type UserHandler struct {
+	userUseCase usecase.UserUseCase
+	tokens      *auth.TokenManager
+	logger      *slog.Logger
+}
+
+// NewUserHandler создаёт новый экземпляр UserHandler.
+func NewUserHandler(uc usecase.UserUseCase, tokens *auth.TokenManager, logger *slog.Logger) *UserHandler {
+	return &UserHandler{
+		userUseCase: uc,
+		tokens:      tokens,
+		logger:      logger,
+	}
+}
+
+type registerRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type updateProfileRequest struct {
+	Username        *string `json:"username"`
+	Email           *string `json:"email"`
+	CurrentPassword *string `json:"currentPassword"`
+	NewPassword     *string `json:"newPassword"`
+}
+
+// authResponse — пользователь и выданный ему токен
+type authResponse struct {
+	User  *domain.User `json:"user"`
+	Token string       `json:"token"`
+}
+
+// Register — регистрирует нового пользователя и возвращает токен.
+func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid register request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	req.Email = strings.TrimSpace(req.Email)
+	if msg := validateUsername(req.Username); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg, h.logger)
+		return
+	}
+	if msg := validateEmail(req.Email); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg, h.logger)
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		respondWithError(w, http.StatusBadRequest, "Пароль должен содержать не менее 8 символов", h.logger)
+		return
+	}
+
+	user, err := h.userUseCase.Register(r.Context(), req.Username, req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			respondWithErrorCode(w, http.StatusConflict, CodeUserAlreadyExists, "Пользователь с таким username или email уже существует", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to register user", "username", req.Username, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка при регистрации пользователя", h.logger)
+		return
+	}
+
+	h.respondWithToken(w, r, http.StatusCreated, user)
+}
+
+// Login — проверяет email и пароль и возвращает токен.
+func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid login request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+
+	user, err := h.userUseCase.Authenticate(r.Context(), strings.TrimSpace(req.Email), req.Password)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			respondWithErrorCode(w, http.StatusUnauthorized, CodeInvalidCredentials, "Неверный email или пароль", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to authenticate user", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка при аутентификации", h.logger)
+		return
+	}
+
+	h.respondWithToken(w, r, http.StatusOK, user)
+}
+
+// GetMe — возвращает профиль текущего пользователя.
+func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	user, err := h.userUseCase.GetProfile(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			respondWithErrorCode(w, http.StatusNotFound, CodeUserNotFound, "Пользователь не найден", h.logger)
+			return
+		}
+		h.log(r.Context()).Error("failed to get user profile", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения профиля", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user, h.logger)
+}
+
+// UpdateMe — частично обновляет профиль текущего пользователя, включая смену пароля.
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	var req updateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log(r.Context()).Warn("invalid update profile request body", "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректное тело запроса", h.logger)
+		return
+	}
+
+	if req.Username != nil {
+		*req.Username = strings.TrimSpace(*req.Username)
+		if msg := validateUsername(*req.Username); msg != "" {
+			respondWithError(w, http.StatusBadRequest, msg, h.logger)
+			return
+		}
+	}
+	if req.Email != nil {
+		*req.Email = strings.TrimSpace(*req.Email)
+		if msg := validateEmail(*req.Email); msg != "" {
+			respondWithError(w, http.StatusBadRequest, msg, h.logger)
+			return
+		}
+	}
+	if req.NewPassword != nil {
+		if req.CurrentPassword == nil {
+			respondWithError(w, http.StatusBadRequest, "Для смены пароля укажите currentPassword", h.logger)
+			return
+		}
+		if len(*req.NewPassword) < minPasswordLength {
+			respondWithError(w, http.StatusBadRequest, "Пароль должен содержать не менее 8 символов", h.logger)
+			return
+		}
+	}
+
+	user, err := h.userUseCase.UpdateProfile(r.Context(), userID, usecase.UpdateProfileInput{
+		Username:        req.Username,
+		Email:           req.Email,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserAlreadyExists):
+			respondWithErrorCode(w, http.StatusConflict, CodeUserAlreadyExists, "Пользователь с таким username или email уже существует", h.logger)
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			respondWithErrorCode(w, http.StatusForbidden, CodeInvalidCredentials, "Неверный текущий пароль", h.logger)
+		case errors.Is(err, domain.ErrUserNotFound):
+			respondWithErrorCode(w, http.StatusNotFound, CodeUserNotFound, "Пользователь не найден", h.logger)
+		default:
+			h.log(r.Context()).Error("failed to update user profile", "user_id", userID, "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Ошибка обновления профиля", h.logger)
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user, h.logger)
+}
+
+func (h *UserHandler) respondWithToken(w http.ResponseWriter, r *http.Request, code int, user *domain.User) {
+	token, err := h.tokens.Issue(user.ID)
+	if err != nil {
+		h.log(r.Context()).Error("failed to issue token", "user_id", user.ID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка выдачи токена", h.logger)
+		return
+	}
+	respondWithJSON(w, code, authResponse{User: user, Token: token}, h.logger)
+}
+
+// validateUsername возвращает текст ошибки или пустую строку, если username корректен
+func validateUsername(username string) string {
+	if len(username) < 3 || len(username) > 50 {
+		return "username должен содержать от 3 до 50 символов"
+	}
+	return ""
+}
+
+// validateEmail возвращает текст ошибки или пустую строку, если email корректен
+func validateEmail(email string) string {
+	if len(email) > 100 {
+		return "email не должен быть длиннее 100 символов"
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return "Некорректный email"
+	}
+	return ""
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (h *UserHandler) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
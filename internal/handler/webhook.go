@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler — обработчик HTTP-запросов для управления вебхуками.
+// Все маршруты требуют авторизации: пользователь видит только свои вебхуки
+type WebhookHandler struct {
+	webhookUseCase usecase.WebhookUseCase
+	validator      *validation.Validator
+	logger         *slog.Logger
+}
+
+// NewWebhookHandler создаёт новый экземпляр WebhookHandler.
+func NewWebhookHandler(uc usecase.WebhookUseCase, validator *validation.Validator, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: uc,
+		validator:      validator,
+		logger:         logger,
+	}
+}
+
+// createWebhookResponse — ответ на создание вебхука: единственный ответ, в котором есть секрет
+type createWebhookResponse struct {
+	*domain.Webhook
+	Secret string `json:"secret"`
+}
+
+// CreateWebhook — регистрирует вебхук текущего пользователя.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	var req CreateWebhookRequest
+	if !validateRequest(w, r, h.validator, &req, h.logger) {
+		return
+	}
+
+	webhook, err := h.webhookUseCase.CreateWebhook(r.Context(), userID, req.URL, req.Events, req.Secret)
+	if errors.Is(err, usecase.ErrInvalidWebhookURL) {
+		respondWithErrorCode(w, http.StatusUnprocessableEntity, CodeWebhookURLDenied,
+			"Адрес вебхука недопустим: нужен публичный адрес http или https", h.logger)
+		return
+	}
+	if err != nil {
+		h.log(r.Context()).Error("failed to create webhook", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка при создании вебхука", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, createWebhookResponse{Webhook: webhook, Secret: webhook.Secret}, h.logger)
+}
+
+// ListWebhooks — возвращает вебхуки текущего пользователя.
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return
+	}
+
+	webhooks, err := h.webhookUseCase.ListWebhooks(r.Context(), userID)
+	if err != nil {
+		h.log(r.Context()).Error("failed to list webhooks", "user_id", userID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Ошибка получения вебхуков", h.logger)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, webhooks, h.logger)
+}
+
+// GetWebhook — возвращает вебхук по ID.
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := h.webhookFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookUseCase.GetWebhook(r.Context(), userID, webhookID)
+	if err != nil {
+		h.respondWithWebhookError(w, r, err, "Ошибка получения вебхука")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, webhook, h.logger)
+}
+
+// DeleteWebhook — удаляет вебхук, события на него больше не отправляются.
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, webhookID, ok := h.webhookFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookUseCase.DeleteWebhook(r.Context(), userID, webhookID); err != nil {
+		h.respondWithWebhookError(w, r, err, "Ошибка удаления вебхука")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// webhookFromRequest достаёт текущего пользователя и ID вебхука из пути.
+// При ошибке сам отвечает клиенту и возвращает false
+func (h *WebhookHandler) webhookFromRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Требуется авторизация", h.logger)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	raw := chi.URLParam(r, "id")
+	webhookID, err := uuid.Parse(raw)
+	if err != nil {
+		h.log(r.Context()).Warn("invalid webhook id", "id", raw, "error", err)
+		respondWithError(w, http.StatusBadRequest, "Некорректный ID вебхука", h.logger)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, webhookID, true
+}
+
+// respondWithWebhookError отвечает 404 для ненайденного вебхука и 500 для остальных ошибок
+func (h *WebhookHandler) respondWithWebhookError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if errors.Is(err, domain.ErrWebhookNotFound) {
+		respondWithErrorCode(w, http.StatusNotFound, CodeWebhookNotFound, "Вебхук не найден", h.logger)
+		return
+	}
+	h.log(r.Context()).Error("webhook request failed", "path", r.URL.Path, "error", err)
+	respondWithError(w, http.StatusInternalServerError, msg, h.logger)
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (h *WebhookHandler) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, h.logger)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/usecase"
+	"github.com/GoArmGo/MediaApp/internal/validation"
+	"github.com/google/uuid"
+)
+
+// stubWebhookUseCase подменяет CreateWebhook; вызов остальных методов паникует
+type stubWebhookUseCase struct {
+	usecase.WebhookUseCase
+	create func(ctx context.Context, userID uuid.UUID, url string, events []string, secret string) (*domain.Webhook, error)
+}
+
+func (s *stubWebhookUseCase) CreateWebhook(ctx context.Context, userID uuid.UUID, url string, events []string, secret string) (*domain.Webhook, error) {
+	return s.create(ctx, userID, url, events, secret)
+}
+
+func TestCreateWebhook_InternalURLIsUnprocessable(t *testing.T) {
+	h := NewWebhookHandler(&stubWebhookUseCase{
+		create: func(context.Context, uuid.UUID, string, []string, string) (*domain.Webhook, error) {
+			return nil, fmt.Errorf("usecase: %w: %w", usecase.ErrInvalidWebhookURL, fmt.Errorf("хост 169.254.169.254"))
+		},
+	}, validation.New(), discardLogger())
+
+	body := `{"url":"http://169.254.169.254/latest/meta-data/","events":["photo.created"]}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), userIDKey, uuid.New()))
+	rec := httptest.NewRecorder()
+	h.CreateWebhook(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusUnprocessableEntity, rec.Body)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Code != CodeWebhookURLDenied {
+		t.Errorf("code = %q, want %q", resp.Code, CodeWebhookURLDenied)
+	}
+}
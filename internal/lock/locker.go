@@ -0,0 +1,20 @@
+// Package lock описывает распределённые блокировки, общие для всех экземпляров сервиса и воркера
+package lock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotAcquired возвращается, когда блокировку держит другой экземпляр и дождаться её не удалось.
+// Операцию стоит повторить чуть позже
+var ErrNotAcquired = errors.New("блокировка занята другим экземпляром")
+
+// DistributedLocker захватывает блокировки по ключу (например, в Redis)
+type DistributedLocker interface {
+	// Acquire пытается один раз захватить блокировку key на ttl, не дожидаясь её освобождения.
+	// acquired == false, если блокировку уже держит кто-то другой. unlock освобождает
+	// блокировку, только если она всё ещё наша; по истечении ttl она снимается сама
+	Acquire(ctx context.Context, key string, ttl time.Duration) (unlock func(), acquired bool, err error)
+}
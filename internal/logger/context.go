@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxKey struct{}
+
+// WithRequestID сохраняет ID запроса в контексте
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, requestID)
+}
+
+// RequestIDFromContext возвращает ID запроса из контекста или пустую строку
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(ctxKey{}).(string)
+	return requestID
+}
+
+// FromContext возвращает логгер для текущего запроса: l с атрибутом request_id,
+// если ID запроса есть в контексте, иначе сам l
+func FromContext(ctx context.Context, l *slog.Logger) *slog.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return l.With("request_id", requestID)
+	}
+	return l
+}
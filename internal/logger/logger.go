@@ -1,49 +1,86 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Config описывает параметры логгера (можно расширить позже)
 type SlogConfig struct {
 	Level  string // "debug", "info", "warn", "error"
 	Format string // "json" или "text"
+
+	Output   string // "stdout" (по умолчанию) или "file"
+	FilePath string // путь к файлу логов при Output == "file"
+
+	// Ротация файла логов
+	MaxSizeMB  int  // размер файла, после которого он ротируется
+	MaxBackups int  // сколько старых файлов хранить
+	MaxAgeDays int  // сколько дней хранить старые файлы
+	Compress   bool // сжимать ли старые файлы gzip
 }
 
-// New создаёт и настраивает slog.Logger
-func NewSlog(cfg SlogConfig) *slog.Logger {
+// NewSlog создаёт и настраивает slog.Logger. Пустые Level и Format означают info и json;
+// неизвестный уровень или формат — ошибка, а не молчаливый откат к значению по умолчанию
+func NewSlog(cfg SlogConfig) (*slog.Logger, error) {
 	var lvl slog.Level
 
 	switch cfg.Level {
 	case "debug":
 		lvl = slog.LevelDebug
+	case "", "info":
+		lvl = slog.LevelInfo
 	case "warn":
 		lvl = slog.LevelWarn
 	case "error":
 		lvl = slog.LevelError
 	default:
-		lvl = slog.LevelInfo
+		return nil, fmt.Errorf("неподдерживаемый уровень логирования %q: допустимо debug, info, warn или error", cfg.Level)
 	}
 
-	var handler slog.Handler
+	opts := &slog.HandlerOptions{
+		Level:       lvl,
+		ReplaceAttr: formatTime,
+	}
 
 	// Выбираем формат вывода
-	if cfg.Format == "text" {
-		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: lvl})
-	} else {
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: lvl,
-			// Добавляем timestamp в человекочитаемом виде
-			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-				if a.Key == slog.TimeKey {
-					a.Value = slog.StringValue(time.Now().Format(time.RFC3339))
-				}
-				return a
-			},
-		})
+	var handler slog.Handler
+	switch cfg.Format {
+	case "", "json":
+		handler = slog.NewJSONHandler(newOutput(cfg), opts)
+	case "text":
+		handler = slog.NewTextHandler(newOutput(cfg), opts)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый формат логов %q: допустимо json или text", cfg.Format)
+	}
+
+	return slog.New(handler), nil
+}
+
+// formatTime выводит время записи лога в RFC3339 с долями секунды.
+// Берётся время самой записи, а не момент форматирования: при буферизации они расходятся
+func formatTime(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey && len(groups) == 0 && a.Value.Kind() == slog.KindTime {
+		a.Value = slog.StringValue(a.Value.Time().Format(time.RFC3339Nano))
 	}
+	return a
+}
 
-	return slog.New(handler)
+// newOutput возвращает приёмник логов: stdout или файл с ротацией по размеру
+func newOutput(cfg SlogConfig) io.Writer {
+	if cfg.Output != "file" || cfg.FilePath == "" {
+		return os.Stdout
+	}
+	return &lumberjack.Logger{
+		Filename:   cfg.FilePath,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+	}
 }
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSlog_TimestampIsRecordTime(t *testing.T) {
+	// момент записи намеренно в прошлом: время форматирования с ним не совпадёт
+	recordTime := time.Date(2024, 2, 3, 4, 5, 6, 789123456, time.UTC)
+	want := recordTime.Format(time.RFC3339Nano)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "json", want: `"time":"` + want + `"`},
+		{format: "text", want: "time=" + want + " "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			logger, err := NewSlog(SlogConfig{Format: tt.format, Output: "file", FilePath: path})
+			if err != nil {
+				t.Fatalf("NewSlog: %v", err)
+			}
+			record := slog.NewRecord(recordTime, slog.LevelInfo, "delayed record", 0)
+			if err := logger.Handler().Handle(context.Background(), record); err != nil {
+				t.Fatalf("Handle: %v", err)
+			}
+
+			out, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read log: %v", err)
+			}
+			if !strings.Contains(string(out), tt.want) {
+				t.Errorf("log = %q, want timestamp %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSlog_Levels(t *testing.T) {
+	tests := []struct {
+		level string
+		want  slog.Level
+	}{
+		{level: "debug", want: slog.LevelDebug},
+		{level: "info", want: slog.LevelInfo},
+		{level: "warn", want: slog.LevelWarn},
+		{level: "error", want: slog.LevelError},
+		{level: "", want: slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			logger, err := NewSlog(SlogConfig{Level: tt.level})
+			if err != nil {
+				t.Fatalf("NewSlog: %v", err)
+			}
+			ctx := context.Background()
+			if !logger.Enabled(ctx, tt.want) {
+				t.Errorf("level %s is disabled, want enabled", tt.want)
+			}
+			if logger.Enabled(ctx, tt.want-1) {
+				t.Errorf("level %s is enabled, want only %s and above", tt.want-1, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSlog_Formats(t *testing.T) {
+	tests := []struct {
+		format string
+		prefix string // начало строки лога
+	}{
+		{format: "json", prefix: `{"time":`},
+		{format: "text", prefix: "time="},
+		{format: "", prefix: `{"time":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			logger, err := NewSlog(SlogConfig{Format: tt.format, Output: "file", FilePath: path})
+			if err != nil {
+				t.Fatalf("NewSlog: %v", err)
+			}
+			logger.Info("hello", "key", "value")
+
+			out, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read log: %v", err)
+			}
+			if !strings.HasPrefix(string(out), tt.prefix) {
+				t.Errorf("log = %q, want prefix %q", out, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestNewSlog_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SlogConfig
+		want string // значение, которое должна назвать ошибка
+	}{
+		{name: "level", cfg: SlogConfig{Level: "verbose"}, want: "verbose"},
+		{name: "format", cfg: SlogConfig{Format: "xml"}, want: "xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, err := NewSlog(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want an error naming %q", err, tt.want)
+			}
+			if logger != nil {
+				t.Error("logger is not nil for an invalid config")
+			}
+		})
+	}
+}
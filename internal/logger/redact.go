@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// redacted заменяет скрытые части строки подключения
+const redacted = "xxxxx"
+
+// keyValueSecret находит пароль в DSN вида "host=db user=app password=secret":
+// значение в одинарных кавычках (с экранированием) или до пробела
+var keyValueSecret = regexp.MustCompile(`(?i)\b(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// RedactURL скрывает учётные данные в строке подключения для записи в лог.
+// В URL (postgres://, amqp://, redis://) маскируются имя пользователя и пароль,
+// в DSN формата key=value — пароль. Строка, которую не удалось разобрать как URL,
+// заменяется целиком: в ней может оказаться пароль
+func RedactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	if keyValueSecret.MatchString(raw) {
+		return keyValueSecret.ReplaceAllString(raw, "${1}"+redacted)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if u.User != nil {
+		u.User = url.UserPassword(redacted, redacted)
+	}
+	query := u.Query()
+	if query.Has("password") {
+		query.Set("password", redacted)
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactURL_PasswordNeverLogged(t *testing.T) {
+	const password = "s3cr3t-Pa55"
+	tests := []struct {
+		name string
+		dsn  string
+		keep string // часть строки, которая должна остаться в логе
+	}{
+		{name: "postgres URL", dsn: "postgres://app:" + password + "@db:5432/media?sslmode=disable", keep: "db:5432/media"},
+		{name: "amqp URL", dsn: "amqp://guest:" + password + "@rabbitmq:5672/", keep: "rabbitmq:5672"},
+		{name: "redis URL without user", dsn: "redis://:" + password + "@redis:6379/0", keep: "redis:6379"},
+		{name: "password in query", dsn: "postgres://db/media?user=app&password=" + password, keep: "user=app"},
+		{name: "key-value DSN", dsn: "host=db user=app password=" + password + " dbname=media", keep: "dbname=media"},
+		{name: "quoted key-value DSN", dsn: "host=db password='" + password + " x\\'y' dbname=media", keep: "dbname=media"},
+		{name: "unparseable URL", dsn: "postgres://app:" + password + "@db:5432/%zz", keep: redacted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(slog.NewJSONHandler(&buf, nil)).Info("connecting", "dsn", RedactURL(tt.dsn))
+
+			out := buf.String()
+			if strings.Contains(out, password) {
+				t.Errorf("log contains the password: %s", out)
+			}
+			if !strings.Contains(out, tt.keep) {
+				t.Errorf("log lost %q: %s", tt.keep, out)
+			}
+		})
+	}
+}
+
+func TestRedactURL_MasksUser(t *testing.T) {
+	got := RedactURL("postgres://media_owner:secret@db/media")
+	if strings.Contains(got, "media_owner") {
+		t.Errorf("RedactURL = %q, want the user masked", got)
+	}
+}
@@ -0,0 +1,40 @@
+// Package mediatype определяет тип медиафайла по его содержимому, а не по заголовкам
+package mediatype
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// sniffLen — сколько первых байт читает http.DetectContentType
+const sniffLen = 512
+
+// imageTypes — типы, которые http.DetectContentType распознаёт как изображения
+var imageTypes = map[string]bool{
+	"image/jpeg":   true,
+	"image/png":    true,
+	"image/gif":    true,
+	"image/webp":   true,
+	"image/bmp":    true,
+	"image/avif":   true,
+	"image/x-icon": true,
+}
+
+// DetectImageContentType читает первые 512 байт r и определяет по ним тип содержимого.
+// isImage сообщает, что это известный тип изображения. Возвращаемый reader отдаёт поток
+// целиком, включая уже прочитанные байты, — читать дальше нужно из него, а не из r.
+// Пустой поток определяется как text/plain и изображением не считается
+func DetectImageContentType(r io.Reader) (contentType string, isImage bool, rest io.Reader, err error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", false, nil, fmt.Errorf("ошибка чтения начала файла: %w", err)
+	}
+	head = head[:n]
+
+	contentType = http.DetectContentType(head)
+	return contentType, imageTypes[contentType], io.MultiReader(bytes.NewReader(head), r), nil
+}
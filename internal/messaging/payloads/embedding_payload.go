@@ -0,0 +1,12 @@
+package payloads
+
+import "github.com/google/uuid"
+
+// EmbeddingPayload — задача вычисления эмбеддинга сохранённого фото.
+// Идёт через отдельную очередь, чтобы медленный сервис эмбеддингов не задерживал поиск фото
+type EmbeddingPayload struct {
+	PhotoID uuid.UUID `json:"photo_id"`
+
+	// RequestID — ID HTTP-запроса, породившего задачу; попадает в логи воркера
+	RequestID string `json:"request_id,omitempty"`
+}
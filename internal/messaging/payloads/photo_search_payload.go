@@ -1,9 +1,49 @@
 package payloads
 
-// PhotoSearchPayload представляет данные, необходимые для поиска и сохранения фотографий
-// через RabbitMQ
+// Типы задач воркера (PhotoSearchPayload.Type)
+const (
+	TaskSearchPhotos = "search_photos" // поиск и сохранение фото; пустой тип означает то же самое
+	TaskRefreshPhoto = "refresh_photo" // обновление метаданных фото UnsplashID
+	TaskRefreshStats = "refresh_stats" // обновление просмотров и скачиваний фото из статистики Unsplash (StatsRefresh)
+
+	// TaskSyncCollection — импорт коллекции Unsplash CollectionID страница за страницей,
+	// начиная с Page (по умолчанию с первой), по PerPage фото
+	TaskSyncCollection = "sync_collection"
+
+	// TaskSyncAuthor — импорт фото автора Unsplash AuthorUsername по PerPage фото на страницу,
+	// не больше MaxPhotos фото (0 — без ограничения)
+	TaskSyncAuthor = "sync_author"
+)
+
+// PhotoSearchPayload представляет данные задачи воркера, передаваемой через RabbitMQ.
+// По умолчанию это поиск и сохранение фотографий; Type задаёт другие задачи
 type PhotoSearchPayload struct {
+	Type string `json:"type,omitempty"`
+
 	Query   string `json:"query"`
 	Page    int    `json:"page"`
 	PerPage int    `json:"per_page"`
+
+	// Необязательные фильтры поиска
+	Orientation string `json:"orientation,omitempty"` // landscape, portrait, squarish
+	Color       string `json:"color,omitempty"`
+	OrderBy     string `json:"order_by,omitempty"` // latest, relevant
+	MinWidth    int    `json:"min_width,omitempty"`
+	MinHeight   int    `json:"min_height,omitempty"`
+
+	// UnsplashID — фото для задачи TaskRefreshPhoto
+	UnsplashID string `json:"unsplash_id,omitempty"`
+
+	// StatsRefresh — задача TaskRefreshStats
+	StatsRefresh *PhotoStatsRefreshPayload `json:"stats_refresh,omitempty"`
+
+	// CollectionID — коллекция Unsplash для задачи TaskSyncCollection
+	CollectionID string `json:"collection_id,omitempty"`
+
+	// AuthorUsername и MaxPhotos — автор Unsplash и предел числа фото для задачи TaskSyncAuthor
+	AuthorUsername string `json:"author_username,omitempty"`
+	MaxPhotos      int    `json:"max_photos,omitempty"`
+
+	// RequestID — ID HTTP-запроса, породившего задачу; попадает в логи воркера
+	RequestID string `json:"request_id,omitempty"`
 }
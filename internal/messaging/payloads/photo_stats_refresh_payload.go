@@ -0,0 +1,21 @@
+package payloads
+
+// PhotoStatsRefreshPayload — задача обновления просмотров и скачиваний фото из статистики Unsplash.
+// Идёт через общую очередь воркера: в PhotoSearchPayload.StatsRefresh с типом TaskRefreshStats
+type PhotoStatsRefreshPayload struct {
+	UnsplashID string `json:"unsplash_id"`
+}
+
+// NewStatsRefreshTask упаковывает задачу обновления статистики в сообщение очереди воркера
+func NewStatsRefreshTask(payload PhotoStatsRefreshPayload) PhotoSearchPayload {
+	return PhotoSearchPayload{Type: TaskRefreshStats, StatsRefresh: &payload}
+}
+
+// StatsRefreshPayload возвращает задачу обновления статистики из сообщения TaskRefreshStats.
+// Сообщения, опубликованные до появления PhotoStatsRefreshPayload, несут фото в UnsplashID
+func (p PhotoSearchPayload) StatsRefreshPayload() PhotoStatsRefreshPayload {
+	if p.StatsRefresh != nil {
+		return *p.StatsRefresh
+	}
+	return PhotoStatsRefreshPayload{UnsplashID: p.UnsplashID}
+}
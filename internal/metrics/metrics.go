@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mediaapp"
+
+// Metrics содержит все Prometheus-метрики приложения.
+// Регистрируется в переданном Registerer, поэтому в тестах можно подставить отдельный реестр
+type Metrics struct {
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	unsplashRequests  *prometheus.CounterVec
+	unsplashDuration  *prometheus.HistogramVec
+	unsplashRemaining prometheus.Gauge
+
+	s3Uploads        *prometheus.CounterVec
+	s3UploadBytes    prometheus.Counter
+	s3UploadDuration prometheus.Histogram
+
+	queueMessages *prometheus.CounterVec
+
+	photoEvents *prometheus.CounterVec
+}
+
+// New создаёт метрики и регистрирует их в reg
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Количество HTTP-запросов по маршруту, методу и статусу.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Длительность обработки HTTP-запросов.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+
+		unsplashRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "unsplash_requests_total",
+			Help:      "Количество запросов к Unsplash API по операции и результату.",
+		}, []string{"operation", "result"}),
+		unsplashDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "unsplash_request_duration_seconds",
+			Help:      "Длительность запросов к Unsplash API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		unsplashRemaining: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "unsplash_ratelimit_remaining",
+			Help:      "Сколько запросов к Unsplash API осталось в текущем окне лимита (X-Ratelimit-Remaining).",
+		}),
+
+		s3Uploads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "s3_uploads_total",
+			Help:      "Количество загрузок файлов в S3 по результату.",
+		}, []string{"result"}),
+		s3UploadBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "s3_upload_bytes_total",
+			Help:      "Суммарный объём успешно загруженных в S3 данных.",
+		}),
+		s3UploadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "s3_upload_duration_seconds",
+			Help:      "Длительность загрузки файлов в S3.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}),
+
+		queueMessages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "queue_messages_total",
+			Help:      "Количество сообщений очереди по событию: published, publish_failed, consumed, acked, nacked.",
+		}, []string{"queue", "event"}),
+
+		photoEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "photo_events_total",
+			Help:      "Количество доменных событий фото по типу события и источнику фото.",
+		}, []string{"event", "source"}),
+	}
+
+	reg.MustRegister(
+		m.httpRequests, m.httpDuration,
+		m.unsplashRequests, m.unsplashDuration, m.unsplashRemaining,
+		m.s3Uploads, m.s3UploadBytes, m.s3UploadDuration,
+		m.queueMessages,
+		m.photoEvents,
+	)
+	return m
+}
+
+// NewRegistry создаёт реестр со стандартными метриками Go-рантайма и процесса
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// Handler возвращает HTTP-обработчик /metrics для переданного реестра
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest учитывает обработанный HTTP-запрос
+func (m *Metrics) ObserveHTTPRequest(route, method string, status int, d time.Duration) {
+	m.httpRequests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(route, method).Observe(d.Seconds())
+}
+
+// ObserveUnsplashRequest учитывает вызов Unsplash API
+func (m *Metrics) ObserveUnsplashRequest(operation string, d time.Duration, err error) {
+	m.unsplashRequests.WithLabelValues(operation, result(err)).Inc()
+	m.unsplashDuration.WithLabelValues(operation).Observe(d.Seconds())
+}
+
+// SetUnsplashRateLimitRemaining запоминает остаток лимита запросов к Unsplash API
+func (m *Metrics) SetUnsplashRateLimitRemaining(remaining int) {
+	m.unsplashRemaining.Set(float64(remaining))
+}
+
+// ObserveS3Upload учитывает загрузку файла в S3
+func (m *Metrics) ObserveS3Upload(bytes int64, d time.Duration, err error) {
+	m.s3Uploads.WithLabelValues(result(err)).Inc()
+	m.s3UploadDuration.Observe(d.Seconds())
+	if err == nil {
+		m.s3UploadBytes.Add(float64(bytes))
+	}
+}
+
+// IncQueueMessages учитывает событие очереди сообщений
+func (m *Metrics) IncQueueMessages(queue, event string) {
+	m.queueMessages.WithLabelValues(queue, event).Inc()
+}
+
+// IncPhotoEvents учитывает доменное событие фото (создание, обновление)
+func (m *Metrics) IncPhotoEvents(event, source string) {
+	m.photoEvents.WithLabelValues(event, source).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,122 @@
+// Package netguard не даёт исходящим запросам по адресам пользователей (вебхуки) уходить
+// во внутреннюю сеть: на loopback, частные диапазоны RFC 1918, link-local (включая метаданные
+// облака 169.254.169.254) и другие непубличные адреса
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress возвращается, если адрес не публичный
+var ErrForbiddenAddress = errors.New("адрес во внутренней сети запрещён")
+
+// blockedPrefixes — непубличные диапазоны, которые не покрывают методы netip.Addr
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // «эта сеть»
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT, RFC 6598
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // бенчмарки, RFC 2544
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // зарезервировано, включая 255.255.255.255
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64: внутри может быть частный IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // локальный NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // документация
+	netip.MustParsePrefix("2002::/16"),       // 6to4: внутри может быть частный IPv4
+	netip.MustParsePrefix("2001::/32"),       // Teredo
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("::ffff:0:0:0/96"), // IPv4-translated
+	netip.MustParsePrefix("fec0::/10"),       // устаревшие site-local
+}
+
+// IsPublic сообщает, что на адрес ip можно отправлять запросы по адресам пользователей
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL проверяет, что rawURL — адрес http или https, хост которого разрешается только
+// в публичные IP. Проверка при сохранении адреса не защищает от DNS rebinding —
+// для этого запросы отправляются клиентом из NewHTTPClient
+func CheckURL(ctx context.Context, resolver *net.Resolver, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("некорректный URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("схема %q не поддерживается, нужна http или https", u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("в URL нет хоста")
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !IsPublic(ip) {
+			return fmt.Errorf("хост %s: %w", host, ErrForbiddenAddress)
+		}
+		return nil
+	}
+
+	ips, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("не удалось разрешить хост %s: %w", host, err)
+	}
+	for _, ip := range ips {
+		if !IsPublic(ip) {
+			return fmt.Errorf("хост %s разрешается в %s: %w", host, ip, ErrForbiddenAddress)
+		}
+	}
+	return nil
+}
+
+// DialControl — функция для net.Dialer.Control: отклоняет соединение, если IP, к которому
+// клиент действительно подключается, не публичный. Так проверяется уже разрешённый адрес,
+// и хост, который после проверки в CheckURL стал указывать во внутреннюю сеть, не проходит
+func DialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("некорректный адрес соединения %s: %w", address, err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("некорректный IP соединения %s: %w", address, err)
+	}
+	if !IsPublic(ip) {
+		return fmt.Errorf("соединение с %s: %w", address, ErrForbiddenAddress)
+	}
+	return nil
+}
+
+// NewHTTPClient возвращает http.Client с таймаутом timeout, который подключается только
+// к публичным адресам, в том числе при переходе по редиректам. Прокси из окружения
+// не используется: иначе проверялся бы адрес прокси, а не получателя
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   DialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
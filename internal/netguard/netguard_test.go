@@ -0,0 +1,98 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"64:ff9b::a9fe:a9fe", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := IsPublic(netip.MustParseAddr(tt.ip)); got != tt.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantErr   bool
+		forbidden bool
+	}{
+		{name: "public literal", url: "https://93.184.216.34/hook"},
+		{name: "public literal with port", url: "http://93.184.216.34:8080/hook"},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data/", wantErr: true, forbidden: true},
+		{name: "loopback", url: "http://127.0.0.1:8080/hook", wantErr: true, forbidden: true},
+		{name: "ipv6 loopback", url: "http://[::1]/hook", wantErr: true, forbidden: true},
+		{name: "rfc1918", url: "https://10.0.0.5/hook", wantErr: true, forbidden: true},
+		{name: "localhost", url: "http://localhost:8080/hook", wantErr: true, forbidden: true},
+		{name: "unsupported scheme", url: "file:///etc/passwd", wantErr: true},
+		{name: "no host", url: "http:///hook", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckURL(context.Background(), net.DefaultResolver, tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckURL(%q) err = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got := errors.Is(err, ErrForbiddenAddress); got != tt.forbidden {
+				t.Errorf("errors.Is(err, ErrForbiddenAddress) = %v, want %v (err: %v)", got, tt.forbidden, err)
+			}
+		})
+	}
+}
+
+// TestNewHTTPClient_RefusesInternalAddress проверяет защиту на уровне соединения: даже если
+// адрес прошёл CheckURL, а потом хост стал указывать на loopback, клиент не подключится
+func TestNewHTTPClient_RefusesInternalAddress(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	resp, err := NewHTTPClient(5 * time.Second).Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("request to loopback succeeded, want error")
+	}
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("err = %v, want ErrForbiddenAddress", err)
+	}
+	if called {
+		t.Error("server received the request")
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/config"
+	applog "github.com/GoArmGo/MediaApp/internal/logger"
 	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
+	"github.com/GoArmGo/MediaApp/internal/metrics"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -18,16 +20,20 @@ type Client struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
 	queue   amqp.Queue
-	cfg     *config.Config
-	logger  *slog.Logger
+	// embeddingQueue — очередь задач вычисления эмбеддингов фото
+	embeddingQueue amqp.Queue
+	cfg            *config.Config
+	metrics        *metrics.Metrics
+	logger         *slog.Logger
 }
 
 // NewClient создает и инициализирует новый клиент RabbitMQ
-func NewClient(cfg *config.Config, logger *slog.Logger) (*Client, error) {
+func NewClient(cfg *config.Config, m *metrics.Metrics, logger *slog.Logger) (*Client, error) {
 	start := time.Now()
 	client := &Client{
-		cfg:    cfg,
-		logger: logger,
+		cfg:     cfg,
+		metrics: m,
+		logger:  logger,
 	}
 
 	// Подключение к RabbitMQ
@@ -38,7 +44,7 @@ func NewClient(cfg *config.Config, logger *slog.Logger) (*Client, error) {
 	}
 	client.conn = conn
 	logger.Info("connected to RabbitMQ",
-		"url", cfg.RabbitMQ.RabbitMQURL,
+		"url", applog.RedactURL(cfg.RabbitMQ.RabbitMQURL),
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
 
@@ -51,28 +57,38 @@ func NewClient(cfg *config.Config, logger *slog.Logger) (*Client, error) {
 	client.channel = ch
 	logger.Info("RabbitMQ channel opened successfully")
 
-	// Объявление очереди
-	// Это идемпотентная операция: очередь будет создана, если ее нет,
-	// и ничего не произойдет, если она уже существует.
-	q, err := ch.QueueDeclare(
-		cfg.RabbitMQ.RabbitMQQueueName, // name
-		true,                           // durable - очередь будет сохраняться при перезапуске RabbitMQ
-		false,                          // delete when unused
-		false,                          // exclusive - только один потребитель
-		false,                          // no-wait
-		nil,                            // arguments
+	// Объявление очередей задач поиска и эмбеддингов
+	if client.queue, err = client.declareQueue(cfg.RabbitMQ.RabbitMQQueueName); err != nil {
+		return nil, err
+	}
+	if client.embeddingQueue, err = client.declareQueue(cfg.RabbitMQ.RabbitMQEmbeddingQueueName); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// declareQueue объявляет устойчивую очередь.
+// Это идемпотентная операция: очередь будет создана, если ее нет,
+// и ничего не произойдет, если она уже существует.
+func (c *Client) declareQueue(name string) (amqp.Queue, error) {
+	q, err := c.channel.QueueDeclare(
+		name,  // name
+		true,  // durable - очередь будет сохраняться при перезапуске RabbitMQ
+		false, // delete when unused
+		false, // exclusive - только один потребитель
+		false, // no-wait
+		nil,   // arguments
 	)
 	if err != nil {
-		logger.Error("failed to declare queue", "queue", cfg.RabbitMQ.RabbitMQQueueName, "error", err)
-		return nil, fmt.Errorf("failed to declare a queue: %v", err)
+		c.logger.Error("failed to declare queue", "queue", name, "error", err)
+		return amqp.Queue{}, fmt.Errorf("failed to declare a queue: %v", err)
 	}
-	client.queue = q
-	logger.Info("queue declared successfully",
+	c.logger.Info("queue declared successfully",
 		"queue", q.Name,
 		"messages_in_queue", q.Messages,
 	)
-
-	return client, nil
+	return q, nil
 }
 
 // Close закрывает соединение и канал RabbitMQ
@@ -96,6 +112,24 @@ func (c *Client) Close() {
 
 // PublishPhotoSearchRequest публикует сообщение о поиске фото в очередь RabbitMQ
 func (c *Client) PublishPhotoSearchRequest(ctx context.Context, payload payloads.PhotoSearchPayload) error {
+	// Передаём ID запроса в воркер, чтобы логи обработки задачи были связаны с исходным запросом
+	if payload.RequestID == "" {
+		payload.RequestID = applog.RequestIDFromContext(ctx)
+	}
+	return c.publish(ctx, c.queue.Name, payload)
+}
+
+// PublishEmbeddingRequest публикует задачу вычисления эмбеддинга фото в очередь эмбеддингов.
+// Этот метод реализует интерфейс ports.EmbeddingPublisher
+func (c *Client) PublishEmbeddingRequest(ctx context.Context, payload payloads.EmbeddingPayload) error {
+	if payload.RequestID == "" {
+		payload.RequestID = applog.RequestIDFromContext(ctx)
+	}
+	return c.publish(ctx, c.embeddingQueue.Name, payload)
+}
+
+// publish кодирует payload в JSON и публикует его в очередь queue
+func (c *Client) publish(ctx context.Context, queue string, payload any) error {
 	// Маршалинг структуры payload в JSON
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -109,21 +143,23 @@ func (c *Client) PublishPhotoSearchRequest(ctx context.Context, payload payloads
 	start := time.Now()
 	err = c.channel.PublishWithContext(
 		publishCtx,
-		"",           // exchange
-		c.queue.Name, // routing key
-		false,        // mandatory
-		false,        // immediate
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false, // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
 			Body:        body,
 		},
 	)
 	if err != nil {
-		c.logger.Error("failed to publish message", "queue", c.queue.Name, "error", err)
+		c.metrics.IncQueueMessages(queue, "publish_failed")
+		c.logger.Error("failed to publish message", "queue", queue, "error", err)
 		return fmt.Errorf("failed to publish a message: %w", err)
 	}
+	c.metrics.IncQueueMessages(queue, "published")
 	c.logger.Info("message published successfully",
-		"queue", c.queue.Name,
+		"queue", queue,
 		"payload", string(body),
 		"duration_ms", time.Since(start).Milliseconds(),
 	)
@@ -133,8 +169,22 @@ func (c *Client) PublishPhotoSearchRequest(ctx context.Context, payload payloads
 // StartConsumingPhotoSearchRequests начинает потребление сообщений из очереди
 // Этот метод реализует интерфейс ports.PhotoSearchConsumer
 func (c *Client) StartConsumingPhotoSearchRequests(ctx context.Context, handler func(context.Context, payloads.PhotoSearchPayload) error) error {
+	return consume(ctx, c, c.queue.Name, handler)
+}
+
+// StartConsumingEmbeddingRequests начинает потребление задач из очереди эмбеддингов
+// Этот метод реализует интерфейс ports.EmbeddingConsumer
+func (c *Client) StartConsumingEmbeddingRequests(ctx context.Context, handler func(context.Context, payloads.EmbeddingPayload) error) error {
+	return consume(ctx, c, c.embeddingQueue.Name, handler)
+}
+
+// consume регистрирует потребителя очереди queue и в отдельной горутине декодирует сообщения в T
+// и передаёт их handler. Успешно обработанное сообщение подтверждается, при ошибке обработчика
+// возвращается в очередь, а не декодируемое отклоняется без возврата.
+// Это функция, а не метод: методы в Go не могут иметь параметров типа
+func consume[T any](ctx context.Context, c *Client, queue string, handler func(context.Context, T) error) error {
 	msgs, err := c.channel.Consume(
-		c.queue.Name,
+		queue,
 		"",
 		false,
 		false,
@@ -147,7 +197,7 @@ func (c *Client) StartConsumingPhotoSearchRequests(ctx context.Context, handler
 		return fmt.Errorf("failed to register a consumer: %w", err)
 	}
 
-	c.logger.Info("consumer registered, waiting for messages", "queue", c.queue.Name)
+	c.logger.Info("consumer registered, waiting for messages", "queue", queue)
 
 	// Запускаем горутину для обработки сообщений
 	go func() {
@@ -159,8 +209,21 @@ func (c *Client) StartConsumingPhotoSearchRequests(ctx context.Context, handler
 					return // Канал закрыт, выходим из горутины
 				}
 
-				var payload payloads.PhotoSearchPayload
+				// select выбирает готовую ветку случайно, поэтому после отмены контекста
+				// уже доставленное сообщение возвращаем в очередь, а не обрабатываем
+				if ctx.Err() != nil {
+					if err := msg.Nack(false, true); err != nil {
+						c.logger.Error("failed to NACK message after context cancellation", "error", err)
+					}
+					c.logger.Warn("context cancelled, stopping RabbitMQ consumer")
+					return
+				}
+
+				c.metrics.IncQueueMessages(queue, "consumed")
+
+				var payload T
 				if err := json.Unmarshal(msg.Body, &payload); err != nil {
+					c.metrics.IncQueueMessages(queue, "nacked")
 					c.logger.Error("failed to unmarshal message", "error", err, "body", string(msg.Body))
 					// Если демаршалинг не удался
 					// Отклоняем сообщение, но не возвращаем его в очередь (false, false)
@@ -171,12 +234,13 @@ func (c *Client) StartConsumingPhotoSearchRequests(ctx context.Context, handler
 					continue // Переходим к следующему сообщению
 				}
 
-				c.logger.Info("received message from queue", "queue", c.queue.Name, "payload", payload)
+				c.logger.Info("received message from queue", "queue", queue, "payload", payload)
 
 				// Вызываем переданную функцию-обработчик
 				if err := handler(ctx, payload); err != nil {
 					c.logger.Error("error processing message", "error", err, "payload", payload)
 					// Если обработка не удалась, возвращаем сообщение в очередь (requeue = true)
+					c.metrics.IncQueueMessages(queue, "nacked")
 					if err := msg.Nack(false, true); err != nil {
 						c.logger.Error("failed to NACK message after handler failure", "error", err)
 					}
@@ -185,6 +249,7 @@ func (c *Client) StartConsumingPhotoSearchRequests(ctx context.Context, handler
 					if err := msg.Ack(false); err != nil {
 						c.logger.Error("failed to ACK message", "error", err)
 					} else {
+						c.metrics.IncQueueMessages(queue, "acked")
 						c.logger.Info("message processed and ACKed", "payload", payload)
 					}
 				}
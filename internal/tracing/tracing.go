@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config описывает параметры трассировки
+type Config struct {
+	OTLPEndpoint string // адрес OTLP/HTTP коллектора, например http://localhost:4318; пусто — трассировка выключена
+	ServiceName  string
+}
+
+// ShutdownFunc сбрасывает накопленные спаны и останавливает экспортёр
+type ShutdownFunc func(ctx context.Context) error
+
+// Init настраивает глобальный TracerProvider.
+// Если OTLPEndpoint не задан, остаётся no-op провайдер OpenTelemetry, и спаны ничего не стоят
+func Init(ctx context.Context, cfg Config, logger *slog.Logger) (ShutdownFunc, error) {
+	if cfg.OTLPEndpoint == "" {
+		logger.Info("tracing disabled: OTLP endpoint is not set")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания OTLP экспортёра: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания ресурса трассировки: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	logger.Info("tracing enabled", "endpoint", cfg.OTLPEndpoint, "service", cfg.ServiceName)
+	return provider.Shutdown, nil
+}
+
+// Tracer возвращает именованный tracer из глобального провайдера
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer("github.com/GoArmGo/MediaApp/" + name)
+}
+
+// RecordError отмечает спан как завершившийся ошибкой (nil игнорируется)
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CollectionUseCase определяет интерфейс бизнес-логики подборок фото.
+// Все методы работают только с подборками пользователя userID: чужая подборка
+// неотличима от несуществующей и даёт domain.ErrCollectionNotFound
+type CollectionUseCase interface {
+	// CreateCollection создаёт подборку пользователя
+	CreateCollection(ctx context.Context, userID uuid.UUID, name, description string) (*domain.Collection, error)
+
+	// GetCollection возвращает подборку по ID
+	GetCollection(ctx context.Context, userID, id uuid.UUID) (*domain.Collection, error)
+
+	// ListCollections возвращает страницу подборок пользователя и их общее количество
+	ListCollections(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Collection, int64, error)
+
+	// UpdateCollection переименовывает подборку и/или меняет описание и возвращает обновлённую подборку
+	UpdateCollection(ctx context.Context, userID, id uuid.UUID, updates domain.CollectionUpdate) (*domain.Collection, error)
+
+	// DeleteCollection удаляет подборку; фото из неё остаются
+	DeleteCollection(ctx context.Context, userID, id uuid.UUID) error
+
+	// AddPhoto добавляет фото в подборку. Повторное добавление не ошибка, added == false.
+	// Если фото нет, возвращает domain.ErrPhotoNotFound
+	AddPhoto(ctx context.Context, userID, collectionID, photoID uuid.UUID) (added bool, err error)
+
+	// RemovePhoto убирает фото из подборки
+	RemovePhoto(ctx context.Context, userID, collectionID, photoID uuid.UUID) error
+
+	// ListPhotos возвращает страницу фото подборки и их общее количество
+	ListPhotos(ctx context.Context, userID, collectionID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error)
+}
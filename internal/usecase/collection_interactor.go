@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+)
+
+// collectionUseCase implements CollectionUseCase
+type collectionUseCase struct {
+	collectionStorage ports.CollectionStorage
+	photoStorage      ports.PhotoStorage
+	logger            *slog.Logger
+}
+
+// NewCollectionUseCase создает новый экземпляр CollectionUseCase
+func NewCollectionUseCase(collectionStorage ports.CollectionStorage, photoStorage ports.PhotoStorage, logger *slog.Logger) CollectionUseCase {
+	return &collectionUseCase{
+		collectionStorage: collectionStorage,
+		photoStorage:      photoStorage,
+		logger:            logger,
+	}
+}
+
+// CreateCollection создаёт подборку пользователя
+func (uc *collectionUseCase) CreateCollection(ctx context.Context, userID uuid.UUID, name, description string) (*domain.Collection, error) {
+	collection := &domain.Collection{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+	}
+	if err := uc.collectionStorage.CreateCollection(ctx, collection); err != nil {
+		uc.log(ctx).Error("ошибка создания подборки", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при создании подборки: %w", err)
+	}
+
+	uc.log(ctx).Info("подборка создана", slog.String("collection_id", collection.ID.String()), slog.String("user_id", userID.String()))
+	return collection, nil
+}
+
+// GetCollection возвращает подборку пользователя по ID
+func (uc *collectionUseCase) GetCollection(ctx context.Context, userID, id uuid.UUID) (*domain.Collection, error) {
+	return uc.ownCollection(ctx, userID, id)
+}
+
+// ListCollections возвращает страницу подборок пользователя
+func (uc *collectionUseCase) ListCollections(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Collection, int64, error) {
+	collections, err := uc.collectionStorage.ListCollectionsByUser(ctx, userID, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения подборок", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении подборок: %w", err)
+	}
+	total, err := uc.collectionStorage.CountCollectionsByUser(ctx, userID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта подборок", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте подборок: %w", err)
+	}
+	return collections, total, nil
+}
+
+// UpdateCollection обновляет название и/или описание подборки
+func (uc *collectionUseCase) UpdateCollection(ctx context.Context, userID, id uuid.UUID, updates domain.CollectionUpdate) (*domain.Collection, error) {
+	if _, err := uc.ownCollection(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if err := uc.collectionStorage.UpdateCollection(ctx, id, updates); err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			return nil, err
+		}
+		uc.log(ctx).Error("ошибка обновления подборки", slog.String("collection_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при обновлении подборки: %w", err)
+	}
+
+	uc.log(ctx).Info("подборка обновлена", slog.String("collection_id", id.String()))
+	return uc.ownCollection(ctx, userID, id)
+}
+
+// DeleteCollection удаляет подборку пользователя
+func (uc *collectionUseCase) DeleteCollection(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := uc.ownCollection(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := uc.collectionStorage.DeleteCollection(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			return err
+		}
+		uc.log(ctx).Error("ошибка удаления подборки", slog.String("collection_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при удалении подборки: %w", err)
+	}
+
+	uc.log(ctx).Info("подборка удалена", slog.String("collection_id", id.String()))
+	return nil
+}
+
+// AddPhoto добавляет существующее неудалённое фото в подборку
+func (uc *collectionUseCase) AddPhoto(ctx context.Context, userID, collectionID, photoID uuid.UUID) (bool, error) {
+	if _, err := uc.ownCollection(ctx, userID, collectionID); err != nil {
+		return false, err
+	}
+
+	photo, err := uc.photoStorage.GetPhotoByIDFromDB(ctx, photoID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото", slog.String("photo_id", photoID.String()), slog.Any("error", err))
+		return false, fmt.Errorf("usecase: ошибка при получении фото %s: %w", photoID, err)
+	}
+	if photo == nil {
+		return false, domain.ErrPhotoNotFound
+	}
+
+	added, err := uc.collectionStorage.AddPhotoToCollection(ctx, collectionID, photoID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка добавления фото в подборку",
+			slog.String("collection_id", collectionID.String()),
+			slog.String("photo_id", photoID.String()),
+			slog.Any("error", err),
+		)
+		return false, fmt.Errorf("usecase: ошибка при добавлении фото в подборку: %w", err)
+	}
+	return added, nil
+}
+
+// RemovePhoto убирает фото из подборки
+func (uc *collectionUseCase) RemovePhoto(ctx context.Context, userID, collectionID, photoID uuid.UUID) error {
+	if _, err := uc.ownCollection(ctx, userID, collectionID); err != nil {
+		return err
+	}
+	if err := uc.collectionStorage.RemovePhotoFromCollection(ctx, collectionID, photoID); err != nil {
+		uc.log(ctx).Error("ошибка удаления фото из подборки",
+			slog.String("collection_id", collectionID.String()),
+			slog.String("photo_id", photoID.String()),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("usecase: ошибка при удалении фото из подборки: %w", err)
+	}
+	return nil
+}
+
+// ListPhotos возвращает страницу фото подборки
+func (uc *collectionUseCase) ListPhotos(ctx context.Context, userID, collectionID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error) {
+	if _, err := uc.ownCollection(ctx, userID, collectionID); err != nil {
+		return nil, 0, err
+	}
+
+	photos, err := uc.collectionStorage.ListCollectionPhotos(ctx, collectionID, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото подборки", slog.String("collection_id", collectionID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении фото подборки: %w", err)
+	}
+	total, err := uc.collectionStorage.CountCollectionPhotos(ctx, collectionID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото подборки", slog.String("collection_id", collectionID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте фото подборки: %w", err)
+	}
+	return photos, total, nil
+}
+
+// ownCollection получает подборку и проверяет, что она принадлежит userID.
+// Чужая подборка возвращается как несуществующая, чтобы не раскрывать её наличие
+func (uc *collectionUseCase) ownCollection(ctx context.Context, userID, id uuid.UUID) (*domain.Collection, error) {
+	collection, err := uc.collectionStorage.GetCollectionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrCollectionNotFound) {
+			return nil, err
+		}
+		uc.log(ctx).Error("ошибка получения подборки", slog.String("collection_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении подборки: %w", err)
+	}
+	if collection.UserID != userID {
+		uc.log(ctx).Warn("попытка доступа к чужой подборке",
+			slog.String("collection_id", id.String()),
+			slog.String("user_id", userID.String()),
+		)
+		return nil, domain.ErrCollectionNotFound
+	}
+	return collection, nil
+}
+
+func (uc *collectionUseCase) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, uc.logger)
+}
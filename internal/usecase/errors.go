@@ -0,0 +1,47 @@
+package usecase
+
+import "errors"
+
+var (
+	// ErrInvalidCredentials возвращается при неверном email/пароле или текущем пароле при его смене
+	ErrInvalidCredentials = errors.New("неверные учётные данные")
+
+	// ErrUnsupportedImageType возвращается, если загруженный файл не является изображением допустимого типа
+	ErrUnsupportedImageType = errors.New("неподдерживаемый тип изображения")
+
+	// ErrDownloadNotImage возвращается, если файл, скачанный из внешнего источника, по содержимому
+	// не является изображением (например, CDN вернул HTML-страницу ошибки)
+	ErrDownloadNotImage = errors.New("скачанный файл не является изображением")
+
+	// ErrInvalidDateRange возвращается, если начало периода выгрузки не раньше его конца
+	ErrInvalidDateRange = errors.New("начало периода должно быть раньше конца")
+
+	// ErrInvalidColor возвращается, если цвет для поиска не в формате RRGGBB
+	ErrInvalidColor = errors.New("некорректный цвет")
+
+	// ErrCollectionImportUnavailable возвращается при импорте коллекции, если источник Unsplash не настроен
+	ErrCollectionImportUnavailable = errors.New("импорт коллекций недоступен: источник Unsplash не настроен")
+
+	// ErrStatsUnavailable возвращается при обновлении статистики фото, если источник Unsplash не настроен
+	ErrStatsUnavailable = errors.New("статистика фото недоступна: источник Unsplash не настроен")
+
+	// ErrAuthorImportUnavailable возвращается при импорте фото автора, если источник Unsplash не настроен
+	ErrAuthorImportUnavailable = errors.New("импорт фото автора недоступен: источник Unsplash не настроен")
+
+	// ErrEmbeddingUnavailable возвращается при вычислении эмбеддинга, если сервис эмбеддингов не настроен
+	ErrEmbeddingUnavailable = errors.New("вычисление эмбеддингов недоступно: EMBEDDING_SERVICE_URL не задан")
+
+	// ErrFileNotFound возвращается файловым хранилищем, если файла с таким ключом нет
+	ErrFileNotFound = errors.New("файл не найден в хранилище")
+
+	// ErrUploadNotReceived возвращается при подтверждении загрузки, если клиент ещё не загрузил файл по ссылке
+	ErrUploadNotReceived = errors.New("файл ещё не загружен в хранилище")
+
+	// ErrUploadMismatch возвращается при подтверждении загрузки, если размер или тип загруженного файла
+	// не совпадает с заявленным при создании загрузки
+	ErrUploadMismatch = errors.New("загруженный файл не совпадает с заявленным")
+
+	// ErrInvalidWebhookURL возвращается при создании вебхука, если адрес не http(s) или указывает
+	// во внутреннюю сеть (loopback, частные диапазоны, метаданные облака)
+	ErrInvalidWebhookURL = errors.New("недопустимый адрес вебхука")
+)
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// exportBufferSize — сколько прочитанных из бд фото может ждать записи в ответ
+const exportBufferSize = 100
+
+// ExportPhotos реализует метод PhotoUseCase
+func (uc *photoUseCase) ExportPhotos(ctx context.Context, filter domain.PhotoFilter) (<-chan domain.Photo, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	out := make(chan domain.Photo, exportBufferSize)
+	go func() {
+		defer close(out)
+		// отмена ctx — это ушедший клиент, а не сбой
+		if err := uc.photoStorage.StreamPhotosFromDB(ctx, filter, out); err != nil && ctx.Err() == nil {
+			uc.log(ctx).Error("ошибка выгрузки фото", slog.String("query", filter.Query), slog.Any("error", err))
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// FindPhotosNear реализует метод PhotoUseCase
+func (uc *photoUseCase) FindPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64, page, perPage int) ([]domain.Photo, int64, error) {
+	photos, err := uc.photoStorage.FindPhotosNear(ctx, lat, lon, radiusKm, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка поиска фото рядом с точкой", slog.Float64("lat", lat), slog.Float64("lon", lon), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при поиске фото рядом с точкой: %w", err)
+	}
+	total, err := uc.photoStorage.CountPhotosNear(ctx, lat, lon, radiusKm)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото рядом с точкой", slog.Float64("lat", lat), slog.Float64("lon", lon), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте фото рядом с точкой: %w", err)
+	}
+	uc.log(ctx).Info("найдены фото рядом с точкой",
+		slog.Float64("lat", lat),
+		slog.Float64("lon", lon),
+		slog.Float64("radius_km", radiusKm),
+		slog.Int("count", len(photos)),
+		slog.Int64("total", total),
+	)
+	return photos, total, nil
+}
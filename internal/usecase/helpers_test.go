@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/database/sqlite"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/events"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// testStorages — хранилища на пустой базе SQLite во временном каталоге теста
+type testStorages struct {
+	db     *sqlx.DB
+	photos *sqlite.PhotoStorage
+	users  *sqlite.UserStorage
+}
+
+func newTestStorages(t *testing.T) testStorages {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"), discardLogger())
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return testStorages{
+		db:     db,
+		photos: sqlite.NewPhotoStorage(db, discardLogger()),
+		users:  sqlite.NewUserStorage(db, discardLogger()),
+	}
+}
+
+// testPNG возвращает PNG-изображение width x height
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: 200, G: 40, B: 40, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newImageServer отдаёт body на любой запрос, как CDN источника отдаёт оригинал фото
+func newImageServer(t *testing.T, body []byte) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// memFile — файл в memFileStorage
+type memFile struct {
+	data        []byte
+	contentType string
+	modified    time.Time
+}
+
+// memFileStorage — FileStorage в памяти
+type memFileStorage struct {
+	mu    sync.Mutex
+	files map[string]memFile
+}
+
+func newMemFileStorage() *memFileStorage {
+	return &memFileStorage{files: make(map[string]memFile)}
+}
+
+func (m *memFileStorage) url(key string) string {
+	return "http://localhost:9000/photos/" + key
+}
+
+func (m *memFileStorage) put(key string, data []byte, contentType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[key] = memFile{data: data, contentType: contentType, modified: time.Now()}
+}
+
+func (m *memFileStorage) has(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.files[key]
+	return ok
+}
+
+func (m *memFileStorage) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.files))
+	for key := range m.files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (m *memFileStorage) UploadFile(_ context.Context, key string, r io.Reader, contentType string) (string, int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", 0, err
+	}
+	m.put(key, data, contentType)
+	return m.url(key), int64(len(data)), nil
+}
+
+func (m *memFileStorage) GetFile(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[key]
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", key, ErrFileNotFound)
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+func (m *memFileStorage) DeleteFile(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, key)
+	return nil
+}
+
+func (m *memFileStorage) DeleteFiles(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		_ = m.DeleteFile(ctx, key)
+	}
+	return nil
+}
+
+func (m *memFileStorage) ListFiles(_ context.Context, prefix string) ([]FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var files []FileInfo
+	for key, f := range m.files {
+		if strings.HasPrefix(key, prefix) {
+			files = append(files, FileInfo{Key: key, Size: int64(len(f.data)), LastModified: f.modified,
+				ContentType: f.contentType, URL: m.url(key)})
+		}
+	}
+	return files, nil
+}
+
+func (m *memFileStorage) StatFile(_ context.Context, key string) (FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[key]
+	if !ok {
+		return FileInfo{}, fmt.Errorf("file %s: %w", key, ErrFileNotFound)
+	}
+	return FileInfo{Key: key, Size: int64(len(f.data)), LastModified: f.modified, ContentType: f.contentType, URL: m.url(key)}, nil
+}
+
+func (m *memFileStorage) GeneratePresignedPutURL(_ context.Context, key, _ string, _ int64, _ time.Duration) (string, error) {
+	return m.url(key) + "?X-Amz-Signature=test", nil
+}
+
+// stubFetcher — PhotoFetcher, который возвращает заранее заданные фото
+type stubFetcher struct {
+	photos map[string]domain.Photo // по Unsplash ID
+	search []domain.Photo
+}
+
+func (f *stubFetcher) FetcherName() string { return domain.SourceUnsplash }
+
+func (f *stubFetcher) FetchPhotoByIDFromExternal(_ context.Context, unsplashID string) (*domain.Photo, error) {
+	photo, ok := f.photos[unsplashID]
+	if !ok {
+		return nil, domain.ErrExternalPhotoNotFound
+	}
+	photo.ID = uuid.New()
+	return &photo, nil
+}
+
+func (f *stubFetcher) SearchPhotosFromExternal(context.Context, string, int, int, string, string, string) (domain.PhotoPage, error) {
+	photos := make([]domain.Photo, len(f.search))
+	for i, photo := range f.search {
+		photo.ID = uuid.New()
+		photos[i] = photo
+	}
+	return domain.PhotoPage{Photos: photos, Total: len(photos), TotalPages: 1}, nil
+}
+
+func (f *stubFetcher) ListNewPhotosFromExternal(context.Context, int, int) ([]domain.Photo, error) {
+	return nil, nil
+}
+
+// externalPhoto возвращает фото источника с оригиналом по адресу srv
+func externalPhoto(srv *httptest.Server, unsplashID string) domain.Photo {
+	return domain.Photo{
+		UnsplashID:  unsplashID,
+		Title:       "photo " + unsplashID,
+		AuthorName:  "author",
+		Width:       4,
+		Height:      3,
+		OriginalURL: srv.URL + "/" + unsplashID,
+	}
+}
+
+// recordingPublisher запоминает опубликованные события
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event events.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) created() []domain.PhotoCreatedEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var created []domain.PhotoCreatedEvent
+	for _, e := range p.events {
+		if c, ok := e.(domain.PhotoCreatedEvent); ok {
+			created = append(created, c)
+		}
+	}
+	return created
+}
+
+// useCaseDeps — зависимости photoUseCase в тестах; незаданные необязательные остаются nil
+type useCaseDeps struct {
+	photos  ports.PhotoStorage
+	users   ports.UserStorage
+	fetcher PhotoFetcher
+	stats   StatsFetcher
+	files   FileStorage
+	views   ViewCounter
+	cache   ports.Cache
+	locker  lock.DistributedLocker
+	events  EventPublisher
+}
+
+func newTestPhotoUseCase(d useCaseDeps) PhotoUseCase {
+	return NewPhotoUseCase(d.photos, d.users, nil, d.fetcher, nil, nil, d.stats, d.files, nil, nil,
+		d.views, d.cache, time.Minute, StepTimeouts{}, d.locker, d.events, discardLogger())
+}
+
+// countingViews — ViewCounter, который считает вызовы по ID фото
+type countingViews struct {
+	mu    sync.Mutex
+	count map[uuid.UUID]int
+}
+
+func (v *countingViews) IncrementViews(_ context.Context, id uuid.UUID) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.count == nil {
+		v.count = make(map[uuid.UUID]int)
+	}
+	v.count[id]++
+}
+
+func (v *countingViews) of(id uuid.UUID) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.count[id]
+}
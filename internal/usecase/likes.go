@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// LikePhoto добавляет фото в избранное пользователя
+func (uc *photoUseCase) LikePhoto(ctx context.Context, userID, photoID uuid.UUID) (bool, error) {
+	added, err := uc.photoStorage.LikePhoto(ctx, photoID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			uc.log(ctx).Warn("фото для избранного не найдено", slog.String("photo_id", photoID.String()))
+		} else {
+			uc.log(ctx).Error("ошибка добавления фото в избранное", slog.String("photo_id", photoID.String()), slog.Any("error", err))
+		}
+		return false, fmt.Errorf("usecase: ошибка при добавлении фото %s в избранное: %w", photoID, err)
+	}
+	uc.log(ctx).Info("фото добавлено в избранное",
+		slog.String("photo_id", photoID.String()),
+		slog.String("user_id", userID.String()),
+		slog.Bool("added", added),
+	)
+	return added, nil
+}
+
+// UnlikePhoto убирает фото из избранного пользователя
+func (uc *photoUseCase) UnlikePhoto(ctx context.Context, userID, photoID uuid.UUID) error {
+	if err := uc.photoStorage.UnlikePhoto(ctx, photoID, userID); err != nil {
+		uc.log(ctx).Error("ошибка удаления фото из избранного", slog.String("photo_id", photoID.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при удалении фото %s из избранного: %w", photoID, err)
+	}
+	return nil
+}
+
+// ListLikedPhotos возвращает страницу избранного пользователя
+func (uc *photoUseCase) ListLikedPhotos(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error) {
+	photos, err := uc.photoStorage.ListLikedPhotos(ctx, userID, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения избранного", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении избранного: %w", err)
+	}
+	total, err := uc.photoStorage.CountLikedPhotos(ctx, userID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта избранного", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте избранного: %w", err)
+	}
+	return photos, total, nil
+}
+
+// GetPhotoLikes возвращает лайки фото для карточки
+func (uc *photoUseCase) GetPhotoLikes(ctx context.Context, photoID, userID uuid.UUID) (domain.PhotoLikes, error) {
+	likes, err := uc.photoStorage.GetPhotoLikes(ctx, photoID, userID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения лайков фото", slog.String("photo_id", photoID.String()), slog.Any("error", err))
+		return domain.PhotoLikes{}, fmt.Errorf("usecase: ошибка при получении лайков фото %s: %w", photoID, err)
+	}
+	return likes, nil
+}
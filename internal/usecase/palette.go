@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// paletteSize — сколько доминирующих цветов сохраняется для фото
+const paletteSize = 5
+
+// uploadWithPalette загружает файл в S3 и по ходу загрузки извлекает из того же потока палитру,
+// чтобы не скачивать фото повторно. Ошибка извлечения только логируется: фото сохраняется без палитры
+func (uc *photoUseCase) uploadWithPalette(ctx context.Context, key string, r io.Reader, contentType string) (string, int64, domain.ColorPalette, error) {
+	ctx, cancel := withStepTimeout(ctx, uc.timeouts.Upload)
+	defer cancel()
+
+	if uc.palette == nil {
+		s3URL, size, err := uc.fileStorage.UploadFile(ctx, key, r, contentType)
+		return s3URL, size, nil, err
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan []string, 1)
+	go func() {
+		colors, err := uc.palette.ExtractPalette(ctx, pr, paletteSize)
+		if err != nil {
+			uc.log(ctx).Warn("не удалось извлечь палитру фото", slog.String("s3_key", key), slog.Any("error", err))
+		}
+		// дочитываем поток, иначе загрузка в S3 встанет на записи в pipe
+		_, _ = io.Copy(io.Discard, pr)
+		done <- colors
+	}()
+
+	s3URL, size, err := uc.fileStorage.UploadFile(ctx, key, io.TeeReader(r, pw), contentType)
+	pw.CloseWithError(err) // при nil извлекатель получит io.EOF
+	colors := <-done
+	return s3URL, size, colors, err
+}
+
+// SearchPhotosByColor ищет фото по цвету палитры и считает общее количество совпадений
+func (uc *photoUseCase) SearchPhotosByColor(ctx context.Context, color string, maxDistance float64, page, perPage int) ([]domain.Photo, int64, error) {
+	color, err := domain.NormalizeHexColor(color)
+	if err != nil {
+		return nil, 0, fmt.Errorf("usecase: %w: %w", ErrInvalidColor, err)
+	}
+
+	photos, err := uc.photoStorage.SearchPhotosByColor(ctx, color, maxDistance, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка поиска фото по цвету", slog.String("color", color), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при поиске фото по цвету: %w", err)
+	}
+	total, err := uc.photoStorage.CountPhotosByColor(ctx, color, maxDistance)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото по цвету", slog.String("color", color), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте фото по цвету: %w", err)
+	}
+	uc.log(ctx).Info("найдены фото по цвету", slog.String("color", color), slog.Int("count", len(photos)), slog.Int64("total", total))
+	return photos, total, nil
+}
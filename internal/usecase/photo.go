@@ -3,51 +3,332 @@ package usecase
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/events"
 	"github.com/google/uuid"
 )
 
 // PhotoFetcher определяет интерфейс для получения данных о фотографиях из внешних источников (например, Unsplash API).
 // Этот Fetcher будет принимать данные от Unsplash и маппить их во внутреннюю доменную модель Photo
 type PhotoFetcher interface {
+	// FetcherName — имя источника для логов, например "unsplash"
+	FetcherName() string
+
 	// FetchPhotoByIDFromExternal возвращает ОДНУ Photo (из нашей БД), полученную по ID из Unsplash
 	// Возможно, он сначала сходит на Unsplash, получит данные, сохранит их в БД, а затем вернет
 	FetchPhotoByIDFromExternal(ctx context.Context, unsplashID string) (*domain.Photo, error)
 
-	// SearchPhotosFromExternal ищет фото во внешнем источнике и возвращает список наших доменных Photo
-	SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int) ([]domain.Photo, error)
+	// SearchPhotosFromExternal ищет фото во внешнем источнике и возвращает страницу наших доменных Photo
+	// вместе с общим числом результатов и страниц у источника.
+	// orientation, color и orderBy (latest или relevant) необязательны: пустая строка означает
+	// «без фильтра» и порядок источника по умолчанию
+	SearchPhotosFromExternal(ctx context.Context, query string, page, perPage int, orientation, color, orderBy string) (domain.PhotoPage, error)
 
 	// ListNewPhotosFromExternal получает новые фото из внешнего источника и возвращает список наших доменных Photo
 	ListNewPhotosFromExternal(ctx context.Context, page, perPage int) ([]domain.Photo, error)
 }
 
+// CollectionFetcher получает фото коллекции внешнего источника постранично.
+// Фото закончились, если пришла пустая страница или пройдена последняя из TotalPages
+type CollectionFetcher interface {
+	FetchCollectionPhotos(ctx context.Context, collectionID string, page, perPage int) (domain.PhotoPage, error)
+}
+
+// AuthorFetcher получает фото автора внешнего источника постранично, новые первыми.
+// Фото закончились, если пришла пустая страница или пройдена последняя из TotalPages
+type AuthorFetcher interface {
+	FetchUserPhotos(ctx context.Context, username string, page, perPage int) (domain.PhotoPage, error)
+}
+
+// StatsFetcher получает статистику фото внешнего источника за всё время
+type StatsFetcher interface {
+	FetchPhotoStatistics(ctx context.Context, unsplashID string) (domain.PhotoStats, error)
+}
+
 // FileStorage определяет интерфейс для работы с файловым хранилищем (AWS S3, MinIO)
 // порт для хранения бинарных данных (самих изображений)
 type FileStorage interface {
-	// UploadFile загружает файл в хранилище и возвращает его публичный URL.
+	// UploadFile загружает файл в хранилище и возвращает его публичный URL и число записанных байт.
 	// `key` - это уникальное имя файла в хранилище (например, UUID фото).
 	// `reader` - это источник данных файла (например, тело HTTP-ответа после скачивания).
-	// `contentType` - MIME-тип файла (например, "image/jpeg").
-	UploadFile(ctx context.Context, key string, reader io.Reader, contentType string) (string, error)
+	// `contentType` - MIME-тип файла (например, "image/jpeg"), сохраняется и в метаданных объекта.
+	UploadFile(ctx context.Context, key string, reader io.Reader, contentType string) (string, int64, error)
 
-	// DeleteFile удаляет файл из хранилища по его ключу. (Пока не требуется, но полезно для будущего).
+	// GetFile открывает файл из хранилища для чтения; вызывающий закрывает его.
+	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// DeleteFile удаляет файл из хранилища по его ключу.
 	DeleteFile(ctx context.Context, key string) error
+
+	// DeleteFiles удаляет несколько файлов пачками. Ключи, которые не удалось удалить,
+	// перечисляются в ошибке; остальные удаляются в любом случае.
+	DeleteFiles(ctx context.Context, keys []string) error
+
+	// ListFiles возвращает все файлы, ключи которых начинаются с prefix
+	ListFiles(ctx context.Context, prefix string) ([]FileInfo, error)
+
+	// StatFile возвращает сведения о файле, не скачивая его. Если файла нет, ошибка оборачивает ErrFileNotFound
+	StatFile(ctx context.Context, key string) (FileInfo, error)
+
+	// GeneratePresignedPutURL возвращает ссылку, по которой клиент сам загружает файл key запросом PUT
+	// в течение expiry. Content-Type и размер size входят в подпись: запрос с другим типом
+	// или другим Content-Length хранилище отклонит
+	GeneratePresignedPutURL(ctx context.Context, key, contentType string, size int64, expiry time.Duration) (string, error)
+}
+
+// ColorExtractor извлекает доминирующие цвета изображения
+type ColorExtractor interface {
+	// ExtractPalette читает изображение из r и возвращает до n цветов вида "#rrggbb", самый частый первым
+	ExtractPalette(ctx context.Context, r io.Reader, n int) ([]string, error)
+}
+
+// VectorEmbedder вычисляет эмбеддинг изображения для поиска похожих фото
+type VectorEmbedder interface {
+	// ComputeEmbedding возвращает вектор из domain.EmbeddingDimensions чисел для изображения по адресу imageURL
+	ComputeEmbedding(ctx context.Context, imageURL string) ([]float32, error)
+}
+
+// ViewCounter учитывает просмотры фото. Запись в бд может быть отложена и агрегирована,
+// поэтому IncrementViews не блокируется и ошибок не возвращает
+type ViewCounter interface {
+	IncrementViews(ctx context.Context, id uuid.UUID)
+}
+
+// EventPublisher публикует доменные события (domain.PhotoCreatedEvent, domain.PhotoUpdatedEvent);
+// подписчики — кеш, аналитика и другие побочные эффекты
+type EventPublisher interface {
+	Publish(ctx context.Context, event events.Event)
+}
+
+// UploadPhotoInput — данные фото, загружаемого пользователем.
+// File должен поддерживать Seek: тип и размеры изображения читаются до загрузки в хранилище
+type UploadPhotoInput struct {
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	File        io.ReadSeeker
+}
+
+// UploadIntentInput — фото, которое пользователь загрузит в хранилище сам по подписанной ссылке.
+// ContentType и SizeBytes заявляет клиент; ссылка действует TTL
+type UploadIntentInput struct {
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	ContentType string
+	SizeBytes   int64
+	TTL         time.Duration
+}
+
+// UploadIntentResult — созданная ожидающая загрузка, её pending-фото и подписанная ссылка для PUT файла
+type UploadIntentResult struct {
+	Intent    domain.UploadIntent
+	Photo     domain.Photo
+	UploadURL string
+}
+
+// BatchTagResult — итог пакетного изменения тегов: сколько фото изменено
+// и какие ID не найдены (нет в бд или в корзине)
+type BatchTagResult struct {
+	Updated  int64
+	NotFound []uuid.UUID
+}
+
+// FileInfo — сведения о файле в хранилище. ContentType и URL заполняет только StatFile
+type FileInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ContentType  string
+	URL          string // публичный URL, такой же, как возвращает UploadFile
 }
 
 // PhotoUseCase определяет интерфейс для бизнес-логики работы с фото/видео/аудио/
 type PhotoUseCase interface {
 	// GetOrCreatePhotoByUnsplashID ищет фото по ID от Unsplash.
-	// Если оно уже есть в бд, возвращает его. Иначе получает от Unsplash, сохраняет в бд и возвращает
+	// Если оно уже есть в бд, возвращает его. Иначе получает от Unsplash, сохраняет в бд и возвращает.
+	// Если такого фото нет в Unsplash, ошибка оборачивает domain.ErrExternalPhotoNotFound,
+	// если оно в корзине — только domain.ErrPhotoNotFound
 	GetOrCreatePhotoByUnsplashID(ctx context.Context, unsplashID string) (*domain.Photo, error)
 
 	// SearchAndSavePhotos ищет фото по запросу пользователя.
-	// Результаты сохраняются в бд, и возвращается список сохраненных фото
-	SearchAndSavePhotos(ctx context.Context, query string, page, perPage int) ([]domain.Photo, error)
+	// Результаты сохраняются в бд; возвращаются сохранённые фото и общее число результатов
+	// и страниц у внешнего источника.
+	// orientation и color передаются во внешний API, minWidth и minHeight (0 — без ограничения)
+	// отсекают слишком маленькие фото до загрузки. source (domain.SearchSourceServer,
+	// domain.SearchSourceWorker или domain.SearchSourceGRPC) сохраняется в истории поиска
+	SearchAndSavePhotos(ctx context.Context, query string, page, perPage int, orientation, color, orderBy string, minWidth, minHeight int, source string) (domain.PhotoPage, error)
+
+	// UploadPhoto сохраняет фото, загруженное пользователем (JPEG, PNG, WebP или GIF).
+	// Для других типов возвращает ошибку, оборачивающую ErrUnsupportedImageType
+	UploadPhoto(ctx context.Context, in UploadPhotoInput) (*domain.Photo, error)
+
+	// CreateUploadIntent начинает загрузку фото напрямую в хранилище: сохраняет ожидающую загрузку
+	// и фото в статусе pending, невидимое до подтверждения, и подписывает ссылку для PUT файла заявленного типа и размера. Для типов, кроме JPEG, PNG, WebP и GIF,
+	// ошибка оборачивает ErrUnsupportedImageType
+	CreateUploadIntent(ctx context.Context, in UploadIntentInput) (*UploadIntentResult, error)
+
+	// CompleteUpload проверяет, что файл ожидающей загрузки id есть в хранилище и совпадает с заявленным,
+	// и переводит pending-фото с тем же ID в active. Если загрузки нет, она чужая или истекла, ошибка оборачивает
+	// domain.ErrUploadIntentNotFound; если файл ещё не загружен — ErrUploadNotReceived;
+	// если размер или тип другие — ErrUploadMismatch
+	CompleteUpload(ctx context.Context, userID, id uuid.UUID) (*domain.Photo, error)
+
+	// CleanupExpiredUploadIntents удаляет брошенные загрузки с истёкшей ссылкой и их файлы.
+	// Возвращает количество удалённых загрузок
+	CleanupExpiredUploadIntents(ctx context.Context) (int, error)
 
-	// GetPhotoDetailsFromDB получает детали фото из нашей бд по нашему внутреннему ID
+	// RefreshPhotoMetadata заново запрашивает фото из Unsplash и обновляет в бд лайки, просмотры,
+	// скачивания и описание. Файл повторно не скачивается. Если фото нет в бд (или оно в корзине),
+	// ошибка оборачивает domain.ErrPhotoNotFound, если его удалили из Unsplash — domain.ErrExternalPhotoNotFound
+	RefreshPhotoMetadata(ctx context.Context, unsplashID string) (*domain.Photo, error)
+
+	// AutocompletePhotos возвращает до limit подсказок (названия фото и имена авторов) по префиксу
+	AutocompletePhotos(ctx context.Context, prefix string, limit int) ([]string, error)
+
+	// GetTagCloud возвращает до limit самых частых тегов с числом фото. Результат кешируется на tagCloudCacheTTL
+	GetTagCloud(ctx context.Context, limit int) ([]domain.TagCount, error)
+
+	// SearchTags возвращает до limit тегов, начинающихся с prefix, в алфавитном порядке
+	SearchTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error)
+
+	// BatchUpdateTags снимает с фото photoIDs теги removeTags и добавляет addTags одной транзакцией.
+	// Не найденные фото не считаются ошибкой и возвращаются в BatchTagResult.NotFound
+	BatchUpdateTags(ctx context.Context, photoIDs []uuid.UUID, addTags, removeTags []string) (BatchTagResult, error)
+
+	// ListSearchHistory возвращает страницу истории поиска во внешнем источнике и общее число записей
+	ListSearchHistory(ctx context.Context, page, perPage int) ([]domain.SearchQuery, int64, error)
+
+	// SuggestSearchQueries возвращает до limit самых частых прошлых запросов, начинающихся с prefix
+	SuggestSearchQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error)
+
+	// GetPhotoDetailsFromDB получает детали фото из нашей бд по нашему внутреннему ID. Просмотр не учитывается:
+	// метод вызывают и служебные сценарии (подпись, обновление, восстановление, gRPC), просмотр показа
+	// клиенту учитывает CountView. Если фото нет (или оно в корзине), ошибка оборачивает domain.ErrPhotoNotFound
 	GetPhotoDetailsFromDB(ctx context.Context, id uuid.UUID) (*domain.Photo, error)
 
-	// GetRecentPhotosFromDB получает последние фото из нашей бд
-	GetRecentPhotosFromDB(ctx context.Context, page, perPage int) ([]domain.Photo, error)
+	// CountView учитывает просмотр фото, показанного клиенту, через отложенный счётчик ViewCounter;
+	// без счётчика ничего не делает. В отличие от RecordView не обращается к бд и ошибок не возвращает
+	CountView(ctx context.Context, id uuid.UUID)
+
+	// GetPhotoDetailsIncludingDeleted — как GetPhotoDetailsFromDB, но находит и фото в корзине.
+	// Нужен администраторам; просмотр не учитывается
+	GetPhotoDetailsIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.Photo, error)
+
+	// CleanupOrphanedObjects удаляет из файлового хранилища фото Unsplash, для которых нет записи в бд.
+	// Файлы моложе minAge не трогаются: они могут принадлежать ещё не завершённому сохранению.
+	// Возвращает количество удалённых файлов
+	CleanupOrphanedObjects(ctx context.Context, minAge time.Duration) (int, error)
+
+	// GetRecentPhotosFromDB получает последние фото из нашей бд и общее количество фото
+	// при includeTags теги загружаются сразу для всей страницы
+	GetRecentPhotosFromDB(ctx context.Context, page, perPage int, includeTags bool, sort domain.PhotoSort) ([]domain.Photo, int64, error)
+
+	// SearchPhotosInDB ищет среди уже сохранённых фото, не обращаясь к внешнему API.
+	// filter ограничивает минимальные лайки и размер (нулевой — без ограничений).
+	// Возвращает страницу результатов и общее количество совпадений
+	SearchPhotosInDB(ctx context.Context, query string, page, perPage int, filter domain.PhotoSearchFilter, sort domain.PhotoSort) ([]domain.Photo, int64, error)
+
+	// SearchPhotosByColor ищет фото, в палитре которых есть цвет не дальше maxDistance от color
+	// (hex "RRGGBB", решётка необязательна), и считает общее количество совпадений
+	SearchPhotosByColor(ctx context.Context, color string, maxDistance float64, page, perPage int) ([]domain.Photo, int64, error)
+
+	// SoftDeletePhoto перемещает фото в корзину; удалённые фото не видны в выдаче.
+	// Если фото не найдено или уже удалено, ошибка оборачивает domain.ErrPhotoNotFound
+	SoftDeletePhoto(ctx context.Context, id uuid.UUID) error
+
+	// RecordView и RecordDownload увеличивают локальные счётчики просмотров и скачиваний фото.
+	// Для несуществующего или удалённого фото возвращают domain.ErrPhotoNotFound
+	RecordView(ctx context.Context, id uuid.UUID) error
+	RecordDownload(ctx context.Context, id uuid.UUID) error
+
+	// LikePhoto добавляет фото в избранное пользователя. Повторный лайк не ошибка, added == false.
+	// Для несуществующего или удалённого фото ошибка оборачивает domain.ErrPhotoNotFound
+	LikePhoto(ctx context.Context, userID, photoID uuid.UUID) (added bool, err error)
+	// UnlikePhoto убирает фото из избранного пользователя
+	UnlikePhoto(ctx context.Context, userID, photoID uuid.UUID) error
+	// ListLikedPhotos возвращает страницу избранного пользователя и его общий размер
+	ListLikedPhotos(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error)
+	// GetPhotoLikes возвращает число лайков фото и лайкнул ли его userID (uuid.Nil — аноним)
+	GetPhotoLikes(ctx context.Context, photoID, userID uuid.UUID) (domain.PhotoLikes, error)
+
+	// RestorePhoto возвращает фото из корзины и отдаёт восстановленное фото
+	RestorePhoto(ctx context.Context, id uuid.UUID) (*domain.Photo, error)
+
+	// ListPhotosByAuthor получает фото автора (имя без учёта регистра) и их общее количество
+	ListPhotosByAuthor(ctx context.Context, authorName string, page, perPage int) ([]domain.Photo, int64, error)
+
+	// ListPhotosByUser получает фото, сохранённые пользователем, и их общее количество.
+	// Для несуществующего пользователя ошибка оборачивает domain.ErrUserNotFound
+	ListPhotosByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error)
+
+	// GetPhotosByIDs получает фото по списку ID в порядке ids.
+	// Если хотя бы одного фото нет (или оно в корзине), ошибка оборачивает domain.ErrPhotoNotFound
+	GetPhotosByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Photo, error)
+
+	// OpenPhotoFile открывает файл фото в S3 для потоковой отдачи; вызывающий закрывает файл
+	OpenPhotoFile(ctx context.Context, photo domain.Photo) (io.ReadCloser, error)
+
+	// ListDeletedPhotos получает фото из корзины и их общее количество
+	ListDeletedPhotos(ctx context.Context, page, perPage int) ([]domain.Photo, int64, error)
+
+	// PurgeDeletedPhotos окончательно удаляет фото, пролежавшие в корзине дольше retention, и их файлы.
+	// Возвращает количество удалённых фото
+	PurgeDeletedPhotos(ctx context.Context, retention time.Duration) (int, error)
+
+	// UpdatePopularityScores пересчитывает оценку популярности всех фото вне корзины.
+	// Возвращает количество обновлённых фото
+	UpdatePopularityScores(ctx context.Context) (int64, error)
+
+	// GetTrendingPhotos получает до limit самых популярных фото по последнему пересчёту оценки
+	GetTrendingPhotos(ctx context.Context, limit int) ([]domain.Photo, error)
+
+	// FindPhotosNear ищет фото, снятые не дальше radiusKm от точки (lat, lon), ближайшие первыми,
+	// и считает общее количество совпадений. Фото без координат не попадают в выдачу
+	FindPhotosNear(ctx context.Context, lat, lon float64, radiusKm float64, page, perPage int) ([]domain.Photo, int64, error)
+
+	// ImportUnsplashCollection загружает фото коллекции Unsplash страница за страницей, начиная со startPage,
+	// пока не придёт пустая страница, и возвращает число сохранённых фото. Уже сохранённые фото пропускаются.
+	// При исчерпании лимита Unsplash ждёт его сброса и повторяет ту же страницу.
+	// Если коллекции нет, ошибка оборачивает domain.ErrExternalCollectionNotFound,
+	// если источник Unsplash не настроен — ErrCollectionImportUnavailable
+	ImportUnsplashCollection(ctx context.Context, collectionID string, startPage, perPage int) (int, error)
+
+	// RefreshPhotoStats обновляет views_count и downloads_count фото из статистики Unsplash.
+	// Если фото нет у нас или в Unsplash, ошибка оборачивает domain.ErrPhotoNotFound
+	// (во втором случае — domain.ErrExternalPhotoNotFound), если Unsplash не настроен — ErrStatsUnavailable
+	RefreshPhotoStats(ctx context.Context, unsplashID string) (*domain.Photo, error)
+
+	// RefreshRecentPhotoStats обновляет статистику до limit фото Unsplash, просмотренных последними,
+	// выдерживая паузу spacing между запросами, и возвращает число обновлённых фото.
+	// Ошибка одного фото не прерывает обход; исчерпанный лимит Unsplash его прерывает
+	RefreshRecentPhotoStats(ctx context.Context, limit int, spacing time.Duration) (int, error)
+
+	// CheckUnsplashAuthor проверяет, что автор username есть в Unsplash.
+	// Если автора нет, ошибка оборачивает domain.ErrExternalAuthorNotFound,
+	// если источник Unsplash не настроен — ErrAuthorImportUnavailable
+	CheckUnsplashAuthor(ctx context.Context, username string) error
+
+	// ImportAuthorPhotos загружает фото автора Unsplash страница за страницей, пока не придёт пустая страница
+	// или не будет просмотрено maxPhotos фото (0 — без ограничения), и возвращает число сохранённых фото.
+	// Уже сохранённые фото пропускаются, но учитываются в maxPhotos. Лимит Unsplash выдерживается
+	// так же, как в ImportUnsplashCollection. Ошибки — как у CheckUnsplashAuthor
+	ImportAuthorPhotos(ctx context.Context, username string, perPage, maxPhotos int) (int, error)
+
+	// ComputePhotoEmbedding вычисляет эмбеддинг фото внешним сервисом и сохраняет его в бд.
+	// Если фото нет (или оно в корзине), ошибка оборачивает domain.ErrPhotoNotFound,
+	// если сервис эмбеддингов не настроен — ErrEmbeddingUnavailable
+	ComputePhotoEmbedding(ctx context.Context, id uuid.UUID) error
+
+	// FindSimilarPhotos возвращает до limit фото, ближайших к фото id по эмбеддингу, самые похожие первыми.
+	// Если фото нет, ошибка оборачивает domain.ErrPhotoNotFound, если его эмбеддинг ещё не вычислен —
+	// domain.ErrEmbeddingNotReady
+	FindSimilarPhotos(ctx context.Context, id uuid.UUID, limit int) ([]domain.Photo, error)
+
+	// ExportPhotos выгружает фото из выборки filter вместе с тегами, старые первыми. Фото приходят
+	// в канал по мере чтения из бд; канал закрывается в конце выборки, при ошибке бд (она логируется)
+	// и при отмене ctx. Если From не раньше To, возвращается ErrInvalidDateRange
+	ExportPhotos(ctx context.Context, filter domain.PhotoFilter) (<-chan domain.Photo, error)
 }
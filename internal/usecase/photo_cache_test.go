@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+// memCache — ports.Cache в памяти без истечения ключей
+type memCache struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func (c *memCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.items[key]
+	if !ok {
+		return nil, ports.ErrCacheMiss
+	}
+	return value, nil
+}
+
+func (c *memCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.items == nil {
+		c.items = make(map[string][]byte)
+	}
+	c.items[key] = value
+	return nil
+}
+
+func (c *memCache) Del(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+func (c *memCache) has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.items[key]
+	return ok
+}
+
+// lookupCountingStorage считает обращения к бд за фото по Unsplash ID
+type lookupCountingStorage struct {
+	ports.PhotoStorage
+	lookups int
+}
+
+func (s *lookupCountingStorage) GetPhotosByUnsplashIDFromDB(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+	s.lookups++
+	return s.PhotoStorage.GetPhotosByUnsplashIDFromDB(ctx, unsplashID)
+}
+
+func TestGetOrCreatePhotoByUnsplashID_Cache(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+	storage := &lookupCountingStorage{PhotoStorage: st.photos}
+	cache := &memCache{}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: storage, users: st.users, fetcher: fetcher,
+		stats: &stubStats{}, files: newMemFileStorage(), cache: cache})
+	key := photoCacheKey("abc")
+
+	created, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if err != nil {
+		t.Fatalf("create photo: %v", err)
+	}
+
+	t.Run("miss reads the db and fills the cache", func(t *testing.T) {
+		storage.lookups = 0
+		photo, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+		if err != nil {
+			t.Fatalf("GetOrCreatePhotoByUnsplashID: %v", err)
+		}
+		if photo.ID != created.ID || storage.lookups != 1 {
+			t.Errorf("photo %s after %d db lookups, want %s after 1", photo.ID, storage.lookups, created.ID)
+		}
+		if !cache.has(key) {
+			t.Error("photo was not cached")
+		}
+	})
+
+	t.Run("hit skips the db", func(t *testing.T) {
+		storage.lookups = 0
+		photo, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+		if err != nil {
+			t.Fatalf("GetOrCreatePhotoByUnsplashID: %v", err)
+		}
+		if photo.ID != created.ID || photo.Title != created.Title {
+			t.Errorf("cached photo = %s %q, want %s %q", photo.ID, photo.Title, created.ID, created.Title)
+		}
+		if storage.lookups != 0 {
+			t.Errorf("db lookups = %d, want 0 on a cache hit", storage.lookups)
+		}
+	})
+
+	t.Run("update invalidates", func(t *testing.T) {
+		if _, err := uc.RefreshPhotoStats(ctx, "abc"); err != nil {
+			t.Fatalf("RefreshPhotoStats: %v", err)
+		}
+		if cache.has(key) {
+			t.Error("photo is still cached after an update")
+		}
+		if _, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc"); err != nil {
+			t.Fatalf("GetOrCreatePhotoByUnsplashID: %v", err)
+		}
+	})
+
+	t.Run("delete invalidates", func(t *testing.T) {
+		if !cache.has(key) {
+			t.Fatal("photo is not cached before the delete")
+		}
+		if err := uc.SoftDeletePhoto(ctx, created.ID); err != nil {
+			t.Fatalf("SoftDeletePhoto: %v", err)
+		}
+		if cache.has(key) {
+			t.Error("photo is still cached after the delete")
+		}
+		if _, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc"); !errors.Is(err, domain.ErrPhotoNotFound) {
+			t.Errorf("err = %v, want domain.ErrPhotoNotFound for a deleted photo", err)
+		}
+	})
+}
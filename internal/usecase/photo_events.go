@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/events"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/messaging/payloads"
+)
+
+// publish публикует доменное событие, если издатель событий задан
+func (uc *photoUseCase) publish(ctx context.Context, event events.Event) {
+	if uc.events == nil {
+		return
+	}
+	uc.events.Publish(ctx, event)
+}
+
+// NewPhotoCacheWarmer возвращает подписчика на domain.PhotoCreatedEvent и domain.PhotoUpdatedEvent,
+// который кладёт фото в кеш, чтобы первый запрос за ним не шёл в бд.
+// Фото, загруженные пользователями, пропускаются: кеш ключуется по Unsplash ID
+func NewPhotoCacheWarmer(cache ports.Cache, ttl time.Duration, log *slog.Logger) events.Handler {
+	return func(ctx context.Context, event events.Event) {
+		var photo domain.Photo
+		switch e := event.(type) {
+		case domain.PhotoCreatedEvent:
+			photo = e.Photo
+		case domain.PhotoUpdatedEvent:
+			photo = e.Photo
+		default:
+			return
+		}
+		if photo.UnsplashID == "" {
+			return
+		}
+
+		if err := storePhotoInCache(ctx, cache, ttl, &photo); err != nil {
+			logger.FromContext(ctx, log).Warn("не удалось прогреть кеш фото",
+				slog.String("event_type", event.EventType()),
+				slog.String("unsplash_id", photo.UnsplashID),
+				slog.Any("error", err),
+			)
+			return
+		}
+		logger.FromContext(ctx, log).Debug("кеш фото прогрет",
+			slog.String("event_type", event.EventType()),
+			slog.String("unsplash_id", photo.UnsplashID),
+		)
+	}
+}
+
+// NewEmbeddingScheduler возвращает подписчика на domain.PhotoCreatedEvent, который ставит
+// в очередь задачу вычисления эмбеддинга нового фото. Ошибка публикации только логируется:
+// без эмбеддинга фото сохраняется, но не участвует в поиске похожих
+func NewEmbeddingScheduler(publisher ports.EmbeddingPublisher, log *slog.Logger) events.Handler {
+	return func(ctx context.Context, event events.Event) {
+		e, ok := event.(domain.PhotoCreatedEvent)
+		if !ok {
+			return
+		}
+
+		if err := publisher.PublishEmbeddingRequest(ctx, payloads.EmbeddingPayload{PhotoID: e.Photo.ID}); err != nil {
+			logger.FromContext(ctx, log).Warn("не удалось поставить в очередь вычисление эмбеддинга",
+				slog.String("photo_id", e.Photo.ID.String()),
+				slog.Any("error", err),
+			)
+			return
+		}
+		logger.FromContext(ctx, log).Debug("вычисление эмбеддинга поставлено в очередь",
+			slog.String("photo_id", e.Photo.ID.String()),
+		)
+	}
+}
@@ -3,132 +3,340 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/GoArmGo/MediaApp/internal/core/ports"
 	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/mediatype"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	// unsplashPhotosPrefix — префикс ключей S3 для фото, полученных из Unsplash
+	unsplashPhotosPrefix = "unsplash-photos/"
+
+	// orphanCheckBatchSize — сколько ключей проверяем в БД одним запросом при очистке
+	orphanCheckBatchSize = 500
+
+	// compensationTimeout — время на удаление файла, если сохранить фото в БД не удалось
+	compensationTimeout = 10 * time.Second
+)
+
+var tracer = tracing.Tracer("usecase")
+
 // photoUseCase implements PhotoUseCase
 type photoUseCase struct {
 	photoStorage ports.PhotoStorage
 	userStorage  ports.UserStorage
+	history      ports.SearchHistoryStorage // nil — история поиска не ведётся
 	photoFetcher PhotoFetcher
+	collections  CollectionFetcher // nil — импорт коллекций недоступен
+	authors      AuthorFetcher     // nil — импорт фото авторов недоступен
+	stats        StatsFetcher      // nil — статистика фото не обновляется
 	fileStorage  FileStorage
+	palette      ColorExtractor // nil — палитра не извлекается
+	embedder     VectorEmbedder // nil — эмбеддинги не вычисляются
+	views        ViewCounter    // nil — CountView ничего не делает
+	cache        ports.Cache    // nil — кеш выключен, все запросы идут в бд
+	cacheTTL     time.Duration
+	timeouts     StepTimeouts
+	locker       lock.DistributedLocker // nil — фото обрабатываются без распределённой блокировки
+	events       EventPublisher         // nil — события не публикуются
 	logger       *slog.Logger
 }
 
 // NewPhotoUseCase создает новый экземпляр PhotoUseCase
-// принимает реализации портов PhotoStorage и PhotoFetcher
+// принимает реализации портов PhotoStorage и PhotoFetcher.
+// cache может быть nil: тогда фото всегда читаются из бд.
+// timeouts ограничивают запросы к источнику, скачивание и загрузку в S3; нулевые — без ограничений.
+// eventPublisher может быть nil: тогда события о сохранении и обновлении фото не публикуются.
+// searchHistory может быть nil: тогда поиски не сохраняются, а история пуста.
+// colorExtractor может быть nil: тогда фото сохраняются без палитры.
+// embedder может быть nil: тогда ComputePhotoEmbedding возвращает ErrEmbeddingUnavailable.
+// viewCounter может быть nil: тогда чтение фото не увеличивает views_count.
+// collectionFetcher может быть nil: тогда импорт коллекций возвращает ErrCollectionImportUnavailable.
+// authorFetcher может быть nil: тогда импорт фото авторов возвращает ErrAuthorImportUnavailable.
+// statsFetcher может быть nil: тогда обновление статистики возвращает ErrStatsUnavailable.
+// locker может быть nil: тогда параллельные запросы одного фото не согласуются между экземплярами
 func NewPhotoUseCase(
 	photoStorage ports.PhotoStorage,
 	userStorage ports.UserStorage,
+	searchHistory ports.SearchHistoryStorage,
 	photoFetcher PhotoFetcher,
+	collectionFetcher CollectionFetcher,
+	authorFetcher AuthorFetcher,
+	statsFetcher StatsFetcher,
 	fileStorage FileStorage,
+	colorExtractor ColorExtractor,
+	embedder VectorEmbedder,
+	viewCounter ViewCounter,
+	cache ports.Cache,
+	cacheTTL time.Duration,
+	timeouts StepTimeouts,
+	locker lock.DistributedLocker,
+	eventPublisher EventPublisher,
 	logger *slog.Logger,
 ) PhotoUseCase {
 	return &photoUseCase{
 		photoStorage: photoStorage,
 		userStorage:  userStorage,
+		history:      searchHistory,
 		photoFetcher: photoFetcher,
+		collections:  collectionFetcher,
+		authors:      authorFetcher,
+		stats:        statsFetcher,
 		fileStorage:  fileStorage,
+		palette:      colorExtractor,
+		embedder:     embedder,
+		views:        viewCounter,
+		cache:        cache,
+		cacheTTL:     cacheTTL,
+		timeouts:     timeouts,
+		locker:       locker,
+		events:       eventPublisher,
 		logger:       logger,
 	}
 }
 
 // GetOrCreatePhotoByUnsplashID получает фото по его Unsplash ID
 // Сначала ищет в локальной бд. Если не найдено, получает из Unsplash API,
-// загружает в S3, сохраняет в бд и возвращает
-func (uc *photoUseCase) GetOrCreatePhotoByUnsplashID(ctx context.Context, unsplashID string) (*domain.Photo, error) {
+// загружает в S3, сохраняет в бд и возвращает.
+// Если фото уже обрабатывает другой экземпляр, ошибка оборачивает lock.ErrNotAcquired
+func (uc *photoUseCase) GetOrCreatePhotoByUnsplashID(ctx context.Context, unsplashID string) (_ *domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.GetOrCreatePhotoByUnsplashID",
+		trace.WithAttributes(attribute.String("unsplash_id", unsplashID)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// 0. Сначала смотрим в кеш
+	if photo := uc.getCachedPhoto(ctx, unsplashID); photo != nil {
+		span.SetAttributes(attribute.Bool("cache_hit", true))
+		return photo, nil
+	}
 
-	uc.logger.Info("поиск фото в локальной БД", slog.String("unsplash_id", unsplashID))
+	// Проверка «есть ли фото в бд» и его создание идут под блокировкой, чтобы два экземпляра
+	// не скачивали и не сохраняли одно и то же фото одновременно
+	unlock, err := uc.lockPhoto(ctx, unsplashID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	uc.log(ctx).Info("поиск фото в локальной БД", slog.String("unsplash_id", unsplashID))
 	// 1. Попытка получить фото из собственной базы данных
 	photo, err := uc.photoStorage.GetPhotosByUnsplashIDFromDB(ctx, unsplashID)
 
 	if err != nil && err != sql.ErrNoRows { // Проверяем на ошибку, кроме "нет строк"
-		uc.logger.Error("ошибка при получении фото из БД", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		uc.log(ctx).Error("ошибка при получении фото из БД", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
 		return nil, fmt.Errorf("usecase: ошибка при получении фото из БД по Unsplash ID: %w", err)
 	}
 	if photo != nil {
 		// Фото найдено в бд, возвращаем его
-		uc.logger.Debug("фото найдено в локальной БД", slog.String("photo_id", photo.ID.String()))
+		uc.log(ctx).Debug("фото найдено в локальной БД", slog.String("photo_id", photo.ID.String()))
+		uc.cachePhoto(ctx, photo)
 		return photo, nil
 	}
 
+	// Мягко удалённое фото не загружаем заново: оно вернётся только через восстановление
+	deleted, err := uc.photoStorage.ExistsByUnsplashIDs(ctx, []string{unsplashID})
+	if err != nil {
+		uc.log(ctx).Error("ошибка при проверке фото в БД", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при проверке фото в БД по Unsplash ID: %w", err)
+	}
+	if _, ok := deleted[unsplashID]; ok {
+		uc.log(ctx).Info("фото удалено в корзину", slog.String("unsplash_id", unsplashID))
+		return nil, fmt.Errorf("usecase: фото с Unsplash ID %s удалено: %w", unsplashID, domain.ErrPhotoNotFound)
+	}
+
 	// 2. Если фото не найдено в бд, получаем его из Unsplash API
-	uc.logger.Info("фото не найдено в БД, запрашиваем из Unsplash API", slog.String("unsplash_id", unsplashID))
+	uc.log(ctx).Info("фото не найдено в БД, запрашиваем из Unsplash API", slog.String("unsplash_id", unsplashID))
 
-	unsplashPhoto, err := uc.photoFetcher.FetchPhotoByIDFromExternal(ctx, unsplashID)
+	fetchCtx, cancelFetch := withStepTimeout(ctx, uc.timeouts.Fetch)
+	defer cancelFetch()
+	unsplashPhoto, err := uc.photoFetcher.FetchPhotoByIDFromExternal(fetchCtx, unsplashID)
+	if errors.Is(err, domain.ErrExternalPhotoNotFound) {
+		uc.log(ctx).Warn("фото не найдено во внешнем API", slog.String("unsplash_id", unsplashID))
+		return nil, fmt.Errorf("usecase: %w", err)
+	}
 	if err != nil {
-		uc.logger.Error("ошибка при запросе в Unsplash API", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		uc.log(ctx).Error("ошибка при запросе в Unsplash API", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
 		return nil, fmt.Errorf("usecase: ошибка при получении фото из Unsplash API по ID %s: %w", unsplashID, err)
 	}
 	if unsplashPhoto == nil {
-		uc.logger.Warn("фото не найдено во внешнем API", slog.String("unsplash_id", unsplashID))
-		return nil, fmt.Errorf("usecase: фото с Unsplash ID %s не найдено во внешнем API", unsplashID)
+		uc.log(ctx).Warn("фото не найдено во внешнем API", slog.String("unsplash_id", unsplashID))
+		return nil, fmt.Errorf("usecase: фото с Unsplash ID %s: %w", unsplashID, domain.ErrExternalPhotoNotFound)
 	}
 
 	// 3. Скачиваем оригинальное фото и загружаем его в S3
-	uc.logger.Info("скачиваем оригинальное фото", slog.String("url", unsplashPhoto.OriginalURL))
-	resp, err := http.Get(unsplashPhoto.OriginalURL)
+	uc.log(ctx).Info("скачиваем оригинальное фото", slog.String("url", unsplashPhoto.OriginalURL))
+	span.AddEvent("download original photo", trace.WithAttributes(attribute.String("url", unsplashPhoto.OriginalURL)))
+	resp, err := uc.downloadOriginal(ctx, unsplashPhoto.OriginalURL)
 	if err != nil {
-		uc.logger.Error("ошибка при скачивании фото", slog.String("url", unsplashPhoto.OriginalURL), slog.Any("error", err))
+		uc.log(ctx).Error("ошибка при скачивании фото", slog.String("url", unsplashPhoto.OriginalURL), slog.Any("error", err))
 		return nil, fmt.Errorf("usecase: ошибка при скачивании фото с Unsplash URL %s: %w", unsplashPhoto.OriginalURL, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		uc.logger.Warn("неуспешный статус ответа", slog.Int("status_code", resp.StatusCode))
+		uc.log(ctx).Warn("неуспешный статус ответа", slog.Int("status_code", resp.StatusCode))
 		return nil, fmt.Errorf("usecase: неуспешный статус при скачивании фото с Unsplash: %s", resp.Status)
 	}
 
-	// Используем resp.Body напрямую как io.Reader
-	fileStream := resp.Body
-
-	// Определяем Content-Type для S3
-	contentType := resp.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
+	// Тип определяем по содержимому: вместо фото CDN иногда отдаёт HTML-страницу ошибки
+	contentType, fileStream, err := uc.sniffDownloadedImage(ctx, resp.Body, unsplashPhoto.OriginalURL)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: фото %s: %w", unsplashPhoto.UnsplashID, err)
 	}
 
-	// Генерируем уникальный ключ для S3 на основе UnsplashID или нашего внутреннего ID
-	// Используем UnsplashID, так как это уникальный идентификатор фото во внешней системе,
-	// и это упрощает его связывание с файлом в S3
-	s3Key := fmt.Sprintf("unsplash-photos/%s", unsplashPhoto.UnsplashID) // Можно добавить расширение: ".jpg"
+	// Ключ S3 содержит и UnsplashID, и наш ID записи: если фото параллельно сохранит другой запрос,
+	// его файл останется нетронутым, а наш можно будет удалить
+	if unsplashPhoto.ID == uuid.Nil {
+		unsplashPhoto.ID = uuid.New()
+	}
+	s3Key := externalFileKey(*unsplashPhoto)
+	span.SetAttributes(attribute.String("s3_key", s3Key))
 
-	s3URL, err := uc.fileStorage.UploadFile(ctx, s3Key, fileStream, contentType)
+	s3URL, size, colors, err := uc.uploadWithPalette(ctx, s3Key, fileStream, contentType)
 	if err != nil {
-		uc.logger.Error("ошибка загрузки в S3", slog.String("unsplash_id", unsplashPhoto.UnsplashID), slog.Any("error", err))
+		uc.log(ctx).Error("ошибка загрузки в S3", slog.String("unsplash_id", unsplashPhoto.UnsplashID), slog.Any("error", err))
 		return nil, fmt.Errorf("usecase: ошибка загрузки фото %s в S3: %w", unsplashPhoto.UnsplashID, err)
 	}
 	unsplashPhoto.S3URL = s3URL // Сохраняем полученный S3 URL
+	unsplashPhoto.SizeBytes = size
+	unsplashPhoto.MimeType = contentType
+	unsplashPhoto.DominantColors = colors
 
 	// 4. Сохраняем полученное и обработанное фото в собственной бд
 	// photo.UserID будет установлен в SavePhoto
 	systemUserID, err := uc.userStorage.GetOrCreateSystemUser(ctx)
 	if err != nil {
-		uc.logger.Error("ошибка получения системного пользователя", slog.Any("error", err))
+		uc.log(ctx).Error("ошибка получения системного пользователя", slog.Any("error", err))
+		uc.deleteUploadedFile(ctx, s3Key)
 		return nil, fmt.Errorf("usecase: ошибка при сохранении фото %s в локальной БД: %w", unsplashPhoto.ID, err)
 	}
 
 	unsplashPhoto.UserID = systemUserID
 
-	err = uc.photoStorage.SavePhoto(ctx, unsplashPhoto)
+	created, err := uc.photoStorage.SavePhotoTx(ctx, unsplashPhoto)
 	if err != nil {
-		uc.logger.Error("ошибка сохранения фото в БД", slog.String("photo_id", unsplashPhoto.ID.String()), slog.Any("error", err))
+		uc.log(ctx).Error("ошибка сохранения фото в БД", slog.String("photo_id", unsplashPhoto.ID.String()), slog.Any("error", err))
+		uc.deleteUploadedFile(ctx, s3Key)
 		return nil, fmt.Errorf("usecase: ошибка при сохранении фото %s в локальной БД: %w", unsplashPhoto.ID, err)
 	}
+	if !created {
+		// фото успел сохранить параллельный запрос (без блокировки или после её истечения):
+		// unsplashPhoto уже заполнено его строкой, а наш файл в S3 ни на что не ссылается
+		uc.log(ctx).Info("фото уже сохранено параллельно", slog.String("unsplash_id", unsplashID),
+			slog.String("photo_id", unsplashPhoto.ID.String()))
+		uc.deleteUploadedFile(ctx, s3Key)
+		if unsplashPhoto.DeletedAt != nil {
+			return nil, fmt.Errorf("usecase: фото с Unsplash ID %s удалено: %w", unsplashID, domain.ErrPhotoNotFound)
+		}
+		uc.cachePhoto(ctx, unsplashPhoto)
+		return unsplashPhoto, nil
+	}
 
-	uc.logger.Info("фото успешно сохранено", slog.String("photo_id", unsplashPhoto.ID.String()))
+	uc.log(ctx).Info("фото успешно сохранено", slog.String("photo_id", unsplashPhoto.ID.String()))
+	uc.publish(ctx, domain.PhotoCreatedEvent{EventMeta: domain.NewEventMeta(), Photo: *unsplashPhoto})
 	return unsplashPhoto, nil
 }
 
+// RefreshPhotoMetadata обновляет метаданные уже сохранённого фото из Unsplash
+func (uc *photoUseCase) RefreshPhotoMetadata(ctx context.Context, unsplashID string) (_ *domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.RefreshPhotoMetadata",
+		trace.WithAttributes(attribute.String("unsplash_id", unsplashID)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// Обновляем только существующие фото: без записи в бд нет и файла в S3
+	existing, err := uc.photoStorage.GetPhotosByUnsplashIDFromDB(ctx, unsplashID)
+	if err != nil && err != sql.ErrNoRows {
+		uc.log(ctx).Error("ошибка при получении фото из БД", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении фото из БД по Unsplash ID: %w", err)
+	}
+	if existing == nil {
+		uc.log(ctx).Warn("фото для обновления не найдено", slog.String("unsplash_id", unsplashID))
+		return nil, fmt.Errorf("usecase: фото с Unsplash ID %s не найдено: %w", unsplashID, domain.ErrPhotoNotFound)
+	}
+
+	fetchCtx, cancelFetch := withStepTimeout(ctx, uc.timeouts.Fetch)
+	defer cancelFetch()
+	fresh, err := uc.photoFetcher.FetchPhotoByIDFromExternal(fetchCtx, unsplashID)
+	if errors.Is(err, domain.ErrExternalPhotoNotFound) {
+		uc.log(ctx).Warn("фото для обновления удалено из Unsplash", slog.String("unsplash_id", unsplashID))
+		return nil, fmt.Errorf("usecase: %w", err)
+	}
+	if err != nil {
+		uc.log(ctx).Error("ошибка при запросе в Unsplash API", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении фото из Unsplash API по ID %s: %w", unsplashID, err)
+	}
+	if fresh == nil {
+		return nil, fmt.Errorf("usecase: фото с Unsplash ID %s: %w", unsplashID, domain.ErrExternalPhotoNotFound)
+	}
+
+	// Неизменяемые поля берём из существующей записи; upsert их всё равно не трогает.
+	// Просмотры и скачивания источника идут в отдельные поля: собственные счётчики считает приложение
+	photo := *existing
+	photo.LikesCount = fresh.LikesCount
+	photo.UnsplashViews = fresh.ViewsCount
+	photo.UnsplashDownloads = fresh.DownloadsCount
+	photo.Description = fresh.Description
+	photo.BlurHash = fresh.BlurHash
+	photo.DominantColor = fresh.DominantColor
+	photo.Tags = nil
+
+	if err := uc.photoStorage.UpsertPhoto(ctx, &photo); err != nil {
+		uc.log(ctx).Error("ошибка обновления метаданных фото", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при обновлении фото %s: %w", unsplashID, err)
+	}
+
+	uc.invalidateCachedPhoto(ctx, unsplashID)
+	uc.publish(ctx, domain.PhotoUpdatedEvent{EventMeta: domain.NewEventMeta(), Photo: photo})
+	uc.log(ctx).Info("метаданные фото обновлены",
+		slog.String("photo_id", photo.ID.String()),
+		slog.Int("likes", photo.LikesCount),
+		slog.Int64("unsplash_views", photo.UnsplashViews),
+		slog.Int64("unsplash_downloads", photo.UnsplashDownloads),
+	)
+	return &photo, nil
+}
+
 // SearchAndSavePhotos ищет фото по запросу пользователя во внешнем API, сохраняет их в бд
-// и возвращает список сохраненных фото
-func (uc *photoUseCase) SearchAndSavePhotos(ctx context.Context, query string, page, perPage int) ([]domain.Photo, error) {
+// и возвращает сохраненные фото вместе с общим числом результатов и страниц у источника
+// и списком источников, ответивших на поиск
+func (uc *photoUseCase) SearchAndSavePhotos(ctx context.Context, query string, page, perPage int,
+	orientation, color, orderBy string, minWidth, minHeight int, source string) (_ domain.PhotoPage, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.SearchAndSavePhotos", trace.WithAttributes(
+		attribute.String("query", query),
+		attribute.Int("page", page),
+		attribute.Int("per_page", perPage),
+		attribute.String("orientation", orientation),
+		attribute.String("color", color),
+		attribute.String("order_by", orderBy),
+		attribute.Int("min_width", minWidth),
+		attribute.Int("min_height", minHeight),
+		attribute.String("source", source),
+	))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
 
 	// Устанавливаем значение по умолчанию, если perPage не указан или равен 0
 	if perPage <= 0 {
@@ -139,108 +347,709 @@ func (uc *photoUseCase) SearchAndSavePhotos(ctx context.Context, query string, p
 	}
 
 	// 1. Ищем фото во внешнем API (Unsplash)
-	uc.logger.Info("поиск фото во внешнем API", slog.String("query", query), slog.Int("page", page), slog.Int("per_page", perPage))
-	externalPhotos, err := uc.photoFetcher.SearchPhotosFromExternal(ctx, query, page, perPage)
+	uc.log(ctx).Info("поиск фото во внешнем API", slog.String("query", query), slog.Int("page", page), slog.Int("per_page", perPage))
+	fetchCtx, cancelFetch := withStepTimeout(ctx, uc.timeouts.Fetch)
+	defer cancelFetch()
+	result, err := uc.photoFetcher.SearchPhotosFromExternal(fetchCtx, query, page, perPage, orientation, color, orderBy)
 
 	if err != nil {
-		uc.logger.Error("ошибка поиска во внешнем API", slog.Any("error", err))
-		return nil, fmt.Errorf("usecase: ошибка при поиске фото во внешнем API: %w", err)
+		uc.log(ctx).Error("ошибка поиска во внешнем API", slog.Any("error", err))
+		return domain.PhotoPage{}, fmt.Errorf("usecase: ошибка при поиске фото во внешнем API: %w", err)
+	}
+	span.SetAttributes(attribute.Int("photos.total", result.Total), attribute.Int("photos.total_pages", result.TotalPages))
+	// одиночный источник не перечисляет себя в Sources
+	sources := result.Sources
+	if len(sources) == 0 && len(result.Photos) > 0 {
+		sources = []string{uc.photoFetcher.FetcherName()}
 	}
+	externalPhotos := filterByMinSize(result.Photos, minWidth, minHeight)
 	if len(externalPhotos) == 0 {
-		uc.logger.Warn("поиск не дал результатов", slog.String("query", query))
-		return []domain.Photo{}, nil
+		uc.log(ctx).Warn("поиск не дал результатов", slog.String("query", query), slog.Int("total", result.Total))
+		uc.recordSearch(ctx, query, page, perPage, 0, source)
+		return domain.PhotoPage{Photos: []domain.Photo{}, Total: result.Total, TotalPages: result.TotalPages, Sources: sources}, nil
 	}
 
-	var savedPhotos []domain.Photo
 	// 2. Сохраняем каждое найденное фото в нашей бд и S3
 	systemUserID, err := uc.userStorage.GetOrCreateSystemUser(ctx)
 	if err != nil {
-		uc.logger.Error("ошибка получения системного пользователя", slog.Any("error", err))
-		return nil, fmt.Errorf("usecase: не удалось получить или создать системного пользователя для пачки фото: %w", err)
+		uc.log(ctx).Error("ошибка получения системного пользователя", slog.Any("error", err))
+		return domain.PhotoPage{}, fmt.Errorf("usecase: не удалось получить или создать системного пользователя для пачки фото: %w", err)
 	}
 
+	// Одним запросом получаем фото пачки, которые уже есть в БД, вместе с фото в корзине.
+	// Конфликт по unsplash_id в SavePhotos случается только после скачивания оригинала и загрузки
+	// его в S3, поэтому без этой проверки каждый поиск заново качал бы все уже сохранённые фото.
+	// Гонку с параллельным поиском, который сохранит фото после проверки, разрешает SavePhotos:
+	// такие фото вернутся его сохранёнными строками, а наши лишние файлы удаляются
+	unsplashIDs := make([]string, 0, len(externalPhotos))
 	for _, photo := range externalPhotos {
-		// Избегаем дублирования: проверяем, существует ли уже фото по UnsplashID
-		existingPhoto, err := uc.photoStorage.GetPhotosByUnsplashIDFromDB(ctx, photo.UnsplashID)
-		if err != nil && err != sql.ErrNoRows {
-			uc.logger.Error("ошибка проверки существующего фото", slog.String("unsplash_id", photo.UnsplashID), slog.Any("error", err))
-			continue // пропускаем это фото, если нет ошибки "нет строк"
-		}
-		if existingPhoto != nil {
-			uc.logger.Debug("фото уже существует", slog.String("unsplash_id", photo.UnsplashID))
-			savedPhotos = append(savedPhotos, *existingPhoto) // добавляем существующее фото в список возвращаемых
-			continue
+		unsplashIDs = append(unsplashIDs, photo.UnsplashID)
+	}
+
+	storedPhotos, err := uc.photoStorage.FindPhotosByUnsplashIDs(ctx, unsplashIDs)
+	if err != nil {
+		uc.log(ctx).Error("ошибка проверки существующих фото", slog.Any("error", err))
+		return domain.PhotoPage{}, fmt.Errorf("usecase: ошибка при проверке существующих фото: %w", err)
+	}
+	stored := make(map[string]domain.Photo, len(storedPhotos))
+	for _, p := range storedPhotos {
+		stored[p.UnsplashID] = p
+	}
+
+	var fresh []domain.Photo
+	existing := 0
+	for _, photo := range externalPhotos {
+		storedPhoto, ok := stored[photo.UnsplashID]
+		switch {
+		case !ok:
+			fresh = append(fresh, photo)
+		case storedPhoto.DeletedAt != nil:
+			// запись есть, но мягко удалена — не показываем и не загружаем повторно
+			uc.log(ctx).Debug("фото удалено в корзину, пропускаем", slog.String("unsplash_id", photo.UnsplashID))
+			delete(stored, photo.UnsplashID)
+		default:
+			uc.log(ctx).Debug("фото уже существует", slog.String("unsplash_id", photo.UnsplashID))
+			existing++
 		}
+	}
 
-		// Скачиваем оригинальное фото с Unsplash
-		resp, err := http.Get(photo.OriginalURL)
-		if err != nil {
-			uc.logger.Error("ошибка скачивания фото", slog.String("url", photo.OriginalURL), slog.Any("error", err))
-			continue // Пропускаем это фото, если не удалось скачать
+	// 3. Новые фото загружаем в S3 и сохраняем в бд одной пачкой
+	created, concurrent, err := uc.uploadAndSaveExternalPhotos(ctx, fresh, systemUserID)
+	if err != nil {
+		return domain.PhotoPage{}, err
+	}
+	for _, p := range created {
+		stored[p.UnsplashID] = p
+	}
+	for _, p := range concurrent {
+		stored[p.UnsplashID] = p
+	}
+	existing += len(concurrent)
+
+	// Возвращаем фото в порядке выдачи источника
+	savedPhotos := make([]domain.Photo, 0, len(stored))
+	for _, photo := range externalPhotos {
+		if p, ok := stored[photo.UnsplashID]; ok {
+			savedPhotos = append(savedPhotos, p)
+			delete(stored, photo.UnsplashID) // источник мог вернуть одно фото дважды
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("photos.found", len(externalPhotos)),
+		attribute.Int("photos.saved", len(savedPhotos)),
+		attribute.Int("photos.created", len(created)),
+		attribute.Int("photos.existing", existing),
+	)
+	uc.log(ctx).Info("поиск завершён", slog.String("query", query), slog.Int("saved", len(savedPhotos)), slog.Int("found", len(externalPhotos)),
+		slog.Int("created", len(created)), slog.Int("existing", existing),
+		slog.Int("total", result.Total), slog.Int("total_pages", result.TotalPages))
+	uc.recordSearch(ctx, query, page, perPage, len(savedPhotos), source)
+	return domain.PhotoPage{Photos: savedPhotos, Total: result.Total, TotalPages: result.TotalPages, Sources: sources}, nil
+}
+
+// sniffDownloadedImage определяет тип скачанного файла по первым байтам и отклоняет всё,
+// что не является изображением. Возвращает тип и поток файла целиком для загрузки в S3
+func (uc *photoUseCase) sniffDownloadedImage(ctx context.Context, body io.Reader, url string) (string, io.Reader, error) {
+	contentType, isImage, stream, err := mediatype.DetectImageContentType(body)
+	if err != nil {
+		uc.log(ctx).Error("ошибка чтения скачанного фото", slog.String("url", url), slog.Any("error", err))
+		return "", nil, fmt.Errorf("ошибка чтения скачанного фото %s: %w", url, err)
+	}
+	if !isImage {
+		uc.log(ctx).Warn("скачанный файл не является изображением", slog.String("url", url), slog.String("content_type", contentType))
+		return "", nil, fmt.Errorf("скачанный файл %s имеет тип %s: %w", url, contentType, ErrDownloadNotImage)
+	}
+	return contentType, stream, nil
+}
+
+// uploadAndSaveExternalPhotos скачивает новые фото из внешнего источника, загружает их в S3
+// и сохраняет в бд одной пачкой от имени userID. Фото, которые не удалось скачать или загрузить,
+// пропускаются. created — фото, вставленные этим вызовом; existing — фото, которые успел
+// сохранить параллельный поиск, в виде сохранённых строк (без тех, что уже в корзине)
+func (uc *photoUseCase) uploadAndSaveExternalPhotos(ctx context.Context, photos []domain.Photo, userID uuid.UUID) (created, existing []domain.Photo, err error) {
+	// Загруженные в S3 фото копятся здесь и сохраняются в бд одним запросом в конце.
+	// Ключи запоминаются заранее: фото, проигравшее гонку, SavePhotos перезапишет чужой строкой
+	var (
+		uploaded []*domain.Photo
+		keys     []string
+	)
+	for _, photo := range photos {
+		if !uc.transferOriginal(ctx, &photo) {
+			continue // Пропускаем фото, которое не удалось скачать или загрузить в S3
+		}
+		photo.UserID = userID
+
+		uploaded = append(uploaded, &photo)
+		keys = append(keys, externalFileKey(photo))
+	}
+
+	if len(uploaded) == 0 {
+		return nil, nil, nil
+	}
+
+	ourIDs := make([]uuid.UUID, len(uploaded))
+	for i, photo := range uploaded {
+		ourIDs[i] = photo.ID
+	}
+
+	insertedIDs, err := uc.photoStorage.SavePhotos(ctx, uploaded)
+	if err != nil {
+		uc.log(ctx).Error("ошибка пакетного сохранения фото", slog.Int("count", len(uploaded)), slog.Any("error", err))
+		for _, key := range keys {
+			uc.deleteUploadedFile(ctx, key)
+		}
+		return nil, nil, fmt.Errorf("usecase: ошибка при сохранении фото: %w", err)
+	}
+
+	inserted := make(map[uuid.UUID]struct{}, len(insertedIDs))
+	for _, id := range insertedIDs {
+		inserted[id] = struct{}{}
+	}
+	created = make([]domain.Photo, 0, len(insertedIDs))
+	for i, photo := range uploaded {
+		if _, ok := inserted[ourIDs[i]]; !ok {
+			// фото успел сохранить параллельный поиск: photo уже заполнено его строкой,
+			// а наш файл в S3 ни на что не ссылается
+			uc.log(ctx).Debug("фото уже сохранено параллельно", slog.String("unsplash_id", photo.UnsplashID))
+			uc.deleteUploadedFile(ctx, keys[i])
+			if photo.DeletedAt == nil {
+				existing = append(existing, *photo)
+			}
+			continue
 		}
-		defer resp.Body.Close()
+		created = append(created, *photo)
+		uc.publish(ctx, domain.PhotoCreatedEvent{EventMeta: domain.NewEventMeta(), Photo: *photo})
+	}
+	return created, existing, nil
+}
+
+// transferOriginal скачивает оригинал внешнего фото и загружает его в S3, заполняя S3URL, размер,
+// тип и палитру. Ошибки только логируются: false означает, что фото нужно пропустить
+func (uc *photoUseCase) transferOriginal(ctx context.Context, photo *domain.Photo) bool {
+	// Скачиваем оригинальное фото из источника
+	resp, err := uc.downloadOriginal(ctx, photo.OriginalURL)
+	if err != nil {
+		uc.log(ctx).Error("ошибка скачивания фото", slog.String("url", photo.OriginalURL), slog.Any("error", err))
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		uc.log(ctx).Warn("неуспешный статус скачивания", slog.String("url", photo.OriginalURL), slog.Int("status_code", resp.StatusCode))
+		return false
+	}
+
+	contentType, fileStream, err := uc.sniffDownloadedImage(ctx, resp.Body, photo.OriginalURL)
+	if err != nil {
+		return false // скачалось не изображение
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			uc.logger.Warn("неуспешный статус скачивания", slog.String("url", photo.OriginalURL), slog.Int("status_code", resp.StatusCode))
-			continue // Пропускаем, если статус не 200 OK
+	if photo.ID == uuid.Nil {
+		photo.ID = uuid.New()
+	}
+	s3Key := externalFileKey(*photo)
+
+	s3URL, size, colors, err := uc.uploadWithPalette(ctx, s3Key, fileStream, contentType)
+	if err != nil {
+		uc.log(ctx).Error("ошибка загрузки в S3", slog.String("unsplash_id", photo.UnsplashID), slog.Any("error", err))
+		return false
+	}
+
+	photo.S3URL = s3URL
+	photo.SizeBytes = size
+	photo.MimeType = contentType
+	photo.DominantColors = colors
+	return true
+}
+
+// filterByMinSize оставляет фото не меньше minWidth x minHeight (0 — без ограничения).
+// Unsplash не умеет фильтровать по размеру, поэтому отсекаем до скачивания
+func filterByMinSize(photos []domain.Photo, minWidth, minHeight int) []domain.Photo {
+	if minWidth <= 0 && minHeight <= 0 {
+		return photos
+	}
+	filtered := photos[:0]
+	for _, photo := range photos {
+		if photo.Width >= minWidth && photo.Height >= minHeight {
+			filtered = append(filtered, photo)
 		}
+	}
+	return filtered
+}
 
-		fileStream := resp.Body
+// deleteUploadedFile удаляет только что загруженный файл, если сохранить фото в бд не удалось,
+// чтобы в хранилище не оставалось объектов без записи в бд (компенсирующее действие)
+func (uc *photoUseCase) deleteUploadedFile(ctx context.Context, s3Key string) {
+	// исходный контекст мог быть отменён — именно это часто и ломает сохранение
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+	defer cancel()
+
+	if err := uc.fileStorage.DeleteFile(ctx, s3Key); err != nil {
+		uc.log(ctx).Error("компенсация не удалась: файл остался в S3 без записи в БД", slog.String("s3_key", s3Key), slog.Any("error", err))
+		return
+	}
+	uc.log(ctx).Info("компенсация выполнена: файл удалён из S3", slog.String("s3_key", s3Key))
+}
+
+// photoCacheKey возвращает ключ кеша для фото по Unsplash ID
+func photoCacheKey(unsplashID string) string {
+	return "photo:unsplash:" + unsplashID
+}
 
-		// Определяем Content-Type для S3
-		contentType := resp.Header.Get("Content-Type")
-		if contentType == "" {
-			contentType = "application/octet-stream"
+// getCachedPhoto возвращает фото из кеша или nil при промахе.
+// Ошибки кеша не прерывают запрос: фото просто будет прочитано из бд
+func (uc *photoUseCase) getCachedPhoto(ctx context.Context, unsplashID string) *domain.Photo {
+	if uc.cache == nil {
+		return nil
+	}
+	data, err := uc.cache.Get(ctx, photoCacheKey(unsplashID))
+	if err != nil {
+		if !errors.Is(err, ports.ErrCacheMiss) {
+			uc.log(ctx).Warn("ошибка чтения из кеша", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
 		}
+		return nil
+	}
+	var photo domain.Photo
+	if err := json.Unmarshal(data, &photo); err != nil {
+		uc.log(ctx).Warn("повреждённая запись в кеше", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		uc.invalidateCachedPhoto(ctx, unsplashID)
+		return nil
+	}
+	uc.log(ctx).Debug("фото найдено в кеше", slog.String("unsplash_id", unsplashID))
+	return &photo
+}
+
+// cachePhoto кладёт фото в кеш на cacheTTL
+func (uc *photoUseCase) cachePhoto(ctx context.Context, photo *domain.Photo) {
+	if uc.cache == nil {
+		return
+	}
+	if err := storePhotoInCache(ctx, uc.cache, uc.cacheTTL, photo); err != nil {
+		uc.log(ctx).Warn("ошибка записи фото в кеш", slog.String("unsplash_id", photo.UnsplashID), slog.Any("error", err))
+	}
+}
+
+// storePhotoInCache сериализует фото и кладёт его в кеш под ключом photoCacheKey
+func storePhotoInCache(ctx context.Context, cache ports.Cache, ttl time.Duration, photo *domain.Photo) error {
+	data, err := json.Marshal(photo)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации фото %s для кеша: %w", photo.ID, err)
+	}
+	if err := cache.Set(ctx, photoCacheKey(photo.UnsplashID), data, ttl); err != nil {
+		return fmt.Errorf("ошибка записи фото %s в кеш: %w", photo.ID, err)
+	}
+	return nil
+}
+
+// invalidateCachedPhoto удаляет фото из кеша
+func (uc *photoUseCase) invalidateCachedPhoto(ctx context.Context, unsplashID string) {
+	if uc.cache == nil || unsplashID == "" {
+		return
+	}
+	if err := uc.cache.Del(ctx, photoCacheKey(unsplashID)); err != nil {
+		uc.log(ctx).Warn("ошибка удаления из кеша", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+	}
+}
+
+const (
+	// MaxAutocompleteLimit — сколько подсказок можно запросить за раз; столько же их кешируется на префикс
+	MaxAutocompleteLimit = 20
+	autocompleteCacheTTL = time.Minute
+)
+
+// AutocompletePhotos ищет подсказки по префиксу. Из бд всегда берётся MaxAutocompleteLimit подсказок,
+// они кешируются по префиксу, а клиенту отдаётся первые limit — так кеш не зависит от limit
+func (uc *photoUseCase) AutocompletePhotos(ctx context.Context, prefix string, limit int) ([]string, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	limit = min(max(limit, 1), MaxAutocompleteLimit)
+	key := "autocomplete:" + prefix
 
-		// Генерируем уникальный ключ для S3
-		s3Key := fmt.Sprintf("unsplash-photos/%s", photo.UnsplashID)
+	var suggestions []string
+	if uc.cache != nil {
+		data, err := uc.cache.Get(ctx, key)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &suggestions); err != nil {
+				uc.log(ctx).Warn("повреждённая запись в кеше", slog.String("key", key), slog.Any("error", err))
+				suggestions = nil
+			}
+		case !errors.Is(err, ports.ErrCacheMiss):
+			uc.log(ctx).Warn("ошибка чтения из кеша", slog.String("key", key), slog.Any("error", err))
+		}
+	}
 
-		s3URL, err := uc.fileStorage.UploadFile(ctx, s3Key, fileStream, contentType)
+	if suggestions == nil {
+		var err error
+		suggestions, err = uc.photoStorage.AutocompletePhotoTitles(ctx, prefix, MaxAutocompleteLimit)
 		if err != nil {
-			uc.logger.Error("ошибка загрузки в S3", slog.String("unsplash_id", photo.UnsplashID), slog.Any("error", err))
-			continue // пропускаем, если не удалось загрузить в S3
+			uc.log(ctx).Error("ошибка поиска подсказок", slog.String("prefix", prefix), slog.Any("error", err))
+			return nil, fmt.Errorf("usecase: ошибка при поиске подсказок: %w", err)
 		}
+		if uc.cache != nil {
+			if data, err := json.Marshal(suggestions); err == nil {
+				if err := uc.cache.Set(ctx, key, data, autocompleteCacheTTL); err != nil {
+					uc.log(ctx).Warn("ошибка записи в кеш", slog.String("key", key), slog.Any("error", err))
+				}
+			}
+		}
+	}
 
-		photo.S3URL = s3URL
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// CleanupOrphanedObjects находит в S3 файлы фото Unsplash без соответствующей записи в бд и удаляет их.
+// Файл с ID записи в ключе (см. externalFileKey) осиротел и тогда, когда фото с его unsplash_id
+// сохранено под другим ID: такой файл оставил запрос, проигравший гонку за сохранение
+func (uc *photoUseCase) CleanupOrphanedObjects(ctx context.Context, minAge time.Duration) (int, error) {
+	files, err := uc.fileStorage.ListFiles(ctx, unsplashPhotosPrefix)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения списка файлов из S3", slog.Any("error", err))
+		return 0, fmt.Errorf("usecase: ошибка при получении списка файлов из S3: %w", err)
+	}
 
-		photo.UserID = systemUserID
+	// слишком свежие файлы пропускаем: их запись в БД может ещё сохраняться
+	cutoff := time.Now().Add(-minAge)
+	keysByUnsplashID := make(map[string][]string, len(files))
+	checked := 0
+	for _, f := range files {
+		if f.LastModified.After(cutoff) {
+			continue
+		}
+		unsplashID, _, _ := strings.Cut(strings.TrimPrefix(f.Key, unsplashPhotosPrefix), "/")
+		keysByUnsplashID[unsplashID] = append(keysByUnsplashID[unsplashID], f.Key)
+		checked++
+	}
 
-		// Сохраняем полученное и обработанное фото в собственной базе данных
-		err = uc.photoStorage.SavePhoto(ctx, &photo)
+	var orphanKeys []string
+	batch := make([]string, 0, orphanCheckBatchSize)
+	flush := func() error {
+		existing, err := uc.photoStorage.ExistsByUnsplashIDs(ctx, batch)
 		if err != nil {
-			uc.logger.Error("ошибка сохранения фото", slog.String("unsplash_id", photo.UnsplashID), slog.Any("error", err))
-			continue // Продолжаем цикл, даже если одно фото не сохранилось
+			return err
+		}
+		for _, unsplashID := range batch {
+			id, ok := existing[unsplashID]
+			for _, key := range keysByUnsplashID[unsplashID] {
+				if !ok || !fileBelongsToPhoto(key, unsplashID, id) {
+					orphanKeys = append(orphanKeys, key)
+				}
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for unsplashID := range keysByUnsplashID {
+		batch = append(batch, unsplashID)
+		if len(batch) == orphanCheckBatchSize {
+			if err := flush(); err != nil {
+				uc.log(ctx).Error("ошибка проверки файлов на осиротевшие", slog.Any("error", err))
+				return 0, fmt.Errorf("usecase: ошибка при проверке файлов в БД: %w", err)
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			uc.log(ctx).Error("ошибка проверки файлов на осиротевшие", slog.Any("error", err))
+			return 0, fmt.Errorf("usecase: ошибка при проверке файлов в БД: %w", err)
 		}
-		savedPhotos = append(savedPhotos, photo)
 	}
 
-	uc.logger.Info("поиск завершён", slog.String("query", query), slog.Int("saved", len(savedPhotos)), slog.Int("found", len(externalPhotos)))
-	return savedPhotos, nil
+	deleted := 0
+	for _, key := range orphanKeys {
+		if err := uc.fileStorage.DeleteFile(ctx, key); err != nil {
+			uc.log(ctx).Error("ошибка удаления осиротевшего файла", slog.String("s3_key", key), slog.Any("error", err))
+			continue
+		}
+		deleted++
+	}
+
+	uc.log(ctx).Info("очистка осиротевших файлов завершена",
+		slog.Int("checked", checked),
+		slog.Int("orphaned", len(orphanKeys)),
+		slog.Int("deleted", deleted),
+	)
+	return deleted, nil
+}
+
+// fileBelongsToPhoto сообщает, что файл key принадлежит сохранённому фото unsplashID с ID id:
+// ключ без ID записи (так фото сохранялись раньше) или ключ ровно с этим ID
+func fileBelongsToPhoto(key, unsplashID string, id uuid.UUID) bool {
+	legacy := unsplashPhotosPrefix + unsplashID
+	return key == legacy || key == legacy+"/"+id.String()
 }
 
 // GetPhotoDetailsFromDB получает детали фото из бд по нашему внутреннему ID
 func (uc *photoUseCase) GetPhotoDetailsFromDB(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
 	photo, err := uc.photoStorage.GetPhotoByIDFromDB(ctx, id)
+	if err != nil && err != sql.ErrNoRows {
+		uc.log(ctx).Error("ошибка получения фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении фото из БД по ID %s: %w", id, err)
+	}
+	// хранилище сообщает об отсутствии фото пустым результатом без ошибки
+	if photo == nil {
+		uc.log(ctx).Warn("фото не найдено", slog.String("photo_id", id.String()))
+		return nil, fmt.Errorf("usecase: фото с ID %s не найдено в БД: %w", id, domain.ErrPhotoNotFound)
+	}
+	uc.log(ctx).Debug("фото успешно получено", slog.String("photo_id", id.String()))
+	return photo, nil
+}
+
+// GetPhotoDetailsIncludingDeleted реализует метод PhotoUseCase
+func (uc *photoUseCase) GetPhotoDetailsIncludingDeleted(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	photo, err := uc.photoStorage.GetPhotoByIDIncludingDeleted(ctx, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			uc.logger.Warn("фото не найдено", slog.String("photo_id", id.String()))
-			return nil, fmt.Errorf("usecase: фото с ID %s не найдено в БД", id)
-		}
-		uc.logger.Error("ошибка получения фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		uc.log(ctx).Error("ошибка получения фото", slog.String("photo_id", id.String()), slog.Any("error", err))
 		return nil, fmt.Errorf("usecase: ошибка при получении фото из БД по ID %s: %w", id, err)
 	}
-	uc.logger.Debug("фото успешно получено", slog.String("photo_id", id.String()))
+	if photo == nil {
+		uc.log(ctx).Warn("фото не найдено", slog.String("photo_id", id.String()))
+		return nil, fmt.Errorf("usecase: фото с ID %s не найдено в БД: %w", id, domain.ErrPhotoNotFound)
+	}
 	return photo, nil
 }
 
+// CountView учитывает просмотр фото, если счётчик просмотров задан
+func (uc *photoUseCase) CountView(ctx context.Context, id uuid.UUID) {
+	if uc.views != nil {
+		uc.views.IncrementViews(ctx, id)
+	}
+}
+
 // GetRecentPhotosFromDB получает последние фото из бд с пагинацией
-func (uc *photoUseCase) GetRecentPhotosFromDB(ctx context.Context, page, perPage int) ([]domain.Photo, error) {
-	photos, err := uc.photoStorage.ListPhotosInDB(ctx, page, perPage)
+func (uc *photoUseCase) GetRecentPhotosFromDB(ctx context.Context, page, perPage int, includeTags bool, sort domain.PhotoSort) ([]domain.Photo, int64, error) {
+	var (
+		photos []domain.Photo
+		err    error
+	)
+	if includeTags {
+		photos, err = uc.photoStorage.ListPhotosWithTagsInDB(ctx, page, perPage, sort)
+	} else {
+		photos, err = uc.photoStorage.ListPhotosInDB(ctx, page, perPage, sort)
+	}
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения последних фото", slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении последних фото из БД: %w", err)
+	}
+	total, err := uc.photoStorage.CountPhotosInDB(ctx)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото", slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте фото в БД: %w", err)
+	}
+	uc.log(ctx).Info("получены последние фото", slog.Int("count", len(photos)), slog.Int64("total", total), slog.Int("page", page), slog.Int("per_page", perPage))
+	return photos, total, nil
+}
+
+// SearchPhotosInDB ищет фото в бд с пагинацией и считает общее количество совпадений
+func (uc *photoUseCase) SearchPhotosInDB(ctx context.Context, query string, page, perPage int, filter domain.PhotoSearchFilter, sort domain.PhotoSort) ([]domain.Photo, int64, error) {
+	photos, err := uc.photoStorage.SearchPhotosInDB(ctx, query, page, perPage, filter, sort)
+	if err != nil {
+		uc.log(ctx).Error("ошибка поиска фото в БД", slog.String("query", query), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при поиске фото в БД: %w", err)
+	}
+	total, err := uc.photoStorage.CountSearchResults(ctx, query, filter)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта результатов поиска", slog.String("query", query), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте результатов поиска в БД: %w", err)
+	}
+	uc.log(ctx).Info("поиск в БД завершён", slog.String("query", query), slog.Int("count", len(photos)), slog.Int64("total", total))
+	return photos, total, nil
+}
+
+// SoftDeletePhoto перемещает фото в корзину
+func (uc *photoUseCase) SoftDeletePhoto(ctx context.Context, id uuid.UUID) error {
+	// Unsplash ID нужен, чтобы убрать фото из кеша
+	photo, err := uc.photoStorage.GetPhotoByIDFromDB(ctx, id)
+	if err != nil && err != sql.ErrNoRows {
+		uc.log(ctx).Error("ошибка получения фото перед удалением", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при удалении фото %s: %w", id, err)
+	}
+
+	deletedAt := time.Now().UTC()
+	if err := uc.photoStorage.SetPhotoDeletedAt(ctx, id, &deletedAt); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			uc.log(ctx).Warn("фото для удаления не найдено", slog.String("photo_id", id.String()))
+		} else {
+			uc.log(ctx).Error("ошибка удаления фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		}
+		return fmt.Errorf("usecase: ошибка при удалении фото %s: %w", id, err)
+	}
+	if photo != nil {
+		uc.invalidateCachedPhoto(ctx, photo.UnsplashID)
+		uc.publish(ctx, domain.PhotoDeletedEvent{EventMeta: domain.NewEventMeta(), Photo: *photo})
+	}
+	uc.log(ctx).Info("фото перемещено в корзину", slog.String("photo_id", id.String()))
+	return nil
+}
+
+// RecordView учитывает просмотр сохранённого фото
+func (uc *photoUseCase) RecordView(ctx context.Context, id uuid.UUID) error {
+	if err := uc.photoStorage.IncrementViewsCount(ctx, id); err != nil {
+		uc.log(ctx).Warn("не удалось учесть просмотр фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при учёте просмотра фото %s: %w", id, err)
+	}
+	return nil
+}
+
+// RecordDownload учитывает скачивание сохранённого фото
+func (uc *photoUseCase) RecordDownload(ctx context.Context, id uuid.UUID) error {
+	if err := uc.photoStorage.IncrementDownloadsCount(ctx, id); err != nil {
+		uc.log(ctx).Warn("не удалось учесть скачивание фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при учёте скачивания фото %s: %w", id, err)
+	}
+	return nil
+}
+
+// RestorePhoto возвращает фото из корзины
+func (uc *photoUseCase) RestorePhoto(ctx context.Context, id uuid.UUID) (*domain.Photo, error) {
+	if err := uc.photoStorage.SetPhotoDeletedAt(ctx, id, nil); err != nil {
+		if errors.Is(err, domain.ErrPhotoNotFound) {
+			uc.log(ctx).Warn("фото для восстановления не найдено в корзине", slog.String("photo_id", id.String()))
+		} else {
+			uc.log(ctx).Error("ошибка восстановления фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		}
+		return nil, fmt.Errorf("usecase: ошибка при восстановлении фото %s: %w", id, err)
+	}
+	uc.log(ctx).Info("фото восстановлено из корзины", slog.String("photo_id", id.String()))
+	return uc.GetPhotoDetailsFromDB(ctx, id)
+}
+
+// ListDeletedPhotos получает фото из корзины с пагинацией
+func (uc *photoUseCase) ListDeletedPhotos(ctx context.Context, page, perPage int) ([]domain.Photo, int64, error) {
+	photos, err := uc.photoStorage.ListDeletedPhotosInDB(ctx, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото из корзины", slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении удалённых фото из БД: %w", err)
+	}
+	total, err := uc.photoStorage.CountDeletedPhotosInDB(ctx)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото в корзине", slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте удалённых фото в БД: %w", err)
+	}
+	uc.log(ctx).Info("получены фото из корзины", slog.Int("count", len(photos)), slog.Int64("total", total), slog.Int("page", page), slog.Int("per_page", perPage))
+	return photos, total, nil
+}
+
+// GetPhotosByIDs получает фото по списку ID и возвращает их в порядке запроса
+func (uc *photoUseCase) GetPhotosByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Photo, error) {
+	found, err := uc.photoStorage.GetPhotosByIDs(ctx, ids)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото по списку ID", slog.Int("count", len(ids)), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении фото из БД: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]domain.Photo, len(found))
+	for _, photo := range found {
+		byID[photo.ID] = photo
+	}
+
+	photos := make([]domain.Photo, 0, len(ids))
+	for _, id := range ids {
+		photo, ok := byID[id]
+		if !ok {
+			uc.log(ctx).Warn("фото не найдено", slog.String("photo_id", id.String()))
+			return nil, fmt.Errorf("usecase: фото с ID %s не найдено: %w", id, domain.ErrPhotoNotFound)
+		}
+		photos = append(photos, photo)
+	}
+	return photos, nil
+}
+
+// OpenPhotoFile открывает файл фото в S3
+func (uc *photoUseCase) OpenPhotoFile(ctx context.Context, photo domain.Photo) (io.ReadCloser, error) {
+	key := photoFileKey(photo)
+	file, err := uc.fileStorage.GetFile(ctx, key)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения файла из S3", slog.String("s3_key", key), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении файла фото %s: %w", photo.ID, err)
+	}
+	return file, nil
+}
+
+// ListPhotosByAuthor получает фото автора и их общее количество
+func (uc *photoUseCase) ListPhotosByAuthor(ctx context.Context, authorName string, page, perPage int) ([]domain.Photo, int64, error) {
+	photos, err := uc.photoStorage.ListPhotosByAuthor(ctx, authorName, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото автора", slog.String("author_name", authorName), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении фото автора %q из БД: %w", authorName, err)
+	}
+	total, err := uc.photoStorage.CountPhotosByAuthor(ctx, authorName)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото автора", slog.String("author_name", authorName), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте фото автора %q в БД: %w", authorName, err)
+	}
+	uc.log(ctx).Info("получены фото автора", slog.String("author_name", authorName), slog.Int("count", len(photos)), slog.Int64("total", total))
+	return photos, total, nil
+}
+
+// ListPhotosByUser получает фото пользователя и их общее количество
+func (uc *photoUseCase) ListPhotosByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error) {
+	// Пустой список не отличить от несуществующего пользователя, поэтому проверяем его явно
+	if _, err := uc.userStorage.GetUserByID(ctx, userID); err != nil {
+		uc.log(ctx).Warn("ошибка получения пользователя", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении пользователя %s: %w", userID, err)
+	}
+
+	photos, err := uc.photoStorage.ListPhotosByUser(ctx, userID, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото пользователя", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении фото пользователя %s из БД: %w", userID, err)
+	}
+	total, err := uc.photoStorage.CountPhotosByUser(ctx, userID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото пользователя", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте фото пользователя %s в БД: %w", userID, err)
+	}
+	uc.log(ctx).Info("получены фото пользователя", slog.String("user_id", userID.String()), slog.Int("count", len(photos)), slog.Int64("total", total))
+	return photos, total, nil
+}
+
+// PurgeDeletedPhotos окончательно удаляет фото, пролежавшие в корзине дольше retention, вместе с файлами в S3
+func (uc *photoUseCase) PurgeDeletedPhotos(ctx context.Context, retention time.Duration) (int, error) {
+	purged, err := uc.photoStorage.PurgeDeletedPhotos(ctx, time.Now().Add(-retention))
 	if err != nil {
-		uc.logger.Error("ошибка получения последних фото", slog.Any("error", err))
-		return nil, fmt.Errorf("usecase: ошибка при получении последних фото из БД: %w", err)
+		uc.log(ctx).Error("ошибка очистки корзины", slog.Any("error", err))
+		return 0, fmt.Errorf("usecase: ошибка при очистке корзины: %w", err)
+	}
+
+	for _, photo := range purged {
+		key := photoFileKey(photo)
+		if err := uc.fileStorage.DeleteFile(ctx, key); err != nil {
+			// запись уже удалена — оставшийся файл подберёт очистка осиротевших объектов
+			uc.log(ctx).Error("ошибка удаления файла очищенного фото", slog.String("s3_key", key), slog.Any("error", err))
+		}
 	}
-	uc.logger.Info("получены последние фото", slog.Int("count", len(photos)), slog.Int("page", page), slog.Int("per_page", perPage))
+
+	uc.log(ctx).Info("корзина очищена", slog.Int("purged", len(purged)), slog.Duration("retention", retention))
+	return len(purged), nil
+}
+
+// UpdatePopularityScores пересчитывает popularity_score фото одним запросом к бд
+func (uc *photoUseCase) UpdatePopularityScores(ctx context.Context) (int64, error) {
+	updated, err := uc.photoStorage.UpdatePopularityScores(ctx)
+	if err != nil {
+		uc.log(ctx).Error("ошибка пересчёта популярности фото", slog.Any("error", err))
+		return 0, fmt.Errorf("usecase: ошибка при пересчёте популярности фото: %w", err)
+	}
+	uc.log(ctx).Info("популярность фото пересчитана", slog.Int64("updated", updated))
+	return updated, nil
+}
+
+// GetTrendingPhotos получает самые популярные фото из бд
+func (uc *photoUseCase) GetTrendingPhotos(ctx context.Context, limit int) ([]domain.Photo, error) {
+	photos, err := uc.photoStorage.ListTrendingPhotos(ctx, limit)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения популярных фото", slog.Int("limit", limit), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении популярных фото из БД: %w", err)
+	}
+	uc.log(ctx).Info("получены популярные фото", slog.Int("count", len(photos)), slog.Int("limit", limit))
 	return photos, nil
 }
+
+// log возвращает логгер с request_id текущего запроса
+func (uc *photoUseCase) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, uc.logger)
+}
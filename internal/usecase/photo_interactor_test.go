@@ -0,0 +1,351 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// racingPhotoStorage не видит уже сохранённые фото при проверках перед сохранением —
+// как будто параллельный запрос сохранил фото сразу после них
+type racingPhotoStorage struct {
+	ports.PhotoStorage
+}
+
+func (racingPhotoStorage) GetPhotosByUnsplashIDFromDB(context.Context, string) (*domain.Photo, error) {
+	return nil, nil
+}
+
+func (racingPhotoStorage) ExistsByUnsplashIDs(context.Context, []string) (map[string]uuid.UUID, error) {
+	return map[string]uuid.UUID{}, nil
+}
+
+func (racingPhotoStorage) FindPhotosByUnsplashIDs(context.Context, []string) ([]domain.Photo, error) {
+	return nil, nil
+}
+
+func TestGetOrCreatePhotoByUnsplashID_LosingRaceKeepsWinner(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+
+	winnerEvents := &recordingPublisher{}
+	winnerUC := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, files: files, events: winnerEvents})
+	winner, err := winnerUC.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if err != nil {
+		t.Fatalf("winner: %v", err)
+	}
+	if n := len(winnerEvents.created()); n != 1 {
+		t.Fatalf("winner published %d PhotoCreatedEvent, want 1", n)
+	}
+	winnerKey := photoFileKey(*winner)
+
+	loserEvents := &recordingPublisher{}
+	loserUC := newTestPhotoUseCase(useCaseDeps{photos: racingPhotoStorage{st.photos}, users: st.users, fetcher: fetcher, files: files, events: loserEvents})
+	got, err := loserUC.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if err != nil {
+		t.Fatalf("loser: %v", err)
+	}
+
+	if got.ID != winner.ID {
+		t.Errorf("loser got photo %s, want winner %s", got.ID, winner.ID)
+	}
+	if n := len(loserEvents.created()); n != 0 {
+		t.Errorf("loser published %d PhotoCreatedEvent, want 0", n)
+	}
+	if keys := files.keys(); len(keys) != 1 || keys[0] != winnerKey {
+		t.Errorf("files = %v, want only the winner's %s", keys, winnerKey)
+	}
+}
+
+func TestGetOrCreatePhotoByUnsplashID_LosingRaceToTrashedPhoto(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, files: files})
+	winner, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if err != nil {
+		t.Fatalf("winner: %v", err)
+	}
+	if err := uc.SoftDeletePhoto(ctx, winner.ID); err != nil {
+		t.Fatalf("delete winner: %v", err)
+	}
+
+	loserUC := newTestPhotoUseCase(useCaseDeps{photos: racingPhotoStorage{st.photos}, users: st.users, fetcher: fetcher, files: files})
+	_, err = loserUC.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if !errors.Is(err, domain.ErrPhotoNotFound) {
+		t.Fatalf("err = %v, want ErrPhotoNotFound for a trashed photo", err)
+	}
+	if keys := files.keys(); len(keys) != 1 || keys[0] != photoFileKey(*winner) {
+		t.Errorf("files = %v, want only the winner's file", keys)
+	}
+}
+
+func TestSearchAndSavePhotos_LosingRaceKeepsWinner(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{search: []domain.Photo{externalPhoto(srv, "a"), externalPhoto(srv, "b")}}
+
+	winnerUC := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, files: files})
+	first, err := winnerUC.SearchAndSavePhotos(ctx, "cats", 1, 10, "", "", "", 0, 0, domain.SearchSourceServer)
+	if err != nil {
+		t.Fatalf("winner search: %v", err)
+	}
+	winnerKeys := files.keys()
+
+	loserEvents := &recordingPublisher{}
+	loserUC := newTestPhotoUseCase(useCaseDeps{photos: racingPhotoStorage{st.photos}, users: st.users, fetcher: fetcher, files: files, events: loserEvents})
+	second, err := loserUC.SearchAndSavePhotos(ctx, "cats", 1, 10, "", "", "", 0, 0, domain.SearchSourceServer)
+	if err != nil {
+		t.Fatalf("loser search: %v", err)
+	}
+
+	if len(second.Photos) != len(first.Photos) {
+		t.Fatalf("loser got %d photos, want %d", len(second.Photos), len(first.Photos))
+	}
+	for i := range first.Photos {
+		if second.Photos[i].ID != first.Photos[i].ID {
+			t.Errorf("photo %d: ID %s, want stored %s", i, second.Photos[i].ID, first.Photos[i].ID)
+		}
+	}
+	if n := len(loserEvents.created()); n != 0 {
+		t.Errorf("loser published %d PhotoCreatedEvent, want 0", n)
+	}
+	if keys := files.keys(); len(keys) != len(winnerKeys) {
+		t.Errorf("files = %v, want only the winner's %v", keys, winnerKeys)
+	}
+}
+
+// errDBDown — ошибка бд, которую возвращают failingPhotoStorage и failingUserStorage
+var errDBDown = errors.New("db is down")
+
+// failingPhotoStorage не может сохранить ни одного фото, остальные запросы идут в обёрнутое хранилище
+type failingPhotoStorage struct {
+	ports.PhotoStorage
+}
+
+func (failingPhotoStorage) SavePhotoTx(context.Context, *domain.Photo) (bool, error) {
+	return false, errDBDown
+}
+
+func (failingPhotoStorage) SavePhotos(context.Context, []*domain.Photo) ([]uuid.UUID, error) {
+	return nil, errDBDown
+}
+
+// failingUserStorage не может получить системного пользователя
+type failingUserStorage struct {
+	ports.UserStorage
+}
+
+func (failingUserStorage) GetOrCreateSystemUser(context.Context) (uuid.UUID, error) {
+	return uuid.Nil, errDBDown
+}
+
+func TestGetOrCreatePhotoByUnsplashID_DeletesFileWhenSaveFails(t *testing.T) {
+	tests := []struct {
+		name   string
+		photos func(st testStorages) ports.PhotoStorage
+		users  func(st testStorages) ports.UserStorage
+	}{
+		{
+			name:   "SavePhotoTx fails",
+			photos: func(st testStorages) ports.PhotoStorage { return failingPhotoStorage{st.photos} },
+			users:  func(st testStorages) ports.UserStorage { return st.users },
+		},
+		{
+			name:   "system user lookup fails",
+			photos: func(st testStorages) ports.PhotoStorage { return st.photos },
+			users:  func(st testStorages) ports.UserStorage { return failingUserStorage{st.users} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newTestStorages(t)
+			files := newMemFileStorage()
+			srv := newImageServer(t, testPNG(t, 4, 3))
+			fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+			uc := newTestPhotoUseCase(useCaseDeps{photos: tt.photos(st), users: tt.users(st), fetcher: fetcher, files: files})
+
+			if _, err := uc.GetOrCreatePhotoByUnsplashID(context.Background(), "abc"); !errors.Is(err, errDBDown) {
+				t.Fatalf("err = %v, want the db error", err)
+			}
+			if keys := files.keys(); len(keys) != 0 {
+				t.Errorf("files = %v, want the uploaded file deleted", keys)
+			}
+		})
+	}
+}
+
+func TestSearchAndSavePhotos_DeletesFilesWhenSaveFails(t *testing.T) {
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{search: []domain.Photo{externalPhoto(srv, "a"), externalPhoto(srv, "b")}}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: failingPhotoStorage{st.photos}, users: st.users, fetcher: fetcher, files: files})
+
+	_, err := uc.SearchAndSavePhotos(context.Background(), "cats", 1, 10, "", "", "", 0, 0, domain.SearchSourceServer)
+	if !errors.Is(err, errDBDown) {
+		t.Fatalf("err = %v, want the db error", err)
+	}
+	if keys := files.keys(); len(keys) != 0 {
+		t.Errorf("files = %v, want every uploaded file deleted", keys)
+	}
+}
+
+func TestCleanupOrphanedObjects(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, files: files})
+
+	photo, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if err != nil {
+		t.Fatalf("GetOrCreatePhotoByUnsplashID: %v", err)
+	}
+	ownKey := photoFileKey(*photo)
+	staleLoserKey := unsplashPhotosPrefix + "abc/" + uuid.NewString()
+	noRowKey := unsplashPhotosPrefix + "missing/" + uuid.NewString()
+	legacyNoRowKey := unsplashPhotosPrefix + "missing-legacy"
+	for _, key := range []string{staleLoserKey, noRowKey, legacyNoRowKey} {
+		files.put(key, []byte("x"), "image/png")
+	}
+
+	deleted, err := uc.CleanupOrphanedObjects(ctx, 0)
+	if err != nil {
+		t.Fatalf("CleanupOrphanedObjects: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("deleted = %d, want 3", deleted)
+	}
+	if keys := files.keys(); len(keys) != 1 || keys[0] != ownKey {
+		t.Errorf("files = %v, want only %s", keys, ownKey)
+	}
+}
+
+func TestPhotoFileKey(t *testing.T) {
+	id := uuid.MustParse("11111111-2222-3333-4444-555555555555")
+	tests := []struct {
+		name  string
+		photo domain.Photo
+		want  string
+	}{
+		{
+			name:  "legacy unsplash key",
+			photo: domain.Photo{ID: id, UnsplashID: "abc", S3URL: "http://localhost:9000/photos/unsplash-photos/abc"},
+			want:  "unsplash-photos/abc",
+		},
+		{
+			name:  "unsplash key with record ID",
+			photo: domain.Photo{ID: id, UnsplashID: "abc", S3URL: "http://localhost:9000/photos/unsplash-photos/abc/" + id.String()},
+			want:  "unsplash-photos/abc/" + id.String(),
+		},
+		{
+			name:  "user upload",
+			photo: domain.Photo{ID: id, ExternalSource: domain.SourceUser, S3URL: "http://localhost:9000/photos/uploads/" + id.String() + ".png"},
+			want:  "uploads/" + id.String() + ".png",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := photoFileKey(tt.photo); got != tt.want {
+				t.Errorf("photoFileKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPhotoDetailsFromDB_DoesNotCountView(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+	views := &countingViews{}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, files: files, views: views})
+
+	photo, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if err != nil {
+		t.Fatalf("GetOrCreatePhotoByUnsplashID: %v", err)
+	}
+	if _, err := uc.GetPhotoDetailsFromDB(ctx, photo.ID); err != nil {
+		t.Fatalf("GetPhotoDetailsFromDB: %v", err)
+	}
+	file, err := uc.OpenPhotoFile(ctx, *photo)
+	if err != nil {
+		t.Fatalf("OpenPhotoFile: %v", err)
+	}
+	_ = file.Close()
+	if n := views.of(photo.ID); n != 0 {
+		t.Fatalf("views after reads = %d, want 0", n)
+	}
+
+	uc.CountView(ctx, photo.ID)
+	if n := views.of(photo.ID); n != 1 {
+		t.Errorf("views after CountView = %d, want 1", n)
+	}
+}
+
+func TestRestorePhoto(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{
+		"deleted": externalPhoto(srv, "deleted"),
+		"kept":    externalPhoto(srv, "kept"),
+	}}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, files: files})
+
+	deleted, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "deleted")
+	if err != nil {
+		t.Fatalf("save photo: %v", err)
+	}
+	kept, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "kept")
+	if err != nil {
+		t.Fatalf("save photo: %v", err)
+	}
+	if err := uc.SoftDeletePhoto(ctx, deleted.ID); err != nil {
+		t.Fatalf("SoftDeletePhoto: %v", err)
+	}
+
+	t.Run("soft-deleted photo", func(t *testing.T) {
+		restored, err := uc.RestorePhoto(ctx, deleted.ID)
+		if err != nil {
+			t.Fatalf("RestorePhoto: %v", err)
+		}
+		if restored.ID != deleted.ID || restored.DeletedAt != nil {
+			t.Errorf("restored = %s (deleted_at %v), want %s without deleted_at", restored.ID, restored.DeletedAt, deleted.ID)
+		}
+		if _, err := uc.GetPhotoDetailsFromDB(ctx, deleted.ID); err != nil {
+			t.Errorf("restored photo is not visible: %v", err)
+		}
+	})
+
+	noop := []struct {
+		name string
+		id   uuid.UUID
+	}{
+		{name: "never deleted", id: kept.ID},
+		{name: "missing", id: uuid.New()},
+	}
+	for _, tt := range noop {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.RestorePhoto(ctx, tt.id); !errors.Is(err, domain.ErrPhotoNotFound) {
+				t.Errorf("err = %v, want ErrPhotoNotFound", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/lock"
+)
+
+const (
+	// photoLockTTL — на сколько захватывается блокировка фото: с запасом на скачивание и загрузку в S3
+	photoLockTTL = 30 * time.Second
+
+	// photoLockWait — сколько ждём блокировку, которую держит другой экземпляр
+	photoLockWait = 500 * time.Millisecond
+
+	// photoLockPollInterval — как часто повторяем попытку захвата, пока ждём
+	photoLockPollInterval = 50 * time.Millisecond
+)
+
+// lockPhoto захватывает блокировку photo:lock:<unsplashID>, чтобы фото не скачали и не сохранили
+// одновременно несколько экземпляров. Если за photoLockWait блокировку получить не удалось,
+// ошибка оборачивает lock.ErrNotAcquired. Без locker, а также при недоступности Redis
+// работаем без блокировки: дубликат всё равно отсечёт уникальный индекс в бд
+func (uc *photoUseCase) lockPhoto(ctx context.Context, unsplashID string) (func(), error) {
+	if uc.locker == nil {
+		return func() {}, nil
+	}
+
+	key := "photo:lock:" + unsplashID
+	deadline := time.NewTimer(photoLockWait)
+	defer deadline.Stop()
+	for {
+		unlock, acquired, err := uc.locker.Acquire(ctx, key, photoLockTTL)
+		if err != nil {
+			uc.log(ctx).Warn("не удалось захватить блокировку фото, продолжаем без неё",
+				slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+			return func() {}, nil
+		}
+		if acquired {
+			return unlock, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("usecase: ожидание блокировки фото %s прервано: %w", unsplashID, ctx.Err())
+		case <-deadline.C:
+			uc.log(ctx).Warn("фото обрабатывается другим экземпляром", slog.String("unsplash_id", unsplashID))
+			return nil, fmt.Errorf("usecase: фото %s: %w", unsplashID, lock.ErrNotAcquired)
+		case <-time.After(photoLockPollInterval):
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/lock"
+)
+
+// memLocker — DistributedLocker в памяти с той же семантикой, что SET NX в Redis
+type memLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *memLocker) Acquire(_ context.Context, key string, _ time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[string]bool)
+	}
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, key)
+	}, true, nil
+}
+
+func TestGetOrCreatePhotoByUnsplashID_LockedRaceInsertsOnce(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	img := testPNG(t, 4, 3)
+	var downloads atomic.Int64
+	// медленный оригинал: второй экземпляр успевает упереться в блокировку, но не в photoLockWait
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		downloads.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(img)
+	}))
+	t.Cleanup(srv.Close)
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+	locker := &memLocker{}
+	events := &recordingPublisher{}
+
+	// два экземпляра сервиса с общими бд, S3 и Redis
+	var wg sync.WaitGroup
+	results := make([]*domain.Photo, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, files: files,
+			locker: locker, events: events})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("instance %d: %v", i, err)
+		}
+	}
+	if results[0].ID != results[1].ID {
+		t.Errorf("instances got photos %s and %s, want the same one", results[0].ID, results[1].ID)
+	}
+	var rows int
+	if err := st.db.GetContext(ctx, &rows, `SELECT COUNT(*) FROM photos WHERE unsplash_id = 'abc'`); err != nil {
+		t.Fatalf("count photos: %v", err)
+	}
+	if rows != 1 {
+		t.Errorf("photo rows = %d, want 1", rows)
+	}
+	if n := downloads.Load(); n != 1 {
+		t.Errorf("original downloaded %d times, want 1", n)
+	}
+	if n := len(events.created()); n != 1 {
+		t.Errorf("published %d PhotoCreatedEvent, want 1", n)
+	}
+	if keys := files.keys(); len(keys) != 1 {
+		t.Errorf("files = %v, want one", keys)
+	}
+}
+
+func TestGetOrCreatePhotoByUnsplashID_LockHeldElsewhere(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+	locker := &memLocker{}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher,
+		files: newMemFileStorage(), locker: locker})
+
+	unlock, _, _ := locker.Acquire(ctx, "photo:lock:abc", time.Minute)
+	defer unlock()
+
+	start := time.Now()
+	_, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if !errors.Is(err, lock.ErrNotAcquired) {
+		t.Fatalf("err = %v, want lock.ErrNotAcquired", err)
+	}
+	if waited := time.Since(start); waited < photoLockWait {
+		t.Errorf("gave up after %v, want to wait at least %v", waited, photoLockWait)
+	}
+}
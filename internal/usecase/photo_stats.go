@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RefreshPhotoStats реализует метод PhotoUseCase
+func (uc *photoUseCase) RefreshPhotoStats(ctx context.Context, unsplashID string) (_ *domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.RefreshPhotoStats",
+		trace.WithAttributes(attribute.String("unsplash_id", unsplashID)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if uc.stats == nil {
+		return nil, ErrStatsUnavailable
+	}
+
+	existing, err := uc.photoStorage.GetPhotosByUnsplashIDFromDB(ctx, unsplashID)
+	if err != nil && err != sql.ErrNoRows {
+		uc.log(ctx).Error("ошибка при получении фото из БД", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении фото из БД по Unsplash ID: %w", err)
+	}
+	if existing == nil {
+		uc.log(ctx).Warn("фото для обновления статистики не найдено", slog.String("unsplash_id", unsplashID))
+		return nil, fmt.Errorf("usecase: фото с Unsplash ID %s не найдено: %w", unsplashID, domain.ErrPhotoNotFound)
+	}
+
+	fetchCtx, cancelFetch := withStepTimeout(ctx, uc.timeouts.Fetch)
+	defer cancelFetch()
+	stats, err := uc.stats.FetchPhotoStatistics(fetchCtx, unsplashID)
+	if err != nil {
+		var rateLimited *domain.ErrRateLimited
+		switch {
+		case errors.Is(err, domain.ErrExternalPhotoNotFound):
+			uc.log(ctx).Warn("фото для обновления статистики удалено из Unsplash", slog.String("unsplash_id", unsplashID))
+		case errors.As(err, &rateLimited):
+			uc.log(ctx).Warn("лимит запросов исчерпан, статистика фото не обновлена", slog.String("unsplash_id", unsplashID))
+		default:
+			uc.log(ctx).Error("ошибка получения статистики фото", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		}
+		return nil, fmt.Errorf("usecase: ошибка при получении статистики фото %s: %w", unsplashID, err)
+	}
+
+	// Статистика источника хранится отдельно: views_count и downloads_count считает само приложение,
+	// и upsert их не трогает, поэтому просмотры, накопленные между чтением и записью, не теряются
+	photo := *existing
+	photo.UnsplashViews = stats.Views
+	photo.UnsplashDownloads = stats.Downloads
+	photo.Tags = nil
+
+	if err := uc.photoStorage.UpsertPhoto(ctx, &photo); err != nil {
+		uc.log(ctx).Error("ошибка обновления статистики фото", slog.String("unsplash_id", unsplashID), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при обновлении статистики фото %s: %w", unsplashID, err)
+	}
+
+	uc.invalidateCachedPhoto(ctx, unsplashID)
+	uc.publish(ctx, domain.PhotoUpdatedEvent{EventMeta: domain.NewEventMeta(), Photo: photo})
+	uc.log(ctx).Info("статистика фото обновлена",
+		slog.String("photo_id", photo.ID.String()),
+		slog.Int64("unsplash_views", photo.UnsplashViews),
+		slog.Int64("unsplash_downloads", photo.UnsplashDownloads),
+	)
+	return &photo, nil
+}
+
+// RefreshRecentPhotoStats реализует метод PhotoUseCase
+func (uc *photoUseCase) RefreshRecentPhotoStats(ctx context.Context, limit int, spacing time.Duration) (refreshed int, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.RefreshRecentPhotoStats", trace.WithAttributes(
+		attribute.Int("limit", limit),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("photos.refreshed", refreshed))
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if uc.stats == nil {
+		return 0, ErrStatsUnavailable
+	}
+
+	photos, err := uc.photoStorage.ListRecentlyViewedPhotos(ctx, domain.SourceUnsplash, limit)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения недавно просмотренных фото", slog.Any("error", err))
+		return 0, fmt.Errorf("usecase: ошибка при получении недавно просмотренных фото: %w", err)
+	}
+
+	failed := 0
+	for i, photo := range photos {
+		// Пауза между запросами, чтобы обход не выбирал лимит Unsplash одним залпом
+		if i > 0 {
+			if err := sleepContext(ctx, spacing); err != nil {
+				return refreshed, fmt.Errorf("usecase: обновление статистики прервано: %w", err)
+			}
+		}
+
+		_, err := uc.RefreshPhotoStats(ctx, photo.UnsplashID)
+		var rateLimited *domain.ErrRateLimited
+		switch {
+		case err == nil:
+			refreshed++
+		case errors.As(err, &rateLimited):
+			// Остальные фото обновятся при следующем запуске
+			uc.log(ctx).Warn("лимит запросов исчерпан, обновление статистики остановлено",
+				slog.Int("refreshed", refreshed),
+				slog.Int("remaining", len(photos)-i),
+				slog.Duration("retry_in", rateLimited.RetryAfter()),
+			)
+			return refreshed, nil
+		case ctx.Err() != nil:
+			return refreshed, fmt.Errorf("usecase: обновление статистики прервано: %w", ctx.Err())
+		default:
+			// Фото удалено или Unsplash ответил ошибкой — переходим к следующему, подробности уже в логе
+			failed++
+		}
+	}
+
+	uc.log(ctx).Info("статистика недавно просмотренных фото обновлена",
+		slog.Int("photos", len(photos)),
+		slog.Int("refreshed", refreshed),
+		slog.Int("failed", failed),
+	)
+	return refreshed, nil
+}
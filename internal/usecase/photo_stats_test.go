@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// stubStats — StatsFetcher со статистикой stats; перед ответом вызывает onFetch, если он задан
+type stubStats struct {
+	stats   domain.PhotoStats
+	onFetch func()
+}
+
+func (s *stubStats) FetchPhotoStatistics(context.Context, string) (domain.PhotoStats, error) {
+	if s.onFetch != nil {
+		s.onFetch()
+	}
+	return s.stats, nil
+}
+
+func TestRefreshPhotoStats_KeepsLocalCounters(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	srv := newImageServer(t, testPNG(t, 4, 3))
+	fetcher := &stubFetcher{photos: map[string]domain.Photo{"abc": externalPhoto(srv, "abc")}}
+	stats := &stubStats{stats: domain.PhotoStats{Views: 1000, Downloads: 50}}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, fetcher: fetcher, stats: stats, files: newMemFileStorage()})
+
+	photo, err := uc.GetOrCreatePhotoByUnsplashID(ctx, "abc")
+	if err != nil {
+		t.Fatalf("GetOrCreatePhotoByUnsplashID: %v", err)
+	}
+	for range 2 {
+		if err := st.photos.IncrementViewsCount(ctx, photo.ID); err != nil {
+			t.Fatalf("IncrementViewsCount: %v", err)
+		}
+	}
+	if err := st.photos.IncrementDownloadsCount(ctx, photo.ID); err != nil {
+		t.Fatalf("IncrementDownloadsCount: %v", err)
+	}
+	// Сброс буфера просмотров между чтением фото и записью статистики
+	stats.onFetch = func() {
+		if err := st.photos.AddViews(ctx, map[uuid.UUID]int64{photo.ID: 3}); err != nil {
+			t.Errorf("AddViews: %v", err)
+		}
+	}
+
+	refreshed, err := uc.RefreshPhotoStats(ctx, "abc")
+	if err != nil {
+		t.Fatalf("RefreshPhotoStats: %v", err)
+	}
+
+	stored, err := st.photos.GetPhotoByIDFromDB(ctx, photo.ID)
+	if err != nil {
+		t.Fatalf("GetPhotoByIDFromDB: %v", err)
+	}
+	for _, got := range []*domain.Photo{refreshed, stored} {
+		if got.ViewsCount != 5 || got.DownloadsCount != 1 {
+			t.Errorf("local counters = %d views, %d downloads, want 5 and 1", got.ViewsCount, got.DownloadsCount)
+		}
+		if got.UnsplashViews != 1000 || got.UnsplashDownloads != 50 {
+			t.Errorf("unsplash stats = %d views, %d downloads, want 1000 and 50", got.UnsplashViews, got.UnsplashDownloads)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MaxSearchSuggestLimit — сколько подсказок из истории поиска можно запросить за раз
+const MaxSearchSuggestLimit = 20
+
+// recordSearch сохраняет выполненный поиск в истории. Ошибка только логируется:
+// сбой истории не должен ломать сам поиск
+func (uc *photoUseCase) recordSearch(ctx context.Context, query string, page, perPage, saved int, source string) {
+	if uc.history == nil {
+		return
+	}
+	entry := &domain.SearchQuery{
+		ID:           uuid.New(),
+		Query:        query,
+		Page:         page,
+		PerPage:      perPage,
+		ResultsSaved: saved,
+		Source:       source,
+		CreatedAt:    time.Now(),
+	}
+	if err := uc.history.RecordSearchQuery(ctx, entry); err != nil {
+		uc.log(ctx).Warn("не удалось сохранить поиск в истории", slog.String("query", query), slog.Any("error", err))
+	}
+}
+
+// ListSearchHistory возвращает страницу истории поиска, новые запросы первыми
+func (uc *photoUseCase) ListSearchHistory(ctx context.Context, page, perPage int) ([]domain.SearchQuery, int64, error) {
+	if uc.history == nil {
+		return []domain.SearchQuery{}, 0, nil
+	}
+	queries, err := uc.history.ListSearchQueries(ctx, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения истории поиска", slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении истории поиска: %w", err)
+	}
+	total, err := uc.history.CountSearchQueries(ctx)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта истории поиска", slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте истории поиска: %w", err)
+	}
+	return queries, total, nil
+}
+
+// SuggestSearchQueries возвращает самые частые прошлые запросы, начинающиеся с prefix
+func (uc *photoUseCase) SuggestSearchQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	if uc.history == nil {
+		return []domain.SearchSuggestion{}, nil
+	}
+	prefix = strings.TrimSpace(prefix)
+	limit = min(max(limit, 1), MaxSearchSuggestLimit)
+
+	suggestions, err := uc.history.SuggestSearchQueries(ctx, prefix, limit)
+	if err != nil {
+		uc.log(ctx).Error("ошибка поиска подсказок по истории", slog.String("prefix", prefix), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при поиске подсказок по истории: %w", err)
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SeriesUseCase определяет интерфейс бизнес-логики серий фото.
+// Все методы работают только с сериями пользователя userID: чужая серия
+// неотличима от несуществующей и даёт domain.ErrSeriesNotFound
+type SeriesUseCase interface {
+	// CreateSeries создаёт пустую серию пользователя
+	CreateSeries(ctx context.Context, userID uuid.UUID, title, description string) (*domain.PhotoSeries, error)
+
+	// GetSeriesWithPhotos возвращает серию и первые MaxSeriesPreviewPhotos её фото в порядке серии
+	GetSeriesWithPhotos(ctx context.Context, userID, id uuid.UUID) (*domain.PhotoSeriesWithPhotos, error)
+
+	// ListSeries возвращает страницу серий пользователя и их общее количество
+	ListSeries(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.PhotoSeries, int64, error)
+
+	// UpdateSeries меняет название и/или описание серии и возвращает обновлённую серию
+	UpdateSeries(ctx context.Context, userID, id uuid.UUID, updates domain.PhotoSeriesUpdate) (*domain.PhotoSeries, error)
+
+	// DeleteSeries удаляет серию; фото из неё остаются
+	DeleteSeries(ctx context.Context, userID, id uuid.UUID) error
+
+	// AddPhotoToSeries вставляет фото в серию на позицию position (с нуля), сдвигая следующие;
+	// отрицательная или слишком большая позиция — в конец. Повторное добавление не ошибка,
+	// added == false. Если фото нет, возвращает domain.ErrPhotoNotFound
+	AddPhotoToSeries(ctx context.Context, userID, seriesID, photoID uuid.UUID, position int) (added bool, err error)
+
+	// RemovePhotoFromSeries убирает фото из серии
+	RemovePhotoFromSeries(ctx context.Context, userID, seriesID, photoID uuid.UUID) error
+
+	// ReorderPhotos расставляет фото серии в порядке photoIDs. Если в photoIDs не каждое фото
+	// серии ровно по одному разу, возвращает domain.ErrInvalidSeriesOrder
+	ReorderPhotos(ctx context.Context, userID, seriesID uuid.UUID, photoIDs []uuid.UUID) error
+
+	// ListPhotos возвращает страницу фото серии в порядке серии и их общее количество
+	ListPhotos(ctx context.Context, userID, seriesID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error)
+}
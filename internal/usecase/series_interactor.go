@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+)
+
+// MaxSeriesPreviewPhotos — сколько фото серии отдаёт GetSeriesWithPhotos; остальные — через ListPhotos
+const MaxSeriesPreviewPhotos = 100
+
+// seriesUseCase implements SeriesUseCase
+type seriesUseCase struct {
+	seriesStorage ports.SeriesStorage
+	photoStorage  ports.PhotoStorage
+	logger        *slog.Logger
+}
+
+// NewSeriesUseCase создает новый экземпляр SeriesUseCase
+func NewSeriesUseCase(seriesStorage ports.SeriesStorage, photoStorage ports.PhotoStorage, logger *slog.Logger) SeriesUseCase {
+	return &seriesUseCase{
+		seriesStorage: seriesStorage,
+		photoStorage:  photoStorage,
+		logger:        logger,
+	}
+}
+
+// CreateSeries создаёт серию пользователя
+func (uc *seriesUseCase) CreateSeries(ctx context.Context, userID uuid.UUID, title, description string) (*domain.PhotoSeries, error) {
+	series := &domain.PhotoSeries{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Title:       title,
+		Description: description,
+	}
+	if err := uc.seriesStorage.CreateSeries(ctx, series); err != nil {
+		uc.log(ctx).Error("ошибка создания серии", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при создании серии: %w", err)
+	}
+
+	uc.log(ctx).Info("серия создана", slog.String("series_id", series.ID.String()), slog.String("user_id", userID.String()))
+	return series, nil
+}
+
+// GetSeriesWithPhotos возвращает серию пользователя вместе с первыми фото
+func (uc *seriesUseCase) GetSeriesWithPhotos(ctx context.Context, userID, id uuid.UUID) (*domain.PhotoSeriesWithPhotos, error) {
+	series, err := uc.ownSeries(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	photos, total, err := uc.listPhotos(ctx, id, 1, MaxSeriesPreviewPhotos)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PhotoSeriesWithPhotos{PhotoSeries: *series, Photos: photos, PhotoCount: total}, nil
+}
+
+// ListSeries возвращает страницу серий пользователя
+func (uc *seriesUseCase) ListSeries(ctx context.Context, userID uuid.UUID, page, perPage int) ([]domain.PhotoSeries, int64, error) {
+	series, err := uc.seriesStorage.ListSeriesByUser(ctx, userID, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения серий", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении серий: %w", err)
+	}
+	total, err := uc.seriesStorage.CountSeriesByUser(ctx, userID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта серий", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте серий: %w", err)
+	}
+	return series, total, nil
+}
+
+// UpdateSeries обновляет название и/или описание серии
+func (uc *seriesUseCase) UpdateSeries(ctx context.Context, userID, id uuid.UUID, updates domain.PhotoSeriesUpdate) (*domain.PhotoSeries, error) {
+	if _, err := uc.ownSeries(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if err := uc.seriesStorage.UpdateSeries(ctx, id, updates); err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return nil, err
+		}
+		uc.log(ctx).Error("ошибка обновления серии", slog.String("series_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при обновлении серии: %w", err)
+	}
+
+	uc.log(ctx).Info("серия обновлена", slog.String("series_id", id.String()))
+	return uc.ownSeries(ctx, userID, id)
+}
+
+// DeleteSeries удаляет серию пользователя
+func (uc *seriesUseCase) DeleteSeries(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := uc.ownSeries(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := uc.seriesStorage.DeleteSeries(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return err
+		}
+		uc.log(ctx).Error("ошибка удаления серии", slog.String("series_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при удалении серии: %w", err)
+	}
+
+	uc.log(ctx).Info("серия удалена", slog.String("series_id", id.String()))
+	return nil
+}
+
+// AddPhotoToSeries добавляет существующее неудалённое фото в серию на заданную позицию
+func (uc *seriesUseCase) AddPhotoToSeries(ctx context.Context, userID, seriesID, photoID uuid.UUID, position int) (bool, error) {
+	if _, err := uc.ownSeries(ctx, userID, seriesID); err != nil {
+		return false, err
+	}
+
+	photo, err := uc.photoStorage.GetPhotoByIDFromDB(ctx, photoID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото", slog.String("photo_id", photoID.String()), slog.Any("error", err))
+		return false, fmt.Errorf("usecase: ошибка при получении фото %s: %w", photoID, err)
+	}
+	if photo == nil {
+		return false, domain.ErrPhotoNotFound
+	}
+
+	added, err := uc.seriesStorage.AddPhotoToSeries(ctx, seriesID, photoID, position)
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return false, err
+		}
+		uc.log(ctx).Error("ошибка добавления фото в серию",
+			slog.String("series_id", seriesID.String()),
+			slog.String("photo_id", photoID.String()),
+			slog.Any("error", err),
+		)
+		return false, fmt.Errorf("usecase: ошибка при добавлении фото в серию: %w", err)
+	}
+	return added, nil
+}
+
+// RemovePhotoFromSeries убирает фото из серии
+func (uc *seriesUseCase) RemovePhotoFromSeries(ctx context.Context, userID, seriesID, photoID uuid.UUID) error {
+	if _, err := uc.ownSeries(ctx, userID, seriesID); err != nil {
+		return err
+	}
+	if err := uc.seriesStorage.RemovePhotoFromSeries(ctx, seriesID, photoID); err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return err
+		}
+		uc.log(ctx).Error("ошибка удаления фото из серии",
+			slog.String("series_id", seriesID.String()),
+			slog.String("photo_id", photoID.String()),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("usecase: ошибка при удалении фото из серии: %w", err)
+	}
+	return nil
+}
+
+// ReorderPhotos меняет порядок фото серии
+func (uc *seriesUseCase) ReorderPhotos(ctx context.Context, userID, seriesID uuid.UUID, photoIDs []uuid.UUID) error {
+	if _, err := uc.ownSeries(ctx, userID, seriesID); err != nil {
+		return err
+	}
+	if err := uc.seriesStorage.ReorderSeriesPhotos(ctx, seriesID, photoIDs); err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return err
+		}
+		if errors.Is(err, domain.ErrInvalidSeriesOrder) {
+			uc.log(ctx).Warn("некорректный порядок фото серии", slog.String("series_id", seriesID.String()), slog.Int("photos", len(photoIDs)))
+			return err
+		}
+		uc.log(ctx).Error("ошибка изменения порядка фото серии", slog.String("series_id", seriesID.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при изменении порядка фото серии: %w", err)
+	}
+
+	uc.log(ctx).Info("порядок фото серии изменён", slog.String("series_id", seriesID.String()), slog.Int("photos", len(photoIDs)))
+	return nil
+}
+
+// ListPhotos возвращает страницу фото серии
+func (uc *seriesUseCase) ListPhotos(ctx context.Context, userID, seriesID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error) {
+	if _, err := uc.ownSeries(ctx, userID, seriesID); err != nil {
+		return nil, 0, err
+	}
+	return uc.listPhotos(ctx, seriesID, page, perPage)
+}
+
+// listPhotos получает страницу фото серии и их общее количество без проверки владельца
+func (uc *seriesUseCase) listPhotos(ctx context.Context, seriesID uuid.UUID, page, perPage int) ([]domain.Photo, int64, error) {
+	photos, err := uc.seriesStorage.ListPhotosInSeries(ctx, seriesID, page, perPage)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото серии", slog.String("series_id", seriesID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при получении фото серии: %w", err)
+	}
+	total, err := uc.seriesStorage.CountPhotosInSeries(ctx, seriesID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подсчёта фото серии", slog.String("series_id", seriesID.String()), slog.Any("error", err))
+		return nil, 0, fmt.Errorf("usecase: ошибка при подсчёте фото серии: %w", err)
+	}
+	return photos, total, nil
+}
+
+// ownSeries получает серию и проверяет, что она принадлежит userID.
+// Чужая серия возвращается как несуществующая, чтобы не раскрывать её наличие
+func (uc *seriesUseCase) ownSeries(ctx context.Context, userID, id uuid.UUID) (*domain.PhotoSeries, error) {
+	series, err := uc.seriesStorage.GetSeriesByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrSeriesNotFound) {
+			return nil, err
+		}
+		uc.log(ctx).Error("ошибка получения серии", slog.String("series_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении серии: %w", err)
+	}
+	if series.UserID != userID {
+		uc.log(ctx).Warn("попытка доступа к чужой серии",
+			slog.String("series_id", id.String()),
+			slog.String("user_id", userID.String()),
+		)
+		return nil, domain.ErrSeriesNotFound
+	}
+	return series, nil
+}
+
+func (uc *seriesUseCase) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, uc.logger)
+}
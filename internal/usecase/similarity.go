@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ComputePhotoEmbedding реализует метод PhotoUseCase
+func (uc *photoUseCase) ComputePhotoEmbedding(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.ComputePhotoEmbedding",
+		trace.WithAttributes(attribute.String("photo_id", id.String())))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if uc.embedder == nil {
+		return ErrEmbeddingUnavailable
+	}
+
+	photo, err := uc.photoStorage.GetPhotoByIDFromDB(ctx, id)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото для эмбеддинга", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при получении фото %s для эмбеддинга: %w", id, err)
+	}
+	if photo == nil {
+		return fmt.Errorf("usecase: фото %s для эмбеддинга: %w", id, domain.ErrPhotoNotFound)
+	}
+
+	// Сервис скачивает изображение сам: наша копия в S3 надёжнее ссылки на внешний источник
+	imageURL := photo.S3URL
+	if imageURL == "" {
+		imageURL = photo.OriginalURL
+	}
+
+	vector, err := uc.embedder.ComputeEmbedding(ctx, imageURL)
+	if err != nil {
+		uc.log(ctx).Error("ошибка вычисления эмбеддинга", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при вычислении эмбеддинга фото %s: %w", id, err)
+	}
+	photo.EmbeddingVector = vector
+
+	if err := uc.photoStorage.UpdatePhotoEmbedding(ctx, id, photo.EmbeddingVector); err != nil {
+		uc.log(ctx).Error("ошибка сохранения эмбеддинга", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при сохранении эмбеддинга фото %s: %w", id, err)
+	}
+
+	uc.log(ctx).Info("эмбеддинг фото сохранён", slog.String("photo_id", id.String()))
+	return nil
+}
+
+// FindSimilarPhotos реализует метод PhotoUseCase
+func (uc *photoUseCase) FindSimilarPhotos(ctx context.Context, id uuid.UUID, limit int) ([]domain.Photo, error) {
+	vector, err := uc.photoStorage.GetPhotoEmbedding(ctx, id)
+	if err != nil {
+		if !errors.Is(err, domain.ErrPhotoNotFound) {
+			uc.log(ctx).Error("ошибка получения эмбеддинга фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		}
+		return nil, fmt.Errorf("usecase: ошибка при поиске фото, похожих на %s: %w", id, err)
+	}
+	if vector == nil {
+		return nil, fmt.Errorf("usecase: фото %s: %w", id, domain.ErrEmbeddingNotReady)
+	}
+
+	photos, err := uc.photoStorage.FindSimilarPhotos(ctx, vector, id, limit)
+	if err != nil {
+		uc.log(ctx).Error("ошибка поиска похожих фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при поиске фото, похожих на %s: %w", id, err)
+	}
+	uc.log(ctx).Info("найдены похожие фото", slog.String("photo_id", id.String()), slog.Int("count", len(photos)))
+	return photos, nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxTagCloudLimit — сколько тегов облака можно запросить за раз
+	MaxTagCloudLimit = 200
+	// MaxTagSearchLimit — сколько тегов можно получить за раз при поиске по префиксу
+	MaxTagSearchLimit = 20
+	tagCloudCacheTTL  = 5 * time.Minute
+)
+
+// GetTagCloud реализует метод PhotoUseCase. Облако кешируется отдельно для каждого limit (ключ tagcloud:<limit>)
+func (uc *photoUseCase) GetTagCloud(ctx context.Context, limit int) ([]domain.TagCount, error) {
+	limit = min(max(limit, 1), MaxTagCloudLimit)
+	key := "tagcloud:" + strconv.Itoa(limit)
+
+	if uc.cache != nil {
+		data, err := uc.cache.Get(ctx, key)
+		switch {
+		case err == nil:
+			var tags []domain.TagCount
+			if err := json.Unmarshal(data, &tags); err != nil {
+				uc.log(ctx).Warn("повреждённая запись в кеше", slog.String("key", key), slog.Any("error", err))
+				break
+			}
+			return tags, nil
+		case !errors.Is(err, ports.ErrCacheMiss):
+			uc.log(ctx).Warn("ошибка чтения из кеша", slog.String("key", key), slog.Any("error", err))
+		}
+	}
+
+	tags, err := uc.photoStorage.GetTagCloud(ctx, limit)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения облака тегов", slog.Int("limit", limit), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении облака тегов: %w", err)
+	}
+
+	if uc.cache != nil {
+		if data, err := json.Marshal(tags); err == nil {
+			if err := uc.cache.Set(ctx, key, data, tagCloudCacheTTL); err != nil {
+				uc.log(ctx).Warn("ошибка записи в кеш", slog.String("key", key), slog.Any("error", err))
+			}
+		}
+	}
+	return tags, nil
+}
+
+// SearchTags реализует метод PhotoUseCase
+func (uc *photoUseCase) SearchTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	limit = min(max(limit, 1), MaxTagSearchLimit)
+
+	tags, err := uc.photoStorage.SearchTagsByPrefix(ctx, prefix, limit)
+	if err != nil {
+		uc.log(ctx).Error("ошибка поиска тегов", slog.String("prefix", prefix), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при поиске тегов: %w", err)
+	}
+	return tags, nil
+}
+
+// BatchUpdateTags реализует метод PhotoUseCase. Найденные фото определяются заранее одним запросом,
+// чтобы вернуть клиенту список не найденных ID; изменённые фото убираются из кеша
+func (uc *photoUseCase) BatchUpdateTags(ctx context.Context, photoIDs []uuid.UUID, addTags, removeTags []string) (BatchTagResult, error) {
+	photos, err := uc.photoStorage.GetPhotosByIDs(ctx, photoIDs)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения фото для изменения тегов", slog.Int("count", len(photoIDs)), slog.Any("error", err))
+		return BatchTagResult{}, fmt.Errorf("usecase: ошибка при изменении тегов фото: %w", err)
+	}
+
+	found := make(map[uuid.UUID]struct{}, len(photos))
+	for _, photo := range photos {
+		found[photo.ID] = struct{}{}
+	}
+	result := BatchTagResult{NotFound: []uuid.UUID{}}
+	existing := make([]uuid.UUID, 0, len(photos))
+	for _, id := range photoIDs {
+		if _, ok := found[id]; ok {
+			existing = append(existing, id)
+		} else {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+	if len(existing) == 0 {
+		uc.log(ctx).Warn("фото для изменения тегов не найдены", slog.Int("count", len(photoIDs)))
+		return result, nil
+	}
+
+	result.Updated, err = uc.photoStorage.BatchUpdateTags(ctx, existing, addTags, removeTags)
+	if err != nil {
+		uc.log(ctx).Error("ошибка пакетного изменения тегов", slog.Int("count", len(existing)), slog.Any("error", err))
+		return BatchTagResult{}, fmt.Errorf("usecase: ошибка при изменении тегов фото: %w", err)
+	}
+
+	for _, photo := range photos {
+		uc.invalidateCachedPhoto(ctx, photo.UnsplashID)
+	}
+	uc.log(ctx).Info("теги фото изменены",
+		slog.Int64("updated", result.Updated),
+		slog.Int("not_found", len(result.NotFound)),
+		slog.Int("added", len(addTags)),
+		slog.Int("removed", len(removeTags)),
+	)
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestBatchUpdateTags_TenPhotosEightTagged(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users})
+	ownerID, err := st.users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("GetOrCreateSystemUser: %v", err)
+	}
+
+	photos := make([]*domain.Photo, 10)
+	for i := range photos {
+		id := fmt.Sprintf("p%d", i)
+		photos[i] = &domain.Photo{
+			UnsplashID:  id,
+			UserID:      ownerID,
+			S3URL:       "http://localhost:9000/photos/unsplash-photos/" + id,
+			Title:       "photo " + id,
+			AuthorName:  "author",
+			Width:       640,
+			Height:      480,
+			OriginalURL: "https://images.example.com/" + id,
+			Tags:        []domain.Tag{{Name: "indoor"}, {Name: "keep"}},
+		}
+	}
+	if _, err := st.photos.SavePhotos(ctx, photos); err != nil {
+		t.Fatalf("SavePhotos: %v", err)
+	}
+
+	missing := uuid.New()
+	ids := []uuid.UUID{missing}
+	for _, photo := range photos[:8] {
+		ids = append(ids, photo.ID)
+	}
+	result, err := uc.BatchUpdateTags(ctx, ids, []string{"Nature", "sunset", " nature "}, []string{"indoor"})
+	if err != nil {
+		t.Fatalf("BatchUpdateTags: %v", err)
+	}
+	if result.Updated != 8 {
+		t.Errorf("updated = %d, want 8", result.Updated)
+	}
+	if len(result.NotFound) != 1 || result.NotFound[0] != missing {
+		t.Errorf("not found = %v, want [%s]", result.NotFound, missing)
+	}
+
+	var links []struct {
+		PhotoID uuid.UUID `db:"photo_id"`
+		Name    string    `db:"name"`
+	}
+	if err := st.db.SelectContext(ctx, &links,
+		`SELECT pt.photo_id, t.name FROM photo_tags pt JOIN tags t ON t.id = pt.tag_id`); err != nil {
+		t.Fatalf("select photo_tags: %v", err)
+	}
+	tagsOf := make(map[uuid.UUID][]string)
+	for _, link := range links {
+		tagsOf[link.PhotoID] = append(tagsOf[link.PhotoID], link.Name)
+	}
+	for i, photo := range photos {
+		want := "keep,nature,sunset"
+		if i >= 8 {
+			want = "indoor,keep"
+		}
+		got := tagsOf[photo.ID]
+		sort.Strings(got)
+		if strings.Join(got, ",") != want {
+			t.Errorf("photo %s tags = %v, want %s", photo.UnsplashID, got, want)
+		}
+	}
+
+	var names []string
+	if err := st.db.SelectContext(ctx, &names, `SELECT name FROM tags ORDER BY name`); err != nil {
+		t.Fatalf("select tags: %v", err)
+	}
+	if strings.Join(names, ",") != "indoor,keep,nature,sunset" {
+		t.Errorf("tags = %v, want each name once, lowercased", names)
+	}
+}
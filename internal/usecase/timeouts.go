@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// StepTimeouts ограничивает отдельные шаги обработки фото, чтобы медленный внешний сервис
+// проваливал свой шаг, а не весь запрос целиком. Нулевое значение — шаг без собственного ограничения
+type StepTimeouts struct {
+	Fetch    time.Duration // запрос к API источника фото
+	Download time.Duration // скачивание оригинала, включая чтение тела по ходу загрузки в S3
+	Upload   time.Duration // загрузка файла в S3
+}
+
+// withStepTimeout возвращает контекст шага с ограничением d; при d <= 0 — просто отменяемый контекст
+func withStepTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// downloadOriginal начинает скачивание файла по url. Таймаут скачивания действует, пока тело ответа
+// не закрыто: тело читается потоком во время загрузки в S3. Close тела освобождает контекст
+func (uc *photoUseCase) downloadOriginal(ctx context.Context, url string) (*http.Response, error) {
+	ctx, cancel := withStepTimeout(ctx, uc.timeouts.Download)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose отменяет контекст запроса при закрытии тела ответа
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CheckUnsplashAuthor реализует метод PhotoUseCase
+func (uc *photoUseCase) CheckUnsplashAuthor(ctx context.Context, username string) (err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.CheckUnsplashAuthor", trace.WithAttributes(
+		attribute.String("username", username),
+	))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if uc.authors == nil {
+		return ErrAuthorImportUnavailable
+	}
+	// Отдельного запроса профиля нет в AuthorFetcher: достаточно первой страницы из одного фото
+	fetchCtx, cancelFetch := withStepTimeout(ctx, uc.timeouts.Fetch)
+	defer cancelFetch()
+	if _, err := uc.authors.FetchUserPhotos(fetchCtx, username, 1, 1); err != nil {
+		return fmt.Errorf("usecase: ошибка при проверке автора %s: %w", username, err)
+	}
+	return nil
+}
+
+// ImportAuthorPhotos реализует метод PhotoUseCase
+func (uc *photoUseCase) ImportAuthorPhotos(ctx context.Context, username string, perPage, maxPhotos int) (saved int, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.ImportAuthorPhotos", trace.WithAttributes(
+		attribute.String("username", username),
+		attribute.Int("per_page", perPage),
+		attribute.Int("max_photos", maxPhotos),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("photos.saved", saved))
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if uc.authors == nil {
+		return 0, ErrAuthorImportUnavailable
+	}
+	if perPage <= 0 || perPage > collectionPageSize {
+		perPage = collectionPageSize
+	}
+
+	systemUserID, err := uc.userStorage.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения системного пользователя", slog.Any("error", err))
+		return 0, fmt.Errorf("usecase: не удалось получить или создать системного пользователя для автора: %w", err)
+	}
+
+	uc.log(ctx).Info("импорт фото автора начат",
+		slog.String("username", username),
+		slog.Int("per_page", perPage),
+		slog.Int("max_photos", maxPhotos),
+	)
+
+	page, seen := 1, 0
+	for maxPhotos <= 0 || seen < maxPhotos {
+		fetchCtx, cancelFetch := withStepTimeout(ctx, uc.timeouts.Fetch)
+		result, err := uc.authors.FetchUserPhotos(fetchCtx, username, page, perPage)
+		cancelFetch()
+		var rateLimited *domain.ErrRateLimited
+		if errors.As(err, &rateLimited) {
+			// Ждём сброса лимита и запрашиваем ту же страницу снова, не теряя пройденные
+			wait := rateLimited.RetryAfter()
+			uc.log(ctx).Warn("лимит запросов исчерпан, импорт фото автора приостановлен",
+				slog.String("username", username),
+				slog.Int("page", page),
+				slog.Duration("retry_in", wait),
+			)
+			if err := sleepContext(ctx, wait); err != nil {
+				return saved, fmt.Errorf("usecase: импорт фото автора %s прерван на странице %d: %w", username, page, err)
+			}
+			continue
+		}
+		if err != nil {
+			uc.log(ctx).Error("ошибка получения страницы фото автора",
+				slog.String("username", username),
+				slog.Int("page", page),
+				slog.Any("error", err),
+			)
+			return saved, fmt.Errorf("usecase: ошибка при получении страницы %d фото автора %s: %w", page, username, err)
+		}
+		photos := result.Photos
+		if len(photos) == 0 {
+			break
+		}
+		// Последняя страница может перешагнуть предел — берём из неё только недостающие фото
+		if maxPhotos > 0 && seen+len(photos) > maxPhotos {
+			photos = photos[:maxPhotos-seen]
+		}
+		seen += len(photos)
+
+		unsplashIDs := make([]string, 0, len(photos))
+		for _, photo := range photos {
+			unsplashIDs = append(unsplashIDs, photo.UnsplashID)
+		}
+		existingIDs, err := uc.photoStorage.ExistsByUnsplashIDs(ctx, unsplashIDs)
+		if err != nil {
+			uc.log(ctx).Error("ошибка проверки существующих фото", slog.Any("error", err))
+			return saved, fmt.Errorf("usecase: ошибка при проверке существующих фото: %w", err)
+		}
+		fresh := make([]domain.Photo, 0, len(photos))
+		for _, photo := range photos {
+			if _, ok := existingIDs[photo.UnsplashID]; !ok {
+				fresh = append(fresh, photo)
+			}
+		}
+
+		inserted, _, err := uc.uploadAndSaveExternalPhotos(ctx, fresh, systemUserID)
+		if err != nil {
+			return saved, err
+		}
+		saved += len(inserted)
+
+		uc.log(ctx).Info("страница фото автора обработана",
+			slog.String("username", username),
+			slog.Int("page", page),
+			slog.Int("found", len(photos)),
+			slog.Int("skipped", len(photos)-len(fresh)),
+			slog.Int("saved", len(inserted)),
+			slog.Int("total_saved", saved),
+			slog.Int("total_pages", result.TotalPages),
+		)
+		page++
+		if result.TotalPages > 0 && page > result.TotalPages {
+			break
+		}
+	}
+
+	uc.log(ctx).Info("импорт фото автора завершён",
+		slog.String("username", username),
+		slog.Int("pages", page-1),
+		slog.Int("seen", seen),
+		slog.Int("saved", saved),
+	)
+	return saved, nil
+}
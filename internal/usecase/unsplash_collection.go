@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// collectionPageSize — размер страницы коллекции по умолчанию, максимум, который отдаёт Unsplash
+const collectionPageSize = 30
+
+// ImportUnsplashCollection реализует метод PhotoUseCase
+func (uc *photoUseCase) ImportUnsplashCollection(ctx context.Context, collectionID string, startPage, perPage int) (saved int, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.ImportUnsplashCollection", trace.WithAttributes(
+		attribute.String("collection_id", collectionID),
+		attribute.Int("start_page", startPage),
+		attribute.Int("per_page", perPage),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("photos.saved", saved))
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if uc.collections == nil {
+		return 0, ErrCollectionImportUnavailable
+	}
+	if startPage <= 0 {
+		startPage = 1
+	}
+	if perPage <= 0 || perPage > collectionPageSize {
+		perPage = collectionPageSize
+	}
+
+	systemUserID, err := uc.userStorage.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения системного пользователя", slog.Any("error", err))
+		return 0, fmt.Errorf("usecase: не удалось получить или создать системного пользователя для коллекции: %w", err)
+	}
+
+	uc.log(ctx).Info("импорт коллекции начат",
+		slog.String("collection_id", collectionID),
+		slog.Int("start_page", startPage),
+		slog.Int("per_page", perPage),
+	)
+
+	page := startPage
+	for {
+		fetchCtx, cancelFetch := withStepTimeout(ctx, uc.timeouts.Fetch)
+		result, err := uc.collections.FetchCollectionPhotos(fetchCtx, collectionID, page, perPage)
+		cancelFetch()
+		var rateLimited *domain.ErrRateLimited
+		if errors.As(err, &rateLimited) {
+			// Ждём сброса лимита и запрашиваем ту же страницу снова, не теряя пройденные
+			wait := rateLimited.RetryAfter()
+			uc.log(ctx).Warn("лимит запросов исчерпан, импорт коллекции приостановлен",
+				slog.String("collection_id", collectionID),
+				slog.Int("page", page),
+				slog.Duration("retry_in", wait),
+			)
+			if err := sleepContext(ctx, wait); err != nil {
+				return saved, fmt.Errorf("usecase: импорт коллекции %s прерван на странице %d: %w", collectionID, page, err)
+			}
+			continue
+		}
+		if err != nil {
+			uc.log(ctx).Error("ошибка получения страницы коллекции",
+				slog.String("collection_id", collectionID),
+				slog.Int("page", page),
+				slog.Any("error", err),
+			)
+			return saved, fmt.Errorf("usecase: ошибка при получении страницы %d коллекции %s: %w", page, collectionID, err)
+		}
+		photos := result.Photos
+		if len(photos) == 0 {
+			break
+		}
+
+		// Одним запросом проверяем, какие фото страницы уже есть в бд (в том числе в корзине)
+		unsplashIDs := make([]string, 0, len(photos))
+		for _, photo := range photos {
+			unsplashIDs = append(unsplashIDs, photo.UnsplashID)
+		}
+		existingIDs, err := uc.photoStorage.ExistsByUnsplashIDs(ctx, unsplashIDs)
+		if err != nil {
+			uc.log(ctx).Error("ошибка проверки существующих фото", slog.Any("error", err))
+			return saved, fmt.Errorf("usecase: ошибка при проверке существующих фото: %w", err)
+		}
+		fresh := make([]domain.Photo, 0, len(photos))
+		for _, photo := range photos {
+			if _, ok := existingIDs[photo.UnsplashID]; !ok {
+				fresh = append(fresh, photo)
+			}
+		}
+
+		inserted, _, err := uc.uploadAndSaveExternalPhotos(ctx, fresh, systemUserID)
+		if err != nil {
+			return saved, err
+		}
+		saved += len(inserted)
+
+		uc.log(ctx).Info("страница коллекции обработана",
+			slog.String("collection_id", collectionID),
+			slog.Int("page", page),
+			slog.Int("found", len(photos)),
+			slog.Int("skipped", len(photos)-len(fresh)),
+			slog.Int("saved", len(inserted)),
+			slog.Int("total_saved", saved),
+			slog.Int("total_pages", result.TotalPages),
+		)
+		page++
+		// Размер коллекции известен — не запрашиваем заведомо пустую страницу после последней
+		if result.TotalPages > 0 && page > result.TotalPages {
+			break
+		}
+	}
+
+	uc.log(ctx).Info("импорт коллекции завершён",
+		slog.String("collection_id", collectionID),
+		slog.Int("pages", page-startPage),
+		slog.Int("saved", saved),
+	)
+	return saved, nil
+}
+
+// sleepContext ждёт d или отмены контекста; во втором случае возвращает ошибку контекста
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // регистрация декодера для image.DecodeConfig
+	_ "image/jpeg" // регистрация декодера для image.DecodeConfig
+	_ "image/png"  // регистрация декодера для image.DecodeConfig
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	_ "golang.org/x/image/webp" // регистрация декодера для image.DecodeConfig
+)
+
+// uploadsPrefix — префикс ключей S3 для фото, загруженных пользователями
+const uploadsPrefix = "uploads/"
+
+// uploadExtensions — допустимые MIME-типы загружаемых фото и расширения ключей в S3
+var uploadExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// UploadPhoto сохраняет фото, загруженное пользователем: проверяет тип по содержимому,
+// читает размеры изображения, загружает файл в S3 под uploads/<uuid>.<ext> и сохраняет запись в бд
+func (uc *photoUseCase) UploadPhoto(ctx context.Context, in UploadPhotoInput) (_ *domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.UploadPhoto")
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// Тип определяем по первым байтам, а не по заголовку от клиента
+	head := make([]byte, 512)
+	n, err := io.ReadFull(in.File, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("usecase: ошибка чтения загруженного файла: %w", err)
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := uploadExtensions[contentType]
+	if !ok {
+		uc.log(ctx).Warn("неподдерживаемый тип загружаемого файла", slog.String("content_type", contentType))
+		return nil, fmt.Errorf("usecase: тип %s не поддерживается: %w", contentType, ErrUnsupportedImageType)
+	}
+
+	if _, err := in.File.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("usecase: ошибка чтения загруженного файла: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(in.File)
+	if err != nil {
+		uc.log(ctx).Warn("не удалось прочитать изображение", slog.String("content_type", contentType), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: файл не является корректным изображением %s: %w", contentType, ErrUnsupportedImageType)
+	}
+	if _, err := in.File.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("usecase: ошибка чтения загруженного файла: %w", err)
+	}
+
+	user, err := uc.userStorage.GetUserByID(ctx, in.UserID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения пользователя", slog.String("user_id", in.UserID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении пользователя %s: %w", in.UserID, err)
+	}
+
+	photoID := uuid.New()
+	s3Key := uploadsPrefix + photoID.String() + ext
+
+	s3URL, size, colors, err := uc.uploadWithPalette(ctx, s3Key, in.File, contentType)
+	if err != nil {
+		uc.log(ctx).Error("ошибка загрузки в S3", slog.String("s3_key", s3Key), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка загрузки фото в S3: %w", err)
+	}
+
+	photo := &domain.Photo{
+		ID:             photoID,
+		ExternalSource: domain.SourceUser,
+		UserID:         user.ID,
+		S3URL:          s3URL,
+		Title:          strings.TrimSpace(in.Title),
+		Description:    strings.TrimSpace(in.Description),
+		AuthorName:     user.Username,
+		Width:          cfg.Width,
+		Height:         cfg.Height,
+		OriginalURL:    s3URL,
+		SizeBytes:      size,
+		MimeType:       contentType,
+		DominantColors: colors,
+	}
+	// у загрузки пользователя нет unsplash_id, поэтому конфликта при вставке не бывает
+	if _, err := uc.photoStorage.SavePhotoTx(ctx, photo); err != nil {
+		uc.log(ctx).Error("ошибка сохранения загруженного фото в БД", slog.String("photo_id", photoID.String()), slog.Any("error", err))
+		uc.deleteUploadedFile(ctx, s3Key)
+		return nil, fmt.Errorf("usecase: ошибка при сохранении фото %s в локальной БД: %w", photoID, err)
+	}
+
+	uc.log(ctx).Info("загруженное фото сохранено",
+		slog.String("photo_id", photoID.String()),
+		slog.String("user_id", user.ID.String()),
+		slog.String("content_type", contentType),
+	)
+	uc.publish(ctx, domain.PhotoCreatedEvent{EventMeta: domain.NewEventMeta(), Photo: *photo})
+	return photo, nil
+}
+
+// uploadIntentGrace — сколько после истечения ссылки загрузку ещё можно подтвердить: PUT, начатый
+// в последний момент, успевает завершиться. Очистка удаляет загрузки только после этого запаса
+const uploadIntentGrace = 5 * time.Minute
+
+// CreateUploadIntent сохраняет ожидающую загрузку с ключом uploads/<uuid>.<ext> вместе с pending-фото
+// и подписывает ссылку, по которой клиент загрузит файл в S3 сам, минуя сервер
+func (uc *photoUseCase) CreateUploadIntent(ctx context.Context, in UploadIntentInput) (_ *UploadIntentResult, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.CreateUploadIntent",
+		trace.WithAttributes(attribute.String("content_type", in.ContentType), attribute.Int64("size_bytes", in.SizeBytes)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	ext, ok := uploadExtensions[in.ContentType]
+	if !ok {
+		uc.log(ctx).Warn("неподдерживаемый тип загружаемого файла", slog.String("content_type", in.ContentType))
+		return nil, fmt.Errorf("usecase: тип %s не поддерживается: %w", in.ContentType, ErrUnsupportedImageType)
+	}
+
+	user, err := uc.userStorage.GetUserByID(ctx, in.UserID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения пользователя", slog.String("user_id", in.UserID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении пользователя %s: %w", in.UserID, err)
+	}
+
+	now := time.Now()
+	intent := domain.UploadIntent{
+		ID:          uuid.New(),
+		UserID:      in.UserID,
+		ContentType: in.ContentType,
+		SizeBytes:   in.SizeBytes,
+		Title:       strings.TrimSpace(in.Title),
+		Description: strings.TrimSpace(in.Description),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(in.TTL),
+	}
+	intent.S3Key = uploadsPrefix + intent.ID.String() + ext
+
+	uploadURL, err := uc.fileStorage.GeneratePresignedPutURL(ctx, intent.S3Key, intent.ContentType, intent.SizeBytes, in.TTL)
+	if err != nil {
+		uc.log(ctx).Error("ошибка подписи ссылки на загрузку", slog.String("s3_key", intent.S3Key), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при подписи ссылки на загрузку фото: %w", err)
+	}
+	// фото создаётся сразу, но до подтверждения загрузки остаётся pending и в выдачу не попадает
+	pending := domain.Photo{
+		ID:             intent.ID,
+		ExternalSource: domain.SourceUser,
+		UserID:         user.ID,
+		Title:          intent.Title,
+		Description:    intent.Description,
+		AuthorName:     user.Username,
+		SizeBytes:      intent.SizeBytes,
+		MimeType:       intent.ContentType,
+		Status:         domain.PhotoStatusPending,
+	}
+	if err := uc.photoStorage.CreateUploadIntent(ctx, &intent, &pending); err != nil {
+		uc.log(ctx).Error("ошибка сохранения ожидающей загрузки", slog.String("id", intent.ID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при сохранении ожидающей загрузки: %w", err)
+	}
+
+	uc.log(ctx).Info("ожидающая загрузка создана",
+		slog.String("id", intent.ID.String()),
+		slog.String("user_id", in.UserID.String()),
+		slog.String("content_type", intent.ContentType),
+		slog.Int64("size_bytes", intent.SizeBytes),
+	)
+	return &UploadIntentResult{Intent: intent, Photo: pending, UploadURL: uploadURL}, nil
+}
+
+// CompleteUpload сверяет файл ожидающей загрузки с заявленными размером и типом, читает размеры
+// изображения и палитру и переводит pending-фото загрузки в active. Фото активирует только одно
+// из параллельных подтверждений одной загрузки, остальные получают domain.ErrUploadIntentNotFound
+func (uc *photoUseCase) CompleteUpload(ctx context.Context, userID, id uuid.UUID) (_ *domain.Photo, err error) {
+	ctx, span := tracer.Start(ctx, "PhotoUseCase.CompleteUpload",
+		trace.WithAttributes(attribute.String("upload_intent_id", id.String())))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	intent, err := uc.photoStorage.GetUploadIntent(ctx, id)
+	if err != nil {
+		if !errors.Is(err, domain.ErrUploadIntentNotFound) {
+			uc.log(ctx).Error("ошибка получения ожидающей загрузки", slog.String("id", id.String()), slog.Any("error", err))
+		}
+		return nil, fmt.Errorf("usecase: ошибка при получении ожидающей загрузки %s: %w", id, err)
+	}
+	// чужую загрузку не отличить от отсутствующей
+	if intent.UserID != userID || time.Now().After(intent.ExpiresAt.Add(uploadIntentGrace)) {
+		uc.log(ctx).Warn("ожидающая загрузка чужая или истекла",
+			slog.String("id", id.String()),
+			slog.String("user_id", userID.String()),
+			slog.Time("expires_at", intent.ExpiresAt),
+		)
+		return nil, fmt.Errorf("usecase: загрузка %s: %w", id, domain.ErrUploadIntentNotFound)
+	}
+
+	info, err := uc.fileStorage.StatFile(ctx, intent.S3Key)
+	if errors.Is(err, ErrFileNotFound) {
+		uc.log(ctx).Warn("файл загрузки ещё не получен", slog.String("id", id.String()), slog.String("s3_key", intent.S3Key))
+		return nil, fmt.Errorf("usecase: загрузка %s: %w", id, ErrUploadNotReceived)
+	}
+	if err != nil {
+		uc.log(ctx).Error("ошибка проверки файла загрузки", slog.String("s3_key", intent.S3Key), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при проверке файла загрузки %s: %w", id, err)
+	}
+	if info.Size != intent.SizeBytes {
+		uc.log(ctx).Warn("размер файла загрузки не совпадает с заявленным",
+			slog.String("id", id.String()),
+			slog.Int64("size_bytes", info.Size),
+			slog.Int64("declared_size_bytes", intent.SizeBytes),
+		)
+		return nil, fmt.Errorf("usecase: размер файла %d байт вместо заявленных %d: %w", info.Size, intent.SizeBytes, ErrUploadMismatch)
+	}
+
+	cfg, colors, err := uc.inspectUploadedFile(ctx, intent)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &domain.Photo{
+		ID:             intent.ID,
+		S3URL:          info.URL,
+		Width:          cfg.Width,
+		Height:         cfg.Height,
+		OriginalURL:    info.URL,
+		SizeBytes:      info.Size,
+		MimeType:       intent.ContentType,
+		DominantColors: colors,
+	}
+	// файл не удаляется при ошибке: если фото уже активировал параллельный запрос, файл принадлежит ему,
+	// а брошенную загрузку вместе с файлом удалит CleanupExpiredUploadIntents
+	if err := uc.photoStorage.ActivateUploadedPhoto(ctx, photo); err != nil {
+		if !errors.Is(err, domain.ErrUploadIntentNotFound) {
+			uc.log(ctx).Error("ошибка активации загруженного фото", slog.String("photo_id", id.String()), slog.Any("error", err))
+		}
+		return nil, fmt.Errorf("usecase: ошибка при подтверждении загрузки %s: %w", id, err)
+	}
+
+	uc.log(ctx).Info("загрузка подтверждена, фото сохранено",
+		slog.String("photo_id", photo.ID.String()),
+		slog.String("user_id", userID.String()),
+		slog.String("content_type", photo.MimeType),
+	)
+	uc.publish(ctx, domain.PhotoCreatedEvent{EventMeta: domain.NewEventMeta(), Photo: *photo})
+	return photo, nil
+}
+
+// inspectUploadedFile читает файл загрузки из S3: тип по содержимому должен совпасть с заявленным,
+// затем читаются размеры изображения и из того же потока извлекается палитра
+func (uc *photoUseCase) inspectUploadedFile(ctx context.Context, intent *domain.UploadIntent) (image.Config, domain.ColorPalette, error) {
+	file, err := uc.fileStorage.GetFile(ctx, intent.S3Key)
+	if err != nil {
+		uc.log(ctx).Error("ошибка чтения файла загрузки", slog.String("s3_key", intent.S3Key), slog.Any("error", err))
+		return image.Config{}, nil, fmt.Errorf("usecase: ошибка чтения файла загрузки %s: %w", intent.ID, err)
+	}
+	defer file.Close()
+	r := io.LimitReader(file, intent.SizeBytes)
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return image.Config{}, nil, fmt.Errorf("usecase: ошибка чтения файла загрузки %s: %w", intent.ID, err)
+	}
+	head = head[:n]
+	if contentType := http.DetectContentType(head); contentType != intent.ContentType {
+		uc.log(ctx).Warn("тип файла загрузки не совпадает с заявленным",
+			slog.String("id", intent.ID.String()),
+			slog.String("content_type", contentType),
+			slog.String("declared_content_type", intent.ContentType),
+		)
+		return image.Config{}, nil, fmt.Errorf("usecase: файл типа %s вместо заявленного %s: %w", contentType, intent.ContentType, ErrUploadMismatch)
+	}
+
+	// DecodeConfig читает только начало файла; прочитанное копится в consumed, чтобы палитра получила файл целиком
+	var consumed bytes.Buffer
+	stream := io.MultiReader(bytes.NewReader(head), r)
+	cfg, _, err := image.DecodeConfig(io.TeeReader(stream, &consumed))
+	if err != nil {
+		uc.log(ctx).Warn("не удалось прочитать изображение", slog.String("content_type", intent.ContentType), slog.Any("error", err))
+		return image.Config{}, nil, fmt.Errorf("usecase: файл не является корректным изображением %s: %w", intent.ContentType, ErrUnsupportedImageType)
+	}
+
+	if uc.palette == nil {
+		return cfg, nil, nil
+	}
+	colors, err := uc.palette.ExtractPalette(ctx, io.MultiReader(&consumed, stream), paletteSize)
+	if err != nil {
+		uc.log(ctx).Warn("не удалось извлечь палитру фото", slog.String("s3_key", intent.S3Key), slog.Any("error", err))
+	}
+	return cfg, colors, nil
+}
+
+// CleanupExpiredUploadIntents удаляет загрузки, ссылка которых истекла больше uploadIntentGrace назад,
+// и их файлы, если клиент успел их загрузить
+func (uc *photoUseCase) CleanupExpiredUploadIntents(ctx context.Context) (int, error) {
+	expired, err := uc.photoStorage.DeleteExpiredUploadIntents(ctx, time.Now().Add(-uploadIntentGrace))
+	if err != nil {
+		uc.log(ctx).Error("ошибка очистки брошенных загрузок", slog.Any("error", err))
+		return 0, fmt.Errorf("usecase: ошибка при очистке брошенных загрузок: %w", err)
+	}
+
+	keys := make([]string, 0, len(expired))
+	for _, intent := range expired {
+		keys = append(keys, intent.S3Key)
+	}
+	// удаление отсутствующего ключа в S3 не ошибка, поэтому файлы удаляются без проверки, загружены ли они
+	if err := uc.fileStorage.DeleteFiles(ctx, keys); err != nil {
+		uc.log(ctx).Error("ошибка удаления файлов брошенных загрузок", slog.Int("count", len(keys)), slog.Any("error", err))
+	}
+
+	uc.log(ctx).Info("брошенные загрузки очищены", slog.Int("deleted", len(expired)))
+	return len(expired), nil
+}
+
+// photoFileKey возвращает ключ файла фото в S3
+func photoFileKey(photo domain.Photo) string {
+	if photo.ExternalSource == domain.SourceUser {
+		// ключ загрузки — uploads/<uuid>.<ext>, последний сегмент URL совпадает с ним
+		return uploadsPrefix + path.Base(photo.S3URL)
+	}
+	// фото, сохранённые до перехода на ключи с ID записи, лежат по ключу без него
+	if legacy := unsplashPhotosPrefix + photo.UnsplashID; strings.HasSuffix(photo.S3URL, "/"+legacy) {
+		return legacy
+	}
+	return externalFileKey(photo)
+}
+
+// externalFileKey возвращает ключ, под которым загружается оригинал фото внешнего источника:
+// unsplash-photos/<unsplash_id>/<id>. ID записи в ключе нужен, чтобы запрос, проигравший гонку
+// за сохранение фото, не перезаписал файл победителя и мог удалить свой
+func externalFileKey(photo domain.Photo) string {
+	return unsplashPhotosPrefix + photo.UnsplashID + "/" + photo.ID.String()
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// createTestUploadIntent создаёт ожидающую загрузку файла contentType размером size для пользователя userID
+func createTestUploadIntent(t *testing.T, uc PhotoUseCase, userID uuid.UUID, contentType string, size int64, ttl time.Duration) domain.UploadIntent {
+	t.Helper()
+	result, err := uc.CreateUploadIntent(context.Background(), UploadIntentInput{
+		UserID:      userID,
+		Title:       "upload",
+		ContentType: contentType,
+		SizeBytes:   size,
+		TTL:         ttl,
+	})
+	if err != nil {
+		t.Fatalf("CreateUploadIntent: %v", err)
+	}
+	if result.Photo.ID != result.Intent.ID || result.Photo.Status != domain.PhotoStatusPending {
+		t.Fatalf("intent photo = %s (%s), want %s pending", result.Photo.ID, result.Photo.Status, result.Intent.ID)
+	}
+	return result.Intent
+}
+
+func TestCompleteUpload_Errors(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, files: files})
+	ownerID, err := st.users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("GetOrCreateSystemUser: %v", err)
+	}
+	img := testPNG(t, 4, 3)
+	size := int64(len(img))
+
+	active := createTestUploadIntent(t, uc, ownerID, "image/png", size, time.Hour)
+	expired := createTestUploadIntent(t, uc, ownerID, "image/png", size, -time.Hour)
+	files.put(expired.S3Key, img, "image/png")
+	wrongSize := createTestUploadIntent(t, uc, ownerID, "image/png", size+1, time.Hour)
+	files.put(wrongSize.S3Key, img, "image/png")
+	wrongType := createTestUploadIntent(t, uc, ownerID, "image/jpeg", size, time.Hour)
+	files.put(wrongType.S3Key, img, "image/png")
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		id     uuid.UUID
+		want   error
+	}{
+		{name: "missing intent", userID: ownerID, id: uuid.New(), want: domain.ErrUploadIntentNotFound},
+		{name: "foreign intent", userID: uuid.New(), id: active.ID, want: domain.ErrUploadIntentNotFound},
+		{name: "expired intent", userID: ownerID, id: expired.ID, want: domain.ErrUploadIntentNotFound},
+		{name: "file not uploaded", userID: ownerID, id: active.ID, want: ErrUploadNotReceived},
+		{name: "size mismatch", userID: ownerID, id: wrongSize.ID, want: ErrUploadMismatch},
+		{name: "type mismatch", userID: ownerID, id: wrongType.ID, want: ErrUploadMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CompleteUpload(ctx, tt.userID, tt.id); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	for _, intent := range []domain.UploadIntent{active, expired, wrongSize, wrongType} {
+		if _, err := uc.GetPhotoDetailsFromDB(ctx, intent.ID); !errors.Is(err, domain.ErrPhotoNotFound) {
+			t.Errorf("photo %s after failed completion: err = %v, want ErrPhotoNotFound", intent.ID, err)
+		}
+	}
+}
+
+func TestCompleteUpload_ActivatesPendingPhoto(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	events := &recordingPublisher{}
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, files: files, events: events})
+	ownerID, err := st.users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("GetOrCreateSystemUser: %v", err)
+	}
+	img := testPNG(t, 4, 3)
+	intent := createTestUploadIntent(t, uc, ownerID, "image/png", int64(len(img)), time.Hour)
+
+	if _, err := uc.GetPhotoDetailsFromDB(ctx, intent.ID); !errors.Is(err, domain.ErrPhotoNotFound) {
+		t.Fatalf("pending photo: err = %v, want ErrPhotoNotFound", err)
+	}
+	recent, total, err := uc.GetRecentPhotosFromDB(ctx, 1, 10, false, domain.PhotoSort{})
+	if err != nil {
+		t.Fatalf("GetRecentPhotosFromDB: %v", err)
+	}
+	if len(recent) != 0 || total != 0 {
+		t.Fatalf("recent photos = %d of %d before completion, want none", len(recent), total)
+	}
+
+	files.put(intent.S3Key, img, "image/png")
+	photo, err := uc.CompleteUpload(ctx, ownerID, intent.ID)
+	if err != nil {
+		t.Fatalf("CompleteUpload: %v", err)
+	}
+	if photo.ID != intent.ID || photo.Status != domain.PhotoStatusActive {
+		t.Errorf("photo = %s (%s), want %s active", photo.ID, photo.Status, intent.ID)
+	}
+	if photo.Width != 4 || photo.Height != 3 || photo.Title != intent.Title || photo.UserID != ownerID {
+		t.Errorf("photo = %dx%d %q by %s, want 4x3 %q by %s", photo.Width, photo.Height, photo.Title, photo.UserID, intent.Title, ownerID)
+	}
+	if n := len(events.created()); n != 1 {
+		t.Errorf("published %d PhotoCreatedEvent, want 1", n)
+	}
+
+	got, err := uc.GetPhotoDetailsFromDB(ctx, intent.ID)
+	if err != nil {
+		t.Fatalf("completed photo is not visible: %v", err)
+	}
+	if got.SizeBytes != int64(len(img)) || got.MimeType != "image/png" {
+		t.Errorf("stored photo = %d bytes %s, want %d bytes image/png", got.SizeBytes, got.MimeType, len(img))
+	}
+
+	if _, err := uc.CompleteUpload(ctx, ownerID, intent.ID); !errors.Is(err, domain.ErrUploadIntentNotFound) {
+		t.Errorf("second completion: err = %v, want ErrUploadIntentNotFound", err)
+	}
+	if !files.has(intent.S3Key) {
+		t.Error("second completion deleted the file of the completed photo")
+	}
+}
+
+func TestCleanupExpiredUploadIntents_DeletesPendingPhotos(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	uc := newTestPhotoUseCase(useCaseDeps{photos: st.photos, users: st.users, files: files})
+	ownerID, err := st.users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("GetOrCreateSystemUser: %v", err)
+	}
+	img := testPNG(t, 4, 3)
+	expired := createTestUploadIntent(t, uc, ownerID, "image/png", int64(len(img)), -time.Hour)
+	files.put(expired.S3Key, img, "image/png")
+	active := createTestUploadIntent(t, uc, ownerID, "image/png", int64(len(img)), time.Hour)
+
+	deleted, err := uc.CleanupExpiredUploadIntents(ctx)
+	if err != nil {
+		t.Fatalf("CleanupExpiredUploadIntents: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted = %d, want 1", deleted)
+	}
+	if files.has(expired.S3Key) {
+		t.Error("file of the expired upload was not deleted")
+	}
+
+	var ids []uuid.UUID
+	if err := st.db.SelectContext(ctx, &ids, `SELECT id FROM photos`); err != nil {
+		t.Fatalf("select photos: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != active.ID {
+		t.Errorf("photos = %v, want only the pending photo of the live upload %s", ids, active.ID)
+	}
+}
+
+func TestUploadPhoto_DeletesFileWhenSaveFails(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	files := newMemFileStorage()
+	uc := newTestPhotoUseCase(useCaseDeps{photos: failingPhotoStorage{st.photos}, users: st.users, files: files})
+	ownerID, err := st.users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("GetOrCreateSystemUser: %v", err)
+	}
+
+	_, err = uc.UploadPhoto(ctx, UploadPhotoInput{UserID: ownerID, Title: "upload", File: bytes.NewReader(testPNG(t, 4, 3))})
+	if !errors.Is(err, errDBDown) {
+		t.Fatalf("err = %v, want the db error", err)
+	}
+	if keys := files.keys(); len(keys) != 0 {
+		t.Errorf("files = %v, want the uploaded file deleted", keys)
+	}
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// UpdateProfileInput описывает изменения профиля: nil-поля не меняются.
+// Для смены пароля нужно передать и текущий, и новый пароль
+type UpdateProfileInput struct {
+	Username        *string
+	Email           *string
+	CurrentPassword *string
+	NewPassword     *string
+}
+
+// UserUseCase определяет интерфейс бизнес-логики работы с пользователями
+type UserUseCase interface {
+	// Register создаёт нового пользователя с bcrypt-хешем пароля
+	Register(ctx context.Context, username, email, password string) (*domain.User, error)
+
+	// Authenticate проверяет email и пароль и возвращает пользователя
+	Authenticate(ctx context.Context, email, password string) (*domain.User, error)
+
+	// GetProfile возвращает профиль пользователя по ID
+	GetProfile(ctx context.Context, id uuid.UUID) (*domain.User, error)
+
+	// UpdateProfile частично обновляет профиль и возвращает обновлённого пользователя
+	UpdateProfile(ctx context.Context, id uuid.UUID, input UpdateProfileInput) (*domain.User, error)
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptCost — стоимость хеширования паролей
+const bcryptCost = 12
+
+// userUseCase implements UserUseCase
+type userUseCase struct {
+	userStorage ports.UserStorage
+	logger      *slog.Logger
+}
+
+// NewUserUseCase создает новый экземпляр UserUseCase
+func NewUserUseCase(userStorage ports.UserStorage, logger *slog.Logger) UserUseCase {
+	return &userUseCase{
+		userStorage: userStorage,
+		logger:      logger,
+	}
+}
+
+// Register регистрирует нового пользователя
+func (uc *userUseCase) Register(ctx context.Context, username, email, password string) (*domain.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+	if err != nil {
+		uc.log(ctx).Error("ошибка хеширования пароля", slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка хеширования пароля: %w", err)
+	}
+
+	user := &domain.User{
+		ID:           uuid.New(),
+		Username:     username,
+		Email:        email,
+		PasswordHash: string(hash),
+	}
+	if err := uc.userStorage.CreateUser(ctx, user); err != nil {
+		uc.log(ctx).Warn("не удалось создать пользователя", slog.String("username", username), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при регистрации пользователя: %w", err)
+	}
+
+	uc.log(ctx).Info("пользователь зарегистрирован", slog.String("user_id", user.ID.String()))
+	return user, nil
+}
+
+// Authenticate проверяет учётные данные пользователя
+func (uc *userUseCase) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
+	user, err := uc.userStorage.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		uc.log(ctx).Error("ошибка получения пользователя по email", slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при аутентификации: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		uc.log(ctx).Warn("неверный пароль", slog.String("user_id", user.ID.String()))
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// GetProfile возвращает профиль пользователя
+func (uc *userUseCase) GetProfile(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := uc.userStorage.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("usecase: ошибка при получении профиля %s: %w", id, err)
+	}
+	return user, nil
+}
+
+// UpdateProfile обновляет профиль пользователя, включая смену пароля
+func (uc *userUseCase) UpdateProfile(ctx context.Context, id uuid.UUID, input UpdateProfileInput) (*domain.User, error) {
+	updates := domain.UserUpdate{
+		Username: input.Username,
+		Email:    input.Email,
+	}
+
+	if input.NewPassword != nil {
+		user, err := uc.userStorage.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("usecase: ошибка при получении профиля %s: %w", id, err)
+		}
+		if input.CurrentPassword == nil ||
+			bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(*input.CurrentPassword)) != nil {
+			uc.log(ctx).Warn("неверный текущий пароль при смене пароля", slog.String("user_id", id.String()))
+			return nil, ErrInvalidCredentials
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(*input.NewPassword), bcryptCost)
+		if err != nil {
+			uc.log(ctx).Error("ошибка хеширования пароля", slog.Any("error", err))
+			return nil, fmt.Errorf("usecase: ошибка хеширования пароля: %w", err)
+		}
+		passwordHash := string(hash)
+		updates.PasswordHash = &passwordHash
+	}
+
+	if err := uc.userStorage.UpdateUser(ctx, id, updates); err != nil {
+		uc.log(ctx).Warn("не удалось обновить профиль", slog.String("user_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при обновлении профиля %s: %w", id, err)
+	}
+
+	uc.log(ctx).Info("профиль пользователя обновлён", slog.String("user_id", id.String()))
+	return uc.GetProfile(ctx, id)
+}
+
+// log возвращает логгер с request_id текущего запроса
+func (uc *userUseCase) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, uc.logger)
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// WebhookUseCase определяет интерфейс бизнес-логики вебхуков.
+// Все методы работают только с вебхуками пользователя userID: чужой вебхук
+// неотличим от несуществующего и даёт domain.ErrWebhookNotFound
+type WebhookUseCase interface {
+	// CreateWebhook регистрирует вебхук на события events. Если secret пуст, он генерируется;
+	// возвращённый вебхук — единственное место, где клиент видит секрет. Адрес должен быть http(s)
+	// и разрешаться только в публичные IP, иначе возвращается ErrInvalidWebhookURL
+	CreateWebhook(ctx context.Context, userID uuid.UUID, url string, events []string, secret string) (*domain.Webhook, error)
+
+	// ListWebhooks возвращает вебхуки пользователя
+	ListWebhooks(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error)
+
+	// GetWebhook возвращает вебхук по ID
+	GetWebhook(ctx context.Context, userID, id uuid.UUID) (*domain.Webhook, error)
+
+	// DeleteWebhook удаляет вебхук; доставка событий на него прекращается
+	DeleteWebhook(ctx context.Context, userID, id uuid.UUID) error
+}
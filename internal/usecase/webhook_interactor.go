@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/GoArmGo/MediaApp/internal/netguard"
+	"github.com/google/uuid"
+)
+
+// webhookSecretBytes — длина генерируемого секрета вебхука в байтах (в hex вдвое длиннее)
+const webhookSecretBytes = 32
+
+// webhookUseCase implements WebhookUseCase
+type webhookUseCase struct {
+	webhookStorage ports.WebhookStorage
+	logger         *slog.Logger
+}
+
+// NewWebhookUseCase создает новый экземпляр WebhookUseCase
+func NewWebhookUseCase(webhookStorage ports.WebhookStorage, logger *slog.Logger) WebhookUseCase {
+	return &webhookUseCase{
+		webhookStorage: webhookStorage,
+		logger:         logger,
+	}
+}
+
+// CreateWebhook создаёт активный вебхук пользователя. Адрес во внутренней сети отклоняется
+// с ErrInvalidWebhookURL
+func (uc *webhookUseCase) CreateWebhook(ctx context.Context, userID uuid.UUID, url string, events []string, secret string) (*domain.Webhook, error) {
+	if err := netguard.CheckURL(ctx, net.DefaultResolver, url); err != nil {
+		uc.log(ctx).Warn("адрес вебхука отклонён", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: %w: %w", ErrInvalidWebhookURL, err)
+	}
+
+	if secret == "" {
+		buf := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("usecase: ошибка генерации секрета вебхука: %w", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	webhook := &domain.Webhook{
+		ID:     uuid.New(),
+		UserID: userID,
+		URL:    url,
+		Events: domain.EventTypes(events),
+		Secret: secret,
+		Active: true,
+	}
+	if err := uc.webhookStorage.CreateWebhook(ctx, webhook); err != nil {
+		uc.log(ctx).Error("ошибка создания вебхука", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при создании вебхука: %w", err)
+	}
+
+	uc.log(ctx).Info("вебхук создан",
+		slog.String("webhook_id", webhook.ID.String()),
+		slog.String("user_id", userID.String()),
+		slog.Any("events", events),
+	)
+	return webhook, nil
+}
+
+// ListWebhooks возвращает вебхуки пользователя
+func (uc *webhookUseCase) ListWebhooks(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	webhooks, err := uc.webhookStorage.ListWebhooksByUserID(ctx, userID)
+	if err != nil {
+		uc.log(ctx).Error("ошибка получения вебхуков", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении вебхуков: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhook возвращает вебхук пользователя по ID
+func (uc *webhookUseCase) GetWebhook(ctx context.Context, userID, id uuid.UUID) (*domain.Webhook, error) {
+	return uc.ownWebhook(ctx, userID, id)
+}
+
+// DeleteWebhook удаляет вебхук пользователя
+func (uc *webhookUseCase) DeleteWebhook(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := uc.ownWebhook(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := uc.webhookStorage.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			return err
+		}
+		uc.log(ctx).Error("ошибка удаления вебхука", slog.String("webhook_id", id.String()), slog.Any("error", err))
+		return fmt.Errorf("usecase: ошибка при удалении вебхука: %w", err)
+	}
+
+	uc.log(ctx).Info("вебхук удалён", slog.String("webhook_id", id.String()))
+	return nil
+}
+
+// ownWebhook получает вебхук и проверяет, что он принадлежит userID.
+// Чужой вебхук возвращается как несуществующий, чтобы не раскрывать его наличие
+func (uc *webhookUseCase) ownWebhook(ctx context.Context, userID, id uuid.UUID) (*domain.Webhook, error) {
+	webhook, err := uc.webhookStorage.GetWebhookByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			return nil, err
+		}
+		uc.log(ctx).Error("ошибка получения вебхука", slog.String("webhook_id", id.String()), slog.Any("error", err))
+		return nil, fmt.Errorf("usecase: ошибка при получении вебхука: %w", err)
+	}
+	if webhook.UserID != userID {
+		uc.log(ctx).Warn("попытка доступа к чужому вебхуку",
+			slog.String("webhook_id", id.String()),
+			slog.String("user_id", userID.String()),
+		)
+		return nil, domain.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (uc *webhookUseCase) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, uc.logger)
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoArmGo/MediaApp/internal/database/sqlite"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+)
+
+func TestCreateWebhook_URL(t *testing.T) {
+	ctx := context.Background()
+	st := newTestStorages(t)
+	userID, err := st.users.GetOrCreateSystemUser(ctx)
+	if err != nil {
+		t.Fatalf("GetOrCreateSystemUser: %v", err)
+	}
+	webhooks := sqlite.NewWebhookStorage(st.db, discardLogger())
+	uc := NewWebhookUseCase(webhooks, discardLogger())
+
+	tests := []struct {
+		name    string
+		url     string
+		invalid bool
+	}{
+		{name: "public", url: "https://93.184.216.34/hook"},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data/", invalid: true},
+		{name: "loopback", url: "http://127.0.0.1:8080/hook", invalid: true},
+		{name: "private network", url: "http://192.168.0.10/hook", invalid: true},
+		{name: "localhost", url: "http://localhost/hook", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook, err := uc.CreateWebhook(ctx, userID, tt.url, []string{domain.EventPhotoCreated}, "")
+			if tt.invalid {
+				if !errors.Is(err, ErrInvalidWebhookURL) {
+					t.Fatalf("err = %v, want ErrInvalidWebhookURL", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateWebhook: %v", err)
+			}
+			if webhook.URL != tt.url {
+				t.Errorf("URL = %q, want %q", webhook.URL, tt.url)
+			}
+		})
+	}
+
+	saved, err := webhooks.ListWebhooksByUserID(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListWebhooksByUserID: %v", err)
+	}
+	if len(saved) != 1 {
+		t.Errorf("saved %d webhooks, want only the public one", len(saved))
+	}
+}
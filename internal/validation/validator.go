@@ -0,0 +1,195 @@
+// internal/validation/validator.go
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError — ошибка проверки одного поля запроса.
+// Field — имя параметра так, как его передаёт клиент (query, json или путь), Tag — нарушенное правило
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// Validator заполняет структуры запросов из HTTP-запроса и проверяет их по тегам validate.
+// Поля заполняются из query-параметров по тегу query и из JSON-тела по тегу json.
+// Параметры пути (тег path) заполняет обработчик до вызова ValidateRequest
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New создает новый экземпляр Validator
+func New() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// в ошибках поле называется так же, как параметр запроса, а не как поле Go-структуры
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"query", "json", "path"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return f.Name
+	})
+	return &Validator{validate: v}
+}
+
+// ValidateRequest заполняет dst (указатель на структуру) из запроса и проверяет его.
+// Значения, уже записанные в dst, служат значениями по умолчанию для отсутствующих параметров.
+// Возвращает nil, если запрос корректен
+func (v *Validator) ValidateRequest(r *http.Request, dst any) []FieldError {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: ValidateRequest ожидает указатель на структуру, получено %T", dst))
+	}
+
+	if hasJSONBody(r) {
+		if err := json.NewDecoder(r.Body).Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+			return []FieldError{{Field: "body", Tag: "json", Message: "некорректное JSON-тело запроса"}}
+		}
+	}
+	if errs := decodeQuery(r, rv.Elem()); len(errs) > 0 {
+		return errs
+	}
+	return v.Validate(dst)
+}
+
+// Validate проверяет уже заполненную структуру по тегам validate
+func (v *Validator) Validate(dst any) []FieldError {
+	err := v.validate.Struct(dst)
+	if err == nil {
+		return nil
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return []FieldError{{Field: "", Tag: "invalid", Message: err.Error()}}
+	}
+
+	fieldErrors := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	return fieldErrors
+}
+
+// hasJSONBody сообщает, что у запроса есть тело, которое нужно разобрать как JSON
+func hasJSONBody(r *http.Request) bool {
+	if r.Body == nil || r.ContentLength == 0 {
+		return false
+	}
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	default:
+		return false
+	}
+}
+
+// decodeQuery записывает query-параметры в поля с тегом query. Поддерживаются string, int, float64, bool
+// и указатели на них — указатель остаётся nil, если параметра нет, что отличает его от нулевого значения.
+// Отсутствующий параметр оставляет поле как есть; нечисловое значение для числа — ошибка поля
+func decodeQuery(r *http.Request, rv reflect.Value) []FieldError {
+	query := r.URL.Query()
+	rt := rv.Type()
+
+	var errs []FieldError
+	for i := 0; i < rt.NumField(); i++ {
+		name, _, _ := strings.Cut(rt.Field(i).Tag.Get("query"), ",")
+		if name == "" || name == "-" || !query.Has(name) {
+			continue
+		}
+		raw := query.Get(name)
+		field := rv.Field(i)
+		if field.Kind() == reflect.Pointer {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(strings.TrimSpace(raw))
+		case reflect.Int, reflect.Int64:
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				errs = append(errs, FieldError{Field: name, Tag: "int", Message: "должно быть целым числом"})
+				continue
+			}
+			field.SetInt(n)
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				errs = append(errs, FieldError{Field: name, Tag: "float", Message: "должно быть числом"})
+				continue
+			}
+			field.SetFloat(f)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				errs = append(errs, FieldError{Field: name, Tag: "bool", Message: "должно быть true или false"})
+				continue
+			}
+			field.SetBool(b)
+		default:
+			panic(fmt.Sprintf("validation: неподдерживаемый тип %s у query-поля %s", field.Kind(), name))
+		}
+	}
+	return errs
+}
+
+// dateLayoutHint показывает клиенту макет даты Go (2006-01-02) привычным ГГГГ-ММ-ДД
+var dateLayoutHint = strings.NewReplacer("2006", "ГГГГ", "01", "ММ", "02", "ДД")
+
+// message переводит нарушенное правило в текст для клиента
+func message(fe validator.FieldError) string {
+	isString := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required":
+		return "обязательное поле"
+	case "min":
+		if isString {
+			return fmt.Sprintf("должно содержать не менее %s символов", fe.Param())
+		}
+		return fmt.Sprintf("должно быть не меньше %s", fe.Param())
+	case "max":
+		if isString {
+			return fmt.Sprintf("должно содержать не более %s символов", fe.Param())
+		}
+		return fmt.Sprintf("должно быть не больше %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("должно быть больше %s", fe.Param())
+	case "latitude":
+		return "широта должна быть от -90 до 90"
+	case "longitude":
+		return "долгота должна быть от -180 до 180"
+	case "oneof":
+		return "допустимые значения: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "email":
+		return "некорректный email"
+	case "uuid", "uuid4":
+		return "должно быть UUID"
+	case "url", "http_url":
+		return "некорректный URL: ожидается адрес http или https"
+	case "datetime":
+		return fmt.Sprintf("некорректная дата: ожидается формат %s", dateLayoutHint.Replace(fe.Param()))
+	case "unique":
+		return "значения не должны повторяться"
+	default:
+		return fmt.Sprintf("не прошло проверку %s", fe.Tag())
+	}
+}
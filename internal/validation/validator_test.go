@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testRequest покрывает все правила, для которых message формирует свой текст
+type testRequest struct {
+	Query   string   `query:"query" validate:"required,min=2,max=200"`
+	Page    int      `query:"page" validate:"min=1"`
+	PerPage int      `query:"per_page" validate:"min=1,max=100"`
+	Radius  float64  `query:"radius" validate:"gt=0"`
+	Lat     *float64 `query:"lat" validate:"omitempty,latitude"`
+	Lon     *float64 `query:"lon" validate:"omitempty,longitude"`
+	Color   string   `query:"color" validate:"omitempty,oneof=red green blue"`
+	From    string   `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	Exact   bool     `query:"exact"`
+	Email   string   `json:"email" validate:"omitempty,email"`
+	UserID  string   `json:"user_id" validate:"omitempty,uuid"`
+	URL     string   `json:"url" validate:"omitempty,http_url"`
+	Tags    []string `json:"tags" validate:"unique"`
+}
+
+// validRequest — значения по умолчанию, с которыми запрос без параметров проходит проверку
+func validRequest() testRequest {
+	return testRequest{Page: 1, PerPage: 10, Radius: 1}
+}
+
+func TestValidateRequest_Rules(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		body    string
+		field   string
+		tag     string
+		message string
+	}{
+		{name: "required", query: "", field: "query", tag: "required", message: "обязательное поле"},
+		{name: "string min", query: "query=a", field: "query", tag: "min", message: "должно содержать не менее 2 символов"},
+		{name: "string max", query: "query=" + strings.Repeat("a", 201), field: "query", tag: "max", message: "должно содержать не более 200 символов"},
+		{name: "number min", query: "query=ok&page=0", field: "page", tag: "min", message: "должно быть не меньше 1"},
+		{name: "number max", query: "query=ok&per_page=101", field: "per_page", tag: "max", message: "должно быть не больше 100"},
+		{name: "gt", query: "query=ok&radius=0", field: "radius", tag: "gt", message: "должно быть больше 0"},
+		{name: "latitude", query: "query=ok&lat=91", field: "lat", tag: "latitude", message: "широта должна быть от -90 до 90"},
+		{name: "longitude", query: "query=ok&lon=-181", field: "lon", tag: "longitude", message: "долгота должна быть от -180 до 180"},
+		{name: "oneof", query: "query=ok&color=pink", field: "color", tag: "oneof", message: "допустимые значения: red, green, blue"},
+		{name: "datetime", query: "query=ok&from=01.02.2024", field: "from", tag: "datetime", message: "некорректная дата: ожидается формат ГГГГ-ММ-ДД"},
+		{name: "int", query: "query=ok&page=two", field: "page", tag: "int", message: "должно быть целым числом"},
+		{name: "float", query: "query=ok&radius=NaN", field: "radius", tag: "float", message: "должно быть числом"},
+		{name: "bool", query: "query=ok&exact=maybe", field: "exact", tag: "bool", message: "должно быть true или false"},
+		{name: "email", query: "query=ok", body: `{"email":"not-an-email"}`, field: "email", tag: "email", message: "некорректный email"},
+		{name: "uuid", query: "query=ok", body: `{"user_id":"123"}`, field: "user_id", tag: "uuid", message: "должно быть UUID"},
+		{name: "http_url", query: "query=ok", body: `{"url":"ftp://example.com"}`, field: "url", tag: "http_url", message: "некорректный URL: ожидается адрес http или https"},
+		{name: "unique", query: "query=ok", body: `{"tags":["a","a"]}`, field: "tags", tag: "unique", message: "значения не должны повторяться"},
+		{name: "json", query: "query=ok", body: `{"email":`, field: "body", tag: "json", message: "некорректное JSON-тело запроса"},
+	}
+	v := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+			if tt.body != "" {
+				req = httptest.NewRequest(http.MethodPost, "/?"+tt.query, strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+			}
+			dst := validRequest()
+			errs := v.ValidateRequest(req, &dst)
+			if len(errs) != 1 {
+				t.Fatalf("errors = %+v, want exactly one for %s", errs, tt.field)
+			}
+			want := FieldError{Field: tt.field, Tag: tt.tag, Message: tt.message}
+			if errs[0] != want {
+				t.Errorf("error = %+v, want %+v", errs[0], want)
+			}
+		})
+	}
+}
+
+func TestValidateRequest_Valid(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/?query=cats&page=2&lat=55.7&lon=37.6&color=red&from=2024-02-03&exact=true",
+		strings.NewReader(`{"email":"user@example.com","url":"https://example.com/hook","tags":["a","b"]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	dst := validRequest()
+	if errs := New().ValidateRequest(req, &dst); errs != nil {
+		t.Fatalf("errors = %+v, want none", errs)
+	}
+	if dst.Query != "cats" || dst.Page != 2 || dst.PerPage != 10 || dst.Lat == nil || *dst.Lat != 55.7 || !dst.Exact {
+		t.Errorf("decoded = %+v, want query cats, page 2, default per_page 10, lat 55.7, exact", dst)
+	}
+	if dst.Email != "user@example.com" || len(dst.Tags) != 2 {
+		t.Errorf("decoded body = %+v", dst)
+	}
+}
@@ -0,0 +1,216 @@
+// Package webhook доставляет доменные события фото на вебхуки пользователей
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GoArmGo/MediaApp/internal/core/ports"
+	"github.com/GoArmGo/MediaApp/internal/domain"
+	"github.com/GoArmGo/MediaApp/internal/events"
+	"github.com/GoArmGo/MediaApp/internal/logger"
+	"github.com/google/uuid"
+)
+
+// Заголовки запроса доставки
+const (
+	// SignatureHeader — подпись тела: "sha256=" + hex(HMAC-SHA256(secret, body))
+	SignatureHeader = "X-Signature-256"
+	// EventHeader — тип события, например photo.created
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader — ID события; одинаков во всех повторах, по нему получатель отсеивает дубликаты
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+// maxResponseBodyBytes — сколько байт ответа получателя вычитывается перед закрытием соединения
+const maxResponseBodyBytes = 64 << 10
+
+// Payload — JSON, который получает вебхук
+type Payload struct {
+	ID         uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       PayloadData `json:"data"`
+}
+
+// PayloadData — данные события фото
+type PayloadData struct {
+	Photo domain.Photo `json:"photo"`
+}
+
+// Dispatcher подписывается на события фото и отправляет их на активные вебхуки.
+// Ответ не 2xx или сетевая ошибка повторяются до maxAttempts раз с паузой baseDelay,
+// удваивающейся после каждой неудачи. Каждая попытка пишется в webhook_deliveries
+type Dispatcher struct {
+	storage     ports.WebhookStorage
+	client      *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	logger   *slog.Logger
+}
+
+// NewDispatcher создает новый экземпляр Dispatcher
+func NewDispatcher(storage ports.WebhookStorage, client *http.Client, maxAttempts int, baseDelay time.Duration, logger *slog.Logger) *Dispatcher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Dispatcher{
+		storage:     storage,
+		client:      client,
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+		stop:        make(chan struct{}),
+		logger:      logger,
+	}
+}
+
+// Handle — подписчик шины событий. Доставляет событие на все подписанные вебхуки параллельно
+// и возвращается, когда каждая доставка завершилась успехом или исчерпала попытки
+func (d *Dispatcher) Handle(ctx context.Context, event events.Event) {
+	var photo domain.Photo
+	switch e := event.(type) {
+	case domain.PhotoCreatedEvent:
+		photo = e.Photo
+	case domain.PhotoUpdatedEvent:
+		photo = e.Photo
+	case domain.PhotoDeletedEvent:
+		photo = e.Photo
+	default:
+		return
+	}
+	meta := event.(interface{ Meta() domain.EventMeta }).Meta()
+
+	webhooks, err := d.storage.ListActiveWebhooksByEvent(ctx, event.EventType())
+	if err != nil {
+		d.log(ctx).Error("failed to list webhooks for event", "event_type", event.EventType(), "event_id", meta.ID, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:         meta.ID,
+		Type:       event.EventType(),
+		OccurredAt: meta.OccurredAt,
+		Data:       PayloadData{Photo: photo},
+	})
+	if err != nil {
+		d.log(ctx).Error("failed to marshal webhook payload", "event_type", event.EventType(), "event_id", meta.ID, "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, webhook, meta.ID, event.EventType(), body)
+		}()
+	}
+	wg.Wait()
+}
+
+// Close прерывает ожидание повторов: текущие попытки завершаются, новые не начинаются.
+// Вызывается перед закрытием шины событий, чтобы остановка не ждала всех пауз
+func (d *Dispatcher) Close() {
+	d.stopOnce.Do(func() { close(d.stop) })
+}
+
+// deliver отправляет событие на один вебхук с повторами
+func (d *Dispatcher) deliver(ctx context.Context, webhook domain.Webhook, eventID uuid.UUID, eventType string, body []byte) {
+	log := d.log(ctx).With("webhook_id", webhook.ID, "event_id", eventID, "event_type", eventType)
+	delay := d.baseDelay
+
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		start := time.Now()
+		status, err := d.send(ctx, webhook, eventID, eventType, body)
+
+		delivery := &domain.WebhookDelivery{
+			WebhookID:  webhook.ID,
+			EventID:    eventID,
+			EventType:  eventType,
+			Attempt:    attempt,
+			StatusCode: status,
+			Success:    err == nil,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if recErr := d.storage.RecordWebhookDelivery(ctx, delivery); recErr != nil {
+			log.Warn("failed to record webhook delivery", "attempt", attempt, "error", recErr)
+		}
+
+		if err == nil {
+			log.Info("webhook delivered", "attempt", attempt, "status", status, "duration_ms", delivery.DurationMs)
+			return
+		}
+		if attempt == d.maxAttempts {
+			log.Error("webhook delivery failed, giving up", "attempts", attempt, "status", status, "error", err)
+			return
+		}
+		log.Warn("webhook delivery failed, retrying", "attempt", attempt, "status", status, "retry_in", delay, "error", err)
+
+		select {
+		case <-time.After(delay):
+		case <-d.stop:
+			log.Warn("webhook retries aborted on shutdown", "attempt", attempt)
+			return
+		case <-ctx.Done():
+			return
+		}
+		delay *= 2
+	}
+}
+
+// send выполняет одну попытку доставки и возвращает HTTP-статус ответа (0, если ответа нет)
+func (d *Dispatcher) send(ctx context.Context, webhook domain.Webhook, eventID uuid.UUID, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MediaApp-Webhooks/1.0")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, eventID.String())
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка отправки запроса: %w", err)
+	}
+	defer resp.Body.Close()
+	// дочитываем ответ, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodyBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("получатель ответил статусом %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign возвращает значение заголовка X-Signature-256 для тела body.
+// Получатель считает тот же HMAC по сырому телу запроса и сравнивает за постоянное время
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Dispatcher) log(ctx context.Context) *slog.Logger {
+	return logger.FromContext(ctx, d.logger)
+}